@@ -0,0 +1,20 @@
+//go:build !gui
+
+/*
+
+	NoWindow.go - builds without the gui tag have no window to open
+
+	mike@pocomotech.com
+
+*/
+
+package main
+
+import (
+	"errors"
+)
+
+// run -- refuse to play, saying how to build the window
+func run(s *session) error {
+	return errors.New("built without a window, rebuild with go build -tags gui ./cmd/gomines-gui")
+}
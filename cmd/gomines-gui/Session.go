@@ -0,0 +1,147 @@
+/*
+
+	Session.go - the game behind the window: the board, the clock and what the status bar shows, kept apart from
+	the GUI toolkit so it builds and is tested without it
+
+	mike@pocomotech.com
+
+*/
+
+package main
+
+import (
+	"fmt"
+	"go-mines/msengine"
+	"strconv"
+	"time"
+)
+
+// Range of numbers the counter and clock show, as on the classic three digit displays
+const (
+	maxDisplay = 999
+	minDisplay = -99
+)
+
+// session : the game being played in the window, and the clock, which starts with the first move and stops when
+// the game is over
+type session struct {
+	difficulty string
+	rules      msengine.Rules
+	board      msengine.Board
+	started    time.Time
+	stopped    time.Time
+	now        func() time.Time // the clock, replaced in tests
+}
+
+// newSession -- a session playing boards of a difficulty by the named rules
+func newSession(difficulty, rules string) (*session, error) {
+	r, err := msengine.LookupRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	retval := &session{difficulty: difficulty, rules: r, now: time.Now}
+	if err = retval.reset(); err != nil {
+		return nil, err
+	}
+	return retval, nil
+}
+
+// reset -- start a new game on a fresh board, with the clock stopped at zero
+func (s *session) reset() error {
+	b, err := msengine.NewBoard(s.difficulty)
+	if err != nil {
+		return err
+	}
+	b.SetRules(s.rules)
+	s.board, s.started, s.stopped = b, time.Time{}, time.Time{}
+	return nil
+}
+
+// click -- reveal a hidden cell, or chord a revealed one, as a left click does
+func (s *session) click(l msengine.Location) (msengine.Status, error) {
+	t := msengine.MoveReveal
+	if v, _ := s.board.Snapshot().Cell(l); v.State == msengine.CellRevealed {
+		t = msengine.MoveChord
+	}
+	return s.play(msengine.Move{Type: t, Location: l})
+}
+
+// flag -- flag or unflag a hidden cell, as a right click does
+func (s *session) flag(l msengine.Location) (msengine.Status, error) {
+	return s.play(msengine.Move{Type: msengine.MoveFlag, Location: l})
+}
+
+// play -- apply a move, starting the clock with the first and stopping it once the game is over. Moves after the
+// game is over are ignored
+func (s *session) play(m msengine.Move) (msengine.Status, error) {
+	switch s.board.Status() {
+	case msengine.StatusWon, msengine.StatusLost:
+		return s.board.Status(), nil
+	}
+	if s.started.IsZero() {
+		s.started = s.now()
+	}
+	status, err := msengine.Apply(s.board, m)
+	if status == msengine.StatusWon || status == msengine.StatusLost {
+		s.stopped = s.now()
+	}
+	return status, err
+}
+
+// minesLeft -- mines less flags placed, below zero if the player flagged too many
+func (s *session) minesLeft() int {
+	snap := s.board.Snapshot()
+	return snap.Mines - snap.Flags
+}
+
+// elapsed -- whole seconds on the clock
+func (s *session) elapsed() int {
+	if s.started.IsZero() {
+		return 0
+	}
+	end := s.stopped
+	if end.IsZero() {
+		end = s.now()
+	}
+	return int(end.Sub(s.started) / time.Second)
+}
+
+// face -- the face on the new game button: smiling while playing, in sunglasses once won, knocked out once lost
+func (s *session) face() string {
+	switch s.board.Status() {
+	case msengine.StatusWon:
+		return "😎"
+	case msengine.StatusLost:
+		return "😵"
+	}
+	return "🙂"
+}
+
+// display -- a number as a three digit display shows it, kept within what the display can show
+func display(n int) string {
+	if n > maxDisplay {
+		n = maxDisplay
+	}
+	if n < minDisplay {
+		n = minDisplay
+	}
+	if n < 0 {
+		return fmt.Sprintf("-%02d", -n)
+	}
+	return fmt.Sprintf("%03d", n)
+}
+
+// cellLabel -- the text on a cell's button: nothing for hidden cells and zeros
+func cellLabel(v msengine.CellView) string {
+	switch v.State {
+	case msengine.CellFlagged:
+		return "🚩"
+	case msengine.CellMine:
+		return "💣"
+	case msengine.CellRevealed:
+		if v.Score != 0 {
+			return strconv.Itoa(v.Score)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"go-mines/msengine"
+	"testing"
+	"time"
+)
+
+// clockAt : a clock that reads whatever it's set to
+type clockAt struct {
+	t time.Time
+}
+
+func (c *clockAt) now() time.Time { return c.t }
+
+func TestSession(t *testing.T) {
+	s, err := newSession("easy", "chord")
+	if err != nil {
+		t.Fatalf("newSession failed: %s", err)
+	}
+	clock := &clockAt{time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	s.now = clock.now
+
+	// the clock waits for the first move
+	clock.t = clock.t.Add(time.Minute)
+	if s.elapsed() != 0 || s.minesLeft() != 10 || s.face() != "🙂" || s.board.Rules().Name() != "chord" {
+		t.Errorf("new session shows %d mines, %ds, %s under %s", s.minesLeft(), s.elapsed(), s.face(),
+			s.board.Rules().Name())
+	}
+	if status, err := s.click(msengine.NewLocation(4, 4)); err != nil || status != msengine.StatusPlaying &&
+		status != msengine.StatusWon {
+		t.Fatalf("first click got %v, %v", status, err)
+	}
+	clock.t = clock.t.Add(12 * time.Second)
+	if s.elapsed() != 12 {
+		t.Errorf("clock wanted 12s got %d", s.elapsed())
+	}
+
+	// flags count down the mines left, even past zero
+	for col := 0; col < 9 && s.board.Status() == msengine.StatusPlaying; col++ {
+		for _, row := range []int{0, 8} {
+			if v, _ := s.board.Snapshot().Cell(msengine.NewLocation(row, col)); v.State == msengine.CellHidden {
+				s.flag(msengine.NewLocation(row, col))
+			}
+		}
+	}
+	if flags := s.board.Snapshot().Flags; s.minesLeft() != 10-flags {
+		t.Errorf("%d flags left %d mines", flags, s.minesLeft())
+	}
+
+	// the clock stops with the game, and moves after it are ignored
+	s.play(msengine.Move{Type: msengine.MoveSurrender})
+	clock.t = clock.t.Add(time.Hour)
+	if s.face() != "😵" || s.elapsed() != 12 {
+		t.Errorf("lost game shows %s at %ds", s.face(), s.elapsed())
+	}
+	if status, err := s.click(msengine.NewLocation(0, 0)); err != nil || status != msengine.StatusLost {
+		t.Errorf("click after the game got %v, %v", status, err)
+	}
+
+	// the face starts again
+	if err := s.reset(); err != nil || s.elapsed() != 0 || s.minesLeft() != 10 || s.face() != "🙂" {
+		t.Errorf("reset left %d mines at %ds, %v", s.minesLeft(), s.elapsed(), err)
+	}
+
+	if _, err := newSession("nightmare", "classic"); nil == err {
+		t.Errorf("newSession accepted an unknown difficulty")
+	}
+	if _, err := newSession("easy", "moving"); nil == err {
+		t.Errorf("newSession accepted unknown rules")
+	}
+}
+
+func TestDisplay(t *testing.T) {
+	for n, want := range map[int]string{0: "000", 7: "007", 99: "099", 1234: "999", -5: "-05", -250: "-99"} {
+		if got := display(n); got != want {
+			t.Errorf("display(%d) wanted %q got %q", n, want, got)
+		}
+	}
+}
+
+func TestCellLabel(t *testing.T) {
+	var cases = []struct {
+		v    msengine.CellView
+		want string
+	}{
+		{msengine.CellView{State: msengine.CellHidden}, ""},
+		{msengine.CellView{State: msengine.CellFlagged}, "🚩"},
+		{msengine.CellView{State: msengine.CellMine}, "💣"},
+		{msengine.CellView{State: msengine.CellRevealed}, ""},
+		{msengine.CellView{State: msengine.CellRevealed, Score: 3}, "3"},
+		{msengine.CellView{State: msengine.CellRevealed, Score: -1}, "-1"},
+	}
+	for _, testcase := range cases {
+		if got := cellLabel(testcase.v); got != testcase.want {
+			t.Errorf("cellLabel(%+v) wanted %q got %q", testcase.v, testcase.want, got)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+//go:build gui
+
+/*
+
+	Window.go - the session drawn with Fyne: the status bar above a grid of cell buttons, redrawn from a snapshot
+	after every move and the clock ticked once a second
+
+	mike@pocomotech.com
+
+*/
+
+package main
+
+import (
+	"go-mines/msengine"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// cellButton : a button for one cell, flagging it on a right click
+type cellButton struct {
+	widget.Button
+	secondary func()
+}
+
+// newCellButton -- a cell button calling tapped on left clicks and secondary on right clicks
+func newCellButton(tapped, secondary func()) *cellButton {
+	retval := &cellButton{secondary: secondary}
+	retval.ExtendBaseWidget(retval)
+	retval.OnTapped = tapped
+	return retval
+}
+
+// TappedSecondary -- a right click
+func (b *cellButton) TappedSecondary(*fyne.PointEvent) {
+	b.secondary()
+}
+
+// window : the widgets showing a session
+type window struct {
+	session *session
+	mines   *widget.Label
+	face    *widget.Button
+	clock   *widget.Label
+	cells   [][]*cellButton
+}
+
+// run -- open the window and play until it's closed
+func run(s *session) error {
+	a := app.New()
+	w := a.NewWindow("go-mines")
+	win := &window{session: s, mines: widget.NewLabel(""), clock: widget.NewLabel("")}
+	win.face = widget.NewButton("", func() {
+		if err := s.reset(); err == nil {
+			win.refresh()
+		}
+	})
+
+	snap := s.board.Snapshot()
+	grid := container.NewGridWithColumns(snap.Cols)
+	win.cells = make([][]*cellButton, snap.Rows)
+	for row := range win.cells {
+		win.cells[row] = make([]*cellButton, snap.Cols)
+		for col := range win.cells[row] {
+			l := msengine.NewLocation(row, col)
+			win.cells[row][col] = newCellButton(func() {
+				s.click(l)
+				win.refresh()
+			}, func() {
+				s.flag(l)
+				win.refresh()
+			})
+			grid.Add(win.cells[row][col])
+		}
+	}
+	bar := container.NewBorder(nil, nil, win.mines, win.clock, win.face)
+	w.SetContent(container.NewBorder(bar, nil, nil, nil, grid))
+	win.refresh()
+
+	go func() {
+		for range time.Tick(time.Second) {
+			fyne.Do(func() {
+				win.clock.SetText(display(s.elapsed()))
+			})
+		}
+	}()
+	w.ShowAndRun()
+	return nil
+}
+
+// refresh -- redraw the status bar and every cell from the board
+func (win *window) refresh() {
+	s := win.session
+	win.mines.SetText(display(s.minesLeft()))
+	win.face.SetText(s.face())
+	win.clock.SetText(display(s.elapsed()))
+
+	snap := s.board.Snapshot()
+	for row := range snap.Cells {
+		for col, v := range snap.Cells[row] {
+			b := win.cells[row][col]
+			b.SetText(cellLabel(v))
+			switch v.State {
+			case msengine.CellRevealed:
+				b.Importance = widget.LowImportance
+			case msengine.CellMine:
+				b.Importance = widget.DangerImportance
+			default:
+				b.Importance = widget.MediumImportance
+			}
+			b.Refresh()
+		}
+	}
+}
@@ -0,0 +1,42 @@
+/*
+
+	gomines-gui - minesweeper in a desktop window: a grid of cells to click, left to reveal and right to flag, under
+	the classic status bar of mines left, a face that starts a new game and a clock
+
+		gomines-gui -difficulty hard -rules chord
+
+	The window is drawn with Fyne, which go-mines doesn't otherwise need, so it builds only when asked for; without
+	the tag the command says how to rebuild it:
+
+		go get fyne.io/fyne/v2
+		go build -tags gui ./cmd/gomines-gui
+
+	The game is played entirely through msengine, the same API other front ends embed the engine with.
+
+	mike@pocomotech.com
+
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	difficulty := flag.String("difficulty", "easy", "board to play: easy, medium or hard")
+	rules := flag.String("rules", "classic", "rules of play: classic, or chord to reveal around a score whose flags are all placed by clicking it")
+	flag.Parse()
+
+	s, err := newSession(*difficulty, *rules)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := run(s); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
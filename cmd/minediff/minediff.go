@@ -0,0 +1,77 @@
+/*
+
+	minediff - report where two saved games part ways: the first move or cell at which two replays, or a replay
+	and a puzzle file, differ, with the differing region of both boards shown side by side
+
+		minediff a.json b.json
+
+	Used to track down nondeterminism, e.g. a replay that plays out differently under a newer engine
+
+	mike@pocomotech.com
+
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go-mines/mspuzzle"
+	"go-mines/msreplay"
+	"os"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: minediff <replay or puzzle file> <replay or puzzle file>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var games [2]msreplay.Replay
+	for i, filename := range flag.Args() {
+		game, err := load(filename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", filename, err)
+			os.Exit(2)
+		}
+		games[i] = game
+		fmt.Printf("%s: %s, engine %s, seed %d, %d moves\n", "ab"[i:i+1], filename, engineName(game), game.Seed,
+			len(game.Moves))
+	}
+
+	d, err := msreplay.Diff(games[0], games[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	msreplay.WriteDivergence(os.Stdout, d)
+	if nil != d {
+		os.Exit(1)
+	}
+}
+
+// load -- read a replay, or a puzzle file as a replay with no moves
+func load(filename string) (msreplay.Replay, error) {
+	replay, err := msreplay.LoadFile(filename)
+	if err == nil {
+		return replay, nil
+	}
+	puzzle, puzzleErr := mspuzzle.LoadFile(filename)
+	if puzzleErr != nil || puzzle.Layout == "" {
+		return msreplay.Replay{}, err
+	}
+	return msreplay.Replay{Format: puzzle.Format, Difficulty: "puzzle", Layout: puzzle.Layout}, nil
+}
+
+// engineName -- engine version that wrote a game, if known
+func engineName(r msreplay.Replay) string {
+	if r.Format.Engine == "" {
+		return "unknown"
+	}
+	return r.Format.Engine
+}
@@ -1,13 +1,586 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"go-mines/msanalysis"
+	"go-mines/msboard"
+	"go-mines/msbot"
+	"go-mines/msengine"
 	"go-mines/msgame"
+	"go-mines/mspuzzle"
+	"go-mines/msrender"
+	"go-mines/msreplay"
+	"go-mines/msstats"
+	"go-mines/msstore"
 	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
 	"time"
 )
 
 func main() {
-	game := msgame.New(time.Now().UnixNano())
+	var display msrender.Overrides
+	flag.StringVar(&display.Color, "color", "auto", "board colors: auto, never, 16, 256 or truecolor")
+	flag.StringVar(&display.UTF8, "utf8", "auto", "unicode board glyphs: auto, yes or no")
+	flag.StringVar(&display.Emoji, "emoji", "auto", "emoji board glyphs: auto (no), yes or no")
+	coords := flag.String("coords", "letter-number", "how cells are typed and labelled: letter-number (c4), number-number (3 7) or chess[:corner], e.g. chess:top-left")
+	rtl := flag.Bool("rtl", false, "draw row labels on the right of the board, for right-to-left reading")
+	transpose := flag.Bool("transpose", false, "play the preset boards turned on their side, e.g. hard as 30 rows of 16 instead of 16 rows of 30")
+	cascade := flag.Duration("cascade", 0, "pause between the waves of a flood reveal on terminals, e.g. 30ms; 0 shows it at once")
+	rules := flag.String("rules", "classic", "rules of play: classic, or chord to reveal around a score whose flags are all placed by stepping on it")
+	fog := flag.Int("fog", 0, "fog of war: only cells within this many of a revealed cell can be clicked (0 for none)")
+	moving := flag.Float64("moving", 0, "fraction of the mines out of sight that move every -moveevery reveals (0 for none)")
+	moveEvery := flag.Int("moveevery", 5, "reveals between mine moves with -moving")
+	antiMines := flag.Int("antimines", 0, "anti-mine variant: make this many of each board's mines anti-mines, which count -1 (0 for none)")
+	arcade := flag.Int("arcade", 0, "arcade mode: hide this many power-up treasures on each board and keep score (0 for none)")
+	boards := flag.Int("boards", 1, "boards to play at once in easy, medium and hard games, moves addressed as 2: c4")
+	lineEdit := flag.Bool("lineedit", true, "edit input lines typed at a terminal, with history on the arrow keys and tab completion")
+	practice := flag.Bool("practice", false, "practice games: bookmark <name> and restore <name> positions to try other lines, no races")
+	analysis := flag.Bool("analysis", false, "analysis games: practice plus undo and peek <loc>, never recorded in results, stats or replays")
+	undoLimit := flag.Int("undolimit", 500, "moves an analysis game keeps to take back, 0 for no limit")
+	dim := flag.Bool("dim", false, "dim numbers that already have all their flags placed")
+	bell := flag.Bool("bell", true, "ring the terminal bell when a game is won or lost")
+	verbose := flag.Bool("verbose", false, "after each move, summarize in words what it revealed and what is left")
+	status := flag.Bool("status", true, "draw the mines left, flags, safe cells, clock and board code above the board")
+	guessFree := flag.Bool("guessfree", false, "show after each move whether a safe move exists")
+	debug := flag.Bool("debug", false, "enable developer commands: xray, reveal <from>:<to>, dump, audit, and in debug builds goto, fork, lines, diff")
+	edit := flag.Bool("edit", false, "run the position editor instead of a game")
+	duel := flag.Bool("duel", false, "two players each build a board in the editor for the other to clear")
+	seed := flag.Int64("seed", 0, "lay out boards from this seed, the same boards on every machine; 0 for a new seed every run")
+	adaptive := flag.Bool("adaptive", false, "offer adaptive games, 16x16 boards with more mines while you win and fewer while you lose")
+	code := flag.String("code", "", "offer the board a board code names, as printed after a game, from the menu")
+	opening := flag.Int("opening", 0, "minimum number of cells the first click must open (0 for any)")
+	replays := flag.String("replays", "", "directory to save a replay of every finished game in")
+	replayKB := flag.Int("replaykb", 0, "largest saved replay in KB, folding the earliest moves of longer games into their starting position; 0 for no limit")
+	analyze := flag.String("analyze", "", "print a move by move review of a saved replay and exit")
+	gifFile := flag.String("gif", "", "also draw the -analyze replay as an animated GIF, a frame per move")
+	gifDelay := flag.Duration("gifdelay", msreplay.DefaultGIFDelay, "time each move of the -gif animation is shown for")
+	gifCell := flag.Int("gifcell", msreplay.DefaultGIFCell, "size of a cell of the -gif animation in pixels")
+	mistakes := flag.String("mistakes", "", "print where games were lost and the risks taken over a directory of saved replays and exit")
+	heatmap := flag.String("heatmap", "", "also draw the -mistakes map of where games were lost as a PNG file")
+	race := flag.String("race", "", "race against a saved replay, playing on its board")
+	opponent := flag.String("opponent", "", "race a computer opponent on every board: random, basic, solver or expert")
+	botSpeed := flag.Float64("botspeed", 0, "moves per second for the -opponent bot, 0 for its skill's own pace")
+	openings := flag.String("openings", "", "simulate bot games to rank first clicks on a board (easy, medium or hard) and exit")
+	policies := flag.String("policies", "", "simulate bot games with every guess policy on a board (easy, medium or hard) and exit")
+	packs := flag.String("packs", "", "directory of puzzle packs to offer from the menu, each a directory of puzzle files or a JSON bundle")
+	stats := flag.String("stats", "", "file to keep puzzle completion in, empty to keep it for this session only")
+	history := flag.String("history", "", "file to keep finished games and the skill rating in, empty to keep them for this session only")
+	store := flag.String("store", "", "keep games, replays and puzzle stats in a directory, or a SQLite database as sqlite:<file>, instead of -history, -replays and -stats; also keeps every game -openings, -policies and -calibrate play")
+	sync := flag.String("sync", "", "WebDAV URL to sync the -store with at the start and after every game, password in the URL or $GOMINES_SYNC_PASSWORD")
+	export := flag.String("export", "", "print the games in the -history file as csv or json and exit")
+	importFile := flag.String("import", "", "merge the games of another client's export into the -history file and exit")
+	importFormat := flag.String("importformat", "msonline", "format of the -import file: msonline, or csv or json from -export")
+	generate := flag.String("generate", "", "add no-guess boards of a difficulty (easy, medium or hard) to the -library file and exit")
+	library := flag.String("library", "puzzles.json", "puzzle library file for -generate")
+	games := flag.Int("games", 200, "games per first click for -openings, per policy for -policies, per mine count tried for -calibrate, boards for -generate")
+	calibrate := flag.String("calibrate", "", "find the mines giving the bot the -winrate on each board size, e.g. 9x9,16x16,16x30, and exit")
+	winRate := flag.Float64("winrate", 0.5, "bot win rate -calibrate aims for, between 0 and 1")
+	simQuery := flag.String("simquery", "", "print the simulated games kept in the -store by density (win rate by board size and mines) or guesses (games by guesses taken) and exit")
+	cpuProfile := flag.String("cpuprofile", "", "write a CPU profile of -openings, -policies, -calibrate, -generate, -mistakes or -analyze to this file")
+	memProfile := flag.String("memprofile", "", "write a heap profile to this file once -openings, -policies, -calibrate, -generate, -mistakes or -analyze finishes")
+	flag.Parse()
 
+	if *openings != "" {
+		if err := profiled(*cpuProfile, *memProfile, func() error { return rankOpenings(*openings, *games, *store) }); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *policies != "" {
+		if err := profiled(*cpuProfile, *memProfile, func() error { return comparePolicies(*policies, *games, *store) }); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *calibrate != "" {
+		if err := profiled(*cpuProfile, *memProfile, func() error { return calibrateSizes(*calibrate, *winRate, *games, *store) }); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *simQuery != "" {
+		if err := querySims(*store, *simQuery); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *generate != "" {
+		if err := profiled(*cpuProfile, *memProfile, func() error { return fillLibrary(*library, *generate, *games) }); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *export != "" {
+		if err := exportHistory(*history, *store, *export); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *importFile != "" {
+		if err := importHistory(*history, *store, *importFile, *importFormat); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *mistakes != "" {
+		if err := profiled(*cpuProfile, *memProfile, func() error { return reportMistakes(*mistakes, *heatmap) }); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *analyze != "" {
+		animation := msreplay.GIFOptions{Delay: *gifDelay, CellSize: *gifCell}
+		if err := profiled(*cpuProfile, *memProfile, func() error { return analyzeReplay(*analyze, *gifFile, animation) }); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if _, err := msrender.Detect(os.Stdout, os.Getenv, display); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	codec, err := msboard.ParseCoordinates(*coords)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	play, err := msboard.LookupRules(*rules)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	game := msgame.New(*seed)
+	game.SetDisplay(display)
+	game.SetCoordinates(codec)
+	game.SetRightToLeft(*rtl)
+	game.SetTranspose(*transpose)
+	game.SetDebug(*debug)
+	game.SetLineEditing(*lineEdit)
+	game.SetPractice(*practice)
+	game.SetAnalysis(*analysis)
+	game.SetUndoLimit(*undoLimit)
+	game.SetDimSatisfied(*dim)
+	game.SetCascadeDelay(*cascade)
+	game.SetFog(*fog)
+	game.SetRules(play)
+	game.SetArcade(*arcade)
+	if err := game.SetMovingMines(*moving, *moveEvery); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := game.SetBoards(*boards); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	game.SetGuessFree(*guessFree)
+	game.SetVerbose(*verbose)
+	game.SetStatusLine(*status)
+	if !*bell {
+		game.SetSounds(msengine.Silence{})
+	}
+	game.SetGenerator(msboard.GeneratorOptions{MinOpening: *opening, AntiMines: *antiMines})
+	game.SetReplayDir(*replays)
+	game.SetReplayLimit(*replayKB * 1024)
+	if *adaptive {
+		game.SetAdaptive(msstats.DefaultChallengeBand())
+	}
+	if *code != "" {
+		parsed, err := msboard.ParseBoardCode(*code)
+		if err == nil {
+			err = game.SetBoardCode(parsed)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if *packs != "" {
+		loaded, err := mspuzzle.LoadPacks(*packs)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		game.SetPuzzlePacks(loaded)
+	}
+	if *stats != "" {
+		if err := game.SetStatsFile(*stats); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if *history != "" {
+		if err := game.SetHistoryFile(*history); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if *store != "" {
+		s, err := msstore.Open(*store)
+		if err == nil {
+			err = game.SetStore(s)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if *sync != "" {
+		if err := syncStore(game, *sync); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if *opponent != "" {
+		skill, err := msbot.ParseSkill(*opponent)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		game.SetOpponent(skill)
+		game.SetOpponentSpeed(*botSpeed)
+	} else if *race != "" {
+		replay, err := msreplay.LoadFile(*race)
+		if err == nil {
+			err = game.SetGhost(replay)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	if *edit {
+		game.RunEditor(os.Stdin, os.Stdout)
+		return
+	}
+	if *duel {
+		game.RunDuel(os.Stdin, os.Stdout)
+		return
+	}
 	game.RunConsole(os.Stdin, os.Stdout)
 }
+
+// profiled -- run one of the batch modes, writing a CPU profile of it and a heap profile after it to the files
+// given, for go tool pprof; empty names write no profile
+func profiled(cpu, mem string, run func() error) error {
+	if cpu != "" {
+		f, err := os.Create(cpu)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err = pprof.StartCPUProfile(f); err != nil {
+			return err
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	if err := run(); err != nil {
+		return err
+	}
+
+	if mem != "" {
+		f, err := os.Create(mem)
+		if err != nil {
+			return err
+		}
+		// up to date statistics, counting everything the run freed
+		runtime.GC()
+		if err = pprof.WriteHeapProfile(f); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	return nil
+}
+
+// analyzeReplay -- print the post-game review of a replay file, drawing the game as an animated GIF too if a file
+// is given
+func analyzeReplay(filename, gifFile string, animation msreplay.GIFOptions) error {
+	replay, err := msreplay.LoadFile(filename)
+	if err != nil {
+		return err
+	}
+	notes, err := msreplay.Analyze(replay, msanalysis.ReviewProber())
+	if err != nil {
+		return err
+	}
+	if err = msreplay.WriteReport(os.Stdout, notes); err != nil {
+		return err
+	}
+
+	if gifFile != "" {
+		f, err := os.Create(gifFile)
+		if err != nil {
+			return err
+		}
+		if err = msreplay.WriteGIF(f, replay, animation); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	return nil
+}
+
+// syncStore -- sync the game's store with a WebDAV server before play starts
+func syncStore(game *msgame.Game, rawurl string) error {
+	if !game.Stored() {
+		return errors.New("-sync needs a -store to sync")
+	}
+	remote, err := msstore.NewWebDAV(rawurl, os.Getenv("GOMINES_SYNC_PASSWORD"))
+	if err != nil {
+		return err
+	}
+	report, err := game.SetSync(remote)
+	if err != nil {
+		return fmt.Errorf("-sync: %v", err)
+	}
+	fmt.Fprintln(os.Stderr, "synced:", report)
+	return nil
+}
+
+// openHistory -- the history in a file, or failing that in a store, with a function that saves it back
+func openHistory(filename, location string) (*msstats.History, func(*msstats.History) error, error) {
+	if filename != "" {
+		h, err := msstats.LoadHistory(filename)
+		return h, func(h *msstats.History) error { return msstats.SaveHistory(filename, h) }, err
+	}
+	if location == "" {
+		return nil, nil, errors.New("needs the -history file or -store")
+	}
+	s, err := msstore.Open(location)
+	if err != nil {
+		return nil, nil, err
+	}
+	h, err := msstore.LoadHistory(s)
+	return h, func(h *msstats.History) error { return msstore.SaveHistory(s, h) }, err
+}
+
+// exportHistory -- print the games of a history file or store as CSV or JSON
+func exportHistory(filename, location, format string) error {
+	h, _, err := openHistory(filename, location)
+	if err != nil {
+		return fmt.Errorf("-export: %v", err)
+	}
+	return msstats.Export(os.Stdout, h.Games, format)
+}
+
+// importHistory -- merge the games of an export into a history file or store, leaving out those already in it
+func importHistory(filename, location, from, format string) error {
+	f, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	games, err := msstats.Import(f, format)
+	if err != nil {
+		return fmt.Errorf("%s: %v", from, err)
+	}
+
+	h, save, err := openHistory(filename, location)
+	if err != nil {
+		return fmt.Errorf("-import: %v", err)
+	}
+	added := h.Merge(games)
+	if err = save(h); err != nil {
+		return err
+	}
+	fmt.Printf("imported %d of %d games, %d were already in the history\n", added, len(games), len(games)-added)
+	return nil
+}
+
+// reportMistakes -- print the mistake report over a directory of replays, drawing the losses as a PNG too if a file
+// is given
+func reportMistakes(dir, heatmap string) error {
+	replays, err := msreplay.LoadDir(dir)
+	if err != nil {
+		return err
+	}
+	report, err := msanalysis.Mistakes(replays, msanalysis.ReviewProber())
+	if err != nil {
+		return err
+	}
+	if err = msanalysis.WriteMistakes(os.Stdout, report); err != nil {
+		return err
+	}
+
+	if heatmap != "" {
+		f, err := os.Create(heatmap)
+		if err != nil {
+			return err
+		}
+		if err = report.Losses.WritePNG(f, 16); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	return nil
+}
+
+// simRecorder -- a recorder keeping every simulated game in a store, numbered from when it's made; nil for no store
+func simRecorder(location string) (msanalysis.Recorder, error) {
+	if location == "" {
+		return nil, nil
+	}
+	s, err := msstore.Open(location)
+	if err != nil {
+		return nil, err
+	}
+	run, played := time.Now(), 0
+	return func(g msanalysis.GameResult) error {
+		played++
+		return msstore.AddSimGame(s, msstore.SimKey(run, played), g)
+	}, nil
+}
+
+// querySims -- print the simulated games kept in a store by density or by the guesses they took
+func querySims(location, query string) error {
+	if location == "" {
+		return errors.New("-simquery needs the -store the games were kept in")
+	}
+	s, err := msstore.Open(location)
+	if err != nil {
+		return err
+	}
+	games, err := msstore.LoadSimGames(s)
+	if err != nil {
+		return err
+	}
+	if len(games) == 0 {
+		return fmt.Errorf("no simulated games in %s, run -openings, -policies or -calibrate with -store first", location)
+	}
+
+	switch query {
+	case "density":
+		return msanalysis.WriteDensities(os.Stdout, msanalysis.ByDensity(games))
+	case "guesses":
+		return msanalysis.WriteGuesses(os.Stdout, msanalysis.GuessDistribution(games))
+	}
+	return fmt.Errorf("-simquery: unknown query %q, use density or guesses", query)
+}
+
+// rankOpenings -- print the bot's win rate from every distinct first click on a board, keeping every game in a
+// store if one is given
+func rankOpenings(difficulty string, games int, location string) error {
+	b := msboard.NewBoard(difficulty)
+	if nil == b {
+		return fmt.Errorf("unsupported board difficulty %q", difficulty)
+	}
+	record, err := simRecorder(location)
+	if err != nil {
+		return err
+	}
+	results, err := msanalysis.Openings(games, b.Rows(), b.Cols(), b.MineCount(),
+		msanalysis.OpeningCandidates(b.Rows(), b.Cols()), msbot.Player{}, record)
+	if err != nil {
+		return err
+	}
+	return msanalysis.WriteOpenings(os.Stdout, results)
+}
+
+// comparePolicies -- print the bot's win rate with every guess policy, starting from the recommended first click,
+// keeping every game in a store if one is given
+func comparePolicies(difficulty string, games int, location string) error {
+	b := msboard.NewBoard(difficulty)
+	if nil == b {
+		return fmt.Errorf("unsupported board difficulty %q", difficulty)
+	}
+	record, err := simRecorder(location)
+	if err != nil {
+		return err
+	}
+	results, err := msanalysis.ComparePolicies(games, b.Rows(), b.Cols(), b.MineCount(),
+		msanalysis.OpeningHint(difficulty, b.Rows(), b.Cols()), msbot.Policies(), msbot.Player{}, record)
+	if err != nil {
+		return err
+	}
+	return msanalysis.WritePolicies(os.Stdout, results)
+}
+
+// calibrateSizes -- print the mine count giving the bot a target win rate on each of a comma separated list of
+// board sizes, rows x cols, keeping every game in a store if one is given
+func calibrateSizes(sizes string, target float64, games int, location string) error {
+	record, err := simRecorder(location)
+	if err != nil {
+		return err
+	}
+	var results []msanalysis.Calibration
+	for _, size := range strings.Split(sizes, ",") {
+		var rows, cols int
+		if n, _ := fmt.Sscanf(strings.TrimSpace(size), "%dx%d", &rows, &cols); n != 2 {
+			return fmt.Errorf("-calibrate: bad board size %q, wanted rows x cols, e.g. 16x30", size)
+		}
+		c, err := msanalysis.Calibrate(target, games, rows, cols, msbot.Player{}, record)
+		if err != nil {
+			return err
+		}
+		results = append(results, c)
+	}
+	return msanalysis.WriteCalibrations(os.Stdout, results)
+}
+
+// fillLibrary -- generate no-guess boards from the recommended first click and add the new ones to a puzzle library
+func fillLibrary(filename, difficulty string, boards int) error {
+	b := msboard.NewBoard(difficulty)
+	if nil == b {
+		return fmt.Errorf("unsupported board difficulty %q", difficulty)
+	}
+	lib, err := mspuzzle.LoadLibrary(filename)
+	if err != nil {
+		return err
+	}
+
+	first := msanalysis.OpeningHint(difficulty, b.Rows(), b.Cols())
+	opts := msboard.GeneratorOptions{MinOpening: 1}
+	added := 0
+	for i := 0; i < boards; i++ {
+		p, _, err := mspuzzle.Generate(difficulty, b.Rows(), b.Cols(), b.MineCount(), first, opts, 1000)
+		if err != nil {
+			return err
+		}
+		if _, ok, err := lib.Add(p, difficulty); err != nil {
+			return err
+		} else if ok {
+			added++
+		}
+	}
+	if err = mspuzzle.SaveLibrary(filename, lib); err != nil {
+		return err
+	}
+
+	fmt.Printf("added %d of %d boards, %d were already in %s\n", added, boards, boards-added, filename)
+	for _, grade := range []string{"gentle", "tricky", "fiendish"} {
+		fmt.Printf("%-8s %d\n", grade, len(lib.Tagged(grade)))
+	}
+	return nil
+}
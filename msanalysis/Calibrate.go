@@ -0,0 +1,107 @@
+/*
+
+	Calibrate.go - mine counts that give a chosen win rate, found by letting the bot play boards of each size at
+	different densities
+
+	The bot's win rate falls as mines are added, so the count is found by bisection: each step plays a batch of
+	games at the middle count and keeps the half the target lies in. Every batch is laid out from the same seeds,
+	which keeps the rates of neighboring counts in order despite the noise of a finite batch.
+
+	mike@pocomotech.com
+
+*/
+
+package msanalysis
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/msbot"
+	"io"
+)
+
+// Calibration : the most mines a board size can hold with the bot still winning at least the target rate
+type Calibration struct {
+	Rows, Cols int
+	Mines      int
+	Target     float64 // win rate searched for
+	Games      int     // played at Mines
+	Wins       int
+}
+
+// WinRate -- fraction of games won at the calibrated count
+func (c Calibration) WinRate() float64 {
+	if c.Games == 0 {
+		return 0
+	}
+	return float64(c.Wins) / float64(c.Games)
+}
+
+// Density -- mines per cell
+func (c Calibration) Density() float64 {
+	return float64(c.Mines) / float64(c.Rows*c.Cols)
+}
+
+// Calibrate -- the largest mine count at which the player wins at least target of n games on a board of rows by
+// cols, started from the opening hint for the size. Every game of every mine count tried is told to record, if not
+// nil
+func Calibrate(target float64, n, rows, cols int, player msbot.Player, record Recorder) (Calibration, error) {
+	if n < 1 {
+		return Calibration{}, fmt.Errorf("need at least one game, got %d", n)
+	}
+	if target <= 0 || target > 1 {
+		return Calibration{}, fmt.Errorf("target win rate %v isn't above 0 and at most 1", target)
+	}
+	if rows < 1 || cols < 1 {
+		return Calibration{}, fmt.Errorf("can't calibrate a %dx%d board", rows, cols)
+	}
+
+	first := OpeningHint("custom", rows, cols)
+	retval := Calibration{Rows: rows, Cols: cols, Target: target}
+	// a board without mines is always won; the first click must stay clear
+	low, high := 0, rows*cols-1
+	if _, err := retval.play(n, 0, first, player, record); err != nil {
+		return Calibration{}, err
+	}
+	for low < high {
+		mid := (low + high + 1) / 2
+		probe := retval
+		won, err := probe.play(n, mid, first, player, record)
+		if err != nil {
+			return Calibration{}, err
+		}
+		if won {
+			low, retval = mid, probe
+		} else {
+			high = mid - 1
+		}
+	}
+	return retval, nil
+}
+
+// play -- play n games at a mine count, recording them, and report whether they met the target
+func (c *Calibration) play(n, mines int, first msboard.Location, player msbot.Player, record Recorder) (bool,
+	error) {
+	games, wins, _, err := playGames(n, c.Rows, c.Cols, mines, first, player, GameResult{Mode: ModeCalibrate}, record)
+	if err != nil {
+		return false, err
+	}
+	c.Mines, c.Games, c.Wins = mines, games, wins
+	return c.WinRate() >= c.Target, nil
+}
+
+// WriteCalibrations -- print calibrations as a table, one board size per line, each with its preset size as a
+// board code writes it
+func WriteCalibrations(out io.Writer, results []Calibration) error {
+	if _, err := fmt.Fprintf(out, "%-12s %7s %8s %7s %8s\n", "preset", "mines", "density", "games", "win %"); err != nil {
+		return err
+	}
+	for _, c := range results {
+		preset := fmt.Sprintf("%dx%dx%d", c.Rows, c.Cols, c.Mines)
+		if _, err := fmt.Fprintf(out, "%-12s %7d %7.1f%% %7d %7.1f%%\n", preset, c.Mines, 100*c.Density(), c.Games,
+			100*c.WinRate()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+/*
+	Test functions for difficulty calibration
+
+	mike@pocomotech.com
+*/
+
+package msanalysis
+
+import (
+	"bytes"
+	"go-mines/msbot"
+	"strings"
+	"testing"
+)
+
+func TestCalibrate(t *testing.T) {
+	c, err := Calibrate(0.5, 20, 9, 9, msbot.Player{}, nil)
+	if err != nil {
+		t.Fatalf("Calibrate failed: %s", err)
+	}
+	if c.Rows != 9 || c.Cols != 9 || c.Games != 20 || c.WinRate() < 0.5 {
+		t.Errorf("calibration inconsistent: %+v", c)
+	}
+	// the bot wins most easy games, so half its games are lost somewhere above the easy preset's 10 mines
+	if c.Mines <= 10 || c.Mines >= 40 {
+		t.Errorf("50%% win rate on 9x9 wanted between 10 and 40 mines got %d", c.Mines)
+	}
+
+	// one more mine misses the target, and a harder target takes fewer mines
+	over := c
+	if won, _ := over.play(20, c.Mines+1, OpeningHint("custom", 9, 9), msbot.Player{}, nil); won {
+		t.Errorf("%d mines still wins %.0f%%", c.Mines+1, 100*over.WinRate())
+	}
+	if harder, _ := Calibrate(0.9, 20, 9, 9, msbot.Player{}, nil); harder.Mines > c.Mines {
+		t.Errorf("90%% target took %d mines, more than 50%%'s %d", harder.Mines, c.Mines)
+	}
+
+	out := bytes.NewBufferString("")
+	WriteCalibrations(out, []Calibration{c})
+	if !strings.Contains(out.String(), "9x9x") || strings.Count(out.String(), "\n") != 2 {
+		t.Errorf("WriteCalibrations output unexpected:\n%s", out.String())
+	}
+
+	for _, bad := range [][2]float64{{0, 20}, {1.5, 20}, {0.5, 0}} {
+		if _, err := Calibrate(bad[0], int(bad[1]), 9, 9, msbot.Player{}, nil); nil == err {
+			t.Errorf("Calibrate(%v, %v) should fail", bad[0], bad[1])
+		}
+	}
+}
@@ -0,0 +1,142 @@
+/*
+
+	HeatMap.go - per-cell mine likelihood maps, rendered as text or PNG
+
+	mike@pocomotech.com
+
+*/
+
+// Package msanalysis -- offline analysis tools for boards, generators and games
+package msanalysis
+
+import (
+	"bufio"
+	"fmt"
+	"go-mines/msboard"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// HeatMap : a value between 0 and 1 for every cell of a board, normally the probability it holds a mine
+type HeatMap struct {
+	Rows, Cols int
+	Values     [][]float64
+}
+
+// NewHeatMap -- heat map over existing per-cell values, such as solver mine probabilities
+func NewHeatMap(values [][]float64) (HeatMap, error) {
+	if len(values) == 0 || len(values[0]) == 0 {
+		return HeatMap{}, fmt.Errorf("heat map needs at least one cell")
+	}
+	for row := range values {
+		if len(values[row]) != len(values[0]) {
+			return HeatMap{}, fmt.Errorf("heat map row %d has %d cells, expected %d", row+1, len(values[row]), len(values[0]))
+		}
+	}
+	return HeatMap{Rows: len(values), Cols: len(values[0]), Values: values}, nil
+}
+
+// MineFrequency -- generate n boards and report how often each cell received a mine, for checking generator
+// uniformity. The safe spot is passed to the generator exactly as a first click would be
+func MineFrequency(n, rows, cols, mines int, safespot msboard.Location, opts msboard.GeneratorOptions) (HeatMap, error) {
+	if n < 1 {
+		return HeatMap{}, fmt.Errorf("need at least one board, got %d", n)
+	}
+
+	counts := make([][]float64, rows)
+	for row := range counts {
+		counts[row] = make([]float64, cols)
+	}
+
+	for i := 0; i < n; i++ {
+		b := msboard.NewCustomBoard(rows, cols, mines)
+		if nil == b {
+			return HeatMap{}, fmt.Errorf("can't create a %dx%d board with %d mines", rows, cols, mines)
+		}
+		if err := b.InitializeWithOptions(safespot, opts); err != nil {
+			return HeatMap{}, err
+		}
+		for _, l := range b.Mines() {
+			counts[l.Row()][l.Col()]++
+		}
+	}
+
+	for row := range counts {
+		for col := range counts[row] {
+			counts[row][col] /= float64(n)
+		}
+	}
+	return NewHeatMap(counts)
+}
+
+// Range -- smallest and largest values in the map
+func (h HeatMap) Range() (min, max float64) {
+	min, max = h.Values[0][0], h.Values[0][0]
+	for row := range h.Values {
+		for _, v := range h.Values[row] {
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+	}
+	return
+}
+
+// normalized -- value scaled to 0..1 across the map's range, so small differences stay visible
+func normalized(v, min, max float64) float64 {
+	if max == min {
+		return 0
+	}
+	return (v - min) / (max - min)
+}
+
+// shades : text heat map characters from coolest to hottest
+const shades = " .:-=+*#%@"
+
+// WriteText -- render the map as a character grid, one shade character per cell scaled between the map's
+// minimum and maximum, followed by a legend
+func (h HeatMap) WriteText(out io.Writer) error {
+	w := bufio.NewWriter(out)
+	min, max := h.Range()
+
+	for row := 0; row < h.Rows; row++ {
+		fmt.Fprintf(w, "%3d |", row+1)
+		for col := 0; col < h.Cols; col++ {
+			shade := int(normalized(h.Values[row][col], min, max) * float64(len(shades)-1))
+			w.WriteByte(shades[shade])
+		}
+		fmt.Fprintln(w, "|")
+	}
+	fmt.Fprintf(w, "scale %q from %.4f to %.4f\n", shades, min, max)
+
+	return w.Flush()
+}
+
+// WritePNG -- render the map as a PNG image with square cells of cellSize pixels, blue for the coolest cells
+// through to red for the hottest
+func (h HeatMap) WritePNG(out io.Writer, cellSize int) error {
+	if cellSize < 1 {
+		return fmt.Errorf("cell size must be positive, got %d", cellSize)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, h.Cols*cellSize, h.Rows*cellSize))
+	min, max := h.Range()
+	for row := 0; row < h.Rows; row++ {
+		for col := 0; col < h.Cols; col++ {
+			heat := normalized(h.Values[row][col], min, max)
+			c := color.RGBA{uint8(255 * heat), 0, uint8(255 * (1 - heat)), 255}
+			for y := row * cellSize; y < (row+1)*cellSize; y++ {
+				for x := col * cellSize; x < (col+1)*cellSize; x++ {
+					img.SetRGBA(x, y, c)
+				}
+			}
+		}
+	}
+
+	return png.Encode(out, img)
+}
@@ -0,0 +1,76 @@
+/*
+	Test functions for heat maps
+
+	mike@pocomotech.com
+*/
+
+package msanalysis
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"image/png"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestNewHeatMap(t *testing.T) {
+	if _, err := NewHeatMap(nil); err == nil {
+		t.Errorf("NewHeatMap() accepted an empty map")
+	}
+	if _, err := NewHeatMap([][]float64{{0, 1}, {1}}); err == nil {
+		t.Errorf("NewHeatMap() accepted ragged rows")
+	}
+}
+
+func TestMineFrequency(t *testing.T) {
+	rand.Seed(1995)
+	safe := msboard.NewLocation(0, 0)
+
+	h, err := MineFrequency(200, 9, 9, 10, safe, msboard.GeneratorOptions{})
+	if err != nil {
+		t.Fatalf("MineFrequency() failed: %s", err)
+	}
+	if h.Values[0][0] != 0 {
+		t.Errorf("Safe spot received mines with frequency %f", h.Values[0][0])
+	}
+
+	// every board has exactly 10 mines, so the frequencies must sum to 10
+	total := 0.0
+	for row := range h.Values {
+		for _, v := range h.Values[row] {
+			total += v
+		}
+	}
+	if total < 9.999 || total > 10.001 {
+		t.Errorf("MineFrequency() values sum to %f, wanted 10", total)
+	}
+
+	if _, err = MineFrequency(0, 9, 9, 10, safe, msboard.GeneratorOptions{}); err == nil {
+		t.Errorf("MineFrequency() accepted zero boards")
+	}
+}
+
+func TestHeatMapRendering(t *testing.T) {
+	h, _ := NewHeatMap([][]float64{{0, 0.5}, {0.25, 1}})
+
+	text := bytes.NewBufferString("")
+	h.WriteText(text)
+	want := "  1 | =|\n  2 |:@|\n"
+	if !strings.HasPrefix(text.String(), want) {
+		t.Errorf("WriteText() wanted prefix %q got %q", want, text.String())
+	}
+
+	buf := bytes.NewBufferString("")
+	if err := h.WritePNG(buf, 4); err != nil {
+		t.Fatalf("WritePNG() failed: %s", err)
+	}
+	img, err := png.Decode(buf)
+	if err != nil || img.Bounds().Dx() != 8 || img.Bounds().Dy() != 8 {
+		t.Errorf("WritePNG() produced an unexpected image, err %v", err)
+	}
+	if r, _, b, _ := img.At(7, 7).RGBA(); r>>8 != 255 || b != 0 {
+		t.Errorf("Hottest cell should be red")
+	}
+}
@@ -0,0 +1,190 @@
+/*
+
+	Mistakes.go - where a player's games go wrong, found by reviewing a whole archive of replays: the cells where
+	mines went off and mistakes were made, as heat maps, and the risks the player accepted when guessing
+
+	mike@pocomotech.com
+
+*/
+
+package msanalysis
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/msreplay"
+	"go-mines/mssolver"
+	"io"
+	"strings"
+)
+
+// riskBands : guesses are counted in bands of this many, each 1/riskBands wide
+const riskBands = 10
+
+// ReviewProber -- the prober reviews of finished games judge risks with: Endgame's exact probabilities, which honor
+// the number of mines left as Frontier's don't, or the sampler's estimates on positions too big to enumerate
+func ReviewProber() msreplay.Prober {
+	return mssolver.Fallback{Exact: mssolver.Endgame{}}
+}
+
+// MistakeReport : the mistakes over the replays of one board size
+type MistakeReport struct {
+	Rows, Cols int
+	Games      int
+	Lost       int
+	Skipped    int     // replays of other sizes, or that couldn't be reviewed
+	Losses     HeatMap // fraction of lost games that ended on each cell
+	Mistakes   HeatMap // mistakes made on each cell, per game
+
+	Guesses     int            // reveals that weren't certainly safe
+	Unnecessary int            // guesses made while a certainly safe reveal existed
+	Suboptimal  int            // guesses riskier than the best available
+	LostGuess   int            // losses on a guess that wasn't a mistake
+	LostMistake int            // losses on a mistake
+	Risk        float64        // mean chance of a mine on the guesses made
+	BestRisk    float64        // mean lowest chance available when they were made
+	Bands       [riskBands]int // guesses by chance of a mine: 0-10%, 10-20% and so on
+}
+
+// Mistakes -- review every replay with a prober and report on the board size most of them were played on, so the
+// heat maps line up cell for cell. Replays of other sizes are counted as skipped
+func Mistakes(replays []msreplay.Replay, p msreplay.Prober) (MistakeReport, error) {
+	type size struct{ rows, cols int }
+	boards := make([]*msboard.Board, len(replays))
+	counts := map[size]int{}
+	var common size
+	for i, r := range replays {
+		b, err := r.Board()
+		if err != nil {
+			continue
+		}
+		boards[i] = b
+		s := size{b.Rows(), b.Cols()}
+		counts[s]++
+		if counts[s] > counts[common] {
+			common = s
+		}
+	}
+	if counts[common] == 0 {
+		return MistakeReport{}, fmt.Errorf("no replays to review")
+	}
+
+	retval := MistakeReport{Rows: common.rows, Cols: common.cols}
+	losses, mistakes := grid(common.rows, common.cols), grid(common.rows, common.cols)
+	for i, r := range replays {
+		if nil == boards[i] || boards[i].Rows() != common.rows || boards[i].Cols() != common.cols {
+			retval.Skipped++
+			continue
+		}
+		notes, err := msreplay.Analyze(r, p)
+		if err != nil {
+			retval.Skipped++
+			continue
+		}
+
+		retval.Games++
+		for _, a := range notes {
+			l := a.Move.Location
+			mistake := a.Verdict == msreplay.VerdictUnnecessaryGuess || a.Verdict == msreplay.VerdictSuboptimal
+			switch a.Verdict {
+			case msreplay.VerdictGuess, msreplay.VerdictSuboptimal, msreplay.VerdictUnnecessaryGuess:
+				retval.Guesses++
+				retval.Risk += a.Risk
+				retval.BestRisk += a.BestRisk
+				retval.Bands[band(a.Risk)]++
+			}
+			if a.Verdict == msreplay.VerdictUnnecessaryGuess {
+				retval.Unnecessary++
+			} else if a.Verdict == msreplay.VerdictSuboptimal {
+				retval.Suboptimal++
+			}
+			if mistake {
+				mistakes[l.Row()][l.Col()]++
+			}
+			if a.Exploded {
+				retval.Lost++
+				losses[l.Row()][l.Col()]++
+				if mistake {
+					retval.LostMistake++
+				} else {
+					retval.LostGuess++
+				}
+			}
+		}
+	}
+
+	if retval.Guesses > 0 {
+		retval.Risk /= float64(retval.Guesses)
+		retval.BestRisk /= float64(retval.Guesses)
+	}
+	scale(losses, retval.Lost)
+	scale(mistakes, retval.Games)
+	retval.Losses, _ = NewHeatMap(losses)
+	retval.Mistakes, _ = NewHeatMap(mistakes)
+	return retval, nil
+}
+
+// grid -- a rows x cols grid of zeros
+func grid(rows, cols int) [][]float64 {
+	retval := make([][]float64, rows)
+	for row := range retval {
+		retval[row] = make([]float64, cols)
+	}
+	return retval
+}
+
+// scale -- divide every value by n, leaving them alone for n 0
+func scale(values [][]float64, n int) {
+	if n == 0 {
+		return
+	}
+	for row := range values {
+		for col := range values[row] {
+			values[row][col] /= float64(n)
+		}
+	}
+}
+
+// band -- the risk band a chance of a mine falls in, certain mines counted in the top band. Exact probabilities
+// are fractions, so one on a band's lower edge, such as a fifth, may come out a rounding error short of it
+func band(risk float64) int {
+	if retval := int(risk*riskBands + 1e-9); retval < riskBands {
+		return retval
+	}
+	return riskBands - 1
+}
+
+// WriteMistakes -- print the report: totals, the guesses by risk, and heat maps of where games were lost and
+// mistakes made
+func WriteMistakes(out io.Writer, r MistakeReport) error {
+	fmt.Fprintf(out, "%d games on %dx%d boards", r.Games, r.Cols, r.Rows)
+	if r.Skipped > 0 {
+		fmt.Fprintf(out, ", %d replays of other sizes or unreadable skipped", r.Skipped)
+	}
+	fmt.Fprintf(out, "\nlost %d: %d on a guess that was the best available, %d on a mistake\n", r.Lost, r.LostGuess,
+		r.LostMistake)
+	fmt.Fprintf(out, "%d guesses, %d while a safe reveal existed, %d riskier than the best available\n", r.Guesses,
+		r.Unnecessary, r.Suboptimal)
+	if r.Guesses > 0 {
+		fmt.Fprintf(out, "average risk taken %.1f%%, lowest available %.1f%%\n", 100*r.Risk, 100*r.BestRisk)
+
+		most := 0
+		for _, n := range r.Bands {
+			if n > most {
+				most = n
+			}
+		}
+		fmt.Fprintln(out, "\nguesses by chance of a mine:")
+		for i, n := range r.Bands {
+			fmt.Fprintf(out, "%3d-%3d%% |%-40s %d\n", 100*i/riskBands, 100*(i+1)/riskBands,
+				strings.Repeat("#", (40*n+most-1)/most), n)
+		}
+	}
+
+	fmt.Fprintln(out, "\nwhere games were lost:")
+	if err := r.Losses.WriteText(out); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "\nwhere mistakes were made:")
+	return r.Mistakes.WriteText(out)
+}
@@ -0,0 +1,155 @@
+/*
+	Test functions for mistake reports
+
+	mike@pocomotech.com
+*/
+
+package msanalysis
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"go-mines/msreplay"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestMistakes(t *testing.T) {
+	reveal := func(row, col int) []msboard.Move {
+		return []msboard.Move{{Type: msboard.MoveReveal, Location: msboard.NewLocation(row, col)}}
+	}
+	// no safe cell: B1, A2 and B2 each hide the mine a third of the time, C1 and C2 half the time
+	replays := []msreplay.Replay{
+		{Layout: "1*./..*", Moves: reveal(1, 0)}, // a guess, and safe
+		{Layout: "1*./..*", Moves: reveal(1, 2)}, // a riskier guess than needed, lost
+		{Layout: "1*./..*", Moves: reveal(0, 1)}, // the best guess, lost
+		{Layout: "1*/..", Moves: reveal(1, 0)},   // another size
+	}
+
+	r, err := Mistakes(replays, ReviewProber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Rows != 2 || r.Cols != 3 || r.Games != 3 || r.Skipped != 1 {
+		t.Fatalf("report on %dx%d, %d games, %d skipped", r.Rows, r.Cols, r.Games, r.Skipped)
+	}
+	if r.Lost != 2 || r.LostGuess != 1 || r.LostMistake != 1 || r.Guesses != 3 || r.Suboptimal != 1 ||
+		r.Unnecessary != 0 {
+		t.Errorf("report %+v", r)
+	}
+	if r.Bands[3] != 2 || r.Bands[5] != 1 {
+		t.Errorf("bands %v", r.Bands)
+	}
+	if math.Abs(r.Risk-(1.0/3+0.5+1.0/3)/3) > 1e-9 || math.Abs(r.BestRisk-1.0/3) > 1e-9 {
+		t.Errorf("risk %v, best %v", r.Risk, r.BestRisk)
+	}
+	if r.Losses.Values[1][2] != 0.5 || r.Losses.Values[0][1] != 0.5 || r.Losses.Values[1][0] != 0 {
+		t.Errorf("losses %v", r.Losses.Values)
+	}
+	if math.Abs(r.Mistakes.Values[1][2]-1.0/3) > 1e-9 || r.Mistakes.Values[0][1] != 0 {
+		t.Errorf("mistakes %v", r.Mistakes.Values)
+	}
+
+	var out bytes.Buffer
+	if err := WriteMistakes(&out, r); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"3 games on 3x2 boards, 1 replays", "lost 2: 1 on a guess", "30- 40% |####",
+		"where games were lost:", "where mistakes were made:"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, out.String())
+		}
+	}
+
+	if _, err := Mistakes(nil, ReviewProber()); err == nil {
+		t.Error("report on no replays")
+	}
+}
+
+func TestReviewRisks(t *testing.T) {
+	// opening A1 leaves 4 mines for the rest of the board; 3 of them are among the cells next to the opening, so
+	// A3 hides a mine a fifth of the time. Counting arrangements along the edge alone, as Frontier does, gives half
+	replay := msreplay.Replay{Layout: "...*/...*/*..*/....", Moves: []msboard.Move{
+		{Type: msboard.MoveReveal, Location: msboard.NewLocation(0, 0)},
+		{Type: msboard.MoveReveal, Location: msboard.NewLocation(2, 0)},
+	}}
+	positions, err := replay.Positions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := bruteForce(positions[1], msboard.NewLocation(2, 0))
+	if math.Abs(want-0.2) > 1e-9 {
+		t.Fatalf("brute force gives A3 %v", want)
+	}
+
+	notes, err := msreplay.Analyze(replay, ReviewProber())
+	if err != nil || len(notes) != 2 {
+		t.Fatalf("Analyze got %v, %v", notes, err)
+	}
+	if math.Abs(notes[1].Risk-want) > 1e-9 || !notes[1].Exploded {
+		t.Errorf("A3 reviewed as a %v risk, exploded %v; brute force gives %v", notes[1].Risk, notes[1].Exploded,
+			want)
+	}
+
+	// the mistake report takes the same risk
+	r, err := Mistakes([]msreplay.Replay{replay}, ReviewProber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Guesses != 1 || math.Abs(r.Risk-want) > 1e-9 || r.Bands[2] != 1 || r.Lost != 1 {
+		t.Errorf("report on the A3 guess: %d guesses, risk %v, bands %v", r.Guesses, r.Risk, r.Bands)
+	}
+}
+
+// bruteForce -- chance a cell holds a mine, counting every placement of the mines left over the hidden cells that
+// fits the revealed scores
+func bruteForce(s msboard.Snapshot, target msboard.Location) float64 {
+	var hidden []msboard.Location
+	for row := range s.Cells {
+		for col, v := range s.Cells[row] {
+			if v.State != msboard.CellRevealed {
+				hidden = append(hidden, msboard.NewLocation(row, col))
+			}
+		}
+	}
+
+	fitting, mined := 0, 0
+	for set := 0; set < 1<<uint(len(hidden)); set++ {
+		mines := make(map[msboard.Location]bool)
+		for i, l := range hidden {
+			if set&(1<<uint(i)) != 0 {
+				mines[l] = true
+			}
+		}
+		if len(mines) != s.Mines || !fits(s, mines) {
+			continue
+		}
+		fitting++
+		if mines[target] {
+			mined++
+		}
+	}
+	return float64(mined) / float64(fitting)
+}
+
+// fits -- true if every revealed score counts the mines around it
+func fits(s msboard.Snapshot, mines map[msboard.Location]bool) bool {
+	for row := range s.Cells {
+		for col, v := range s.Cells[row] {
+			if v.State != msboard.CellRevealed {
+				continue
+			}
+			count := 0
+			for _, n := range s.Neighbors(msboard.NewLocation(row, col)) {
+				if mines[n] {
+					count++
+				}
+			}
+			if count != v.Score {
+				return false
+			}
+		}
+	}
+	return true
+}
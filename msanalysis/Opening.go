@@ -0,0 +1,174 @@
+/*
+
+	Opening.go - which first click gives the best chance of winning, found by letting the bot play many games
+
+	mike@pocomotech.com
+
+*/
+
+package msanalysis
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/msbot"
+	"io"
+	"sort"
+)
+
+// OpeningResult : how the bot fared over a batch of games started from one cell
+type OpeningResult struct {
+	Location msboard.Location
+	Games    int
+	Wins     int
+	Clicks   int // total clicks over every game, won or lost
+}
+
+// WinRate -- fraction of games won
+func (r OpeningResult) WinRate() float64 {
+	if r.Games == 0 {
+		return 0
+	}
+	return float64(r.Wins) / float64(r.Games)
+}
+
+// knownOpenings : best first clicks for the standard boards, from
+//
+//	gomines -openings <difficulty> -games 1000
+//
+// The middle of an edge does best on every size, winning about 89% of easy, 88% of medium and 72% of hard games
+// for the default bot; the center is a few points worse on easy and medium and ten points worse on hard
+var knownOpenings = map[string]msboard.Location{
+	"easy":   msboard.NewLocation(0, 4),
+	"medium": msboard.NewLocation(7, 0),
+	"hard":   msboard.NewLocation(7, 0),
+}
+
+// OpeningHint -- the recommended first click for a board of the given difficulty and size, turned with the board if
+// it's a transposed preset; boards without simulation results get the middle of the top edge
+func OpeningHint(difficulty string, rows, cols int) msboard.Location {
+	if l, ok := knownOpenings[difficulty]; ok {
+		if preset := msboard.NewBoard(difficulty); nil != preset && rows != preset.Rows() && rows == preset.Cols() {
+			return msboard.TransformTranspose.Location(l, preset.Rows(), preset.Cols())
+		}
+		return l
+	}
+	return msboard.NewLocation(0, (cols-1)/2)
+}
+
+// OpeningCandidates -- one first click from each group of cells that are alike under the board's mirror
+// symmetries, i.e. the top left quarter of the board, middle row and column included
+func OpeningCandidates(rows, cols int) []msboard.Location {
+	retval := make([]msboard.Location, 0, (rows+1)/2*((cols+1)/2))
+	for row := 0; row < (rows+1)/2; row++ {
+		for col := 0; col < (cols+1)/2; col++ {
+			retval = append(retval, msboard.NewLocation(row, col))
+		}
+	}
+	return retval
+}
+
+// Openings -- have the player play n games from each candidate first click, returning the results best first.
+// Game i from every candidate is laid out from the same seed, so the candidates face the same run of boards.
+// Every game is told to record, if not nil
+func Openings(n, rows, cols, mines int, candidates []msboard.Location, player msbot.Player,
+	record Recorder) ([]OpeningResult, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("need at least one game, got %d", n)
+	}
+
+	retval := make([]OpeningResult, 0, len(candidates))
+	for _, first := range candidates {
+		result := OpeningResult{Location: first}
+		var err error
+		result.Games, result.Wins, result.Clicks, err = playGames(n, rows, cols, mines, first, player,
+			GameResult{Mode: ModeOpenings}, record)
+		if err != nil {
+			return nil, err
+		}
+		retval = append(retval, result)
+	}
+
+	sort.SliceStable(retval, func(i, j int) bool {
+		return retval[i].Wins > retval[j].Wins
+	})
+	return retval, nil
+}
+
+// playGames -- have the player play n games from one first click, game i laid out by a PCG32 generator of its own
+// seeded with i+1, so the games don't depend on math/rand or on anything else drawing from it. record, if not nil,
+// is given game for each with the board and results filled in. Returns the games played, the wins and the total
+// clicks
+func playGames(n, rows, cols, mines int, first msboard.Location, player msbot.Player, game GameResult,
+	record Recorder) (games, wins, clicks int, err error) {
+	for i := 0; i < n; i++ {
+		seed := int64(i) + 1
+		b, err := layOut(rows, cols, mines, first, seed)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		// the bot's opening reveal of first is then a click on a revealed cell, unless it already won the game
+		status, played, guesses := b.Status(), 1, 0
+		if status == msboard.StatusPlaying {
+			if status, played, guesses, err = player.PlayCounted(b, first); err != nil {
+				return 0, 0, 0, err
+			}
+		}
+		games++
+		clicks += played
+		if status == msboard.StatusWon {
+			wins++
+		}
+
+		if nil != record {
+			game.Rows, game.Cols, game.Mines, game.First = rows, cols, mines, locationName(first)
+			game.Seed, game.Won, game.Clicks, game.Guesses = seed, status == msboard.StatusWon, played, guesses
+			if err := record(game); err != nil {
+				return 0, 0, 0, err
+			}
+		}
+	}
+	return games, wins, clicks, nil
+}
+
+// layOut -- a board of rows by cols with mines laid out by a PCG32 generator seeded with seed, opened at first
+func layOut(rows, cols, mines int, first msboard.Location, seed int64) (*msboard.Board, error) {
+	b := msboard.NewCustomBoard(rows, cols, mines)
+	if nil == b {
+		return nil, fmt.Errorf("can't create a %dx%d board with %d mines", rows, cols, mines)
+	}
+	if !b.ValidLocation(first) {
+		return nil, fmt.Errorf("first click %v is not on a %dx%d board", first, rows, cols)
+	}
+	if _, err := b.FirstClickWithOptions(first, msboard.GeneratorOptions{RNG: msboard.NewPCG(seed)}); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// WriteOpenings -- print opening results as a table, one first click per line
+func WriteOpenings(out io.Writer, results []OpeningResult) error {
+	if _, err := fmt.Fprintf(out, "%-6s %7s %7s %8s %12s\n", "first", "games", "wins", "win %", "clicks/game"); err != nil {
+		return err
+	}
+	for _, r := range results {
+		clicks := 0.0
+		if r.Games > 0 {
+			clicks = float64(r.Clicks) / float64(r.Games)
+		}
+		if _, err := fmt.Fprintf(out, "%-6s %7d %7d %7.1f%% %12.1f\n", locationName(r.Location), r.Games, r.Wins,
+			100*r.WinRate(), clicks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// locationName -- location as a player types it, column letters then row number
+func locationName(l msboard.Location) string {
+	letters := ""
+	for col := l.Col(); col >= 0; col = col/26 - 1 {
+		letters = string(rune('A'+col%26)) + letters
+	}
+	return fmt.Sprintf("%s%d", letters, l.Row()+1)
+}
@@ -0,0 +1,82 @@
+/*
+	Test functions for the opening simulations
+
+	mike@pocomotech.com
+*/
+
+package msanalysis
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"go-mines/msbot"
+	"strings"
+	"testing"
+)
+
+func TestOpeningCandidates(t *testing.T) {
+	var cases = []struct {
+		rows, cols, want int
+	}{
+		{9, 9, 25},
+		{16, 16, 64},
+		{30, 16, 120},
+		{1, 2, 1},
+	}
+	for _, testcase := range cases {
+		if got := OpeningCandidates(testcase.rows, testcase.cols); len(got) != testcase.want {
+			t.Errorf("OpeningCandidates(%d, %d) wanted %d cells got %d", testcase.rows, testcase.cols, testcase.want, len(got))
+		}
+	}
+}
+
+func TestOpenings(t *testing.T) {
+	candidates := []msboard.Location{msboard.NewLocation(0, 0), msboard.NewLocation(4, 4)}
+	results, err := Openings(20, 9, 9, 10, candidates, msbot.Player{}, nil)
+	if err != nil {
+		t.Fatalf("Openings failed: %s", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Openings wanted 2 results got %d", len(results))
+	}
+	if results[0].Wins < results[1].Wins {
+		t.Errorf("Openings results not sorted best first: %+v", results)
+	}
+	for _, r := range results {
+		if r.Games != 20 || r.Wins > r.Games || r.Clicks < r.Games || r.WinRate() != float64(r.Wins)/20 {
+			t.Errorf("Openings result inconsistent: %+v", r)
+		}
+	}
+
+	// the same seeds give the same results
+	again, _ := Openings(20, 9, 9, 10, candidates, msbot.Player{}, nil)
+	if again[0] != results[0] || again[1] != results[1] {
+		t.Errorf("Openings not repeatable: %+v then %+v", results, again)
+	}
+
+	out := bytes.NewBufferString("")
+	WriteOpenings(out, results)
+	if strings.Count(out.String(), "\n") != 3 || !strings.Contains(out.String(), "E5") {
+		t.Errorf("WriteOpenings output unexpected:\n%s", out.String())
+	}
+
+	if _, err := Openings(0, 9, 9, 10, candidates, msbot.Player{}, nil); err == nil {
+		t.Errorf("Openings with no games should fail")
+	}
+	if _, err := Openings(5, 3, 3, 1, candidates, msbot.Player{}, nil); err == nil {
+		t.Errorf("Openings with a first click off the board should fail")
+	}
+}
+
+func TestOpeningHint(t *testing.T) {
+	if got := OpeningHint("hard", 16, 30); got != msboard.NewLocation(7, 0) {
+		t.Errorf("OpeningHint(hard) wanted A8 got %v", got)
+	}
+	// turned on its side, the hint turns with it
+	if got := OpeningHint("hard", 30, 16); got != msboard.NewLocation(0, 7) {
+		t.Errorf("OpeningHint(hard) transposed wanted H1 got %v", got)
+	}
+	if got := OpeningHint("custom", 10, 20); got != msboard.NewLocation(0, 9) {
+		t.Errorf("OpeningHint(custom) wanted the middle of the top edge got %v", got)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+
+	Policy.go - which of the bot's guess policies wins most, found by letting each play the same run of boards
+
+	mike@pocomotech.com
+
+*/
+
+package msanalysis
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/msbot"
+	"io"
+	"math"
+	"sort"
+)
+
+// PolicyResult : how the bot fared over a batch of games with one guess policy
+type PolicyResult struct {
+	Policy string
+	Games  int
+	Wins   int
+	Clicks int // total clicks over every game, won or lost
+}
+
+// WinRate -- fraction of games won
+func (r PolicyResult) WinRate() float64 {
+	if r.Games == 0 {
+		return 0
+	}
+	return float64(r.Wins) / float64(r.Games)
+}
+
+// Margin -- half width of a 95% confidence interval on the win rate
+func (r PolicyResult) Margin() float64 {
+	if r.Games == 0 {
+		return 0
+	}
+	return 1.96 * math.Sqrt(r.WinRate()*(1-r.WinRate())/float64(r.Games))
+}
+
+// ComparePolicies -- have the player play n games from first with each guess policy in turn, returning the results
+// best first, ties by name. Game i with every policy is laid out from the same seed, so the policies face the same
+// run of boards. Every game is told to record, if not nil
+func ComparePolicies(n, rows, cols, mines int, first msboard.Location, policies map[string]msbot.GuessPolicy,
+	player msbot.Player, record Recorder) ([]PolicyResult, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("need at least one game, got %d", n)
+	}
+
+	retval := make([]PolicyResult, 0, len(policies))
+	for name, policy := range policies {
+		result := PolicyResult{Policy: name}
+		player.Policy = policy
+		var err error
+		result.Games, result.Wins, result.Clicks, err = playGames(n, rows, cols, mines, first, player,
+			GameResult{Mode: ModePolicies, Policy: name}, record)
+		if err != nil {
+			return nil, err
+		}
+		retval = append(retval, result)
+	}
+
+	sort.Slice(retval, func(i, j int) bool {
+		if retval[i].Wins != retval[j].Wins {
+			return retval[i].Wins > retval[j].Wins
+		}
+		return retval[i].Policy < retval[j].Policy
+	})
+	return retval, nil
+}
+
+// WritePolicies -- print policy results as a table, one policy per line
+func WritePolicies(out io.Writer, results []PolicyResult) error {
+	if _, err := fmt.Fprintf(out, "%-10s %7s %7s %8s %7s %12s\n", "policy", "games", "wins", "win %", "margin",
+		"clicks/game"); err != nil {
+		return err
+	}
+	for _, r := range results {
+		clicks := 0.0
+		if r.Games > 0 {
+			clicks = float64(r.Clicks) / float64(r.Games)
+		}
+		if _, err := fmt.Fprintf(out, "%-10s %7d %7d %7.1f%% %6.1f%% %12.1f\n", r.Policy, r.Games, r.Wins,
+			100*r.WinRate(), 100*r.Margin(), clicks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+/*
+	Test functions for the guess policy comparisons
+
+	mike@pocomotech.com
+*/
+
+package msanalysis
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"go-mines/msbot"
+	"strings"
+	"testing"
+)
+
+func TestComparePolicies(t *testing.T) {
+	first := msboard.NewLocation(0, 4)
+	results, err := ComparePolicies(20, 9, 9, 10, first, msbot.Policies(), msbot.Player{}, nil)
+	if err != nil {
+		t.Fatalf("ComparePolicies failed: %s", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("ComparePolicies wanted 3 results got %d", len(results))
+	}
+	for i, r := range results {
+		if r.Games != 20 || r.Wins > r.Games || r.Clicks < r.Games || r.Margin() <= 0 && r.Wins < r.Games {
+			t.Errorf("ComparePolicies result inconsistent: %+v", r)
+		}
+		if i > 0 && results[i-1].Wins < r.Wins {
+			t.Errorf("ComparePolicies results not sorted best first: %+v", results)
+		}
+	}
+
+	// the same seeds give the same results, and the default player's policy matches lowest risk
+	again, _ := ComparePolicies(20, 9, 9, 10, first, msbot.Policies(), msbot.Player{}, nil)
+	for i := range results {
+		if again[i] != results[i] {
+			t.Errorf("ComparePolicies not repeatable: %+v then %+v", results, again)
+		}
+	}
+	openings, _ := Openings(20, 9, 9, 10, []msboard.Location{first}, msbot.Player{}, nil)
+	for _, r := range results {
+		if r.Policy == "lowest" && r.Wins != openings[0].Wins {
+			t.Errorf("lowest risk won %d games, the default player %d", r.Wins, openings[0].Wins)
+		}
+	}
+
+	out := bytes.NewBufferString("")
+	WritePolicies(out, results)
+	if strings.Count(out.String(), "\n") != 4 || !strings.Contains(out.String(), "infogain") {
+		t.Errorf("WritePolicies output unexpected:\n%s", out.String())
+	}
+
+	if _, err := ComparePolicies(0, 9, 9, 10, first, msbot.Policies(), msbot.Player{}, nil); err == nil {
+		t.Errorf("ComparePolicies with no games should fail")
+	}
+}
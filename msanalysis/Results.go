@@ -0,0 +1,171 @@
+/*
+
+	Results.go - the games of simulations one by one, for keeping in a store and asking questions of later, such as
+	how the win rate falls with density or how many guesses games take
+
+	The simulations themselves print only totals; a Recorder passed to them hears every game as it ends.
+
+	mike@pocomotech.com
+
+*/
+
+package msanalysis
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"io"
+	"sort"
+)
+
+// Simulations a game can come from
+const (
+	ModeOpenings  = "openings"
+	ModePolicies  = "policies"
+	ModeCalibrate = "calibrate"
+)
+
+// GameResult : one game a simulation had the bot play
+type GameResult struct {
+	Mode    string `json:"mode"` // the simulation that played it, see ModeOpenings
+	Rows    int    `json:"rows"`
+	Cols    int    `json:"cols"`
+	Mines   int    `json:"mines"`
+	First   string `json:"first"`            // first click, as a player types it
+	Policy  string `json:"policy,omitempty"` // guess policy, for games comparing them
+	Seed    int64  `json:"seed"`             // PCG32 seed the board was laid out from, see Board
+	Won     bool   `json:"won"`
+	Clicks  int    `json:"clicks"`
+	Guesses int    `json:"guesses"` // clicks after the first made with no cell proven safe
+}
+
+// Board -- the board the game was played on, laid out again from its seed and opened at its first click, ready
+// for the bot to play on from there
+func (g GameResult) Board() (*msboard.Board, error) {
+	first, err := msboard.LetterNumberCodec{}.Parse(g.First, g.Rows, g.Cols)
+	if err != nil {
+		return nil, err
+	}
+	return layOut(g.Rows, g.Cols, g.Mines, first, g.Seed)
+}
+
+// Recorder : told about every game a simulation plays, as it ends; an error stops the simulation
+type Recorder func(g GameResult) error
+
+// DensityResult : how the bot fared over every game kept at one board size and mine count
+type DensityResult struct {
+	Rows, Cols int
+	Mines      int
+	Games      int
+	Wins       int
+	Guesses    int // total over every game
+}
+
+// Density -- mines per cell
+func (r DensityResult) Density() float64 {
+	return float64(r.Mines) / float64(r.Rows*r.Cols)
+}
+
+// WinRate -- fraction of games won
+func (r DensityResult) WinRate() float64 {
+	if r.Games == 0 {
+		return 0
+	}
+	return float64(r.Wins) / float64(r.Games)
+}
+
+// ByDensity -- games totalled by board size and mine count, sparsest first, ties smallest board first
+func ByDensity(games []GameResult) []DensityResult {
+	type size struct{ rows, cols, mines int }
+	totals := make(map[size]*DensityResult)
+	var retval []DensityResult
+	for _, g := range games {
+		key := size{g.Rows, g.Cols, g.Mines}
+		r, ok := totals[key]
+		if !ok {
+			r = &DensityResult{Rows: g.Rows, Cols: g.Cols, Mines: g.Mines}
+			totals[key] = r
+		}
+		r.Games++
+		r.Guesses += g.Guesses
+		if g.Won {
+			r.Wins++
+		}
+	}
+	for _, r := range totals {
+		retval = append(retval, *r)
+	}
+
+	sort.Slice(retval, func(i, j int) bool {
+		if di, dj := retval[i].Density(), retval[j].Density(); di != dj {
+			return di < dj
+		}
+		if ci, cj := retval[i].Rows*retval[i].Cols, retval[j].Rows*retval[j].Cols; ci != cj {
+			return ci < cj
+		}
+		return retval[i].Rows < retval[j].Rows
+	})
+	return retval
+}
+
+// WriteDensities -- print density results as a table, one board size and mine count per line
+func WriteDensities(out io.Writer, results []DensityResult) error {
+	if _, err := fmt.Fprintf(out, "%-12s %8s %7s %7s %8s %13s\n", "preset", "density", "games", "wins", "win %",
+		"guesses/game"); err != nil {
+		return err
+	}
+	for _, r := range results {
+		preset := fmt.Sprintf("%dx%dx%d", r.Rows, r.Cols, r.Mines)
+		if _, err := fmt.Fprintf(out, "%-12s %7.1f%% %7d %7d %7.1f%% %13.2f\n", preset, 100*r.Density(), r.Games,
+			r.Wins, 100*r.WinRate(), float64(r.Guesses)/float64(r.Games)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GuessCount : games kept that took the same number of guesses
+type GuessCount struct {
+	Guesses int
+	Games   int
+	Wins    int
+}
+
+// GuessDistribution -- games counted by the guesses they took, fewest first, with every count up to the most
+// listed even if no game took it
+func GuessDistribution(games []GameResult) []GuessCount {
+	var retval []GuessCount
+	for _, g := range games {
+		for len(retval) <= g.Guesses {
+			retval = append(retval, GuessCount{Guesses: len(retval)})
+		}
+		retval[g.Guesses].Games++
+		if g.Won {
+			retval[g.Guesses].Wins++
+		}
+	}
+	return retval
+}
+
+// WriteGuesses -- print a guess distribution as a table, one number of guesses per line, with the share of games
+// taking it and the share of those won
+func WriteGuesses(out io.Writer, counts []GuessCount) error {
+	total := 0
+	for _, c := range counts {
+		total += c.Games
+	}
+	if _, err := fmt.Fprintf(out, "%-7s %7s %8s %7s %8s\n", "guesses", "games", "share", "wins", "win %"); err != nil {
+		return err
+	}
+	for _, c := range counts {
+		winRate := 0.0
+		if c.Games > 0 {
+			winRate = float64(c.Wins) / float64(c.Games)
+		}
+		if _, err := fmt.Fprintf(out, "%-7d %7d %7.1f%% %7d %7.1f%%\n", c.Guesses, c.Games,
+			100*float64(c.Games)/float64(total), c.Wins, 100*winRate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
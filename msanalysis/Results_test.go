@@ -0,0 +1,107 @@
+/*
+	Test functions for simulation results kept game by game
+
+	mike@pocomotech.com
+*/
+
+package msanalysis
+
+import (
+	"bytes"
+	"errors"
+	"go-mines/msboard"
+	"go-mines/msbot"
+	"strings"
+	"testing"
+)
+
+func TestRecordedGames(t *testing.T) {
+	var games []GameResult
+	record := func(g GameResult) error {
+		games = append(games, g)
+		return nil
+	}
+	candidates := []msboard.Location{msboard.NewLocation(0, 0), msboard.NewLocation(4, 4)}
+	results, err := Openings(10, 9, 9, 10, candidates, msbot.Player{}, record)
+	if err != nil {
+		t.Fatalf("Openings failed: %s", err)
+	}
+
+	// every game is recorded, and adds up to the totals printed
+	if len(games) != 20 {
+		t.Fatalf("wanted 20 games recorded got %d", len(games))
+	}
+	wins, clicks := 0, 0
+	for _, r := range results {
+		wins += r.Wins
+		clicks += r.Clicks
+	}
+	for i, g := range games {
+		if g.Mode != ModeOpenings || g.Rows != 9 || g.Cols != 9 || g.Mines != 10 || g.Seed != int64(i%10)+1 ||
+			g.Guesses >= g.Clicks {
+			t.Errorf("game %d recorded as %+v", i, g)
+		}
+		if g.Won {
+			wins--
+		}
+		clicks -= g.Clicks
+	}
+	if wins != 0 || clicks != 0 {
+		t.Errorf("recorded games differ from the totals by %d wins and %d clicks", wins, clicks)
+	}
+	if games[0].First != "A1" || games[10].First != "E5" {
+		t.Errorf("first clicks recorded as %s and %s", games[0].First, games[10].First)
+	}
+
+	// a recorder's error stops the simulation
+	stop := errors.New("full")
+	if _, err := ComparePolicies(5, 9, 9, 10, candidates[0], msbot.Policies(), msbot.Player{},
+		func(g GameResult) error { return stop }); err != stop {
+		t.Errorf("ComparePolicies wanted the recorder's error got %v", err)
+	}
+}
+
+func TestByDensity(t *testing.T) {
+	games := []GameResult{
+		{Rows: 16, Cols: 30, Mines: 99, Won: false, Guesses: 3},
+		{Rows: 9, Cols: 9, Mines: 10, Won: true, Guesses: 0},
+		{Rows: 9, Cols: 9, Mines: 10, Won: false, Guesses: 2},
+		{Rows: 16, Cols: 16, Mines: 40, Won: true, Guesses: 1},
+	}
+	got := ByDensity(games)
+	if len(got) != 3 || got[0].Mines != 10 || got[1].Mines != 40 || got[2].Mines != 99 {
+		t.Fatalf("ByDensity wanted 9x9, 16x16 and 16x30 in that order got %+v", got)
+	}
+	if got[0].Games != 2 || got[0].Wins != 1 || got[0].Guesses != 2 || got[0].WinRate() != 0.5 {
+		t.Errorf("ByDensity totalled 9x9 as %+v", got[0])
+	}
+
+	out := new(bytes.Buffer)
+	WriteDensities(out, got)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[1], "9x9x10") || !strings.Contains(lines[1], "12.3%") {
+		t.Errorf("WriteDensities wrote:\n%s", out)
+	}
+}
+
+func TestGuessDistribution(t *testing.T) {
+	games := []GameResult{{Guesses: 0, Won: true}, {Guesses: 3, Won: false}, {Guesses: 0, Won: false}}
+	got := GuessDistribution(games)
+	want := []GuessCount{{0, 2, 1}, {1, 0, 0}, {2, 0, 0}, {3, 1, 0}}
+	if len(got) != len(want) {
+		t.Fatalf("GuessDistribution wanted %v got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("GuessDistribution wanted %v got %v", want, got)
+			break
+		}
+	}
+
+	out := new(bytes.Buffer)
+	WriteGuesses(out, got)
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 5 ||
+		!strings.Contains(lines[1], "66.7%") {
+		t.Errorf("WriteGuesses wrote:\n%s", out)
+	}
+}
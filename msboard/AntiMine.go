@@ -0,0 +1,102 @@
+/*
+
+	AntiMine.go - the anti-mine variant: some mines are anti-mines, which count -1 towards the scores around them
+	instead of +1
+
+	Anti-mines are as deadly as mines and are flagged the same way, but they cancel mines out, so a score can be
+	negative and a zero no longer proves its neighbors safe. Boards only have anti-mines when generated with
+	GeneratorOptions.AntiMines or built from a layout containing them; Snapshot.AntiMines tells solvers and front
+	ends which rules apply.
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+// value -- what the cell adds to its neighbors' scores: 1 for a mine, -1 for an anti-mine, otherwise 0
+func (c *cell) value() int {
+	switch {
+	case !c.hasMine:
+		return 0
+	case c.anti:
+		return -1
+	}
+	return 1
+}
+
+// scoreRune -- the digit for a score, negative scores sharing the digit of their size. Renderers and layouts
+// mark the sign themselves
+func scoreRune(score int) rune {
+	if score < 0 {
+		score = -score
+	}
+	if score >= len(scoreRunes) {
+		return '?'
+	}
+	return scoreRunes[score]
+}
+
+// AntiMineAt -- true if the location holds an anti-mine
+func (b *Board) AntiMineAt(l Location) bool {
+	c := b.getCell(l)
+	return nil != c && c.hasMine && c.anti
+}
+
+// AntiMineCount -- how many of the board's mines are anti-mines, 0 under the classic rules
+func (b *Board) AntiMineCount() int {
+	retval := 0
+	if nil == b || !b.initialized {
+		return 0
+	}
+	for _, l := range b.mines {
+		if b.getCell(l).anti {
+			retval++
+		}
+	}
+	return retval
+}
+
+// SetAntiMine -- place or remove an anti-mine, which counts towards the board's mines, keeping scores and counters
+// consistent. A mine already there is turned into an anti-mine, and back into a mine when it's removed
+func (b *Board) SetAntiMine(l Location, anti bool) error {
+	c, err := b.editableCell(l)
+	if err != nil {
+		return err
+	}
+	if anti && !c.hasMine {
+		if err := b.SetMine(l, true); err != nil {
+			return err
+		}
+	}
+	if c.hasMine {
+		c.anti = anti
+		initializeScores(b)
+	}
+	b.checkAudit("SetAntiMine")
+	return nil
+}
+
+// placeAntiMines -- turn count of the mines, chosen at random, into anti-mines
+func (b *Board) placeAntiMines(rng RNG, count int) {
+	mines := make([]*cell, len(b.mines))
+	for i, l := range b.mines {
+		mines[i] = b.getCell(l)
+	}
+	for ; count > 0 && len(mines) > 0; count-- {
+		takeCell(&mines, rng).anti = true
+	}
+}
+
+// opens -- true if revealing the cell spreads to its neighbors: it scores zero with no mine of either kind around
+// it. Under the classic rules every zero opens; with anti-mines a zero can be a mine and an anti-mine cancelling out
+func (b *Board) opens(c *cell) bool {
+	if c.score != 0 {
+		return false
+	}
+	retval := true
+	b.forNeighbors(c.location, func(n *cell) {
+		retval = retval && !n.hasMine
+	})
+	return retval
+}
@@ -0,0 +1,72 @@
+/*
+	Test functions for anti-mines
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestAntiMineLayout(t *testing.T) {
+	layout := "x-1./-1-1."
+	b, err := ParseLayout(layout)
+	if err != nil {
+		t.Fatalf("ParseLayout(%q) failed: %s", layout, err)
+	}
+	if got := b.Layout(); got != layout {
+		t.Errorf("Layout wanted %q got %q", layout, got)
+	}
+	if !b.AntiMineAt(NewLocation(0, 0)) || b.AntiMineCount() != 1 || b.MineCount() != 1 {
+		t.Errorf("wanted one anti-mine at A1, got %d of %d mines", b.AntiMineCount(), b.MineCount())
+	}
+
+	s := b.Snapshot()
+	if v, _ := s.Cell(NewLocation(0, 1)); v.Score != -1 || v.Rune() != '1' || s.AntiMines != 1 {
+		t.Errorf("Snapshot wanted B1 scoring -1 with 1 anti-mine, got %+v and %d", v, s.AntiMines)
+	}
+
+	for _, bad := range []string{"x1./11.", "x-./...", "-"} {
+		if _, err := ParseLayout(bad); err == nil {
+			t.Errorf("ParseLayout(%q) should fail", bad)
+		}
+	}
+}
+
+func TestAntiMineZeroDoesNotOpen(t *testing.T) {
+	// B1 scores zero with a mine on one side and an anti-mine on the other
+	b, err := ParseLayout("*.x/...")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+	if revealed := b.Click(NewLocation(0, 1)); len(revealed) != 1 || b.Status() != StatusPlaying {
+		t.Errorf("Click on a cancelled out zero wanted just it revealed, got %v and %v", revealed, b.Status())
+	}
+}
+
+func TestGenerateAntiMines(t *testing.T) {
+	rand.Seed(1995)
+	b := NewBoard("medium")
+	if _, err := b.FirstClickWithOptions(NewLocation(8, 8), GeneratorOptions{AntiMines: 10, MinOpening: 5}); err != nil {
+		t.Fatalf("FirstClickWithOptions failed: %s", err)
+	}
+	if b.AntiMineCount() != 10 {
+		t.Errorf("wanted 10 anti-mines got %d", b.AntiMineCount())
+	}
+	if a := b.Audit(); !a.OK() {
+		t.Errorf("anti-mine board fails its audit: %s", a)
+	}
+
+	// moved anti-mines stay anti-mines
+	b.ShiftMines(0.5, nil)
+	if a := b.Audit(); !a.OK() || b.AntiMineCount() != 10 {
+		t.Errorf("ShiftMines wanted 10 anti-mines and a clean audit, got %d: %s", b.AntiMineCount(), a)
+	}
+
+	if _, err := NewBoard("easy").FirstClickWithOptions(NewLocation(0, 0), GeneratorOptions{AntiMines: 11}); err == nil {
+		t.Errorf("FirstClickWithOptions should refuse more anti-mines than mines")
+	}
+}
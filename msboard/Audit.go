@@ -0,0 +1,127 @@
+/*
+
+	Audit.go - recompute the board's cached counters from raw cell state, to catch counter drift
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+import (
+	"fmt"
+	"strings"
+)
+
+// auditMoves : when set, every state changing Board call audits the board afterwards and panics on divergence.
+// Set by debug builds (go build -tags debug) and by this package's tests
+var auditMoves = false
+
+// Audit : board state recomputed from the cells, with every way it disagrees with what the board has cached
+type Audit struct {
+	SafeRemaining int      // hidden cells without a mine
+	Flags         int      // flagged cells
+	Revealed      int      // revealed cells, mines included
+	Mines         int      // cells holding a mine
+	Status        Status   // status the cells call for
+	Problems      []string // divergences from the cached values, empty for a healthy board
+}
+
+// OK -- true if the audit found nothing wrong
+func (a Audit) OK() bool {
+	return len(a.Problems) == 0
+}
+
+// String -- one line summary of the audit
+func (a Audit) String() string {
+	if a.OK() {
+		return fmt.Sprintf("audit ok: %d safe remaining, %d flags, %d revealed, status %v",
+			a.SafeRemaining, a.Flags, a.Revealed, a.Status)
+	}
+	return "audit failed: " + strings.Join(a.Problems, "; ")
+}
+
+// Audit -- recount the board from its cells and compare against the cached safe count, mine count, mine list,
+// scores and status. Uninitialized boards have nothing to audit
+func (b *Board) Audit() Audit {
+	var retval Audit
+	if nil == b || !b.initialized {
+		retval.Status = StatusUninitialized
+		return retval
+	}
+
+	problem := func(format string, args ...interface{}) {
+		retval.Problems = append(retval.Problems, fmt.Sprintf(format, args...))
+	}
+
+	mineRevealed := false
+	for row := range b.cells {
+		for _, c := range b.cells[row] {
+			switch {
+			case c.hasMine:
+				retval.Mines++
+				if c.revealed {
+					mineRevealed = true
+				}
+			case !c.revealed:
+				retval.SafeRemaining++
+			}
+			if c.revealed {
+				retval.Revealed++
+			}
+			if c.flagged {
+				retval.Flags++
+				if c.revealed {
+					problem("%v is both flagged and revealed", c.location)
+				}
+			}
+
+			score := 0
+			for _, n := range b.getNeighborCells(c.location) {
+				score += n.value()
+			}
+			if score != c.score {
+				problem("%v has score %d but %d neighboring mines", c.location, c.score, score)
+			}
+		}
+	}
+
+	switch {
+	case b.explosionOccured:
+		retval.Status = StatusLost
+	case retval.SafeRemaining == 0:
+		retval.Status = StatusWon
+	default:
+		retval.Status = StatusPlaying
+	}
+
+	if retval.SafeRemaining != b.safeRemaining {
+		problem("safe remaining cached as %d, cells have %d", b.safeRemaining, retval.SafeRemaining)
+	}
+	if retval.Mines != b.mineCount || retval.Mines != len(b.mines) {
+		problem("mine count cached as %d with %d listed, cells have %d", b.mineCount, len(b.mines), retval.Mines)
+	}
+	for _, l := range b.mines {
+		if c := b.getCell(l); nil == c || !c.hasMine {
+			problem("mine list has %v, which holds no mine", l)
+		}
+	}
+	if b.explosionOccured && !mineRevealed {
+		problem("explosion recorded but no mine is revealed")
+	}
+	if status := b.Status(); status != retval.Status {
+		problem("status reported as %v, cells call for %v", status, retval.Status)
+	}
+
+	return retval
+}
+
+// checkAudit -- audit the board after a state change when auditMoves is set, panicking if it has drifted
+func (b *Board) checkAudit(op string) {
+	if !auditMoves {
+		return
+	}
+	if a := b.Audit(); !a.OK() {
+		panic(fmt.Sprintf("%s: %s", op, a))
+	}
+}
@@ -0,0 +1,17 @@
+//go:build debug
+
+/*
+
+	AuditDebug.go - debug builds audit the board after every move
+
+		go build -tags debug
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+func init() {
+	auditMoves = true
+}
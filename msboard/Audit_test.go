@@ -0,0 +1,106 @@
+/*
+	Test functions for Board audits. Every test in this package runs with auditing switched on, so any Board call
+	that leaves the cached counters out of step with the cells panics
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func init() {
+	auditMoves = true
+}
+
+func TestAuditHealthy(t *testing.T) {
+	var b *Board
+	if a := b.Audit(); !a.OK() || a.Status != StatusUninitialized {
+		t.Errorf("nil board audit wanted ok and uninitialized got %v", a)
+	}
+
+	b, _ = ParseLayout("F*./f2./...")
+	a := b.Audit()
+	if !a.OK() {
+		t.Fatalf("parsed board failed audit: %v", a)
+	}
+	if a.SafeRemaining != 6 || a.Flags != 2 || a.Revealed != 1 || a.Mines != 2 || a.Status != StatusPlaying {
+		t.Errorf("audit counts wrong: %+v", a)
+	}
+
+	rand.Seed(1995)
+	b = NewBoard("medium")
+	b.FirstClick(NewLocation(5, 5))
+	for row := 0; row < b.Rows(); row++ {
+		for col := 0; col < b.Cols(); col++ {
+			if !b.MineAt(NewLocation(row, col)) {
+				b.Click(NewLocation(row, col))
+			}
+		}
+	}
+	if a = b.Audit(); !a.OK() || a.Status != StatusWon {
+		t.Errorf("cleared board audit wanted ok and won got %v", a)
+	}
+}
+
+func TestAuditDivergence(t *testing.T) {
+	var cases = []struct {
+		name    string
+		corrupt func(b *Board)
+		want    string
+	}{
+		{"safe count", func(b *Board) { b.safeRemaining-- }, "safe remaining cached as 5"},
+		{"score", func(b *Board) { b.cells[2][2].score = 3 }, "has score 3 but 0"},
+		{"mine list", func(b *Board) { b.mines = b.mines[:1] }, "mine count cached as 2 with 1 listed"},
+		{"flag", func(b *Board) { b.cells[1][1].flagged = true }, "both flagged and revealed"},
+		{"explosion", func(b *Board) { b.explosionOccured = true }, "no mine is revealed"},
+		{"status", func(b *Board) { b.safeRemaining = 0 }, "status reported as won, cells call for playing"},
+	}
+
+	for _, testcase := range cases {
+		b, _ := ParseLayout("F*./f2./...")
+		testcase.corrupt(b)
+		if a := b.Audit(); a.OK() || !strings.Contains(a.String(), testcase.want) {
+			t.Errorf("%s: audit wanted %q got %v", testcase.name, testcase.want, a)
+		}
+	}
+}
+
+func TestAuditPanics(t *testing.T) {
+	b, _ := ParseLayout("..*/.../...")
+	b.safeRemaining = 99
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Click on a drifted board should panic while auditing")
+		}
+	}()
+	b.Click(NewLocation(2, 0))
+}
+
+func TestAuditLayoutEdits(t *testing.T) {
+	// the layout editing calls are audited like moves
+	var edits = map[string]func(b *Board) error{
+		"SetMine":     func(b *Board) error { return b.SetMine(NewLocation(0, 0), true) },
+		"SetAntiMine": func(b *Board) error { return b.SetAntiMine(NewLocation(0, 0), true) },
+		"SetRevealed": func(b *Board) error { return b.SetRevealed(NewLocation(2, 0), true) },
+		"SetFlagged":  func(b *Board) error { return b.SetFlagged(NewLocation(2, 0), true) },
+	}
+
+	for name, edit := range edits {
+		b, _ := ParseLayout("..*/.../...")
+		b.safeRemaining = 99
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s on a drifted board should panic while auditing", name)
+				}
+			}()
+			edit(b)
+		}()
+	}
+}
@@ -0,0 +1,127 @@
+/*
+
+	Bitboard.go - mine scores counted a row at a time over packed bitmaps, for generating boards in bulk
+
+	Each row of mines is packed into 64 bit words, one bit per column. A cell's score is the sum of eight bitmaps:
+	the row above, the row itself and the row below, each shifted a column either way, and the rows above and below
+	unshifted. The sum is kept bit-sliced, one bitmap per binary digit, so adding a bitmap is a handful of word
+	operations covering 64 cells at once.
+
+	Only the square grid is counted this way; other topologies choose their own neighbors and are counted cell by
+	cell
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+// bitboard : one bit per cell, each row packed into words of 64 columns
+type bitboard struct {
+	rows, cols, words int // words per row
+	bits              []uint64
+}
+
+// newBitboard -- an empty bitboard of rows by cols
+func newBitboard(rows, cols int) bitboard {
+	words := (cols + 63) / 64
+	return bitboard{rows: rows, cols: cols, words: words, bits: make([]uint64, rows*words)}
+}
+
+// set -- set the bit for a cell
+func (bb bitboard) set(row, col int) {
+	bb.bits[row*bb.words+col/64] |= 1 << uint(col%64)
+}
+
+// row -- the words of a row, nil for rows off the board
+func (bb bitboard) row(row int) []uint64 {
+	if row < 0 || row >= bb.rows {
+		return nil
+	}
+	return bb.bits[row*bb.words : (row+1)*bb.words]
+}
+
+// fromWest -- word w of a row moved one column east, so each cell sees its western neighbor
+func fromWest(row []uint64, w int) uint64 {
+	retval := row[w] << 1
+	if w > 0 {
+		retval |= row[w-1] >> 63
+	}
+	return retval
+}
+
+// fromEast -- word w of a row moved one column west, so each cell sees its eastern neighbor
+func fromEast(row []uint64, w int) uint64 {
+	retval := row[w] >> 1
+	if w+1 < len(row) {
+		retval |= row[w+1] << 63
+	}
+	return retval
+}
+
+// forCounts -- visit every cell with the number of its neighbors whose bits are set. Bits past the last column
+// are never set, so nothing shifts in from off the board
+func (bb bitboard) forCounts(visit func(row, col, count int)) {
+	// 8 neighbors need 4 binary digits
+	var digits [4]uint64
+	add := func(x uint64) {
+		for d := 0; x != 0 && d < len(digits); d++ {
+			digits[d], x = digits[d]^x, digits[d]&x
+		}
+	}
+
+	for row := 0; row < bb.rows; row++ {
+		above, here, below := bb.row(row-1), bb.row(row), bb.row(row+1)
+		for w := 0; w < bb.words; w++ {
+			digits = [4]uint64{}
+			add(fromWest(here, w))
+			add(fromEast(here, w))
+			for _, other := range [][]uint64{above, below} {
+				if nil != other {
+					add(fromWest(other, w))
+					add(other[w])
+					add(fromEast(other, w))
+				}
+			}
+
+			last := bb.cols - w*64
+			if last > 64 {
+				last = 64
+			}
+			for bit := 0; bit < last; bit++ {
+				count := int(digits[0]>>uint(bit)&1 | (digits[1]>>uint(bit)&1)<<1 | (digits[2]>>uint(bit)&1)<<2 |
+					(digits[3]>>uint(bit)&1)<<3)
+				visit(row, w*64+bit, count)
+			}
+		}
+	}
+}
+
+// initializeGridScores -- set every cell's score on a square grid board from bitboards of its mines and
+// anti-mines
+func initializeGridScores(b *Board) {
+	mines, antis := newBitboard(b.rows, b.cols), bitboard{}
+	for row := range b.cells {
+		for col, c := range b.cells[row] {
+			switch {
+			case !c.hasMine:
+			case c.anti:
+				if nil == antis.bits {
+					antis = newBitboard(b.rows, b.cols)
+				}
+				antis.set(row, col)
+			default:
+				mines.set(row, col)
+			}
+		}
+	}
+
+	mines.forCounts(func(row, col, count int) {
+		b.cells[row][col].score = count
+	})
+	if nil != antis.bits {
+		antis.forCounts(func(row, col, count int) {
+			b.cells[row][col].score -= count
+		})
+	}
+}
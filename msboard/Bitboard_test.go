@@ -0,0 +1,69 @@
+/*
+	Test functions for bitboard score counting, checked against counting each cell's neighbors
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"testing"
+)
+
+// countedScores -- every cell's score counted neighbor by neighbor
+func countedScores(b *Board) [][]int {
+	retval := make([][]int, b.rows)
+	for row := range b.cells {
+		retval[row] = make([]int, b.cols)
+		for col := range b.cells[row] {
+			b.forNeighbors(Location{row, col}, func(n *cell) {
+				retval[row][col] += n.value()
+			})
+		}
+	}
+	return retval
+}
+
+func TestGridScores(t *testing.T) {
+	rng := NewPCG(1995)
+	// sizes either side of the 64 column words
+	for _, size := range [][3]int{{1, 1, 0}, {1, 64, 20}, {2, 63, 40}, {3, 65, 60}, {9, 9, 10}, {16, 30, 72},
+		{7, 128, 300}, {5, 200, 500}} {
+		for _, antis := range []int{0, size[2] / 3} {
+			b := NewCustomBoard(size[0], size[1], size[2])
+			if err := b.InitializeWithOptions(Location{0, 0}, GeneratorOptions{AntiMines: antis, RNG: rng}); err != nil {
+				t.Fatalf("%v board failed: %s", size, err)
+			}
+			want := countedScores(b)
+			for row := range b.cells {
+				for col, c := range b.cells[row] {
+					if c.score != want[row][col] {
+						t.Errorf("%v board with %d anti-mines: %v scored %d, wanted %d", size, antis,
+							Location{row, col}, c.score, want[row][col])
+					}
+				}
+			}
+		}
+	}
+}
+
+// benchmarkScores -- time counting the scores of a board of the given size and mine count
+func benchmarkScores(bm *testing.B, rows, cols, mines int) {
+	b := NewCustomBoard(rows, cols, mines)
+	if err := b.InitializeWithOptions(Location{0, 0}, GeneratorOptions{RNG: NewPCG(1995)}); err != nil {
+		bm.Fatal(err)
+	}
+	bm.ReportAllocs()
+	bm.ResetTimer()
+	for i := 0; i < bm.N; i++ {
+		initializeScores(b)
+	}
+}
+
+func BenchmarkScoresHard(bm *testing.B) {
+	benchmarkScores(bm, 16, 30, 72)
+}
+
+func BenchmarkScoresLarge(bm *testing.B) {
+	benchmarkScores(bm, 256, 256, 13000)
+}
@@ -11,11 +11,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"math/rand"
+	"math"
 	"os"
 )
 
-// Location : zero-based cell location, {0,0} is upper left
+// Location : zero-based cell location, {0,0} is upper left; the row counts down the board and the column across it
 type Location struct {
 	row, col int
 }
@@ -26,13 +26,26 @@ func NewLocation(row, col int) Location {
 	return retval
 }
 
+// Row -- zero-based row of the Location
+func (l Location) Row() int {
+	return l.row
+}
+
+// Col -- zero-based column of the Location
+func (l Location) Col() int {
+	return l.col
+}
+
 // cell : manage state for a single cell on the board
 type cell struct {
 	location Location // cell position in grid, zero based, {0,0} is upper left
 	hasMine  bool     // cell holds mine
+	anti     bool     // the mine is an anti-mine, see AntiMine.go
 	score    int      // cache static score for this cell
 	flagged  bool     // user flag
 	revealed bool     // all cells start hidden
+	treasure Treasure // power-up hidden in a safe cell, see Treasure.go
+	looted   bool     // treasure already collected
 }
 
 // BoardSaveState : Persistable board state object, read/written as JSON
@@ -45,12 +58,64 @@ type boardSaveState struct {
 	explosionOccured bool
 }
 
-// Board struct manages state of the Minesweeper board
+// Board struct manages state of the Minesweeper board. Boards are laid out in rows across the screen, top to bottom:
+// Rows is the Height and Cols the Width, and sizes are written rows x cols, so the 16 x 30 "hard" board is drawn
+// wider than it is tall, like the classic expert board
 type Board struct {
-	boardSaveState           // persistable state
-	cells          [][]*cell // cells of initialized board
-	safeRemaining  int       // cache number of non-mine cells remaining to be revealed
-	mineCount      int       // number of mines defined for this board
+	boardSaveState                 // persistable state
+	cells          [][]*cell       // cells of initialized board
+	safeRemaining  int             // cache number of non-mine cells remaining to be revealed
+	mineCount      int             // number of mines defined for this board
+	propagation    PropagationRule // how reveals spread from zero score cells
+	floodFlags     bool            // cascades reveal flagged cells, clearing the flags, instead of stopping at them
+	fog            int             // visibility radius limiting which cells can be clicked, 0 for none, see Fog.go
+	rules          Rules           // rules of play, nil for ClassicRules, see Rules.go
+	topology       *Topology       // board shape, nil for the square grid, see Topology.go
+	console        *consoleFrame   // buffers reused by ConsoleRender, see ConsoleFrame.go
+}
+
+// PropagationRule : how a click on a zero score cell spreads to the cells around it
+type PropagationRule int
+
+// Supported propagation rules
+const (
+	// PropagateStandard : every neighbor of a revealed zero is revealed, so a cascade opens the connected zero
+	// region plus the numbered cells bordering it. Numbered cells don't spread further
+	PropagateStandard PropagationRule = iota
+	// PropagateZerosOnly : a variant where cascades reveal only the connected zero cells, leaving the numbered
+	// border hidden
+	PropagateZerosOnly
+)
+
+var propagationNames = [...]string{"standard", "zeros-only"}
+
+// String -- human readable rule name
+func (p PropagationRule) String() string {
+	if p < 0 || int(p) >= len(propagationNames) {
+		return "unknown"
+	}
+	return propagationNames[p]
+}
+
+// Status : overall state of play for a board
+type Status int
+
+// Board status values, in the order a game moves through them
+const (
+	StatusUninitialized Status = iota // board allocated, mines not yet placed
+	StatusPlaying                     // mines placed, game in progress
+	StatusWon                         // all safe cells revealed
+	StatusLost                        // a mine was revealed
+)
+
+var statusNames = [...]string{"uninitialized", "playing", "won", "lost"}
+
+// String -- human readable status name
+func (s Status) String() string {
+	if s < 0 || int(s) >= len(statusNames) {
+		return "unknown"
+	}
+	return statusNames[s]
 }
 
 /************************************\
@@ -82,7 +147,7 @@ func (c *cell) Render() rune {
 		return '*'
 	}
 
-	return scoreRunes[c.score]
+	return scoreRune(c.score)
 }
 
 /************************************\
@@ -99,11 +164,12 @@ var boardDefinitionsDict = func() map[string]boardparams {
 		// name : difficulty, rows, cols, mines
 		"easy":   {"easy", 9, 9, 10},
 		"medium": {"medium", 16, 16, 30},
-		"hard":   {"hard", 30, 16, 72},
+		"hard":   {"hard", 16, 30, 72},
 	}
 }
 
-// NewBoard : allocate new, uninitialized board. Supported sizes are "easy" (9x9), "medium", (16x16) and "hard" (30x16)
+// NewBoard : allocate new, uninitialized board. Supported sizes are "easy" (9x9), "medium", (16x16) and "hard" (16
+// rows of 30)
 func NewBoard(difficulty string) *Board {
 	params, ok := boardDefinitionsDict()[difficulty]
 
@@ -118,58 +184,68 @@ func NewBoard(difficulty string) *Board {
 	return retval
 }
 
-// Initialize : construct a new Board with consideratioon for user's selected 'safe' Location
-func (b *Board) Initialize(safespot Location) error {
+// NewTransposedBoard : allocate a new, uninitialized preset board turned on its side, Width and Height swapped, for
+// players who'd rather scroll down than across; "hard" becomes 30 rows of 16. Returns nil for unrecognized boards
+func NewTransposedBoard(difficulty string) *Board {
+	retval := NewBoard(difficulty)
+	if nil == retval {
+		return nil
+	}
+	retval.rows, retval.cols = retval.cols, retval.rows
+	return retval
+}
 
-	// Create default cells, then loop over grid and place bombs randomly at 10% probbality until bomb supply exhausted
-	b.cells = make([][]*cell, b.rows)
-	for row := range b.cells {
-		b.cells[row] = make([]*cell, b.cols)
-		for col := range b.cells[row] {
-			b.cells[row][col] = new(cell)
-			b.cells[row][col].location = NewLocation(row, col)
-		}
+// NewCustomBoard : allocate new, uninitialized board of any shape. Returns nil unless there is room for all mines
+// plus the player's safe starting cell, or the board would have more cells than an int can count
+func NewCustomBoard(rows, cols, mines int) *Board {
+	if rows < 1 || cols < 1 || rows > math.MaxInt32/cols || mines < 0 || mines >= rows*cols {
+		return nil
 	}
-	b.safeRemaining = b.rows * b.cols
 
-	minesToPlace := b.mineCount
-	for minesToPlace > 0 {
-		for row := range b.cells {
-			for col := range b.cells[row] {
-				if minesToPlace == 0 {
-					continue
-				}
+	retval := new(Board)
+	retval.difficulty, retval.rows, retval.cols, retval.mineCount = "custom", rows, cols, mines
 
-				currloc := Location{row, col}
-				if currloc == safespot {
-					continue // can't place mine at user's safe starting cell
-				}
-				mineshot := rand.Intn(100)
-
-				if mineshot < 2 {
-					currcell := b.getCell(currloc)
-					if currcell.hasMine {
-						continue // we already placed a mine here
-					}
-					// place and record mine at current Location
-					b.cells[row][col].hasMine = true
-					b.mines = append(b.mines, currloc)
-					minesToPlace--
-					b.safeRemaining--
-				}
-			}
-		}
-	}
+	return retval
+}
 
-	// once mines are placed, go ahead and calculate cell scores
-	initializeScores(b)
+// Initialize : construct a new Board with consideratioon for user's selected 'safe' Location
+func (b *Board) Initialize(safespot Location) error {
+	return b.InitializeWithOptions(safespot, GeneratorOptions{})
+}
 
-	b.initialized = true
-	return nil
+// FirstClick -- generate the mine layout around the player's first, guaranteed safe, cell and reveal it in one
+// step, returning the cells revealed as Click does. Boards don't need to be initialized before being rendered,
+// their Snapshot shows every cell hidden
+func (b *Board) FirstClick(l Location) ([]Location, error) {
+	return b.FirstClickWithOptions(l, GeneratorOptions{})
+}
+
+// FirstClickWithOptions -- FirstClick with a mine layout satisfying the generator options. On error the board is
+// left uninitialized
+func (b *Board) FirstClickWithOptions(l Location, opts GeneratorOptions) ([]Location, error) {
+	if nil == b {
+		return nil, errors.New("called FirstClick() on a nil board")
+	}
+	if b.initialized {
+		return nil, errors.New("called FirstClick() on a board that is already in play")
+	}
+	if !b.ValidLocation(l) {
+		return nil, fmt.Errorf("first click %v is not on the board", l)
+	}
+
+	if err := b.InitializeWithOptions(l, opts); err != nil {
+		return nil, err
+	}
+	return b.Click(l), nil
 }
 
-// initializeScores - calculate and set mine proximity scores for each cell
+// initializeScores - calculate and set mine proximity scores for each cell. The square grid is counted over
+// bitboards, see Bitboard.go; other topologies cell by cell
 func initializeScores(b *Board) {
+	if nil == b.topology {
+		initializeGridScores(b)
+		return
+	}
 
 	for row := range b.cells {
 		for col := range b.cells[row] {
@@ -183,9 +259,7 @@ func initializeScores(b *Board) {
 			}
 
 			for _, neighbor := range neighbors {
-				if neighbor.hasMine {
-					cellScore++
-				}
+				cellScore += neighbor.value()
 			}
 			currcell.score = cellScore
 		}
@@ -203,21 +277,10 @@ func (b *Board) getNeighborCells(loc Location) []*cell {
 
 	retval := make([]*cell, 0, 8)
 
-	// iterate over all potential neighbor cell position
-	for nrow := loc.row - 1; nrow <= (loc.row + 1); nrow++ {
-		for ncol := loc.col - 1; ncol <= (loc.col + 1); ncol++ {
-			neighborloc := Location{nrow, ncol}
-			// don't include center point
-			if loc == neighborloc {
-				continue
-			}
-			neighbor := b.getCell(neighborloc)
-			if nil == neighbor { // invalid Location outside grid
-				continue
-			}
-			retval = append(retval, neighbor)
-		}
-	}
+	// the board's topology decides which cells are adjacent
+	b.forNeighbors(loc, func(neighbor *cell) {
+		retval = append(retval, neighbor)
+	})
 
 	return retval
 }
@@ -230,10 +293,10 @@ func (b *Board) Initialized() bool {
 	return b.initialized
 }
 
-// GetCell : return a reference to a particular cell
+// GetCell : return a reference to a particular cell, nil off the board or before its cells are allocated
 func (b *Board) getCell(selected Location) *cell {
 	// bunch of preconditions
-	if selected.row < 0 || selected.row >= b.rows || selected.col < 0 || selected.col >= b.cols {
+	if nil == b.cells || selected.row < 0 || selected.row >= b.rows || selected.col < 0 || selected.col >= b.cols {
 		return nil
 	}
 	return b.cells[selected.row][selected.col]
@@ -247,7 +310,8 @@ func (b *Board) SafeRemaining() int {
 	return b.safeRemaining
 }
 
-// RevealAll : set all cells to revealed (for debugging or surrender); this is irreversible
+// RevealAll : set all cells to revealed (for debugging or surrender); this is irreversible. No safe cells remain
+// hidden afterwards, so unless a mine was hit the board reports itself won
 func (b *Board) RevealAll() error {
 	if nil == b || !b.initialized {
 		return errors.New("called RevealAll() on an uninitialized board")
@@ -255,84 +319,126 @@ func (b *Board) RevealAll() error {
 	for row := range b.cells {
 		for col := range b.cells[row] {
 			b.cells[row][col].revealed = true
+			b.cells[row][col].flagged = false
+		}
+	}
+	b.safeRemaining = 0
+	b.checkAudit("RevealAll")
+
+	return nil
+}
+
+// Reset -- hide every cell and clear flags and any explosion, keeping the mine layout, so the same board can be
+// played again
+func (b *Board) Reset() error {
+	if nil == b || !b.initialized {
+		return errors.New("called Reset() on an uninitialized board")
+	}
+	for row := range b.cells {
+		for _, c := range b.cells[row] {
+			c.revealed, c.flagged, c.looted = false, false, false
 		}
 	}
+	b.explosionOccured = false
+	b.safeRemaining = b.rows*b.cols - b.mineCount
+	b.checkAudit("Reset")
 
 	return nil
 }
 
-// ConsoleRender -- render a console image of the board state
+// ConsoleRender -- render a console image of the board state, with row and column labels in the default scheme of
+// its topology, laid out to fit the board's size
 func (b *Board) ConsoleRender(cout io.Writer) error {
 
 	if nil == b || !b.initialized {
 		return errors.New("called Render() on an uninitialized board")
 	}
 
-	// top line is header
-	headingLine := ""
-	switch b.difficulty {
-	case "easy":
-		headingLine = "    A  B  C  D  E  F  G  H  I"
-	case "medium", "hard":
-		headingLine = "    A  B  C  D  E  F  G  H  I  J  K  L  M  N  O  P"
-	}
-	fmt.Fprintln(cout, headingLine)
-
+	// the whole frame is built in the board's reused buffer, top line is header
+	frame := b.consoleFrame()
+	buf := append(frame.layout.AppendHeader(frame.buf[:0], frame.colLabels), '\n')
 	for row := range b.cells {
-		// index column along left side
-		nextLine := fmt.Sprintf("%2d  ", row+1)
-
-		for col := range b.cells[row] {
-			if col != 0 {
-				nextLine += "  "
-			}
-			nextLine += string(b.cells[row][col].Render())
+		for col, c := range b.cells[row] {
+			frame.cells[col] = consoleGlyph(c.Render())
 		}
-		fmt.Fprintln(cout, nextLine)
+		buf = append(frame.layout.AppendRow(buf, frame.rowLabels[row], frame.cells), '\n')
 	}
+	frame.buf = buf
 
-	return nil
+	_, err := cout.Write(buf)
+	return err
 }
 
-// Click -- Calculate and apply board state changes for a cell click event
-func (b *Board) Click(l Location) {
+// Click -- Calculate and apply board state changes for a cell click event, returning the cells revealed: the
+// clicked cell first, then any revealed by propagation in breadth first order, so a cascade lists its cells wave by
+// wave outwards from the click, see CascadeWaves. The board's Rules decide which cells can be clicked and how
+// reveals spread. Clicks on uninitialized boards do nothing; the first goes through FirstClick
+func (b *Board) Click(l Location) []Location {
+	if !b.Initialized() {
+		return nil
+	}
 	c := b.getCell(l)
 
 	if nil == c {
-		return
+		return nil
 	}
+	defer b.checkAudit("Click")
 
 	// flagged cells are protected from inadvertant clicks
 	if c.flagged {
-		return
+		return nil
 	}
 
-	// already revealed cells do not respond to clicks
+	// already revealed cells respond only if the rules chord them
 	if c.revealed {
-		return
+		return b.chord(l)
 	}
 
-	// reveal cell
+	// the rules may put cells out of reach, such as those lost in the fog
+	if !b.Rules().Clickable(b, l) {
+		return nil
+	}
+
+	return b.reveal(c)
+}
+
+// chord -- reveal the cells the rules chord from the revealed cell at l, returning them as Click does
+func (b *Board) chord(l Location) []Location {
+	var retval []Location
+	for _, target := range b.Rules().Chord(b, l) {
+		if c := b.getCell(target); nil != c && !c.revealed && !c.flagged {
+			retval = append(retval, b.reveal(c)...)
+		}
+	}
+	return retval
+}
+
+// reveal -- reveal a hidden cell, exploding it if it's a mine or cascading if it scores zero, returning the cells
+// revealed as Click does
+func (b *Board) reveal(c *cell) []Location {
 	c.revealed = true
+	retval := []Location{c.location}
 
 	// Mine? Explode
 	if c.hasMine {
 		b.explosionOccured = true
-		return
+		return retval
 	}
+	b.safeRemaining--
 
-	// non-zero score cells do not propagate (I think)
-	if c.score == 0 {
-		// propagate reveals for zero score cells
-		b.PropagateReveals(c)
+	// only cells the rules open propagate; numbered cells end a cascade under the classic rules
+	if b.Rules().Opens(b, c.location) {
+		retval = append(retval, b.PropagateReveals(c)...)
 	}
 
+	return retval
 }
 
-// PropagateReveals -- clicking on a zero score cell reveals all connected zero score cells
-func (b *Board) PropagateReveals(c *cell) {
+// PropagateReveals -- clicking on a zero score cell reveals all connected zero score cells, and under the
+// standard rule the numbered cells around them. Returns the cells newly revealed
+func (b *Board) PropagateReveals(c *cell) []Location {
 	if nil == c {
-		return
+		return nil
 	}
 
 	neighbors := b.getNeighborCells(c.location)
@@ -342,22 +448,65 @@ func (b *Board) PropagateReveals(c *cell) {
 		fmt.Fprintln(os.Stderr, "PropogateReveals failure for cell (this should not happen :() :  ", c.location)
 	}
 
-	// reveal unrevealed neighbors and recurse for any zero-scored ones
-	for _, n := range neighbors {
-		if n.revealed {
-			continue
-		}
+	var retval []Location
+	b.propagate(c, neighbors, &retval)
+	return retval
+}
 
-		n.revealed = true
+// propagate -- reveal unrevealed neighbors of the zero-scored cell c, then those of each zero-scored cell revealed
+// in turn, appending the cells revealed to one shared list. The list doubles as the breadth first queue: cells
+// are taken from it in the order they were revealed, so the whole of one wave is revealed before the next
+func (b *Board) propagate(c *cell, neighbors []*cell, revealed *[]Location) {
+	rules := b.Rules()
+	for next := len(*revealed); ; next++ {
+		for _, n := range neighbors {
+			if n.revealed {
+				continue
+			}
+			if !rules.Spreads(b, n.location) {
+				continue
+			}
+			// player flags stop a cascade unless the board is set to sweep them away
+			if n.flagged {
+				if !b.floodFlags {
+					continue
+				}
+				n.flagged = false
+			}
 
-		// debug
-		// fmt.Fprintln(os.Stderr, "Revealing ", n.location, " (score = ", n.score, ") from ", c.location)
+			// neighbors of a zero are never mines
+			n.revealed = true
+			b.safeRemaining--
+			*revealed = append(*revealed, n.location)
+		}
 
-		if n.score == 0 {
-			b.PropagateReveals(n)
+		// the next zero waiting its turn spreads further
+		for ; next < len(*revealed); next++ {
+			if c = b.getCell((*revealed)[next]); rules.Opens(b, c.location) {
+				break
+			}
 		}
+		if next == len(*revealed) {
+			return
+		}
+		neighbors = b.getNeighborCells(c.location)
 	}
+}
+
+// SetPropagation -- choose how cascades spread from zero score cells, see PropagationRule
+func (b *Board) SetPropagation(rule PropagationRule) {
+	b.propagation = rule
+}
 
+// SetFloodClearsFlags -- choose whether cascades stop at flagged cells (the default) or reveal them, removing the
+// flags
+func (b *Board) SetFloodClearsFlags(clears bool) {
+	b.floodFlags = clears
+}
+
+// Propagation -- the board's cascade rule
+func (b *Board) Propagation() PropagationRule {
+	return b.propagation
 }
 
 // MineHit -- convenience function for game loop
@@ -365,12 +514,56 @@ func (b *Board) MineHit() bool {
 	return b.explosionOccured
 }
 
-// ToggleFlag -- toggle flag status for a cell, ignored for non-hidden cells
+// Status -- overall game state derived from the board by its Rules
+func (b *Board) Status() Status {
+	switch {
+	case nil == b || !b.initialized:
+		return StatusUninitialized
+	}
+
+	return b.Rules().Status(b)
+}
+
+// Rows -- number of rows on the board
+func (b *Board) Rows() int {
+	return b.rows
+}
+
+// Cols -- number of columns on the board
+func (b *Board) Cols() int {
+	return b.cols
+}
+
+// Width -- cells across the board as drawn, the number of columns
+func (b *Board) Width() int {
+	return b.cols
+}
+
+// Height -- cells down the board as drawn, the number of rows
+func (b *Board) Height() int {
+	return b.rows
+}
+
+// Difficulty -- difficulty name the board was created with
+func (b *Board) Difficulty() string {
+	return b.difficulty
+}
+
+// MineCount -- number of mines defined for this board
+func (b *Board) MineCount() int {
+	return b.mineCount
+}
+
+// ToggleFlag -- toggle flag status for a cell, ignored for non-hidden cells and on uninitialized boards
 func (b *Board) ToggleFlag(l Location) {
+	if !b.Initialized() {
+		return
+	}
 	c := b.getCell(l)
 
 	if nil != c && c.revealed == false {
 		c.flagged = !c.flagged
+		b.checkAudit("ToggleFlag")
 	}
 }
 
@@ -0,0 +1,144 @@
+/*
+
+	BoardCode.go - short codes naming a generated board, for players to share: the board's size, the seed its mines
+	were drawn from and the first click they were laid out around, e.g.
+
+		1.pcg32.9x9x10.1995.E5
+
+	A seed only names a layout for a given generator, so every code starts with the versions it was made by: the
+	generator's, GeneratorVersion, which changes whenever draws are turned into mines differently, and the random
+	number generator's, see NewSeededRNG. Every random number generator there has been is kept, so codes made with
+	an older one are laid out as they always were; codes from another version of the generator are refused rather
+	than laid out differently.
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GeneratorVersion : version of the way InitializeWithOptions turns random draws into a mine layout. Change it
+// whenever the same draws would lay out different mines, so codes and recorded draws from before aren't misread
+const GeneratorVersion = 1
+
+// MaxCodeSide : most rows or columns a board code can name. Codes come from other players, so their sizes are
+// capped well short of boards that would take all memory to lay out
+const MaxCodeSide = 1000
+
+// BoardCode : everything needed to lay out a board generated with the default options again
+type BoardCode struct {
+	Generator         int        // GeneratorVersion of the engine that made it
+	RNG               RNGVersion // generator the seed is drawn from
+	Rows, Cols, Mines int
+	Seed              int64
+	First             Location // the first click, which the layout keeps clear
+}
+
+// NewBoardCode -- the code for a board of a given size laid out around first from a seed, by this engine
+func NewBoardCode(rows, cols, mines int, seed int64, first Location) BoardCode {
+	return BoardCode{Generator: GeneratorVersion, RNG: CurrentRNGVersion, Rows: rows, Cols: cols, Mines: mines,
+		Seed: seed, First: first}
+}
+
+// String -- the code as players share it
+func (c BoardCode) String() string {
+	return fmt.Sprintf("%d.%s.%dx%dx%d.%d.%s", c.Generator, c.RNG, c.Rows, c.Cols, c.Mines, c.Seed,
+		LetterNumberCodec{}.Format(c.First, c.Rows, c.Cols))
+}
+
+// ParseBoardCode -- read a code written by String
+func ParseBoardCode(text string) (BoardCode, error) {
+	var retval BoardCode
+	fields := strings.Split(strings.TrimSpace(text), ".")
+	if len(fields) != 5 {
+		return BoardCode{}, fmt.Errorf("board code %q isn't version.rng.size.seed.first", text)
+	}
+
+	var err error
+	if retval.Generator, err = strconv.Atoi(fields[0]); err != nil {
+		return BoardCode{}, fmt.Errorf("board code %q: bad generator version %q", text, fields[0])
+	}
+	retval.RNG = RNGVersion(-1)
+	for v, name := range rngVersionNames {
+		if name == fields[1] {
+			retval.RNG = RNGVersion(v)
+		}
+	}
+	if retval.RNG < 0 {
+		return BoardCode{}, fmt.Errorf("board code %q: unknown random number generator %q", text, fields[1])
+	}
+	if n, _ := fmt.Sscanf(fields[2], "%dx%dx%d", &retval.Rows, &retval.Cols, &retval.Mines); n != 3 {
+		return BoardCode{}, fmt.Errorf("board code %q: bad size %q, wanted rows x cols x mines", text, fields[2])
+	}
+	if err := checkCodeSize(retval.Rows, retval.Cols, retval.Mines); err != nil {
+		return BoardCode{}, fmt.Errorf("board code %q: %s", text, err)
+	}
+	if retval.Seed, err = strconv.ParseInt(fields[3], 10, 64); err != nil {
+		return BoardCode{}, fmt.Errorf("board code %q: bad seed %q", text, fields[3])
+	}
+	if retval.First, err = (LetterNumberCodec{}).Parse(fields[4], retval.Rows, retval.Cols); err != nil {
+		return BoardCode{}, fmt.Errorf("board code %q: %s", text, err)
+	}
+	if first := retval.First; first.row < 0 || first.row >= retval.Rows || first.col < 0 || first.col >= retval.Cols {
+		return BoardCode{}, fmt.Errorf("board code %q: first click %s is off the board", text, fields[4])
+	}
+	return retval, nil
+}
+
+// Board -- the board the code names, its mines laid out and nothing revealed. Codes from another generator
+// version are refused
+func (c BoardCode) Board() (*Board, error) {
+	if c.Generator != GeneratorVersion {
+		return nil, fmt.Errorf("board code made by generator version %d, this engine lays out version %d",
+			c.Generator, GeneratorVersion)
+	}
+	if err := checkCodeSize(c.Rows, c.Cols, c.Mines); err != nil {
+		return nil, err
+	}
+	rng, err := NewSeededRNG(c.Seed, c.RNG)
+	if err != nil {
+		return nil, err
+	}
+
+	b := boardOfSize(c.Rows, c.Cols, c.Mines)
+	if nil == b {
+		return nil, fmt.Errorf("a %dx%d board can't hold %d mines", c.Rows, c.Cols, c.Mines)
+	}
+	if err := b.InitializeWithOptions(c.First, GeneratorOptions{RNG: rng}); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// checkCodeSize -- an error unless a board of a size is one a code can name
+func checkCodeSize(rows, cols, mines int) error {
+	switch {
+	case rows < 1 || cols < 1:
+		return fmt.Errorf("a %dx%d board has no cells", rows, cols)
+	case rows > MaxCodeSide || cols > MaxCodeSide:
+		return fmt.Errorf("a %dx%d board is bigger than the %dx%d codes can name", rows, cols, MaxCodeSide, MaxCodeSide)
+	case mines < 0 || mines >= rows*cols:
+		return fmt.Errorf("a %dx%d board can't hold %d mines", rows, cols, mines)
+	}
+	return nil
+}
+
+// boardOfSize -- a new board of a size, named for the preset it matches, either way up, or custom
+func boardOfSize(rows, cols, mines int) *Board {
+	for name, params := range boardDefinitionsDict() {
+		switch {
+		case params.mineCount != mines:
+		case params.rows == rows && params.cols == cols:
+			return NewBoard(name)
+		case params.rows == cols && params.cols == rows:
+			return NewTransposedBoard(name)
+		}
+	}
+	return NewCustomBoard(rows, cols, mines)
+}
@@ -0,0 +1,96 @@
+/*
+	Test functions for board codes
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"testing"
+)
+
+func TestBoardCodeText(t *testing.T) {
+	code := NewBoardCode(16, 30, 72, -42, NewLocation(3, 10))
+	if got := code.String(); got != "1.pcg32.16x30x72.-42.K4" {
+		t.Errorf("code text wrong: %s", got)
+	}
+	parsed, err := ParseBoardCode(" " + code.String() + "\n")
+	if err != nil {
+		t.Fatalf("ParseBoardCode failed: %s", err)
+	}
+	if parsed != code {
+		t.Errorf("parsed %+v wanted %+v", parsed, code)
+	}
+
+	for _, bad := range []string{"", "1.pcg32.9x9x10.1995", "x.pcg32.9x9x10.1995.A1", "1.xorshift.9x9x10.1995.A1",
+		"1.pcg32.9x9.1995.A1", "1.pcg32.9x9x10.seed.A1", "1.pcg32.9x9x10.1995.Z1",
+		// sizes no board can have, or too big to lay out
+		"1.pcg32.0x9x0.1995.A1", "1.pcg32.9x9x-1.1995.A1", "1.pcg32.9x9x81.1995.A1",
+		"1.pcg32.100000x100000x5.1.A1", "1.pcg32.1001x9x10.1.A1", "1.pcg32.9x1001x10.1.A1",
+		"1.pcg32.3037000500x3037000500x5.1.A1"} {
+		if _, err := ParseBoardCode(bad); nil == err {
+			t.Errorf("ParseBoardCode accepted %q", bad)
+		}
+	}
+}
+
+func TestBoardCodeBoard(t *testing.T) {
+	first := NewLocation(4, 4)
+	code := NewBoardCode(9, 9, 10, 1995, first)
+	b, err := code.Board()
+	if err != nil {
+		t.Fatalf("Board failed: %s", err)
+	}
+	// the same layout as the seed gives directly, see TestPCGLayout
+	want := "........./*......../.*.**..*./......*../..*....../..*...*../........./........./..*......"
+	if b.Difficulty() != "easy" || b.MineLayout() != want || b.Status() != StatusPlaying {
+		t.Errorf("code board wrong: %s %v %s", b.Difficulty(), b.Status(), b.MineLayout())
+	}
+
+	// codes made with older random number generators are laid out as they were
+	old := code
+	old.RNG = RNGMathRand
+	direct := NewBoard("easy")
+	direct.InitializeWithOptions(first, GeneratorOptions{RNG: mustSeededRNG(t, 1995, RNGMathRand)})
+	if b, err := old.Board(); err != nil || b.MineLayout() != direct.MineLayout() {
+		t.Errorf("mathrand code board wrong: %v", err)
+	}
+
+	// presets either way up keep their names
+	for _, c := range []struct {
+		code       BoardCode
+		difficulty string
+	}{
+		{NewBoardCode(30, 16, 72, 7, first), "hard"},
+		{NewBoardCode(16, 16, 30, 7, first), "medium"},
+		{NewBoardCode(16, 16, 31, 7, first), "custom"},
+	} {
+		if b, err := c.code.Board(); err != nil || b.Difficulty() != c.difficulty {
+			t.Errorf("%s wanted a %s board: %v", c.code, c.difficulty, err)
+		}
+	}
+
+	newer := code
+	newer.Generator = GeneratorVersion + 1
+	if _, err := newer.Board(); nil == err {
+		t.Errorf("code from generator version %d accepted", newer.Generator)
+	}
+	if _, err := NewBoardCode(3, 3, 9, 7, NewLocation(0, 0)).Board(); nil == err {
+		t.Errorf("code for a board too full to play accepted")
+	}
+	if _, err := NewBoardCode(MaxCodeSide+1, 9, 10, 7, first).Board(); nil == err {
+		t.Errorf("code for a board bigger than MaxCodeSide accepted")
+	}
+	if _, err := NewBoardCode(MaxCodeSide, MaxCodeSide, 10, 7, first).Board(); err != nil {
+		t.Errorf("code for the biggest board refused: %s", err)
+	}
+}
+
+func mustSeededRNG(t *testing.T, seed int64, version RNGVersion) RNG {
+	rng, err := NewSeededRNG(seed, version)
+	if err != nil {
+		t.Fatalf("NewSeededRNG failed: %s", err)
+	}
+	return rng
+}
@@ -0,0 +1,102 @@
+/*
+	Benchmarks for Click, the call behind every interactive reveal. Run with
+
+		go test -run - -bench Click ./msboard/
+
+	The latency budget these guard is documented in the README: a click on any standard board, flood fill
+	included, must stay well under a millisecond, and a full flood of the 256x256 open board under 50ms
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// benchmarkClicks -- time Click over the target cells in turn, resetting the board whenever every target has
+// been clicked. Board auditing is switched off, as it would swamp the timings
+func benchmarkClicks(bm *testing.B, b *Board, targets []Location) {
+	defer func(audit bool) { auditMoves = audit }(auditMoves)
+	auditMoves = false
+
+	bm.ReportAllocs()
+	bm.ResetTimer()
+	for i := 0; i < bm.N; i++ {
+		if i%len(targets) == 0 {
+			bm.StopTimer()
+			b.Reset()
+			bm.StartTimer()
+		}
+		b.Click(targets[i%len(targets)])
+	}
+}
+
+// openBoard -- board with no mines at all, where one click floods every cell
+func openBoard(bm *testing.B, rows, cols int) *Board {
+	b := NewCustomBoard(rows, cols, 0)
+	if err := b.Initialize(Location{rows / 2, cols / 2}); err != nil {
+		bm.Fatal(err)
+	}
+	return b
+}
+
+// safeCells -- every safe cell of an initialized board, in reading order
+func safeCells(b *Board) []Location {
+	var retval []Location
+	for row := 0; row < b.Rows(); row++ {
+		for col := 0; col < b.Cols(); col++ {
+			if l := (Location{row, col}); !b.MineAt(l) {
+				retval = append(retval, l)
+			}
+		}
+	}
+	return retval
+}
+
+// denseBoard -- board at the given size and mine count, with a fixed layout
+func denseBoard(bm *testing.B, b *Board) *Board {
+	rand.Seed(1995)
+	if err := b.Initialize(Location{0, 0}); err != nil {
+		bm.Fatal(err)
+	}
+	return b
+}
+
+func BenchmarkClickOpenEasy(bm *testing.B) {
+	benchmarkClicks(bm, openBoard(bm, 9, 9), []Location{{4, 4}})
+}
+
+func BenchmarkClickOpenHard(bm *testing.B) {
+	benchmarkClicks(bm, openBoard(bm, 30, 16), []Location{{15, 8}})
+}
+
+func BenchmarkClickOpenLarge(bm *testing.B) {
+	benchmarkClicks(bm, openBoard(bm, 256, 256), []Location{{128, 128}})
+}
+
+func BenchmarkClickOpenCorner(bm *testing.B) {
+	benchmarkClicks(bm, openBoard(bm, 256, 256), []Location{{0, 0}})
+}
+
+func BenchmarkClickDenseEasy(bm *testing.B) {
+	b := denseBoard(bm, NewBoard("easy"))
+	benchmarkClicks(bm, b, safeCells(b))
+}
+
+func BenchmarkClickDenseMedium(bm *testing.B) {
+	b := denseBoard(bm, NewBoard("medium"))
+	benchmarkClicks(bm, b, safeCells(b))
+}
+
+func BenchmarkClickDenseHard(bm *testing.B) {
+	b := denseBoard(bm, NewBoard("hard"))
+	benchmarkClicks(bm, b, safeCells(b))
+}
+
+func BenchmarkClickDenseLarge(bm *testing.B) {
+	b := denseBoard(bm, NewCustomBoard(256, 256, 256*256/5))
+	benchmarkClicks(bm, b, safeCells(b))
+}
@@ -8,10 +8,10 @@ package msboard
 
 import (
 	"bytes"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"math/rand"
-	"os"
 	"testing"
 )
 
@@ -19,7 +19,7 @@ import (
 	TestBoardCreation -- Board currently supports only 3 sizes
 		9x9 (easy)
 		16x16 (medium)
-		30x16 (hard)
+		16x30 (hard), 16 rows of 30
 */
 func TestBoardCreation(t *testing.T) {
 	var cases = []struct {
@@ -31,7 +31,7 @@ func TestBoardCreation(t *testing.T) {
 		{"", 0, 0, false},
 		{"easy", 9, 9, true},
 		{"medium", 16, 16, true},
-		{"hard", 30, 16, true},
+		{"hard", 16, 30, true},
 		{"nightmare", 1024, 1024, false},
 	}
 
@@ -45,6 +45,18 @@ func TestBoardCreation(t *testing.T) {
 		if got != nil && (got.rows != testcase.rows || got.cols != testcase.cols) {
 			t.Errorf("NewBoard) returned incorrect shape. Expected %dx%d, got %dx%d", testcase.rows, testcase.cols, got.rows, got.cols)
 		}
+		if got != nil && (got.Height() != testcase.rows || got.Width() != testcase.cols) {
+			t.Errorf("NewBoard) %q is %d wide and %d high, wanted %d by %d", testcase.difficulty, got.Width(), got.Height(), testcase.cols, testcase.rows)
+		}
+
+		// turned on its side, the same board with width and height swapped
+		turned := NewTransposedBoard(testcase.difficulty)
+		if (turned != nil) != testcase.want {
+			t.Errorf("NewTransposedBoard) failed for %q got %v", testcase.difficulty, turned)
+		}
+		if turned != nil && (turned.Width() != testcase.rows || turned.Height() != testcase.cols || turned.MineCount() != got.MineCount() || turned.Difficulty() != testcase.difficulty) {
+			t.Errorf("NewTransposedBoard) %q is %d wide and %d high with %d mines", testcase.difficulty, turned.Width(), turned.Height(), turned.MineCount())
+		}
 	}
 }
 
@@ -93,7 +105,7 @@ func countMineCells(b *Board) int {
 
 	retval := 0
 	for r := 0; r < b.rows; r++ {
-		for c := 0; c < b.rows; c++ {
+		for c := 0; c < b.cols; c++ {
 			testcell := b.getCell(Location{r, c})
 			if testcell.HasMine() {
 				retval++
@@ -155,40 +167,42 @@ func TestCellScores(t *testing.T) {
 	}
 }
 
-/*
-
-//	This test function is used to generate correct test cases as teh board layout evolves; normally commented out
+// updateGolden : rewrite the render golden files in testdata from the current output instead of comparing to them,
+// for when the board layout evolves: go test -run TestConsoleRender -update
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
 
-func TestConsoleRenderToFile(t *testing.T) {
-	rand.Seed(1995) // want same test sequence each time
-
-	boardTypes := []boardparams{boardDefinitionsDict()["easy"], boardDefinitionsDict()["medium"], boardDefinitionsDict()["hard"]}
+func TestConsoleRender(t *testing.T) {
+	boardTypes := []boardparams{boardDefinitionsDict()["easy"], boardDefinitionsDict()["medium"], boardDefinitionsDict()["hard"],
+		{"custom.12x30", 12, 30, 60}, // columns past Z
+		{"custom.104x5", 104, 5, 80}, // three digit rows
+		{"custom.2x60", 2, 60, 20},   // two letter columns throughout
+		{"custom.1x1", 1, 1, 0},
+	}
 
 	for _, bt := range boardTypes {
 		b := NewBoard(bt.difficulty)
+		if b == nil {
+			b = NewCustomBoard(bt.rows, bt.cols, bt.mineCount)
+		}
 		if b == nil {
 			t.Errorf("Board Creation failed for difficulty %q", bt.difficulty)
 			continue
 		}
 
-		// Initialize with random starting Location
-		startingLocation := Location{rand.Intn(bt.rows), rand.Intn(bt.cols)}
-		ok := b.Initialize(startingLocation)
+		// Initialize with a seeded random starting Location and layout, the same each run
+		rng := rand.New(rand.NewSource(1995))
+		startingLocation := Location{rng.Intn(bt.rows), rng.Intn(bt.cols)}
+		ok := b.InitializeWithOptions(startingLocation, GeneratorOptions{RNG: rng})
 		if ok != nil {
 			t.Errorf("Board init for type %q failed with error %q.", bt.difficulty, ok)
 			continue
 		}
 
-		// capture output in a file
-		filename := fmt.Sprintf("render.%s.out", bt.difficulty)
-		buf, err := os.Create(filename)
-		if err != nil {
-			t.Errorf("Could not create output file %q : %s", filename, err)
-			continue
-		}
+		// capture output in a string buffer, which we will compare to a saved result
+		buf := bytes.NewBufferString("")
 
 		// render twice: once hidden, once revealed
-		err = b.ConsoleRender(buf)
+		err := b.ConsoleRender(buf)
 		if err != nil {
 			t.Errorf("Error during ConsoleRender for game type %q: %s", bt.difficulty, err)
 		}
@@ -199,60 +213,194 @@ func TestConsoleRenderToFile(t *testing.T) {
 		if err != nil {
 			t.Errorf("Error during ConsoleRender for game type %q: %s", bt.difficulty, err)
 		}
+
+		// Now compare the render againsgt the expected output
+		testfilename := fmt.Sprintf("testdata/render.%s.out", bt.difficulty)
+		if *updateGolden {
+			if err := ioutil.WriteFile(testfilename, buf.Bytes(), 0644); err != nil {
+				t.Errorf("Could not write Render test data file %q : %s", testfilename, err)
+			}
+			continue
+		}
+		testdata, err := ioutil.ReadFile(testfilename)
+		if err != nil {
+			t.Errorf("Could not read Render test data file %q : %s", testfilename, err)
+			continue
+		}
+		if string(testdata) != string(buf.Bytes()) {
+			t.Errorf("Render test comparison failure.  Expected:\n%q\n\n Got:\n%q\n", string(testdata), string(buf.Bytes()))
+		}
 	}
+
 }
 
-// End of test case generation function
+func TestCustomBoardCreation(t *testing.T) {
+	var cases = []struct {
+		rows, cols, mines int
+		want              bool
+	}{
+		{20, 40, 100, true},
+		{1, 2, 1, true},
+		{0, 10, 1, false},
+		{10, 10, -1, false},
+		{3, 3, 9, false},             // no room for the safe starting cell
+		{1 << 20, 1 << 20, 5, false}, // more cells than NewCustomBoard counts
+	}
 
-----------------------------------------*/
+	for _, testcase := range cases {
+		got := NewCustomBoard(testcase.rows, testcase.cols, testcase.mines)
+		if (got != nil) != testcase.want {
+			t.Errorf("NewCustomBoard(%d, %d, %d) wanted %v got %v", testcase.rows, testcase.cols, testcase.mines, testcase.want, got)
+			continue
+		}
+		if got != nil && (got.Rows() != testcase.rows || got.Cols() != testcase.cols || got.MineCount() != testcase.mines) {
+			t.Errorf("NewCustomBoard(%d, %d, %d) returned %dx%d with %d mines", testcase.rows, testcase.cols, testcase.mines, got.Rows(), got.Cols(), got.MineCount())
+		}
+	}
+}
 
-func TestConsoleRender(t *testing.T) {
-	rand.Seed(1995) // want same test sequence each time
+func TestBoardReset(t *testing.T) {
+	b, _ := ParseLayout("..*/...")
+	layout := b.Layout()
 
-	boardTypes := []boardparams{boardDefinitionsDict()["easy"], boardDefinitionsDict()["medium"], boardDefinitionsDict()["hard"]}
+	b.Click(NewLocation(0, 0))
+	b.ToggleFlag(NewLocation(1, 2))
+	b.Click(NewLocation(0, 2))
+	if b.Status() != StatusLost {
+		t.Fatalf("clicking the mine wanted a lost board got %v", b.Status())
+	}
 
-	for _, bt := range boardTypes {
-		b := NewBoard(bt.difficulty)
-		if b == nil {
-			t.Errorf("Board Creation failed for difficulty %q", bt.difficulty)
-			continue
-		}
+	if err := b.Reset(); err != nil {
+		t.Fatalf("Reset() failed: %s", err)
+	}
+	if b.Layout() != layout || b.Status() != StatusPlaying || b.SafeRemaining() != 5 {
+		t.Errorf("Reset() wanted layout %q playing with 5 safe got %q %v with %d safe", layout, b.Layout(), b.Status(), b.SafeRemaining())
+	}
 
-		// Initialize with random starting Location
-		startingLocation := Location{rand.Intn(bt.rows), rand.Intn(bt.cols)}
-		ok := b.Initialize(startingLocation)
-		if ok != nil {
-			t.Errorf("Board init for type %q failed with error %q.", bt.difficulty, ok)
-			continue
+	if err := NewBoard("easy").Reset(); err == nil {
+		t.Errorf("Reset() accepted an uninitialized board")
+	}
+}
+
+func TestFirstClick(t *testing.T) {
+	rand.Seed(1995)
+	b := NewBoard("medium")
+	start := NewLocation(7, 9)
+
+	revealed, err := b.FirstClick(start)
+	if err != nil {
+		t.Fatalf("FirstClick() failed: %s", err)
+	}
+	if len(revealed) == 0 || revealed[0] != start {
+		t.Errorf("FirstClick() wanted the reveals to start at %v got %v", start, revealed)
+	}
+	if view, _ := b.Snapshot().Cell(start); view.State != CellRevealed {
+		t.Errorf("FirstClick() wanted %v revealed got %v", start, view)
+	}
+	if b.Status() != StatusPlaying || len(b.Mines()) != 30 {
+		t.Errorf("FirstClick() wanted a playing board with 30 mines got %v with %d", b.Status(), len(b.Mines()))
+	}
+
+	if _, err := b.FirstClick(start); err == nil {
+		t.Errorf("FirstClick() accepted a board already in play")
+	}
+	if _, err := NewBoard("easy").FirstClick(NewLocation(9, 0)); err == nil {
+		t.Errorf("FirstClick() accepted an off-board location")
+	}
+
+	// constraints that can't be met leave the board ready for another try
+	b = NewBoard("easy")
+	if _, err := b.FirstClickWithOptions(start, GeneratorOptions{MinOpening: 80}); err == nil || b.Initialized() {
+		t.Errorf("FirstClickWithOptions() with an impossible opening wanted an uninitialized board, err %v", err)
+	}
+}
+
+func TestClickRevealed(t *testing.T) {
+	b, _ := ParseLayout("..*/.../...")
+
+	var cases = []struct {
+		l    Location
+		want int
+	}{
+		{Location{0, 1}, 1}, // a number reveals only itself
+		{Location{2, 0}, 7}, // the zero at A3 floods everything except B1, already revealed, and the mine
+		{Location{2, 0}, 0}, // already revealed
+		{Location{0, 2}, 1}, // the mine
+	}
+	for _, testcase := range cases {
+		revealed := b.Click(testcase.l)
+		if len(revealed) != testcase.want || (testcase.want > 0 && revealed[0] != testcase.l) {
+			t.Errorf("Click(%v) wanted %d cells starting with it, got %v", testcase.l, testcase.want, revealed)
 		}
+	}
+}
 
-		// capture output in a string buffer, which we will compare to a saved result
-		buf := bytes.NewBufferString("")
+func TestPropagationRules(t *testing.T) {
+	var cases = []struct {
+		rule PropagationRule
+		want string
+	}{
+		{PropagateStandard, "___/111/.*."},
+		{PropagateZerosOnly, "___/.../.*."},
+	}
 
-		// render twice: once hidden, once revealed
-		err := b.ConsoleRender(buf)
-		if err != nil {
-			t.Errorf("Error during ConsoleRender for game type %q: %s", bt.difficulty, err)
+	for _, testcase := range cases {
+		b, _ := ParseLayout(".../.../.*.")
+		b.SetPropagation(testcase.rule)
+		b.Click(NewLocation(0, 0))
+		if got := b.Layout(); got != testcase.want {
+			t.Errorf("%v propagation wanted %q got %q", testcase.rule, testcase.want, got)
 		}
-		fmt.Fprintln(buf)
 
-		b.RevealAll()
-		err = b.ConsoleRender(buf)
-		if err != nil {
-			t.Errorf("Error during ConsoleRender for game type %q: %s", bt.difficulty, err)
+		// numbered cells never start a cascade
+		b, _ = ParseLayout(".../.../.*.")
+		b.SetPropagation(testcase.rule)
+		if got := b.Click(NewLocation(1, 1)); len(got) != 1 {
+			t.Errorf("%v propagation from a number wanted 1 cell got %v", testcase.rule, got)
 		}
+	}
+}
 
-		// Now compare the render againsgt the expected output
-		testfilename := fmt.Sprintf("testdata/render.%s.out", bt.difficulty)
-		testdata, err := ioutil.ReadFile(testfilename)
-		if err != nil {
-			// ignore errors around reading test case data
-			fmt.Fprintf(os.Stderr, "Could not read Render test data file %q, skipping render comparison", testfilename)
-			continue
+func TestFloodKeepsFlags(t *testing.T) {
+	var cases = []struct {
+		clears bool
+		want   string
+		safe   int
+	}{
+		{false, "__f/111/.*.", 3},
+		{true, "___/111/.*.", 2},
+	}
+
+	for _, testcase := range cases {
+		b, _ := ParseLayout(".../.../.*.")
+		b.SetFloodClearsFlags(testcase.clears)
+		b.ToggleFlag(NewLocation(0, 2))
+		b.Click(NewLocation(0, 0))
+
+		if got := b.Layout(); got != testcase.want {
+			t.Errorf("flood with clears=%v wanted %q got %q", testcase.clears, testcase.want, got)
 		}
-		if string(testdata) != string(buf.Bytes()) {
-			t.Errorf("Render test comparison failure.  Expected:\n%q\n\n Got:\n%q\n", string(testdata), string(buf.Bytes()))
+		if b.SafeRemaining() != testcase.safe {
+			t.Errorf("flood with clears=%v left %d safe cells", testcase.clears, b.SafeRemaining())
 		}
 	}
+}
+
+func TestSafeRemainingCountsReveals(t *testing.T) {
+	b, _ := ParseLayout(".../.../.*.")
+	if b.SafeRemaining() != 8 {
+		t.Fatalf("new board wanted 8 safe cells got %d", b.SafeRemaining())
+	}
 
+	b.Click(NewLocation(2, 0))
+	b.Click(NewLocation(2, 0)) // revealed cells don't count twice
+	if b.SafeRemaining() != 7 || b.Status() != StatusPlaying {
+		t.Errorf("one reveal wanted 7 safe cells, playing got %d, %v", b.SafeRemaining(), b.Status())
+	}
+
+	b.Click(NewLocation(0, 0))
+	b.Click(NewLocation(2, 2))
+	if b.SafeRemaining() != 0 || b.Status() != StatusWon {
+		t.Errorf("revealing every safe cell wanted a win got %d safe, %v", b.SafeRemaining(), b.Status())
+	}
 }
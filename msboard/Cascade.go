@@ -0,0 +1,38 @@
+/*
+
+	Cascade.go - the waves of a flood reveal, for front ends that show a cascade spreading rather than all at once
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+// CascadeWaves -- split the cells revealed by a click, as Click returns them, into waves: the clicked cell alone,
+// then the cells revealed by its zero score, then those revealed by the zeros among those, and so on. s is the
+// position after the click
+func CascadeWaves(s Snapshot, revealed []Location) [][]Location {
+	if len(revealed) == 0 {
+		return nil
+	}
+
+	wave := map[Location]int{revealed[0]: 0}
+	retval := [][]Location{{revealed[0]}}
+	for _, l := range revealed[1:] {
+		// Click lists cells breadth first, so the zero that revealed l is already placed, in the earliest wave
+		// of any zero next to it
+		w := len(retval)
+		for _, n := range s.Neighbors(l) {
+			from, placed := wave[n]
+			if view, _ := s.Cell(n); placed && view.Score == 0 && from+1 < w {
+				w = from + 1
+			}
+		}
+		if w == len(retval) {
+			retval = append(retval, nil)
+		}
+		wave[l] = w
+		retval[w] = append(retval[w], l)
+	}
+	return retval
+}
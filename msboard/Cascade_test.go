@@ -0,0 +1,70 @@
+/*
+	Test functions for cascade waves
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCascadeWaves(t *testing.T) {
+	// the click at A1 opens everything but the far corner mine, spreading a ring at a time
+	b, err := ParseLayout("..../..../..../...*")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+	revealed := b.Click(NewLocation(0, 0))
+	waves := CascadeWaves(b.Snapshot(), revealed)
+
+	wantSizes := []int{1, 3, 5, 6}
+	if len(waves) != len(wantSizes) {
+		t.Fatalf("CascadeWaves wanted %d waves got %v", len(wantSizes), waves)
+	}
+	for i, want := range wantSizes {
+		if len(waves[i]) != want {
+			t.Errorf("CascadeWaves wave %d wanted %d cells got %v", i, want, waves[i])
+		}
+	}
+	if b.Status() != StatusWon || waves[3][0] != NewLocation(0, 3) {
+		t.Errorf("CascadeWaves last wave wanted the ring from D1 got %v", waves[3])
+	}
+}
+
+func TestClickBreadthFirst(t *testing.T) {
+	rand.Seed(1995)
+	b := NewBoard("hard")
+	revealed, _ := b.FirstClickWithOptions(NewLocation(8, 15), GeneratorOptions{MinOpening: 40})
+	waves := CascadeWaves(b.Snapshot(), revealed)
+
+	// Click lists the waves one after another, and every cell after the first is next to a zero a wave before it
+	i := 0
+	for w, wave := range waves {
+		for _, l := range wave {
+			if revealed[i] != l {
+				t.Fatalf("Click order differs from the waves at %d: %v", i, l)
+			}
+			i++
+			if w > 0 && !besideZero(b.Snapshot(), l, waves[w-1]) {
+				t.Errorf("%v in wave %d has no zero beside it in the wave before", l, w)
+			}
+		}
+	}
+	if i != len(revealed) {
+		t.Errorf("CascadeWaves placed %d of %d cells", i, len(revealed))
+	}
+}
+
+// besideZero -- true if one of the cells is a revealed zero next to l
+func besideZero(s Snapshot, l Location, cells []Location) bool {
+	for _, n := range cells {
+		view, _ := s.Cell(n)
+		if view.Score == 0 && n != l && n.row-l.row <= 1 && l.row-n.row <= 1 && n.col-l.col <= 1 && l.col-n.col <= 1 {
+			return true
+		}
+	}
+	return false
+}
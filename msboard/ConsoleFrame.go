@@ -0,0 +1,62 @@
+/*
+
+	ConsoleFrame.go - buffers kept between calls to Board.ConsoleRender, so drawing a board after every move doesn't
+	allocate: the layout and labels are worked out once per board, and each frame is built in a reused buffer and
+	written in one go
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+// consoleFrame : what ConsoleRender keeps for a board of a given size
+type consoleFrame struct {
+	rows, cols int
+	layout     GridLayout
+	colLabels  []string
+	rowLabels  []string
+	cells      []string // one row's sign and glyph pairs
+	buf        []byte
+}
+
+// consoleGlyphs : sign and glyph for each rune a cell renders as, a blank sign before it
+var consoleGlyphs = func() map[rune]string {
+	retval := map[rune]string{}
+	for _, r := range "~.+*_12345678" {
+		retval[r] = " " + string(r)
+	}
+	return retval
+}()
+
+// consoleGlyph -- sign and glyph drawn for a cell rune
+func consoleGlyph(r rune) string {
+	if glyph, ok := consoleGlyphs[r]; ok {
+		return glyph
+	}
+	return " " + string(r)
+}
+
+// consoleFrame -- the board's console buffers, set up again if its size or topology changed since the last frame
+func (b *Board) consoleFrame() *consoleFrame {
+	if nil != b.console && b.console.rows == b.rows && b.console.cols == b.cols {
+		return b.console
+	}
+
+	var codec LocationCodec = LetterNumberCodec{}
+	if nil != b.topology {
+		codec = b.topology.Codec
+	}
+	frame := &consoleFrame{rows: b.rows, cols: b.cols, layout: NewGridLayout(b.rows, b.cols, codec),
+		colLabels: make([]string, b.cols), rowLabels: make([]string, b.rows), cells: make([]string, b.cols)}
+	for col := range frame.colLabels {
+		frame.colLabels[col] = codec.ColLabel(col, b.cols)
+	}
+	for row := range frame.rowLabels {
+		frame.rowLabels[row] = codec.RowLabel(row, b.rows)
+	}
+	// room for every line: margin, cells and newline
+	frame.buf = make([]byte, 0, (b.rows+1)*(frame.layout.Column(b.cols)+frame.layout.LabelWidth+2))
+	b.console = frame
+	return frame
+}
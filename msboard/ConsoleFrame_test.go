@@ -0,0 +1,72 @@
+/*
+	Test functions and benchmarks for the buffers ConsoleRender reuses. Run the benchmarks with
+
+		go test -run - -bench ConsoleRender ./msboard/
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestConsoleRenderAllocs -- once a board has been drawn, drawing it again allocates nothing
+func TestConsoleRenderAllocs(t *testing.T) {
+	b := NewBoard("hard")
+	if err := b.InitializeWithOptions(Location{8, 15}, GeneratorOptions{RNG: NewPCG(1995)}); err != nil {
+		t.Fatal(err)
+	}
+	b.Click(Location{8, 15})
+	if allocs := testing.AllocsPerRun(100, func() { b.ConsoleRender(io.Discard) }); allocs != 0 {
+		t.Errorf("ConsoleRender allocated %.1f times a frame", allocs)
+	}
+}
+
+// TestConsoleRenderResized -- a board turned on its side is drawn at its new size, not from the old one's buffers
+func TestConsoleRenderResized(t *testing.T) {
+	b := NewCustomBoard(2, 5, 1)
+	if err := b.InitializeWithOptions(Location{0, 0}, GeneratorOptions{RNG: NewPCG(1995)}); err != nil {
+		t.Fatal(err)
+	}
+	b.ConsoleRender(io.Discard)
+	turned, err := b.Transform(TransformTranspose)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	turned.ConsoleRender(&buf)
+	if lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"); len(lines) != 6 ||
+		strings.TrimSpace(lines[0]) != "A  B" {
+		t.Errorf("transposed board drawn wrong:\n%s", buf.String())
+	}
+}
+
+// benchmarkConsoleRender -- time drawing a board of the given size and mine count, part revealed
+func benchmarkConsoleRender(bm *testing.B, rows, cols, mines int) {
+	b := NewCustomBoard(rows, cols, mines)
+	first := Location{rows / 2, cols / 2}
+	if err := b.InitializeWithOptions(first, GeneratorOptions{RNG: NewPCG(1995)}); err != nil {
+		bm.Fatal(err)
+	}
+	b.Click(first)
+
+	bm.ReportAllocs()
+	bm.ResetTimer()
+	for i := 0; i < bm.N; i++ {
+		b.ConsoleRender(io.Discard)
+	}
+}
+
+func BenchmarkConsoleRenderHard(bm *testing.B) {
+	benchmarkConsoleRender(bm, 16, 30, 72)
+}
+
+func BenchmarkConsoleRenderLarge(bm *testing.B) {
+	benchmarkConsoleRender(bm, 256, 256, 6500)
+}
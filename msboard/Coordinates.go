@@ -0,0 +1,217 @@
+/*
+
+	Coordinates.go - coordinate schemes for naming cells to players: reading the locations they type, naming cells
+	in messages and labelling the rows and columns renderers draw, all from one LocationCodec so they always agree
+
+	Letter-number (C4, the default), number-number (3 7, row then column) and chess-like (c4 with a1 in a chosen
+	corner) are built in; ParseCoordinates picks one by name.
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// LocationCodec : a coordinate scheme. Schemes counting from the bottom or right need the board size, so every
+// method is given it; the location Parse returns may be off the board, for the caller to check
+type LocationCodec interface {
+	Format(l Location, rows, cols int) string
+	Parse(name string, rows, cols int) (Location, error)
+	RowLabel(row, rows int) string // label drawn beside a row
+	ColLabel(col, cols int) string // label drawn above a column
+}
+
+// LetterNumberCodec : spreadsheet style column letters and a row number counted from the top, as in C4; columns
+// past Z are AA, AB, ...
+type LetterNumberCodec struct{}
+
+// NumberNumberCodec : row and column numbers counted from the top left, row first, as in "3 7" for the seventh
+// cell of the third row; for players used to grid paper
+type NumberNumberCodec struct{}
+
+// Corner : a corner of the board
+type Corner int
+
+// Board corners, the chess-like scheme's origin
+const (
+	CornerBottomLeft Corner = iota // where a1 is on a chess board
+	CornerTopLeft
+	CornerTopRight
+	CornerBottomRight
+)
+
+var cornerNames = [...]string{"bottom-left", "top-left", "top-right", "bottom-right"}
+
+// String -- human readable corner name
+func (c Corner) String() string {
+	if c < 0 || int(c) >= len(cornerNames) {
+		return "unknown"
+	}
+	return cornerNames[c]
+}
+
+// ChessCodec : files as lower case letters and ranks as numbers, counted from the Origin corner, as in c4. The
+// zero value puts a1 at the bottom left, as on a chess board
+type ChessCodec struct {
+	Origin Corner
+}
+
+// compile time checks that the built in schemes can be used as codecs
+var (
+	_ LocationCodec = LetterNumberCodec{}
+	_ LocationCodec = NumberNumberCodec{}
+	_ LocationCodec = ChessCodec{}
+)
+
+// ParseCoordinates -- a coordinate scheme by name: "letter-number", "number-number", or "chess" optionally
+// followed by a colon and the origin corner, e.g. "chess:top-right"; chess alone is bottom-left
+func ParseCoordinates(name string) (LocationCodec, error) {
+	scheme, corner := strings.ToLower(name), ""
+	if i := strings.Index(scheme, ":"); i >= 0 {
+		scheme, corner = scheme[:i], scheme[i+1:]
+	}
+
+	switch {
+	case scheme == "letter-number" && corner == "":
+		return LetterNumberCodec{}, nil
+	case scheme == "number-number" && corner == "":
+		return NumberNumberCodec{}, nil
+	case scheme == "chess" && corner == "":
+		return ChessCodec{}, nil
+	case scheme == "chess":
+		for i, cornerName := range cornerNames {
+			if corner == cornerName {
+				return ChessCodec{Origin: Corner(i)}, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown corner %q, expected one of %s", corner, strings.Join(cornerNames[:], ", "))
+	}
+	return nil, fmt.Errorf("unknown coordinate scheme %q, expected letter-number, number-number or chess[:corner]", name)
+}
+
+// Format -- column letters then row number, e.g. C4
+func (LetterNumberCodec) Format(l Location, rows, cols int) string {
+	return letters(l.col) + strconv.Itoa(l.row+1)
+}
+
+// Parse -- a location from a row number and column letters in either order and either case, e.g. c4 or 4C
+func (LetterNumberCodec) Parse(name string, rows, cols int) (Location, error) {
+	col, row, ok := lettersAndNumber(name)
+	if !ok {
+		return Location{-1, -1}, fmt.Errorf("%q is not a cell location, expected e.g. a1", name)
+	}
+	return Location{row - 1, col}, nil // players count rows from 1
+}
+
+// RowLabel -- the row number
+func (LetterNumberCodec) RowLabel(row, rows int) string {
+	return strconv.Itoa(row + 1)
+}
+
+// ColLabel -- the column letters
+func (LetterNumberCodec) ColLabel(col, cols int) string {
+	return letters(col)
+}
+
+// Format -- row and column numbers, e.g. "3 7"
+func (NumberNumberCodec) Format(l Location, rows, cols int) string {
+	return fmt.Sprintf("%d %d", l.row+1, l.col+1)
+}
+
+// Parse -- a location from two numbers, row then column, separated by anything that isn't a digit
+func (NumberNumberCodec) Parse(name string, rows, cols int) (Location, error) {
+	numbers := strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsDigit(r) })
+	if len(numbers) != 2 {
+		return Location{-1, -1}, fmt.Errorf("%q is not a cell location, expected a row and column, e.g. 3 7", name)
+	}
+	row, _ := strconv.Atoi(numbers[0])
+	col, _ := strconv.Atoi(numbers[1])
+	return Location{row - 1, col - 1}, nil
+}
+
+// RowLabel -- the row number
+func (NumberNumberCodec) RowLabel(row, rows int) string {
+	return strconv.Itoa(row + 1)
+}
+
+// ColLabel -- the column number
+func (NumberNumberCodec) ColLabel(col, cols int) string {
+	return strconv.Itoa(col + 1)
+}
+
+// Format -- file letters then rank, e.g. c4
+func (c ChessCodec) Format(l Location, rows, cols int) string {
+	return c.ColLabel(l.col, cols) + c.RowLabel(l.row, rows)
+}
+
+// Parse -- a location from a rank and file letters in either order and either case, e.g. c4 or 4C
+func (c ChessCodec) Parse(name string, rows, cols int) (Location, error) {
+	file, rank, ok := lettersAndNumber(name)
+	if !ok {
+		return Location{-1, -1}, fmt.Errorf("%q is not a cell location, expected e.g. a1", name)
+	}
+	return Location{c.flipRow(rank-1, rows), c.flipCol(file, cols)}, nil
+}
+
+// RowLabel -- the rank, counted from the origin's edge
+func (c ChessCodec) RowLabel(row, rows int) string {
+	return strconv.Itoa(c.flipRow(row, rows) + 1)
+}
+
+// ColLabel -- the file in lower case, counted from the origin's edge
+func (c ChessCodec) ColLabel(col, cols int) string {
+	return strings.ToLower(letters(c.flipCol(col, cols)))
+}
+
+// flipRow -- rank index for a row or row for a rank index, 0 based; the mapping is its own inverse
+func (c ChessCodec) flipRow(row, rows int) int {
+	if c.Origin == CornerBottomLeft || c.Origin == CornerBottomRight {
+		return rows - 1 - row
+	}
+	return row
+}
+
+// flipCol -- file index for a column or column for a file index, 0 based; the mapping is its own inverse
+func (c ChessCodec) flipCol(col, cols int) int {
+	if c.Origin == CornerTopRight || c.Origin == CornerBottomRight {
+		return cols - 1 - col
+	}
+	return col
+}
+
+// letters -- spreadsheet style name of a 0 based index: A..Z, then AA, AB, ...
+func letters(index int) string {
+	retval := ""
+	for ; index >= 0; index = index/26 - 1 {
+		retval = string(rune('A'+index%26)) + retval
+	}
+	return retval
+}
+
+// lettersAndNumber -- the 0 based index of the letters and the value of the digits in a name, wherever they
+// appear in it; false unless it has both
+func lettersAndNumber(name string) (index, number int, ok bool) {
+	digits := ""
+	index = -1
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsDigit(r) {
+			digits += string(r)
+		} else if r >= 'a' && r <= 'z' {
+			// past z come aa, ab, ...
+			index = (index+1)*26 + int(r-'a')
+		}
+	}
+
+	number, err := strconv.Atoi(digits)
+	if err != nil || index < 0 {
+		return 0, 0, false
+	}
+	return index, number, true
+}
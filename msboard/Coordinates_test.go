@@ -0,0 +1,63 @@
+/*
+	Test functions for coordinate schemes
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"testing"
+)
+
+func TestCoordinateSchemes(t *testing.T) {
+	var cases = []struct {
+		scheme     string
+		l          Location
+		name       string
+		row, col   string // labels
+		alternates []string
+	}{
+		{"letter-number", Location{3, 2}, "C4", "4", "C", []string{"c4", "4c", "c 4"}},
+		{"letter-number", Location{9, 26}, "AA10", "10", "AA", []string{"aa10"}},
+		{"number-number", Location{2, 6}, "3 7", "3", "7", []string{"3,7", " 3  7 "}},
+		{"chess", Location{7, 0}, "a1", "1", "a", []string{"A1", "1a"}},
+		{"chess", Location{0, 2}, "c8", "8", "c", nil},
+		{"chess:top-left", Location{0, 0}, "a1", "1", "a", nil},
+		{"chess:top-right", Location{1, 7}, "a2", "2", "a", nil},
+		{"chess:bottom-right", Location{7, 6}, "b1", "1", "b", nil},
+	}
+
+	// an 8x8 board
+	for _, testcase := range cases {
+		codec, err := ParseCoordinates(testcase.scheme)
+		if err != nil {
+			t.Fatalf("ParseCoordinates(%q) failed: %s", testcase.scheme, err)
+		}
+		if got := codec.Format(testcase.l, 8, 8); got != testcase.name {
+			t.Errorf("%s Format(%v) wanted %q got %q", testcase.scheme, testcase.l, testcase.name, got)
+		}
+		if row, col := codec.RowLabel(testcase.l.row, 8), codec.ColLabel(testcase.l.col, 8); row != testcase.row || col != testcase.col {
+			t.Errorf("%s labels of %v wanted %q, %q got %q, %q", testcase.scheme, testcase.l, testcase.row, testcase.col, row, col)
+		}
+		for _, name := range append([]string{testcase.name}, testcase.alternates...) {
+			if got, err := codec.Parse(name, 8, 8); err != nil || got != testcase.l {
+				t.Errorf("%s Parse(%q) wanted %v got %v, %v", testcase.scheme, name, testcase.l, got, err)
+			}
+		}
+	}
+
+	for _, bad := range []string{"", "chess:middle", "letter-number:top-left", "hex"} {
+		if _, err := ParseCoordinates(bad); err == nil {
+			t.Errorf("ParseCoordinates(%q) should fail", bad)
+		}
+	}
+	for _, bad := range []string{"c", "12"} {
+		if _, err := (LetterNumberCodec{}).Parse(bad, 8, 8); err == nil {
+			t.Errorf("letter-number Parse(%q) should fail", bad)
+		}
+	}
+	if _, err := (NumberNumberCodec{}).Parse("37", 8, 8); err == nil {
+		t.Errorf("number-number Parse of one number should fail")
+	}
+}
@@ -0,0 +1,107 @@
+/*
+
+	Debug.go - developer access to hidden Board state, used by the debug console
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MineAt -- true if the cell at l holds a mine, revealed or not. For debugging and tools only
+func (b *Board) MineAt(l Location) bool {
+	if nil == b || !b.initialized {
+		return false
+	}
+	return b.getCell(l).HasMine()
+}
+
+// Mines -- locations of every mine on the board. For debugging and tools only
+func (b *Board) Mines() []Location {
+	if nil == b {
+		return nil
+	}
+	return append([]Location(nil), b.mines...)
+}
+
+// RevealRegion -- reveal every unflagged cell in the rectangle spanned by two corners, mines included, without
+// triggering an explosion. Returns the number of cells newly revealed
+func (b *Board) RevealRegion(from, to Location) (int, error) {
+	if nil == b || !b.initialized {
+		return 0, errors.New("called RevealRegion() on an uninitialized board")
+	}
+	if !b.ValidLocation(from) || !b.ValidLocation(to) {
+		return 0, fmt.Errorf("region %v:%v is not on the board", from, to)
+	}
+
+	top, bottom := from.row, to.row
+	if top > bottom {
+		top, bottom = bottom, top
+	}
+	left, right := from.col, to.col
+	if left > right {
+		left, right = right, left
+	}
+
+	retval := 0
+	for row := top; row <= bottom; row++ {
+		for col := left; col <= right; col++ {
+			c := b.cells[row][col]
+			if c.revealed || c.flagged {
+				continue
+			}
+			c.revealed = true
+			if !c.hasMine {
+				b.safeRemaining--
+			}
+			retval++
+		}
+	}
+	b.checkAudit("RevealRegion")
+
+	return retval, nil
+}
+
+// DebugDump -- write the full internal board state: counters, mine list and a grid showing every mine and score,
+// with hidden cells in parentheses and flagged cells marked +. Anti-mines show as x and negative scores as a-h
+func (b *Board) DebugDump(cout io.Writer) error {
+	if nil == b || !b.initialized {
+		return errors.New("called DebugDump() on an uninitialized board")
+	}
+
+	fmt.Fprintf(cout, "difficulty %s, %dx%d, %d mines, %d safe remaining, status %v, explosion %v\n",
+		b.difficulty, b.rows, b.cols, b.mineCount, b.safeRemaining, b.Status(), b.explosionOccured)
+	fmt.Fprintln(cout, "mines:", b.mines)
+
+	for row := range b.cells {
+		fmt.Fprintf(cout, "%3d ", row+1)
+		for _, c := range b.cells[row] {
+			glyph := scoreRune(c.score)
+			switch {
+			case c.hasMine && c.anti:
+				glyph = 'x'
+			case c.hasMine:
+				glyph = '*'
+			case c.score < 0:
+				glyph = rune('a' - 1 - c.score)
+			}
+			switch {
+			case c.flagged:
+				fmt.Fprintf(cout, "+%c ", glyph)
+			case c.revealed:
+				fmt.Fprintf(cout, " %c ", glyph)
+			default:
+				fmt.Fprintf(cout, "(%c)", glyph)
+			}
+		}
+		fmt.Fprintln(cout)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+/*
+	Test functions for Board debugging helpers
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestDebugMines(t *testing.T) {
+	rand.Seed(1995)
+	b := NewBoard("easy")
+	if b.MineAt(Location{0, 0}) || len(b.Mines()) != 0 {
+		t.Errorf("Uninitialized board should report no mines")
+	}
+
+	// initializing twice must not accumulate mines from the first layout
+	b.Initialize(Location{0, 0})
+	b.Initialize(Location{4, 4})
+	mines := b.Mines()
+	if len(mines) != b.mineCount {
+		t.Errorf("Mines() wanted %d locations got %d", b.mineCount, len(mines))
+	}
+	for _, l := range mines {
+		if !b.MineAt(l) {
+			t.Errorf("MineAt(%v) false for a location listed by Mines()", l)
+		}
+	}
+}
+
+func TestRevealRegion(t *testing.T) {
+	rand.Seed(1995)
+	b := NewBoard("easy")
+	if _, err := b.RevealRegion(Location{0, 0}, Location{1, 1}); err == nil {
+		t.Errorf("RevealRegion() should fail on an uninitialized board")
+	}
+
+	b.Initialize(Location{4, 4})
+	b.ToggleFlag(Location{0, 0})
+	got, err := b.RevealRegion(Location{2, 2}, Location{0, 0})
+	if err != nil || got != 8 {
+		t.Errorf("RevealRegion() of 3x3 corner with one flag wanted 8 reveals got %d err %v", got, err)
+	}
+	if b.MineHit() {
+		t.Errorf("RevealRegion() must not trigger explosions")
+	}
+	if _, err = b.RevealRegion(Location{0, 0}, Location{9, 9}); err == nil {
+		t.Errorf("RevealRegion() accepted an off-board corner")
+	}
+
+	buf := bytes.NewBufferString("")
+	b.DebugDump(buf)
+	if !strings.Contains(buf.String(), "10 mines") || strings.Count(buf.String(), "\n") != 2+b.rows {
+		t.Errorf("DebugDump() output unexpected:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,80 @@
+/*
+
+	Fog.go - fog of war: with a visibility radius set, only hidden cells within that many rows and columns of a
+	revealed cell can be clicked. The rest are out of range until the revealed area grows towards them
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+// SetFog -- limit clicks to hidden cells within radius of a revealed cell; 0 turns the fog off
+func (b *Board) SetFog(radius int) {
+	if radius < 0 {
+		radius = 0
+	}
+	b.fog = radius
+}
+
+// Fog -- the board's visibility radius, 0 if there is no fog
+func (b *Board) Fog() int {
+	return b.fog
+}
+
+// InRange -- true if the location can be clicked through the fog: it's within the radius of a revealed safe cell,
+// or the board has no fog or nothing revealed yet, so the first click can go anywhere
+func (b *Board) InRange(l Location) bool {
+	if !b.ValidLocation(l) {
+		return false
+	}
+	if b.fog == 0 || !b.anyRevealed() {
+		return true
+	}
+	for row := l.row - b.fog; row <= l.row+b.fog; row++ {
+		for col := l.col - b.fog; col <= l.col+b.fog; col++ {
+			if c := b.getCell(Location{row, col}); nil != c && c.revealed && !c.hasMine {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Reachable -- InRange for every cell, found in one pass over the revealed cells
+func (b *Board) Reachable() [][]bool {
+	retval := make([][]bool, b.rows)
+	for row := range retval {
+		retval[row] = make([]bool, b.cols)
+	}
+
+	if b.fog == 0 || !b.anyRevealed() {
+		for row := range retval {
+			for col := range retval[row] {
+				retval[row][col] = true
+			}
+		}
+		return retval
+	}
+
+	for _, cells := range b.cells {
+		for _, c := range cells {
+			if !c.revealed || c.hasMine {
+				continue
+			}
+			for row := c.location.row - b.fog; row <= c.location.row+b.fog; row++ {
+				for col := c.location.col - b.fog; col <= c.location.col+b.fog; col++ {
+					if b.ValidLocation(Location{row, col}) {
+						retval[row][col] = true
+					}
+				}
+			}
+		}
+	}
+	return retval
+}
+
+// anyRevealed -- true once a safe cell has been revealed on an initialized board
+func (b *Board) anyRevealed() bool {
+	return b.initialized && b.safeRemaining < b.rows*b.cols-b.mineCount
+}
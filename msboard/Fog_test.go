@@ -0,0 +1,67 @@
+/*
+	Test functions for fog of war
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import "testing"
+
+func TestFog(t *testing.T) {
+	b, err := ParseLayout("_...*/_..../_....")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+
+	// without fog everything can be clicked
+	if !b.InRange(NewLocation(2, 4)) {
+		t.Errorf("InRange wanted every cell in range without fog")
+	}
+
+	b.SetFog(1)
+	var cases = []struct {
+		row, col int
+		want     bool
+	}{
+		{0, 0, true},
+		{0, 1, true},
+		{2, 1, true},
+		{0, 2, false},
+		{2, 4, false},
+		{3, 0, false}, // off the board
+	}
+	reach := b.Reachable()
+	s := b.Snapshot()
+	for _, testcase := range cases {
+		l := NewLocation(testcase.row, testcase.col)
+		if got := b.InRange(l); got != testcase.want {
+			t.Errorf("InRange(%v) wanted %v got %v", l, testcase.want, got)
+		}
+		if !b.ValidLocation(l) {
+			continue
+		}
+		if reach[testcase.row][testcase.col] != testcase.want {
+			t.Errorf("Reachable at %v wanted %v", l, testcase.want)
+		}
+		if v, _ := s.Cell(l); v.OutOfRange != (v.State == CellHidden && !testcase.want) {
+			t.Errorf("Snapshot cell %v out of range wanted %v got %+v", l, !testcase.want, v)
+		}
+	}
+
+	// clicks out of range are ignored, in range ones cascade as usual
+	if revealed := b.Click(NewLocation(0, 2)); revealed != nil {
+		t.Errorf("Click out of range wanted nothing revealed, got %v", revealed)
+	}
+	b.Click(NewLocation(1, 1))
+	if b.Status() != StatusWon {
+		t.Errorf("Click in range wanted the cascade to clear the board, got %v", b.Status())
+	}
+
+	// the first click of a game can go anywhere
+	fresh := NewBoard("easy")
+	fresh.SetFog(2)
+	if !fresh.InRange(NewLocation(8, 8)) {
+		t.Errorf("InRange wanted every cell in range before the first click")
+	}
+}
@@ -0,0 +1,292 @@
+/*
+
+	Generator.go - mine placement for new boards
+
+	Mines are placed by picking uniformly at random among "orbits": groups of cells that the requested symmetry
+	maps onto each other. With no symmetry every cell is its own orbit and this is a plain uniform shuffle, which
+	avoids the row-order bias of the old scan-and-roll placement.
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Symmetry : mirror or rotation constraint on generated mine layouts
+type Symmetry int
+
+// Supported layout symmetries
+const (
+	SymmetryNone       Symmetry = iota
+	SymmetryHorizontal          // top half mirrors the bottom half
+	SymmetryVertical            // left half mirrors the right half
+	SymmetryRotational          // unchanged by a half turn
+)
+
+var symmetryNames = [...]string{"none", "horizontal", "vertical", "rotational"}
+
+// String -- human readable symmetry name
+func (s Symmetry) String() string {
+	if s < 0 || int(s) >= len(symmetryNames) {
+		return "unknown"
+	}
+	return symmetryNames[s]
+}
+
+// Algorithm : how mines are placed
+type Algorithm int
+
+// Supported placement algorithms
+const (
+	AlgorithmUniform Algorithm = iota // uniform over the cells the other options allow
+	AlgorithmWinmine                  // classic Windows Minesweeper placement, see Winmine.go
+)
+
+var algorithmNames = [...]string{"uniform", "winmine"}
+
+// String -- human readable algorithm name
+func (a Algorithm) String() string {
+	if a < 0 || int(a) >= len(algorithmNames) {
+		return "unknown"
+	}
+	return algorithmNames[a]
+}
+
+// GeneratorOptions : constraints on mine placement; the zero value is a uniformly random layout
+type GeneratorOptions struct {
+	Algorithm  Algorithm `json:"algorithm,omitempty"`
+	Symmetry   Symmetry  `json:"symmetry,omitempty"`
+	NoEights   bool      `json:"noEights,omitempty"`   // reject layouts where a safe cell is surrounded by 8 mines
+	MinOpening int       `json:"minOpening,omitempty"` // if > 0, the safe spot must be a zero whose first click reveals at least this many cells
+	AntiMines  int       `json:"antiMines,omitempty"`  // how many of the mines are anti-mines, see AntiMine.go
+	Treasures  int       `json:"treasures,omitempty"`  // safe cells to hide a random treasure in, see Treasure.go
+	RNG        RNG       `json:"-"`                    // source of random draws; nil for math/rand
+}
+
+// maxGeneratorAttempts : how many layouts to try before giving up on the options
+const maxGeneratorAttempts = 1000
+
+// InitializeWithOptions : construct a new Board around the user's 'safe' Location, with the mine layout
+// satisfying the generator options
+func (b *Board) InitializeWithOptions(safespot Location, opts GeneratorOptions) error {
+	if nil == b {
+		return errors.New("called Initialize() on a nil board")
+	}
+	switch opts.Algorithm {
+	case AlgorithmUniform:
+	case AlgorithmWinmine:
+		return b.initializeWinmine(safespot, opts)
+	default:
+		return fmt.Errorf("unsupported generator algorithm %v", opts.Algorithm)
+	}
+
+	// keeping the safe spot's neighbors clear guarantees it scores zero, so the first click opens a region
+	excluded := map[Location]bool{safespot: true}
+	if opts.MinOpening > 0 {
+		if opts.MinOpening > b.rows*b.cols-b.mineCount {
+			return fmt.Errorf("an opening of %d cells can't fit on a %dx%d board with %d mines", opts.MinOpening, b.rows, b.cols, b.mineCount)
+		}
+		for _, l := range b.neighborLocations(safespot) {
+			excluded[l] = true
+		}
+	}
+
+	if opts.AntiMines < 0 || opts.AntiMines > b.mineCount {
+		return fmt.Errorf("can't make %d of %d mines anti-mines", opts.AntiMines, b.mineCount)
+	}
+
+	orbits, err := b.orbits(excluded, opts.Symmetry)
+	if err != nil {
+		return err
+	}
+	rng := opts.RNG
+	if nil == rng {
+		rng = globalRNG{}
+	}
+
+	for attempt := 0; attempt < maxGeneratorAttempts; attempt++ {
+		b.allocateCells()
+		b.safeRemaining = b.rows * b.cols
+
+		for _, orbit := range pickOrbits(rng, orbits, b.mineCount) {
+			for _, l := range orbit {
+				b.getCell(l).hasMine = true
+				b.mines = append(b.mines, l)
+				b.safeRemaining--
+			}
+		}
+		b.placeAntiMines(rng, opts.AntiMines)
+
+		if failed, ok := rng.(interface{ Err() error }); ok && nil != failed.Err() {
+			b.allocateCells()
+			return fmt.Errorf("generating from recorded draws: %s", failed.Err())
+		}
+
+		// once mines are placed, go ahead and calculate cell scores
+		initializeScores(b)
+
+		if opts.NoEights && b.hasEights() {
+			continue
+		}
+		if opts.MinOpening > 0 && b.openingSize(safespot) < opts.MinOpening {
+			continue
+		}
+		b.placeTreasures(rng, opts.Treasures, safespot)
+		b.initialized = true
+		b.checkAudit("Initialize")
+		return nil
+	}
+
+	return fmt.Errorf("could not generate a %dx%d board with %d mines satisfying %+v", b.rows, b.cols, b.mineCount, opts)
+}
+
+// orbits -- groups of cells that must all hold mines or all be empty under the symmetry, leaving out any group
+// containing an excluded cell. Each group has one or two cells
+func (b *Board) orbits(excluded map[Location]bool, symmetry Symmetry) ([][]Location, error) {
+	if symmetry < SymmetryNone || symmetry > SymmetryRotational {
+		return nil, fmt.Errorf("unsupported symmetry %v", symmetry)
+	}
+
+	retval := make([][]Location, 0, b.rows*b.cols)
+	for row := 0; row < b.rows; row++ {
+		for col := 0; col < b.cols; col++ {
+			l := Location{row, col}
+			image := l
+			switch symmetry {
+			case SymmetryHorizontal:
+				image = Location{b.rows - 1 - row, col}
+			case SymmetryVertical:
+				image = Location{row, b.cols - 1 - col}
+			case SymmetryRotational:
+				image = Location{b.rows - 1 - row, b.cols - 1 - col}
+			}
+
+			// visit each pair once, from its first cell in row-major order
+			if image.row < row || (image.row == row && image.col < col) {
+				continue
+			}
+			orbit := []Location{l}
+			if image != l {
+				orbit = append(orbit, image)
+			}
+
+			if excluded[l] || excluded[image] {
+				continue // can't place mine at user's safe starting cell
+			}
+			retval = append(retval, orbit)
+		}
+	}
+
+	singles, pairs := countOrbits(retval)
+	if !orbitsCanHold(singles, pairs, b.mineCount) {
+		return nil, fmt.Errorf("a %dx%d board can't hold %d mines with %v symmetry", b.rows, b.cols, b.mineCount, symmetry)
+	}
+	return retval, nil
+}
+
+// countOrbits -- number of single cell and two cell orbits
+func countOrbits(orbits [][]Location) (singles, pairs int) {
+	for _, orbit := range orbits {
+		if len(orbit) == 1 {
+			singles++
+		} else {
+			pairs++
+		}
+	}
+	return
+}
+
+// orbitsCanHold -- true if some choice of orbits adds up to exactly mines cells
+func orbitsCanHold(singles, pairs, mines int) bool {
+	// use as few single cells as the parity allows, then make up any shortfall of pairs with more singles
+	needSingles := mines % 2
+	if excess := mines - needSingles - 2*pairs; excess > 0 {
+		needSingles += excess
+	}
+	return needSingles <= singles
+}
+
+// pickOrbits -- choose a random set of orbits holding exactly mines cells, using as few single cell orbits as the
+// count allows. Caller checks feasibility
+func pickOrbits(rng RNG, orbits [][]Location, mines int) [][]Location {
+	singles, pairs := make([][]Location, 0), make([][]Location, 0)
+	for _, i := range perm(rng, len(orbits)) {
+		if len(orbits[i]) == 1 {
+			singles = append(singles, orbits[i])
+		} else {
+			pairs = append(pairs, orbits[i])
+		}
+	}
+
+	useSingles := mines % 2
+	if excess := mines - useSingles - 2*len(pairs); excess > 0 {
+		useSingles += excess
+	}
+	// with no symmetry everything is a single, so this is a plain shuffle-and-take
+	return append(singles[:useSingles:useSingles], pairs[:(mines-useSingles)/2]...)
+}
+
+// hasEights -- true if any safe cell scores 8
+func (b *Board) hasEights() bool {
+	for row := range b.cells {
+		for _, c := range b.cells[row] {
+			if !c.hasMine && c.score == 8 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// neighborLocations -- on-board locations adjacent to l, which itself may be off the board
+func (b *Board) neighborLocations(l Location) []Location {
+	retval := make([]Location, 0, 8)
+	if nil != b.topology {
+		for _, neighbor := range b.topology.Neighbors(b.rows, b.cols, l) {
+			if neighbor != l && b.ValidLocation(neighbor) {
+				retval = append(retval, neighbor)
+			}
+		}
+		return retval
+	}
+	for nrow := l.row - 1; nrow <= l.row+1; nrow++ {
+		for ncol := l.col - 1; ncol <= l.col+1; ncol++ {
+			neighbor := Location{nrow, ncol}
+			if neighbor != l && b.ValidLocation(neighbor) {
+				retval = append(retval, neighbor)
+			}
+		}
+	}
+	return retval
+}
+
+// openingSize -- number of cells a first click at l would reveal: the connected zero region plus its numbered
+// border. Board state is not changed
+func (b *Board) openingSize(l Location) int {
+	start := b.getCell(l)
+	if nil == start || start.hasMine {
+		return 0
+	}
+
+	seen := map[Location]bool{l: true}
+	queue := []Location{l}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if !b.opens(b.getCell(current)) {
+			continue // numbered cells are revealed but don't spread
+		}
+		for _, neighbor := range b.neighborLocations(current) {
+			if !seen[neighbor] {
+				seen[neighbor] = true
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+	return len(seen)
+}
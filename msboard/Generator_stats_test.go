@@ -0,0 +1,142 @@
+//go:build stats
+
+/*
+	Statistical tests for mine placement uniformity. These generate many thousands of boards, so they only run
+	with the stats build tag:
+
+		go test -tags stats ./msboard/
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// boardsPerSeed : generated layouts per seed; enough for ~50 expected mines per cell on an expert board
+const boardsPerSeed = 4000
+
+// chiSquared -- Pearson's statistic for observed counts against a uniform expectation
+func chiSquared(observed []float64, expected float64) float64 {
+	retval := 0.0
+	for _, o := range observed {
+		retval += (o - expected) * (o - expected) / expected
+	}
+	return retval
+}
+
+// chiSquaredCritical -- upper critical value of the chi-squared distribution with df degrees of freedom at
+// significance 0.001, via the Wilson-Hilferty approximation
+func chiSquaredCritical(df int) float64 {
+	const z = 3.090 // standard normal quantile for p = 0.999
+	k := float64(df)
+	return k * math.Pow(1-2/(9*k)+z*math.Sqrt(2/(9*k)), 3)
+}
+
+// generatedCounts -- per-cell (row-major) and per-row mine counts over many generated boards
+func generatedCounts(t *testing.T, seed int64, rows, cols, mines int, safe Location, opts GeneratorOptions) (grid, rowTotals []float64) {
+	rand.Seed(seed)
+	grid = make([]float64, rows*cols)
+	rowTotals = make([]float64, rows)
+
+	for i := 0; i < boardsPerSeed; i++ {
+		b := NewCustomBoard(rows, cols, mines)
+		if err := b.InitializeWithOptions(safe, opts); err != nil {
+			t.Fatalf("generation failed: %s", err)
+		}
+		for _, l := range b.mines {
+			grid[l.row*cols+l.col]++
+			rowTotals[l.row]++
+		}
+	}
+	return grid, rowTotals
+}
+
+// withoutCells -- counts with the given row-major indexes left out
+func withoutCells(grid []float64, skip ...int) []float64 {
+	retval := make([]float64, 0, len(grid))
+	for i, count := range grid {
+		skipped := false
+		for _, s := range skip {
+			skipped = skipped || i == s
+		}
+		if !skipped {
+			retval = append(retval, count)
+		}
+	}
+	return retval
+}
+
+// TestChiSquaredDetectsBias -- sanity check that the statistic and threshold catch a modest positional skew
+func TestChiSquaredDetectsBias(t *testing.T) {
+	observed := make([]float64, 100)
+	for i := range observed {
+		observed[i] = 50
+		if i < 10 {
+			observed[i] = 85 // early cells favored, as the old row-order scan did
+		}
+	}
+	if chiSquared(observed, 53.5) < chiSquaredCritical(len(observed)-1) {
+		t.Errorf("chi-squared test failed to detect a 70%% skew on the first tenth of the cells")
+	}
+}
+
+func TestGeneratorUniformity(t *testing.T) {
+	var cases = []struct {
+		difficulty string
+		safe       Location
+	}{
+		{"easy", Location{4, 4}},
+		{"medium", Location{0, 0}},
+		{"hard", Location{15, 7}},
+	}
+
+	for _, testcase := range cases {
+		params := boardDefinitionsDict()[testcase.difficulty]
+		for _, seed := range []int64{1995, 2024, 31337} {
+			grid, rowTotals := generatedCounts(t, seed, params.rows, params.cols, params.mineCount, testcase.safe, GeneratorOptions{})
+			cells := withoutCells(grid, testcase.safe.row*params.cols+testcase.safe.col)
+
+			// every cell but the safe spot should be equally likely to hold a mine
+			expected := float64(boardsPerSeed*params.mineCount) / float64(len(cells))
+			if stat, limit := chiSquared(cells, expected), chiSquaredCritical(len(cells)-1); stat > limit {
+				t.Errorf("%s seed %d: per-cell chi-squared %.1f exceeds %.1f", testcase.difficulty, seed, stat, limit)
+			}
+
+			// rows are compared after removing the safe spot's share, which targets row-order bias directly
+			rowExpected := make([]float64, len(rowTotals))
+			for row := range rowExpected {
+				safeCells := params.cols
+				if row == testcase.safe.row {
+					safeCells--
+				}
+				rowExpected[row] = expected * float64(safeCells)
+			}
+			stat := 0.0
+			for row, observed := range rowTotals {
+				stat += (observed - rowExpected[row]) * (observed - rowExpected[row]) / rowExpected[row]
+			}
+			if limit := chiSquaredCritical(len(rowTotals) - 1); stat > limit {
+				t.Errorf("%s seed %d: per-row chi-squared %.1f exceeds %.1f", testcase.difficulty, seed, stat, limit)
+			}
+		}
+	}
+}
+
+func TestSymmetricGeneratorUniformity(t *testing.T) {
+	// with rotational symmetry each pair of cells is one choice, so counts are compared over one half of the board
+	rows, cols, mines := 16, 16, 40
+	safe := Location{3, 5}
+	grid, _ := generatedCounts(t, 1995, rows, cols, mines, safe, GeneratorOptions{Symmetry: SymmetryRotational})
+
+	// the safe spot is in the top half, its mirror image in the bottom half
+	half := withoutCells(grid[:rows*cols/2], safe.row*cols+safe.col)
+	expected := float64(boardsPerSeed*mines) / float64(2*len(half))
+	if stat, limit := chiSquared(half, expected), chiSquaredCritical(len(half)-1); stat > limit {
+		t.Errorf("rotational generator chi-squared %.1f exceeds %.1f", stat, limit)
+	}
+}
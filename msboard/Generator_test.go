@@ -0,0 +1,112 @@
+/*
+	Test functions for mine layout generation
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGeneratorSymmetry(t *testing.T) {
+	rand.Seed(1995)
+
+	var cases = []struct {
+		rows, cols, mines int
+		symmetry          Symmetry
+		image             func(l Location, rows, cols int) Location
+	}{
+		{16, 16, 40, SymmetryHorizontal, func(l Location, rows, cols int) Location { return Location{rows - 1 - l.row, l.col} }},
+		{16, 16, 40, SymmetryVertical, func(l Location, rows, cols int) Location { return Location{l.row, cols - 1 - l.col} }},
+		{9, 9, 11, SymmetryRotational, func(l Location, rows, cols int) Location { return Location{rows - 1 - l.row, cols - 1 - l.col} }},
+		{9, 9, 21, SymmetryVertical, func(l Location, rows, cols int) Location { return Location{l.row, cols - 1 - l.col} }},
+	}
+
+	for _, testcase := range cases {
+		b := NewCustomBoard(testcase.rows, testcase.cols, testcase.mines)
+		safe := Location{2, 3}
+		if err := b.InitializeWithOptions(safe, GeneratorOptions{Symmetry: testcase.symmetry}); err != nil {
+			t.Errorf("%v generation failed: %s", testcase.symmetry, err)
+			continue
+		}
+
+		if len(b.Mines()) != testcase.mines || b.SafeRemaining() != testcase.rows*testcase.cols-testcase.mines {
+			t.Errorf("%v generation placed %d mines, wanted %d", testcase.symmetry, len(b.Mines()), testcase.mines)
+		}
+		if b.MineAt(safe) || b.MineAt(testcase.image(safe, testcase.rows, testcase.cols)) {
+			t.Errorf("%v generation placed a mine on the safe spot or its mirror image", testcase.symmetry)
+		}
+		for _, l := range b.Mines() {
+			if !b.MineAt(testcase.image(l, testcase.rows, testcase.cols)) {
+				t.Errorf("%v generation: mine at %v has no mirror image", testcase.symmetry, l)
+			}
+		}
+	}
+}
+
+func TestGeneratorInfeasible(t *testing.T) {
+	// an even sized board has no cells on the axis, so an odd mine count can't be symmetric
+	b := NewCustomBoard(8, 8, 11)
+	if err := b.InitializeWithOptions(Location{0, 0}, GeneratorOptions{Symmetry: SymmetryRotational}); err == nil {
+		t.Errorf("Odd mine count on an even board should not be rotationally symmetric")
+	}
+	if b.Initialized() {
+		t.Errorf("Failed generation left the board initialized")
+	}
+
+	if err := b.InitializeWithOptions(Location{0, 0}, GeneratorOptions{Symmetry: Symmetry(42)}); err == nil {
+		t.Errorf("Unknown symmetry should be rejected")
+	}
+}
+
+func TestGeneratorNoEights(t *testing.T) {
+	rand.Seed(1995)
+
+	// dense enough that eights turn up regularly without the constraint
+	for i := 0; i < 20; i++ {
+		b := NewCustomBoard(10, 10, 60)
+		if err := b.InitializeWithOptions(Location{5, 5}, GeneratorOptions{NoEights: true}); err != nil {
+			t.Fatalf("NoEights generation failed: %s", err)
+		}
+		if b.hasEights() {
+			t.Errorf("NoEights generation produced a cell scoring 8:\n%s", b.Layout())
+		}
+	}
+}
+
+func TestGeneratorMinOpening(t *testing.T) {
+	rand.Seed(1995)
+
+	for _, minOpening := range []int{1, 10, 30} {
+		b := NewBoard("easy")
+		safe := Location{4, 4}
+		if err := b.InitializeWithOptions(safe, GeneratorOptions{MinOpening: minOpening}); err != nil {
+			t.Errorf("MinOpening %d generation failed: %s", minOpening, err)
+			continue
+		}
+		if b.getCell(safe).score != 0 {
+			t.Errorf("MinOpening %d: safe spot scores %d, wanted a zero", minOpening, b.getCell(safe).score)
+		}
+
+		b.Click(safe)
+		revealed := 0
+		for _, row := range b.Snapshot().Cells {
+			for _, view := range row {
+				if view.State == CellRevealed {
+					revealed++
+				}
+			}
+		}
+		if revealed < minOpening {
+			t.Errorf("MinOpening %d: first click only revealed %d cells", minOpening, revealed)
+		}
+	}
+
+	b := NewBoard("easy")
+	if err := b.InitializeWithOptions(Location{0, 0}, GeneratorOptions{MinOpening: 72}); err == nil {
+		t.Errorf("Opening larger than the safe cell count should be rejected")
+	}
+}
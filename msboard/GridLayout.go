@@ -0,0 +1,126 @@
+/*
+
+	GridLayout.go - the layout of a board drawn as lines of text, worked out from its row and column labels so the
+	header and the grid line up on boards of any size
+
+	Each line of the grid is a margin holding the row label, then the cells CellWidth characters apart. A cell is
+	drawn as a sign (blank unless a score is negative) and its glyph, one column wide unless GlyphWidth says
+	otherwise; the header puts each column's label over the glyph. Right-to-left grids move the row labels after the last cell, leaving a narrow margin for
+	scroll markers.
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+import (
+	"unicode/utf8"
+)
+
+// Smallest widths, those of the classic boards, so boards with short labels keep their familiar look
+const (
+	minLabelWidth = 2
+	minCellWidth  = 3
+	rtlMargin     = 2 // room for a scroll marker before the cells when the row labels are on the right
+)
+
+// GridLayout : widths for drawing a board as text
+type GridLayout struct {
+	LabelWidth  int  // width of the widest row label
+	CellWidth   int  // characters from one cell's glyph to the next
+	RightToLeft bool // row labels after the cells instead of before them
+	GlyphWidth  int  // terminal columns each glyph takes, 0 for 1; emoji take 2, which the layout must allow for
+}
+
+// NewGridLayout -- the layout fitting the widest row and column labels of a board in a coordinate scheme; a column
+// label needs a space after it to stay clear of the next
+func NewGridLayout(rows, cols int, codec LocationCodec) GridLayout {
+	retval := GridLayout{LabelWidth: minLabelWidth, CellWidth: minCellWidth}
+	for row := 0; row < rows; row++ {
+		if width := utf8.RuneCountInString(codec.RowLabel(row, rows)); width > retval.LabelWidth {
+			retval.LabelWidth = width
+		}
+	}
+	for col := 0; col < cols; col++ {
+		if width := utf8.RuneCountInString(codec.ColLabel(col, cols)) + 1; width > retval.CellWidth {
+			retval.CellWidth = width
+		}
+	}
+	return retval
+}
+
+// Margin -- characters before the first cell's glyph on every line
+func (g GridLayout) Margin() int {
+	if g.RightToLeft {
+		return rtlMargin
+	}
+	// the label, a space and the first cell's sign
+	return g.LabelWidth + 2
+}
+
+// Column -- 0-based character offset of the glyph of the i-th cell drawn on a line
+func (g GridLayout) Column(i int) int {
+	return g.Margin() + i*g.CellWidth
+}
+
+// Header -- a line of column labels, each starting over its cell's glyph
+func (g GridLayout) Header(labels []string) string {
+	return string(g.AppendHeader(nil, labels))
+}
+
+// AppendHeader -- append the Header line to dst, for drawing into a reused buffer
+func (g GridLayout) AppendHeader(dst []byte, labels []string) []byte {
+	dst = appendSpaces(dst, g.Margin())
+	for i, label := range labels {
+		dst = append(dst, label...)
+		if i != len(labels)-1 {
+			dst = appendSpaces(dst, g.CellWidth-utf8.RuneCountInString(label))
+		}
+	}
+	return dst
+}
+
+// Row -- a line of the grid: the row label and the cells, each given as its sign and glyph. Glyphs may carry
+// terminal escapes, so cells are never measured
+func (g GridLayout) Row(label string, cells []string) string {
+	return string(g.AppendRow(nil, label, cells))
+}
+
+// AppendRow -- append the Row line to dst, for drawing into a reused buffer
+func (g GridLayout) AppendRow(dst []byte, label string, cells []string) []byte {
+	if g.RightToLeft {
+		dst = appendSpaces(dst, g.Margin()-1)
+	} else {
+		dst = append(appendSpaces(dst, g.LabelWidth-utf8.RuneCountInString(label)), label...)
+		dst = append(dst, ' ')
+	}
+	// each cell is its sign and its glyph
+	gap := g.CellWidth - 1 - g.glyphWidth()
+	for i, c := range cells {
+		if i != 0 {
+			dst = appendSpaces(dst, gap)
+		}
+		dst = append(dst, c...)
+	}
+	if g.RightToLeft {
+		dst = append(append(dst, ' '), label...)
+	}
+	return dst
+}
+
+// glyphWidth -- terminal columns each glyph takes
+func (g GridLayout) glyphWidth() int {
+	if g.GlyphWidth < 1 {
+		return 1
+	}
+	return g.GlyphWidth
+}
+
+// appendSpaces -- append n spaces to dst, none for n below 1
+func appendSpaces(dst []byte, n int) []byte {
+	for ; n > 0; n-- {
+		dst = append(dst, ' ')
+	}
+	return dst
+}
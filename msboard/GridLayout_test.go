@@ -0,0 +1,76 @@
+/*
+	Test functions for laying out boards as text
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewGridLayout(t *testing.T) {
+	var cases = []struct {
+		rows, cols int
+		codec      LocationCodec
+		want       GridLayout
+	}{
+		{9, 9, LetterNumberCodec{}, GridLayout{LabelWidth: 2, CellWidth: 3}},
+		{30, 16, LetterNumberCodec{}, GridLayout{LabelWidth: 2, CellWidth: 3}},
+		{100, 5, LetterNumberCodec{}, GridLayout{LabelWidth: 3, CellWidth: 3}},
+		{5, 703, LetterNumberCodec{}, GridLayout{LabelWidth: 2, CellWidth: 4}}, // AAA
+		{5, 100, NumberNumberCodec{}, GridLayout{LabelWidth: 2, CellWidth: 4}},
+		{1000, 8, ChessCodec{}, GridLayout{LabelWidth: 4, CellWidth: 3}},
+	}
+
+	for _, testcase := range cases {
+		if got := NewGridLayout(testcase.rows, testcase.cols, testcase.codec); got != testcase.want {
+			t.Errorf("NewGridLayout(%d, %d, %T) wanted %+v got %+v", testcase.rows, testcase.cols, testcase.codec, testcase.want, got)
+		}
+	}
+}
+
+// TestGridLayoutAligned -- every column label starts over its cells' glyphs, whatever the widths
+func TestGridLayoutAligned(t *testing.T) {
+	for _, layout := range []GridLayout{{2, 3, false, 0}, {3, 3, false, 0}, {2, 4, false, 0}, {4, 5, true, 0}} {
+		labels := []string{"A", "BB", "C"}
+		header := layout.Header(labels)
+		row := layout.Row("7", []string{" x", "-y", " z"})
+		for i, label := range labels {
+			at := layout.Column(i)
+			if !strings.HasPrefix(header[at:], label) {
+				t.Errorf("%+v header %q doesn't have %q at %d", layout, header, label, at)
+			}
+			if glyph := "xyz"[i]; row[at] != glyph {
+				t.Errorf("%+v row %q doesn't have %c at %d", layout, row, glyph, at)
+			}
+		}
+	}
+}
+
+func TestGridLayoutRows(t *testing.T) {
+	layout := GridLayout{LabelWidth: 3, CellWidth: 3}
+	if got, want := layout.Row("12", []string{" 1", "-2"}), " 12  1 -2"; got != want {
+		t.Errorf("row wanted %q got %q", want, got)
+	}
+	if got, want := layout.Header([]string{"A", "B"}), "     A  B"; got != want {
+		t.Errorf("header wanted %q got %q", want, got)
+	}
+
+	// right-to-left: the label follows the cells
+	layout.RightToLeft = true
+	if got, want := layout.Row("12", []string{" 1", "-2"}), "  1 -2 12"; got != want {
+		t.Errorf("right-to-left row wanted %q got %q", want, got)
+	}
+	if got, want := layout.Header([]string{"A", "B"}), "  A  B"; got != want {
+		t.Errorf("right-to-left header wanted %q got %q", want, got)
+	}
+
+	// double width glyphs fill the space between cells, keeping them under their labels
+	layout = GridLayout{LabelWidth: 2, CellWidth: 4, GlyphWidth: 2}
+	if got, want := layout.Row("1", []string{" ⬜", " 💣"}), " 1  ⬜  💣"; got != want {
+		t.Errorf("double width row wanted %q got %q", want, got)
+	}
+}
@@ -0,0 +1,299 @@
+/*
+
+	Layout.go - explicit board layouts for editors, puzzles and tests
+
+	A layout string describes every cell of a position, one character per cell, with rows separated by '/'
+	(or newlines):
+
+		.      hidden safe cell
+		*      hidden mine
+		_ 1-8  revealed safe cell, showing its score; the score must match the mines around it
+		F      flagged mine
+		f      flagged safe cell (a wrong flag)
+
+	and for the anti-mine variant:
+
+		x      hidden anti-mine
+		X      flagged anti-mine
+		-1-8   revealed safe cell with a negative score, the only cells taking two characters
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NewLayoutBoard : allocate an initialized, mine-free board of any shape for editors to populate
+func NewLayoutBoard(rows, cols int) *Board {
+	retval := NewCustomBoard(rows, cols, 0)
+	if nil == retval {
+		return nil
+	}
+
+	retval.allocateCells()
+	retval.safeRemaining = rows * cols
+	retval.initialized = true
+	return retval
+}
+
+// allocateCells -- create fresh hidden, mine-free cells for the whole grid
+func (b *Board) allocateCells() {
+	b.cells = make([][]*cell, b.rows)
+	for row := range b.cells {
+		b.cells[row] = make([]*cell, b.cols)
+		for col := range b.cells[row] {
+			b.cells[row][col] = new(cell)
+			b.cells[row][col].location = NewLocation(row, col)
+		}
+	}
+	b.mines = nil
+}
+
+// editableCell -- look up a cell for the editing functions
+func (b *Board) editableCell(l Location) (*cell, error) {
+	if nil == b || !b.initialized {
+		return nil, errors.New("board must be initialized before editing")
+	}
+	c := b.getCell(l)
+	if nil == c {
+		return nil, fmt.Errorf("location %v is not on the board", l)
+	}
+	return c, nil
+}
+
+// SetMine -- place or remove a mine, keeping scores and counters consistent. Revealed cells can't hold mines.
+// Removing an anti-mine removes it altogether
+func (b *Board) SetMine(l Location, mine bool) error {
+	c, err := b.editableCell(l)
+	if err != nil {
+		return err
+	}
+	if c.hasMine == mine {
+		return nil
+	}
+	if mine && c.revealed {
+		return fmt.Errorf("can't place a mine on revealed cell %v", l)
+	}
+
+	c.hasMine, c.anti = mine, false
+	if mine {
+		b.mines = append(b.mines, l)
+		b.mineCount++
+		b.safeRemaining--
+	} else {
+		for i := range b.mines {
+			if b.mines[i] == l {
+				b.mines = append(b.mines[:i], b.mines[i+1:]...)
+				break
+			}
+		}
+		b.mineCount--
+		b.safeRemaining++
+	}
+
+	initializeScores(b)
+	b.checkAudit("SetMine")
+	return nil
+}
+
+// SetRevealed -- reveal or hide a single safe cell, without any flood fill. Mines can't be revealed
+func (b *Board) SetRevealed(l Location, revealed bool) error {
+	c, err := b.editableCell(l)
+	if err != nil {
+		return err
+	}
+	if c.revealed == revealed {
+		return nil
+	}
+	if c.hasMine {
+		return fmt.Errorf("can't reveal mine at %v", l)
+	}
+
+	c.revealed, c.flagged = revealed, false
+	if revealed {
+		b.safeRemaining--
+	} else {
+		b.safeRemaining++
+	}
+	b.checkAudit("SetRevealed")
+	return nil
+}
+
+// SetFlagged -- place or remove a flag on a hidden cell
+func (b *Board) SetFlagged(l Location, flagged bool) error {
+	c, err := b.editableCell(l)
+	if err != nil {
+		return err
+	}
+	if flagged && c.revealed {
+		return fmt.Errorf("can't flag revealed cell %v", l)
+	}
+
+	c.flagged = flagged
+	b.checkAudit("SetFlagged")
+	return nil
+}
+
+// Layout -- encode the complete position as a layout string
+func (b *Board) Layout() string {
+	if nil == b || !b.initialized {
+		return ""
+	}
+
+	var sb strings.Builder
+	for row := range b.cells {
+		if row != 0 {
+			sb.WriteByte('/')
+		}
+		for _, c := range b.cells[row] {
+			if c.revealed && c.score < 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(c.layoutRune())
+		}
+	}
+	return sb.String()
+}
+
+// MineLayout -- layout string of the mines alone, with every cell hidden and unflagged: the board as it was
+// generated
+func (b *Board) MineLayout() string {
+	if nil == b || !b.initialized {
+		return ""
+	}
+
+	var sb strings.Builder
+	for row := range b.cells {
+		if row != 0 {
+			sb.WriteByte('/')
+		}
+		for _, c := range b.cells[row] {
+			if c.hasMine && c.anti {
+				sb.WriteByte('x')
+			} else if c.hasMine {
+				sb.WriteByte('*')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+	}
+	return sb.String()
+}
+
+// layoutRune -- layout string character for a cell
+func (c *cell) layoutRune() rune {
+	switch {
+	case c.revealed:
+		return scoreRune(c.score)
+	case c.flagged && c.hasMine && c.anti:
+		return 'X'
+	case c.flagged && c.hasMine:
+		return 'F'
+	case c.flagged:
+		return 'f'
+	case c.hasMine && c.anti:
+		return 'x'
+	case c.hasMine:
+		return '*'
+	}
+	return '.'
+}
+
+// ParseLayout : build an initialized board from a layout string
+func ParseLayout(layout string) (*Board, error) {
+	return ParseTopologyLayout(layout, GridTopology)
+}
+
+// ParseTopologyLayout -- build an initialized board on a registered topology from a layout string, checking its
+// revealed scores against that topology's neighborhoods
+func ParseTopologyLayout(layout, topology string) (*Board, error) {
+	lines := strings.FieldsFunc(layout, func(r rune) bool {
+		return r == '/' || r == '\n' || r == '\r'
+	})
+	rows := make([][]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), "")
+		if line != "" {
+			rows = append(rows, layoutCells(line))
+		}
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("empty layout")
+	}
+
+	b := NewLayoutBoard(len(rows), len(rows[0]))
+	if err := b.SetTopology(topology); err != nil {
+		return nil, err
+	}
+	type revealedScore struct {
+		l     Location
+		score int
+	}
+	revealed := make([]revealedScore, 0)
+
+	for row := range rows {
+		if len(rows[row]) != b.cols {
+			return nil, fmt.Errorf("layout row %d has %d cells, expected %d", row+1, len(rows[row]), b.cols)
+		}
+		for col, text := range rows[row] {
+			l := Location{row, col}
+			cell := []rune(text)
+			r, negative := cell[len(cell)-1], len(cell) > 1
+			switch {
+			case negative && (r < '1' || r > '8'):
+				return nil, fmt.Errorf("unrecognized layout cell %q at row %d column %d", text, row+1, col+1)
+			case r == '.':
+			case r == '*' || r == 'F':
+				b.SetMine(l, true)
+				b.SetFlagged(l, r == 'F')
+			case r == 'x' || r == 'X':
+				b.SetAntiMine(l, true)
+				b.SetFlagged(l, r == 'X')
+			case r == 'f':
+				b.SetFlagged(l, true)
+			case r == '_' || (r >= '1' && r <= '8'):
+				score := 0
+				if r != '_' {
+					score = int(r - '0')
+				}
+				if negative {
+					score = -score
+				}
+				revealed = append(revealed, revealedScore{l, score})
+			default:
+				return nil, fmt.Errorf("unrecognized layout character %q at row %d column %d", r, row+1, col+1)
+			}
+		}
+	}
+
+	// reveal after all mines are placed, so the written scores can be checked
+	for _, rs := range revealed {
+		b.SetRevealed(rs.l, true)
+		if got := b.getCell(rs.l).score; got != rs.score {
+			return nil, fmt.Errorf("layout cell %v shows %d but has %d neighboring mines", rs.l, rs.score, got)
+		}
+	}
+
+	return b, nil
+}
+
+// layoutCells -- split a layout row into its cells: one character each, except a '-' joins the character after it
+func layoutCells(line string) []string {
+	runes := []rune(line)
+	var retval []string
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '-' && i+1 < len(runes) {
+			retval = append(retval, string(runes[i:i+2]))
+			i++
+			continue
+		}
+		retval = append(retval, string(runes[i]))
+	}
+	return retval
+}
@@ -0,0 +1,87 @@
+/*
+	Test functions for explicit board layouts
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestLayoutRoundTrip(t *testing.T) {
+	layout := "*1_../331.f/F*1.."
+
+	b, err := ParseLayout(layout)
+	if err != nil {
+		t.Fatalf("ParseLayout(%q) failed: %s", layout, err)
+	}
+	if b.Rows() != 3 || b.Cols() != 5 || b.MineCount() != 3 {
+		t.Errorf("ParseLayout(%q) gave %dx%d with %d mines", layout, b.Rows(), b.Cols(), b.MineCount())
+	}
+	if got := b.SafeRemaining(); got != 15-3-6 {
+		t.Errorf("ParseLayout(%q) safe remaining wanted %d got %d", layout, 15-3-6, got)
+	}
+	if got := b.Layout(); got != layout {
+		t.Errorf("Layout() round trip wanted %q got %q", layout, got)
+	}
+	if got := b.MineLayout(); got != "*..../...../**..." {
+		t.Errorf("MineLayout() wanted %q got %q", "*..../...../**...", got)
+	}
+
+	// generated boards survive a round trip too
+	rand.Seed(1995)
+	generated := NewBoard("medium")
+	generated.Initialize(Location{8, 8})
+	generated.Click(Location{8, 8})
+	copied, err := ParseLayout(generated.Layout())
+	if err != nil || copied.Layout() != generated.Layout() {
+		t.Errorf("Generated board layout did not round trip: %s", err)
+	}
+}
+
+func TestParseLayoutErrors(t *testing.T) {
+	var cases = []string{
+		"",
+		"..*/..",  // ragged rows
+		"..?/...", // unknown character
+		"*2./...", // score doesn't match mines
+		".../.1.", // score with no mines
+	}
+
+	for _, layout := range cases {
+		if _, err := ParseLayout(layout); err == nil {
+			t.Errorf("ParseLayout(%q) should have failed", layout)
+		}
+	}
+}
+
+func TestLayoutEditing(t *testing.T) {
+	b := NewLayoutBoard(3, 3)
+	center := Location{1, 1}
+
+	if err := b.SetMine(center, true); err != nil {
+		t.Fatalf("SetMine() failed: %s", err)
+	}
+	if b.getCell(Location{0, 0}).score != 1 || b.MineCount() != 1 || b.SafeRemaining() != 8 {
+		t.Errorf("SetMine() did not update scores and counters")
+	}
+	if err := b.SetRevealed(center, true); err == nil {
+		t.Errorf("SetRevealed() revealed a mine")
+	}
+
+	b.SetRevealed(Location{0, 0}, true)
+	if err := b.SetFlagged(Location{0, 0}, true); err == nil {
+		t.Errorf("SetFlagged() flagged a revealed cell")
+	}
+	if err := b.SetMine(Location{0, 0}, true); err == nil {
+		t.Errorf("SetMine() placed a mine on a revealed cell")
+	}
+
+	b.SetMine(center, false)
+	if b.getCell(Location{0, 0}).score != 0 || b.MineCount() != 0 || len(b.Mines()) != 0 || b.SafeRemaining() != 8 {
+		t.Errorf("Removing mine did not update scores and counters")
+	}
+}
@@ -0,0 +1,136 @@
+/*
+
+	Move.go - player actions that can be applied to a Board, and Board.Apply, the one entry point for playing them so
+	front ends, replays and bots don't each map their own commands onto board calls
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// MoveType : kind of player action
+type MoveType int
+
+// Supported player actions
+const (
+	MoveReveal    MoveType = iota // reveal ("step on") a cell
+	MoveFlag                      // toggle the flag on a hidden cell
+	MoveChord                     // reveal around a revealed cell, as far as the board's Rules chord it
+	MoveSurrender                 // give up: the game is lost and the mines shown; the location is ignored
+)
+
+var moveTypeNames = [...]string{"reveal", "flag", "chord", "surrender"}
+
+// String -- human readable move type name
+func (t MoveType) String() string {
+	if t < 0 || int(t) >= len(moveTypeNames) {
+		return "unknown"
+	}
+	return moveTypeNames[t]
+}
+
+// Move : a single player action at a board location
+type Move struct {
+	Type     MoveType `json:"type"`
+	Location Location `json:"location"`
+}
+
+// Apply -- play a move, returning the cells it revealed, in the order Click gives them, and the status after it.
+// A first reveal on an uninitialized board goes through FirstClick, so only a reveal lays the mines out and the
+// first cell revealed is always safe; there is nothing to flag, chord or give up before it. Once the game is won
+// or lost it's over, and every move is refused. Moves that change nothing, like a flag on a revealed cell, aren't
+// errors
+func (b *Board) Apply(m Move) ([]Location, Status, error) {
+	if nil == b {
+		return nil, StatusUninitialized, errors.New("called Apply() on a nil board")
+	}
+	switch status := b.Status(); status {
+	case StatusWon, StatusLost:
+		return nil, status, fmt.Errorf("can't %v, the game is over", m.Type)
+	}
+	if m.Type != MoveSurrender && !b.ValidLocation(m.Location) {
+		return nil, b.Status(), fmt.Errorf("invalid board location %v", m.Location)
+	}
+
+	if !b.initialized {
+		if m.Type != MoveReveal {
+			return nil, b.Status(), fmt.Errorf("can't %v before the first reveal", m.Type)
+		}
+		revealed, err := b.FirstClick(m.Location)
+		return revealed, b.Status(), err
+	}
+
+	var retval []Location
+	switch m.Type {
+	case MoveReveal:
+		retval = b.Click(m.Location)
+	case MoveFlag:
+		b.ToggleFlag(m.Location)
+	case MoveChord:
+		if c := b.getCell(m.Location); c.revealed && !c.hasMine {
+			retval = b.chord(m.Location)
+			b.checkAudit("Chord")
+		}
+	case MoveSurrender:
+		retval = b.surrender()
+	default:
+		return nil, b.Status(), fmt.Errorf("unsupported move type %v", m.Type)
+	}
+	return retval, b.Status(), nil
+}
+
+// surrender -- lose the game, revealing every hidden mine so the player sees where they were. Returns the mines
+// revealed
+func (b *Board) surrender() []Location {
+	var retval []Location
+	for _, l := range b.mines {
+		if c := b.getCell(l); !c.revealed {
+			c.revealed, c.flagged = true, false
+			retval = append(retval, l)
+		}
+	}
+	b.explosionOccured = true
+	b.checkAudit("Surrender")
+	return retval
+}
+
+// MarshalText -- encode a move type by name
+func (t MoveType) MarshalText() ([]byte, error) {
+	if t < 0 || int(t) >= len(moveTypeNames) {
+		return nil, fmt.Errorf("unknown move type %d", int(t))
+	}
+	return []byte(moveTypeNames[t]), nil
+}
+
+// UnmarshalText -- decode a move type name
+func (t *MoveType) UnmarshalText(text []byte) error {
+	for i, name := range moveTypeNames {
+		if name == string(text) {
+			*t = MoveType(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown move type %q", text)
+}
+
+// MarshalJSON -- encode a Location as a [row, col] pair
+func (l Location) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]int{l.row, l.col})
+}
+
+// UnmarshalJSON -- decode a [row, col] pair
+func (l *Location) UnmarshalJSON(data []byte) error {
+	var pair [2]int
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	*l = Location{pair[0], pair[1]}
+	return nil
+}
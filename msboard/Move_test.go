@@ -0,0 +1,106 @@
+package msboard
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMoveJSON(t *testing.T) {
+	moves := []Move{
+		{MoveReveal, NewLocation(3, 4)},
+		{MoveFlag, NewLocation(0, 12)},
+	}
+
+	data, err := json.Marshal(moves)
+	if err != nil {
+		t.Fatalf("Marshal failed: %s", err)
+	}
+	want := `[{"type":"reveal","location":[3,4]},{"type":"flag","location":[0,12]}]`
+	if string(data) != want {
+		t.Errorf("Marshal wanted %s got %s", want, data)
+	}
+
+	var got []Move
+	if err = json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %s", err)
+	}
+	if len(got) != len(moves) || got[0] != moves[0] || got[1] != moves[1] {
+		t.Errorf("round trip wanted %v got %v", moves, got)
+	}
+
+	if err = json.Unmarshal([]byte(`{"type":"defuse","location":[0,0]}`), new(Move)); nil == err {
+		t.Errorf("Unmarshal accepted an unknown move type")
+	}
+}
+
+func TestApply(t *testing.T) {
+	b := NewBoard("easy")
+	if _, _, err := b.Apply(Move{MoveChord, NewLocation(0, 0)}); nil == err {
+		t.Errorf("Apply chorded an uninitialized board")
+	}
+	if _, _, err := b.Apply(Move{MoveReveal, NewLocation(9, 0)}); nil == err {
+		t.Errorf("Apply revealed a cell off the board")
+	}
+	revealed, status, err := b.Apply(Move{MoveReveal, NewLocation(4, 4)})
+	if err != nil || len(revealed) == 0 || revealed[0] != NewLocation(4, 4) || status == StatusUninitialized {
+		t.Errorf("first reveal wanted E5 revealed got %v, %v, %v", revealed, status, err)
+	}
+
+	// chords follow the board's rules
+	b, _ = ParseLayout("F1./11./...")
+	if revealed, _, _ := b.Apply(Move{MoveChord, NewLocation(0, 1)}); len(revealed) != 0 {
+		t.Errorf("classic rules chorded %v", revealed)
+	}
+	b.SetRules(ChordRules{})
+	if revealed, status, _ := b.Apply(Move{MoveChord, NewLocation(0, 1)}); len(revealed) == 0 || status != StatusWon {
+		t.Errorf("chording B1 wanted the rest of the board revealed got %v, %v", revealed, status)
+	}
+
+	// giving up loses, showing every mine, and the location doesn't matter
+	b, _ = ParseLayout("*.*/.../F..")
+	revealed, status, err = b.Apply(Move{Type: MoveSurrender, Location: NewLocation(-1, -1)})
+	if err != nil || len(revealed) != 3 || status != StatusLost || b.Snapshot().Flags != 0 {
+		t.Errorf("surrender wanted 3 mines shown and a loss got %v, %v, %v", revealed, status, err)
+	}
+	if a := b.Audit(); !a.OK() {
+		t.Errorf("surrender left the board inconsistent: %v", a)
+	}
+}
+
+func TestApplyAfterGameOver(t *testing.T) {
+	// once the game is over nothing more can be played on the board, whichever way it ended
+	won, _ := ParseLayout("*.")
+	won.Apply(Move{MoveReveal, NewLocation(0, 1)})
+	lost, _ := ParseLayout("*.")
+	lost.Apply(Move{MoveReveal, NewLocation(0, 0)})
+	if won.Status() != StatusWon || lost.Status() != StatusLost {
+		t.Fatalf("wanted a won and a lost game got %v, %v", won.Status(), lost.Status())
+	}
+	for _, b := range []*Board{won, lost} {
+		status := b.Status()
+		for _, m := range []Move{{MoveReveal, NewLocation(0, 0)}, {MoveFlag, NewLocation(0, 0)},
+			{MoveChord, NewLocation(0, 1)}, {MoveSurrender, NewLocation(0, 0)}} {
+			before := b.Snapshot()
+			revealed, got, err := b.Apply(m)
+			if nil == err || len(revealed) != 0 || got != status || b.Snapshot().Cells[0][0] != before.Cells[0][0] {
+				t.Errorf("%v after the game was %v wanted refused got %v, %v, %v", m.Type, status, revealed, got,
+					err)
+			}
+		}
+	}
+}
+
+func TestFlagBeforeReveal(t *testing.T) {
+	// only the first reveal lays the mines out, so it's safe whatever was tried before it
+	for i := 0; i < 500; i++ {
+		b := NewBoard("easy")
+		l := NewLocation(i%9, i/9%9)
+		if _, _, err := b.Apply(Move{MoveFlag, l}); nil == err || b.Initialized() {
+			t.Fatalf("game %d: flag before the first reveal wanted refused got %v, initialized %v", i, err,
+				b.Initialized())
+		}
+		if _, status, err := b.Apply(Move{MoveReveal, l}); err != nil || status == StatusLost {
+			t.Fatalf("game %d: reveal of %v after a flag got %v, %v", i, l, status, err)
+		}
+	}
+}
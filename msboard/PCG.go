@@ -0,0 +1,107 @@
+/*
+
+	PCG.go - a small random number generator implemented here rather than borrowed from math/rand, so a seed lays out
+	the same boards on every platform and Go release
+
+	math/rand makes no promise that its shared generator follows a seed, and since Go 1.24 rand.Seed does nothing
+	at all, so a seed shared between players only means something if the generator behind it is pinned down. Each
+	generator a seed can be drawn from has a version; a seed and version pair always gives the same draws.
+
+	PCG32 is M.E. O'Neill's permuted congruential generator, the XSH RR variant: a 64 bit linear congruential state
+	with each output permuted by a shift and a rotation. See https://www.pcg-random.org
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+)
+
+// RNGVersion : which generator a seed is drawn from
+type RNGVersion int
+
+// Seeded generators, by version. New versions are only ever added, so a recorded seed and version keep their
+// meaning
+const (
+	RNGMathRand RNGVersion = iota // math/rand's seeded source, what seeds meant before versions
+	RNGPCG32                      // PCG32, implemented in this package
+
+	CurrentRNGVersion = RNGPCG32 // the version new games are seeded with
+)
+
+// rngVersionNames : lower case name of each version
+var rngVersionNames = []string{"mathrand", "pcg32"}
+
+// String -- version name
+func (v RNGVersion) String() string {
+	if v < 0 || int(v) >= len(rngVersionNames) {
+		return "unknown"
+	}
+	return rngVersionNames[v]
+}
+
+// NewSeededRNG -- a generator of a given version drawing from a seed
+func NewSeededRNG(seed int64, version RNGVersion) (RNG, error) {
+	switch version {
+	case RNGMathRand:
+		return rand.New(rand.NewSource(seed)), nil
+	case RNGPCG32:
+		return NewPCG(seed), nil
+	}
+	return nil, fmt.Errorf("unsupported random number generator version %d", version)
+}
+
+// PCG32 constants: the multiplier of the reference implementation, and the stream every seed is drawn on
+const (
+	pcgMultiplier = 6364136223846793005
+	pcgStream     = 1995
+)
+
+// PCG : the PCG32 generator
+type PCG struct {
+	state     uint64
+	increment uint64 // always odd, selecting the stream
+}
+
+// NewPCG -- a PCG32 generator seeded with seed
+func NewPCG(seed int64) *PCG {
+	return newPCG(uint64(seed), pcgStream)
+}
+
+// newPCG -- a PCG32 generator on a stream, seeded as the reference implementation's pcg32_srandom is
+func newPCG(seed, stream uint64) *PCG {
+	retval := &PCG{increment: stream<<1 | 1}
+	retval.Uint32()
+	retval.state += seed
+	retval.Uint32()
+	return retval
+}
+
+// Uint32 -- the next 32 random bits
+func (p *PCG) Uint32() uint32 {
+	old := p.state
+	p.state = old*pcgMultiplier + p.increment
+	xorShifted := uint32(((old >> 18) ^ old) >> 27)
+	return bits.RotateLeft32(xorShifted, -int(old>>59))
+}
+
+// Intn -- a number in [0,n), every one equally likely: draws falling in the part of the 32 bit range that doesn't
+// divide evenly by n are thrown away. n must be positive and fit in 32 bits
+func (p *PCG) Intn(n int) int {
+	if n <= 0 || uint64(n) > math.MaxUint32 {
+		panic(fmt.Sprintf("PCG.Intn(%d) out of range", n))
+	}
+	bound := uint32(n)
+	threshold := -bound % bound
+	for {
+		if r := p.Uint32(); r >= threshold {
+			return int(r % bound)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+/*
+	Test functions for the seeded random number generators
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"testing"
+)
+
+func TestPCGReference(t *testing.T) {
+	// the first outputs of the reference implementation's demo, pcg32_srandom(42, 54)
+	want := []uint32{0xa15c02b7, 0x7b47f409, 0xba1d3330, 0x83d2f293, 0xbfa4784b, 0xcbed606e}
+	p := newPCG(42, 54)
+	for i, w := range want {
+		if got := p.Uint32(); got != w {
+			t.Errorf("PCG32 output %d wanted %#x got %#x", i+1, w, got)
+		}
+	}
+}
+
+func TestPCGIntn(t *testing.T) {
+	p := NewPCG(1995)
+	counts := make([]int, 6)
+	for i := 0; i < 6000; i++ {
+		n := p.Intn(6)
+		if n < 0 || n >= 6 {
+			t.Fatalf("Intn(6) returned %d", n)
+		}
+		counts[n]++
+	}
+	for face, count := range counts {
+		if count < 850 || count > 1150 {
+			t.Errorf("Intn(6) drew %d %d times of 6000, counts %v", face, count, counts)
+		}
+	}
+
+	for _, n := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Intn(%d) wanted a panic", n)
+				}
+			}()
+			p.Intn(n)
+		}()
+	}
+}
+
+// TestPCGLayout -- a seed lays out the same board on every platform and Go release; if this changes, shared seeds
+// have stopped meaning what they did, and the generator needs a new version instead
+func TestPCGLayout(t *testing.T) {
+	b := NewBoard("easy")
+	if err := b.InitializeWithOptions(Location{4, 4}, GeneratorOptions{RNG: NewPCG(1995)}); err != nil {
+		t.Fatalf("generation failed: %s", err)
+	}
+	want := "........./*......../.*.**..*./......*../..*....../..*...*../........./........./..*......"
+	if got := b.MineLayout(); got != want {
+		t.Errorf("seed 1995 wanted layout %s got %s", want, got)
+	}
+}
+
+func TestNewSeededRNG(t *testing.T) {
+	for _, version := range []RNGVersion{RNGMathRand, RNGPCG32} {
+		a, err := NewSeededRNG(7, version)
+		if err != nil {
+			t.Fatalf("NewSeededRNG(%v) failed: %s", version, err)
+		}
+		b, _ := NewSeededRNG(7, version)
+		for i := 0; i < 20; i++ {
+			if x, y := a.Intn(1000), b.Intn(1000); x != y {
+				t.Errorf("%v seed 7 draw %d differs: %d and %d", version, i+1, x, y)
+			}
+		}
+	}
+	if _, err := NewSeededRNG(7, RNGVersion(99)); nil == err {
+		t.Errorf("NewSeededRNG accepted version 99")
+	}
+	if CurrentRNGVersion.String() != "pcg32" || RNGVersion(99).String() != "unknown" {
+		t.Errorf("version names wrong: %v, %v", CurrentRNGVersion, RNGVersion(99))
+	}
+}
@@ -0,0 +1,186 @@
+/*
+	Property tests for Board invariants: random boards take random sequences of reveals and flags, and after every
+	move the board must still agree with itself
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// gameScript : a random board and the moves played on it
+type gameScript struct {
+	Seed        int64
+	Rows, Cols  int
+	Mines       int
+	Rule        PropagationRule
+	FloodFlags  bool
+	Moves       []Move
+	PlayOnAfter bool // keep applying moves after the game ends
+}
+
+// Generate -- quick.Generator for game scripts of up to 12x12 cells
+func (gameScript) Generate(r *rand.Rand, size int) reflect.Value {
+	s := gameScript{
+		Seed:        r.Int63(),
+		Rows:        1 + r.Intn(12),
+		Cols:        2 + r.Intn(11),
+		Rule:        PropagationRule(r.Intn(2)),
+		FloodFlags:  r.Intn(2) == 0,
+		PlayOnAfter: r.Intn(2) == 0,
+	}
+	s.Mines = 1 + r.Intn(s.Rows*s.Cols-1)
+
+	moves := 1 + r.Intn(3*size+1)
+	for i := 0; i < moves; i++ {
+		m := Move{Type: MoveReveal, Location: Location{r.Intn(s.Rows), r.Intn(s.Cols)}}
+		if i > 0 && r.Intn(3) == 0 {
+			m.Type = MoveFlag
+		}
+		s.Moves = append(s.Moves, m)
+	}
+	return reflect.ValueOf(s)
+}
+
+// play -- run the script, checking every invariant after each move
+func (s gameScript) play() error {
+	rand.Seed(s.Seed)
+	b := NewCustomBoard(s.Rows, s.Cols, s.Mines)
+	b.SetPropagation(s.Rule)
+	b.SetFloodClearsFlags(s.FloodFlags)
+
+	for i, m := range s.Moves {
+		if i > 0 && !s.PlayOnAfter && b.Status() != StatusPlaying {
+			break
+		}
+
+		before := b.Snapshot()
+		var revealed []Location
+		switch {
+		case i == 0:
+			var err error
+			if revealed, err = b.FirstClick(m.Location); err != nil {
+				return fmt.Errorf("first click %v: %s", m.Location, err)
+			}
+		case m.Type == MoveReveal:
+			revealed = b.Click(m.Location)
+		default:
+			b.ToggleFlag(m.Location)
+		}
+
+		if err := checkInvariants(b, before, m, revealed); err != nil {
+			return fmt.Errorf("move %d %v: %s", i+1, m, err)
+		}
+	}
+	return nil
+}
+
+// checkInvariants -- everything that must hold after move m took the board from before to its current state
+func checkInvariants(b *Board, before Snapshot, m Move, revealed []Location) error {
+	if a := b.Audit(); !a.OK() {
+		return fmt.Errorf("%v", a)
+	}
+
+	s := b.Snapshot()
+	counts := map[CellState]int{}
+	for row := range s.Cells {
+		for col, view := range s.Cells[row] {
+			l := Location{row, col}
+			counts[view.State]++
+
+			if view.State == CellRevealed {
+				mines := 0
+				for _, n := range b.neighborLocations(l) {
+					if b.MineAt(n) {
+						mines++
+					}
+				}
+				if view.Score != mines {
+					return fmt.Errorf("%v shows %d but has %d neighboring mines", l, view.Score, mines)
+				}
+			}
+
+			// flags only disappear when toggled, or when swept up by a cascade on boards set to do so
+			was := before.Cells[row][col].State
+			if was == CellFlagged && view.State != CellFlagged {
+				toggled := m.Type == MoveFlag && l == m.Location
+				swept := b.floodFlags && m.Type == MoveReveal && view.State == CellRevealed
+				if !toggled && !swept {
+					return fmt.Errorf("flag at %v was removed by %v", l, m)
+				}
+			}
+			if was != CellHidden && was != CellFlagged && view != before.Cells[row][col] {
+				return fmt.Errorf("revealed cell %v changed from %v to %v", l, before.Cells[row][col], view)
+			}
+		}
+	}
+	if total := counts[CellHidden] + counts[CellFlagged] + counts[CellRevealed] + counts[CellMine]; total != s.Rows*s.Cols {
+		return fmt.Errorf("cell states sum to %d on a %dx%d board", total, s.Rows, s.Cols)
+	}
+	if counts[CellFlagged] != s.Flags {
+		return fmt.Errorf("snapshot counts %d flags but has %d flagged cells", s.Flags, counts[CellFlagged])
+	}
+	if (counts[CellMine] > 0) != (s.Status == StatusLost) {
+		return fmt.Errorf("%d mines revealed with status %v", counts[CellMine], s.Status)
+	}
+
+	// won exactly when no safe cells remain hidden and nothing exploded
+	if (s.Status == StatusWon) != (s.SafeRemaining == 0 && !b.MineHit()) {
+		return fmt.Errorf("status %v with %d safe cells remaining", s.Status, s.SafeRemaining)
+	}
+	if s.SafeRemaining != s.Rows*s.Cols-s.Mines-counts[CellRevealed] {
+		return fmt.Errorf("%d safe remaining but %d of %d safe cells revealed", s.SafeRemaining,
+			counts[CellRevealed], s.Rows*s.Cols-s.Mines)
+	}
+
+	// a reveal uncovers at most the clicked cell as a mine; flood fill never reaches one
+	for i, l := range revealed {
+		if i == 0 && l != m.Location {
+			return fmt.Errorf("reveal started at %v, not the clicked cell", l)
+		}
+		if i > 0 && b.MineAt(l) {
+			return fmt.Errorf("flood fill revealed the mine at %v", l)
+		}
+		if view, _ := s.Cell(l); view.State != CellRevealed && view.State != CellMine {
+			return fmt.Errorf("%v reported revealed but shows %v", l, view.State)
+		}
+	}
+	if newly := counts[CellRevealed] + counts[CellMine] - countRevealed(before); newly != len(revealed) {
+		return fmt.Errorf("%d cells newly revealed but %d reported", newly, len(revealed))
+	}
+
+	return nil
+}
+
+// countRevealed -- revealed cells in a snapshot, mines included
+func countRevealed(s Snapshot) int {
+	retval := 0
+	for row := range s.Cells {
+		for _, view := range s.Cells[row] {
+			if view.State == CellRevealed || view.State == CellMine {
+				retval++
+			}
+		}
+	}
+	return retval
+}
+
+func TestBoardProperties(t *testing.T) {
+	check := func(s gameScript) bool {
+		if err := s.play(); err != nil {
+			t.Logf("seed %d, %dx%d with %d mines, rule %v: %s", s.Seed, s.Rows, s.Cols, s.Mines, s.Rule, err)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(check, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+}
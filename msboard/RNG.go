@@ -0,0 +1,101 @@
+/*
+
+	RNG.go - the random draws behind board generation, which can be recorded and played back so a layout can be
+	regenerated exactly even if the generator's use of randomness changes
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// RNG : source of the random draws made while generating a board
+type RNG interface {
+	// Intn returns a number in [0,n)
+	Intn(n int) int
+}
+
+// globalRNG : math/rand's shared generator, the default for boards laid out without one of their own; unlike
+// NewSeededRNG's generators its draws can't be relied on to repeat for a seed
+type globalRNG struct{}
+
+// Intn -- draw from math/rand
+func (globalRNG) Intn(n int) int {
+	return rand.Intn(n)
+}
+
+// RecordingRNG : passes draws through from another RNG, keeping every result so the generation can be played back
+// with a PlaybackRNG
+type RecordingRNG struct {
+	Source RNG   // where draws come from; nil for math/rand
+	Draws  []int // every draw made so far, in order
+}
+
+// Intn -- draw from the source and record the result
+func (r *RecordingRNG) Intn(n int) int {
+	source := r.Source
+	if nil == source {
+		source = globalRNG{}
+	}
+	retval := source.Intn(n)
+	r.Draws = append(r.Draws, retval)
+	return retval
+}
+
+// PlaybackRNG : returns recorded draws in order. Generation that asks for more draws than were recorded, or for a
+// draw the recording can't satisfy, gets zeros and leaves an error for Err
+type PlaybackRNG struct {
+	Draws []int
+	next  int
+	err   error
+}
+
+// NewPlaybackRNG -- play back recorded draws from the start
+func NewPlaybackRNG(draws []int) *PlaybackRNG {
+	return &PlaybackRNG{Draws: draws}
+}
+
+// Intn -- the next recorded draw
+func (p *PlaybackRNG) Intn(n int) int {
+	if nil != p.err {
+		return 0
+	}
+	if p.next >= len(p.Draws) {
+		p.err = fmt.Errorf("recording ran out after %d draws", len(p.Draws))
+		return 0
+	}
+	retval := p.Draws[p.next]
+	if retval < 0 || retval >= n {
+		p.err = fmt.Errorf("recorded draw %d is %d, outside [0,%d)", p.next+1, retval, n)
+		return 0
+	}
+	p.next++
+	return retval
+}
+
+// Err -- why playback failed, nil if every draw was satisfied
+func (p *PlaybackRNG) Err() error {
+	return p.err
+}
+
+// Remaining -- recorded draws not yet used; a complete playback uses them all
+func (p *PlaybackRNG) Remaining() int {
+	return len(p.Draws) - p.next
+}
+
+// perm -- a random permutation of [0,n), drawing exactly as math/rand's Perm does so the default generator lays
+// out the same boards as before
+func perm(rng RNG, n int) []int {
+	retval := make([]int, n)
+	for i := 0; i < n; i++ {
+		j := rng.Intn(i + 1)
+		retval[i] = retval[j]
+		retval[j] = i
+	}
+	return retval
+}
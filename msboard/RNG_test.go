@@ -0,0 +1,65 @@
+/*
+	Test functions for recorded and played back generator draws
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRecordedDraws(t *testing.T) {
+	// recording changes nothing about the layout math/rand produces
+	rand.Seed(1995)
+	plain := NewBoard("medium")
+	plain.Initialize(Location{8, 8})
+
+	rand.Seed(1995)
+	recorder := &RecordingRNG{}
+	recorded := NewBoard("medium")
+	if err := recorded.InitializeWithOptions(Location{8, 8}, GeneratorOptions{RNG: recorder}); err != nil {
+		t.Fatalf("recorded generation failed: %s", err)
+	}
+	if recorded.MineLayout() != plain.MineLayout() {
+		t.Errorf("recording changed the layout:\n%s\n%s", plain.MineLayout(), recorded.MineLayout())
+	}
+	if len(recorder.Draws) == 0 {
+		t.Fatalf("no draws recorded")
+	}
+
+	// and playing the draws back, whatever math/rand is doing now, gives the same layout again
+	rand.Seed(7)
+	played := NewBoard("medium")
+	playback := NewPlaybackRNG(recorder.Draws)
+	if err := played.InitializeWithOptions(Location{8, 8}, GeneratorOptions{RNG: playback}); err != nil {
+		t.Fatalf("playback failed: %s", err)
+	}
+	if played.MineLayout() != plain.MineLayout() || playback.Remaining() != 0 || playback.Err() != nil {
+		t.Errorf("playback wanted the recorded layout using every draw, got %d left, err %v:\n%s", playback.Remaining(),
+			playback.Err(), played.MineLayout())
+	}
+}
+
+func TestPlaybackErrors(t *testing.T) {
+	var cases = []struct {
+		name  string
+		draws []int
+	}{
+		{"short", []int{0, 1, 2}},
+		{"out of range", []int{0, 5}},
+	}
+
+	for _, testcase := range cases {
+		b := NewBoard("easy")
+		playback := NewPlaybackRNG(testcase.draws)
+		if err := b.InitializeWithOptions(Location{0, 0}, GeneratorOptions{RNG: playback}); err == nil {
+			t.Errorf("%s: generating from bad draws should fail", testcase.name)
+		}
+		if b.Initialized() || playback.Err() == nil {
+			t.Errorf("%s: failed playback should leave the board uninitialized and report why", testcase.name)
+		}
+	}
+}
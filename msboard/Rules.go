@@ -0,0 +1,149 @@
+/*
+
+	Rules.go - the rules of play a Board consults on every move, so a variant can change what a click does without
+	Click growing a flag for each one
+
+	ClassicRules is the default and covers the board settings that already exist: the propagation rule, fog of war
+	and anti-mines. A variant embeds ClassicRules and overrides the decisions it changes, as ChordRules does.
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+import (
+	"fmt"
+)
+
+// Rules : the decisions of play a variant can change. Methods get the board, so rules can look at anything on it
+// and needn't keep state of their own; they must not change it
+type Rules interface {
+	Name() string                          // short name for menus and messages
+	Clickable(b *Board, l Location) bool   // a click on the hidden, unflagged cell at l is allowed
+	Opens(b *Board, l Location) bool       // revealing the safe cell at l cascades to its neighbors, none of them mines
+	Spreads(b *Board, l Location) bool     // a cascade reveals the hidden neighbor at l; flags are left to the board
+	Chord(b *Board, l Location) []Location // cells a click on the revealed cell at l reveals, nil for none
+	Status(b *Board) Status                // won, lost or still playing, for a board whose mines are placed
+}
+
+// ClassicRules : the standard game. Clicks reach any cell in range of the fog, zeros with no mine around them
+// open their neighbors as the board's PropagationRule allows, clicks on revealed cells do nothing, and the game is
+// lost with the first mine and won with the last safe cell
+type ClassicRules struct{}
+
+// ChordRules : the classic rules plus chording: a click on a revealed score with as many flags around it as its
+// score reveals its other hidden neighbors, exploding any mine a wrong flag left uncovered
+type ChordRules struct {
+	ClassicRules
+}
+
+// compile time checks that the rules can be set on a board
+var (
+	_ Rules = ClassicRules{}
+	_ Rules = ChordRules{}
+)
+
+// namedRules : the rules players can choose by name, the default first
+var namedRules = []Rules{ClassicRules{}, ChordRules{}}
+
+// Name -- "classic"
+func (ClassicRules) Name() string {
+	return "classic"
+}
+
+// Clickable -- true for cells within range of the fog, see InRange
+func (ClassicRules) Clickable(b *Board, l Location) bool {
+	return b.InRange(l)
+}
+
+// Opens -- true for zeros with no mine of either kind around them
+func (ClassicRules) Opens(b *Board, l Location) bool {
+	return b.opens(b.getCell(l))
+}
+
+// Spreads -- true for every neighbor under PropagateStandard, only zeros under PropagateZerosOnly
+func (ClassicRules) Spreads(b *Board, l Location) bool {
+	return b.propagation != PropagateZerosOnly || b.getCell(l).score == 0
+}
+
+// Chord -- nothing, a revealed cell doesn't respond to clicks
+func (ClassicRules) Chord(b *Board, l Location) []Location {
+	return nil
+}
+
+// Status -- lost once a mine has exploded, won once no safe cells are left hidden
+func (ClassicRules) Status(b *Board) Status {
+	switch {
+	case b.explosionOccured:
+		return StatusLost
+	case b.safeRemaining == 0:
+		return StatusWon
+	}
+	return StatusPlaying
+}
+
+// Name -- "chord"
+func (ChordRules) Name() string {
+	return "chord"
+}
+
+// Chord -- the hidden, unflagged neighbors of a revealed score whose flags match it, in reading order; nothing for
+// zeros and scores missing flags. Negative scores never chord, since flags don't tell mines from anti-mines
+func (ChordRules) Chord(b *Board, l Location) []Location {
+	c := b.getCell(l)
+	if nil == c || !c.revealed || c.hasMine || c.score <= 0 {
+		return nil
+	}
+
+	flags := 0
+	var retval []Location
+	for _, n := range b.getNeighborCells(l) {
+		switch {
+		case n.flagged:
+			flags++
+		case !n.revealed:
+			retval = append(retval, n.location)
+		}
+	}
+	if flags != c.score {
+		return nil
+	}
+	return retval
+}
+
+// SetRules -- choose the rules the board is played by; nil restores ClassicRules
+func (b *Board) SetRules(rules Rules) {
+	b.rules = rules
+}
+
+// Rules -- the rules the board is played by
+func (b *Board) Rules() Rules {
+	if nil == b.rules {
+		return ClassicRules{}
+	}
+	return b.rules
+}
+
+// LookupRules -- the rules with a name, for flags, save files and replays that name them; the empty name is
+// ClassicRules
+func LookupRules(name string) (Rules, error) {
+	if name == "" {
+		return ClassicRules{}, nil
+	}
+	for _, rules := range namedRules {
+		if rules.Name() == name {
+			return rules, nil
+		}
+	}
+	return nil, fmt.Errorf("no rules of play named %q", name)
+}
+
+// RulesNames -- names of the rules LookupRules knows, the default first
+func RulesNames() []string {
+	retval := make([]string, len(namedRules))
+	for i, rules := range namedRules {
+		retval[i] = rules.Name()
+	}
+	return retval
+}
@@ -0,0 +1,94 @@
+/*
+	Test functions for the rules of play
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"testing"
+)
+
+// walledRules : classic rules with the first column out of reach
+type walledRules struct {
+	ClassicRules
+}
+
+func (walledRules) Clickable(b *Board, l Location) bool {
+	return l.Col() > 0
+}
+
+func TestClassicRules(t *testing.T) {
+	b, err := ParseLayout("F1./11./...")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+	if name := b.Rules().Name(); name != "classic" {
+		t.Errorf("default rules wanted classic got %q", name)
+	}
+	if revealed := b.Click(NewLocation(0, 1)); len(revealed) != 0 {
+		t.Errorf("Click on a revealed cell under the classic rules wanted nothing got %v", revealed)
+	}
+
+	b.SetRules(walledRules{})
+	if revealed := b.Click(NewLocation(2, 0)); len(revealed) != 0 {
+		t.Errorf("Click on an unreachable cell wanted nothing got %v", revealed)
+	}
+	if revealed := b.Click(NewLocation(2, 2)); len(revealed) == 0 || b.Status() != StatusWon {
+		t.Errorf("Click on C3 wanted a cascade clearing the board got %v, %v", revealed, b.Status())
+	}
+
+	b.SetRules(nil)
+	if _, classic := b.Rules().(ClassicRules); !classic {
+		t.Errorf("SetRules(nil) wanted the classic rules back got %T", b.Rules())
+	}
+}
+
+func TestChordRules(t *testing.T) {
+	b, err := ParseLayout("F1./11./...")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+	b.SetRules(ChordRules{})
+	if revealed := b.Click(NewLocation(0, 1)); len(revealed) == 0 || b.Status() != StatusWon {
+		t.Errorf("chording B1 wanted the rest of the board revealed got %v, %v", revealed, b.Status())
+	}
+
+	// a wrong flag uncovers the mine
+	b, err = ParseLayout("*1./f1./...")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+	b.SetRules(ChordRules{})
+	if b.Click(NewLocation(0, 1)); b.Status() != StatusLost {
+		t.Errorf("chording past a wrong flag wanted a loss got %v", b.Status())
+	}
+
+	// too few flags, no chord
+	b, err = ParseLayout("*1./11./...")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+	b.SetRules(ChordRules{})
+	if revealed := b.Click(NewLocation(0, 1)); len(revealed) != 0 {
+		t.Errorf("chording without flags wanted nothing got %v", revealed)
+	}
+}
+
+func TestLookupRules(t *testing.T) {
+	for _, name := range append(RulesNames(), "") {
+		rules, err := LookupRules(name)
+		if err != nil {
+			t.Errorf("LookupRules(%q) failed: %s", name, err)
+		} else if name != "" && rules.Name() != name {
+			t.Errorf("LookupRules(%q) returned %q", name, rules.Name())
+		}
+	}
+	if rules, _ := LookupRules(""); rules.Name() != "classic" {
+		t.Errorf("LookupRules of no name wanted classic got %q", rules.Name())
+	}
+	if _, err := LookupRules("moving-mines"); nil == err {
+		t.Errorf("LookupRules accepted unknown rules")
+	}
+}
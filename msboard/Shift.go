@@ -0,0 +1,105 @@
+/*
+
+	Shift.go - moving mines: part of the minefield relocates while a game is played
+
+	Only mines out of sight move, and only to cells out of sight, so every revealed number stays true and nothing
+	the player has deduced from them goes wrong. Out of sight means hidden, unflagged and not next to a revealed
+	cell, i.e. off the frontier.
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+// MineShift : one mine moved by ShiftMines, for front ends to hint that the minefield changed
+type MineShift struct {
+	From Location `json:"from"`
+	To   Location `json:"to"`
+}
+
+// ShiftMines -- move a fraction of the board's mines, rounded to the nearest whole mine and at least one, from
+// cells out of sight to other safe cells out of sight. Scores are recomputed only around the cells involved.
+// Returns the moves made, fewer than asked for if there aren't enough cells out of sight; nil rng draws from
+// math/rand
+func (b *Board) ShiftMines(fraction float64, rng RNG) []MineShift {
+	if nil == b || !b.initialized || b.Status() != StatusPlaying || fraction <= 0 {
+		return nil
+	}
+	if nil == rng {
+		rng = globalRNG{}
+	}
+
+	count := int(fraction*float64(b.mineCount) + 0.5)
+	if count < 1 {
+		count = 1
+	}
+
+	var sources, targets []*cell
+	for row := range b.cells {
+		for _, c := range b.cells[row] {
+			if !b.outOfSight(c) {
+				continue
+			}
+			if c.hasMine {
+				sources = append(sources, c)
+			} else {
+				targets = append(targets, c)
+			}
+		}
+	}
+
+	var retval []MineShift
+	for ; count > 0 && len(sources) > 0 && len(targets) > 0; count-- {
+		from, to := takeCell(&sources, rng), takeCell(&targets, rng)
+		b.moveMine(from, to)
+		retval = append(retval, MineShift{From: from.location, To: to.location})
+	}
+	if len(retval) > 0 {
+		b.checkAudit("ShiftMines")
+	}
+	return retval
+}
+
+// outOfSight -- true if the cell is hidden, unflagged and has no revealed neighbor
+func (b *Board) outOfSight(c *cell) bool {
+	if c.revealed || c.flagged {
+		return false
+	}
+	for _, n := range b.getNeighborCells(c.location) {
+		if n.revealed {
+			return false
+		}
+	}
+	return true
+}
+
+// takeCell -- remove a random cell from the list and return it
+func takeCell(cells *[]*cell, rng RNG) *cell {
+	list := *cells
+	i := rng.Intn(len(list))
+	retval := list[i]
+	list[i] = list[len(list)-1]
+	*cells = list[:len(list)-1]
+	return retval
+}
+
+// moveMine -- move the mine or anti-mine from one cell to another safe one, updating the mine list and the
+// neighbors' scores
+func (b *Board) moveMine(from, to *cell) {
+	value := from.value()
+	from.hasMine, to.hasMine = false, true
+	from.anti, to.anti = false, from.anti
+	for _, n := range b.getNeighborCells(from.location) {
+		n.score -= value
+	}
+	for _, n := range b.getNeighborCells(to.location) {
+		n.score += value
+	}
+	for i, l := range b.mines {
+		if l == from.location {
+			b.mines[i] = to.location
+			break
+		}
+	}
+}
@@ -0,0 +1,51 @@
+/*
+	Test functions for moving mines
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestShiftMines(t *testing.T) {
+	rand.Seed(1995)
+	b := NewBoard("hard")
+	if _, err := b.FirstClick(NewLocation(8, 15)); err != nil {
+		t.Fatalf("FirstClick failed: %s", err)
+	}
+	before := b.Snapshot()
+
+	shifts := b.ShiftMines(0.25, rand.New(rand.NewSource(1)))
+	// the mines next to the opening stay put, so there may not be 25 to move
+	if len(shifts) == 0 || len(shifts) > 25 {
+		t.Errorf("ShiftMines(0.25) of 99 mines wanted up to 25 moves got %d", len(shifts))
+	}
+	for _, s := range shifts {
+		if b.MineAt(s.From) || !b.MineAt(s.To) {
+			t.Errorf("ShiftMines move %v left mines at from %v to %v", s, b.MineAt(s.From), b.MineAt(s.To))
+		}
+	}
+	if a := b.Audit(); !a.OK() {
+		t.Errorf("ShiftMines left the board inconsistent: %s", a)
+	}
+	// nothing the player can see changes
+	if after := b.Snapshot(); !reflect.DeepEqual(before, after) {
+		t.Errorf("ShiftMines changed the visible board")
+	}
+
+	// a small fraction still moves a mine
+	if shifts := b.ShiftMines(0.001, nil); len(shifts) != 1 {
+		t.Errorf("ShiftMines(0.001) wanted 1 move got %d", len(shifts))
+	}
+
+	// with every cell in sight nothing can move
+	small, _ := ParseLayout("*1../1...")
+	if shifts := small.ShiftMines(1, nil); len(shifts) != 0 {
+		t.Errorf("ShiftMines wanted no moves on a board in plain sight, got %v", shifts)
+	}
+}
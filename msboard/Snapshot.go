@@ -0,0 +1,232 @@
+/*
+
+	Snapshot.go - player-visible copies of Board state
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CellState : player-visible state of a single cell
+type CellState int
+
+// Cell states as seen by the player; hidden mines are never exposed
+const (
+	CellHidden   CellState = iota // not yet revealed
+	CellFlagged                   // hidden, with a player flag
+	CellRevealed                  // revealed safe cell, Score is valid
+	CellMine                      // revealed mine
+)
+
+// CellView : player-visible view of one cell in a Snapshot
+type CellView struct {
+	State      CellState `json:"state"`
+	Score      int       `json:"score"`                // neighbor mine count, only meaningful when State == CellRevealed
+	OutOfRange bool      `json:"outOfRange,omitempty"` // hidden and lost in the fog, see Board.InRange
+}
+
+// Snapshot : immutable copy of the player-visible board state. Unrevealed mines are never included, so a
+// Snapshot is safe to hand to renderers, solvers and network clients
+type Snapshot struct {
+	Difficulty    string       `json:"difficulty"`
+	Rows          int          `json:"rows"`
+	Cols          int          `json:"cols"`
+	Mines         int          `json:"mines"`
+	AntiMines     int          `json:"antiMines,omitempty"` // how many of the mines are anti-mines; scores can be negative if any are
+	Topology      string       `json:"topology,omitempty"`  // board shape for Neighbors, empty for the square grid
+	Flags         int          `json:"flags"`
+	SafeRemaining int          `json:"safeRemaining"`
+	Status        Status       `json:"status"`
+	Cells         [][]CellView `json:"cells"`
+}
+
+// Cell -- return the view of a single cell, false if the location is outside the snapshot
+func (s Snapshot) Cell(l Location) (CellView, bool) {
+	if l.row < 0 || l.row >= s.Rows || l.col < 0 || l.col >= s.Cols || l.row >= len(s.Cells) {
+		return CellView{}, false
+	}
+	return s.Cells[l.row][l.col], true
+}
+
+// FlagsAround -- number of flagged cells next to l
+func (s Snapshot) FlagsAround(l Location) int {
+	retval := 0
+	for _, n := range s.Neighbors(l) {
+		if v, _ := s.Cell(n); v.State == CellFlagged {
+			retval++
+		}
+	}
+	return retval
+}
+
+// view : player-visible state for a cell
+func (c *cell) view() CellView {
+	switch {
+	case nil == c:
+		return CellView{}
+	case c.revealed && c.hasMine:
+		return CellView{State: CellMine}
+	case c.revealed:
+		return CellView{State: CellRevealed, Score: c.score}
+	case c.flagged:
+		return CellView{State: CellFlagged}
+	}
+	return CellView{State: CellHidden}
+}
+
+// Snapshot -- capture the current player-visible board state. Uninitialized boards report every cell hidden
+func (b *Board) Snapshot() Snapshot {
+	if nil == b {
+		return Snapshot{}
+	}
+
+	retval := Snapshot{
+		Difficulty:    b.difficulty,
+		Rows:          b.rows,
+		Cols:          b.cols,
+		Mines:         b.mineCount,
+		AntiMines:     b.AntiMineCount(),
+		SafeRemaining: b.SafeRemaining(),
+		Status:        b.Status(),
+		Cells:         make([][]CellView, b.rows),
+	}
+	if nil != b.topology {
+		retval.Topology = b.topology.Name
+	}
+
+	var reach [][]bool
+	if b.fog > 0 && b.initialized {
+		reach = b.Reachable()
+	}
+	for row := range retval.Cells {
+		retval.Cells[row] = make([]CellView, b.cols)
+		if !b.initialized {
+			continue
+		}
+		for col := range retval.Cells[row] {
+			v := b.cells[row][col].view()
+			switch {
+			case v.State == CellFlagged:
+				retval.Flags++
+			case v.State == CellHidden && nil != reach:
+				v.OutOfRange = !reach[row][col]
+			}
+			retval.Cells[row][col] = v
+		}
+	}
+
+	return retval
+}
+
+// Restore -- put the board back to an earlier snapshot of itself: cells are revealed, flagged or hidden as the
+// snapshot shows them, and a revealed mine means the game is lost again. The mines stay where they are, so the
+// snapshot must agree with them; if it doesn't, such as one taken of another board or before mines moved, the board
+// is left as it was and an error returned. Treasures already collected stay collected
+func (b *Board) Restore(s Snapshot) error {
+	if nil == b || !b.initialized {
+		return errors.New("called Restore() on an uninitialized board")
+	}
+	if s.Rows != b.rows || s.Cols != b.cols || len(s.Cells) != b.rows {
+		return fmt.Errorf("a %dx%d snapshot can't be restored on a %dx%d board", s.Rows, s.Cols, b.rows, b.cols)
+	}
+
+	for row := range s.Cells {
+		if len(s.Cells[row]) != b.cols {
+			return fmt.Errorf("snapshot row %d has %d cells, expected %d", row+1, len(s.Cells[row]), b.cols)
+		}
+		for col, v := range s.Cells[row] {
+			c := b.cells[row][col]
+			if (v.State == CellMine && !c.hasMine) || (v.State == CellRevealed && (c.hasMine || v.Score != c.score)) {
+				return fmt.Errorf("the snapshot doesn't match the board's mines at row %d column %d", row+1, col+1)
+			}
+		}
+	}
+
+	b.explosionOccured = false
+	b.safeRemaining = b.rows*b.cols - b.mineCount
+	for row := range s.Cells {
+		for col, v := range s.Cells[row] {
+			c := b.cells[row][col]
+			c.revealed = v.State == CellRevealed || v.State == CellMine
+			c.flagged = v.State == CellFlagged
+			if v.State == CellMine {
+				b.explosionOccured = true
+			} else if c.revealed {
+				b.safeRemaining--
+			}
+		}
+	}
+	b.checkAudit("Restore")
+	return nil
+}
+
+// CellChange : a single cell whose visible state differs between two snapshots
+type CellChange struct {
+	Location Location
+	From     CellView
+	To       CellView
+}
+
+// SnapshotDelta : everything that changed between two snapshots of the same board
+type SnapshotDelta struct {
+	Cells      []CellChange // changed cells in row-major order
+	FromStatus Status
+	ToStatus   Status
+	Reshaped   bool // board dimensions differ, consumers should redraw from scratch
+}
+
+// StatusChanged -- true if the game status transitioned between the snapshots
+func (d SnapshotDelta) StatusChanged() bool {
+	return d.FromStatus != d.ToStatus
+}
+
+// Empty -- true if nothing visible changed between the snapshots
+func (d SnapshotDelta) Empty() bool {
+	return len(d.Cells) == 0 && !d.StatusChanged() && !d.Reshaped
+}
+
+// SnapshotDiff -- compare two snapshots and report the changed cells and status transition from a to b. If the
+// snapshots have different shapes, every cell of b is reported and Reshaped is set
+func SnapshotDiff(a, b Snapshot) SnapshotDelta {
+	retval := SnapshotDelta{
+		FromStatus: a.Status,
+		ToStatus:   b.Status,
+		Reshaped:   a.Rows != b.Rows || a.Cols != b.Cols,
+	}
+
+	for row := range b.Cells {
+		for col, to := range b.Cells[row] {
+			loc := Location{row, col}
+			from, _ := a.Cell(loc)
+			if from != to || retval.Reshaped {
+				retval.Cells = append(retval.Cells, CellChange{loc, from, to})
+			}
+		}
+	}
+
+	return retval
+}
+
+// Rune -- console character for a cell view, matching the runes used by ConsoleRender, plus ~ for hidden cells
+// out of range in the fog. Negative scores get the digit of their size; renderers draw the sign in front
+func (v CellView) Rune() rune {
+	switch v.State {
+	case CellHidden:
+		if v.OutOfRange {
+			return '~'
+		}
+	case CellFlagged:
+		return '+'
+	case CellMine:
+		return '*'
+	case CellRevealed:
+		return scoreRune(v.Score)
+	}
+	return '.'
+}
@@ -0,0 +1,120 @@
+/*
+
+	SnapshotEncoder.go - JSON encoding of the player-visible board straight from board state, for servers that send
+	a position after every move and can't afford a Snapshot's allocations each time
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// snapshotBuffers : reusable output buffers for WriteSnapshotJSON
+var snapshotBuffers = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, 4096)
+		return &buf
+	},
+}
+
+// WriteSnapshotJSON -- write the board's Snapshot as JSON, byte for byte what json.NewEncoder(w).Encode(
+// b.Snapshot()) writes, without building the Snapshot. The output goes to w in a single Write
+func (b *Board) WriteSnapshotJSON(w io.Writer) error {
+	bufp := snapshotBuffers.Get().(*[]byte)
+	buf := b.AppendSnapshotJSON((*bufp)[:0])
+	_, err := w.Write(buf)
+
+	*bufp = buf
+	snapshotBuffers.Put(bufp)
+	return err
+}
+
+// AppendSnapshotJSON -- append the board's Snapshot as JSON, followed by a newline, to buf
+func (b *Board) AppendSnapshotJSON(buf []byte) []byte {
+	if nil == b {
+		empty, _ := json.Marshal(Snapshot{})
+		return append(append(buf, empty...), '\n')
+	}
+
+	flags := 0
+	if b.initialized {
+		for row := range b.cells {
+			for _, c := range b.cells[row] {
+				if !c.revealed && c.flagged {
+					flags++
+				}
+			}
+		}
+	}
+
+	buf = append(buf, `{"difficulty":`...)
+	buf = appendJSONString(buf, b.difficulty)
+	buf = appendJSONInt(buf, `,"rows":`, b.rows)
+	buf = appendJSONInt(buf, `,"cols":`, b.cols)
+	buf = appendJSONInt(buf, `,"mines":`, b.mineCount)
+	if anti := b.AntiMineCount(); anti > 0 {
+		buf = appendJSONInt(buf, `,"antiMines":`, anti)
+	}
+	if nil != b.topology {
+		buf = appendJSONString(append(buf, `,"topology":`...), b.topology.Name)
+	}
+	buf = appendJSONInt(buf, `,"flags":`, flags)
+	buf = appendJSONInt(buf, `,"safeRemaining":`, b.SafeRemaining())
+	buf = appendJSONInt(buf, `,"status":`, int(b.Status()))
+
+	var reach [][]bool
+	if b.fog > 0 && b.initialized {
+		reach = b.Reachable()
+	}
+	buf = append(buf, `,"cells":[`...)
+	for row := 0; row < b.rows; row++ {
+		if row > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, '[')
+		for col := 0; col < b.cols; col++ {
+			if col > 0 {
+				buf = append(buf, ',')
+			}
+			var view CellView
+			if b.initialized {
+				view = b.cells[row][col].view()
+			}
+			buf = appendJSONInt(buf, `{"state":`, int(view.State))
+			buf = appendJSONInt(buf, `,"score":`, view.Score)
+			if nil != reach && view.State == CellHidden && !reach[row][col] {
+				buf = append(buf, `,"outOfRange":true`...)
+			}
+			buf = append(buf, '}')
+		}
+		buf = append(buf, ']')
+	}
+
+	return append(buf, "]}\n"...)
+}
+
+// appendJSONInt -- append a literal, typically a key, followed by an integer
+func appendJSONInt(buf []byte, prefix string, n int) []byte {
+	return strconv.AppendInt(append(buf, prefix...), int64(n), 10)
+}
+
+// appendJSONString -- append s as a JSON string. Plain strings, which every difficulty name is, are copied as
+// they are; anything needing escapes goes through encoding/json so the output always matches it
+func appendJSONString(buf []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c >= 0x7f || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+			quoted, _ := json.Marshal(s)
+			return append(buf, quoted...)
+		}
+	}
+	buf = append(buf, '"')
+	buf = append(buf, s...)
+	return append(buf, '"')
+}
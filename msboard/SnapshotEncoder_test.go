@@ -0,0 +1,77 @@
+/*
+	Test functions for the streaming snapshot encoder, and benchmarks comparing it with encoding a Snapshot
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+)
+
+func TestWriteSnapshotJSON(t *testing.T) {
+	var nilBoard *Board
+	flagged, _ := ParseLayout("F*./f2./...")
+	rand.Seed(1995)
+	played := NewBoard("medium")
+	played.FirstClick(NewLocation(8, 8))
+	played.ToggleFlag(NewLocation(0, 0))
+	lost, _ := ParseLayout("*../.../...")
+	lost.Click(NewLocation(0, 0))
+	odd := NewCustomBoard(2, 3, 1)
+	odd.difficulty = `<"tricky">`
+	fogged, _ := ParseLayout("_...*/_..../_....")
+	anti, _ := ParseLayout("x-1./-1-1.")
+	fogged.SetFog(1)
+
+	for _, b := range []*Board{nilBoard, NewBoard("easy"), flagged, played, lost, odd, fogged, anti} {
+		var want, got bytes.Buffer
+		json.NewEncoder(&want).Encode(b.Snapshot())
+		if err := b.WriteSnapshotJSON(&got); err != nil {
+			t.Fatalf("WriteSnapshotJSON failed: %s", err)
+		}
+		if got.String() != want.String() {
+			t.Errorf("WriteSnapshotJSON wanted\n%s got\n%s", want.String(), got.String())
+		}
+
+		var decoded Snapshot
+		if err := json.Unmarshal(got.Bytes(), &decoded); err != nil {
+			t.Errorf("WriteSnapshotJSON output doesn't decode: %s", err)
+		}
+	}
+}
+
+// benchmarkBoard -- a hard board part way through a game
+func benchmarkBoard(bm *testing.B) *Board {
+	rand.Seed(1995)
+	b := NewBoard("hard")
+	if _, err := b.FirstClick(NewLocation(8, 15)); err != nil {
+		bm.Fatal(err)
+	}
+	b.ToggleFlag(NewLocation(0, 0))
+	return b
+}
+
+func BenchmarkSnapshotEncode(bm *testing.B) {
+	b := benchmarkBoard(bm)
+	enc := json.NewEncoder(ioutil.Discard)
+	bm.ReportAllocs()
+	bm.ResetTimer()
+	for i := 0; i < bm.N; i++ {
+		enc.Encode(b.Snapshot())
+	}
+}
+
+func BenchmarkWriteSnapshotJSON(bm *testing.B) {
+	b := benchmarkBoard(bm)
+	bm.ReportAllocs()
+	bm.ResetTimer()
+	for i := 0; i < bm.N; i++ {
+		b.WriteSnapshotJSON(ioutil.Discard)
+	}
+}
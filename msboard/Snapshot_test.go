@@ -0,0 +1,167 @@
+/*
+	Test functions for Board snapshots
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSnapshotUninitialized(t *testing.T) {
+	b := NewBoard("medium")
+	snap := b.Snapshot()
+
+	if snap.Status != StatusUninitialized {
+		t.Errorf("Uninitialized board snapshot status wanted %v got %v", StatusUninitialized, snap.Status)
+	}
+	if len(snap.Cells) != b.rows || len(snap.Cells[0]) != b.cols {
+		t.Errorf("Uninitialized board snapshot has wrong shape %dx%d", len(snap.Cells), len(snap.Cells[0]))
+	}
+	if view, ok := snap.Cell(Location{3, 3}); !ok || view.State != CellHidden {
+		t.Errorf("Uninitialized board snapshot cell wanted hidden got %v (ok %v)", view, ok)
+	}
+}
+
+func TestSnapshotHidesMines(t *testing.T) {
+	rand.Seed(1995)
+	b := NewBoard("easy")
+	b.Initialize(Location{4, 4})
+
+	// flag a mine and reveal the safe starting cell
+	mine := b.mines[0]
+	b.ToggleFlag(mine)
+	b.Click(Location{4, 4})
+
+	snap := b.Snapshot()
+	if snap.Flags != 1 {
+		t.Errorf("Snapshot flag count wanted 1 got %d", snap.Flags)
+	}
+	for row := range snap.Cells {
+		for col, view := range snap.Cells[row] {
+			if view.State == CellMine {
+				t.Errorf("Snapshot exposed a mine at %d,%d before it was revealed", row, col)
+			}
+		}
+	}
+	if view, _ := snap.Cell(mine); view.State != CellFlagged {
+		t.Errorf("Snapshot of flagged cell wanted %v got %v", CellFlagged, view.State)
+	}
+	if view, _ := snap.Cell(Location{4, 4}); view.State != CellRevealed || view.Score != b.getCell(Location{4, 4}).score {
+		t.Errorf("Snapshot of revealed cell wrong, got %v", view)
+	}
+
+	// snapshots are copies, later moves must not change them
+	b.RevealAll()
+	if view, _ := snap.Cell(mine); view.State != CellFlagged {
+		t.Errorf("Snapshot changed after board was modified, got %v", view.State)
+	}
+	if view, _ := b.Snapshot().Cell(mine); view.State != CellMine {
+		t.Errorf("Snapshot after RevealAll wanted mine got %v", view.State)
+	}
+}
+
+func TestSnapshotDiff(t *testing.T) {
+	rand.Seed(1995)
+	b := NewBoard("easy")
+	before := b.Snapshot()
+	b.Initialize(Location{0, 0})
+
+	initialized := b.Snapshot()
+	delta := SnapshotDiff(before, initialized)
+	if len(delta.Cells) != 0 || delta.Reshaped {
+		t.Errorf("Initializing board should not change any visible cells, got %d changes", len(delta.Cells))
+	}
+	if !delta.StatusChanged() || delta.FromStatus != StatusUninitialized || delta.ToStatus != StatusPlaying {
+		t.Errorf("Expected status transition uninitialized -> playing, got %v -> %v", delta.FromStatus, delta.ToStatus)
+	}
+
+	// flag one hidden cell, then compare
+	flagAt := b.mines[0]
+	b.ToggleFlag(flagAt)
+	delta = SnapshotDiff(initialized, b.Snapshot())
+	if len(delta.Cells) != 1 || delta.Cells[0].Location != flagAt || delta.Cells[0].To.State != CellFlagged {
+		t.Errorf("Expected single flag change at %v, got %v", flagAt, delta.Cells)
+	}
+	if delta.StatusChanged() || delta.Empty() {
+		t.Errorf("Flag change delta reported wrong status/emptiness: %v", delta)
+	}
+
+	if !SnapshotDiff(initialized, initialized).Empty() {
+		t.Errorf("Diff of a snapshot against itself should be empty")
+	}
+
+	// reshaped boards report every cell
+	other := NewBoard("medium").Snapshot()
+	delta = SnapshotDiff(initialized, other)
+	if !delta.Reshaped || len(delta.Cells) != other.Rows*other.Cols {
+		t.Errorf("Diff across board sizes wanted %d reshaped changes, got %d (reshaped %v)", other.Rows*other.Cols, len(delta.Cells), delta.Reshaped)
+	}
+}
+
+func TestFlagsAround(t *testing.T) {
+	b, _ := ParseLayout("F*./f2./...")
+	s := b.Snapshot()
+
+	var cases = []struct {
+		l    Location
+		want int
+	}{
+		{Location{1, 1}, 2},
+		{Location{0, 0}, 1}, // a cell's own flag doesn't count
+		{Location{2, 2}, 0},
+		{Location{5, 5}, 0},
+	}
+	for _, testcase := range cases {
+		if got := s.FlagsAround(testcase.l); got != testcase.want {
+			t.Errorf("FlagsAround(%v) wanted %d got %d", testcase.l, testcase.want, got)
+		}
+	}
+}
+
+func TestRestore(t *testing.T) {
+	b, err := ParseLayout("*1../11../....")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mark := b.Snapshot()
+
+	b.ToggleFlag(NewLocation(0, 0))
+	b.Click(NewLocation(2, 3))
+	b.Click(NewLocation(0, 0)) // flagged, ignored
+	if b.Status() != StatusWon {
+		t.Fatalf("board should have been won, got %v\n%s", b.Status(), b.Layout())
+	}
+	if err := b.Restore(mark); err != nil {
+		t.Fatalf("Restore() failed: %s", err)
+	}
+	if b.Layout() != "*1../11../...." || b.SafeRemaining() != mark.SafeRemaining || b.Status() != StatusPlaying {
+		t.Errorf("restored board wanted the bookmarked position got %s, %d safe, %v", b.Layout(), b.SafeRemaining(),
+			b.Status())
+	}
+
+	// a lost position comes back lost
+	b.Click(NewLocation(0, 0))
+	lost := b.Snapshot()
+	if err := b.Restore(mark); err != nil || b.Status() != StatusPlaying {
+		t.Fatalf("Restore() of the playing position failed: %v, %v", err, b.Status())
+	}
+	if err := b.Restore(lost); err != nil || b.Status() != StatusLost {
+		t.Errorf("Restore() of a lost position wanted it lost got %v, %v", err, b.Status())
+	}
+
+	// snapshots of other boards are refused, leaving the board alone
+	other, _ := ParseLayout(".1*./.11./....")
+	for _, s := range []Snapshot{other.Snapshot(), NewBoard("easy").Snapshot()} {
+		before := b.Layout()
+		if err := b.Restore(s); err == nil || b.Layout() != before {
+			t.Errorf("Restore() of a mismatched snapshot wanted an error and no change, got %v and %s", err, b.Layout())
+		}
+	}
+	if err := NewBoard("easy").Restore(mark); err == nil {
+		t.Error("Restore() on an uninitialized board should fail")
+	}
+}
@@ -0,0 +1,169 @@
+/*
+
+	Topology.go - board shapes by name: which cells neighbor each other and how cells are named. The square grid is
+	built in; other packages add their own, such as triangular grids or wrapped edges, with RegisterTopology
+
+	Cells are still addressed by row and column whatever the topology; a topology decides what is adjacent to what.
+	Fog of war and power-ups that act on an area still measure it in rows and columns.
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// GridTopology : name of the built in square grid, where each cell has up to 8 neighbors
+const GridTopology = "grid"
+
+// NeighborFunc : the cells adjacent to l on a board of rows by cols. Locations off the board and l itself are
+// dropped by the caller, so a function need not check for them. Adjacency should be symmetric
+type NeighborFunc func(rows, cols int, l Location) []Location
+
+// Topology : a registered board shape
+type Topology struct {
+	Name      string
+	Neighbors NeighborFunc
+	Codec     LocationCodec // default coordinate scheme for the topology's cells, see Coordinates.go
+}
+
+// topologies : every topology available by name
+var topologies = struct {
+	sync.RWMutex
+	byName map[string]Topology
+}{
+	byName: map[string]Topology{
+		GridTopology: {Name: GridTopology, Neighbors: gridNeighbors, Codec: LetterNumberCodec{}},
+	},
+}
+
+// RegisterTopology -- make a board shape available by name, for SetTopology and for reading save files and replays
+// that name it. Names are unique; a nil codec names cells as the grid does
+func RegisterTopology(name string, neighbors NeighborFunc, codec LocationCodec) error {
+	if name == "" || nil == neighbors {
+		return errors.New("topologies need a name and a neighbor function")
+	}
+	if nil == codec {
+		codec = LetterNumberCodec{}
+	}
+
+	topologies.Lock()
+	defer topologies.Unlock()
+	if _, taken := topologies.byName[name]; taken {
+		return fmt.Errorf("topology %q is already registered", name)
+	}
+	topologies.byName[name] = Topology{Name: name, Neighbors: neighbors, Codec: codec}
+	return nil
+}
+
+// LookupTopology -- the topology registered under a name; the empty name is the grid
+func LookupTopology(name string) (Topology, error) {
+	if name == "" {
+		name = GridTopology
+	}
+	topologies.RLock()
+	defer topologies.RUnlock()
+	t, ok := topologies.byName[name]
+	if !ok {
+		return Topology{}, fmt.Errorf("no board topology named %q", name)
+	}
+	return t, nil
+}
+
+// TopologyNames -- names of every registered topology, sorted
+func TopologyNames() []string {
+	topologies.RLock()
+	defer topologies.RUnlock()
+	retval := make([]string, 0, len(topologies.byName))
+	for name := range topologies.byName {
+		retval = append(retval, name)
+	}
+	sort.Strings(retval)
+	return retval
+}
+
+// SetTopology -- play the board on a registered topology, recounting the scores of an initialized board for its
+// neighborhoods
+func (b *Board) SetTopology(name string) error {
+	t, err := LookupTopology(name)
+	if err != nil {
+		return err
+	}
+	if t.Name == GridTopology {
+		b.topology = nil
+	} else {
+		b.topology = &t
+	}
+	// cells may be named differently
+	b.console = nil
+	if b.initialized {
+		initializeScores(b)
+		b.checkAudit("SetTopology")
+	}
+	return nil
+}
+
+// Topology -- name of the board's topology
+func (b *Board) Topology() string {
+	if nil == b.topology {
+		return GridTopology
+	}
+	return b.topology.Name
+}
+
+// forNeighbors -- call visit with every on-board cell adjacent to l in the board's topology; the grid's in reading
+// order
+func (b *Board) forNeighbors(l Location, visit func(n *cell)) {
+	if nil != b.topology {
+		for _, n := range b.topology.Neighbors(b.rows, b.cols, l) {
+			if c := b.getCell(n); nil != c && n != l {
+				visit(c)
+			}
+		}
+		return
+	}
+	for row := l.row - 1; row <= l.row+1; row++ {
+		for col := l.col - 1; col <= l.col+1; col++ {
+			if c := b.getCell(Location{row, col}); nil != c && (row != l.row || col != l.col) {
+				visit(c)
+			}
+		}
+	}
+}
+
+// Neighbors -- on-board locations adjacent to l in the snapshot's topology
+func (s Snapshot) Neighbors(l Location) []Location {
+	var candidates []Location
+	if t, err := LookupTopology(s.Topology); err == nil && t.Name != GridTopology {
+		candidates = t.Neighbors(s.Rows, s.Cols, l)
+	} else {
+		candidates = gridNeighbors(s.Rows, s.Cols, l)
+	}
+
+	retval := make([]Location, 0, len(candidates))
+	for _, n := range candidates {
+		if _, ok := s.Cell(n); ok && n != l {
+			retval = append(retval, n)
+		}
+	}
+	return retval
+}
+
+// gridNeighbors -- the 8 locations around l, on the board or not
+func gridNeighbors(rows, cols int, l Location) []Location {
+	retval := make([]Location, 0, 8)
+	for row := l.row - 1; row <= l.row+1; row++ {
+		for col := l.col - 1; col <= l.col+1; col++ {
+			if row != l.row || col != l.col {
+				retval = append(retval, Location{row, col})
+			}
+		}
+	}
+	return retval
+}
@@ -0,0 +1,78 @@
+/*
+	Test functions for board topologies
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"testing"
+)
+
+// torusNeighbors -- the grid's neighborhoods with opposite edges joined
+func torusNeighbors(rows, cols int, l Location) []Location {
+	var retval []Location
+	for _, n := range gridNeighbors(rows, cols, l) {
+		retval = append(retval, Location{(n.row + rows) % rows, (n.col + cols) % cols})
+	}
+	return retval
+}
+
+func init() {
+	if err := RegisterTopology("torus", torusNeighbors, nil); err != nil {
+		panic(err)
+	}
+}
+
+func TestRegisterTopology(t *testing.T) {
+	if err := RegisterTopology("torus", torusNeighbors, nil); err == nil {
+		t.Errorf("registering a taken name should fail")
+	}
+	if err := RegisterTopology("", torusNeighbors, nil); err == nil {
+		t.Errorf("registering without a name should fail")
+	}
+	if err := RegisterTopology("flat", nil, nil); err == nil {
+		t.Errorf("registering without neighbors should fail")
+	}
+	if _, err := LookupTopology("hex"); err == nil {
+		t.Errorf("LookupTopology of an unknown topology should fail")
+	}
+	if got, err := LookupTopology(""); err != nil || got.Name != GridTopology {
+		t.Errorf("LookupTopology(\"\") wanted the grid got %v, %v", got.Name, err)
+	}
+}
+
+func TestTorusBoard(t *testing.T) {
+	// D4 touches A1 across both edges
+	layout := "*.../..../..../...1"
+	if _, err := ParseLayout(layout); err == nil {
+		t.Errorf("ParseLayout(%q) on the grid should fail", layout)
+	}
+	b, err := ParseTopologyLayout(layout, "torus")
+	if err != nil {
+		t.Fatalf("ParseTopologyLayout(%q) failed: %s", layout, err)
+	}
+	if b.Topology() != "torus" || b.Layout() != layout {
+		t.Errorf("wanted a torus board with layout %q got %s, %q", layout, b.Topology(), b.Layout())
+	}
+
+	s := b.Snapshot()
+	if n := len(s.Neighbors(NewLocation(0, 0))); s.Topology != "torus" || n != 8 {
+		t.Errorf("Snapshot wanted 8 torus neighbors for a corner got %d on %q", n, s.Topology)
+	}
+	if _, err := b.Transform(TransformRotate90); err == nil {
+		t.Errorf("Transform of a torus board should fail")
+	}
+	if a := b.Audit(); !a.OK() {
+		t.Errorf("torus board fails its audit: %s", a)
+	}
+
+	// back on the grid the corner mine is out of D4's reach
+	if err = b.SetTopology(GridTopology); err != nil {
+		t.Fatalf("SetTopology failed: %s", err)
+	}
+	if v, _ := b.Snapshot().Cell(NewLocation(3, 3)); v.Score != 0 {
+		t.Errorf("grid D4 wanted score 0 got %d", v.Score)
+	}
+}
@@ -0,0 +1,210 @@
+/*
+
+	Transform.go - rotations and reflections of boards and snapshots
+
+	A board can be turned or flipped eight ways. Quarter turns and the diagonal flips swap the number of rows and
+	columns; the rest keep the shape. Every transform moves cells, flags, reveals and mines together, so scores are
+	unchanged and a transformed game plays the same as the original.
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+)
+
+// Transform : one of the eight ways to turn or flip a board
+type Transform int
+
+// Supported transforms
+const (
+	TransformIdentity      Transform = iota
+	TransformRotate90                // a quarter turn clockwise
+	TransformRotate180               // a half turn
+	TransformRotate270               // a quarter turn anticlockwise
+	TransformMirrorH                 // top and bottom swapped, the flip SymmetryHorizontal layouts don't change under
+	TransformMirrorV                 // left and right swapped, the flip SymmetryVertical layouts don't change under
+	TransformTranspose               // rows and columns swapped, flipping over the top left to bottom right diagonal
+	TransformAntiTranspose           // flipped over the top right to bottom left diagonal
+)
+
+var transformNames = [...]string{"identity", "rotate90", "rotate180", "rotate270", "mirrorH", "mirrorV",
+	"transpose", "antitranspose"}
+
+// String -- human readable transform name
+func (t Transform) String() string {
+	if t < 0 || int(t) >= len(transformNames) {
+		return "unknown"
+	}
+	return transformNames[t]
+}
+
+// Transforms -- every transform, identity first
+func Transforms() []Transform {
+	retval := make([]Transform, len(transformNames))
+	for i := range retval {
+		retval[i] = Transform(i)
+	}
+	return retval
+}
+
+// Swaps -- true if the transform exchanges the number of rows and columns
+func (t Transform) Swaps() bool {
+	return t == TransformRotate90 || t == TransformRotate270 || t == TransformTranspose || t == TransformAntiTranspose
+}
+
+// Size -- the rows and columns of a rows x cols board after the transform
+func (t Transform) Size(rows, cols int) (int, int) {
+	if t.Swaps() {
+		return cols, rows
+	}
+	return rows, cols
+}
+
+// Location -- where a location on a rows x cols board ends up after the transform
+func (t Transform) Location(l Location, rows, cols int) Location {
+	switch t {
+	case TransformRotate90:
+		return Location{l.col, rows - 1 - l.row}
+	case TransformRotate180:
+		return Location{rows - 1 - l.row, cols - 1 - l.col}
+	case TransformRotate270:
+		return Location{cols - 1 - l.col, l.row}
+	case TransformMirrorH:
+		return Location{rows - 1 - l.row, l.col}
+	case TransformMirrorV:
+		return Location{l.row, cols - 1 - l.col}
+	case TransformTranspose:
+		return Location{l.col, l.row}
+	case TransformAntiTranspose:
+		return Location{cols - 1 - l.col, rows - 1 - l.row}
+	}
+	return l
+}
+
+// Inverse -- the transform that undoes this one
+func (t Transform) Inverse() Transform {
+	switch t {
+	case TransformRotate90:
+		return TransformRotate270
+	case TransformRotate270:
+		return TransformRotate90
+	}
+	return t
+}
+
+// Transform -- a copy of the snapshot turned or flipped
+func (s Snapshot) Transform(t Transform) Snapshot {
+	retval := s
+	retval.Rows, retval.Cols = t.Size(s.Rows, s.Cols)
+	retval.Cells = make([][]CellView, retval.Rows)
+	for row := range retval.Cells {
+		retval.Cells[row] = make([]CellView, retval.Cols)
+	}
+	for row := range s.Cells {
+		for col, v := range s.Cells[row] {
+			l := t.Location(Location{row, col}, s.Rows, s.Cols)
+			retval.Cells[l.row][l.col] = v
+		}
+	}
+	return retval
+}
+
+// Transform -- a copy of the board turned or flipped, mines, reveals and flags included. Uninitialized boards
+// only change shape
+func (b *Board) Transform(t Transform) (*Board, error) {
+	if nil == b {
+		return nil, errors.New("called Transform() on a nil board")
+	}
+	if t < TransformIdentity || t > TransformAntiTranspose {
+		return nil, fmt.Errorf("unsupported transform %v", t)
+	}
+	if nil != b.topology && t != TransformIdentity {
+		return nil, fmt.Errorf("%s boards can't be turned or flipped", b.topology.Name)
+	}
+
+	retval := new(Board)
+	*retval = *b
+	retval.rows, retval.cols = t.Size(b.rows, b.cols)
+	retval.console = nil
+	if !b.initialized {
+		retval.cells, retval.mines = nil, nil
+		return retval, nil
+	}
+
+	retval.allocateCells()
+	for row := range b.cells {
+		for col, c := range b.cells[row] {
+			l := t.Location(Location{row, col}, b.rows, b.cols)
+			moved := *c
+			moved.location = l
+			retval.cells[l.row][l.col] = &moved
+		}
+	}
+	for _, l := range b.mines {
+		retval.mines = append(retval.mines, t.Location(l, b.rows, b.cols))
+	}
+	retval.checkAudit("Transform")
+	return retval, nil
+}
+
+// CanonicalLayout -- the same layout for every position that a shape keeping transform turns into another, so
+// boards can be de-duplicated: the smallest layout string among those transforms, and the transform that gives it.
+// Uninitialized boards have no layout
+func (b *Board) CanonicalLayout() (string, Transform) {
+	retval, best := b.Layout(), TransformIdentity
+	if retval == "" {
+		return "", TransformIdentity
+	}
+	for _, t := range b.shapeKeeping()[1:] {
+		turned, _ := b.Transform(t)
+		if layout := turned.Layout(); layout < retval {
+			retval, best = layout, t
+		}
+	}
+	return retval, best
+}
+
+// CanonicalHash -- a short name for the position shared by every copy a shape keeping transform turns it into,
+// for indexing de-duplicated boards: the smallest of the hashes of their layouts, as 16 hex digits. Uninitialized
+// boards have no hash
+func (b *Board) CanonicalHash() string {
+	if b.Layout() == "" {
+		return ""
+	}
+	retval := ""
+	for _, t := range b.shapeKeeping() {
+		turned, _ := b.Transform(t)
+		if hash := LayoutHash(turned.Layout()); retval == "" || hash < retval {
+			retval = hash
+		}
+	}
+	return retval
+}
+
+// LayoutHash -- 64 bit FNV-1a hash of a layout string as 16 hex digits
+func LayoutHash(layout string) string {
+	h := fnv.New64a()
+	h.Write([]byte(layout))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// shapeKeeping -- the transforms that leave the board's rows and columns as they are, identity first. Only the
+// grid can be turned or flipped
+func (b *Board) shapeKeeping() []Transform {
+	if nil != b.topology {
+		return []Transform{TransformIdentity}
+	}
+	var retval []Transform
+	for _, t := range Transforms() {
+		if rows, cols := t.Size(b.rows, b.cols); rows == b.rows && cols == b.cols {
+			retval = append(retval, t)
+		}
+	}
+	return retval
+}
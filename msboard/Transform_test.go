@@ -0,0 +1,141 @@
+/*
+	Test functions for board rotations and reflections
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestTransformLayouts(t *testing.T) {
+	// mines in two opposite corners, with one score revealed beside the top left mine
+	b, err := ParseLayout("*1./.../..*")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+
+	var cases = []struct {
+		transform Transform
+		want      string
+	}{
+		{TransformIdentity, "*1./.../..*"},
+		{TransformRotate90, "..*/..1/*.."},
+		{TransformRotate180, "*../.../.1*"},
+		{TransformRotate270, "..*/1../*.."},
+		{TransformMirrorH, "..*/.../*1."},
+		{TransformMirrorV, ".1*/.../*.."},
+		{TransformTranspose, "*../1../..*"},
+		{TransformAntiTranspose, "*../..1/..*"},
+	}
+	for _, testcase := range cases {
+		turned, err := b.Transform(testcase.transform)
+		if err != nil {
+			t.Fatalf("Transform(%v) failed: %s", testcase.transform, err)
+		}
+		if got := turned.Layout(); got != testcase.want {
+			t.Errorf("Transform(%v) wanted %q got %q", testcase.transform, testcase.want, got)
+		}
+		// the layout parses, so every revealed score still matches the mines around it
+		if _, err := ParseLayout(turned.Layout()); err != nil {
+			t.Errorf("Transform(%v) layout doesn't parse: %s", testcase.transform, err)
+		}
+	}
+}
+
+func TestTransformRoundTrip(t *testing.T) {
+	rand.Seed(1995)
+	b := NewBoard("hard")
+	b.FirstClick(NewLocation(3, 5))
+	b.ToggleFlag(NewLocation(15, 29))
+	s := b.Snapshot()
+
+	for _, tr := range Transforms() {
+		turned, err := b.Transform(tr)
+		if err != nil {
+			t.Fatalf("Transform(%v) failed: %s", tr, err)
+		}
+		if rows, cols := tr.Size(16, 30); turned.Rows() != rows || turned.Cols() != cols {
+			t.Errorf("Transform(%v) gave %dx%d, Size says %dx%d", tr, turned.Rows(), turned.Cols(), rows, cols)
+		}
+		if a := turned.Audit(); !a.OK() || turned.SafeRemaining() != b.SafeRemaining() {
+			t.Errorf("Transform(%v) board inconsistent: %v", tr, a)
+		}
+
+		// the snapshot of the turned board is the turned snapshot, and turning back restores the board
+		if got, want := turned.Snapshot(), s.Transform(tr); !sameCells(got, want) {
+			t.Errorf("Transform(%v) board and snapshot disagree", tr)
+		}
+		back, _ := turned.Transform(tr.Inverse())
+		if back.Layout() != b.Layout() {
+			t.Errorf("Transform(%v) then its inverse changed the board", tr)
+		}
+		l := NewLocation(3, 5)
+		if got := tr.Inverse().Location(tr.Location(l, 16, 30), turned.Rows(), turned.Cols()); got != l {
+			t.Errorf("Transform(%v) location round trip wanted %v got %v", tr, l, got)
+		}
+	}
+
+	blank, _ := NewBoard("hard").Transform(TransformRotate90)
+	if blank.Rows() != 30 || blank.Cols() != 16 || blank.Initialized() {
+		t.Errorf("Transform of an uninitialized board wanted a 30x16 blank got %dx%d", blank.Rows(), blank.Cols())
+	}
+	if _, err := b.Transform(Transform(8)); err == nil {
+		t.Errorf("Transform with an unknown transform should fail")
+	}
+}
+
+func TestCanonicalLayout(t *testing.T) {
+	rand.Seed(7)
+	b := NewBoard("medium")
+	b.Initialize(NewLocation(0, 0))
+	want, _ := b.CanonicalLayout()
+	wantHash := b.CanonicalHash()
+
+	for _, tr := range Transforms() {
+		turned, _ := b.Transform(tr)
+		got, how := turned.CanonicalLayout()
+		if got != want {
+			t.Errorf("CanonicalLayout after %v differs", tr)
+		}
+		if hash := turned.CanonicalHash(); hash != wantHash || len(hash) != 16 {
+			t.Errorf("CanonicalHash after %v wanted %q got %q", tr, wantHash, hash)
+		}
+		if canonical, _ := turned.Transform(how); canonical.Layout() != got {
+			t.Errorf("CanonicalLayout after %v: its transform %v doesn't give it", tr, how)
+		}
+	}
+
+	// a 16x30 board only has the four shape keeping transforms to choose from
+	hard := NewBoard("hard")
+	hard.Initialize(NewLocation(0, 0))
+	if _, how := hard.CanonicalLayout(); how.Swaps() {
+		t.Errorf("CanonicalLayout of a non-square board chose %v", how)
+	}
+	if got, _ := NewBoard("easy").CanonicalLayout(); got != "" || NewBoard("easy").CanonicalHash() != "" {
+		t.Errorf("CanonicalLayout of an uninitialized board wanted nothing got %q", got)
+	}
+	other := NewBoard("medium")
+	other.Initialize(NewLocation(8, 8))
+	if other.CanonicalHash() == wantHash {
+		t.Errorf("CanonicalHash is the same for two different boards")
+	}
+}
+
+// sameCells -- true if two snapshots show the same cells
+func sameCells(a, b Snapshot) bool {
+	if a.Rows != b.Rows || a.Cols != b.Cols {
+		return false
+	}
+	for row := range a.Cells {
+		for col := range a.Cells[row] {
+			if a.Cells[row][col] != b.Cells[row][col] {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+/*
+
+	Treasure.go - treasure cells for the arcade variant: safe cells hiding a power-up, which the player collects by
+	revealing them
+
+	The generator hides treasures on safe cells once the mines are laid. They are never shown in a Snapshot, so
+	solvers and network clients see the same board as ever; front ends ask Collect which ones a move uncovered.
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+// Treasure : the power-up hidden in a cell
+type Treasure int
+
+// Supported treasures
+const (
+	TreasureNone       Treasure = iota
+	TreasureDefuser             // clears a patch of the board safely, see Defuse
+	TreasureFreeFlag            // flags a mine for the player, see FlagMine
+	TreasureMultiplier          // raises the arcade score multiplier
+)
+
+var treasureNames = [...]string{"none", "defuser", "free flag", "multiplier"}
+
+// String -- human readable treasure name
+func (t Treasure) String() string {
+	if t < 0 || int(t) >= len(treasureNames) {
+		return "unknown"
+	}
+	return treasureNames[t]
+}
+
+// TreasureFind : a treasure uncovered by a move
+type TreasureFind struct {
+	Location Location
+	Treasure Treasure
+}
+
+// placeTreasures -- hide count treasures of random kinds on safe cells other than the safe spot. Boards without
+// enough safe cells get as many as fit
+func (b *Board) placeTreasures(rng RNG, count int, safespot Location) {
+	var safe []*cell
+	for row := range b.cells {
+		for _, c := range b.cells[row] {
+			if !c.hasMine && c.location != safespot {
+				safe = append(safe, c)
+			}
+		}
+	}
+	for ; count > 0 && len(safe) > 0; count-- {
+		takeCell(&safe, rng).treasure = Treasure(1 + rng.Intn(len(treasureNames)-1))
+	}
+}
+
+// TreasureAt -- the uncollected treasure hidden at a location, TreasureNone if there is none
+func (b *Board) TreasureAt(l Location) Treasure {
+	if c := b.getCell(l); nil != c && !c.looted {
+		return c.treasure
+	}
+	return TreasureNone
+}
+
+// Collect -- take the treasures from the revealed cells among those given, typically the cells a Click returned.
+// Each treasure is collected once; Reset hides them again
+func (b *Board) Collect(revealed []Location) []TreasureFind {
+	var retval []TreasureFind
+	for _, l := range revealed {
+		if c := b.getCell(l); nil != c && c.revealed && !c.hasMine && c.treasure != TreasureNone && !c.looted {
+			c.looted = true
+			retval = append(retval, TreasureFind{l, c.treasure})
+		}
+	}
+	return retval
+}
+
+// Defuse -- reveal every hidden safe cell within radius of the center, clearing any wrong flags, and flag every
+// mine there. Revealed zeros cascade as usual. Returns the cells revealed and the mines newly flagged
+func (b *Board) Defuse(center Location, radius int) ([]Location, []Location) {
+	if b.Status() != StatusPlaying || !b.ValidLocation(center) {
+		return nil, nil
+	}
+
+	var revealed, flagged []Location
+	for row := center.row - radius; row <= center.row+radius; row++ {
+		for col := center.col - radius; col <= center.col+radius; col++ {
+			c := b.getCell(Location{row, col})
+			switch {
+			case nil == c || c.revealed:
+			case c.hasMine && !c.flagged:
+				c.flagged = true
+				flagged = append(flagged, c.location)
+			case !c.hasMine:
+				c.flagged = false
+				revealed = append(revealed, b.reveal(c)...)
+			}
+		}
+	}
+	b.checkAudit("Defuse")
+	return revealed, flagged
+}
+
+// FlagMine -- flag a random unflagged mine, choosing among those next to a revealed cell if there are any, as
+// those are the ones the player is working on. False if every mine is already flagged
+func (b *Board) FlagMine(rng RNG) (Location, bool) {
+	if b.Status() != StatusPlaying {
+		return Location{}, false
+	}
+	if nil == rng {
+		rng = globalRNG{}
+	}
+
+	var frontier, rest []*cell
+	for _, l := range b.mines {
+		c := b.getCell(l)
+		if c.flagged {
+			continue
+		}
+		if b.outOfSight(c) {
+			rest = append(rest, c)
+		} else {
+			frontier = append(frontier, c)
+		}
+	}
+	if len(frontier) == 0 {
+		frontier = rest
+	}
+	if len(frontier) == 0 {
+		return Location{}, false
+	}
+	c := takeCell(&frontier, rng)
+	c.flagged = true
+	b.checkAudit("FlagMine")
+	return c.location, true
+}
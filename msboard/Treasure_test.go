@@ -0,0 +1,85 @@
+/*
+	Test functions for treasure cells
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPlaceTreasures(t *testing.T) {
+	rand.Seed(1995)
+	b := NewBoard("easy")
+	revealed, err := b.FirstClickWithOptions(NewLocation(4, 4), GeneratorOptions{Treasures: 6})
+	if err != nil {
+		t.Fatalf("FirstClickWithOptions failed: %s", err)
+	}
+
+	hidden := 0
+	for row := 0; row < b.Rows(); row++ {
+		for col := 0; col < b.Cols(); col++ {
+			l := NewLocation(row, col)
+			if b.TreasureAt(l) == TreasureNone {
+				continue
+			}
+			hidden++
+			if b.MineAt(l) || l == NewLocation(4, 4) {
+				t.Errorf("treasure at %v, which is a mine or the first click", l)
+			}
+		}
+	}
+	if hidden != 6 {
+		t.Errorf("FirstClickWithOptions wanted 6 treasures hidden got %d", hidden)
+	}
+
+	// treasures uncovered by the opening are collected once each, and hidden again by a reset
+	found := b.Collect(revealed)
+	for _, f := range found {
+		if f.Treasure == TreasureNone || b.TreasureAt(f.Location) != TreasureNone {
+			t.Errorf("Collect found %+v but it's still there or empty", f)
+		}
+	}
+	if again := b.Collect(revealed); len(again) != 0 {
+		t.Errorf("Collect twice wanted nothing the second time, got %v", again)
+	}
+	b.Reset()
+	for _, f := range found {
+		if b.TreasureAt(f.Location) != f.Treasure {
+			t.Errorf("Reset wanted %v back at %v", f.Treasure, f.Location)
+		}
+	}
+
+	if _, err := NewBoard("easy").FirstClickWithOptions(NewLocation(0, 0),
+		GeneratorOptions{Algorithm: AlgorithmWinmine, Treasures: 1}); err == nil {
+		t.Errorf("winmine layouts should refuse treasures")
+	}
+}
+
+func TestDefuse(t *testing.T) {
+	b, err := ParseLayout("*.../..../....")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+	b.SetFlagged(NewLocation(1, 0), true) // a wrong flag
+
+	revealed, flagged := b.Defuse(NewLocation(0, 0), 1)
+	if len(revealed) != 3 || len(flagged) != 1 || flagged[0] != NewLocation(0, 0) {
+		t.Errorf("Defuse wanted 3 cells revealed and A1 flagged, got %v and %v", revealed, flagged)
+	}
+	if a := b.Audit(); !a.OK() {
+		t.Errorf("Defuse left the board inconsistent: %s", a)
+	}
+
+	// the only mine is flagged already
+	if l, ok := b.FlagMine(nil); ok {
+		t.Errorf("FlagMine wanted no mine left to flag, got %v", l)
+	}
+	fresh, _ := ParseLayout("*.../..../....")
+	if l, ok := fresh.FlagMine(nil); !ok || l != NewLocation(0, 0) {
+		t.Errorf("FlagMine wanted A1 flagged got %v %v", l, ok)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+
+	Winmine.go - the classic Windows Minesweeper layout algorithm, for nostalgia and for comparing against boards
+	recorded from the original game
+
+	Windows Minesweeper seeds the Microsoft C runtime's rand() and places each mine by drawing a column and then a
+	row, rand() % width and rand() % height, drawing again whenever the cell already has a mine. Mines are placed
+	before the first click; if that click lands on a mine, the mine moves to the first empty cell in reading order,
+	starting from the top left corner.
+
+	mike@pocomotech.com
+
+*/
+
+package msboard
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MSRand : the Microsoft C runtime's rand(), a linear congruential generator producing 15 bit values
+type MSRand struct {
+	state uint32
+}
+
+// NewMSRand -- generator seeded as srand(seed) would
+func NewMSRand(seed uint32) *MSRand {
+	return &MSRand{state: seed}
+}
+
+// Rand -- the next value, as rand() returns it
+func (r *MSRand) Rand() int {
+	r.state = r.state*214013 + 2531011
+	return int(r.state>>16) & 0x7fff
+}
+
+// Intn -- rand() % n, the way Windows Minesweeper draws a coordinate
+func (r *MSRand) Intn(n int) int {
+	return r.Rand() % n
+}
+
+// WinmineOptions -- generator options that lay out the board Windows Minesweeper would for a seed
+func WinmineOptions(seed uint32) GeneratorOptions {
+	return GeneratorOptions{Algorithm: AlgorithmWinmine, RNG: NewMSRand(seed)}
+}
+
+// initializeWinmine -- place mines with the Windows Minesweeper algorithm, drawing from opts.RNG, which should
+// be an MSRand for a faithful layout. The other layout constraints aren't part of the original game and are
+// rejected
+func (b *Board) initializeWinmine(safespot Location, opts GeneratorOptions) error {
+	if opts.Symmetry != SymmetryNone || opts.NoEights || opts.MinOpening > 0 || opts.Treasures > 0 ||
+		opts.AntiMines > 0 {
+		return errors.New("the winmine algorithm takes no symmetry, no-eights, opening, treasure or anti-mine " +
+			"constraints")
+	}
+	if !b.ValidLocation(safespot) {
+		return fmt.Errorf("first click %v is not on the board", safespot)
+	}
+	rng := opts.RNG
+	if nil == rng {
+		rng = globalRNG{}
+	}
+
+	b.allocateCells()
+	for placed := 0; placed < b.mineCount; {
+		col := rng.Intn(b.cols)
+		row := rng.Intn(b.rows)
+		if failed, ok := rng.(interface{ Err() error }); ok && nil != failed.Err() {
+			b.allocateCells()
+			return fmt.Errorf("generating from recorded draws: %s", failed.Err())
+		}
+		if c := b.cells[row][col]; !c.hasMine {
+			c.hasMine = true
+			placed++
+		}
+	}
+
+	// a first click on a mine moves it to the first empty cell
+	if first := b.getCell(safespot); first.hasMine {
+		first.hasMine = false
+	relocate:
+		for row := range b.cells {
+			for _, c := range b.cells[row] {
+				if !c.hasMine && c != first {
+					c.hasMine = true
+					break relocate
+				}
+			}
+		}
+	}
+
+	b.mines = nil
+	for row := range b.cells {
+		for _, c := range b.cells[row] {
+			if c.hasMine {
+				b.mines = append(b.mines, c.location)
+			}
+		}
+	}
+	b.safeRemaining = b.rows*b.cols - b.mineCount
+	initializeScores(b)
+	b.initialized = true
+	b.checkAudit("Initialize")
+	return nil
+}
@@ -0,0 +1,133 @@
+/*
+	Test functions for the Windows Minesweeper compatible generator
+
+	mike@pocomotech.com
+*/
+
+package msboard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMSRand(t *testing.T) {
+	// the well known start of the Microsoft C runtime's rand() after srand(1)
+	want := []int{41, 18467, 6334, 26500, 19169, 15724, 11478, 29358, 26962, 24464}
+	r := NewMSRand(1)
+	for i, w := range want {
+		if got := r.Rand(); got != w {
+			t.Fatalf("rand() call %d wanted %d got %d", i+1, w, got)
+		}
+	}
+}
+
+func TestWinmineLayout(t *testing.T) {
+	// the first mine is column 41 % 9, row 18467 % 9 = F9, the second 6334 % 9, 26500 % 9 = H5
+	const seed1 = "...*...../........*/.......**/........./.......*./.......*./*......*./........./.....*.*."
+
+	var cases = []struct {
+		first Location
+		want  string
+	}{
+		{Location{0, 0}, seed1},
+		// clicking the F9 mine moves it to the top left corner
+		{Location{8, 5}, "*" + seed1[1:len(seed1)-4] + "..*."},
+		// and when the corner is taken, to the next empty cell along
+		{Location{0, 3}, strings.Replace(seed1, "...*", "*...", 1)},
+	}
+
+	for _, testcase := range cases {
+		b := NewBoard("easy")
+		if err := b.InitializeWithOptions(testcase.first, WinmineOptions(1)); err != nil {
+			t.Fatalf("winmine generation failed: %s", err)
+		}
+		if got := b.MineLayout(); got != testcase.want {
+			t.Errorf("winmine seed 1 first click %v wanted\n%s got\n%s", testcase.first, testcase.want, got)
+		}
+		if b.MineAt(testcase.first) || len(b.Mines()) != 10 {
+			t.Errorf("winmine layout must keep the first click clear and place every mine")
+		}
+	}
+}
+
+func TestWinmineReference(t *testing.T) {
+	// Windows Minesweeper's expert and intermediate boards, whose mine counts differ from the presets here. The
+	// layouts were worked out from the published rand() sequence by a separate script following the algorithm
+	// described at the top of Winmine.go, not by this generator
+	var cases = []struct {
+		rows, cols, mines int
+		seed              uint32
+		first             Location
+		want              string
+	}{
+		{16, 30, 99, 1995, Location{7, 14},
+			".....*...*...............*.*../" +
+			"...**..**......**...*.*.*....*/" +
+			"....**......*...*............./" +
+			".**......**.**....*....*....../" +
+			"...*......*..*......*...*....*/" +
+			".....**.........*............./" +
+			"**........*..*........*......./" +
+			".......*.......*.*.........*.*/" +
+			"......*..*.*.......*..*......./" +
+			".*.*.**.*.................*..*/" +
+			".**.....*..........**....*.*.*/" +
+			".*.......**....*....*.*......*/" +
+			"...*..*..*..................../" +
+			".**.*.................*......./" +
+			"......**.*..*....*.......**..*/" +
+			"*..*.*.*..*.......*.*..**.**.*"},
+		// the first click lands on a mine, which moves to the empty top left corner
+		{16, 16, 40, 42, Location{0, 4},
+			"*.....*........*/" +
+			".*............../" +
+			"..............*./" +
+			"....**.*.....*../" +
+			"................/" +
+			"...*.*..**....../" +
+			"......*..*.....*/" +
+			".**...*........./" +
+			".......*.....*../" +
+			"**......**....../" +
+			"...........*..../" +
+			"*.*.......*....*/" +
+			"**.........**..*/" +
+			"......*......*../" +
+			".........*....../" +
+			"...**..........."},
+	}
+
+	for _, testcase := range cases {
+		b := NewCustomBoard(testcase.rows, testcase.cols, testcase.mines)
+		if err := b.InitializeWithOptions(testcase.first, WinmineOptions(testcase.seed)); err != nil {
+			t.Fatalf("winmine generation failed: %s", err)
+		}
+		if got := b.MineLayout(); got != testcase.want {
+			t.Errorf("winmine %dx%d seed %d wanted\n%s got\n%s", testcase.rows, testcase.cols, testcase.seed,
+				testcase.want, got)
+		}
+	}
+}
+
+func TestWinmineOptions(t *testing.T) {
+	b := NewBoard("easy")
+	opts := WinmineOptions(1)
+	opts.NoEights = true
+	if err := b.InitializeWithOptions(Location{0, 0}, opts); err == nil {
+		t.Errorf("winmine generation should reject constraints the original game lacks")
+	}
+
+	// recording works through the winmine generator too
+	recorder := &RecordingRNG{Source: NewMSRand(99)}
+	b.InitializeWithOptions(Location{0, 0}, GeneratorOptions{Algorithm: AlgorithmWinmine, RNG: recorder})
+	played := NewBoard("easy")
+	played.InitializeWithOptions(Location{0, 0}, GeneratorOptions{Algorithm: AlgorithmWinmine, RNG: NewPlaybackRNG(recorder.Draws)})
+	if played.MineLayout() != b.MineLayout() {
+		t.Errorf("winmine playback wanted\n%s got\n%s", b.MineLayout(), played.MineLayout())
+	}
+
+	if err := b.InitializeWithOptions(Location{0, 0}, GeneratorOptions{Algorithm: 7}); err == nil {
+		t.Errorf("an unknown algorithm should be rejected")
+	}
+}
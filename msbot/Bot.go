@@ -0,0 +1,153 @@
+/*
+
+	Bot.go - an automatic player, for simulations and as an opponent
+
+	mike@pocomotech.com
+
+*/
+
+// Package msbot -- an automatic player that sees only what a human would, through Snapshots, and plays using the
+// solvers in mssolver
+package msbot
+
+import (
+	"errors"
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"go-mines/mssolver"
+)
+
+// Prober : solver that estimates the chance each cell of a position holds a mine, see mssolver.Frontier
+type Prober interface {
+	Probabilities(s msboard.Snapshot) ([][]float64, error)
+}
+
+// Player : plays by revealing cells its solver proves safe, and when there are none, guessing the cell its policy
+// picks from the prober's probabilities. It never flags, as flags don't help it win. The zero Player uses
+// mssolver.Subset, mssolver.Frontier and LowestRisk
+type Player struct {
+	Solver msengine.Solver // certain moves; nil for mssolver.Subset
+	Prober Prober          // guesses; nil for mssolver.Frontier
+	Policy GuessPolicy     // which cell to guess; nil for LowestRisk
+}
+
+// solver -- the solver in use
+func (p Player) solver() msengine.Solver {
+	if nil == p.Solver {
+		return mssolver.Subset{}
+	}
+	return p.Solver
+}
+
+// prober -- the prober in use
+func (p Player) prober() Prober {
+	if nil == p.Prober {
+		return mssolver.Frontier{}
+	}
+	return p.Prober
+}
+
+// policy -- the guess policy in use
+func (p Player) policy() GuessPolicy {
+	if nil == p.Policy {
+		return LowestRisk{}
+	}
+	return p.Policy
+}
+
+// Next -- the player's next moves in a position: every cell its solver proves safe, or failing that a single
+// guess. Positions too complex for the prober get the first hidden cell in reading order. No moves once the game
+// is over
+func (p Player) Next(s msboard.Snapshot) []msboard.Move {
+	moves, _ := p.next(s)
+	return moves
+}
+
+// next -- Next, and whether its move is a guess
+func (p Player) next(s msboard.Snapshot) ([]msboard.Move, bool) {
+	if s.Status != msboard.StatusPlaying {
+		return nil, false
+	}
+
+	safe, _ := p.solver().Deductions(s)
+	if len(safe) > 0 {
+		retval := make([]msboard.Move, len(safe))
+		for i, l := range safe {
+			retval[i] = msboard.Move{Type: msboard.MoveReveal, Location: l}
+		}
+		return retval, false
+	}
+
+	policy := p.policy()
+	probabilities, err := p.prober().Probabilities(s)
+	if err != nil {
+		policy, probabilities = LowestRisk{}, make([][]float64, s.Rows)
+		for row := range probabilities {
+			probabilities[row] = make([]float64, s.Cols)
+		}
+	}
+	guess, ok := policy.Guess(s, probabilities)
+	if !ok {
+		return nil, false
+	}
+	return []msboard.Move{{Type: msboard.MoveReveal, Location: guess}}, true
+}
+
+// Play -- play a board to the end, starting with a reveal of first, which also lays out the mines on an
+// uninitialized board. Returns the final status and the number of cells the player clicked
+func (p Player) Play(b msengine.Board, first msboard.Location) (msboard.Status, int, error) {
+	return p.PlayEach(b, first, nil)
+}
+
+// PlayEach -- Play, calling made, if not nil, with every move just before it's applied
+func (p Player) PlayEach(b msengine.Board, first msboard.Location, made func(m msboard.Move)) (msboard.Status, int,
+	error) {
+	status, clicks, _, err := p.play(b, first, made)
+	return status, clicks, err
+}
+
+// PlayCounted -- Play, also returning how many clicks after the first were guesses, made with no cell the solver
+// proves safe
+func (p Player) PlayCounted(b msengine.Board, first msboard.Location) (msboard.Status, int, int, error) {
+	return p.play(b, first, nil)
+}
+
+// play -- PlayEach, also counting the guesses
+func (p Player) play(b msengine.Board, first msboard.Location, made func(m msboard.Move)) (status msboard.Status,
+	clicks, guesses int, err error) {
+	if nil == b {
+		return msboard.StatusUninitialized, 0, 0, errors.New("bot needs a board to play")
+	}
+
+	apply := func(m msboard.Move) (msboard.Status, error) {
+		if nil != made {
+			made(m)
+		}
+		return msengine.Apply(b, m)
+	}
+
+	if status, err = apply(msboard.Move{Type: msboard.MoveReveal, Location: first}); err != nil {
+		return status, 0, 0, err
+	}
+	clicks = 1
+
+	for status == msboard.StatusPlaying {
+		moves, guess := p.next(b.Snapshot())
+		if len(moves) == 0 {
+			return status, clicks, guesses, errors.New("bot found no move to make")
+		}
+		if guess {
+			guesses++
+		}
+		for _, m := range moves {
+			if status, err = apply(m); err != nil {
+				return status, clicks, guesses, err
+			}
+			clicks++
+			if status != msboard.StatusPlaying {
+				break
+			}
+		}
+	}
+	return status, clicks, guesses, nil
+}
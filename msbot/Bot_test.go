@@ -0,0 +1,100 @@
+package msbot
+
+import (
+	"go-mines/msboard"
+	"go-mines/mssolver"
+	"math/rand"
+	"testing"
+)
+
+func TestNext(t *testing.T) {
+	var cases = []struct {
+		layout string
+		want   []msboard.Location
+	}{
+		// deduced safe cells come first, all of them
+		{"*.*/121/___/...", []msboard.Location{msboard.NewLocation(0, 1), msboard.NewLocation(3, 0),
+			msboard.NewLocation(3, 1), msboard.NewLocation(3, 2)}},
+		{"*.*/121/___", []msboard.Location{msboard.NewLocation(0, 1)}},
+		// otherwise the least likely mine: one of three cells by the 1, against 1 in 4 for the interior
+		{"1*../....", []msboard.Location{msboard.NewLocation(0, 2)}},
+	}
+
+	for _, testcase := range cases {
+		b, _ := msboard.ParseLayout(testcase.layout)
+		moves := Player{}.Next(b.Snapshot())
+		if len(moves) != len(testcase.want) {
+			t.Errorf("Next(%q) wanted %v got %v", testcase.layout, testcase.want, moves)
+			continue
+		}
+		for i, m := range moves {
+			if m.Type != msboard.MoveReveal || m.Location != testcase.want[i] {
+				t.Errorf("Next(%q) wanted reveals of %v got %v", testcase.layout, testcase.want, moves)
+			}
+		}
+	}
+}
+
+func TestNextGameOver(t *testing.T) {
+	b, _ := msboard.ParseLayout("*./..")
+	b.Click(msboard.NewLocation(0, 0))
+	if moves := (Player{}).Next(b.Snapshot()); len(moves) != 0 {
+		t.Errorf("Next on a lost game wanted no moves got %v", moves)
+	}
+}
+
+func TestPlay(t *testing.T) {
+	wins := 0
+	for seed := int64(1); seed <= 50; seed++ {
+		rand.Seed(seed)
+		b := msboard.NewBoard("easy")
+		status, clicks, err := Player{Solver: mssolver.Counting{}}.Play(b, msboard.NewLocation(4, 4))
+		if err != nil {
+			t.Fatalf("seed %d: Play failed: %s", seed, err)
+		}
+		if status != msboard.StatusWon && status != msboard.StatusLost || status != b.Status() {
+			t.Errorf("seed %d: Play ended with status %v, board says %v", seed, status, b.Status())
+		}
+		if clicks < 1 {
+			t.Errorf("seed %d: Play reported %d clicks", seed, clicks)
+		}
+		if status == msboard.StatusWon {
+			wins++
+		}
+	}
+
+	// a solver playing easy boards should win most of them
+	if wins < 30 {
+		t.Errorf("bot won only %d of 50 easy games", wins)
+	}
+
+	if _, _, err := (Player{}).Play(nil, msboard.NewLocation(0, 0)); err == nil {
+		t.Errorf("Play without a board should fail")
+	}
+}
+
+func TestPlayCounted(t *testing.T) {
+	// the first click opens everything but the mine
+	b, _ := msboard.ParseLayout("*../.../...")
+	if status, clicks, guesses, err := (Player{}).PlayCounted(b, msboard.NewLocation(2, 2)); err != nil ||
+		status != msboard.StatusWon || clicks != 1 || guesses != 0 {
+		t.Errorf("open board wanted a win in 1 click and no guesses got %v, %d, %d, %v", status, clicks, guesses, err)
+	}
+
+	// the 1 at B2 leaves nothing to deduce, so the next click is a guess
+	b, _ = msboard.ParseLayout("*./..")
+	status, clicks, guesses, err := (Player{}).PlayCounted(b, msboard.NewLocation(1, 1))
+	if err != nil || guesses < 1 || guesses >= clicks {
+		t.Errorf("guessing board got %v in %d clicks with %d guesses, %v", status, clicks, guesses, err)
+	}
+
+	// a game is only lost on a guess
+	for seed := int64(1); seed <= 20; seed++ {
+		rand.Seed(seed)
+		b := msboard.NewBoard("easy")
+		status, _, guesses, err := (Player{}).PlayCounted(b, msboard.NewLocation(4, 4))
+		if err != nil || status == msboard.StatusLost && guesses == 0 {
+			t.Errorf("seed %d: lost with no guesses, %v", seed, err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+/*
+
+	Policy.go - how the bot picks a cell when it has to guess
+
+	Lowest risk alone isn't always the best guess. A cell whose score will tell a lot about its neighbours can be
+	worth a little more risk, and so can a corner, which opens a region more often than other cells and is then
+	easier to follow up.
+
+	mike@pocomotech.com
+
+*/
+
+package msbot
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"math"
+	"sort"
+)
+
+// DefaultSlack : extra risk the built in policies accept over the lowest available to get a better cell
+const DefaultSlack = 0.02
+
+// GuessPolicy : picks the cell to reveal when nothing is certainly safe, given every cell's chance of holding a
+// mine indexed [row][col]. Only hidden cells may be picked; false if there are none
+type GuessPolicy interface {
+	Guess(s msboard.Snapshot, probabilities [][]float64) (msboard.Location, bool)
+}
+
+// LowestRisk : the hidden cell least likely to hold a mine, the first in reading order on ties
+type LowestRisk struct{}
+
+// InformationGain : among the hidden cells within Slack of the lowest risk, the one whose reveal is expected to
+// tell the most, i.e. the chance it's safe times the entropy of the score it would show. Neighbours are taken as
+// independent, so the entropy is an estimate
+type InformationGain struct {
+	Slack float64 // extra risk accepted for more information; 0 only breaks ties
+}
+
+// CornerPreference : among the hidden cells within Slack of the lowest risk, the one with the fewest neighbours
+// on the board, so corners before edges before the rest
+type CornerPreference struct {
+	Slack float64 // extra risk accepted for a corner or edge; 0 only breaks ties
+}
+
+// Policies -- the built in guess policies by name, as a new map each call
+func Policies() map[string]GuessPolicy {
+	return map[string]GuessPolicy{
+		"lowest":   LowestRisk{},
+		"infogain": InformationGain{Slack: DefaultSlack},
+		"corner":   CornerPreference{Slack: DefaultSlack},
+	}
+}
+
+// PolicyNames -- names of the built in guess policies, sorted
+func PolicyNames() []string {
+	retval := make([]string, 0, 3)
+	for name := range Policies() {
+		retval = append(retval, name)
+	}
+	sort.Strings(retval)
+	return retval
+}
+
+// LookupPolicy -- the built in guess policy with a name
+func LookupPolicy(name string) (GuessPolicy, error) {
+	if p, ok := Policies()[name]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("no guess policy named %q", name)
+}
+
+// Guess -- the hidden cell with the lowest risk
+func (LowestRisk) Guess(s msboard.Snapshot, probabilities [][]float64) (msboard.Location, bool) {
+	return best(s, probabilities, 0, func(l msboard.Location) float64 { return 0 })
+}
+
+// Guess -- the hidden cell near the lowest risk with the most expected information
+func (ig InformationGain) Guess(s msboard.Snapshot, probabilities [][]float64) (msboard.Location, bool) {
+	return best(s, probabilities, ig.Slack, func(l msboard.Location) float64 {
+		return (1 - probabilities[l.Row()][l.Col()]) * scoreEntropy(s, probabilities, l)
+	})
+}
+
+// Guess -- the hidden cell near the lowest risk with the fewest neighbours
+func (cp CornerPreference) Guess(s msboard.Snapshot, probabilities [][]float64) (msboard.Location, bool) {
+	return best(s, probabilities, cp.Slack, func(l msboard.Location) float64 {
+		return -float64(len(neighbors(s, l)))
+	})
+}
+
+// best -- the hidden cell with the highest value among those within slack of the lowest risk, ties going to the
+// lower risk and then the first in reading order
+func best(s msboard.Snapshot, probabilities [][]float64, slack float64,
+	value func(l msboard.Location) float64) (msboard.Location, bool) {
+	lowest := 2.0
+	for row := 0; row < s.Rows; row++ {
+		for col := 0; col < s.Cols; col++ {
+			if view, _ := s.Cell(msboard.NewLocation(row, col)); view.State == msboard.CellHidden {
+				lowest = math.Min(lowest, probabilities[row][col])
+			}
+		}
+	}
+	if lowest > 1 {
+		return msboard.Location{}, false
+	}
+
+	retval, bestValue, bestRisk := msboard.NewLocation(-1, -1), math.Inf(-1), 2.0
+	for row := 0; row < s.Rows; row++ {
+		for col := 0; col < s.Cols; col++ {
+			l := msboard.NewLocation(row, col)
+			risk := probabilities[row][col]
+			if view, _ := s.Cell(l); view.State != msboard.CellHidden || risk > lowest+slack {
+				continue
+			}
+			if v := value(l); v > bestValue || v == bestValue && risk < bestRisk {
+				retval, bestValue, bestRisk = l, v, risk
+			}
+		}
+	}
+	return retval, true
+}
+
+// scoreEntropy -- entropy in bits of the score a cell would show, from its neighbours' chances of holding a mine
+func scoreEntropy(s msboard.Snapshot, probabilities [][]float64, l msboard.Location) float64 {
+	scores := []float64{1}
+	for _, n := range neighbors(s, l) {
+		p := probabilities[n.Row()][n.Col()]
+		next := make([]float64, len(scores)+1)
+		for k, q := range scores {
+			next[k] += q * (1 - p)
+			next[k+1] += q * p
+		}
+		scores = next
+	}
+
+	retval := 0.0
+	for _, q := range scores {
+		if q > 0 {
+			retval -= q * math.Log2(q)
+		}
+	}
+	return retval
+}
+
+// neighbors -- on-board locations around l in the snapshot's topology
+func neighbors(s msboard.Snapshot, l msboard.Location) []msboard.Location {
+	return s.Neighbors(l)
+}
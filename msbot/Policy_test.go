@@ -0,0 +1,60 @@
+package msbot
+
+import (
+	"go-mines/msboard"
+	"testing"
+)
+
+func TestPolicies(t *testing.T) {
+	b, _ := msboard.ParseLayout("..../..../....")
+	s := b.Snapshot()
+	probabilities := make([][]float64, s.Rows)
+	for row := range probabilities {
+		probabilities[row] = []float64{0.2, 0.2, 0.2, 0.2}
+	}
+	probabilities[0][0] = 0.19
+
+	var cases = []struct {
+		policy GuessPolicy
+		want   msboard.Location
+	}{
+		{LowestRisk{}, msboard.NewLocation(0, 0)},
+		{InformationGain{}, msboard.NewLocation(0, 0)},
+		// a little more risk buys a cell with eight neighbours, none of them the slightly safer A1
+		{InformationGain{Slack: DefaultSlack}, msboard.NewLocation(1, 2)},
+		{CornerPreference{Slack: DefaultSlack}, msboard.NewLocation(0, 0)},
+	}
+	for _, testcase := range cases {
+		if got, ok := testcase.policy.Guess(s, probabilities); !ok || got != testcase.want {
+			t.Errorf("%T wanted %v got %v, %v", testcase.policy, testcase.want, got, ok)
+		}
+	}
+
+	// the lowest risk is in the middle, but a corner is close enough
+	probabilities[0][0], probabilities[1][1] = 0.2, 0.19
+	if got, _ := (CornerPreference{Slack: DefaultSlack}).Guess(s, probabilities); got != msboard.NewLocation(0, 0) {
+		t.Errorf("CornerPreference wanted A1 got %v", got)
+	}
+	if got, _ := (CornerPreference{}).Guess(s, probabilities); got != msboard.NewLocation(1, 1) {
+		t.Errorf("CornerPreference without slack wanted B2 got %v", got)
+	}
+
+	b.Click(msboard.NewLocation(0, 0))
+	if _, ok := (LowestRisk{}).Guess(b.Snapshot(), probabilities); ok {
+		t.Errorf("Guess with nothing hidden should fail")
+	}
+}
+
+func TestLookupPolicy(t *testing.T) {
+	for _, name := range PolicyNames() {
+		if _, err := LookupPolicy(name); err != nil {
+			t.Errorf("LookupPolicy(%q) failed: %s", name, err)
+		}
+	}
+	if len(PolicyNames()) != 3 {
+		t.Errorf("wanted 3 built in policies got %v", PolicyNames())
+	}
+	if _, err := LookupPolicy("psychic"); err == nil {
+		t.Errorf("LookupPolicy of an unknown name should fail")
+	}
+}
@@ -0,0 +1,115 @@
+/*
+
+	Skill.go - computer opponents of different strengths, for racing against people
+
+	mike@pocomotech.com
+
+*/
+
+package msbot
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/mssolver"
+	"math/rand"
+	"time"
+)
+
+// Skill : how well a computer opponent plays
+type Skill int
+
+// Skill levels, weakest first
+const (
+	SkillRandom Skill = iota // clicks hidden cells at random
+	SkillBasic               // single score deductions, random guesses
+	SkillSolver              // every deduction the frontier allows, random guesses
+	SkillExpert              // every deduction, and guesses at the cell least likely to hold a mine
+)
+
+var skillNames = [...]string{"random", "basic", "solver", "expert"}
+
+// skillPaces -- typical time each skill takes over a click; the stronger players are quicker, as people are
+var skillPaces = [...]time.Duration{time.Second, 2 * time.Second, 1500 * time.Millisecond, time.Second}
+
+// String -- skill name, as taken by ParseSkill
+func (s Skill) String() string {
+	if s < 0 || int(s) >= len(skillNames) {
+		return "unknown"
+	}
+	return skillNames[s]
+}
+
+// ParseSkill -- the skill level with a name
+func ParseSkill(name string) (Skill, error) {
+	for i, n := range skillNames {
+		if n == name {
+			return Skill(i), nil
+		}
+	}
+	return SkillRandom, fmt.Errorf("unknown skill %q, choose random, basic, solver or expert", name)
+}
+
+// Player -- a player of this skill, making its random choices with rng
+func (s Skill) Player(rng *rand.Rand) Player {
+	switch s {
+	case SkillBasic:
+		return Player{Solver: mssolver.Counting{}, Prober: flat{}, Policy: RandomGuess{Rand: rng}}
+	case SkillSolver:
+		return Player{Solver: mssolver.Frontier{}, Prober: flat{}, Policy: RandomGuess{Rand: rng}}
+	case SkillExpert:
+		return Player{Prober: mssolver.Fallback{Exact: mssolver.Endgame{}}}
+	}
+	return Player{Solver: noDeductions{}, Prober: flat{}, Policy: RandomGuess{Rand: rng}}
+}
+
+// Pace -- think time for one click at this skill, between half and one and a half times the skill's typical time
+func (s Skill) Pace(rng *rand.Rand) time.Duration {
+	typical := skillPaces[SkillRandom]
+	if s > 0 && int(s) < len(skillPaces) {
+		typical = skillPaces[s]
+	}
+	return time.Duration((0.5 + rng.Float64()) * float64(typical))
+}
+
+// RandomGuess : any hidden cell, each equally likely, whatever the probabilities
+type RandomGuess struct {
+	Rand *rand.Rand
+}
+
+// Guess -- a hidden cell chosen at random
+func (rg RandomGuess) Guess(s msboard.Snapshot, probabilities [][]float64) (msboard.Location, bool) {
+	var hidden []msboard.Location
+	for row := 0; row < s.Rows; row++ {
+		for col := 0; col < s.Cols; col++ {
+			l := msboard.NewLocation(row, col)
+			if view, _ := s.Cell(l); view.State == msboard.CellHidden {
+				hidden = append(hidden, l)
+			}
+		}
+	}
+	if len(hidden) == 0 {
+		return msboard.Location{}, false
+	}
+	return hidden[rg.Rand.Intn(len(hidden))], true
+}
+
+// flat : prober for players that guess without probabilities, rating every cell the same
+type flat struct{}
+
+// Probabilities -- zero for every cell
+func (flat) Probabilities(s msboard.Snapshot) ([][]float64, error) {
+	retval := make([][]float64, s.Rows)
+	for row := range retval {
+		retval[row] = make([]float64, s.Cols)
+	}
+	return retval, nil
+}
+
+// noDeductions : solver for players that don't think
+type noDeductions struct{}
+
+// Deductions -- none
+func (noDeductions) Deductions(s msboard.Snapshot) (safe, mines []msboard.Location) {
+	return nil, nil
+}
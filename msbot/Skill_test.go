@@ -0,0 +1,53 @@
+package msbot
+
+import (
+	"go-mines/msboard"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestParseSkill(t *testing.T) {
+	for s := SkillRandom; s <= SkillExpert; s++ {
+		if got, err := ParseSkill(s.String()); err != nil || got != s {
+			t.Errorf("ParseSkill(%q) wanted %d got %d, %v", s.String(), s, got, err)
+		}
+	}
+	if _, err := ParseSkill("grandmaster"); err == nil {
+		t.Errorf("ParseSkill of an unknown name should fail")
+	}
+	if got := Skill(9).String(); got != "unknown" {
+		t.Errorf("out of range skill wanted unknown got %q", got)
+	}
+}
+
+func TestSkillStrength(t *testing.T) {
+	wins := make([]int, SkillExpert+1)
+	for s := SkillRandom; s <= SkillExpert; s++ {
+		rng := rand.New(rand.NewSource(1))
+		for seed := int64(1); seed <= 100; seed++ {
+			rand.Seed(seed)
+			b := msboard.NewBoard("easy")
+			status, _, err := s.Player(rng).Play(b, msboard.NewLocation(0, 4))
+			if err != nil {
+				t.Fatalf("%v seed %d: Play failed: %s", s, seed, err)
+			}
+			if status == msboard.StatusWon {
+				wins[s]++
+			}
+		}
+		if pace := s.Pace(rng); pace <= 0 || pace > 3*time.Second {
+			t.Errorf("%v paced at %s a click", s, pace)
+		}
+	}
+
+	// each level at least as strong as the one below, and random clicking hopeless
+	if wins[SkillRandom] > 5 {
+		t.Errorf("random clicking won %d of 100 easy games", wins[SkillRandom])
+	}
+	for s := SkillBasic; s <= SkillExpert; s++ {
+		if wins[s] < wins[s-1] {
+			t.Errorf("%v won %d of 100 easy games, %v %d", s, wins[s], s-1, wins[s-1])
+		}
+	}
+}
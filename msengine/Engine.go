@@ -0,0 +1,135 @@
+/*
+
+	Engine.go - stable public API for embedding the Go Minesweeper engine
+
+	mike@pocomotech.com
+
+*/
+
+// Package msengine -- headless Minesweeper engine API. This package is the supported surface for downstream
+// projects: it only depends on the board model, never on console, TUI or server code, and follows semantic
+// versioning (see Version) so breaking changes are signalled by a major version bump.
+package msengine
+
+import (
+	"fmt"
+	"go-mines/msboard"
+)
+
+// Version : semantic version of the public engine API
+const Version = "0.4.0"
+
+// Location : zero-based cell location, {0,0} is upper left
+type Location = msboard.Location
+
+// Move : a single player action at a board location
+type Move = msboard.Move
+
+// MoveType : kind of player action
+type MoveType = msboard.MoveType
+
+// Supported player actions
+const (
+	MoveReveal    = msboard.MoveReveal
+	MoveFlag      = msboard.MoveFlag
+	MoveChord     = msboard.MoveChord
+	MoveSurrender = msboard.MoveSurrender
+)
+
+// Rules : the decisions of play a variant can change, see msboard.Rules
+type Rules = msboard.Rules
+
+// Status : overall state of play for a board
+type Status = msboard.Status
+
+// Board status values
+const (
+	StatusUninitialized = msboard.StatusUninitialized
+	StatusPlaying       = msboard.StatusPlaying
+	StatusWon           = msboard.StatusWon
+	StatusLost          = msboard.StatusLost
+)
+
+// Snapshot : player-visible copy of board state, never exposes hidden mines
+type Snapshot = msboard.Snapshot
+
+// SnapshotDelta : changed cells and status transition between two snapshots
+type SnapshotDelta = msboard.SnapshotDelta
+
+// CellChange : a single cell whose visible state differs between two snapshots
+type CellChange = msboard.CellChange
+
+// CellView : player-visible view of one cell in a Snapshot
+type CellView = msboard.CellView
+
+// CellState : player-visible state of a single cell
+type CellState = msboard.CellState
+
+// Cell states as seen by the player
+const (
+	CellHidden   = msboard.CellHidden
+	CellFlagged  = msboard.CellFlagged
+	CellRevealed = msboard.CellRevealed
+	CellMine     = msboard.CellMine
+)
+
+// Board : a playable Minesweeper board. The first reveal should go through FirstClick so the mine layout is
+// generated around the player's guaranteed-safe starting cell as it is revealed
+type Board interface {
+	Initialize(safespot Location) error
+	FirstClick(l Location) ([]Location, error)
+	Initialized() bool
+	Click(l Location) []Location
+	ToggleFlag(l Location)
+	Apply(m Move) ([]Location, Status, error)
+	ValidLocation(l Location) bool
+	SafeRemaining() int
+	Status() Status
+	Snapshot() Snapshot
+	SetRules(rules Rules)
+	Rules() Rules
+}
+
+// Solver : strategy that derives certain moves from the player-visible state of a board
+type Solver interface {
+	// Deductions returns locations that are provably safe and provably mined for the given position
+	Deductions(s Snapshot) (safe, mines []Location)
+}
+
+// compile time check that the board model satisfies the public interface
+var _ Board = (*msboard.Board)(nil)
+
+// NewLocation -- create a Location from zero-based row and column
+func NewLocation(row, col int) Location {
+	return msboard.NewLocation(row, col)
+}
+
+// SnapshotDiff -- report the cells and status that changed from snapshot a to snapshot b
+func SnapshotDiff(a, b Snapshot) SnapshotDelta {
+	return msboard.SnapshotDiff(a, b)
+}
+
+// NewBoard -- allocate a new, uninitialized board. Supported difficulties are "easy", "medium" and "hard"
+func NewBoard(difficulty string) (Board, error) {
+	b := msboard.NewBoard(difficulty)
+	if nil == b {
+		return nil, fmt.Errorf("unsupported board difficulty %q", difficulty)
+	}
+	return b, nil
+}
+
+// LookupRules -- the rules of play with a name, see msboard.RulesNames; the empty name is the classic rules
+func LookupRules(name string) (Rules, error) {
+	return msboard.LookupRules(name)
+}
+
+// Apply -- apply a Move to a board, see Board.Apply. A first reveal on an uninitialized board goes through
+// FirstClick, the only move that lays the mines out; flags and other moves before it are refused, as are moves
+// after the game is won or lost
+func Apply(b Board, m Move) (Status, error) {
+	if nil == b {
+		return StatusUninitialized, fmt.Errorf("Apply() called with nil board")
+	}
+	_, _, err := b.Apply(m)
+	return b.Status(), err
+}
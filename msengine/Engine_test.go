@@ -0,0 +1,71 @@
+/*
+	Test functions for the public engine API
+
+	mike@pocomotech.com
+*/
+
+package msengine
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestNewBoard(t *testing.T) {
+	var cases = []struct {
+		difficulty string
+		want       bool
+	}{
+		{"easy", true},
+		{"medium", true},
+		{"hard", true},
+		{"nightmare", false},
+	}
+
+	for _, testcase := range cases {
+		b, err := NewBoard(testcase.difficulty)
+		if (err == nil) != testcase.want || (b != nil) != testcase.want {
+			t.Errorf("NewBoard(%q) got board %v err %v", testcase.difficulty, b, err)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	rand.Seed(1995)
+	b, _ := NewBoard("easy")
+
+	start := NewLocation(4, 4)
+	status, err := Apply(b, Move{Type: MoveReveal, Location: start})
+	if err != nil {
+		t.Fatalf("Apply() of first move failed: %s", err)
+	}
+	if status != StatusPlaying && status != StatusWon {
+		t.Errorf("Apply() of guaranteed safe first move returned status %v", status)
+	}
+	if view, _ := b.Snapshot().Cell(start); view.State != CellRevealed {
+		t.Errorf("Apply() did not reveal starting cell, got state %v", view.State)
+	}
+
+	if _, err = Apply(b, Move{Type: MoveFlag, Location: NewLocation(-1, 3)}); err == nil {
+		t.Errorf("Apply() accepted an off-board location")
+	}
+}
+
+func TestUninitializedBoard(t *testing.T) {
+	// nothing but the first reveal touches a board before its mines are laid out
+	b, _ := NewBoard("easy")
+	l := NewLocation(4, 4)
+	if revealed := b.Click(l); len(revealed) != 0 {
+		t.Errorf("Click on an uninitialized board revealed %v", revealed)
+	}
+	b.ToggleFlag(l)
+	if s := b.Snapshot(); s.Flags != 0 || s.Status != StatusUninitialized || b.Initialized() {
+		t.Errorf("ToggleFlag on an uninitialized board left %d flags, %v", s.Flags, s.Status)
+	}
+	if _, err := Apply(b, Move{Type: MoveFlag, Location: l}); nil == err {
+		t.Errorf("Apply flagged an uninitialized board")
+	}
+	if status, err := Apply(b, Move{Type: MoveReveal, Location: l}); err != nil || status == StatusLost {
+		t.Errorf("first reveal after the refused moves got %v, %v", status, err)
+	}
+}
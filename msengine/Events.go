@@ -0,0 +1,79 @@
+/*
+
+	Events.go - curated callbacks for front ends that play sounds or animations as moves land
+
+	mike@pocomotech.com
+
+*/
+
+package msengine
+
+// EngineEvents : front end hooks called by ApplyWithEvents once a move has been applied. For a single move the
+// calls always come in this order:
+//
+//	OnReveal   once per newly revealed safe cell, in reading order (top row first, left to right)
+//	OnFlag     once if a flag was placed or removed
+//	OnExplode  once if the move revealed a mine, at that mine; a chord setting off more than one reports the first
+//	           in reading order
+//	OnWin      once if the move won the game
+//
+// Moves that change nothing produce no calls
+type EngineEvents interface {
+	OnReveal(l Location, v CellView)
+	OnFlag(l Location, flagged bool)
+	OnExplode(l Location)
+	OnWin()
+}
+
+// NoEvents : EngineEvents that ignore everything, for embedding in front ends that only want some of the hooks
+type NoEvents struct{}
+
+// OnReveal -- ignored
+func (NoEvents) OnReveal(l Location, v CellView) {}
+
+// OnFlag -- ignored
+func (NoEvents) OnFlag(l Location, flagged bool) {}
+
+// OnExplode -- ignored
+func (NoEvents) OnExplode(l Location) {}
+
+// OnWin -- ignored
+func (NoEvents) OnWin() {}
+
+// ApplyWithEvents -- Apply a move, then report what it did through ev
+func ApplyWithEvents(b Board, m Move, ev EngineEvents) (Status, error) {
+	if nil == b {
+		return Apply(b, m)
+	}
+
+	before := b.Snapshot()
+	status, err := Apply(b, m)
+	if err != nil || nil == ev {
+		return status, err
+	}
+	delta := SnapshotDiff(before, b.Snapshot())
+
+	var flagged, exploded []CellChange
+	for _, change := range delta.Cells {
+		switch {
+		case change.To.State == CellRevealed:
+			ev.OnReveal(change.Location, change.To)
+		case change.To.State == CellMine:
+			exploded = append(exploded, change)
+		case change.From.State == CellFlagged || change.To.State == CellFlagged:
+			flagged = append(flagged, change)
+		}
+	}
+	for _, change := range flagged {
+		ev.OnFlag(change.Location, change.To.State == CellFlagged)
+	}
+	// giving up shows the mines without setting one off
+	if len(exploded) > 0 && m.Type != MoveSurrender {
+		ev.OnExplode(exploded[0].Location)
+	}
+	if delta.StatusChanged() && delta.ToStatus == StatusWon {
+		ev.OnWin()
+	}
+
+	return status, nil
+}
@@ -0,0 +1,95 @@
+package msengine
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"reflect"
+	"testing"
+)
+
+// eventLog : EngineEvents that records every call
+type eventLog struct {
+	calls []string
+}
+
+func (e *eventLog) OnReveal(l Location, v CellView) {
+	e.calls = append(e.calls, fmt.Sprintf("reveal %d,%d %d", l.Row(), l.Col(), v.Score))
+}
+func (e *eventLog) OnFlag(l Location, flagged bool) {
+	e.calls = append(e.calls, fmt.Sprintf("flag %d,%d %v", l.Row(), l.Col(), flagged))
+}
+func (e *eventLog) OnExplode(l Location) {
+	e.calls = append(e.calls, fmt.Sprintf("explode %d,%d", l.Row(), l.Col()))
+}
+func (e *eventLog) OnWin() { e.calls = append(e.calls, "win") }
+
+// winningBoard : wraps a board, reporting a win once the watched cell is revealed
+type winningBoard struct {
+	*msboard.Board
+	last Location
+}
+
+func (w winningBoard) Status() Status {
+	if view, _ := w.Board.Snapshot().Cell(w.last); view.State == CellRevealed {
+		return StatusWon
+	}
+	return w.Board.Status()
+}
+
+func (w winningBoard) Snapshot() Snapshot {
+	retval := w.Board.Snapshot()
+	retval.Status = w.Status()
+	return retval
+}
+
+func reveal(l Location) Move { return Move{Type: MoveReveal, Location: l} }
+func flag(l Location) Move   { return Move{Type: MoveFlag, Location: l} }
+
+func TestApplyWithEvents(t *testing.T) {
+	var cases = []struct {
+		moves []Move
+		want  []string
+	}{
+		{[]Move{reveal(NewLocation(0, 0))},
+			[]string{"reveal 0,0 0", "reveal 0,1 1", "reveal 1,0 0", "reveal 1,1 1"}},
+		{[]Move{flag(NewLocation(0, 2)), flag(NewLocation(0, 2))},
+			[]string{"flag 0,2 true", "flag 0,2 false"}},
+		{[]Move{flag(NewLocation(0, 2)), reveal(NewLocation(0, 2))},
+			[]string{"flag 0,2 true"}},
+		{[]Move{reveal(NewLocation(0, 2))},
+			[]string{"explode 0,2"}},
+		{[]Move{reveal(NewLocation(0, 0)), reveal(NewLocation(1, 2))},
+			[]string{"reveal 0,0 0", "reveal 0,1 1", "reveal 1,0 0", "reveal 1,1 1", "reveal 1,2 1", "win"}},
+		// giving up shows the mine without setting it off
+		{[]Move{flag(NewLocation(0, 2)), {Type: MoveSurrender}},
+			[]string{"flag 0,2 true"}},
+	}
+
+	for _, testcase := range cases {
+		b, _ := msboard.ParseLayout("..*/...")
+		board := winningBoard{b, NewLocation(1, 2)}
+		log := new(eventLog)
+		for _, m := range testcase.moves {
+			if _, err := ApplyWithEvents(board, m, log); err != nil {
+				t.Fatalf("ApplyWithEvents(%v) failed: %s", m, err)
+			}
+		}
+		if !reflect.DeepEqual(log.calls, testcase.want) {
+			t.Errorf("ApplyWithEvents(%v) wanted %v got %v", testcase.moves, testcase.want, log.calls)
+		}
+	}
+
+	// a chord around a wrong flag sets off the mine beside it, not the cell chorded
+	b, _ := msboard.ParseLayout("f1*/...")
+	b.SetRules(msboard.ChordRules{})
+	log := new(eventLog)
+	if _, err := ApplyWithEvents(b, Move{Type: MoveChord, Location: NewLocation(0, 1)}, log); err != nil {
+		t.Fatalf("ApplyWithEvents(chord) failed: %s", err)
+	}
+	if want := "explode 0,2"; len(log.calls) == 0 || log.calls[len(log.calls)-1] != want {
+		t.Errorf("chord into a mine wanted %q last got %v", want, log.calls)
+	}
+
+	// front ends can embed NoEvents and override only what they need
+	var _ EngineEvents = struct{ NoEvents }{}
+}
@@ -0,0 +1,180 @@
+/*
+
+	Format.go - the header shared by save files, replays and network messages, so a reader can tell whether it
+	understands what it's been given before trying to play it
+
+	mike@pocomotech.com
+
+*/
+
+package msengine
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Header : what a stored or transmitted game needs from the engine reading it. The zero Header stands for data
+// written before headers existed: a classic game on a grid with no rule changes
+type Header struct {
+	Engine   string   `json:"engine"`          // Version of the engine that wrote the data
+	Variant  string   `json:"variant"`         // name of the board's msboard.Rules, "classic" if empty
+	Topology string   `json:"topology"`        // board shape and neighborhoods, "grid" if empty; see msboard.RegisterTopology
+	Rules    []string `json:"rules,omitempty"` // optional rule changes, all of which the reader must support
+}
+
+// Header defaults for fields left empty
+const (
+	DefaultVariant  = "classic"
+	DefaultTopology = msboard.GridTopology
+)
+
+// Rule changes a header can name, besides the variant
+const (
+	RuleZerosOnly = "zeros-only" // cascades stop at numbered cells, see msboard.PropagateZerosOnly
+	RuleAntiMines = "anti-mines" // some mines count -1, so scores can be negative; the layout holds them
+	RuleFog       = "fog"        // only cells near revealed ones can be clicked; the data holds the radius
+)
+
+// rule changes this engine can play; variants are the rules msboard.LookupRules knows, topologies whatever msboard
+// has registered
+var supportedRules = map[string]bool{RuleZerosOnly: true, RuleAntiMines: true, RuleFog: true}
+
+// CurrentHeader -- header for data written by this engine
+func CurrentHeader() Header {
+	return Header{Engine: Version, Variant: DefaultVariant, Topology: DefaultTopology}
+}
+
+// HeaderFor -- header for data written by this engine about a board, naming its topology, the rules it's played
+// by and every rule change that would make a reader playing it the classic way go wrong
+func HeaderFor(b *msboard.Board) Header {
+	retval := CurrentHeader()
+	retval.Topology = b.Topology()
+	retval.Variant = b.Rules().Name()
+	if b.Propagation() == msboard.PropagateZerosOnly {
+		retval.Rules = append(retval.Rules, RuleZerosOnly)
+	}
+	if b.AntiMineCount() > 0 {
+		retval.Rules = append(retval.Rules, RuleAntiMines)
+	}
+	if b.Fog() > 0 {
+		retval.Rules = append(retval.Rules, RuleFog)
+	}
+	return retval.normalized()
+}
+
+// Configure -- set a board up to be played as the header says: by the rules of its variant, with its rule changes.
+// The header must have passed Check
+func (h Header) Configure(b *msboard.Board) error {
+	h = h.normalized()
+	rules, err := msboard.LookupRules(h.Variant)
+	if err != nil {
+		return err
+	}
+	b.SetRules(rules)
+	for _, rule := range h.Rules {
+		if rule == RuleZerosOnly {
+			b.SetPropagation(msboard.PropagateZerosOnly)
+		}
+	}
+	return nil
+}
+
+// normalized -- header with defaults filled in and rules sorted
+func (h Header) normalized() Header {
+	if h.Variant == "" {
+		h.Variant = DefaultVariant
+	}
+	if h.Topology == "" {
+		h.Topology = DefaultTopology
+	}
+	h.Rules = append([]string(nil), h.Rules...)
+	sort.Strings(h.Rules)
+	return h
+}
+
+// Check -- error unless this engine can play data with the header. Data from any engine with the same major
+// version is accepted as long as every variant, topology and rule it names is supported
+func (h Header) Check() error {
+	h = h.normalized()
+	if h.Engine != "" {
+		major, _, err := parseVersion(h.Engine)
+		if err != nil {
+			return err
+		}
+		if ours, _, _ := parseVersion(Version); major != ours {
+			return fmt.Errorf("written by engine %s, this engine is %s", h.Engine, Version)
+		}
+	}
+
+	if _, err := msboard.LookupRules(h.Variant); err != nil {
+		return fmt.Errorf("unsupported game variant %q", h.Variant)
+	}
+	if _, err := msboard.LookupTopology(h.Topology); err != nil {
+		return fmt.Errorf("unsupported board topology %q", h.Topology)
+	}
+	for _, rule := range h.Rules {
+		if !supportedRules[rule] {
+			return fmt.Errorf("unsupported rule %q", rule)
+		}
+	}
+	return nil
+}
+
+// Negotiate -- agree the header for a game between two engines, e.g. a client and server. Both sides must be able
+// to play it and must ask for the same variant, topology and rules; the agreed engine version is the older of
+// the two
+func Negotiate(local, remote Header) (Header, error) {
+	local, remote = local.normalized(), remote.normalized()
+	if err := local.Check(); err != nil {
+		return Header{}, fmt.Errorf("local: %s", err)
+	}
+	if err := remote.Check(); err != nil {
+		return Header{}, fmt.Errorf("remote: %s", err)
+	}
+
+	if local.Variant != remote.Variant || local.Topology != remote.Topology {
+		return Header{}, fmt.Errorf("game mismatch: %s/%s here, %s/%s remote",
+			local.Variant, local.Topology, remote.Variant, remote.Topology)
+	}
+	if strings.Join(local.Rules, ",") != strings.Join(remote.Rules, ",") {
+		return Header{}, fmt.Errorf("rule mismatch: %v here, %v remote", local.Rules, remote.Rules)
+	}
+
+	retval := local
+	if remote.Engine == "" || (local.Engine != "" && olderVersion(remote.Engine, local.Engine)) {
+		retval.Engine = remote.Engine
+	}
+	return retval, nil
+}
+
+// parseVersion -- major and minor numbers of a "major.minor.patch" version
+func parseVersion(v string) (major, minor int, err error) {
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return 0, 0, fmt.Errorf("malformed engine version %q", v)
+	}
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		if numbers[i], err = strconv.Atoi(part); err != nil || numbers[i] < 0 {
+			return 0, 0, fmt.Errorf("malformed engine version %q", v)
+		}
+	}
+	return numbers[0], numbers[1], nil
+}
+
+// olderVersion -- true if version a precedes version b. Both must already have passed parseVersion
+func olderVersion(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range as {
+		an, _ := strconv.Atoi(as[i])
+		bn, _ := strconv.Atoi(bs[i])
+		if an != bn {
+			return an < bn
+		}
+	}
+	return false
+}
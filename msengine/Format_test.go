@@ -0,0 +1,106 @@
+package msengine
+
+import (
+	"go-mines/msboard"
+	"reflect"
+	"testing"
+)
+
+func TestHeaderCheck(t *testing.T) {
+	var cases = []struct {
+		h     Header
+		valid bool
+	}{
+		{CurrentHeader(), true},
+		{Header{}, true}, // written before headers existed
+		{Header{Engine: "0.99.3"}, true},
+		{Header{Engine: "1.0.0"}, false},
+		{Header{Engine: "zero"}, false},
+		{Header{Engine: Version, Variant: "battleship"}, false},
+		{Header{Engine: Version, Topology: "hex"}, false},
+		{Header{Engine: Version, Rules: []string{"moving-mines"}}, false},
+	}
+
+	for _, testcase := range cases {
+		if err := testcase.h.Check(); (err == nil) != testcase.valid {
+			t.Errorf("Check(%+v) wanted valid %v got err %v", testcase.h, testcase.valid, err)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	older := Header{Engine: "0.0.9"}
+	got, err := Negotiate(CurrentHeader(), older)
+	want := Header{Engine: "0.0.9", Variant: DefaultVariant, Topology: DefaultTopology}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Negotiate with an older engine wanted %+v got %+v err %v", want, got, err)
+	}
+
+	if got, err = Negotiate(older, CurrentHeader()); err != nil || got.Engine != "0.0.9" {
+		t.Errorf("Negotiate from an older engine wanted 0.0.9 got %+v err %v", got, err)
+	}
+
+	if _, err = Negotiate(CurrentHeader(), Header{Engine: "2.1.0"}); err == nil {
+		t.Errorf("Negotiate accepted an incompatible major version")
+	}
+	if _, err = Negotiate(CurrentHeader(), Header{Engine: Version, Variant: "fog"}); err == nil {
+		t.Errorf("Negotiate accepted an unsupported variant")
+	}
+}
+
+func TestHeaderFor(t *testing.T) {
+	b := msboard.NewBoard("easy")
+	if got := HeaderFor(b); !reflect.DeepEqual(got, CurrentHeader()) {
+		t.Errorf("HeaderFor a classic board wanted %+v got %+v", CurrentHeader(), got)
+	}
+
+	b.SetRules(msboard.ChordRules{})
+	b.SetPropagation(msboard.PropagateZerosOnly)
+	h := HeaderFor(b)
+	if h.Variant != "chord" || !reflect.DeepEqual(h.Rules, []string{RuleZerosOnly}) {
+		t.Fatalf("HeaderFor a variant board wanted chord with zeros-only got %+v", h)
+	}
+	if err := h.Check(); err != nil {
+		t.Errorf("Check refused its own variant: %s", err)
+	}
+
+	played := msboard.NewBoard("easy")
+	if err := h.Configure(played); err != nil {
+		t.Fatalf("Configure failed: %s", err)
+	}
+	if played.Rules().Name() != "chord" || played.Propagation() != msboard.PropagateZerosOnly {
+		t.Errorf("Configure wanted chord with zeros-only got %s with %s", played.Rules().Name(), played.Propagation())
+	}
+
+	// an engine that only plays classic games can't agree to play the variant
+	if _, err := Negotiate(Header{Engine: "0.3.0"}, h); nil == err {
+		t.Errorf("Negotiate agreed to a variant game with a classic header")
+	}
+	// and one that doesn't know a rule change refuses data using it
+	delete(supportedRules, RuleZerosOnly)
+	defer func() { supportedRules[RuleZerosOnly] = true }()
+	if err := h.Check(); nil == err {
+		t.Errorf("Check accepted a rule the engine doesn't support")
+	}
+}
+
+func TestHeaderForAntiMines(t *testing.T) {
+	b, err := msboard.ParseLayout("x../.../...")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+	h := HeaderFor(b)
+	if h.Variant != DefaultVariant || !reflect.DeepEqual(h.Rules, []string{RuleAntiMines}) {
+		t.Fatalf("HeaderFor an anti-mine board wanted classic with anti-mines got %+v", h)
+	}
+	if err := h.Check(); err != nil {
+		t.Errorf("Check refused anti-mines: %s", err)
+	}
+
+	// an engine without anti-mines would score the game wrong, so it refuses it
+	delete(supportedRules, RuleAntiMines)
+	defer func() { supportedRules[RuleAntiMines] = true }()
+	if err := h.Check(); nil == err {
+		t.Errorf("Check accepted anti-mines the engine doesn't support")
+	}
+}
@@ -0,0 +1,97 @@
+/*
+
+	Sounds.go - audio cues for front ends: a Cue for each kind of thing a move can do, played through Sounds by
+	ApplyWithSounds. GUI front ends plug real sounds in; terminals get Bell, which rings the terminal bell when a
+	game ends and is silent otherwise
+
+	mike@pocomotech.com
+
+*/
+
+package msengine
+
+import (
+	"io"
+)
+
+// Cue : something a move did that may deserve a sound
+type Cue int
+
+// Cues, one per kind of EngineEvents call
+const (
+	CueReveal  Cue = iota // safe cells revealed
+	CueFlag               // a flag placed or removed
+	CueExplode            // a mine set off
+	CueWin                // the game won
+)
+
+var cueNames = [...]string{"reveal", "flag", "explode", "win"}
+
+// String -- cue name
+func (c Cue) String() string {
+	if c < 0 || int(c) >= len(cueNames) {
+		return "unknown"
+	}
+	return cueNames[c]
+}
+
+// Sounds : plays audio cues. Play is called on the game's goroutine, so it shouldn't block for long
+type Sounds interface {
+	Play(c Cue)
+}
+
+// Silence : Sounds that play nothing
+type Silence struct{}
+
+// Play -- nothing
+func (Silence) Play(c Cue) {}
+
+// Bell : Sounds for terminals, ringing the bell on W when a mine goes off or the game is won and silent otherwise
+type Bell struct {
+	W io.Writer
+}
+
+// Play -- ring the bell for explosions and wins
+func (b Bell) Play(c Cue) {
+	if c == CueExplode || c == CueWin {
+		b.W.Write([]byte{'\a'})
+	}
+}
+
+// ApplyWithSounds -- Apply a move, then play a cue for each kind of thing it did through s: the reveal cue once
+// however many cells it revealed
+func ApplyWithSounds(b Board, m Move, s Sounds) (Status, error) {
+	if nil == s {
+		return Apply(b, m)
+	}
+	return ApplyWithEvents(b, m, &soundEvents{sounds: s})
+}
+
+// soundEvents : EngineEvents playing the cues of one move
+type soundEvents struct {
+	sounds   Sounds
+	revealed bool
+}
+
+// OnReveal -- the reveal cue, for the move's first cell only
+func (e *soundEvents) OnReveal(l Location, v CellView) {
+	if !e.revealed {
+		e.sounds.Play(CueReveal)
+	}
+	e.revealed = true
+}
+
+// OnFlag -- the flag cue
+func (e *soundEvents) OnFlag(l Location, flagged bool) {
+	e.sounds.Play(CueFlag)
+}
+
+// OnExplode -- the explosion cue
+func (e *soundEvents) OnExplode(l Location) {
+	e.sounds.Play(CueExplode)
+}
+
+// OnWin -- the win cue
+func (e *soundEvents) OnWin() {
+	e.sounds.Play(CueWin)
+}
@@ -0,0 +1,50 @@
+package msengine
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"reflect"
+	"testing"
+)
+
+// cueLog : Sounds that records every cue
+type cueLog []Cue
+
+func (c *cueLog) Play(cue Cue) { *c = append(*c, cue) }
+
+func TestApplyWithSounds(t *testing.T) {
+	b, _ := msboard.ParseLayout("..*/...")
+	board := winningBoard{b, NewLocation(1, 2)}
+	var log cueLog
+	// a flag, a four cell reveal with one cue, a second reveal in a row, and the win
+	for _, m := range []Move{flag(NewLocation(0, 2)), reveal(NewLocation(0, 0)), reveal(NewLocation(1, 2))} {
+		if _, err := ApplyWithSounds(board, m, &log); err != nil {
+			t.Fatalf("ApplyWithSounds(%v) failed: %s", m, err)
+		}
+	}
+	if want := (cueLog{CueFlag, CueReveal, CueReveal, CueWin}); !reflect.DeepEqual(log, want) {
+		t.Errorf("ApplyWithSounds wanted %v got %v", want, log)
+	}
+
+	b, _ = msboard.ParseLayout("..*/...")
+	log = nil
+	ApplyWithSounds(b, reveal(NewLocation(0, 2)), &log)
+	if want := (cueLog{CueExplode}); !reflect.DeepEqual(log, want) {
+		t.Errorf("explosion wanted %v got %v", want, log)
+	}
+}
+
+func TestBell(t *testing.T) {
+	var buf bytes.Buffer
+	bell := Bell{W: &buf}
+	for _, c := range []Cue{CueReveal, CueFlag, CueExplode, CueWin} {
+		bell.Play(c)
+	}
+	Silence{}.Play(CueWin)
+	if buf.String() != "\a\a" {
+		t.Errorf("bell wanted two rings got %q", buf.String())
+	}
+	if CueExplode.String() != "explode" || Cue(9).String() != "unknown" {
+		t.Errorf("cue names wrong: %v, %v", CueExplode, Cue(9))
+	}
+}
@@ -0,0 +1,51 @@
+/*
+
+	Adaptive.go - adaptive games: medium sized boards whose mine count follows the player's recent adaptive games,
+	chosen by a msstats.DensityPolicy and explained before each board
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/msstats"
+	"io"
+	"math"
+)
+
+// Adaptive boards are the medium board's size, starting at its density
+const (
+	adaptiveRows, adaptiveCols = 16, 16
+	adaptiveStartMines         = 30
+)
+
+// SetAdaptive -- offer adaptive games from the main menu, their mines chosen by policy; nil for none
+func (g *Game) SetAdaptive(policy msstats.DensityPolicy) {
+	g.adaptive = policy
+}
+
+// adaptiveBoard -- a new adaptive board, at the density the policy chooses after the player's adaptive games so far,
+// telling the player how many mines it has and why
+func (g *Game) adaptiveBoard(out io.Writer) *msboard.Board {
+	cells := adaptiveRows * adaptiveCols
+	recent := g.playerHistory().Adaptive()
+	current := float64(adaptiveStartMines) / float64(cells)
+	if len(recent) > 0 {
+		current = recent[len(recent)-1].Density
+	}
+
+	density, reason := g.adaptive.Next(recent, current)
+	mines := int(math.Round(density * float64(cells)))
+	if mines < 1 {
+		mines = 1
+	} else if mines > cells-1 {
+		mines = cells - 1
+	}
+	fmt.Fprintf(out, "Adaptive board (%s): %d mines, %.1f%%, %s\n", g.adaptive.Name(), mines,
+		100*float64(mines)/float64(cells), reason)
+	return msboard.NewCustomBoard(adaptiveRows, adaptiveCols, mines)
+}
@@ -0,0 +1,33 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/msstats"
+	"strings"
+	"testing"
+)
+
+func TestAdaptive(t *testing.T) {
+	game := New(1995)
+	game.SetAdaptive(msstats.DefaultChallengeBand())
+
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("a\nh8\n"), out); err != nil {
+		t.Fatalf("adaptive game failed: %s", err)
+	}
+	for _, want := range []string{"[A]daptive", "Adaptive board (win 40%-70% of the last 10): 30 mines, 11.7%, " +
+		"no adaptive games yet"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("adaptive game output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	// two losses make the next board sparser
+	loss := msstats.Record{Difficulty: msstats.AdaptiveDifficulty, Metrics: msstats.Metrics{Density: 30.0 / 256}}
+	game.history = &msstats.History{Games: []msstats.Record{loss, {Difficulty: "easy", Won: true}, loss}}
+	out.Reset()
+	b := game.adaptiveBoard(out)
+	if b.MineCount() != 27 || b.Rows() != 16 || b.Cols() != 16 || !strings.Contains(out.String(), "won 0 of 2") {
+		t.Errorf("adaptive board after two losses wanted 27 mines got %d: %s", b.MineCount(), out.String())
+	}
+}
@@ -0,0 +1,93 @@
+/*
+
+	Analysis.go - analysis games: practice games with unlimited takebacks and peeks at single cells, kept apart from
+	rated play so results, puzzle stats and replays only ever show games played straight
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"errors"
+	"fmt"
+	"go-mines/msboard"
+	"io"
+	"strings"
+)
+
+// SetAnalysis -- play analysis games: as practice games, with bookmarks, plus undo to take back any number of moves
+// and peek to see whether a cell holds a mine. Analysis games aren't kept in the results, puzzle stats or replays
+func (g *Game) SetAnalysis(enabled bool) {
+	g.analysis = enabled
+}
+
+// SetUndoLimit -- let analysis games take back at most limit moves, so long sessions don't hold on to every
+// position they've been through; 0 is no limit
+func (g *Game) SetUndoLimit(limit int) {
+	g.undoLimit = limit
+}
+
+// beforeMove -- remember the board as it is before a move, for undo in analysis games, forgetting the oldest
+// position once there are more than the undo limit
+func (g *Game) beforeMove(board *msboard.Board, p *positions) {
+	if !g.analysis || !board.Initialized() {
+		return
+	}
+	p.undo = append(p.undo, board.Snapshot())
+	if g.undoLimit > 0 && len(p.undo) > g.undoLimit {
+		// the slice runs out of room as it moves along, and append copies just the kept positions to a new one
+		p.undo, p.dropped = p.undo[len(p.undo)-g.undoLimit:], true
+	}
+}
+
+// takeBack -- handle "undo", putting the board back as it was before the last move; the first click can't be
+// taken back, since it lays out the mines
+func (g *Game) takeBack(out io.Writer, board *msboard.Board, p *positions) error {
+	if !g.analysis {
+		return errors.New("moves can only be taken back in analysis games")
+	}
+	if len(p.undo) == 0 {
+		return errors.New("no moves to take back")
+	}
+
+	last := len(p.undo) - 1
+	if err := board.Restore(p.undo[last]); err != nil {
+		return err
+	}
+	p.undo, p.restored = p.undo[:last], true
+	if last == 0 && g.undoLimit > 0 && p.dropped {
+		fmt.Fprintf(out, "Took back a move, no more can be: only the last %d are kept\n", g.undoLimit)
+		return nil
+	}
+	fmt.Fprintf(out, "Took back a move, %d more can be\n", last)
+	return nil
+}
+
+// peek -- handle "peek <loc>", telling the player whether a cell holds a mine without changing the board
+func (g *Game) peek(out io.Writer, board *msboard.Board, args []string) error {
+	if !g.analysis {
+		return errors.New("cells can only be peeked at in analysis games")
+	}
+	if !board.Initialized() {
+		return errors.New("the mines are laid by the first click, peek after it")
+	}
+	l, err := g.parseLocation(strings.Join(args, " "), board)
+	if err != nil {
+		return err
+	}
+	if !board.ValidLocation(l) {
+		return fmt.Errorf("%s is not on the board", g.cellName(l, board))
+	}
+
+	switch {
+	case board.AntiMineAt(l):
+		fmt.Fprintf(out, "%s is an anti-mine\n", g.cellName(l, board))
+	case board.MineAt(l):
+		fmt.Fprintf(out, "%s is a mine\n", g.cellName(l, board))
+	default:
+		fmt.Fprintf(out, "%s is safe\n", g.cellName(l, board))
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/mspuzzle"
+	"strings"
+	"testing"
+)
+
+func TestAnalysis(t *testing.T) {
+	puzzle := mspuzzle.Puzzle{Title: "corner", Layout: "1*1/111/..."}
+	game := New(1995)
+	game.SetPuzzlePacks([]mspuzzle.Pack{{Title: "Analysis", Puzzles: []mspuzzle.Puzzle{puzzle}}})
+	game.SetAnalysis(true)
+
+	// peek, step on the mine, take it back, then flag it and win, finishing at the prompt
+	script := "p\n1\n1\nundo\npeek b1\npeek a3\npeek z9\ns b1\nundo\nf b1\nundo\nundo\nf b1\ns a3\n\nb\nq\n"
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader(script), out); err != nil {
+		t.Fatalf("analysis game failed: %s", err)
+	}
+	for _, want := range []string{"no moves to take back", "B1 is a mine", "A3 is safe", "Z9 is not on the board",
+		"Game lost. Type undo", "Took back a move, 0 more can be", "Game won. Type undo",
+		"Analysis games aren't recorded, reviewed or saved"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("analysis game output missing %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "solved 1 of") || strings.Contains(out.String(), "Move review") {
+		t.Errorf("analysis games shouldn't count towards puzzles or be reviewed:\n%s", out.String())
+	}
+	if len(game.Results()) != 0 || game.puzzleStats().Completion(puzzle).Attempts != 0 {
+		t.Errorf("analysis games shouldn't be recorded, got %+v", game.Results())
+	}
+}
+
+func TestUndoLimit(t *testing.T) {
+	game := New(1995)
+	game.SetPuzzlePacks([]mspuzzle.Pack{{Title: "Analysis", Puzzles: []mspuzzle.Puzzle{{Title: "corner",
+		Layout: "1*1/111/..."}}}})
+	game.SetAnalysis(true)
+	game.SetUndoLimit(2)
+
+	// three moves, of which only the last two can be taken back
+	script := "p\n1\n1\nf b1\nf b1\nf b1\nundo\nundo\nundo\nq\n"
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader(script), out); err != nil {
+		t.Fatalf("analysis game failed: %s", err)
+	}
+	for _, want := range []string{"Took back a move, 1 more can be",
+		"Took back a move, no more can be: only the last 2 are kept", "no moves to take back"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("analysis game output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestAnalysisOnly(t *testing.T) {
+	pack := mspuzzle.Pack{Title: "Puzzles", Puzzles: []mspuzzle.Puzzle{{Title: "corner", Layout: "1*1/111/..."}}}
+	game := New(1995)
+	game.SetPuzzlePacks([]mspuzzle.Pack{pack})
+	game.SetPractice(true)
+
+	// practice games have bookmarks but no takebacks or peeks
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("p\n1\n1\ns a3\nundo\npeek b1\nq\n"), out); err != nil {
+		t.Fatalf("practice game failed: %s", err)
+	}
+	if strings.Contains(out.String(), "Type undo") {
+		t.Errorf("practice games shouldn't offer undo:\n%s", out.String())
+	}
+
+	game = New(1995)
+	game.SetPuzzlePacks([]mspuzzle.Pack{pack})
+	out = bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("p\n1\n1\nundo\npeek b1\ns a3\nq\n"), out); err != nil {
+		t.Fatalf("puzzle game failed: %s", err)
+	}
+	for _, want := range []string{"moves can only be taken back in analysis games",
+		"cells can only be peeked at in analysis games", "Game won", `"corner" solved 1 of 1 attempts`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("puzzle game output missing %q:\n%s", want, out.String())
+		}
+	}
+}
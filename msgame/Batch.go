@@ -0,0 +1,146 @@
+/*
+
+	Batch.go - one command applied to many cells at once, as in "f a1 a2 b5" or "s a1:c3", to speed up clearing and
+	flagging the obvious cells of big boards
+
+	A batch is checked in full before any of it is played, so a typo in one cell leaves the board untouched rather
+	than half done.
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"strings"
+)
+
+// moveTypes : the move each move command makes
+var moveTypes = map[string]msboard.MoveType{"s": msboard.MoveReveal, "f": msboard.MoveFlag, "c": msboard.MoveChord}
+
+// parseBatch -- the moves a command line makes: s reveals, f flags and c chords each cell its arguments name. Cells
+// are given one at a time, separated by spaces or commas, or as ranges from one corner to the other, e.g. a1:c3.
+// Ranges skip cells the move would do nothing to: for s and f revealed or flagged ones, for c anything but revealed
+// scores. Cells named alone are always kept, so a flag can still be toggled off, and under rules that chord, such
+// as msboard.ChordRules, s on a revealed score chords it too. Every cell must be on the board and cells to reveal
+// within reach of the fog, or nothing is played. Moves are in the order given, without repeats
+func (g *Game) parseBatch(cmd string, args []string, board *msboard.Board) ([]msboard.Move, error) {
+	moveType, ok := moveTypes[cmd]
+	if !ok {
+		return nil, fmt.Errorf("invalid command selection %q", cmd)
+	}
+	if moveType == msboard.MoveChord && !chording(board) {
+		return nil, fmt.Errorf("the %s rules don't chord", board.Rules().Name())
+	}
+
+	text := strings.Join(args, " ")
+	groups := []string{text}
+	// a single cell may take more than one word, as "3 7" does in the number-number scheme, so several words are
+	// taken as one cell only when they don't each name one and together do
+	if strings.Contains(text, ",") {
+		groups = strings.Split(text, ",")
+	} else if len(args) > 1 && (g.allRanges(args, board) || !g.allRanges(groups, board)) {
+		groups = args
+	}
+
+	s := board.Snapshot()
+	seen := map[msboard.Location]bool{}
+	var retval []msboard.Move
+	for _, group := range groups {
+		from, to, err := g.parseRange(strings.TrimSpace(group), board)
+		if err != nil {
+			return nil, err
+		}
+		ranged := from != to
+		for _, l := range rectangle(from, to) {
+			view, _ := s.Cell(l)
+			switch {
+			case seen[l]:
+				continue
+			case ranged && moveType != msboard.MoveChord && view.State != msboard.CellHidden:
+				continue
+			case ranged && moveType == msboard.MoveChord && (view.State != msboard.CellRevealed || view.Score <= 0):
+				continue
+			case moveType == msboard.MoveReveal && board.Initialized() && !board.InRange(l):
+				return nil, fmt.Errorf("%s is lost in the fog, choose a cell within %d of a revealed one",
+					g.cellName(l, board), board.Fog())
+			}
+			seen[l] = true
+			retval = append(retval, msboard.Move{Type: moveType, Location: l})
+		}
+	}
+
+	if len(retval) == 0 {
+		return nil, fmt.Errorf("no cells to play in %s", strings.TrimSpace(text))
+	}
+	return retval, nil
+}
+
+// chording -- true if the board's rules chord, as the classic rules don't
+func chording(board *msboard.Board) bool {
+	return board.Rules().Name() != (msboard.ClassicRules{}).Name()
+}
+
+// moveCommands -- the move commands a board takes, for prompts
+func moveCommands(board *msboard.Board) string {
+	if chording(board) {
+		return "s,f,c"
+	}
+	return "s,f"
+}
+
+// parseRange -- the corners of a range of cells, "from:to", or the cell itself twice for a single location; an
+// error unless both are on the board
+func (g *Game) parseRange(text string, board *msboard.Board) (msboard.Location, msboard.Location, error) {
+	corners := strings.SplitN(text, ":", 2)
+	from, err := g.parseLocation(corners[0], board)
+	if err != nil {
+		return from, from, err
+	}
+	to := from
+	if len(corners) > 1 {
+		if to, err = g.parseLocation(corners[1], board); err != nil {
+			return from, to, err
+		}
+	}
+
+	for _, l := range []msboard.Location{from, to} {
+		if !board.ValidLocation(l) {
+			return from, to, fmt.Errorf("%s is not on the board", g.cellName(l, board))
+		}
+	}
+	return from, to, nil
+}
+
+// allRanges -- true if every text is a cell or range on the board
+func (g *Game) allRanges(texts []string, board *msboard.Board) bool {
+	for _, text := range texts {
+		if _, _, err := g.parseRange(text, board); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// rectangle -- the locations of the rectangle with corners from and to, in reading order
+func rectangle(from, to msboard.Location) []msboard.Location {
+	top, bottom := from.Row(), to.Row()
+	if top > bottom {
+		top, bottom = bottom, top
+	}
+	left, right := from.Col(), to.Col()
+	if left > right {
+		left, right = right, left
+	}
+
+	retval := make([]msboard.Location, 0, (bottom-top+1)*(right-left+1))
+	for row := top; row <= bottom; row++ {
+		for col := left; col <= right; col++ {
+			retval = append(retval, msboard.NewLocation(row, col))
+		}
+	}
+	return retval
+}
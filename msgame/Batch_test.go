@@ -0,0 +1,132 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"go-mines/mspuzzle"
+	"strings"
+	"testing"
+)
+
+func TestParseBatch(t *testing.T) {
+	board, err := msboard.ParseLayout("F1.../11.../.....")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := New(1995)
+
+	for _, test := range []struct {
+		line     string
+		expected string // moves as f or s and the cell, space separated; empty for an error
+	}{
+		{"f c3 d3 c3", "fC3 fD3"},
+		{"s c2:d3", "sC2 sD2 sC3 sD3"},
+		{"s d3:c2", "sC2 sD2 sC3 sD3"},
+		{"s a1:c2", "sC1 sC2"}, // ranges skip flags and revealed cells
+		{"f a1 b1", "fA1 fB1"}, // named cells are kept, to toggle the flag
+		{"s c1, e3 ,c1", "sC1 sE3"},
+		{"s a1:b2", ""},
+		{"f c3 z9", ""},
+		{"f c3 c3:f1", ""},
+		{"x c3", ""},
+		{"s", ""},
+	} {
+		words := strings.Fields(test.line)
+		moves, err := g.parseBatch(words[0], words[1:], board)
+		if test.expected == "" {
+			if err == nil {
+				t.Errorf("%q should have been refused, got %v", test.line, moves)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q failed: %s", test.line, err)
+			continue
+		}
+		var got []string
+		for _, m := range moves {
+			prefix := "s"
+			switch m.Type {
+			case msboard.MoveFlag:
+				prefix = "f"
+			case msboard.MoveChord:
+				prefix = "c"
+			}
+			got = append(got, prefix+g.cellName(m.Location, board))
+		}
+		if strings.Join(got, " ") != test.expected {
+			t.Errorf("%q made moves %q, expected %s", test.line, got, test.expected)
+		}
+	}
+
+	// chords need rules that chord, and ranges of them keep only revealed scores
+	words := strings.Fields("c b1")
+	if _, err := g.parseBatch(words[0], words[1:], board); err == nil {
+		t.Errorf("chord accepted under the classic rules")
+	}
+	board.SetRules(msboard.ChordRules{})
+	words = strings.Fields("c a1:c3")
+	if moves, err := g.parseBatch(words[0], words[1:], board); err != nil || len(moves) != 3 {
+		t.Errorf("chord range wanted B1, A2 and B2 got %v err %v", moves, err)
+	}
+	board.SetRules(nil)
+
+	// cells of more than one word are split with commas
+	g.SetCoordinates(msboard.NumberNumberCodec{})
+	for line, expected := range map[string]int{"s 3 4": 1, "s 3 4, 3 5": 2, "s 3 4:3 5": 2} {
+		words := strings.Fields(line)
+		if moves, err := g.parseBatch(words[0], words[1:], board); err != nil || len(moves) != expected {
+			t.Errorf("%q made moves %v err %v, expected %d", line, moves, err, expected)
+		}
+	}
+}
+
+func TestBatchInFog(t *testing.T) {
+	board, err := msboard.ParseLayout("_...*/_..../_....")
+	if err != nil {
+		t.Fatal(err)
+	}
+	board.SetFog(1)
+
+	// every cell is checked before any is played, so the cell in the fog refuses the batch
+	words := strings.Fields("s b1 c1")
+	if _, err := New(1995).parseBatch(words[0], words[1:], board); err == nil || !strings.Contains(err.Error(), "C1") {
+		t.Errorf("batch reaching into the fog should be refused naming C1, got %v", err)
+	}
+}
+
+func TestBatchGame(t *testing.T) {
+	pack := mspuzzle.Pack{Title: "Batches", Puzzles: []mspuzzle.Puzzle{{Title: "row", Layout: "1*1/111/..."}}}
+	game := New(1995)
+	game.SetPuzzlePacks([]mspuzzle.Pack{pack})
+
+	// a bad cell leaves the flag unplaced, then a range clears the bottom row, its first click opening the rest
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("p\n1\n1\nf b1 d9\ns a3:c3\nq\n"), out); err != nil {
+		t.Fatalf("batch game failed: %s", err)
+	}
+	for _, want := range []string{"D9 is not on the board", "Game won"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("batch game output missing %q:\n%s", want, out.String())
+		}
+	}
+	if results := game.Results(); len(results) != 1 || results[0].Moves != 1 {
+		t.Errorf("batch game should have been won in 1 move, got %+v", results)
+	}
+}
+
+func TestChordGame(t *testing.T) {
+	pack := mspuzzle.Pack{Title: "Chords", Puzzles: []mspuzzle.Puzzle{{Title: "row", Layout: "1*1/111/..."}}}
+	game := New(1995)
+	game.SetPuzzlePacks([]mspuzzle.Pack{pack})
+	game.SetRules(msboard.ChordRules{})
+
+	// with B1 flagged, chording B2 uncovers the bottom row
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("p\n1\n1\nf b1\nc b2\nq\n"), out); err != nil {
+		t.Fatalf("chord game failed: %s", err)
+	}
+	if !strings.Contains(out.String(), "Choose command (s,f,c)") || !strings.Contains(out.String(), "Game won") {
+		t.Errorf("chord game should have offered chords and been won:\n%s", out.String())
+	}
+}
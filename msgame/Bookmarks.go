@@ -0,0 +1,136 @@
+/*
+
+	Bookmarks.go - named positions in practice games: bookmark the board before a guess, play on, and restore it to
+	try another line, as players study a chess game
+
+	Bookmarks are snapshots of the board, so they hold only what the player sees and restore onto the same mines.
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go-mines/msboard"
+	"io"
+	"sort"
+	"strings"
+)
+
+// positions : the earlier positions a practice game can go back to
+type positions struct {
+	bookmarks map[string]msboard.Snapshot // named by the player
+	undo      []msboard.Snapshot          // the board before each move, oldest first; analysis games only
+	restored  bool                        // the board has gone back to one of them
+	dropped   bool                        // the oldest of undo were forgotten, see SetUndoLimit
+}
+
+// newPositions -- no positions to go back to yet
+func newPositions() *positions {
+	return &positions{bookmarks: map[string]msboard.Snapshot{}}
+}
+
+// SetPractice -- play practice games: positions can be bookmarked and restored, even after a mine goes off, and
+// games aren't raced against a ghost or opponent. Games with a restore in them aren't reviewed or saved
+func (g *Game) SetPractice(enabled bool) {
+	g.practice = enabled
+}
+
+// practicing -- true for the games where positions can be gone back to, practice and analysis
+func (g *Game) practicing() bool {
+	return g.practice || g.analysis
+}
+
+// bookmarkCommand -- handle "bookmark <name>", "restore <name>" and "bookmarks" on a board in play, returning true
+// if the board was restored
+func (g *Game) bookmarkCommand(out io.Writer, board *msboard.Board, p *positions, cmd string, args []string) (bool, error) {
+	if !g.practicing() {
+		return false, errors.New("bookmarks are only available in practice games")
+	}
+	if !board.Initialized() {
+		return false, errors.New("bookmarks can be made once the first cell is uncovered")
+	}
+
+	if cmd == "bookmarks" {
+		writeBookmarks(out, p.bookmarks)
+		return false, nil
+	}
+	if len(args) != 1 {
+		return false, fmt.Errorf("%s needs a one word name, e.g. %s before-guess", cmd, cmd)
+	}
+	name := args[0]
+
+	if cmd == "bookmark" {
+		p.bookmarks[name] = board.Snapshot()
+		fmt.Fprintf(out, "Bookmarked %q\n", name)
+		return false, nil
+	}
+	s, ok := p.bookmarks[name]
+	if !ok {
+		return false, fmt.Errorf("no bookmark named %q, type bookmarks for the list", name)
+	}
+	if err := board.Restore(s); err != nil {
+		return false, err
+	}
+	p.restored = true
+	fmt.Fprintf(out, "Restored %q\n", name)
+	return true, nil
+}
+
+// writeBookmarks -- list the bookmarks of a game by name, with how far each had got
+func writeBookmarks(out io.Writer, bookmarks map[string]msboard.Snapshot) {
+	if len(bookmarks) == 0 {
+		fmt.Fprintln(out, "No bookmarks yet, type bookmark <name> to add one")
+		return
+	}
+
+	names := make([]string, 0, len(bookmarks))
+	for name := range bookmarks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := bookmarks[name]
+		fmt.Fprintf(out, "  %-20s %s, %d safe cells left, %d flags\n", name, s.Status, s.SafeRemaining, s.Flags)
+	}
+}
+
+// playOn -- once a practice game with positions to go back to is over, offer to restore a bookmark, or in analysis
+// games take back the last move, and play on. Returns true if the board went back; false to finish the game,
+// including when the input ends
+func (g *Game) playOn(in *bufio.Scanner, out *bufio.Writer, board *msboard.Board, p *positions) bool {
+	if !g.practicing() || (len(p.bookmarks) == 0 && len(p.undo) == 0) {
+		return false
+	}
+
+	for {
+		if len(p.undo) > 0 {
+			fmt.Fprintf(out, "\nGame %v. Type undo or restore a bookmark to play on, or press enter to finish:\n",
+				board.Status())
+		} else {
+			fmt.Fprintf(out, "\nGame %v. Restore a bookmark to play on, or press enter to finish:\n", board.Status())
+		}
+		if len(p.bookmarks) > 0 {
+			writeBookmarks(out, p.bookmarks)
+		}
+		out.Flush()
+
+		line, err := readInput(in)
+		if err != nil || line == "" {
+			return false
+		}
+		if line == "undo" {
+			err = g.takeBack(out, board, p)
+		} else {
+			_, err = g.bookmarkCommand(out, board, p, "restore", strings.Fields(line))
+		}
+		if err == nil {
+			return true
+		}
+		fmt.Fprintln(out, err)
+	}
+}
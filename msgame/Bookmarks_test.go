@@ -0,0 +1,53 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/mspuzzle"
+	"strings"
+	"testing"
+)
+
+func TestBookmarks(t *testing.T) {
+	pack := mspuzzle.Pack{Title: "Practice", Puzzles: []mspuzzle.Puzzle{{Title: "corner", Layout: "1*1/111/..."}}}
+	game := New(1995)
+	game.SetPuzzlePacks([]mspuzzle.Pack{pack})
+	game.SetPractice(true)
+
+	// bookmark the start, step on the mine, go back and take the other line
+	script := "p\n1\n1\nbookmarks\nbookmark start\nrestore nowhere\nbookmark\ns b1\nelsewhere\nstart\nbookmarks\ns a3\nq\n"
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader(script), out); err != nil {
+		t.Fatalf("practice game failed: %s", err)
+	}
+	for _, want := range []string{"No bookmarks yet", `Bookmarked "start"`, `no bookmark named "nowhere"`,
+		"bookmark needs a one word name", "Game lost. Restore a bookmark", `no bookmark named "elsewhere"`,
+		`Restored "start"`, "start                playing, 3 safe cells left, 0 flags", "Game won",
+		"Bookmarks were restored during the game, so it isn't reviewed or saved"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("practice game output missing %q:\n%s", want, out.String())
+		}
+	}
+	if results := game.Results(); len(results) != 1 || results[0].Moves != 2 {
+		t.Errorf("practice game should have been won after 2 moves, got %+v", results)
+	}
+}
+
+func TestBookmarksPracticeOnly(t *testing.T) {
+	pack := mspuzzle.Pack{Title: "Puzzles", Puzzles: []mspuzzle.Puzzle{{Title: "corner", Layout: "1*1/111/..."}}}
+	game := New(1995)
+	game.SetPuzzlePacks([]mspuzzle.Pack{pack})
+
+	// without practice, losing ends the game as usual
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("p\n1\n1\nbookmark start\ns b1\nb\nq\n"), out); err != nil {
+		t.Fatalf("puzzle game failed: %s", err)
+	}
+	for _, want := range []string{"bookmarks are only available in practice games", "Game lost"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("puzzle game output missing %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "Restore a bookmark") {
+		t.Error("games that aren't practice shouldn't offer bookmarks")
+	}
+}
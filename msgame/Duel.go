@@ -0,0 +1,191 @@
+/*
+
+	Duel.go - two player duels on one terminal: each player builds a board in the editor for the other to clear,
+	and the better result wins
+
+	Boards are handed over as puzzles, the same form puzzle files and packs use, and each must be clearable from
+	its revealed cells without guessing, so neither player can set a board that comes down to luck.
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"bufio"
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/mspuzzle"
+	"go-mines/msrender"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// checkDuelBoard -- the board a player built, as a puzzle for the opponent with its rating. Flags are cleared
+// first; boards without mines, with nothing left to clear or needing a guess are refused
+func checkDuelBoard(title string, b *msboard.Board) (mspuzzle.Puzzle, mspuzzle.Metrics, error) {
+	if b.MineCount() == 0 {
+		return mspuzzle.Puzzle{}, mspuzzle.Metrics{}, fmt.Errorf("the board needs at least one mine")
+	}
+	for row, cells := range b.Snapshot().Cells {
+		for col, view := range cells {
+			if view.State == msboard.CellFlagged {
+				b.SetFlagged(msboard.NewLocation(row, col), false)
+			}
+		}
+	}
+
+	p := mspuzzle.FromBoard(title, b)
+	m, err := mspuzzle.Rate(p)
+	switch {
+	case err == mspuzzle.ErrNeedsGuess:
+		return mspuzzle.Puzzle{}, mspuzzle.Metrics{}, fmt.Errorf("the board can't be cleared without guessing, " +
+			"reveal more starting cells or move some mines")
+	case err != nil:
+		return mspuzzle.Puzzle{}, mspuzzle.Metrics{}, err
+	case m.Clicks == 0:
+		return mspuzzle.Puzzle{}, mspuzzle.Metrics{}, fmt.Errorf("every safe cell is revealed, leave some to clear")
+	}
+	return p, m, nil
+}
+
+// duelWinner -- 1 or 2 for the player whose result wins, 0 for a draw. Clearing beats losing, and between two
+// clears the faster wins
+func duelWinner(results [2]GameResult) int {
+	won := [2]bool{results[0].Status == msboard.StatusWon, results[1].Status == msboard.StatusWon}
+	switch {
+	case won[0] && !won[1]:
+		return 1
+	case won[1] && !won[0]:
+		return 2
+	case !won[0]:
+		return 0
+	case results[0].Played < results[1].Played:
+		return 1
+	case results[1].Played < results[0].Played:
+		return 2
+	}
+	return 0
+}
+
+// RunDuel -- run a two player duel on one terminal. Each player in turn builds a board in the editor, which is
+// checked before the other gets it; then each clears the board built for them against the clock. The screen is
+// cleared between players
+func (g *Game) RunDuel(cin io.Reader, cout io.Writer) error {
+	cin, restore := g.editedInput(cin, cout)
+	defer restore()
+	in := bufio.NewScanner(cin)
+	out := bufio.NewWriter(cout)
+	defer out.Flush()
+
+	caps, err := msrender.Detect(cout, os.Getenv, g.display)
+	if err != nil {
+		caps = msrender.Capabilities{}
+	}
+	handover := func(message string) error {
+		msrender.Handover(out, caps, message)
+		fmt.Fprint(out, "Press enter when ready. ")
+		out.Flush()
+		_, err := readLine(in)
+		return err
+	}
+
+	var puzzles [2]mspuzzle.Puzzle
+	for author := range puzzles {
+		if err := handover(fmt.Sprintf("Player %d, build a board for player %d", author+1, 2-author)); err != nil {
+			return ignoreEOF(err)
+		}
+		fmt.Fprintln(out, "Lay mines and reveal the cells to start from, then type done. Type help for the editor commands.")
+
+		board := msboard.NewLayoutBoard(9, 9)
+		for {
+			if board, err = g.edit(in, out, cout, board); err != nil {
+				return ignoreEOF(err)
+			}
+			p, m, err := checkDuelBoard(fmt.Sprintf("player %d's board", author+1), board)
+			if err == nil {
+				fmt.Fprintf(out, "Board accepted, %s to clear in %d steps\n", m.Grade(), m.Steps)
+				puzzles[author] = p
+				break
+			}
+			fmt.Fprintln(out, err)
+		}
+	}
+
+	var results [2]GameResult
+	for player := range results {
+		p := puzzles[1-player]
+		if err := handover(fmt.Sprintf("Player %d, clear %s", player+1, p.Title)); err != nil {
+			return ignoreEOF(err)
+		}
+		if results[player], err = g.playDuel(in, out, caps, p); err != nil {
+			return ignoreEOF(err)
+		}
+	}
+
+	fmt.Fprintln(out)
+	for player, result := range results {
+		fmt.Fprintf(out, "Player %d %v in %s with %d moves\n", player+1, result.Status,
+			result.Played.Round(100*time.Millisecond), result.Moves)
+	}
+	if winner := duelWinner(results); winner == 0 {
+		fmt.Fprintln(out, "The duel is a draw")
+	} else {
+		fmt.Fprintf(out, "Player %d wins the duel\n", winner)
+	}
+	return nil
+}
+
+// playDuel -- clear one duel board against the clock, stepping on and flagging cells. Returns io.EOF if the
+// input ends first
+func (g *Game) playDuel(in *bufio.Scanner, out *bufio.Writer, caps msrender.Capabilities,
+	p mspuzzle.Puzzle) (GameResult, error) {
+	board, err := p.Board()
+	if err != nil {
+		return GameResult{}, err
+	}
+	renderer := caps.Renderer(msrender.Options{Coordinates: g.coords, RightToLeft: g.rtl})
+	g.complete([]string{"f", "s"}, board)
+
+	moves := 0
+	g.startClock()
+	renderer.Render(out, board.Snapshot())
+	for board.Status() == msboard.StatusPlaying {
+		fmt.Fprint(out, "\nChoose command (s,f) & location :  ")
+		out.Flush()
+
+		cmd, args, err := readCommand(in)
+		if err != nil {
+			return GameResult{}, err
+		}
+		location, err := g.parseLocation(strings.Join(args, " "), board)
+		if err == nil && !board.ValidLocation(location) {
+			err = fmt.Errorf("%s is not on the board", g.cellName(location, board))
+		}
+		if err != nil {
+			fmt.Fprintln(out, err)
+			continue
+		}
+
+		moveType, ok := moveTypes[cmd]
+		if !ok || moveType == msboard.MoveChord {
+			fmt.Fprintln(out, "Only s and f moves are allowed in a duel")
+			continue
+		}
+		board.Apply(msboard.Move{Type: moveType, Location: location})
+		moves++
+		renderer.Render(out, board.Snapshot())
+	}
+	return g.record("duel", board.Status(), moves, g.ratedBoard(board)), nil
+}
+
+// ignoreEOF -- nil for the end of the input, which just ends a duel, otherwise err
+func ignoreEOF(err error) error {
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
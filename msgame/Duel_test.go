@@ -0,0 +1,85 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckDuelBoard(t *testing.T) {
+	var cases = []struct {
+		layout string
+		valid  bool
+	}{
+		{"*1..", true},
+		{"*...", false},  // nothing revealed to start from
+		{"....", false},  // no mines
+		{"*1__", false},  // nothing left to clear
+		{"*1..*", false}, // either end could hold the second mine
+	}
+	for _, testcase := range cases {
+		b, err := msboard.ParseLayout(testcase.layout)
+		if err != nil {
+			t.Fatalf("ParseLayout(%q) failed: %s", testcase.layout, err)
+		}
+		if _, _, err = checkDuelBoard("test", b); (err == nil) != testcase.valid {
+			t.Errorf("checkDuelBoard(%q) validity wanted %v got err %v", testcase.layout, testcase.valid, err)
+		}
+	}
+
+	// flags the author left aren't handed over
+	b, _ := msboard.ParseLayout("*1..")
+	b.SetFlagged(msboard.NewLocation(0, 0), true)
+	p, _, err := checkDuelBoard("test", b)
+	if err != nil || p.Layout != "*1.." {
+		t.Errorf("checkDuelBoard wanted flags cleared, got %q err %v", p.Layout, err)
+	}
+}
+
+func TestDuelWinner(t *testing.T) {
+	won := func(played time.Duration) GameResult { return GameResult{Status: msboard.StatusWon, Played: played} }
+	lost := GameResult{Status: msboard.StatusLost}
+
+	var cases = []struct {
+		results [2]GameResult
+		want    int
+	}{
+		{[2]GameResult{won(time.Minute), lost}, 1},
+		{[2]GameResult{lost, won(time.Minute)}, 2},
+		{[2]GameResult{lost, lost}, 0},
+		{[2]GameResult{won(time.Minute), won(time.Second)}, 2},
+		{[2]GameResult{won(time.Second), won(time.Second)}, 0},
+	}
+	for i, testcase := range cases {
+		if got := duelWinner(testcase.results); got != testcase.want {
+			t.Errorf("duelWinner case %d wanted %d got %d", i, testcase.want, got)
+		}
+	}
+}
+
+func TestRunDuel(t *testing.T) {
+	script := strings.Join([]string{
+		"", // player 1 builds
+		"new 1 4", "mine a1", "reveal b1", "done",
+		"", // player 2 builds, first without a starting cell
+		"new 1 4", "mine a1", "done",
+		"reveal b1", "done",
+		"", // player 1 clears player 2's board
+		"d1",
+		"", // player 2 steps on player 1's mine
+		"a1",
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	if err := New(1).RunDuel(strings.NewReader(script), &out); err != nil {
+		t.Fatalf("RunDuel failed: %s", err)
+	}
+	for _, want := range []string{"can't be cleared without guessing", "Board accepted", "Player 1 won",
+		"Player 2 lost", "Player 1 wins the duel"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("RunDuel output wanted %q, got:\n%s", want, out.String())
+		}
+	}
+}
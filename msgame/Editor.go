@@ -0,0 +1,232 @@
+/*
+
+	Editor.go - interactive position editor for building puzzles and tutorials
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"bufio"
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/mspuzzle"
+	"go-mines/msrender"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const editorHelp = `Editor commands:
+  mine <loc>              toggle a mine
+  anti <loc>              toggle an anti-mine, which counts -1 towards the scores around it
+  reveal <loc>            toggle a revealed safe cell
+  flag <loc>              toggle a flag
+  new <rows> <cols>       start an empty board, or "new easy|medium|hard"
+  generate <mines> [horizontal|vertical|rotational] [noeights]
+                          fill the current board size with a generated layout
+  layout                  print the layout string
+  load <layout|file>      load a layout string or puzzle file
+  save <file> [title]     save the position as a puzzle file
+  help                    show this list
+  quit                    leave the editor`
+
+// editorCommands : the editor's command words, for tab completion
+var editorCommands = []string{
+	"anti", "done", "flag", "generate", "help", "layout", "load", "mine", "new", "quit", "reveal", "save",
+}
+
+// RunEditor -- run the position editor, where mines, reveals and flags can be placed freely and the result saved
+// as a puzzle file or layout string
+func (g *Game) RunEditor(cin io.Reader, cout io.Writer) error {
+	cin, restore := g.editedInput(cin, cout)
+	defer restore()
+	in := bufio.NewScanner(cin)
+	out := bufio.NewWriter(cout)
+	defer out.Flush()
+
+	fmt.Fprintln(out, editorHelp)
+	if _, err := g.edit(in, out, cout, msboard.NewLayoutBoard(9, 9)); err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// edit -- run editor commands on a board until the player is done, returning the board as left. cout is the
+// output out writes to, for detecting the terminal. io.EOF if the input ends first
+func (g *Game) edit(in *bufio.Scanner, out *bufio.Writer, cout io.Writer, board *msboard.Board) (*msboard.Board, error) {
+	caps, err := msrender.Detect(cout, os.Getenv, g.display)
+	if err != nil {
+		caps = msrender.Capabilities{}
+	}
+	caps.TTY = false // keep the command history on screen rather than redrawing in place
+	xray := &msrender.XRayOverlay{Enabled: true, Faint: caps.Color != msrender.ColorNone}
+	renderer := caps.Renderer(msrender.Options{Overlay: xray, Coordinates: g.coords, RightToLeft: g.rtl})
+
+	for {
+		xray.MineAt = board.MineAt
+		g.complete(editorCommands, board)
+		renderer.Render(out, board.Snapshot())
+		fmt.Fprint(out, "\nedit> ")
+		out.Flush()
+
+		line, err := readLine(in)
+		if err != nil {
+			return board, err
+		}
+
+		words := strings.Fields(line)
+		if len(words) == 0 {
+			continue
+		}
+
+		cmd, args := strings.ToLower(words[0]), words[1:]
+		switch cmd {
+		case "quit", "q", "done":
+			return board, nil
+		case "help", "?":
+			fmt.Fprintln(out, editorHelp)
+		case "mine", "anti", "reveal", "flag":
+			err = g.editCell(board, cmd, args)
+		case "new":
+			var fresh *msboard.Board
+			if fresh, err = newEditorBoard(args); err == nil {
+				board = fresh
+			}
+		case "generate":
+			var generated *msboard.Board
+			if generated, err = generateEditorBoard(board.Rows(), board.Cols(), args); err == nil {
+				board = generated
+			}
+		case "layout":
+			fmt.Fprintln(out, board.Layout())
+		case "load":
+			var loaded *msboard.Board
+			if loaded, err = loadEditorBoard(args); err == nil {
+				board = loaded
+			}
+		case "save":
+			if len(args) == 0 {
+				err = fmt.Errorf("save needs a file name")
+				break
+			}
+			puzzle := mspuzzle.FromBoard(strings.Join(args[1:], " "), board)
+			if err = mspuzzle.SaveFile(args[0], puzzle); err == nil {
+				fmt.Fprintf(out, "saved %q\n", args[0])
+			}
+		default:
+			err = fmt.Errorf("unrecognized editor command %q, try help", cmd)
+		}
+
+		if err != nil {
+			fmt.Fprintln(out, err)
+		}
+	}
+}
+
+// editCell -- toggle the mine, anti-mine, reveal or flag state of one cell
+func (g *Game) editCell(board *msboard.Board, cmd string, args []string) error {
+	location, err := g.parseLocation(strings.Join(args, " "), board)
+	if err != nil {
+		return err
+	}
+
+	view, ok := board.Snapshot().Cell(location)
+	if !ok {
+		return fmt.Errorf("location %v is not on the board", location)
+	}
+
+	switch cmd {
+	case "mine":
+		return board.SetMine(location, !board.MineAt(location))
+	case "anti":
+		if board.AntiMineAt(location) {
+			return board.SetMine(location, false)
+		}
+		return board.SetAntiMine(location, true)
+	case "reveal":
+		return board.SetRevealed(location, view.State != msboard.CellRevealed)
+	}
+	return board.SetFlagged(location, view.State != msboard.CellFlagged)
+}
+
+// newEditorBoard -- empty board from "<rows> <cols>" or a difficulty name. Sizes are limited as board codes are,
+// see msboard.MaxCodeSide, so a typo can't ask for more cells than memory holds
+func newEditorBoard(args []string) (*msboard.Board, error) {
+	if len(args) == 1 {
+		preset := msboard.NewBoard(strings.ToLower(args[0]))
+		if nil == preset {
+			return nil, fmt.Errorf("unknown difficulty %q", args[0])
+		}
+		return msboard.NewLayoutBoard(preset.Rows(), preset.Cols()), nil
+	}
+
+	if len(args) == 2 {
+		rows, rowErr := strconv.Atoi(args[0])
+		cols, colErr := strconv.Atoi(args[1])
+		if rowErr == nil && colErr == nil {
+			if rows > msboard.MaxCodeSide || cols > msboard.MaxCodeSide {
+				return nil, fmt.Errorf("a %dx%d board is bigger than the %dx%d the editor makes", rows, cols,
+					msboard.MaxCodeSide, msboard.MaxCodeSide)
+			}
+			if b := msboard.NewLayoutBoard(rows, cols); nil != b {
+				return b, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("usage: new <rows> <cols> or new easy|medium|hard")
+}
+
+// generateEditorBoard -- board of the given size with a generated layout from "<mines> [symmetry] [noeights]"
+func generateEditorBoard(rows, cols int, args []string) (*msboard.Board, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("generate needs a mine count")
+	}
+	mines, err := strconv.Atoi(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("bad mine count %q", args[0])
+	}
+
+	var opts msboard.GeneratorOptions
+	for _, arg := range args[1:] {
+		switch strings.ToLower(arg) {
+		case "horizontal":
+			opts.Symmetry = msboard.SymmetryHorizontal
+		case "vertical":
+			opts.Symmetry = msboard.SymmetryVertical
+		case "rotational":
+			opts.Symmetry = msboard.SymmetryRotational
+		case "noeights":
+			opts.NoEights = true
+		default:
+			return nil, fmt.Errorf("unrecognized generator option %q", arg)
+		}
+	}
+
+	b := msboard.NewCustomBoard(rows, cols, mines)
+	if nil == b {
+		return nil, fmt.Errorf("a %dx%d board can't hold %d mines", rows, cols, mines)
+	}
+	// no safe starting cell in the editor
+	if err = b.InitializeWithOptions(msboard.NewLocation(-1, -1), opts); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// loadEditorBoard -- board from a puzzle file name, or failing that a layout string
+func loadEditorBoard(args []string) (*msboard.Board, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("load needs a layout string or file name")
+	}
+
+	if puzzle, err := mspuzzle.LoadFile(args[0]); err == nil {
+		return puzzle.Board()
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	return msboard.ParseLayout(strings.Join(args, "/"))
+}
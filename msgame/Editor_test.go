@@ -0,0 +1,91 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"go-mines/mspuzzle"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEditorSession(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "saved.json")
+	script := strings.Join([]string{
+		"new 3 4",
+		"mine a1",
+		"mine b2",
+		"mine b2", // toggled back off
+		"reveal c3",
+		"flag a1",
+		"bogus",
+		"layout",
+		"save " + filename + " Corner flag",
+		"quit",
+	}, "\n")
+
+	out := bytes.NewBufferString("")
+	if err := New(1995).RunEditor(strings.NewReader(script), out); err != nil {
+		t.Fatalf("RunEditor() failed: %s", err)
+	}
+
+	want := "F.../..../.._."
+	if !strings.Contains(out.String(), want) || !strings.Contains(out.String(), "unrecognized editor command") {
+		t.Errorf("Editor output missing layout %q or error message:\n%s", want, out.String())
+	}
+
+	puzzle, err := mspuzzle.LoadFile(filename)
+	if err != nil || puzzle.Layout != want || puzzle.Title != "Corner flag" {
+		t.Errorf("Saved puzzle wanted %q got %+v err %v", want, puzzle, err)
+	}
+}
+
+func TestEditorGenerate(t *testing.T) {
+	b, err := generateEditorBoard(8, 8, []string{"10", "rotational", "noeights"})
+	if err != nil || b.MineCount() != 10 {
+		t.Fatalf("generateEditorBoard() failed: %v", err)
+	}
+	for _, l := range b.Mines() {
+		if !b.MineAt(msboard.NewLocation(7-l.Row(), 7-l.Col())) {
+			t.Errorf("Generated layout is not rotationally symmetric at %v", l)
+		}
+	}
+
+	if _, err = generateEditorBoard(8, 8, []string{"10", "diagonal"}); err == nil {
+		t.Errorf("generateEditorBoard() accepted an unknown option")
+	}
+}
+
+func TestEditorBoardSize(t *testing.T) {
+	if b, err := newEditorBoard([]string{"1000", "2"}); err != nil || b.Rows() != msboard.MaxCodeSide {
+		t.Errorf("newEditorBoard() of the largest size failed: %v", err)
+	}
+	for _, size := range [][]string{{"1001", "2"}, {"2", "100000000"}} {
+		if _, err := newEditorBoard(size); nil == err || !strings.Contains(err.Error(), "bigger") {
+			t.Errorf("newEditorBoard(%v) wanted refused as too big got %v", size, err)
+		}
+	}
+}
+
+func TestEditorAntiMine(t *testing.T) {
+	script := strings.Join([]string{
+		"new 2 3",
+		"anti a1",
+		"mine c1",
+		"reveal b1",
+		"anti b2",
+		"anti b2", // toggled back off
+		"layout",
+		"quit",
+	}, "\n")
+
+	out := bytes.NewBufferString("")
+	if err := New(1995).RunEditor(strings.NewReader(script), out); err != nil {
+		t.Fatalf("RunEditor() failed: %s", err)
+	}
+
+	// B1 scores zero, the anti-mine cancelling out the mine
+	if want := "x_*/..."; !strings.Contains(out.String(), want) {
+		t.Errorf("Editor output missing layout %q:\n%s", want, out.String())
+	}
+}
@@ -12,14 +12,22 @@ package msgame
 import (
 	"bufio"
 	"fmt"
+	"go-mines/msanalysis"
 	"go-mines/msboard"
+	"go-mines/msbot"
+	"go-mines/msengine"
+	"go-mines/mspuzzle"
+	"go-mines/msrender"
+	"go-mines/msreplay"
+	"go-mines/mssolver"
+	"go-mines/msstats"
+	"go-mines/msstore"
 	"io"
-	"math/rand"
+	"math"
 	"os"
-	"strconv"
+	"path/filepath"
 	"strings"
 	"time"
-	"unicode"
 )
 
 // Game : main minesweeper game runner class
@@ -27,17 +35,171 @@ type Game struct {
 	start     time.Time
 	turnCount int
 	randSeed  int64
+	rng       msboard.RNG        // draws for laying out and changing boards, from randSeed, see msboard.PCG
+	display   msrender.Overrides // user color/UTF-8 settings for renderer selection
+	debug     bool               // developer commands enabled
+	dim       bool               // mark numbers whose flags are all placed
+	guessFree bool               // show after each move whether a safe move exists
+	verbose   bool               // summarize every move in words, see Verbose.go
+	status    bool               // counters drawn above the board, see StatusLine.go
+	sounds    msengine.Sounds    // audio cues for moves, nil for the terminal bell on terminals, see Sounds.go
+	rtl       bool               // row labels on the right of the board
+	transpose bool               // preset boards turned on their side, taller than wide
+	generator msboard.GeneratorOptions
+	code      *msboard.BoardCode    // shared board offered from the main menu, nil for none
+	adaptive  msstats.DensityPolicy // chooses the mines of adaptive boards, nil for no adaptive games
+	adapting  bool                  // the game in play is adaptive, see Adaptive.go
+	coords    msboard.LocationCodec
+	replayDir string          // where finished games are saved, empty to not save them
+	ghost     *msreplay.Ghost // previous game to race against, nil for normal play
+	opponent  *msbot.Skill    // computer opponent to race on every board, nil for none
+	botSpeed  float64         // opponent moves per second, 0 for its skill's own pace
+	cascade   time.Duration   // pause between the waves of a flood reveal on terminals, 0 to show it at once
+	boards    int             // boards played at once in easy, medium and hard games, 0 or 1 for one
+	fog       int             // visibility radius limiting clicks to cells near revealed ones, 0 for none
+	rules     msboard.Rules   // rules every board is played by, nil for the classic rules
+	moving    float64         // fraction of the mines moved out of sight every moveEvery reveals, 0 for none
+	moveEvery int             // reveals between mine moves
+	arcade    int             // treasures hidden on each new board, 0 for normal play
+	practice  bool            // positions can be bookmarked and restored, games aren't raced
+	analysis  bool            // practice with takebacks and peeks, games aren't recorded
+	undoLimit int             // moves an analysis game can take back, 0 for no limit
+	replayMax int             // largest replay saved, in bytes, 0 for no limit
+	powerUps  PowerUps        // arcade inventory and score of the game in play
+	listener  func(PowerUpEvent)
+	editor    *lineEditor      // line editing of terminal input, nil when input isn't edited
+	noEditing bool             // read terminal input as typed, without line editing
+	clock     gameClock        // play time of the current game
+	results   []GameResult     // finished games
+	packs     []mspuzzle.Pack  // puzzle packs offered from the main menu
+	stats     *mspuzzle.Stats  // puzzle completion, nil until the first puzzle is shown
+	statsFile string           // where puzzle completion is kept, empty for this session only
+	history   *msstats.History // finished games, nil until the first is added
+	histFile  string           // where the history is kept, empty for this session only
+	store     msstore.Store    // where games, replays and puzzle completion are kept instead of files, nil for none
+	remote    msstore.Stamped  // where the store is synced to after every game, nil for no syncing
 }
 
-//New -- init a new Game object with given random seed for testing
+// New -- init a new Game object with given random seed for testing
 func New(seed int64) *Game {
 	retval := new(Game)
 	retval.start = time.Now()
 	retval.randSeed = seed
+	retval.rng = msboard.NewPCG(seed)
 
 	return retval
 }
 
+// SetDisplay -- set color and UTF-8 overrides used when choosing a console renderer
+func (g *Game) SetDisplay(o msrender.Overrides) {
+	g.display = o
+}
+
+// SetGenerator -- set the mine layout constraints used for new boards
+func (g *Game) SetGenerator(opts msboard.GeneratorOptions) {
+	g.generator = opts
+}
+
+// generatorOptions -- the options new boards are laid out with, drawing from the seeded generator unless
+// SetGenerator gave a source of its own
+func (g *Game) generatorOptions() msboard.GeneratorOptions {
+	retval := g.generator
+	if nil == retval.RNG {
+		retval.RNG = g.rng
+	}
+	return retval
+}
+
+// SetDimSatisfied -- dim (or mark, without color) numbers that already have as many flags around them as their
+// score
+func (g *Game) SetDimSatisfied(enabled bool) {
+	g.dim = enabled
+}
+
+// SetGuessFree -- after each move, show whether the position has a safe move, so players who want to avoid
+// guessing know when to keep looking
+func (g *Game) SetGuessFree(enabled bool) {
+	g.guessFree = enabled
+}
+
+// SetDebug -- enable the developer commands (xray, reveal, dump, audit) in the console game
+func (g *Game) SetDebug(enabled bool) {
+	g.debug = enabled
+}
+
+// SetReplayDir -- save a replay of every finished game in dir
+func (g *Game) SetReplayDir(dir string) {
+	g.replayDir = dir
+}
+
+// SetReplayLimit -- keep saved replays to at most limit bytes, folding their earliest moves into a starting
+// position when they're bigger; 0 is no limit
+func (g *Game) SetReplayLimit(limit int) {
+	g.replayMax = limit
+}
+
+// SetBoardCode -- offer the board a code names from the main menu, laid out as it was where the code was made
+func (g *Game) SetBoardCode(code msboard.BoardCode) error {
+	if _, err := code.Board(); err != nil {
+		return err
+	}
+	g.code = &code
+	return nil
+}
+
+// SetGhost -- race against a timed replay: every game is played on the replay's board with the ghost's progress
+// shown alongside the player's
+func (g *Game) SetGhost(r msreplay.Replay) error {
+	ghost, err := msreplay.NewGhost(r)
+	if err != nil {
+		return err
+	}
+	g.ghost = ghost
+	return nil
+}
+
+// SetOpponent -- race a computer opponent of the given skill: it plays every board first, and its progress at the
+// same moment is shown alongside the player's. Takes the place of any ghost
+func (g *Game) SetOpponent(skill msbot.Skill) {
+	g.opponent = &skill
+}
+
+// SetOpponentSpeed -- pace the opponent at a fixed number of moves per second rather than its skill's own pace;
+// 0 goes back to the skill's pace
+func (g *Game) SetOpponentSpeed(movesPerSecond float64) {
+	g.botSpeed = movesPerSecond
+}
+
+// SetCascadeDelay -- on terminals, draw the cascade of a click on a zero a wave at a time with this pause between
+// waves rather than all at once; 0 shows it at once
+func (g *Game) SetCascadeDelay(delay time.Duration) {
+	g.cascade = delay
+}
+
+// SetFog -- play with fog of war: after the first click, only cells within radius of a revealed cell can be
+// clicked. 0 turns it off
+func (g *Game) SetFog(radius int) {
+	g.fog = radius
+}
+
+// SetRules -- play every board by a set of rules, such as msboard.ChordRules; nil for the classic rules
+func (g *Game) SetRules(rules msboard.Rules) {
+	g.rules = rules
+}
+
+// SetMovingMines -- play with moving mines: every so many reveals a fraction of the mines out of sight move to
+// other cells out of sight. A fraction of 0 keeps the mines still
+func (g *Game) SetMovingMines(fraction float64, every int) error {
+	if fraction < 0 || fraction > 1 {
+		return fmt.Errorf("the fraction of mines moving must be 0 to 1, not %g", fraction)
+	}
+	if fraction > 0 && every < 1 {
+		return fmt.Errorf("mines need at least 1 reveal between moves, not %d", every)
+	}
+	g.moving, g.moveEvery = fraction, every
+	return nil
+}
+
 // RunConsole -- run a game loop using Console rendering to the provided input/output objects
 func (g *Game) RunConsole(cin io.Reader, cout io.Writer) error {
 
@@ -51,24 +213,55 @@ func (g *Game) RunConsole(cin io.Reader, cout io.Writer) error {
 	until board.HitMine() or board.SafeRemaining() == 0
 	*/
 
-	// get random
-	rand.Seed(g.randSeed)
+	// every run of the same seed lays out the same boards, whatever the platform or Go release
+	g.rng = msboard.NewPCG(g.randSeed)
 	// output seed on stderr for potential replay in debugger
 	fmt.Fprintf(os.Stderr, "{ starting with random seed %d }\n\n", g.randSeed)
 
-	// buffered reader and writer
+	// buffered reader and writer, terminal input edited as it is typed
+	term := &syncWriter{w: cout}
+	cin, restore := g.editedInput(cin, term)
+	defer restore()
 	in := bufio.NewScanner(cin)
-	out := bufio.NewWriter(cout)
+	out := bufio.NewWriter(term)
+
+	// stops the live displays of the current game, its race and status line clock, if it has them
+	stopLive := func() {}
+
+	// the last board played, which can be retried with the same mines, and its board code if it has one
+	var lastBoard *msboard.Board
+	lastCode := ""
 
 	// Outer loop
 	for {
-		fmt.Fprintln(cout, "Welcome to Minesweeper. Choose game type: [E]asy [M]edium [H]ard   or   [Q]uit")
+		choices := "[E]asy [M]edium [H]ard"
+		if nil != lastBoard {
+			choices += " [R]etry last board"
+		}
+		if nil != g.code {
+			choices += " [C]ode " + g.code.String()
+		}
+		if nil != g.adaptive {
+			choices += " [A]daptive"
+		}
+		if len(g.packs) > 0 {
+			choices += " [P]uzzles"
+		}
+		if nil != g.history && len(g.history.Games) > 0 {
+			choices += " [S]tats"
+		}
+		fmt.Fprintf(out, "Welcome to Minesweeper. Choose game type: %s   or   [Q]uit\n", choices)
+		out.Flush()
 		input, err := readOneCharacter(in)
-		if err != nil {
+		if err == io.EOF {
+			goto game_over
+		} else if err != nil {
 			continue
 		}
 
 		boardType := "unknown"
+		retry, shared, adaptive := false, false, false
+		var puzzle *mspuzzle.Puzzle
 
 		switch input {
 		case "e":
@@ -77,100 +270,661 @@ func (g *Game) RunConsole(cin io.Reader, cout io.Writer) error {
 			boardType = "medium"
 		case "h":
 			boardType = "hard"
+		case "r":
+			if nil == lastBoard {
+				continue
+			}
+			retry = true
+		case "c":
+			if nil == g.code {
+				continue
+			}
+			shared = true
+		case "a":
+			if nil == g.adaptive {
+				continue
+			}
+			adaptive = true
+		case "p":
+			if len(g.packs) == 0 {
+				continue
+			}
+			chosen, ok, err := g.choosePuzzle(in, out)
+			if err == io.EOF {
+				goto game_over
+			} else if !ok {
+				continue
+			}
+			puzzle = &chosen
+		case "s":
+			if nil == g.history || len(g.history.Games) == 0 {
+				continue
+			}
+			g.writeStats(out)
+			continue
 		case "q":
 			goto game_over
 		default:
 			continue
 		}
 
-		board := msboard.NewBoard(boardType)
+		// multiboard games have a loop of their own
+		if g.boards > 1 && !retry && !shared && !adaptive && nil == puzzle {
+			caps, err := msrender.Detect(cout, os.Getenv, g.display)
+			if err != nil {
+				caps = msrender.Capabilities{}
+			}
+			if err = g.playMulti(in, out, caps, boardType); err == io.EOF {
+				goto game_over
+			}
+			continue
+		}
 
-		// have to init board before displaying initial blank board; re-init after user chooses safe square
-		board.Initialize(msboard.NewLocation(0, 0))
-		board.ConsoleRender(out)
+		board := g.newBoard(boardType)
+		code := ""
+		if retry {
+			// same mines as the last game, with everything hidden again
+			board = lastBoard
+			board.Reset()
+			code = lastCode
+		} else if shared {
+			// checked when the code was set
+			board, _ = g.code.Board()
+			code = g.code.String()
+		} else if adaptive {
+			board = g.adaptiveBoard(out)
+		} else if nil != puzzle {
+			// already checked when the pack was loaded
+			board, _ = puzzle.Board()
+		}
+		g.adapting = adaptive
+		// terminals get cursor-addressed partial redraws in the richest theme they support, scrolled through a
+		// viewport if the board is bigger than the screen; files and pipes get plain full frames
+		caps, err := msrender.Detect(cout, os.Getenv, g.display)
+		if err != nil {
+			caps = msrender.Capabilities{}
+		}
+		var view msrender.Viewport
+		sounds := g.soundsFor(out, caps)
+		xray := &msrender.XRayOverlay{Faint: caps.Color != msrender.ColorNone, MineAt: board.MineAt}
+		satisfied := &msrender.SatisfiedOverlay{Enabled: g.dim, Faint: caps.Color != msrender.ColorNone}
+		lastMove := &msrender.LastMoveOverlay{Enabled: caps.Color != msrender.ColorNone}
+		overlays := msrender.Overlays{lastMove, xray, satisfied}
+		options := msrender.Options{View: &view, Overlay: overlays, Coordinates: g.coords, RightToLeft: g.rtl}
+		var status *statusLine
+		if g.status {
+			status = newStatusLine(code, g.randSeed, g.Elapsed)
+			options.Status = status.text
+		}
+		view = caps.Viewport(board.Snapshot(), options)
+		renderer := caps.Renderer(options)
 
 		gameInit := false
-		for !board.MineHit() && board.SafeRemaining() > 0 {
+		var replay *msreplay.Replay
+		ghost, rival, racing := g.ghost, "ghost", "a ghost"
+		if nil != puzzle {
+			// puzzles are played alone, against their par time
+			ghost = nil
+			fmt.Fprintf(out, "Puzzle %q, %s\n", puzzle.Title, puzzleRecord(*puzzle, g.puzzleStats().Completion(*puzzle)))
+		} else if g.practicing() {
+			// practice games aren't races
+			ghost = nil
+		} else if nil != g.opponent {
+			// the opponent plays the board first and is then raced like a ghost
+			if ghost, err = g.opponentGhost(board, *g.opponent, g.botSpeed); err != nil {
+				fmt.Fprintln(out, err)
+				continue
+			}
+			rival, racing = "bot", "the "+g.opponent.String()+" bot"
+		}
+		if nil != ghost {
+			// races are played on the ghost's board, which is already laid out
+			if board, err = ghost.Replay().Board(); err != nil {
+				fmt.Fprintln(out, err)
+				continue
+			}
+			xray.MineAt = board.MineAt
+			gameInit = true
+			fmt.Fprintf(out, "Racing %s that finished in %s, it started at %s\n", racing,
+				ghost.Duration().Round(time.Second), g.cellName(ghost.Replay().Moves[0].Location, board))
+		} else if retry || shared || nil != puzzle {
+			gameInit = true
+		}
+		if shared {
+			fmt.Fprintf(out, "Board %s, laid out around %s: start there to play it as it was shared\n", code,
+				g.cellName(g.code.First, board))
+		}
+		// puzzles and races keep their layouts, everything else can have moving mines
+		moving, reveals, moved := g.moving > 0 && nil == puzzle && nil == ghost, 0, 0
+		arcade, powered := g.arcade > 0 && nil == puzzle && nil == ghost, false
+		g.powerUps = PowerUps{Multiplier: 1}
+		// races show both players' progress under every frame, the rival's kept current on terminals
+		render := func() {
+			renderer.Render(out, board.Snapshot())
+			if nil != ghost {
+				writeRace(out, rival, ghost, board.Snapshot(), g.Elapsed())
+			}
+		}
+		follow := func() {
+			var stops []func()
+			if nil != status {
+				stops = append(stops, followStatus(term, renderer))
+			}
+			if nil != ghost {
+				stops = append(stops, followRace(term, renderer, rival, ghost, g.Elapsed))
+			}
+			stopLive = func() {
+				for _, stop := range stops {
+					stop()
+				}
+			}
+		}
+
+		// practice games can go back to earlier positions, even once the game is over
+		earlier := newPositions()
+		// debug builds keep every position, see TimeTravel.go
+		var travel *timeline
+		if g.debug && timeTravel {
+			travel = newTimeline(board)
+		}
+		// hints and developer commands leave the game unrated, see Rated.go
+		assisted := false
+		playOn := func() bool {
+			if !g.playOn(in, out, board, earlier) {
+				return false
+			}
+			render()
+			return true
+		}
+
+		board.SetFog(g.fog)
+		board.SetRules(g.rules)
+		g.complete(sortedWords(commandWords), board)
+		g.startClock()
+		render()
+		follow()
+		shown := time.Now()
+		if gameInit {
+			replay = msreplay.New(board, g.randSeed, shown)
+			replay.Code = code
+		}
+		// the board stays uninitialized, rendering as all hidden, until the first click lays out the mines
+		for board.Status() == msboard.StatusUninitialized || board.Status() == msboard.StatusPlaying || playOn() {
 
 			if !gameInit {
-				fmt.Fprint(out, "\nChoose starting cell location:  ")
+				fmt.Fprint(out, "\nChoose starting cell location, hint, or ? for help:  ")
 			} else {
-				fmt.Fprint(out, "\nChoose command (s,f) & location :  ")
+				fmt.Fprintf(out, "\nChoose command (%s) & location, pause, or ? for help :  ", moveCommands(board))
 			}
 			out.Flush()
 
-			cmd, location, err := readNextMove(in)
-			if err != nil {
-				fmt.Fprintln(os.Stderr, "readNextmove() failure: cmd ", cmd, " location ", location, " err ", err)
+			cmd, args, err := readCommand(in)
+			if err == io.EOF {
+				goto game_over
+			} else if err != nil {
+				fmt.Fprintln(os.Stderr, "readCommand() failure: cmd ", cmd, " args ", args, " err ", err)
 				continue
 			}
-			fmt.Fprintln(out, location)
 
-			// sanity check
-			if !board.ValidLocation(location) {
-				fmt.Fprint(out, "Invalid board location selected, please retry: ", location)
+			// scroll commands move the viewport by half a screen
+			if dRow, dCol, ok := scrollStep(cmd, view); ok {
+				view.Scroll(dRow, dCol, board.Rows(), board.Cols())
+				render()
 				continue
 			}
 
-			if !gameInit {
-				// game starts now with user's 'safe' square
-				board.Initialize(location)
-				gameInit = true
+			// the help screen lists what this game accepts, see Help.go
+			if cmd == "?" {
+				g.writeHelp(out, board, helpState{started: gameInit, arcade: arcade, travel: nil != travel})
+				continue
 			}
 
-			switch cmd {
-			case "s":
-				board.Click(location)
-			case "f":
-				board.ToggleFlag(location)
-			default:
-				fmt.Fprintf(out, "Invalid command selection %q\n", cmd)
+			// the only hint on offer is where to start
+			if cmd == "hint" {
+				if gameInit {
+					fmt.Fprintln(out, "Hints are only available for the first move")
+				} else {
+					fmt.Fprintln(out, "Hint: start at", g.cellName(msanalysis.OpeningHint(board.Difficulty(), board.Rows(), board.Cols()), board))
+					assisted = true
+				}
+				continue
+			}
+
+			// the board is hidden and the clock stopped until the next line of input
+			if cmd == "pause" {
+				stopLive()
+				g.Pause()
+				msrender.PauseScreen(out, renderer, caps)
+				out.Flush()
+				if _, err := readInput(in); err == io.EOF {
+					goto game_over
+				}
+				g.Resume()
+				render()
+				follow()
+				continue
+			}
+
+			if cmd == "bookmark" || cmd == "restore" || cmd == "bookmarks" {
+				if restored, err := g.bookmarkCommand(out, board, earlier, cmd, args); err != nil {
+					fmt.Fprintln(out, err)
+				} else if restored {
+					render()
+				}
+				continue
+			}
+
+			// analysis games can take back moves and look under cells
+			if cmd == "undo" || cmd == "peek" {
+				if cmd == "peek" {
+					err = g.peek(out, board, args)
+				} else if err = g.takeBack(out, board, earlier); err == nil {
+					render()
+				}
+				if err != nil {
+					fmt.Fprintln(out, err)
+				}
+				continue
+			}
+
+			// developer commands, only available with debugging enabled
+			if g.debug && gameInit {
+				handled := true
+				switch cmd {
+				case "xray":
+					xray.Enabled = !xray.Enabled
+					msrender.Redraw(renderer)
+				case "reveal":
+					if err := g.debugRevealRegion(board, args); err != nil {
+						fmt.Fprintln(out, err)
+					}
+				case "dump":
+					board.DebugDump(out)
+					g.dumpSolver(out, board)
+				case "audit":
+					fmt.Fprintln(out, board.Audit())
+				case "goto", "fork", "lines", "diff":
+					if nil == travel {
+						handled = false
+					} else if _, err := g.timeTravelCommand(out, board, travel, earlier, cmd, args); err != nil {
+						fmt.Fprintln(out, err)
+					}
+				default:
+					handled = false
+				}
+				if handled {
+					assisted = true
+					render()
+					continue
+				}
+			}
+
+			// arcade power-ups are spent between moves
+			if arcade && gameInit && (cmd == "defuse" || cmd == "freeflag") {
+				if err := g.usePowerUp(out, board, cmd, args); err != nil {
+					fmt.Fprintln(out, err)
+					continue
+				}
+				powered = true
+				render()
+				writeArcade(out, g.powerUps)
+				continue
+			}
+
+			if !gameInit && cmd != "s" {
+				fmt.Fprintln(out, "Choose a starting cell to uncover first")
+				continue
+			}
+
+			// every cell of a batch is checked before any is played
+			moves, err := g.parseBatch(cmd, args, board)
+			if err != nil {
+				fmt.Fprintln(out, err)
+				continue
+			}
+			if !gameInit && len(moves) > 1 {
+				fmt.Fprintln(out, "Choose a single starting cell to uncover first")
+				continue
 			}
 
-			board.ConsoleRender(out)
+			g.beforeMove(board, earlier)
+			var revealed []msboard.Location
+			var summaries []string
+			batchRevealed := map[msboard.Location]bool{}
+			for _, move := range moves {
+				location := move.Location
+				if status := board.Status(); status == msboard.StatusWon || status == msboard.StatusLost {
+					break
+				}
+				// cells opened by an earlier click of the batch are skipped rather than chorded
+				if batchRevealed[location] {
+					continue
+				}
+				fmt.Fprintln(out, location)
+
+				var opened []msboard.Location
+				switch {
+				case !gameInit:
+					// game starts now with user's 'safe' square, generated and revealed together. The generator's
+					// draws are recorded so the replay can regenerate the board
+					opts := g.generatorOptions()
+					// boards laid out with the default options are drawn from a seed of their own, which a board
+					// code can name
+					if g.generator == (msboard.GeneratorOptions{}) && !arcade {
+						seeded := msboard.NewBoardCode(board.Rows(), board.Cols(), board.MineCount(),
+							int64(g.rng.Intn(math.MaxInt32)), location)
+						opts.RNG, _ = msboard.NewSeededRNG(seeded.Seed, seeded.RNG)
+						code = seeded.String()
+					}
+					rng := &msboard.RecordingRNG{Source: opts.RNG}
+					opts.RNG = rng
+					if arcade {
+						opts.Treasures = g.arcade
+					}
+					if opened, err = board.FirstClickWithOptions(location, opts); err != nil {
+						fmt.Fprintln(out, err, "- using an unconstrained layout")
+						opts, rng = msboard.GeneratorOptions{Treasures: opts.Treasures}, &msboard.RecordingRNG{Source: rng.Source}
+						opts.RNG = rng
+						opened, _ = board.FirstClickWithOptions(location, opts)
+						code = ""
+					}
+					gameInit = true
+					replay = msreplay.New(board, g.randSeed, shown)
+					opts.RNG = nil
+					replay.Options, replay.Draws, replay.Code = opts, rng.Draws, code
+					if nil != status {
+						status.setCode(code, g.randSeed)
+					}
+					replay.Record(move, time.Now())
+				default:
+					// the batch's cells were checked when it was read
+					opened, _, _ = board.Apply(move)
+					if move.Type == msboard.MoveFlag {
+						lastMove.Set(location, nil)
+					}
+					replay.Record(move, time.Now())
+				}
+
+				view.Follow(location, board.Rows(), board.Cols())
+				if g.verbose {
+					summaries = append(summaries, g.describeMove(board, move, opened))
+				}
+				if cue, ok := moveCue(board, move, opened); ok {
+					sounds.Play(cue)
+				}
+				if move.Type == msboard.MoveFlag {
+					continue
+				}
+				// terminals can show a cascade spreading; the time it takes to draw is kept off the clock
+				if g.cascade > 0 && caps.TTY {
+					drawing := time.Now()
+					msrender.Cascade(out, renderer, board.Snapshot(), msboard.CascadeWaves(board.Snapshot(), opened),
+						g.cascade, time.Sleep)
+					g.discount(time.Since(drawing))
+				}
+				for _, l := range opened {
+					batchRevealed[l] = true
+				}
+				revealed = append(revealed, opened...)
+				lastMove.Set(location, revealed)
+				reveals++
+				if arcade {
+					g.collect(out, board, opened)
+				}
+				if moving && reveals%g.moveEvery == 0 {
+					if shifts := board.ShiftMines(g.moving, g.rng); len(shifts) > 0 {
+						moved += len(shifts)
+						fmt.Fprintf(out, "The ground shifts: %d mines moved out of sight\n", len(shifts))
+					}
+				}
+			}
+			if nil != travel {
+				travel.record(board)
+			}
+
+			render()
+			for _, summary := range summaries {
+				fmt.Fprintln(out, summary)
+			}
+			if g.guessFree {
+				writeProgress(out, board.Snapshot(), caps.UTF8)
+			}
+			if arcade {
+				writeArcade(out, g.powerUps)
+			}
 		}
 
+		stopLive()
+		// puzzles are retried from the puzzle menu, which restores their revealed cells
+		if gameInit && nil == puzzle {
+			lastBoard, lastCode = board, code
+		}
+		if nil != replay {
+			rated := g.ratedBoard(board) && !assisted && !powered && !earlier.restored
+			result := g.recordResult(board, len(replay.Moves), rated)
+			fmt.Fprintf(out, "\nGame %v in %s", result.Status, result.Played.Round(100*time.Millisecond))
+			if result.Pauses > 0 {
+				fmt.Fprintf(out, " (plus %s paused)", result.Paused.Round(100*time.Millisecond))
+			}
+			if arcade {
+				fmt.Fprintf(out, ", scoring %d", result.Score)
+			}
+			fmt.Fprintln(out, ratedText(rated))
+			if code != "" {
+				fmt.Fprintf(out, "Board code %s\n", code)
+			}
+			if !g.analysis && nil == puzzle {
+				// puzzles keep records of their own
+				g.finishHistory(out, board, result)
+			}
+			if g.analysis {
+				// kept apart from rated play
+				fmt.Fprintln(out, "Analysis games aren't recorded, reviewed or saved")
+			} else if earlier.restored {
+				// a replay can't go back to a bookmark
+				fmt.Fprintln(out, "Bookmarks were restored during the game, so it isn't reviewed or saved")
+			} else if nil != puzzle {
+				// a replay only keeps the mines, not the cells the puzzle starts with revealed, so puzzles aren't
+				// reviewed or saved
+				g.finishPuzzle(out, *puzzle, result, caps.UTF8)
+			} else if powered {
+				// defusers and free flags aren't moves a replay can hold
+				fmt.Fprintln(out, "Power-ups were used during the game, so it isn't reviewed or saved")
+			} else if moved > 0 {
+				// a replay only keeps the mines as they were laid, so games where they moved aren't saved
+				fmt.Fprintf(out, "%d mines moved during the game, so it isn't reviewed or saved\n", moved)
+			} else {
+				replay.Unrated = !rated
+				g.finishReplay(out, *replay)
+			}
+			g.syncStore(out)
+		}
 	}
 
 game_over:
+	stopLive()
+	out.Flush()
 	return nil
 }
 
-// readNextMove -- read and parse an input line into a cell location
-func readNextMove(in *bufio.Scanner) (string, msboard.Location, error) {
-	/*
-	   A move is picking a cell position, which are numbered for rows and letters for columns
-	   The intent is to allow teh user to specify a row+column combo in whatever order they prefer
-	   We'll gather the digits and letters separately to figure out the intended location
-	*/
+// finishReplay -- print the post-game review of a finished game and save its replay
+func (g *Game) finishReplay(out io.Writer, replay msreplay.Replay) {
+	if notes, err := msreplay.Analyze(replay, msanalysis.ReviewProber()); err == nil {
+		fmt.Fprintln(out, "\nMove review:")
+		msreplay.WriteReport(out, notes)
+	}
 
-	inLine, err := readInput(in)
-	if err != nil {
-		return "", msboard.NewLocation(-1, -1), err
-	}
-	digits := ""
-	letters := make([]rune, 0)
-	inputRunes := []rune(inLine)
-	for i := 0; i < len(inputRunes); i++ {
-		if unicode.IsDigit(inputRunes[i]) {
-			digits += string(inputRunes[i])
+	if limited, err := replay.Limit(g.replayMax); err != nil {
+		fmt.Fprintln(out, "failed to save replay:", err)
+		return
+	} else if limited.Compacted > replay.Compacted {
+		fmt.Fprintf(out, "replay too big to save in full, kept the last %d moves\n", len(limited.Moves))
+		replay = limited
+	}
+
+	if nil != g.store {
+		key := msstore.ReplayKey(time.Now())
+		if err := msstore.SaveReplay(g.store, key, replay); err != nil {
+			fmt.Fprintln(out, "failed to save replay:", err)
+		} else {
+			fmt.Fprintf(out, "replay saved as %s\n", key)
+		}
+	} else if g.replayDir != "" {
+		filename := filepath.Join(g.replayDir, fmt.Sprintf("replay-%d.json", time.Now().Unix()))
+		if err := msreplay.SaveFile(filename, replay); err != nil {
+			fmt.Fprintln(out, "failed to save replay:", err)
 		} else {
-			letters = append(letters, inputRunes[i])
+			fmt.Fprintf(out, "replay saved as %s\n", filename)
 		}
 	}
+}
 
-	userRow, err := strconv.Atoi(digits)
+// debugRevealRegion -- handle the debug "reveal A1:C3" command; a single location reveals just that cell
+func (g *Game) debugRevealRegion(board *msboard.Board, args []string) error {
+	from, to, err := g.parseRange(strings.Join(args, " "), board)
 	if err != nil {
-		userRow = -1
+		return err
 	}
-	userRow-- // offset because locations are 0 based, user sees 1 as first row
 
-	userCol := -1
-	if len(letters) > 0 {
-		userCol = int(letters[0]) - int('a')
+	_, err = board.RevealRegion(from, to)
+	return err
+}
+
+// dumpSolver -- the second half of the debug "dump" command: what the solver makes of the position the player
+// sees, the cells it proves safe or mined and every unknown cell's chance of a mine as a percentage. Positions too
+// complex to enumerate show the sampler's estimates, which prove nothing
+func (g *Game) dumpSolver(out io.Writer, board *msboard.Board) {
+	s := board.Snapshot()
+	probabilities, margins, err := mssolver.Fallback{Exact: mssolver.Endgame{}}.Estimate(s)
+	if err != nil {
+		fmt.Fprintln(out, "solver:", err)
+		return
+	}
+
+	var safe, mines []string
+	codec := g.codec(board)
+	for row := range s.Cells {
+		for col, v := range s.Cells[row] {
+			if v.State != msboard.CellHidden && v.State != msboard.CellFlagged {
+				continue
+			}
+			if l := msboard.NewLocation(row, col); nil == margins && probabilities[row][col] == 0 {
+				safe = append(safe, g.cellName(l, board))
+			} else if nil == margins && probabilities[row][col] == 1 {
+				mines = append(mines, g.cellName(l, board))
+			}
+		}
+	}
+	if nil == margins {
+		fmt.Fprintf(out, "solver: safe %v, mines %v\n", safe, mines)
+	} else {
+		fmt.Fprintln(out, "solver: too complex to enumerate, sampled estimates")
+	}
+
+	fmt.Fprint(out, "chance of a mine, %:\n   ")
+	for col := 0; col < s.Cols; col++ {
+		fmt.Fprintf(out, "%4s", codec.ColLabel(col, s.Cols))
+	}
+	fmt.Fprintln(out)
+	for row := range s.Cells {
+		fmt.Fprintf(out, "%3s", codec.RowLabel(row, s.Rows))
+		for col, v := range s.Cells[row] {
+			if v.State == msboard.CellHidden || v.State == msboard.CellFlagged {
+				fmt.Fprintf(out, "%4.0f", 100*probabilities[row][col])
+			} else {
+				fmt.Fprint(out, "   .")
+			}
+		}
+		fmt.Fprintln(out)
+	}
+}
+
+// commandWords : first words of an input line that name a command rather than start a location
+var commandWords = map[string]bool{
+	"s": true, "f": true, "c": true,
+	"^": true, "v": true, "<": true, ">": true,
+	"pause": true, "hint": true,
+	"bookmark": true, "restore": true, "bookmarks": true, "undo": true, "peek": true,
+	"defuse": true, "freeflag": true,
+	"xray": true, "reveal": true, "dump": true, "audit": true,
+	"?": true,
+}
+
+// readCommand -- read an input line and split it into a command word and its arguments. Lines that don't start
+// with a command word are a location to step on, i.e. an implicit "s"
+func readCommand(in *bufio.Scanner) (string, []string, error) {
+	inLine, err := readInput(in)
+	if err != nil {
+		return "", nil, err
 	}
 
-	return "s", msboard.NewLocation(userRow, userCol), err
+	words := strings.Fields(inLine)
+	if len(words) > 0 && commandWords[words[0]] {
+		return words[0], words[1:], nil
+	}
+	return "s", words, nil
+}
+
+// SetCoordinates -- the scheme cells are typed in, named in messages and labelled with on screen; nil for the
+// default of each board's topology, letter-number on the grid
+func (g *Game) SetCoordinates(codec msboard.LocationCodec) {
+	g.coords = codec
+}
+
+// SetRightToLeft -- draw row labels on the right of the board instead of the left, for players reading right to left
+func (g *Game) SetRightToLeft(rtl bool) {
+	g.rtl = rtl
+}
+
+// SetTranspose -- play the preset boards turned on their side, Width and Height swapped, so "hard" is 30 rows of 16
+func (g *Game) SetTranspose(transpose bool) {
+	g.transpose = transpose
+}
+
+// newBoard -- a new preset board of a difficulty, turned if the player asked for it
+func (g *Game) newBoard(difficulty string) *msboard.Board {
+	if g.transpose {
+		return msboard.NewTransposedBoard(difficulty)
+	}
+	return msboard.NewBoard(difficulty)
+}
+
+// codec -- the coordinate scheme for a board
+func (g *Game) codec(b *msboard.Board) msboard.LocationCodec {
+	if nil != g.coords {
+		return g.coords
+	}
+	if t, err := msboard.LookupTopology(b.Topology()); err == nil {
+		return t.Codec
+	}
+	return msboard.LetterNumberCodec{}
+}
+
+// parseLocation -- parse a cell location the player typed for a board, in the game's coordinate scheme
+func (g *Game) parseLocation(text string, b *msboard.Board) (msboard.Location, error) {
+	return g.codec(b).Parse(text, b.Rows(), b.Cols())
+}
+
+// cellName -- location on a board as the player types it; the inverse of parseLocation
+func (g *Game) cellName(l msboard.Location, b *msboard.Board) string {
+	return g.codec(b).Format(l, b.Rows(), b.Cols())
+}
+
+// scrollStep -- rows and columns to scroll for a scroll command: "^", "v", "<" or ">" move half a viewport
+func scrollStep(cmd string, view msrender.Viewport) (dRow, dCol int, ok bool) {
+	rowStep, colStep := view.Rows/2+1, view.Cols/2+1
+
+	switch cmd {
+	case "^":
+		return -rowStep, 0, true
+	case "v":
+		return rowStep, 0, true
+	case "<":
+		return 0, -colStep, true
+	case ">":
+		return 0, colStep, true
+	}
+	return 0, 0, false
 }
 
 // readOneCharacter -- consume a line of input but return only the first non-whitespace character
@@ -179,16 +933,27 @@ func readOneCharacter(in *bufio.Scanner) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if len(inLine) == 0 {
+		return "", fmt.Errorf("empty input line")
+	}
 
 	return inLine[0:1], nil
 }
 
+// readInput -- read a trimmed, lower cased input line
 func readInput(in *bufio.Scanner) (string, error) {
+	line, err := readLine(in)
+	return strings.ToLower(line), err
+}
+
+// readLine -- read a trimmed input line, preserving case
+func readLine(in *bufio.Scanner) (string, error) {
 	if !in.Scan() {
-		return "", fmt.Errorf("Error or EOF during console read")
+		if err := in.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF // end of input ends the game
 	}
 
-	line := strings.Trim(in.Text(), " \n")
-	line = strings.ToLower(line)
-	return line, nil
+	return strings.Trim(in.Text(), " \n"), nil
 }
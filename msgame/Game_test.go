@@ -1,7 +1,11 @@
 package msgame
 
 import (
+	"bufio"
+	"bytes"
+	"go-mines/msboard"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -16,3 +20,205 @@ func TestRecordedGame(t *testing.T) {
 
 	err = game.RunConsole(infile, os.Stdout)
 }
+
+func TestParseLocation(t *testing.T) {
+	var cases = []struct {
+		text     string
+		row, col int
+		valid    bool
+	}{
+		{"a1", 0, 0, true},
+		{"3c", 2, 2, true},
+		{"c 12", 11, 2, true},
+		{"aa10", 9, 26, true},
+		{"a", -1, -1, false},
+		{"12", -1, -1, false},
+	}
+
+	game, board := New(1995), msboard.NewBoard("hard")
+	for _, testcase := range cases {
+		got, err := game.parseLocation(testcase.text, board)
+		if (err == nil) != testcase.valid {
+			t.Errorf("parseLocation(%q) validity wanted %v got err %v", testcase.text, testcase.valid, err)
+			continue
+		}
+		if testcase.valid && (got.Row() != testcase.row || got.Col() != testcase.col) {
+			t.Errorf("parseLocation(%q) wanted %d,%d got %d,%d", testcase.text, testcase.row, testcase.col, got.Row(), got.Col())
+		}
+	}
+}
+
+func TestReadCommand(t *testing.T) {
+	var cases = []struct {
+		line string
+		cmd  string
+		args int
+	}{
+		{"a1", "s", 1},
+		{"f b2", "f", 1},
+		{"F3", "s", 1}, // column F, not a flag command
+		{"reveal a1:c3", "reveal", 1},
+		{"xray", "xray", 0},
+		{">", ">", 0},
+	}
+
+	for _, testcase := range cases {
+		in := bufio.NewScanner(strings.NewReader(testcase.line + "\n"))
+		cmd, args, err := readCommand(in)
+		if err != nil || cmd != testcase.cmd || len(args) != testcase.args {
+			t.Errorf("readCommand(%q) wanted %q with %d args, got %q %v err %v", testcase.line, testcase.cmd, testcase.args, cmd, args, err)
+		}
+	}
+}
+
+func TestDebugCommands(t *testing.T) {
+	game := New(1995)
+	game.SetDebug(true)
+
+	script := "e\na1\nxray\nreveal a1:c3\ndump\naudit\nq\n"
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader(script), out); err != nil {
+		t.Errorf("Debug game failed: %s", err)
+	}
+	if !strings.Contains(out.String(), "safe remaining") {
+		t.Errorf("Debug dump missing from output:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "solver: safe") || !strings.Contains(out.String(), "chance of a mine") {
+		t.Errorf("Debug dump missing the solver's view from output:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "audit ok") {
+		t.Errorf("Debug audit missing from output:\n%s", out.String())
+	}
+}
+
+func TestSeededBoards(t *testing.T) {
+	// the same seed lays out the same boards, which x-ray shows
+	var outputs []string
+	for run := 0; run < 2; run++ {
+		game, out := New(42), bytes.NewBufferString("")
+		game.SetDebug(true)
+		if err := game.RunConsole(strings.NewReader("e\na1\nxray\nq\n"), out); err != nil {
+			t.Fatalf("seeded game failed: %s", err)
+		}
+		outputs = append(outputs, out.String())
+	}
+	if outputs[0] != outputs[1] {
+		t.Errorf("seed 42 laid out different boards:\n%s\n%s", outputs[0], outputs[1])
+	}
+}
+
+func TestRetryBoard(t *testing.T) {
+	game := New(1995)
+
+	// d1 is a mine on this seed, so the retried board explodes in the same place
+	script := "e\na1\nd1\nr\nd1\nq\n"
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader(script), out); err != nil {
+		t.Fatalf("retried game failed: %s", err)
+	}
+
+	results := game.Results()
+	if len(results) != 2 || results[1].Status != msboard.StatusLost || results[1].Moves != 1 {
+		t.Errorf("retry wanted a second game lost in 1 move got %+v", results)
+	}
+	if !strings.Contains(out.String(), "[R]etry last board") {
+		t.Errorf("retry option not offered after a game")
+	}
+}
+
+func TestBoardCode(t *testing.T) {
+	game := New(1995)
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("e\na1\nd1\nq\n"), out); err != nil {
+		t.Fatalf("game failed: %s", err)
+	}
+	const code = "1.pcg32.9x9x10.1548714027.A1"
+	if !strings.Contains(out.String(), "Board code "+code+"\n") {
+		t.Fatalf("board code missing after the game:\n%s", out.String())
+	}
+
+	// another seed plays the shared board, exploding in the same place
+	parsed, err := msboard.ParseBoardCode(code)
+	if err != nil {
+		t.Fatalf("ParseBoardCode failed: %s", err)
+	}
+	shared := New(42)
+	if err := shared.SetBoardCode(parsed); err != nil {
+		t.Fatalf("SetBoardCode failed: %s", err)
+	}
+	out.Reset()
+	if err := shared.RunConsole(strings.NewReader("c\na1\nd1\nq\n"), out); err != nil {
+		t.Fatalf("shared game failed: %s", err)
+	}
+	for _, want := range []string{"[C]ode " + code, "laid out around A1", "Board code " + code} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("shared game output missing %q:\n%s", want, out.String())
+		}
+	}
+	if results := shared.Results(); len(results) != 1 || results[0].Status != msboard.StatusLost || results[0].Moves != 2 {
+		t.Errorf("shared board wanted a game lost in 2 moves got %+v", results)
+	}
+
+	parsed.Generator++
+	if err := shared.SetBoardCode(parsed); nil == err {
+		t.Errorf("SetBoardCode accepted a code from another generator version")
+	}
+}
+
+func TestOpeningHint(t *testing.T) {
+	game := New(1995)
+
+	script := "e\nhint\na1\nhint\nq\n"
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader(script), out); err != nil {
+		t.Fatalf("hinted game failed: %s", err)
+	}
+	if !strings.Contains(out.String(), "Hint: start at E1") {
+		t.Errorf("first move hint missing from output:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "only available for the first move") {
+		t.Errorf("later hint should be refused:\n%s", out.String())
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	for _, transpose := range []bool{false, true} {
+		game := New(1995)
+		game.SetTranspose(transpose)
+
+		out := bytes.NewBufferString("")
+		if err := game.RunConsole(strings.NewReader("h\nhint\nq\n"), out); err != nil {
+			t.Fatalf("hard game failed: %s", err)
+		}
+		// the hint, at the middle of a short edge, turns with the board
+		header, hint := "AD\n", "Hint: start at A8"
+		if transpose {
+			header, hint = "  P\n", "Hint: start at H1"
+		}
+		if !strings.Contains(out.String(), header) || !strings.Contains(out.String(), hint) {
+			t.Errorf("transpose %v wanted header ending %q and %q in:\n%s", transpose, header, hint, out.String())
+		}
+	}
+}
+
+func TestMovingMines(t *testing.T) {
+	game := New(1995)
+	if err := game.SetMovingMines(1.5, 1); err == nil {
+		t.Errorf("SetMovingMines should refuse a fraction over 1")
+	}
+	if err := game.SetMovingMines(0.5, 0); err == nil {
+		t.Errorf("SetMovingMines should refuse moves with no reveals between them")
+	}
+	if err := game.SetMovingMines(0.5, 1); err != nil {
+		t.Fatalf("SetMovingMines failed: %s", err)
+	}
+
+	// a hard board still has mines out of sight after the first click, and they move straight away
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("h\nh8\n"), out); err != nil {
+		t.Fatalf("moving mines game failed: %s", err)
+	}
+	if !strings.Contains(out.String(), "The ground shifts:") {
+		t.Errorf("moving mines game should report the mines moving:\n%s", out.String())
+	}
+}
@@ -0,0 +1,131 @@
+/*
+
+	Help.go - the "?" command: a help screen built from the game as it is set up, listing only the commands the
+	game in play accepts, how cells are named on its board and the rules it is played by, so it stays current as
+	commands and variants are added
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"io"
+	"strings"
+)
+
+// helpState : what the game in play allows, beyond the game's own settings
+type helpState struct {
+	started bool // the first cell is uncovered
+	arcade  bool // power-ups can be spent
+	travel  bool // every position is kept, see TimeTravel.go
+}
+
+// helpCommand : a line of the help screen and when the command it describes is accepted
+type helpCommand struct {
+	usage string
+	about string
+	when  func(g *Game, h helpState) bool // nil for always
+}
+
+// helpCommands : every command a game accepts, in the order the help screen lists them
+var helpCommands = []helpCommand{
+	{"<cell>, s <cell>", "uncover a cell; ranges (a1:c3) and lists (a1 b2) play several", nil},
+	{"f <cell>", "flag or unflag a cell, or every cell of a range or list",
+		func(g *Game, h helpState) bool { return h.started }},
+	{"c <cell>", "chord a score with all its flags placed, uncovering its other neighbors",
+		chordOnly},
+	{"hint", "where to start, leaving the game unrated",
+		func(g *Game, h helpState) bool { return !h.started }},
+	{"^ v < >", "scroll a board too big for the screen half a screen up, down, left or right", nil},
+	{"pause", "hide the board and stop the clock until enter is pressed", nil},
+	{"bookmark <name>", "keep the position under a name", practiceOnly},
+	{"restore <name>", "go back to a bookmarked position", practiceOnly},
+	{"bookmarks", "list the bookmarks", practiceOnly},
+	{"undo", "take back the last move", func(g *Game, h helpState) bool { return g.analysis }},
+	{"peek <cell>", "look under a cell", func(g *Game, h helpState) bool { return g.analysis }},
+	{"defuse <cell>", "spend a defuser on a cell and its neighbors", arcadeOnly},
+	{"freeflag", "spend a free flag on a mine", arcadeOnly},
+	{"xray", "show or hide the mines", debugOnly},
+	{"reveal <cell>:<cell>", "uncover a range, mines and all", debugOnly},
+	{"dump", "print the board's internals and what the solver deduces", debugOnly},
+	{"audit", "check the board's counts", debugOnly},
+	{"goto <position>", "go back or forward to a position, e.g. 12 or 2:12", travelOnly},
+	{"fork", "start a new line from the current position", travelOnly},
+	{"lines", "list the lines", travelOnly},
+	{"diff <position> <position>", "compare two positions", travelOnly},
+	{"?", "this help", nil},
+}
+
+// chordOnly -- true under rules that chord, once the first cell is uncovered
+func chordOnly(g *Game, h helpState) bool {
+	return h.started && nil != g.rules && g.rules.Name() != (msboard.ClassicRules{}).Name()
+}
+
+// practiceOnly -- true in practice and analysis games once the first cell is uncovered
+func practiceOnly(g *Game, h helpState) bool {
+	return g.practicing() && h.started
+}
+
+// arcadeOnly -- true in arcade games once the first cell is uncovered
+func arcadeOnly(g *Game, h helpState) bool {
+	return h.arcade && h.started
+}
+
+// debugOnly -- true with developer commands enabled, once the first cell is uncovered
+func debugOnly(g *Game, h helpState) bool {
+	return g.debug && h.started
+}
+
+// travelOnly -- true in debug builds with developer commands enabled, once the first cell is uncovered
+func travelOnly(g *Game, h helpState) bool {
+	return debugOnly(g, h) && h.travel
+}
+
+// writeHelp -- print the help screen for a game on the board
+func (g *Game) writeHelp(out io.Writer, board *msboard.Board, h helpState) {
+	fmt.Fprintln(out, "\nCommands:")
+	for _, c := range helpCommands {
+		if nil == c.when || c.when(g, h) {
+			fmt.Fprintf(out, "  %-22s %s\n", c.usage, c.about)
+		}
+	}
+
+	first, last := msboard.NewLocation(0, 0), msboard.NewLocation(board.Rows()-1, board.Cols()-1)
+	fmt.Fprintf(out, "Cells are named from %s, top left, to %s, bottom right\n", g.cellName(first, board),
+		g.cellName(last, board))
+	fmt.Fprintf(out, "Rules: %s\n", strings.Join(g.ruleNotes(board, h), "; "))
+}
+
+// ruleNotes -- the rules the game on the board is played by, the board's first and then the game's variants
+func (g *Game) ruleNotes(board *msboard.Board, h helpState) []string {
+	retval := []string{board.Rules().Name()}
+	if board.Topology() != msboard.GridTopology {
+		retval = append(retval, board.Topology()+" cells")
+	}
+	if board.Propagation() == msboard.PropagateZerosOnly {
+		retval = append(retval, "cascades only open zeros")
+	}
+	if board.Fog() > 0 {
+		retval = append(retval, fmt.Sprintf("fog, only cells within %d of a revealed one can be uncovered", board.Fog()))
+	}
+	if board.AntiMineCount() > 0 {
+		retval = append(retval, fmt.Sprintf("%d anti-mines, which count -1", board.AntiMineCount()))
+	}
+	if g.moving > 0 {
+		retval = append(retval, fmt.Sprintf("%.0f%% of the mines move every %d reveals", 100*g.moving, g.moveEvery))
+	}
+	if h.arcade {
+		retval = append(retval, "arcade treasures")
+	}
+	if g.guessFree {
+		retval = append(retval, "guess-free indicator")
+	}
+	if !g.ratedBoard(board) {
+		retval = append(retval, "unrated")
+	}
+	return retval
+}
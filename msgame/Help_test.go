@@ -0,0 +1,73 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"strings"
+	"testing"
+)
+
+func TestHelp(t *testing.T) {
+	game := New(1995)
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("e\n?\na1\n?\nq\n"), out); err != nil {
+		t.Fatalf("game failed: %s", err)
+	}
+	screens := strings.Split(out.String(), "\nCommands:")
+	if len(screens) != 3 {
+		t.Fatalf("wanted 2 help screens got %d:\n%s", len(screens)-1, out.String())
+	}
+
+	before, after := screens[1], screens[2]
+	for _, c := range []struct {
+		screen, text string
+		want         bool
+	}{
+		{before, "hint", true},
+		{before, "f <cell>", false},
+		{after, "hint", false},
+		{after, "f <cell>", true},
+		{after, "bookmark", false},
+		{after, "xray", false},
+		{after, "Cells are named from A1, top left, to I9, bottom right", true},
+		{after, "Rules: classic\n", true},
+	} {
+		if got := strings.Contains(c.screen, c.text); got != c.want {
+			t.Errorf("help screen containing %q wanted %v:\n%s", c.text, c.want, c.screen)
+		}
+	}
+}
+
+func TestHelpVariants(t *testing.T) {
+	game := New(1995)
+	game.practice = true
+	b := msboard.NewBoard("easy")
+	b.SetRules(msboard.ChordRules{})
+	b.SetFog(2)
+
+	out := bytes.NewBufferString("")
+	game.writeHelp(out, b, helpState{started: true})
+	for _, want := range []string{"bookmark <name>",
+		"Rules: chord; fog, only cells within 2 of a revealed one can be uncovered; unrated"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("help screen missing %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "undo") {
+		t.Errorf("practice game's help offers analysis commands:\n%s", out.String())
+	}
+}
+
+func TestHelpChosenRules(t *testing.T) {
+	game := New(1995)
+	game.SetRules(msboard.ChordRules{})
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("e\na1\n?\nq\n"), out); err != nil {
+		t.Fatalf("game failed: %s", err)
+	}
+	for _, want := range []string{"Rules: chord;", "c <cell>"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("help screen missing %q:\n%s", want, out.String())
+		}
+	}
+}
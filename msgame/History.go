@@ -0,0 +1,99 @@
+/*
+
+	History.go - the player's history of finished games, the skill rating it earns, and the stats screen that shows
+	both
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/msstats"
+	"go-mines/msstore"
+	"io"
+	"time"
+)
+
+// SetHistoryFile -- keep the history of finished games in a file, loading what's already there. Without one,
+// history is only kept for the session
+func (g *Game) SetHistoryFile(filename string) error {
+	history, err := msstats.LoadHistory(filename)
+	if err != nil {
+		return err
+	}
+	g.history, g.histFile = history, filename
+	return nil
+}
+
+// playerHistory -- the history of finished games, created empty on first use
+func (g *Game) playerHistory() *msstats.History {
+	if nil == g.history {
+		g.history = &msstats.History{}
+	}
+	return g.history
+}
+
+// finishHistory -- add a finished game to the history, telling the player how a rated game moved their rating
+func (g *Game) finishHistory(out io.Writer, board *msboard.Board, result GameResult) {
+	h := g.playerHistory()
+	before := h.Rating()
+	r := msstats.Record{
+		Date:       time.Now(),
+		Difficulty: result.Difficulty,
+		Seed:       g.randSeed,
+		Metrics:    msstats.MetricsOf(board),
+		Won:        result.Status == msboard.StatusWon,
+		Time:       result.Played.Seconds(),
+		Moves:      result.Moves,
+		Rated:      result.Rated,
+	}
+	h.Add(r)
+	if result.Rated {
+		after := h.Rating()
+		fmt.Fprintf(out, "Rating %s (%+.0f)\n", ratingText(after), after.Value-before.Value)
+	}
+
+	if nil != g.store {
+		if err := msstore.AddGame(g.store, r); err != nil {
+			fmt.Fprintln(out, "failed to save history:", err)
+		}
+	} else if g.histFile != "" {
+		if err := msstats.SaveHistory(g.histFile, h); err != nil {
+			fmt.Fprintln(out, "failed to save history:", err)
+		}
+	}
+}
+
+// ratingText -- a rating rounded for display, marked while it is still provisional
+func ratingText(r msstats.Rating) string {
+	if r.Provisional() {
+		return fmt.Sprintf("%.0f?", r.Value)
+	}
+	return fmt.Sprintf("%.0f", r.Value)
+}
+
+// writeStats -- the stats screen: games played, won and the best rated time for each difficulty, and the rating
+func (g *Game) writeStats(out io.Writer) {
+	h := g.playerHistory()
+	fmt.Fprintf(out, "\n%-12s %6s %6s %6s %10s\n", "Difficulty", "Played", "Won", "Win %", "Best")
+	for _, s := range h.Summaries() {
+		best := "-"
+		if s.Best > 0 {
+			best = s.Best.Round(100 * time.Millisecond).String()
+		}
+		fmt.Fprintf(out, "%-12s %6d %6d %5.0f%% %10s\n", s.Difficulty, s.Played, s.Won,
+			100*float64(s.Won)/float64(s.Played), best)
+	}
+
+	r := h.Rating()
+	if r.Games == 0 {
+		fmt.Fprintln(out, "\nNo rated games yet, rated games earn a skill rating")
+	} else {
+		fmt.Fprintf(out, "\nRating %s from %d rated games\n", ratingText(r), r.Games)
+	}
+	fmt.Fprintln(out)
+}
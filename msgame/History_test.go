@@ -0,0 +1,51 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/msstats"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "history.json")
+	g := New(1995)
+	if err := g.SetHistoryFile(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	// d1 is a mine on this seed: two lost games, then the stats screen
+	out := bytes.NewBufferString("")
+	if err := g.RunConsole(strings.NewReader("e\na1\nd1\nr\nd1\ns\nq\n"), out); err != nil {
+		t.Fatalf("game failed: %s", err)
+	}
+	if !strings.Contains(out.String(), "[S]tats") || !strings.Contains(out.String(), "Rating 14") {
+		t.Errorf("no rating change or stats choice after a lost game:\n%s", out)
+	}
+	if !strings.Contains(out.String(), "easy              2      0") ||
+		!strings.Contains(out.String(), "from 2 rated games") {
+		t.Errorf("stats screen wrong:\n%s", out)
+	}
+
+	h, err := msstats.LoadHistory(filename)
+	if err != nil || len(h.Games) != 2 {
+		t.Fatalf("history file %v %+v", err, h)
+	}
+	if r := h.Games[0]; r.Difficulty != "easy" || r.Won || !r.Rated || r.Seed != 1995 || r.ThreeBV == 0 {
+		t.Errorf("record %+v", r)
+	}
+	if h.Rating().Value >= msstats.InitialRating {
+		t.Errorf("two losses left the rating at %.0f", h.Rating().Value)
+	}
+
+	// analysis games aren't kept
+	g = New(1995)
+	g.SetAnalysis(true)
+	if err := g.RunConsole(strings.NewReader("e\na1\nd1\n\nq\n"), bytes.NewBufferString("")); err != nil {
+		t.Fatalf("analysis game failed: %s", err)
+	}
+	if nil != g.history {
+		t.Errorf("analysis game added to the history: %+v", g.history)
+	}
+}
@@ -0,0 +1,328 @@
+/*
+
+	LineEditor.go - readline-style editing of the lines typed at a terminal: a history recalled with the arrow keys,
+	cursor movement and deletion within the line, and tab completion of commands and column letters
+
+	The terminal is put in raw mode so keys arrive as they are pressed, and the editor echoes the line itself. Input
+	that isn't a terminal, such as a script piped in, is read as it is.
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"bufio"
+	"fmt"
+	"go-mines/msboard"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// historySize : most lines the editor remembers
+const historySize = 100
+
+// lineEditor : an io.Reader of the lines edited on a raw mode terminal, each ending with a newline, for the game's
+// scanners to read like any other input
+type lineEditor struct {
+	keys     *bufio.Reader
+	echo     io.Writer
+	history  []string // lines entered, oldest first
+	commands []string // words completing the first word on a line
+	columns  []string // column labels completing any word, lower case
+	pending  []byte   // rest of the last line edited, not yet read
+}
+
+// newLineEditor -- an editor reading keys from keys and drawing the line being edited on echo
+func newLineEditor(keys io.Reader, echo io.Writer) *lineEditor {
+	return &lineEditor{keys: bufio.NewReader(keys), echo: echo}
+}
+
+// editedInput -- cin with line editing when it is a terminal and editing hasn't been turned off, and the function
+// restoring the terminal once the game is done with it
+func (g *Game) editedInput(cin io.Reader, echo io.Writer) (io.Reader, func()) {
+	g.editor = nil
+	f, ok := cin.(*os.File)
+	if g.noEditing || !ok {
+		return cin, func() {}
+	}
+	restore, err := rawMode(f)
+	if err != nil {
+		return cin, func() {}
+	}
+	g.editor = newLineEditor(f, echo)
+	return g.editor, restore
+}
+
+// SetLineEditing -- edit console input lines as they are typed, with history and tab completion, when the input
+// is a terminal; on by default
+func (g *Game) SetLineEditing(enabled bool) {
+	g.noEditing = !enabled
+}
+
+// complete -- tab complete commands from words and the column labels of board, if input is being edited
+func (g *Game) complete(words []string, board *msboard.Board) {
+	if nil == g.editor {
+		return
+	}
+	g.editor.commands = words
+	g.editor.columns = g.editor.columns[:0]
+	codec := g.codec(board)
+	for col := 0; col < board.Cols(); col++ {
+		g.editor.columns = append(g.editor.columns, strings.ToLower(codec.ColLabel(col, board.Cols())))
+	}
+}
+
+// Read -- the edited lines, read one at a time
+func (e *lineEditor) Read(p []byte) (int, error) {
+	if len(e.pending) == 0 {
+		line, err := e.ReadLine()
+		if err != nil {
+			return 0, err
+		}
+		e.pending = append([]byte(line), '\n')
+	}
+	n := copy(p, e.pending)
+	e.pending = e.pending[n:]
+	return n, nil
+}
+
+// ReadLine -- edit a line until enter is pressed, returning it without the newline; io.EOF for ctrl-C, ctrl-D on an
+// empty line or the end of the keys
+func (e *lineEditor) ReadLine() (string, error) {
+	var line []rune
+	pos := 0                 // cursor position in line
+	recall := len(e.history) // history entry shown, len(e.history) for the line being typed
+	typed := ""              // the line being typed, kept while browsing the history
+
+	// draw the line from the start, leaving the cursor at to; the cursor was at pos
+	redraw := func(to int) {
+		if pos > 0 {
+			fmt.Fprintf(e.echo, "\x1b[%dD", pos)
+		}
+		fmt.Fprintf(e.echo, "%s\x1b[K", string(line))
+		if back := len(line) - to; back > 0 {
+			fmt.Fprintf(e.echo, "\x1b[%dD", back)
+		}
+		pos = to
+	}
+	// replace the line with text, cursor at its end
+	show := func(text string) {
+		line = []rune(text)
+		redraw(len(line))
+	}
+
+	for {
+		r, _, err := e.keys.ReadRune()
+		if err != nil {
+			if len(line) > 0 {
+				return e.enter(string(line)), nil
+			}
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			redraw(len(line))
+			fmt.Fprint(e.echo, "\n")
+			return e.enter(string(line)), nil
+		case 0x03: // ctrl-C, which raw mode doesn't turn into a signal
+			fmt.Fprint(e.echo, "\n")
+			return "", io.EOF
+		case 0x04: // ctrl-D, end of input on an empty line
+			if len(line) == 0 {
+				return "", io.EOF
+			}
+			if pos < len(line) {
+				line = append(line[:pos], line[pos+1:]...)
+				redraw(pos)
+			}
+		case 0x7f, 0x08: // backspace
+			if pos > 0 {
+				to := pos - 1
+				line = append(line[:to], line[pos:]...)
+				redraw(to)
+			}
+		case 0x01: // ctrl-A
+			redraw(0)
+		case 0x05: // ctrl-E
+			redraw(len(line))
+		case 0x02: // ctrl-B
+			if pos > 0 {
+				redraw(pos - 1)
+			}
+		case 0x06: // ctrl-F
+			if pos < len(line) {
+				redraw(pos + 1)
+			}
+		case 0x0b: // ctrl-K, delete to the end of the line
+			line = line[:pos]
+			redraw(pos)
+		case 0x15: // ctrl-U, delete to the start of the line
+			line = line[pos:]
+			redraw(0)
+		case 0x10, 0x0e: // ctrl-P and ctrl-N
+			recall, typed = e.browse(r == 0x10, recall, typed, string(line), show)
+		case '\t':
+			word := pos
+			for word > 0 && !unicode.IsSpace(line[word-1]) {
+				word--
+			}
+			completion := e.completion(string(line[word:pos]), strings.TrimSpace(string(line[:word])) == "")
+			if completion == "" {
+				fmt.Fprint(e.echo, "\a")
+				break
+			}
+			line = append(line[:pos], append([]rune(completion), line[pos:]...)...)
+			redraw(pos + len([]rune(completion)))
+		case 0x1b:
+			switch e.escape() {
+			case 'A':
+				recall, typed = e.browse(true, recall, typed, string(line), show)
+			case 'B':
+				recall, typed = e.browse(false, recall, typed, string(line), show)
+			case 'C':
+				if pos < len(line) {
+					redraw(pos + 1)
+				}
+			case 'D':
+				if pos > 0 {
+					redraw(pos - 1)
+				}
+			case 'H':
+				redraw(0)
+			case 'F':
+				redraw(len(line))
+			case '~': // delete
+				if pos < len(line) {
+					line = append(line[:pos], line[pos+1:]...)
+					redraw(pos)
+				}
+			}
+		default:
+			if unicode.IsPrint(r) {
+				line = append(line[:pos], append([]rune{r}, line[pos:]...)...)
+				redraw(pos + 1)
+			}
+		}
+	}
+}
+
+// escape -- the key of an escape sequence whose ESC has been read: the final letter of the arrows, home and end,
+// or '~' for delete; 0 for keys the editor doesn't use
+func (e *lineEditor) escape() rune {
+	r, _, err := e.keys.ReadRune()
+	if err != nil || (r != '[' && r != 'O') {
+		return 0
+	}
+	params := ""
+	for {
+		if r, _, err = e.keys.ReadRune(); err != nil {
+			return 0
+		}
+		if r >= 0x40 && r <= 0x7e {
+			break
+		}
+		params += string(r)
+	}
+
+	// home and end come as ESC [ 1 ~ and ESC [ 4 ~ on some terminals, and their ESC [ 7/8 ~ variants
+	switch {
+	case r != '~':
+		return r
+	case params == "3":
+		return '~'
+	case params == "1" || params == "7":
+		return 'H'
+	case params == "4" || params == "8":
+		return 'F'
+	}
+	return 0
+}
+
+// browse -- move back (older) or forward through the history, showing the entry reached. Returns the entry now
+// shown and the line being typed, saved when browsing starts
+func (e *lineEditor) browse(back bool, recall int, typed, line string, show func(string)) (int, string) {
+	if recall == len(e.history) {
+		typed = line
+	}
+	switch {
+	case back && recall > 0:
+		recall--
+	case !back && recall < len(e.history):
+		recall++
+	default:
+		return recall, typed
+	}
+
+	if recall == len(e.history) {
+		show(typed)
+	} else {
+		show(e.history[recall])
+	}
+	return recall, typed
+}
+
+// enter -- add a finished line to the history, unless it is blank or repeats the last one
+func (e *lineEditor) enter(line string) string {
+	if strings.TrimSpace(line) == "" || (len(e.history) > 0 && e.history[len(e.history)-1] == line) {
+		return line
+	}
+	e.history = append(e.history, line)
+	if len(e.history) > historySize {
+		e.history = e.history[len(e.history)-historySize:]
+	}
+	return line
+}
+
+// completion -- text to add to a partly typed word: as much as every matching command and column label share.
+// Commands complete only as the first word, and are followed by a space once unique, ready for a location. Column
+// labels aren't, ready for the row number
+func (e *lineEditor) completion(word string, first bool) string {
+	word = strings.ToLower(word)
+	var matches []string
+	command := false
+	if first {
+		for _, c := range e.commands {
+			if strings.HasPrefix(c, word) {
+				matches = append(matches, c)
+				command = true
+			}
+		}
+	}
+	for _, c := range e.columns {
+		if strings.HasPrefix(c, word) {
+			matches = append(matches, c)
+		}
+	}
+	if len(matches) == 0 {
+		return ""
+	}
+
+	sort.Strings(matches)
+	// sorted, the first and last matches differ soonest
+	shared := matches[0]
+	last := matches[len(matches)-1]
+	for !strings.HasPrefix(last, shared) {
+		shared = shared[:len(shared)-1]
+	}
+	retval := shared[len(word):]
+	if command && len(matches) == 1 {
+		retval += " "
+	}
+	return retval
+}
+
+// sortedWords -- the words of a set, sorted
+func sortedWords(set map[string]bool) []string {
+	retval := make([]string, 0, len(set))
+	for word := range set {
+		retval = append(retval, word)
+	}
+	sort.Strings(retval)
+	return retval
+}
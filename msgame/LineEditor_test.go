@@ -0,0 +1,167 @@
+package msgame
+
+import (
+	"bufio"
+	"bytes"
+	"go-mines/msboard"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// editLines -- the lines an editor makes of keys, up to the end of the keys
+func editLines(t *testing.T, e *lineEditor) []string {
+	var retval []string
+	for {
+		line, err := e.ReadLine()
+		if err == io.EOF {
+			return retval
+		} else if err != nil {
+			t.Fatalf("ReadLine() failed: %s", err)
+		}
+		retval = append(retval, line)
+	}
+}
+
+func TestLineEditorEditing(t *testing.T) {
+	const (
+		up, down, left, right = "\x1b[A", "\x1b[B", "\x1b[D", "\x1b[C"
+		home, end, del        = "\x1b[H", "\x1b[F", "\x1b[3~"
+	)
+	keys := strings.Join([]string{
+		"s c4\r",
+		"f c4\r",
+		up + up + "\x7f5\r", // recall s c4 and correct it
+		up + "\x01\x06x\r",  // recall s c5 and insert after the s
+		"c3" + left + left + "s \r",
+		"a b c" + home + del + del + end + "\x7f\r",
+		"abc\x01\x0b\r",                      // ctrl-K empties the line
+		"abcd" + left + left + "\x15\r",      // ctrl-U keeps the text after the cursor
+		"h1" + up + down + "\r",              // browsing back down returns what was typed
+		"f a1" + left + left + right + "2\r", // ends up as f a21
+	}, "")
+
+	e := newLineEditor(strings.NewReader(keys), io.Discard)
+	got := editLines(t, e)
+	expected := []string{"s c4", "f c4", "s c5", "sx c5", "s c3", "b ", "", "cd", "h1", "f a21"}
+	if strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("edited lines %q, expected %q", got, expected)
+	}
+	if len(e.history) != len(expected)-1 {
+		t.Errorf("history %q should hold every line but the blank one", e.history)
+	}
+}
+
+func TestLineEditorHistory(t *testing.T) {
+	keys := strings.Repeat("a1\r", 3) + strings.Repeat("\x1b[A", 5) + "\r"
+	for i := 0; i < historySize+10; i++ {
+		keys += string(rune('a'+i%26)) + "\r"
+	}
+
+	e := newLineEditor(strings.NewReader(keys), io.Discard)
+	got := editLines(t, e)
+	if got[3] != "a1" {
+		t.Errorf("recalling past the oldest line gave %q, expected a1", got[3])
+	}
+	if len(e.history) != historySize {
+		t.Errorf("history holds %d lines, expected at most %d", len(e.history), historySize)
+	}
+	if e.history[historySize-1] != got[len(got)-1] {
+		t.Errorf("newest history line %q, expected %q", e.history[historySize-1], got[len(got)-1])
+	}
+}
+
+func TestLineEditorEnd(t *testing.T) {
+	for keys, expected := range map[string][]string{
+		"s a1\rf":          {"s a1", "f"}, // an unfinished last line still counts
+		"s a1\r\x04":       {"s a1"},
+		"s a1\r\x03s b2\r": {"s a1"},
+	} {
+		got := editLines(t, newLineEditor(strings.NewReader(keys), io.Discard))
+		if strings.Join(got, "|") != strings.Join(expected, "|") {
+			t.Errorf("keys %q edited to %q, expected %q", keys, got, expected)
+		}
+	}
+}
+
+func TestLineEditorCompletion(t *testing.T) {
+	g := New(1995)
+	g.editor = newLineEditor(strings.NewReader(""), io.Discard)
+	board := msboard.NewLayoutBoard(2, 30)
+	g.complete(sortedWords(commandWords), board)
+
+	for _, test := range []struct {
+		word     string
+		first    bool
+		expected string
+	}{
+		{"fr", true, "eeflag "},
+		{"fr", false, ""},
+		{"a", false, ""}, // a, aa, ab, ac and ad
+		{"pau", true, "se "},
+		{"ad", false, ""}, // already complete
+		{"A", true, ""},   // a, aa, ab, ... and audit
+		{"au", true, "dit "},
+		{"q", false, ""},
+	} {
+		if got := g.editor.completion(test.word, test.first); got != test.expected {
+			t.Errorf("completion(%q, %v) = %q, expected %q", test.word, test.first, got, test.expected)
+		}
+	}
+
+	// tab in a line, completing columns on a 30 column board in the chess scheme
+	g.SetCoordinates(msboard.ChessCodec{})
+	g.editor = newLineEditor(strings.NewReader("pa\tc\t1\r\t\r"), io.Discard)
+	g.complete(sortedWords(commandWords), board)
+	if got := editLines(t, g.editor); strings.Join(got, "|") != "pause c1|" {
+		t.Errorf("completed lines %q, expected pause c1 and an empty line", got)
+	}
+}
+
+func TestLineEditorReader(t *testing.T) {
+	e := newLineEditor(strings.NewReader("s a1\rf\x1b[Db2\r"), io.Discard)
+	in := bufio.NewScanner(e)
+	var got []string
+	for in.Scan() {
+		got = append(got, in.Text())
+	}
+	if strings.Join(got, "|") != "s a1|b2f" {
+		t.Errorf("scanned %q, expected s a1 and b2f", got)
+	}
+}
+
+func TestLineEditorEcho(t *testing.T) {
+	echo := bytes.NewBufferString("")
+	e := newLineEditor(strings.NewReader("ab\x1b[D\x7f\r"), echo)
+	if _, err := e.ReadLine(); err != nil {
+		t.Fatalf("ReadLine() failed: %s", err)
+	}
+	// each key redraws the line from its start, leaving the cursor where it belongs
+	expected := "a\x1b[K" + "\x1b[1Dab\x1b[K" + "\x1b[2Dab\x1b[K\x1b[1D" + "\x1b[1Db\x1b[K\x1b[1D" + "b\x1b[K" + "\n"
+	if echo.String() != expected {
+		t.Errorf("echoed %q, expected %q", echo.String(), expected)
+	}
+}
+
+func TestEditedInputPlain(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "moves.txt")
+	if err := os.WriteFile(filename, []byte("s a1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	g := New(1995)
+	for _, cin := range []io.Reader{strings.NewReader("s a1\n"), f} {
+		in, restore := g.editedInput(cin, io.Discard)
+		if in != cin || nil != g.editor {
+			t.Errorf("input from a %T that isn't a terminal should be read as it is", cin)
+		}
+		restore()
+	}
+}
@@ -0,0 +1,146 @@
+/*
+
+	Multi.go - multiboard mode: several boards played at once, won only when every one is cleared and lost as
+	soon as any mine goes off
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"bufio"
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/msrender"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxBoards : most boards a multiboard game can have, one digit to address each
+const maxBoards = 9
+
+// SetBoards -- play every new easy, medium or hard game on n boards at once; 1 for normal play. Races and puzzles
+// are still played on one board
+func (g *Game) SetBoards(n int) error {
+	if n < 1 || n > maxBoards {
+		return fmt.Errorf("can play 1 to %d boards at once, not %d", maxBoards, n)
+	}
+	g.boards = n
+	return nil
+}
+
+// multiStatus -- combined state of several boards: lost if any is, won once all are, otherwise in play. Boards
+// still waiting for their first click count as in play
+func multiStatus(boards []*msboard.Board) msboard.Status {
+	retval := msboard.StatusWon
+	for _, b := range boards {
+		switch b.Status() {
+		case msboard.StatusLost:
+			return msboard.StatusLost
+		case msboard.StatusUninitialized, msboard.StatusPlaying:
+			retval = msboard.StatusPlaying
+		}
+	}
+	return retval
+}
+
+// parseBoardMove -- split a multiboard move such as "board 2: reveal c4", "2: f b1" or "2 c4" into the 0 based
+// board, the command, s or f, and the location in the game's coordinate scheme
+func (g *Game) parseBoardMove(line string, boards []*msboard.Board) (int, string, msboard.Location, error) {
+	words := strings.Fields(strings.Replace(strings.ToLower(line), ":", " ", 1))
+	if len(words) > 0 && words[0] == "board" {
+		words = words[1:]
+	}
+	if len(words) < 2 {
+		return 0, "", msboard.Location{}, fmt.Errorf("%q is not a move, expected e.g. 2: reveal c4", line)
+	}
+
+	board, err := strconv.Atoi(words[0])
+	if err != nil || board < 1 || board > len(boards) {
+		return 0, "", msboard.Location{}, fmt.Errorf("no board %q, choose 1 to %d", words[0], len(boards))
+	}
+
+	cmd, args := "s", words[1:]
+	switch args[0] {
+	case "s", "reveal":
+		args = args[1:]
+	case "f", "flag":
+		cmd, args = "f", args[1:]
+	case "c", "chord":
+		cmd, args = "c", args[1:]
+	}
+	location, err := g.parseLocation(strings.Join(args, " "), boards[board-1])
+	return board - 1, cmd, location, err
+}
+
+// playMulti -- play one multiboard game of g.boards boards of a difficulty, drawn one under another in the theme
+// the terminal capabilities allow. Returns io.EOF if the input ends first
+func (g *Game) playMulti(in *bufio.Scanner, out *bufio.Writer, caps msrender.Capabilities, difficulty string) error {
+	boards := make([]*msboard.Board, g.boards)
+	for i := range boards {
+		boards[i] = g.newBoard(difficulty)
+		boards[i].SetFog(g.fog)
+		boards[i].SetRules(g.rules)
+	}
+
+	// boards are stacked, so every one is drawn in full each move rather than redrawn in place
+	renderer := msrender.FrameRenderer{Theme: caps.Theme(), Options: msrender.Options{Coordinates: g.coords, RightToLeft: g.rtl}}
+	render := func() {
+		for i, b := range boards {
+			s := b.Snapshot()
+			fmt.Fprintf(out, "\nBoard %d: %s, %d mines, %d flags\n", i+1, s.Status, s.Mines, s.Flags)
+			renderer.Render(out, s)
+		}
+	}
+
+	// moves start with the board number, so only column letters complete
+	g.complete(nil, boards[0])
+	moves := 0
+	g.startClock()
+	render()
+	for multiStatus(boards) == msboard.StatusPlaying {
+		fmt.Fprintf(out, "\nChoose board, command (%s) & location, e.g. 2: s c4 :  ", moveCommands(boards[0]))
+		out.Flush()
+
+		line, err := readInput(in)
+		if err != nil {
+			return err
+		}
+		i, cmd, location, err := g.parseBoardMove(line, boards)
+		if err == nil && !boards[i].ValidLocation(location) {
+			err = fmt.Errorf("%s is not on board %d", g.cellName(location, boards[i]), i+1)
+		}
+		if err != nil {
+			fmt.Fprintln(out, err)
+			continue
+		}
+
+		b := boards[i]
+		switch {
+		case !b.Initialized() && cmd != "s":
+			fmt.Fprintf(out, "Choose a starting cell to uncover first on board %d\n", i+1)
+			continue
+		case b.Initialized() && cmd == "s" && !b.InRange(location):
+			fmt.Fprintf(out, "%s is lost in the fog on board %d\n", g.cellName(location, b), i+1)
+			continue
+		case !b.Initialized():
+			// each board is laid out around its own first click
+			if _, err := b.FirstClickWithOptions(location, g.generatorOptions()); err != nil {
+				fmt.Fprintln(out, err, "- using an unconstrained layout")
+				b.FirstClickWithOptions(location, msboard.GeneratorOptions{RNG: g.rng})
+			}
+		default:
+			b.Apply(msboard.Move{Type: moveTypes[cmd], Location: location})
+		}
+		moves++
+		render()
+	}
+
+	result := g.record(difficulty, multiStatus(boards), moves, g.ratedBoard(boards[0]))
+	fmt.Fprintf(out, "\n%d board game %v in %s%s\n", len(boards), result.Status, result.Played.Round(100*time.Millisecond),
+		ratedText(result.Rated))
+	return nil
+}
@@ -0,0 +1,84 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"strings"
+	"testing"
+)
+
+func TestParseBoardMove(t *testing.T) {
+	var cases = []struct {
+		line     string
+		board    int
+		cmd      string
+		row, col int
+		valid    bool
+	}{
+		{"board 2: reveal C4", 1, "s", 3, 2, true},
+		{"2: f b1", 1, "f", 0, 1, true},
+		{"1 c4", 0, "s", 3, 2, true},
+		{"3:flag a1", 2, "f", 0, 0, true},
+		{"4: c4", 0, "", 0, 0, false},
+		{"c4", 0, "", 0, 0, false},
+		{"2: reveal", 0, "", 0, 0, false},
+	}
+	game := New(1995)
+	boards := []*msboard.Board{msboard.NewBoard("easy"), msboard.NewBoard("easy"), msboard.NewBoard("easy")}
+	for _, testcase := range cases {
+		board, cmd, l, err := game.parseBoardMove(testcase.line, boards)
+		if (err == nil) != testcase.valid {
+			t.Errorf("parseBoardMove(%q) validity wanted %v got err %v", testcase.line, testcase.valid, err)
+			continue
+		}
+		if testcase.valid && (board != testcase.board || cmd != testcase.cmd || l != msboard.NewLocation(testcase.row, testcase.col)) {
+			t.Errorf("parseBoardMove(%q) wanted board %d %s %d,%d got %d %s %v", testcase.line, testcase.board,
+				testcase.cmd, testcase.row, testcase.col, board, cmd, l)
+		}
+	}
+}
+
+func TestMultiStatus(t *testing.T) {
+	won, _ := msboard.ParseLayout("1*1/111/...")
+	won.Click(msboard.NewLocation(2, 0))
+	playing, _ := msboard.ParseLayout("1*1/111/...")
+	lost, _ := msboard.ParseLayout("1*1/111/...")
+	lost.Click(msboard.NewLocation(0, 1))
+
+	var cases = []struct {
+		boards []*msboard.Board
+		want   msboard.Status
+	}{
+		{[]*msboard.Board{won, won}, msboard.StatusWon},
+		{[]*msboard.Board{won, playing}, msboard.StatusPlaying},
+		{[]*msboard.Board{won, msboard.NewBoard("easy")}, msboard.StatusPlaying},
+		{[]*msboard.Board{playing, lost, won}, msboard.StatusLost},
+	}
+	for i, testcase := range cases {
+		if got := multiStatus(testcase.boards); got != testcase.want {
+			t.Errorf("multiStatus case %d wanted %v got %v", i, testcase.want, got)
+		}
+	}
+}
+
+func TestMultiGame(t *testing.T) {
+	game := New(1995)
+	if err := game.SetBoards(2); err != nil {
+		t.Fatalf("SetBoards(2) failed: %s", err)
+	}
+	if err := game.SetBoards(10); err == nil {
+		t.Errorf("SetBoards(10) should fail")
+	}
+
+	out := bytes.NewBufferString("")
+	script := "e\n1: f a1\n3: a1\nboard 1: reveal a1\n2: e5\n"
+	if err := game.RunConsole(strings.NewReader(script), out); err != nil {
+		t.Fatalf("multiboard game failed: %s", err)
+	}
+	for _, want := range []string{"Board 1: uninitialized", "Board 2: uninitialized", "uncover first on board 1",
+		"no board \"3\"", "Board 1: playing", "Board 2: playing"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("multiboard output missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,68 @@
+/*
+
+	Opponent.go - computer opponents raced in the console game. The bot plays each board before the player does,
+	and its game, timed at the bot's pace, is raced like a ghost replay
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"go-mines/msanalysis"
+	"go-mines/msboard"
+	"go-mines/msbot"
+	"go-mines/msreplay"
+	"math/rand"
+	"time"
+)
+
+// opponentGhost -- lay out a board if it isn't already and have the opponent play it at the given moves per
+// second, or its own pace for 0. It starts at the opening hint, or on a board laid out around another first click,
+// the first safe cell in reading order
+func (g *Game) opponentGhost(board *msboard.Board, skill msbot.Skill, speed float64) (*msreplay.Ghost, error) {
+	first := msanalysis.OpeningHint(board.Difficulty(), board.Rows(), board.Cols())
+	if !board.Initialized() {
+		if err := board.InitializeWithOptions(first, g.generatorOptions()); err != nil {
+			if err = board.InitializeWithOptions(first, msboard.GeneratorOptions{RNG: g.rng}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for row := 0; row < board.Rows() && board.MineAt(first); row++ {
+		for col := 0; col < board.Cols() && board.MineAt(first); col++ {
+			first = msboard.NewLocation(row, col)
+		}
+	}
+
+	r, err := opponentReplay(board, first, skill, speed, rand.Int63(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return msreplay.NewGhost(r)
+}
+
+// opponentReplay -- the opponent's game on the mines of a laid out board as a replay, each move timed at speed
+// moves per second, or the skill's pace for 0. Choices are random from seed
+func opponentReplay(board *msboard.Board, first msboard.Location, skill msbot.Skill, speed float64, seed int64,
+	started time.Time) (msreplay.Replay, error) {
+	b, err := msboard.ParseLayout(board.MineLayout())
+	if err != nil {
+		return msreplay.Replay{}, err
+	}
+	retval := msreplay.New(b, seed, started)
+	retval.Difficulty = board.Difficulty()
+
+	rng := rand.New(rand.NewSource(seed))
+	at := started
+	_, _, err = skill.Player(rng).PlayEach(b, first, func(m msboard.Move) {
+		if speed > 0 {
+			at = at.Add(time.Duration(float64(time.Second) / speed))
+		} else {
+			at = at.Add(skill.Pace(rng))
+		}
+		retval.Record(m, at)
+	})
+	return *retval, err
+}
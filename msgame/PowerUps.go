@@ -0,0 +1,149 @@
+/*
+
+	PowerUps.go - the arcade variant: treasure cells hide power-ups, collected by revealing them and spent with
+	commands, and every safe cell revealed scores points
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"io"
+	"strings"
+)
+
+// arcade scoring and power-up strength
+const (
+	cellPoints    = 10 // points for each safe cell revealed, times the multiplier
+	defuseRadius  = 1  // a defuser clears the 3x3 patch around its target
+	maxMultiplier = 5  // multipliers found at this are worth capBonus cells' points instead
+	capBonus      = 10
+)
+
+// PowerUps : the arcade inventory and score of the current game
+type PowerUps struct {
+	Defusers   int // defuse commands available
+	FreeFlags  int // freeflag commands available
+	Multiplier int // applied to the points for each cell revealed
+	Score      int
+}
+
+// PowerUpEvent : a power-up found or spent, reported to the listener set with SetPowerUpListener
+type PowerUpEvent struct {
+	Treasure  msboard.Treasure
+	Location  msboard.Location // where it was found or used
+	Used      bool             // false when found, true when spent
+	Inventory PowerUps         // the inventory afterwards
+}
+
+// String -- the event as a message for the player, naming the cell in letter-number form
+func (e PowerUpEvent) String() string {
+	return e.message(msboard.LetterNumberCodec{}.Format(e.Location, 0, 0))
+}
+
+// message -- the event as a message for the player, given the name of its cell
+func (e PowerUpEvent) message(cell string) string {
+	if e.Used {
+		return fmt.Sprintf("Used a %v at %s", e.Treasure, cell)
+	}
+	if e.Treasure == msboard.TreasureMultiplier {
+		return fmt.Sprintf("Found a %v at %s, now scoring x%d", e.Treasure, cell, e.Inventory.Multiplier)
+	}
+	return fmt.Sprintf("Found a %v at %s", e.Treasure, cell)
+}
+
+// SetArcade -- hide treasures in this many safe cells of each new easy, medium or hard board, and keep score; 0
+// for normal play. Puzzles and races are never arcade games
+func (g *Game) SetArcade(treasures int) {
+	if treasures < 0 {
+		treasures = 0
+	}
+	g.arcade = treasures
+}
+
+// SetPowerUpListener -- call f whenever a power-up is found or spent, for front ends that want to animate them;
+// nil for none
+func (g *Game) SetPowerUpListener(f func(PowerUpEvent)) {
+	g.listener = f
+}
+
+// PowerUps -- the arcade inventory and score of the game in play
+func (g *Game) PowerUps() PowerUps {
+	return g.powerUps
+}
+
+// emit -- report a power-up event on a board to the player and the listener
+func (g *Game) emit(out io.Writer, board *msboard.Board, e PowerUpEvent) {
+	e.Inventory = g.powerUps
+	fmt.Fprintln(out, e.message(g.cellName(e.Location, board)))
+	if nil != g.listener {
+		g.listener(e)
+	}
+}
+
+// collect -- score the safe cells a move revealed and pick up any treasures among them
+func (g *Game) collect(out io.Writer, board *msboard.Board, revealed []msboard.Location) {
+	for _, l := range revealed {
+		if !board.MineAt(l) {
+			g.powerUps.Score += cellPoints * g.powerUps.Multiplier
+		}
+	}
+
+	for _, found := range board.Collect(revealed) {
+		switch found.Treasure {
+		case msboard.TreasureDefuser:
+			g.powerUps.Defusers++
+		case msboard.TreasureFreeFlag:
+			g.powerUps.FreeFlags++
+		case msboard.TreasureMultiplier:
+			if g.powerUps.Multiplier < maxMultiplier {
+				g.powerUps.Multiplier++
+			} else {
+				g.powerUps.Score += capBonus * cellPoints * g.powerUps.Multiplier
+			}
+		}
+		g.emit(out, board, PowerUpEvent{Treasure: found.Treasure, Location: found.Location})
+	}
+}
+
+// usePowerUp -- spend a power-up: "defuse <location>" or "freeflag". Cells the defuser reveals are scored and
+// searched for treasure like any others
+func (g *Game) usePowerUp(out io.Writer, board *msboard.Board, cmd string, args []string) error {
+	switch cmd {
+	case "defuse":
+		if g.powerUps.Defusers == 0 {
+			return fmt.Errorf("no defusers left")
+		}
+		location, err := g.parseLocation(strings.Join(args, " "), board)
+		if err != nil {
+			return err
+		}
+		if !board.ValidLocation(location) || !board.InRange(location) {
+			return fmt.Errorf("can't defuse at %s", g.cellName(location, board))
+		}
+		g.powerUps.Defusers--
+		revealed, _ := board.Defuse(location, defuseRadius)
+		g.emit(out, board, PowerUpEvent{Treasure: msboard.TreasureDefuser, Location: location, Used: true})
+		g.collect(out, board, revealed)
+	case "freeflag":
+		if g.powerUps.FreeFlags == 0 {
+			return fmt.Errorf("no free flags left")
+		}
+		location, ok := board.FlagMine(g.rng)
+		if !ok {
+			return fmt.Errorf("every mine is flagged already")
+		}
+		g.powerUps.FreeFlags--
+		g.emit(out, board, PowerUpEvent{Treasure: msboard.TreasureFreeFlag, Location: location, Used: true})
+	}
+	return nil
+}
+
+// writeArcade -- show the score and inventory under the board
+func writeArcade(out io.Writer, p PowerUps) {
+	fmt.Fprintf(out, "Score %d x%d   defusers %d   free flags %d\n", p.Score, p.Multiplier, p.Defusers, p.FreeFlags)
+}
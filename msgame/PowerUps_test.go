@@ -0,0 +1,69 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestCollectPowerUps(t *testing.T) {
+	// every safe cell but the first click holds a treasure
+	rand.Seed(1995)
+	board := msboard.NewBoard("easy")
+	revealed, err := board.FirstClickWithOptions(msboard.NewLocation(4, 4), msboard.GeneratorOptions{Treasures: 70})
+	if err != nil {
+		t.Fatalf("FirstClickWithOptions failed: %s", err)
+	}
+
+	game := New(1995)
+	var events []PowerUpEvent
+	game.SetPowerUpListener(func(e PowerUpEvent) { events = append(events, e) })
+	game.powerUps = PowerUps{Multiplier: 1}
+
+	var out bytes.Buffer
+	game.collect(&out, board, revealed)
+	p := game.PowerUps()
+	if p.Score < cellPoints*len(revealed) {
+		t.Errorf("collect wanted at least %d points for %d cells got %d", cellPoints*len(revealed), len(revealed), p.Score)
+	}
+	if len(events) != len(revealed)-1 {
+		t.Errorf("collect wanted %d events got %d", len(revealed)-1, len(events))
+	}
+	if p.Defusers+p.FreeFlags+p.Multiplier-1 > len(events) {
+		t.Errorf("collect inventory %+v holds more than the %d treasures found", p, len(events))
+	}
+
+	// spending reports an event too
+	if p.FreeFlags > 0 {
+		before := len(events)
+		if err := game.usePowerUp(&out, board, "freeflag", nil); err != nil {
+			t.Errorf("freeflag failed: %s", err)
+		}
+		if len(events) != before+1 || !events[before].Used || game.PowerUps().FreeFlags != p.FreeFlags-1 {
+			t.Errorf("freeflag wanted a used event and one fewer free flag, got %+v", events[before:])
+		}
+	}
+	game.powerUps.Defusers = 0
+	if err := game.usePowerUp(&out, board, "defuse", []string{"a1"}); err == nil {
+		t.Errorf("defuse should be refused with no defusers")
+	}
+}
+
+func TestArcadeGame(t *testing.T) {
+	// an opening guarantees treasure is found on the first click
+	game := New(1995)
+	game.SetArcade(70)
+	game.SetGenerator(msboard.GeneratorOptions{MinOpening: 10})
+
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("e\ne5\n"), out); err != nil {
+		t.Fatalf("arcade game failed: %s", err)
+	}
+	for _, want := range []string{"Found a", "Score ", "free flags"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("arcade game output missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,49 @@
+/*
+
+	Progress.go - guess-free indicator for the console game, telling the player after each move whether a safe move
+	is there to be found
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/mssolver"
+	"io"
+	"time"
+)
+
+// progressBudget -- time the solver gets after each move to decide whether a safe move exists
+const progressBudget = 50 * time.Millisecond
+
+// progressIcons : indicator glyphs for each answer, for terminals with and without unicode
+var progressIcons = map[mssolver.Progress][2]string{
+	mssolver.ProgressSafe:    {"[+]", "✔"},
+	mssolver.ProgressGuess:   {"[?]", "❓"},
+	mssolver.ProgressUnknown: {"[ ]", "…"},
+}
+
+// progressText : what the indicator tells the player
+var progressText = map[mssolver.Progress]string{
+	mssolver.ProgressSafe:    "a safe move exists, keep looking",
+	mssolver.ProgressGuess:   "no safe move, you'll have to guess",
+	mssolver.ProgressUnknown: "too complex to tell in time",
+}
+
+// writeProgress -- show whether the position has a move that needs no guess, with a unicode icon if the terminal
+// can show one. Finished games show nothing
+func writeProgress(out io.Writer, s msboard.Snapshot, utf8 bool) {
+	if s.Status != msboard.StatusPlaying {
+		return
+	}
+	progress := mssolver.SafeMoveExists(s, progressBudget)
+	icon := progressIcons[progress][0]
+	if utf8 {
+		icon = progressIcons[progress][1]
+	}
+	fmt.Fprintf(out, "%s %s\n", icon, progressText[progress])
+}
@@ -0,0 +1,33 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"testing"
+)
+
+func TestWriteProgress(t *testing.T) {
+	var cases = []struct {
+		layout string
+		utf8   bool
+		want   string
+	}{
+		{"__1*/__2./__1*", false, "[+] a safe move exists, keep looking\n"},
+		{"__1*/__2./__1*", true, "✔ a safe move exists, keep looking\n"},
+		{"*./11/__", false, "[?] no safe move, you'll have to guess\n"},
+		// nothing to say once the game is over
+		{"1*", false, ""},
+	}
+
+	for _, testcase := range cases {
+		b, err := msboard.ParseLayout(testcase.layout)
+		if err != nil {
+			t.Fatalf("ParseLayout(%q) failed: %s", testcase.layout, err)
+		}
+		out := bytes.NewBufferString("")
+		writeProgress(out, b.Snapshot(), testcase.utf8)
+		if got := out.String(); got != testcase.want {
+			t.Errorf("writeProgress(%q, %v) wanted %q got %q", testcase.layout, testcase.utf8, testcase.want, got)
+		}
+	}
+}
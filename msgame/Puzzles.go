@@ -0,0 +1,147 @@
+/*
+
+	Puzzles.go - console menu for browsing puzzle packs, and the player's record on each puzzle
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"bufio"
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/mspuzzle"
+	"go-mines/msstore"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SetPuzzlePacks -- offer the puzzles of these packs from the main menu
+func (g *Game) SetPuzzlePacks(packs []mspuzzle.Pack) {
+	g.packs = packs
+}
+
+// SetStatsFile -- keep puzzle completion in a file, loading what's already there. Without one, completion is only
+// kept for the session
+func (g *Game) SetStatsFile(filename string) error {
+	stats, err := mspuzzle.LoadStats(filename)
+	if err != nil {
+		return err
+	}
+	g.stats, g.statsFile = stats, filename
+	return nil
+}
+
+// choosePuzzle -- browse the packs and pick a puzzle to play. False if the player backs out of the menu
+func (g *Game) choosePuzzle(in *bufio.Scanner, out *bufio.Writer) (mspuzzle.Puzzle, bool, error) {
+	for {
+		fmt.Fprintln(out, "\nPuzzle packs:")
+		for i, pack := range g.packs {
+			fmt.Fprintf(out, "%3d. %-30s %d/%d solved\n", i+1, pack.Title, g.puzzleStats().Solved(pack), len(pack.Puzzles))
+		}
+		pack, ok, err := chooseNumber(in, out, "pack", len(g.packs))
+		if err != nil || !ok {
+			return mspuzzle.Puzzle{}, false, err
+		}
+
+		puzzles := g.packs[pack].Puzzles
+		fmt.Fprintf(out, "\n%s:\n", g.packs[pack].Title)
+		for i, p := range puzzles {
+			fmt.Fprintf(out, "%3d. %-30s %s\n", i+1, p.Title, puzzleRecord(p, g.puzzleStats().Completion(p)))
+		}
+		puzzle, ok, err := chooseNumber(in, out, "puzzle", len(puzzles))
+		if err != nil {
+			return mspuzzle.Puzzle{}, false, err
+		}
+		if ok {
+			// puzzles without a par get one from the bot; those it can't clear are played without
+			chosen, _ := puzzles[puzzle].WithPar()
+			return chosen, true, nil
+		}
+	}
+}
+
+// chooseNumber -- prompt for a menu entry from 1 to n, returned 0 based. False if the player goes back
+func chooseNumber(in *bufio.Scanner, out *bufio.Writer, what string, n int) (int, bool, error) {
+	for {
+		fmt.Fprintf(out, "Choose a %s number, or [B]ack: ", what)
+		out.Flush()
+		line, err := readInput(in)
+		if err != nil {
+			return 0, false, err
+		}
+		if line == "b" || line == "back" {
+			return 0, false, nil
+		}
+		if choice, err := strconv.Atoi(line); err == nil && choice >= 1 && choice <= n {
+			return choice - 1, true, nil
+		}
+	}
+}
+
+// puzzleRecord -- par time and the player's record on a puzzle for the menu
+func puzzleRecord(p mspuzzle.Puzzle, c mspuzzle.Completion) string {
+	retval := "no par"
+	if p.Par > 0 {
+		retval = "par " + p.ParTime().Round(time.Second).String()
+	}
+	switch {
+	case c.Solved > 0:
+		retval += fmt.Sprintf(", best %s, %d/3 stars", c.BestTime().Round(100*time.Millisecond), c.Stars)
+	case c.Attempts > 0:
+		retval += ", unsolved"
+	}
+	return retval
+}
+
+// puzzleStats -- the player's puzzle completion, created empty on first use
+func (g *Game) puzzleStats() *mspuzzle.Stats {
+	if nil == g.stats {
+		g.stats = mspuzzle.NewStats()
+	}
+	return g.stats
+}
+
+// starGlyphs : earned and missing stars, for terminals with and without unicode
+var starGlyphs = [2][2]string{{"*", "-"}, {"★", "☆"}}
+
+// starsText -- stars earned out of 3, drawn with unicode stars if the terminal can show them
+func starsText(stars int, utf8 bool) string {
+	glyphs := starGlyphs[0]
+	if utf8 {
+		glyphs = starGlyphs[1]
+	}
+	return strings.Repeat(glyphs[0], stars) + strings.Repeat(glyphs[1], 3-stars)
+}
+
+// finishPuzzle -- record a finished puzzle and tell the player the stars they earned and how it went against par
+// and their best
+func (g *Game) finishPuzzle(out io.Writer, p mspuzzle.Puzzle, result GameResult, utf8 bool) {
+	won := result.Status == msboard.StatusWon
+	stars, c := g.puzzleStats().Record(p, won, result.Played, result.Moves, result.Rated)
+	if won {
+		fmt.Fprintf(out, "%s %d/3 stars\n", starsText(stars, utf8), stars)
+	}
+	if won && p.Par > 0 {
+		if result.Played <= p.ParTime() {
+			fmt.Fprintf(out, "Under par by %s\n", (p.ParTime() - result.Played).Round(100*time.Millisecond))
+		} else {
+			fmt.Fprintf(out, "Over par by %s\n", (result.Played - p.ParTime()).Round(100*time.Millisecond))
+		}
+	}
+	fmt.Fprintf(out, "%q solved %d of %d attempts\n", p.Title, c.Solved, c.Attempts)
+
+	if nil != g.store {
+		if err := msstore.SavePuzzleStats(g.store, g.stats); err != nil {
+			fmt.Fprintln(out, "failed to save puzzle stats:", err)
+		}
+	} else if g.statsFile != "" {
+		if err := mspuzzle.SaveStats(g.statsFile, g.stats); err != nil {
+			fmt.Fprintln(out, "failed to save puzzle stats:", err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"go-mines/mspuzzle"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPuzzleMenu(t *testing.T) {
+	puzzle := mspuzzle.Puzzle{Title: "corner", Layout: "1*1/111/...", Par: 60}
+	pack := mspuzzle.Pack{Title: "Starter", Puzzles: []mspuzzle.Puzzle{puzzle, {Title: "other", Layout: "*1.."}}}
+	filename := filepath.Join(t.TempDir(), "stats.json")
+
+	game := New(1995)
+	game.SetPuzzlePacks([]mspuzzle.Pack{pack})
+	if err := game.SetStatsFile(filename); err != nil {
+		t.Fatalf("SetStatsFile() failed: %s", err)
+	}
+
+	// back out of the puzzle list once, then clear the first puzzle from its bottom row
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("p\n1\nb\n1\n1\na3\np\nb\nq\n"), out); err != nil {
+		t.Fatalf("puzzle game failed: %s", err)
+	}
+	for _, want := range []string{"[P]uzzles", "Starter", "0/2 solved", "corner", "par 1m0s", "Game won",
+		"*** 3/3 stars", "Under par by", `"corner" solved 1 of 1 attempts`, "1/2 solved", "3/3 stars"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("puzzle game output missing %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "Move review") {
+		t.Errorf("puzzle games shouldn't be reviewed")
+	}
+
+	stats, err := mspuzzle.LoadStats(filename)
+	if err != nil || stats.Completion(puzzle).Solved != 1 {
+		t.Errorf("puzzle stats wanted the corner puzzle solved once got %+v err %v", stats, err)
+	}
+}
+
+func TestPuzzleInFog(t *testing.T) {
+	pack := mspuzzle.Pack{Title: "Fog", Puzzles: []mspuzzle.Puzzle{{Title: "edge", Layout: "_...*/_..../_...."}}}
+	game := New(1995)
+	game.SetPuzzlePacks([]mspuzzle.Pack{pack})
+	game.SetFog(1)
+
+	// C1 is two columns from the revealed edge, B2 is next to it
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("p\n1\n1\nc1\nb2\nq\n"), out); err != nil {
+		t.Fatalf("fog game failed: %s", err)
+	}
+	for _, want := range []string{"C1 is lost in the fog, choose a cell within 1 of a revealed one", "Game won"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("fog game output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestPuzzleCoordinates(t *testing.T) {
+	pack := mspuzzle.Pack{Title: "Fog", Puzzles: []mspuzzle.Puzzle{{Title: "edge", Layout: "_...*/_..../_...."}}}
+	game := New(1995)
+	game.SetPuzzlePacks([]mspuzzle.Pack{pack})
+	game.SetFog(1)
+	game.SetCoordinates(msboard.ChessCodec{})
+
+	// ranks count up from the bottom, so C1 is c3 and B2 is b2
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("p\n1\n1\nc3\nb2\nq\n"), out); err != nil {
+		t.Fatalf("chess coordinates game failed: %s", err)
+	}
+	for _, want := range []string{"    a  b  c  d  e", "c3 is lost in the fog", "Game won"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("chess coordinates game output missing %q:\n%s", want, out.String())
+		}
+	}
+}
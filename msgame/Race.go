@@ -0,0 +1,104 @@
+/*
+
+	Race.go - progress display for racing a ghost replay in the console game
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/msrender"
+	"go-mines/msreplay"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// raceBarWidth -- characters in each progress bar
+const raceBarWidth = 30
+
+// raceTick -- how often a live race display looks for new moves by the rival
+const raceTick = 100 * time.Millisecond
+
+// writeRace -- show the player's and the ghost's progress side by side after the given time, the ghost under the
+// rival's name on the second line
+func writeRace(out io.Writer, rival string, ghost *msreplay.Ghost, player msboard.Snapshot, elapsed time.Duration) {
+	fmt.Fprintf(out, "%-6s %s  %s\n", "you", progressBar(msreplay.Progress(player)), elapsed.Round(100*time.Millisecond))
+	fmt.Fprintln(out, rivalLine(rival, ghost, elapsed))
+}
+
+// rivalLine -- the ghost's progress after the given time, under the rival's name
+func rivalLine(rival string, ghost *msreplay.Ghost, elapsed time.Duration) string {
+	ghostPosition, moves := ghost.At(elapsed)
+	ghostState := fmt.Sprintf("%d moves", moves)
+	if moves == len(ghost.Replay().Moves) {
+		ghostState = fmt.Sprintf("%v in %s", ghostPosition.Status, ghost.Duration().Round(100*time.Millisecond))
+	}
+	return fmt.Sprintf("%-6s %s  %s", rival, progressBar(msreplay.Progress(ghostPosition)), ghostState)
+}
+
+// followRace -- on a terminal, rewrite the rival's line of the race display from a goroutine as the ghost moves,
+// so it keeps up while the player thinks, until the returned function is called. The clock mustn't be paused,
+// resumed or restarted in between. Other renderers only show the race after each move
+func followRace(out io.Writer, renderer msrender.Renderer, rival string, ghost *msreplay.Ghost,
+	elapsed func() time.Duration) (stop func()) {
+	live, ok := renderer.(*msrender.PartialRenderer)
+	if !ok {
+		return func() {}
+	}
+
+	quit, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(raceTick)
+		defer ticker.Stop()
+		_, shown := ghost.At(elapsed())
+		for {
+			select {
+			case <-quit:
+				return
+			case <-ticker.C:
+				now := elapsed()
+				if _, moves := ghost.At(now); moves != shown {
+					shown = moves
+					live.WriteBelow(out, 2, rivalLine(rival, ghost, now))
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(quit)
+			<-done
+		})
+	}
+}
+
+// syncWriter : writer shared by the game loop and a live race display, one whole write at a time
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Write -- write p while holding the lock
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// progressBar -- fixed width bar and percentage for a fraction between 0 and 1
+func progressBar(fraction float64) string {
+	filled := int(fraction*raceBarWidth + 0.5)
+	if filled > raceBarWidth {
+		filled = raceBarWidth
+	}
+	return fmt.Sprintf("[%s%s] %3.0f%%", strings.Repeat("#", filled), strings.Repeat(".", raceBarWidth-filled), fraction*100)
+}
@@ -0,0 +1,134 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"go-mines/msbot"
+	"go-mines/msrender"
+	"go-mines/msreplay"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressBar(t *testing.T) {
+	var cases = []struct {
+		fraction float64
+		want     string
+	}{
+		{0, "[" + strings.Repeat(".", raceBarWidth) + "]   0%"},
+		{0.5, "[" + strings.Repeat("#", raceBarWidth/2) + strings.Repeat(".", raceBarWidth/2) + "]  50%"},
+		{1, "[" + strings.Repeat("#", raceBarWidth) + "] 100%"},
+	}
+
+	for _, testcase := range cases {
+		if got := progressBar(testcase.fraction); got != testcase.want {
+			t.Errorf("progressBar(%v) wanted %q got %q", testcase.fraction, testcase.want, got)
+		}
+	}
+}
+
+func TestGhostRace(t *testing.T) {
+	started := time.Now()
+	ghost := msreplay.Replay{Layout: "...*/..../...*", Started: started}
+	ghost.Record(msboard.Move{Type: msboard.MoveReveal, Location: msboard.NewLocation(0, 0)}, started.Add(time.Second))
+
+	game := New(1995)
+	if err := game.SetGhost(ghost); err != nil {
+		t.Fatalf("SetGhost failed: %s", err)
+	}
+
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("e\na1\nd1\nq\n"), out); err != nil {
+		t.Fatalf("race game failed: %s", err)
+	}
+
+	for _, want := range []string{"it started at A1", "you    [", "ghost  ["} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("race output missing %q", want)
+		}
+	}
+}
+
+func TestOpponentRace(t *testing.T) {
+	game := New(1995)
+	game.SetOpponent(msbot.SkillExpert)
+
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("e\ne1\nq\n"), out); err != nil {
+		t.Fatalf("opponent race failed: %s", err)
+	}
+	for _, want := range []string{"Racing the expert bot", "it started at E1", "you    [", "bot    ["} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("opponent race output missing %q in:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestOpponentReplay(t *testing.T) {
+	b := msboard.NewBoard("easy")
+	b.Initialize(msboard.NewLocation(0, 4))
+	started := time.Now()
+	r, err := opponentReplay(b, msboard.NewLocation(0, 4), msbot.SkillSolver, 0, 7, started)
+	if err != nil {
+		t.Fatalf("opponentReplay failed: %s", err)
+	}
+	if !r.Timed() || r.Layout != b.MineLayout() || r.Moves[0].Location != msboard.NewLocation(0, 4) {
+		t.Fatalf("opponentReplay wanted a timed game on the board from E1 got %+v", r)
+	}
+	for i := range r.Moves {
+		if think := r.ThinkTime(i); think < 750*time.Millisecond || think > 2250*time.Millisecond {
+			t.Errorf("move %d took %s, outside the solver's pace", i+1, think)
+		}
+	}
+	positions, err := r.Positions()
+	if err != nil {
+		t.Fatalf("opponent's replay doesn't play back: %s", err)
+	}
+	if final := positions[len(positions)-1]; final.Status != msboard.StatusWon && final.Status != msboard.StatusLost {
+		t.Errorf("opponent stopped while still playing")
+	}
+}
+
+func TestFollowRace(t *testing.T) {
+	started := time.Now()
+	r := msreplay.Replay{Layout: "...*/..../...*", Started: started}
+	r.Record(msboard.Move{Type: msboard.MoveReveal, Location: msboard.NewLocation(0, 0)}, started.Add(10*time.Millisecond))
+	ghost, _ := msreplay.NewGhost(r)
+	b, _ := r.Board()
+
+	// plain output isn't followed
+	out := &syncWriter{w: bytes.NewBufferString("")}
+	followRace(out, msrender.FrameRenderer{}, "bot", ghost, func() time.Duration { return time.Hour })()
+
+	// the clock reaches the ghost's only move by the first tick, which rewrites its line
+	buf := bytes.NewBufferString("")
+	renderer := msrender.NewPartialRenderer(nil, msrender.Options{})
+	renderer.Render(buf, b.Snapshot())
+	out = &syncWriter{w: buf}
+	var clock time.Duration
+	stop := followRace(out, renderer, "bot", ghost, func() time.Duration {
+		clock += 5 * time.Millisecond
+		return clock
+	})
+	time.Sleep(3 * raceTick)
+	stop()
+	stop()
+	if !strings.Contains(buf.String(), "bot    [") || !strings.Contains(buf.String(), "90%") {
+		t.Errorf("followRace didn't rewrite the rival's line: %q", buf.String())
+	}
+}
+
+func TestOpponentSpeed(t *testing.T) {
+	b := msboard.NewBoard("easy")
+	b.Initialize(msboard.NewLocation(0, 4))
+	r, err := opponentReplay(b, msboard.NewLocation(0, 4), msbot.SkillExpert, 4, 7, time.Now())
+	if err != nil {
+		t.Fatalf("opponentReplay failed: %s", err)
+	}
+	for i := range r.Moves {
+		if think := r.ThinkTime(i); think != 250*time.Millisecond {
+			t.Fatalf("move %d took %s at 4 moves a second", i+1, think)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+/*
+
+	Rated.go - which games are rated: played by the standard rules with no help, so best times and scores only ever
+	compare games played straight. Assisted and variant games are still reported, marked unrated
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"go-mines/msboard"
+)
+
+// ratedBoard -- true if a game on the board can be rated as the game is set up: classic rules on the square grid
+// with standard propagation, no fog, anti-mines, moving mines or arcade treasures, no practice or analysis, and no
+// guess-free indicator pointing out safe moves. How it is played, such as asking for a hint, can still unrate it
+func (g *Game) ratedBoard(board *msboard.Board) bool {
+	return !g.practicing() && !g.guessFree && g.moving == 0 && g.arcade == 0 &&
+		board.Rules().Name() == (msboard.ClassicRules{}).Name() && board.Topology() == msboard.GridTopology &&
+		board.Propagation() == msboard.PropagateStandard && board.Fog() == 0 && board.AntiMineCount() == 0
+}
+
+// ratedText -- a note for the results of unrated games, nothing for rated ones
+func ratedText(rated bool) string {
+	if rated {
+		return ""
+	}
+	return " (unrated)"
+}
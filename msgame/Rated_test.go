@@ -0,0 +1,66 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"go-mines/mspuzzle"
+	"strings"
+	"testing"
+)
+
+func TestRatedBoard(t *testing.T) {
+	g := New(1995)
+	fresh := func() *msboard.Board {
+		b, _ := msboard.ParseLayout("1*1/111/...")
+		return b
+	}
+	if !g.ratedBoard(fresh()) {
+		t.Error("a classic game should be rated")
+	}
+
+	for name, unrate := range map[string]func(*Game, *msboard.Board){
+		"fog":       func(g *Game, b *msboard.Board) { b.SetFog(1) },
+		"chording":  func(g *Game, b *msboard.Board) { b.SetRules(msboard.ChordRules{}) },
+		"zeros":     func(g *Game, b *msboard.Board) { b.SetPropagation(msboard.PropagateZerosOnly) },
+		"guessfree": func(g *Game, b *msboard.Board) { g.SetGuessFree(true) },
+		"practice":  func(g *Game, b *msboard.Board) { g.SetPractice(true) },
+		"analysis":  func(g *Game, b *msboard.Board) { g.SetAnalysis(true) },
+		"arcade":    func(g *Game, b *msboard.Board) { g.SetArcade(2) },
+		"moving":    func(g *Game, b *msboard.Board) { g.SetMovingMines(0.5, 3) },
+	} {
+		g, b := New(1995), fresh()
+		unrate(g, b)
+		if g.ratedBoard(b) {
+			t.Errorf("a game with %s should be unrated", name)
+		}
+	}
+}
+
+func TestRatedResults(t *testing.T) {
+	puzzle := mspuzzle.Puzzle{Title: "corner", Layout: "1*1/111/..."}
+	play := func(g *Game) string {
+		g.SetPuzzlePacks([]mspuzzle.Pack{{Title: "Rated", Puzzles: []mspuzzle.Puzzle{puzzle}}})
+		out := bytes.NewBufferString("")
+		if err := g.RunConsole(strings.NewReader("p\n1\n1\ns a3\nq\n"), out); err != nil {
+			t.Fatalf("puzzle game failed: %s", err)
+		}
+		return out.String()
+	}
+
+	g := New(1995)
+	if out := play(g); strings.Contains(out, "(unrated)") || !g.Results()[0].Rated ||
+		g.puzzleStats().Completion(puzzle).Best == 0 {
+		t.Errorf("a straight game should be rated and set the best time, got %+v %+v:\n%s", g.Results(),
+			g.puzzleStats().Completion(puzzle), out)
+	}
+
+	// the safe move indicator is a hint
+	g = New(1995)
+	g.SetGuessFree(true)
+	if out := play(g); !strings.Contains(out, "Game won in 0s (unrated)") || g.Results()[0].Rated {
+		t.Errorf("a guess-free game should be unrated, got %+v:\n%s", g.Results(), out)
+	}
+	if c := g.puzzleStats().Completion(puzzle); c.Solved != 1 || c.Unrated != 1 || c.Best != 0 {
+		t.Errorf("an unrated clear should count without a best time, got %+v", c)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build darwin
+
+/*
+
+	RawModeDarwin.go - termios ioctl requests on macOS
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import "syscall"
+
+const (
+	ioctlGetTermios = syscall.TIOCGETA
+	ioctlSetTermios = syscall.TIOCSETA
+)
@@ -0,0 +1,18 @@
+//go:build linux
+
+/*
+
+	RawModeLinux.go - termios ioctl requests on Linux
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import "syscall"
+
+const (
+	ioctlGetTermios = syscall.TCGETS
+	ioctlSetTermios = syscall.TCSETS
+)
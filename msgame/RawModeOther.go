@@ -0,0 +1,21 @@
+//go:build !linux && !darwin
+
+/*
+
+	RawModeOther.go - raw mode fallback for platforms without termios; input is read a line at a time as typed
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"errors"
+	"os"
+)
+
+// rawMode -- unsupported on this platform
+func rawMode(f *os.File) (func(), error) {
+	return nil, errors.New("raw terminal input is not supported on this platform")
+}
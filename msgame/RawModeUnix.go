@@ -0,0 +1,44 @@
+//go:build linux || darwin
+
+/*
+
+	RawModeUnix.go - switching a terminal to raw mode for the line editor, via the termios ioctls
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// rawMode -- stop the terminal behind f echoing input, holding it back until enter and turning ctrl-C into a signal,
+// so the line editor sees every key as it is pressed and the game can restore the terminal however it ends. Output
+// processing is left alone. Returns the function putting the terminal back as it was; an error if f isn't a terminal
+func rawMode(f *os.File) (func(), error) {
+	var saved syscall.Termios
+	if err := termios(f, ioctlGetTermios, &saved); err != nil {
+		return nil, err
+	}
+
+	raw := saved
+	raw.Lflag &^= syscall.ICANON | syscall.ECHO | syscall.ISIG
+	raw.Cc[syscall.VMIN], raw.Cc[syscall.VTIME] = 1, 0
+	if err := termios(f, ioctlSetTermios, &raw); err != nil {
+		return nil, err
+	}
+	return func() { termios(f, ioctlSetTermios, &saved) }, nil
+}
+
+// termios -- get or set the terminal settings of f
+func termios(f *os.File, request uintptr, t *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), request, uintptr(unsafe.Pointer(t)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+/*
+
+	Result.go - game clock with pause support, and the results of finished games
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"go-mines/msboard"
+	"go-mines/msstats"
+	"time"
+)
+
+// GameResult : outcome of one finished game. Paused time is kept apart from play time so scores can be compared
+// fairly
+type GameResult struct {
+	Difficulty string
+	Status     msboard.Status
+	Played     time.Duration // time on the clock, pauses excluded
+	Paused     time.Duration // total time spent paused
+	Pauses     int           // number of pauses
+	Moves      int
+	Score      int  // arcade points, 0 outside arcade games
+	Rated      bool // played by the standard rules without assistance, see Rated.go
+}
+
+// gameClock : play time for the current game
+type gameClock struct {
+	started   time.Time
+	pausedAt  time.Time // start of the current pause, zero while running
+	pausedFor time.Duration
+	pauses    int
+}
+
+// startClock -- start timing a new game
+func (g *Game) startClock() {
+	g.clock = gameClock{started: time.Now()}
+}
+
+// Pause -- stop the game clock. Pausing a paused game does nothing
+func (g *Game) Pause() {
+	if g.Paused() {
+		return
+	}
+	g.clock.pausedAt = time.Now()
+	g.clock.pauses++
+}
+
+// Resume -- restart the game clock after a pause
+func (g *Game) Resume() {
+	if !g.Paused() {
+		return
+	}
+	g.clock.pausedFor += time.Since(g.clock.pausedAt)
+	g.clock.pausedAt = time.Time{}
+}
+
+// discount -- take time the player couldn't play in, such as a cascade being drawn, off the clock
+func (g *Game) discount(d time.Duration) {
+	g.clock.started = g.clock.started.Add(d)
+}
+
+// Paused -- true while the game clock is stopped
+func (g *Game) Paused() bool {
+	return !g.clock.pausedAt.IsZero()
+}
+
+// Elapsed -- play time of the current game, excluding pauses
+func (g *Game) Elapsed() time.Duration {
+	if g.clock.started.IsZero() {
+		return 0
+	}
+	end := time.Now()
+	if g.Paused() {
+		end = g.clock.pausedAt
+	}
+	return end.Sub(g.clock.started) - g.clock.pausedFor
+}
+
+// Results -- results of the games finished so far, oldest first
+func (g *Game) Results() []GameResult {
+	return append([]GameResult(nil), g.results...)
+}
+
+// recordResult -- stop the clock and keep the result of a finished game, adaptive games under their own difficulty
+func (g *Game) recordResult(board *msboard.Board, moves int, rated bool) GameResult {
+	difficulty := board.Difficulty()
+	if g.adapting {
+		difficulty = msstats.AdaptiveDifficulty
+	}
+	return g.record(difficulty, board.Status(), moves, rated)
+}
+
+// record -- stop the clock and keep the result of a finished game played on boards of a difficulty; analysis games
+// are only reported, not kept
+func (g *Game) record(difficulty string, status msboard.Status, moves int, rated bool) GameResult {
+	g.Resume()
+	retval := GameResult{
+		Difficulty: difficulty,
+		Status:     status,
+		Played:     g.Elapsed(),
+		Paused:     g.clock.pausedFor,
+		Pauses:     g.clock.pauses,
+		Moves:      moves,
+		Score:      g.powerUps.Score,
+		Rated:      rated,
+	}
+	g.powerUps = PowerUps{}
+	if !g.analysis {
+		g.results = append(g.results, retval)
+	}
+	return retval
+}
@@ -0,0 +1,61 @@
+package msgame
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPauseClock(t *testing.T) {
+	game := New(1995)
+	game.startClock()
+	time.Sleep(10 * time.Millisecond)
+
+	game.Pause()
+	game.Pause() // pausing twice is one pause
+	frozen := game.Elapsed()
+	time.Sleep(20 * time.Millisecond)
+	if !game.Paused() || game.Elapsed() != frozen {
+		t.Errorf("Elapsed moved while paused: %v then %v", frozen, game.Elapsed())
+	}
+
+	game.Resume()
+	if game.Paused() || game.clock.pauses != 1 || game.clock.pausedFor < 20*time.Millisecond {
+		t.Errorf("Resume wanted 1 pause of at least 20ms got %d, %v", game.clock.pauses, game.clock.pausedFor)
+	}
+	if elapsed := game.Elapsed(); elapsed < frozen || elapsed > frozen+15*time.Millisecond {
+		t.Errorf("Elapsed after resume wanted about %v got %v", frozen, elapsed)
+	}
+}
+
+func TestPauseCommand(t *testing.T) {
+	game := New(1995)
+
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("e\na1\npause\n\nd1\nq\n"), out); err != nil {
+		t.Fatalf("paused game failed: %s", err)
+	}
+	if !strings.Contains(out.String(), "*** PAUSED ***") {
+		t.Errorf("pause command didn't show the pause screen")
+	}
+
+	results := game.Results()
+	if len(results) != 1 {
+		t.Fatalf("Results wanted 1 finished game got %d", len(results))
+	}
+	if results[0].Pauses != 1 || results[0].Moves != 2 || results[0].Difficulty != "easy" {
+		t.Errorf("Results wanted 1 pause and 2 moves on easy got %+v", results[0])
+	}
+}
+
+func TestDiscount(t *testing.T) {
+	game := New(1995)
+	game.startClock()
+	time.Sleep(20 * time.Millisecond)
+
+	game.discount(15 * time.Millisecond)
+	if elapsed := game.Elapsed(); elapsed < 5*time.Millisecond || elapsed > 15*time.Millisecond {
+		t.Errorf("Elapsed after discounting 15ms of 20ms wanted about 5ms got %v", elapsed)
+	}
+}
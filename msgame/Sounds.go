@@ -0,0 +1,49 @@
+/*
+
+	Sounds.go - audio cues for console games, see msengine.Sounds. Terminals ring the bell when a game is won or
+	lost unless other sounds are set
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"go-mines/msrender"
+	"io"
+)
+
+// SetSounds -- play audio cues for moves through s, msengine.Silence for none; nil, the default, rings the
+// terminal bell when a game ends if the game is played on a terminal
+func (g *Game) SetSounds(s msengine.Sounds) {
+	g.sounds = s
+}
+
+// soundsFor -- the sounds of a game written to out
+func (g *Game) soundsFor(out io.Writer, caps msrender.Capabilities) msengine.Sounds {
+	if nil != g.sounds {
+		return g.sounds
+	}
+	if caps.TTY {
+		return msengine.Bell{W: out}
+	}
+	return msengine.Silence{}
+}
+
+// moveCue -- the cue for a move just played on the board, which revealed opened; false for moves that did nothing
+func moveCue(board *msboard.Board, move msboard.Move, opened []msboard.Location) (msengine.Cue, bool) {
+	switch {
+	case move.Type == msboard.MoveFlag:
+		return msengine.CueFlag, true
+	case board.Status() == msboard.StatusLost:
+		return msengine.CueExplode, true
+	case board.Status() == msboard.StatusWon:
+		return msengine.CueWin, true
+	case len(opened) > 0:
+		return msengine.CueReveal, true
+	}
+	return 0, false
+}
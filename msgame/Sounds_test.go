@@ -0,0 +1,37 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/msengine"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// cueLog : Sounds that records every cue
+type cueLog []msengine.Cue
+
+func (c *cueLog) Play(cue msengine.Cue) { *c = append(*c, cue) }
+
+func TestSounds(t *testing.T) {
+	game := New(1995)
+	var log cueLog
+	game.SetSounds(&log)
+
+	// d1 is a mine on this seed
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("e\na1\nf d1\nf d1\nd1\nq\n"), out); err != nil {
+		t.Fatalf("game failed: %s", err)
+	}
+	want := cueLog{msengine.CueReveal, msengine.CueFlag, msengine.CueFlag, msengine.CueExplode}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("cues wanted %v got %v", want, log)
+	}
+
+	// by default, games written to files and pipes don't ring the bell
+	out.Reset()
+	New(1995).RunConsole(strings.NewReader("e\na1\nd1\nq\n"), out)
+	if !strings.Contains(out.String(), "Game lost") || strings.Contains(out.String(), "\a") {
+		t.Errorf("bell rung outside a terminal")
+	}
+}
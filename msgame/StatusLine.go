@@ -0,0 +1,112 @@
+/*
+
+	StatusLine.go - a line of counters drawn above the board, so the player needn't count them from the grid
+
+		easy | 8 of 10 mines left | 2 flags | 61 safe cells | 42s | board 1.pcg32.9x9x10.1548714027.A1
+
+	The mines left are an estimate, the mines less the flags, which is only right if every flag is. On terminals
+	the line is redrawn every second, so the clock keeps running while the player types
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/msrender"
+	"io"
+	"sync"
+	"time"
+)
+
+// statusTick -- how often the status line is redrawn on terminals
+const statusTick = time.Second
+
+// SetStatusLine -- draw the game's counters, clock and board above the board
+func (g *Game) SetStatusLine(enabled bool) {
+	g.status = enabled
+}
+
+// statusLine : the status line of a game in play. The board it names changes when the first click lays out the
+// mines, and the line is drawn from the ticker's goroutine as well as the game's
+type statusLine struct {
+	mu      sync.Mutex
+	board   string // board code, or the game's seed for boards without one
+	elapsed func() time.Duration
+}
+
+// newStatusLine -- the status line of a game on the board with a code, or started from seed if it has none, timed
+// by elapsed
+func newStatusLine(code string, seed int64, elapsed func() time.Duration) *statusLine {
+	retval := &statusLine{elapsed: elapsed}
+	retval.setCode(code, seed)
+	return retval
+}
+
+// setCode -- name the board by its code, or by the game's seed if it has none
+func (l *statusLine) setCode(code string, seed int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.board = "board " + code
+	if code == "" {
+		l.board = fmt.Sprintf("seed %d", seed)
+	}
+}
+
+// text -- the line for a snapshot of the board, as msrender.Options.Status
+func (l *statusLine) text(s msboard.Snapshot) string {
+	l.mu.Lock()
+	board := l.board
+	l.mu.Unlock()
+
+	// boards count their safe cells once the mines are laid
+	safe := s.SafeRemaining
+	if s.Status == msboard.StatusUninitialized {
+		safe = s.Rows*s.Cols - s.Mines
+	}
+	return fmt.Sprintf("%s | %d of %d mines left | %s | %s | %s | %s", s.Difficulty, s.Mines-s.Flags, s.Mines,
+		plural(s.Flags, "flag"), plural(safe, "safe cell"), l.elapsed().Truncate(time.Second), board)
+}
+
+// plural -- a count of things, "1 flag" or "2 flags"
+func plural(n int, thing string) string {
+	if n == 1 {
+		return "1 " + thing
+	}
+	return fmt.Sprintf("%d %ss", n, thing)
+}
+
+// followStatus -- keep the status line's clock running on terminals until stop is called. Other renderers draw
+// it with each frame only
+func followStatus(out io.Writer, renderer msrender.Renderer) (stop func()) {
+	live, ok := renderer.(*msrender.PartialRenderer)
+	if !ok {
+		return func() {}
+	}
+
+	quit, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(statusTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-quit:
+				return
+			case <-ticker.C:
+				live.RefreshStatus(out)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(quit)
+			<-done
+		})
+	}
+}
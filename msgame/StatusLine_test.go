@@ -0,0 +1,37 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatusLine(t *testing.T) {
+	game := New(1995)
+	game.SetStatusLine(true)
+
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("e\na1\nf d1\nq\n"), out); err != nil {
+		t.Fatalf("game failed: %s", err)
+	}
+	for _, want := range []string{"easy | 10 of 10 mines left | 0 flags | 71 safe cells | 0s | seed 1995\n",
+		"easy | 9 of 10 mines left | 1 flag | ", " | board 1.pcg32.9x9x10.1548714027.A1\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("status lines missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestStatusLineText(t *testing.T) {
+	b, err := msboard.ParseLayout("*.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := newStatusLine("", 7, func() time.Duration { return 83 * time.Second })
+	want := "custom | 1 of 1 mines left | 0 flags | 1 safe cell | 1m23s | seed 7"
+	if got := line.text(b.Snapshot()); got != want {
+		t.Errorf("status line wanted %q got %q", want, got)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+
+	Store.go - keeping finished games, replays and puzzle completion in a storage backend rather than the files
+	named by SetHistoryFile, SetReplayDir and SetStatsFile, and syncing it with a remote store
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"errors"
+	"fmt"
+	"go-mines/msstore"
+	"io"
+)
+
+// SetStore -- keep finished games, replays and puzzle completion in a store, loading the history and completion
+// already there. Takes the place of the history, replay and stats files
+func (g *Game) SetStore(s msstore.Store) error {
+	history, err := msstore.LoadHistory(s)
+	if err != nil {
+		return err
+	}
+	stats, err := msstore.LoadPuzzleStats(s)
+	if err != nil {
+		return err
+	}
+	g.store, g.history, g.stats = s, history, stats
+	return nil
+}
+
+// Stored -- true once a store has been set
+func (g *Game) Stored() bool {
+	return nil != g.store
+}
+
+// SetSync -- sync the store with a remote one now, picking up the games and stats played elsewhere, and again after
+// every finished game. Needs a store on this machine set first
+func (g *Game) SetSync(remote msstore.Stamped) (msstore.SyncReport, error) {
+	local, ok := g.store.(msstore.Local)
+	if !ok {
+		return msstore.SyncReport{}, errors.New("syncing needs a local store")
+	}
+	report, err := msstore.Sync(local, remote)
+	if err != nil {
+		return report, err
+	}
+	if err = g.SetStore(local); err != nil {
+		return report, err
+	}
+	g.remote = remote
+	return report, nil
+}
+
+// syncStore -- after a game, send it and anything else new to the remote store, telling the player what moved
+func (g *Game) syncStore(out io.Writer) {
+	local, ok := g.store.(msstore.Local)
+	if nil == g.remote || !ok {
+		return
+	}
+	report, err := msstore.Sync(local, g.remote)
+	if err != nil {
+		fmt.Fprintln(out, "sync failed:", err)
+	} else if report.Moved() {
+		fmt.Fprintln(out, "Synced:", report)
+	}
+}
@@ -0,0 +1,92 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"go-mines/msreplay"
+	"go-mines/msstore"
+	"strings"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	s, err := msstore.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := New(1995)
+	if err := g.SetStore(s); err != nil {
+		t.Fatal(err)
+	}
+
+	// d1 is a mine on this seed
+	out := bytes.NewBufferString("")
+	if err := g.RunConsole(strings.NewReader("e\na1\nd1\nq\n"), out); err != nil {
+		t.Fatalf("game failed: %s", err)
+	}
+	if !strings.Contains(out.String(), "replay saved as replay-") {
+		t.Errorf("replay not saved:\n%s", out)
+	}
+	if games, _ := s.List(msstore.KindGames); len(games) != 1 {
+		t.Errorf("stored games %q", games)
+	}
+	if replays, _ := s.List(msstore.KindReplays); len(replays) != 1 {
+		t.Errorf("stored replays %q", replays)
+	}
+
+	// a new session picks up the history
+	g = New(1995)
+	g.SetStore(s)
+	out.Reset()
+	g.RunConsole(strings.NewReader("q\n"), out)
+	if !strings.Contains(out.String(), "[S]tats") {
+		t.Errorf("stored history not loaded:\n%s", out)
+	}
+}
+
+func TestSync(t *testing.T) {
+	remote, _ := msstore.NewFileStore(t.TempDir())
+	g := New(1995)
+	if _, err := g.SetSync(remote); err == nil {
+		t.Error("synced without a store")
+	}
+
+	// a game played elsewhere is picked up before play starts
+	remote.Put(msstore.KindGames, "20240301T120000.000000000Z_1", []byte(`{"difficulty": "hard", "rated": true}`))
+	local, _ := msstore.NewFileStore(t.TempDir())
+	g.SetStore(local)
+	if report, err := g.SetSync(remote); err != nil || report.Downloaded != 1 || len(g.history.Games) != 1 {
+		t.Fatalf("sync %v, %v, history %+v", report, err, g.history)
+	}
+
+	// and a game played here is sent after it's over
+	out := bytes.NewBufferString("")
+	g.RunConsole(strings.NewReader("e\na1\nd1\nq\n"), out)
+	if !strings.Contains(out.String(), "Synced: 2 up, 0 down") {
+		t.Errorf("game and replay not synced:\n%s", out)
+	}
+	if games, _ := remote.List(msstore.KindGames); len(games) != 2 {
+		t.Errorf("remote games %q", games)
+	}
+}
+
+func TestAntiMineReplay(t *testing.T) {
+	dir := t.TempDir()
+	g := New(1995)
+	g.SetGenerator(msboard.GeneratorOptions{AntiMines: 2})
+	g.SetReplayDir(dir)
+
+	// stepping on every cell ends the game at the first mine of either kind
+	out := bytes.NewBufferString("")
+	if err := g.RunConsole(strings.NewReader("e\na1\ns a1:i9\nq\n"), out); err != nil {
+		t.Fatalf("game failed: %s", err)
+	}
+	replays, err := msreplay.LoadDir(dir)
+	if err != nil || len(replays) != 1 {
+		t.Fatalf("wanted a saved replay got %d, err %v:\n%s", len(replays), err, out)
+	}
+	if rules := replays[0].Format.Rules; len(rules) != 1 || rules[0] != msengine.RuleAntiMines {
+		t.Errorf("anti-mine replay wanted the anti-mines rule got %+v", replays[0].Format)
+	}
+}
@@ -0,0 +1,146 @@
+/*
+
+	TimeTravel.go - debug builds keep every position a game passes through, so a bug can be stepped up to, looked at
+	from either side and played differently
+
+	The positions are kept as lines of play, each starting with the board before the first move. goto puts the
+	board back at any position of any line; moves made from there replace the rest of the line, unless fork first
+	starts a new line from the position, keeping the old one to go back to. diff lists the cells that differ between
+	two positions. Positions are written as moves made, e.g. 12, or on another line as line:moves, e.g. 2:12
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"errors"
+	"fmt"
+	"go-mines/msboard"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// timeTravel : when set, games with developer commands enabled keep every position for the goto, fork, lines and
+// diff commands. Set by debug builds (go build -tags debug) and by this package's tests
+var timeTravel = false
+
+// enableTimeTravel -- turn time travel on, making its commands known
+func enableTimeTravel() {
+	timeTravel = true
+	for _, word := range []string{"goto", "fork", "lines", "diff"} {
+		commandWords[word] = true
+	}
+}
+
+// timeline : lines of play, each the position before the first move followed by the position after every move
+type timeline struct {
+	lines [][]msboard.Snapshot
+	line  int // the line the board is on
+	at    int // moves made to reach the board's position on the line
+}
+
+// newTimeline -- a single line, starting from the board as it is
+func newTimeline(board *msboard.Board) *timeline {
+	return &timeline{lines: [][]msboard.Snapshot{{board.Snapshot()}}}
+}
+
+// record -- add the position after a move, forgetting any the line had after the one the move was made from
+func (t *timeline) record(board *msboard.Board) {
+	t.at++
+	t.lines[t.line] = append(t.lines[t.line][:t.at], board.Snapshot())
+}
+
+// position -- find a position given as moves, or line:moves
+func (t *timeline) position(text string) (line, moves int, err error) {
+	line = t.line
+	if at := strings.IndexByte(text, ':'); at >= 0 {
+		if line, err = strconv.Atoi(text[:at]); err != nil || line < 1 || line > len(t.lines) {
+			return 0, 0, fmt.Errorf("no line %q, type lines for the list", text[:at])
+		}
+		line--
+		text = text[at+1:]
+	}
+	if moves, err = strconv.Atoi(text); err != nil || moves < 0 || moves >= len(t.lines[line]) {
+		return 0, 0, fmt.Errorf("line %d has positions 0 to %d, not %q", line+1, len(t.lines[line])-1, text)
+	}
+	return line, moves, nil
+}
+
+// timeTravelCommand -- handle "goto <position>", "fork", "lines" and "diff <position> <position>", returning true
+// if the board went to another position
+func (g *Game) timeTravelCommand(out io.Writer, board *msboard.Board, t *timeline, p *positions, cmd string,
+	args []string) (bool, error) {
+	switch cmd {
+	case "goto":
+		if len(args) != 1 {
+			return false, errors.New("goto needs a position, e.g. goto 12 or goto 2:12")
+		}
+		line, moves, err := t.position(args[0])
+		if err != nil {
+			return false, err
+		}
+		s := t.lines[line][moves]
+		if s.Status == msboard.StatusUninitialized {
+			return false, errors.New("the mines aren't laid until the first move, goto 1 or later")
+		}
+		if err := board.Restore(s); err != nil {
+			return false, err
+		}
+		t.line, t.at = line, moves
+		// a replay can't go back either
+		p.restored = true
+		fmt.Fprintf(out, "At move %d of line %d\n", moves, line+1)
+		return true, nil
+
+	case "fork":
+		fork := append([]msboard.Snapshot{}, t.lines[t.line][:t.at+1]...)
+		t.lines = append(t.lines, fork)
+		fmt.Fprintf(out, "Line %d forked from move %d of line %d\n", len(t.lines), t.at, t.line+1)
+		t.line = len(t.lines) - 1
+		return false, nil
+
+	case "lines":
+		for i, line := range t.lines {
+			marker, at := " ", ""
+			if i == t.line {
+				marker, at = "*", fmt.Sprintf(", at move %d", t.at)
+			}
+			last := line[len(line)-1]
+			fmt.Fprintf(out, "%s line %d: %d moves%s, %s, %d safe cells left\n", marker, i+1, len(line)-1, at,
+				last.Status, last.SafeRemaining)
+		}
+		return false, nil
+
+	case "diff":
+		if len(args) != 2 {
+			return false, errors.New("diff needs two positions, e.g. diff 3 12 or diff 1:12 2:12")
+		}
+		fromLine, fromMoves, err := t.position(args[0])
+		if err != nil {
+			return false, err
+		}
+		toLine, toMoves, err := t.position(args[1])
+		if err != nil {
+			return false, err
+		}
+		delta := msboard.SnapshotDiff(t.lines[fromLine][fromMoves], t.lines[toLine][toMoves])
+		if delta.Empty() {
+			fmt.Fprintln(out, "The positions are the same")
+			return false, nil
+		}
+		fmt.Fprintf(out, "%d cells differ", len(delta.Cells))
+		if delta.StatusChanged() {
+			fmt.Fprintf(out, ", %s to %s", delta.FromStatus, delta.ToStatus)
+		}
+		fmt.Fprintln(out)
+		for _, change := range delta.Cells {
+			fmt.Fprintf(out, "  %-5s %c -> %c\n", g.cellName(change.Location, board), change.From.Rune(),
+				change.To.Rune())
+		}
+		return false, nil
+	}
+	return false, fmt.Errorf("unrecognized time travel command %q", cmd)
+}
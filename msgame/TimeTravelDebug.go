@@ -0,0 +1,17 @@
+//go:build debug
+
+/*
+
+	TimeTravelDebug.go - debug builds keep every position of a game for the time travel commands
+
+		go build -tags debug
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+func init() {
+	enableTimeTravel()
+}
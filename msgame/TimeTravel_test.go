@@ -0,0 +1,40 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/mspuzzle"
+	"strings"
+	"testing"
+)
+
+func TestTimeTravel(t *testing.T) {
+	enableTimeTravel()
+	defer func() {
+		timeTravel = false
+		for _, word := range []string{"goto", "fork", "lines", "diff"} {
+			delete(commandWords, word)
+		}
+	}()
+
+	game := New(1995)
+	game.SetPuzzlePacks([]mspuzzle.Pack{{Title: "Debug", Puzzles: []mspuzzle.Puzzle{{Title: "corner",
+		Layout: "1*1/111/..."}}}})
+	game.SetDebug(true)
+
+	// flag and unflag the mine, go back between the two, fork and flag another cell, compare, then win
+	script := "p\n1\n1\nf b1\nf b1\ngoto 1\nfork\nf c3\nlines\ndiff 1:2 2:2\ndiff 1 1\ngoto 1:2\ngoto 3\n" +
+		"goto 3:0\ngoto\ndiff 1\ns a3\nq\n"
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader(script), out); err != nil {
+		t.Fatalf("debug game failed: %s", err)
+	}
+	for _, want := range []string{"At move 1 of line 1", "Line 2 forked from move 1 of line 1",
+		"  line 1: 2 moves, playing, 3 safe cells left", "* line 2: 2 moves, at move 2, playing, 3 safe cells left",
+		"2 cells differ\n  B1    . -> +\n  C3    . -> +", "The positions are the same", "At move 2 of line 1",
+		`line 1 has positions 0 to 2, not "3"`, `no line "3"`, "goto needs a position", "diff needs two positions",
+		"Game won"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("debug game output missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,98 @@
+/*
+
+	Verbose.go - a spoken style summary of each move, for players who can't easily read the board and for following
+	a game in a log
+
+		Revealed 14 cells; nearest numbers: B3=2, C5=1; 32 safe cells remain
+
+	mike@pocomotech.com
+
+*/
+
+package msgame
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"sort"
+	"strings"
+)
+
+// verboseNumbers : most numbered cells a move summary names, nearest the move first
+const verboseNumbers = 5
+
+// SetVerbose -- after each move, print a one line summary of what it revealed or flagged and what is left
+func (g *Game) SetVerbose(enabled bool) {
+	g.verbose = enabled
+}
+
+// describeMove -- the summary of a move just played on the board, which revealed opened
+func (g *Game) describeMove(board *msboard.Board, move msboard.Move, opened []msboard.Location) string {
+	s := board.Snapshot()
+	name := g.cellName(move.Location, board)
+
+	var parts []string
+	switch {
+	case move.Type == msboard.MoveFlag:
+		verb := "Flagged"
+		if v, _ := s.Cell(move.Location); v.State != msboard.CellFlagged {
+			verb = "Unflagged"
+		}
+		return fmt.Sprintf("%s %s; %d mines unflagged", verb, name, s.Mines-s.Flags)
+	case s.Status == msboard.StatusLost:
+		return fmt.Sprintf("Mine at %s; game lost", name)
+	case len(opened) == 0:
+		parts = append(parts, "Nothing revealed at "+name)
+	case len(opened) == 1:
+		parts = append(parts, "Revealed "+name)
+	default:
+		parts = append(parts, fmt.Sprintf("Revealed %d cells", len(opened)))
+	}
+
+	if numbers := g.nearestNumbers(board, s, move.Location, opened); len(numbers) > 0 {
+		parts = append(parts, "nearest numbers: "+strings.Join(numbers, ", "))
+	}
+	switch {
+	case s.Status == msboard.StatusWon:
+		parts = append(parts, "no safe cells remain, game won")
+	case s.SafeRemaining == 1:
+		parts = append(parts, "1 safe cell remains")
+	default:
+		parts = append(parts, fmt.Sprintf("%d safe cells remain", s.SafeRemaining))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// nearestNumbers -- the numbered cells among those revealed, nearest to the move first then in reading order, as
+// cell=score
+func (g *Game) nearestNumbers(board *msboard.Board, s msboard.Snapshot, from msboard.Location,
+	opened []msboard.Location) []string {
+	distance := func(l msboard.Location) int {
+		dRow, dCol := l.Row()-from.Row(), l.Col()-from.Col()
+		return dRow*dRow + dCol*dCol
+	}
+
+	var numbered []msboard.Location
+	for _, l := range opened {
+		if v, _ := s.Cell(l); v.State == msboard.CellRevealed && v.Score != 0 {
+			numbered = append(numbered, l)
+		}
+	}
+	sort.SliceStable(numbered, func(i, j int) bool {
+		a, b := numbered[i], numbered[j]
+		if distance(a) != distance(b) {
+			return distance(a) < distance(b)
+		}
+		return a.Row() < b.Row() || (a.Row() == b.Row() && a.Col() < b.Col())
+	})
+	if len(numbered) > verboseNumbers {
+		numbered = numbered[:verboseNumbers]
+	}
+
+	retval := make([]string, len(numbered))
+	for i, l := range numbered {
+		v, _ := s.Cell(l)
+		retval[i] = fmt.Sprintf("%s=%d", g.cellName(l, board), v.Score)
+	}
+	return retval
+}
@@ -0,0 +1,47 @@
+package msgame
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"strings"
+	"testing"
+)
+
+func TestVerbose(t *testing.T) {
+	game := New(1995)
+	game.SetVerbose(true)
+
+	out := bytes.NewBufferString("")
+	if err := game.RunConsole(strings.NewReader("e\na1\nf d1\nf d1\nd1\nq\n"), out); err != nil {
+		t.Fatalf("verbose game failed: %s", err)
+	}
+	for _, want := range []string{"Flagged D1; 9 mines unflagged", "Unflagged D1; 10 mines unflagged",
+		"Mine at D1; game lost"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("verbose output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestDescribeMove(t *testing.T) {
+	game := New(1995)
+	b, err := msboard.ParseLayout(".*./.../...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reveal := func(row, col int) string {
+		l := msboard.NewLocation(row, col)
+		return game.describeMove(b, msboard.Move{Type: msboard.MoveReveal, Location: l}, b.Click(l))
+	}
+
+	for _, c := range []struct{ got, want string }{
+		{reveal(2, 0), "Revealed 6 cells; nearest numbers: A2=1, B2=1, C2=1; 2 safe cells remain"},
+		{reveal(2, 0), "Nothing revealed at A3; 2 safe cells remain"},
+		{reveal(0, 0), "Revealed A1; nearest numbers: A1=1; 1 safe cell remains"},
+		{reveal(0, 2), "Revealed C1; nearest numbers: C1=1; no safe cells remain, game won"},
+	} {
+		if c.got != c.want {
+			t.Errorf("wanted %q got %q", c.want, c.got)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+/*
+
+	Mobile.go - gomobile bindings for embedding the engine in Android and iOS apps
+
+	Everything exported here sticks to what gomobile bind can translate: basic types, strings, byte slices,
+	pointers to exported structs and interfaces made of those. Build with e.g.
+
+		gomobile bind -target=android go-mines/msmobile
+
+	mike@pocomotech.com
+
+*/
+
+// Package msmobile -- gomobile-compatible wrapper over the msengine API
+package msmobile
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/msengine"
+)
+
+// Cell values returned by Game.Cell; revealed safe cells return their score, 0 to 8
+const (
+	CellHidden  = -1
+	CellFlagged = -2
+	CellMine    = -3
+)
+
+// Game statuses returned by Game.Status
+const (
+	StatusUninitialized = int(msengine.StatusUninitialized)
+	StatusPlaying       = int(msengine.StatusPlaying)
+	StatusWon           = int(msengine.StatusWon)
+	StatusLost          = int(msengine.StatusLost)
+)
+
+// Listener : callbacks for sounds and animations, implemented on the native side. See msengine.EngineEvents for
+// the order of calls
+type Listener interface {
+	OnReveal(row, col, score int)
+	OnFlag(row, col int, flagged bool)
+	OnExplode(row, col int)
+	OnWin()
+}
+
+// Game : one board and its listener
+type Game struct {
+	board    msengine.Board
+	listener Listener
+}
+
+// NewGame -- start a game on a standard board: "easy", "medium" or "hard"
+func NewGame(difficulty string) (*Game, error) {
+	b, err := msengine.NewBoard(difficulty)
+	if err != nil {
+		return nil, err
+	}
+	return &Game{board: b}, nil
+}
+
+// NewCustomGame -- start a game on a board of any size
+func NewCustomGame(rows, cols, mines int) (*Game, error) {
+	b := msboard.NewCustomBoard(rows, cols, mines)
+	if nil == b {
+		return nil, fmt.Errorf("a %dx%d board can't hold %d mines", rows, cols, mines)
+	}
+	return &Game{board: b}, nil
+}
+
+// SetListener -- receive callbacks for every following move, nil to stop
+func (g *Game) SetListener(l Listener) {
+	g.listener = l
+}
+
+// Reveal -- step on a cell, returning the game status afterwards. The first reveal is always safe
+func (g *Game) Reveal(row, col int) (int, error) {
+	return g.apply(msengine.MoveReveal, row, col)
+}
+
+// ToggleFlag -- place or remove a flag, returning the game status afterwards
+func (g *Game) ToggleFlag(row, col int) (int, error) {
+	return g.apply(msengine.MoveFlag, row, col)
+}
+
+// Chord -- step on the hidden neighbors of a revealed score whose flags are all placed, returning the game status
+// afterwards. Only boards played by rules that chord, see SetRules, respond
+func (g *Game) Chord(row, col int) (int, error) {
+	return g.apply(msengine.MoveChord, row, col)
+}
+
+// SetRules -- play by the rules with a name: "classic", or "chord" for classic play with chording
+func (g *Game) SetRules(name string) error {
+	rules, err := msengine.LookupRules(name)
+	if err != nil {
+		return err
+	}
+	g.board.SetRules(rules)
+	return nil
+}
+
+// apply -- apply a move, forwarding events to the listener
+func (g *Game) apply(t msengine.MoveType, row, col int) (int, error) {
+	m := msengine.Move{Type: t, Location: msengine.NewLocation(row, col)}
+
+	var events msengine.EngineEvents
+	if nil != g.listener {
+		events = listenerEvents{g.listener}
+	}
+	status, err := msengine.ApplyWithEvents(g.board, m, events)
+	return int(status), err
+}
+
+// Status -- current game status, one of the Status constants
+func (g *Game) Status() int {
+	return int(g.board.Status())
+}
+
+// Rows -- board height
+func (g *Game) Rows() int {
+	return g.board.Snapshot().Rows
+}
+
+// Cols -- board width
+func (g *Game) Cols() int {
+	return g.board.Snapshot().Cols
+}
+
+// Mines -- number of mines on the board
+func (g *Game) Mines() int {
+	return g.board.Snapshot().Mines
+}
+
+// Cell -- what the player sees at a cell: a score 0-8 or one of the Cell constants. Off-board cells are hidden
+func (g *Game) Cell(row, col int) int {
+	view, _ := g.board.Snapshot().Cell(msengine.NewLocation(row, col))
+	switch view.State {
+	case msengine.CellFlagged:
+		return CellFlagged
+	case msengine.CellMine:
+		return CellMine
+	case msengine.CellRevealed:
+		return view.Score
+	}
+	return CellHidden
+}
+
+// Snapshot -- the whole visible board as JSON, see msengine.Snapshot
+func (g *Game) Snapshot() ([]byte, error) {
+	return json.Marshal(g.board.Snapshot())
+}
+
+// listenerEvents : adapts a native Listener to the engine's event hooks
+type listenerEvents struct {
+	l Listener
+}
+
+// OnReveal -- forward a reveal
+func (e listenerEvents) OnReveal(l msengine.Location, v msengine.CellView) {
+	e.l.OnReveal(l.Row(), l.Col(), v.Score)
+}
+
+// OnFlag -- forward a flag change
+func (e listenerEvents) OnFlag(l msengine.Location, flagged bool) {
+	e.l.OnFlag(l.Row(), l.Col(), flagged)
+}
+
+// OnExplode -- forward an explosion
+func (e listenerEvents) OnExplode(l msengine.Location) {
+	e.l.OnExplode(l.Row(), l.Col())
+}
+
+// OnWin -- forward a win
+func (e listenerEvents) OnWin() {
+	e.l.OnWin()
+}
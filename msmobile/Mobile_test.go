@@ -0,0 +1,115 @@
+package msmobile
+
+import (
+	"encoding/json"
+	"go-mines/msboard"
+	"math/rand"
+	"testing"
+)
+
+// countingListener : Listener that counts callbacks
+type countingListener struct {
+	reveals, flags, explosions, wins int
+}
+
+func (c *countingListener) OnReveal(row, col, score int)      { c.reveals++ }
+func (c *countingListener) OnFlag(row, col int, flagged bool) { c.flags++ }
+func (c *countingListener) OnExplode(row, col int)            { c.explosions++ }
+func (c *countingListener) OnWin()                            { c.wins++ }
+
+func TestMobileGame(t *testing.T) {
+	rand.Seed(1995)
+	g, err := NewGame("easy")
+	if err != nil {
+		t.Fatalf("NewGame failed: %s", err)
+	}
+	if g.Rows() != 9 || g.Cols() != 9 || g.Mines() != 10 || g.Status() != StatusUninitialized {
+		t.Errorf("NewGame wanted 9x9, 10 mines, uninitialized got %dx%d, %d, %d", g.Rows(), g.Cols(), g.Mines(), g.Status())
+	}
+
+	listener := new(countingListener)
+	g.SetListener(listener)
+	status, err := g.Reveal(4, 4)
+	if err != nil || status != StatusPlaying {
+		t.Fatalf("first Reveal wanted playing got %d, err %v", status, err)
+	}
+	if g.Cell(4, 4) < 0 || listener.reveals == 0 {
+		t.Errorf("first Reveal wanted a revealed score and reveal events, got cell %d and %d events", g.Cell(4, 4), listener.reveals)
+	}
+
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			if g.Cell(row, col) == CellHidden {
+				g.ToggleFlag(row, col)
+				if g.Cell(row, col) != CellFlagged || listener.flags != 1 {
+					t.Errorf("ToggleFlag wanted a flag and one event got %d, %d events", g.Cell(row, col), listener.flags)
+				}
+				row, col = 9, 9
+			}
+		}
+	}
+
+	if _, err = g.Reveal(-1, 0); nil == err {
+		t.Errorf("Reveal accepted an off-board cell")
+	}
+
+	data, err := g.Snapshot()
+	var decoded map[string]interface{}
+	if err != nil || json.Unmarshal(data, &decoded) != nil || decoded["rows"] != 9.0 {
+		t.Errorf("Snapshot wanted JSON with 9 rows got %s, err %v", data, err)
+	}
+
+	if _, err = NewCustomGame(2, 2, 4); nil == err {
+		t.Errorf("NewCustomGame accepted a board with no safe cells")
+	}
+}
+
+func TestMobileChord(t *testing.T) {
+	rand.Seed(1995)
+	g, err := NewGame("easy")
+	if err != nil {
+		t.Fatalf("NewGame failed: %s", err)
+	}
+	if err = g.SetRules("moving-mines"); nil == err {
+		t.Errorf("SetRules accepted unknown rules")
+	}
+	if err = g.SetRules("chord"); err != nil {
+		t.Fatalf("SetRules failed: %s", err)
+	}
+	g.Reveal(4, 4)
+
+	// flag the mines around the first score with a hidden safe neighbor, then chord it
+	b := g.board.(*msboard.Board)
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			if g.Cell(row, col) <= 0 {
+				continue
+			}
+			var mines, safe []msboard.Location
+			for _, n := range b.Snapshot().Neighbors(msboard.NewLocation(row, col)) {
+				if b.MineAt(n) {
+					mines = append(mines, n)
+				} else if g.Cell(n.Row(), n.Col()) == CellHidden {
+					safe = append(safe, n)
+				}
+			}
+			if len(safe) == 0 {
+				continue
+			}
+
+			for _, l := range mines {
+				g.ToggleFlag(l.Row(), l.Col())
+			}
+			if status, err := g.Chord(row, col); err != nil || status == StatusLost {
+				t.Fatalf("Chord at %d,%d wanted play to go on got %d, err %v", row, col, status, err)
+			}
+			for _, l := range safe {
+				if g.Cell(l.Row(), l.Col()) < 0 {
+					t.Errorf("Chord at %d,%d left %v hidden", row, col, l)
+				}
+			}
+			return
+		}
+	}
+	t.Errorf("no score to chord")
+}
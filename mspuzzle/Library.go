@@ -0,0 +1,260 @@
+/*
+
+	Library.go - collections of no-guess puzzles, each position stored once however it's turned or flipped
+
+	A puzzle is no-guess if it can be cleared from its revealed cells by deduction alone. Entries are rated by the
+	weakest solver that clears them and tagged with the resulting grade, so a front end can pick puzzles to suit
+	the player.
+
+	mike@pocomotech.com
+
+*/
+
+package mspuzzle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"go-mines/mssolver"
+	"io"
+	"os"
+	"time"
+)
+
+// ErrNeedsGuess : the puzzle can't be cleared without guessing
+var ErrNeedsGuess = errors.New("puzzle needs a guess")
+
+// rateBudget : time the exact solver gets per position when rating a puzzle. Positions it can't settle in time
+// count as needing a guess
+const rateBudget = time.Second
+
+// graders : solvers used to rate puzzles, weakest first, with the grade of a puzzle needing each
+var graders = []struct {
+	name   string
+	grade  string
+	solver msengine.Solver
+}{
+	{"counting", "gentle", mssolver.Counting{}},
+	{"subset", "tricky", mssolver.Subset{}},
+	{"endgame", "fiendish", mssolver.Endgame{Budget: rateBudget}},
+}
+
+// Metrics : how hard a no-guess puzzle is to clear
+type Metrics struct {
+	Mines  int    `json:"mines"`
+	Steps  int    `json:"steps"`  // rounds of deduction needed, each clicking every cell found safe
+	Clicks int    `json:"clicks"` // cells clicked, not counting those opened by a click
+	Solver string `json:"solver"` // the weakest solver that clears the puzzle
+}
+
+// Grade -- gentle, tricky or fiendish, for puzzles needing single scores, pairs of scores or the whole frontier
+// and mine count. Named apart from the board difficulties so both can be tags
+func (m Metrics) Grade() string {
+	for _, g := range graders {
+		if g.name == m.Solver {
+			return g.grade
+		}
+	}
+	return "unknown"
+}
+
+// Rate -- clear the puzzle by deduction, each time using the weakest solver that finds a safe cell, and measure
+// what it took. ErrNeedsGuess if no solver finds one before the board is cleared
+func Rate(p Puzzle) (Metrics, error) {
+	b, err := p.Board()
+	if err != nil {
+		return Metrics{}, err
+	}
+
+	retval, hardest := Metrics{Mines: b.MineCount()}, 0
+	for b.Status() == msboard.StatusPlaying {
+		s := b.Snapshot()
+		var safe []msboard.Location
+		for level, g := range graders {
+			if safe, _ = g.solver.Deductions(s); len(safe) > 0 {
+				if level > hardest {
+					hardest = level
+				}
+				break
+			}
+		}
+		if len(safe) == 0 {
+			return Metrics{}, ErrNeedsGuess
+		}
+
+		retval.Steps++
+		for _, l := range safe {
+			if view, _ := b.Snapshot().Cell(l); view.State == msboard.CellHidden {
+				b.Click(l)
+				retval.Clicks++
+			}
+		}
+	}
+	if b.Status() != msboard.StatusWon {
+		return Metrics{}, fmt.Errorf("puzzle %q: a deduced safe cell held a mine", p.Title)
+	}
+	retval.Solver = graders[hardest].name
+	return retval, nil
+}
+
+// Generate -- lay out boards around the first click until one is no-guess from there, giving up after attempts
+// boards. The puzzle is the position after the first click
+func Generate(title string, rows, cols, mines int, first msboard.Location, opts msboard.GeneratorOptions,
+	attempts int) (Puzzle, Metrics, error) {
+	for attempt := 0; attempt < attempts; attempt++ {
+		b := msboard.NewCustomBoard(rows, cols, mines)
+		if nil == b {
+			return Puzzle{}, Metrics{}, fmt.Errorf("can't create a %dx%d board with %d mines", rows, cols, mines)
+		}
+		if _, err := b.FirstClickWithOptions(first, opts); err != nil {
+			return Puzzle{}, Metrics{}, err
+		}
+
+		p := FromBoard(title, b)
+		if m, err := Rate(p); err == nil {
+			return p, m, nil
+		}
+	}
+	return Puzzle{}, Metrics{}, fmt.Errorf("no no-guess %dx%d board with %d mines in %d attempts", rows, cols, mines, attempts)
+}
+
+// Entry : a library puzzle with its rating
+type Entry struct {
+	Title   string   `json:"title"`
+	Layout  string   `json:"layout"`
+	Hash    string   `json:"hash"` // msboard.Board.CanonicalHash of the layout
+	Metrics Metrics  `json:"metrics"`
+	Tags    []string `json:"tags,omitempty"` // the grade, then any added by the caller
+}
+
+// Puzzle -- the entry as a puzzle
+func (e Entry) Puzzle() Puzzle {
+	return Puzzle{Format: msengine.CurrentHeader(), Title: e.Title, Layout: e.Layout}
+}
+
+// HasTag -- true if the entry is tagged with tag
+func (e Entry) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Library : no-guess puzzles, no two of them the same position turned or flipped
+type Library struct {
+	Format  msengine.Header `json:"format"`
+	Entries []Entry         `json:"entries"`
+	hashes  map[string]bool
+}
+
+// NewLibrary -- create an empty library
+func NewLibrary() *Library {
+	return &Library{Format: msengine.CurrentHeader(), hashes: make(map[string]bool)}
+}
+
+// Add -- rate a puzzle and add it with the given tags, unless the library already has its position. Returns the
+// entry, or the one already there, and whether it was added. Puzzles needing a guess are refused with
+// ErrNeedsGuess
+func (l *Library) Add(p Puzzle, tags ...string) (Entry, bool, error) {
+	b, err := p.Board()
+	if err != nil {
+		return Entry{}, false, err
+	}
+	hash := b.CanonicalHash()
+	if l.hashes[hash] {
+		for _, e := range l.Entries {
+			if e.Hash == hash {
+				return e, false, nil
+			}
+		}
+	}
+
+	m, err := Rate(p)
+	if err != nil {
+		return Entry{}, false, err
+	}
+	e := Entry{Title: p.Title, Layout: p.Layout, Hash: hash, Metrics: m, Tags: append([]string{m.Grade()}, tags...)}
+	l.Entries = append(l.Entries, e)
+	l.hashes[hash] = true
+	return e, true, nil
+}
+
+// Contains -- true if the library has the board's position, however it's turned or flipped
+func (l *Library) Contains(b *msboard.Board) bool {
+	return l.hashes[b.CanonicalHash()]
+}
+
+// Tagged -- the entries carrying a tag, in the order they were added
+func (l *Library) Tagged(tag string) []Entry {
+	var retval []Entry
+	for _, e := range l.Entries {
+		if e.HasTag(tag) {
+			retval = append(retval, e)
+		}
+	}
+	return retval
+}
+
+// WriteLibrary -- encode the library as indented JSON
+func WriteLibrary(w io.Writer, l *Library) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(l)
+}
+
+// ReadLibrary -- decode a library, checking every layout and rebuilding the hashes, so duplicates added by hand
+// are dropped
+func ReadLibrary(r io.Reader) (*Library, error) {
+	var stored Library
+	if err := json.NewDecoder(r).Decode(&stored); err != nil {
+		return nil, err
+	}
+	if err := stored.Format.Check(); err != nil {
+		return nil, fmt.Errorf("puzzle library: %s", err)
+	}
+
+	retval := NewLibrary()
+	for _, e := range stored.Entries {
+		b, err := e.Puzzle().Board()
+		if err != nil {
+			return nil, err
+		}
+		if e.Hash = b.CanonicalHash(); !retval.hashes[e.Hash] {
+			retval.Entries = append(retval.Entries, e)
+			retval.hashes[e.Hash] = true
+		}
+	}
+	return retval, nil
+}
+
+// SaveLibrary -- write the library to a file, replacing any existing one
+func SaveLibrary(filename string, l *Library) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err = WriteLibrary(f, l); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadLibrary -- read a library from a file; a file that doesn't exist yet is an empty library
+func LoadLibrary(filename string) (*Library, error) {
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return NewLibrary(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadLibrary(f)
+}
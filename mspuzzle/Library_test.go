@@ -0,0 +1,113 @@
+/*
+	Test functions for puzzle libraries
+
+	mike@pocomotech.com
+*/
+
+package mspuzzle
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRate(t *testing.T) {
+	var cases = []struct {
+		layout string
+		want   Metrics
+		grade  string
+	}{
+		// the corner 1 pins the mine, and the 1s below it are then satisfied
+		{"1*1/111/...", Metrics{Mines: 1, Steps: 1, Clicks: 1, Solver: "counting"}, "gentle"},
+		// the only mine must be beside the 1, leaving the far cell safe, but only the mine count says so
+		{"*1..", Metrics{Mines: 1, Steps: 1, Clicks: 1, Solver: "endgame"}, "fiendish"},
+	}
+	for _, testcase := range cases {
+		got, err := Rate(Puzzle{Layout: testcase.layout})
+		if err != nil || got != testcase.want {
+			t.Errorf("Rate(%q) wanted %+v got %+v err %v", testcase.layout, testcase.want, got, err)
+		}
+		if got.Grade() != testcase.grade {
+			t.Errorf("Rate(%q) wanted grade %s got %s", testcase.layout, testcase.grade, got.Grade())
+		}
+	}
+
+	if _, err := Rate(Puzzle{Layout: "..*/..."}); err != ErrNeedsGuess {
+		t.Errorf("Rate() of a position with nothing revealed wanted ErrNeedsGuess got %v", err)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	rand.Seed(1995)
+	p, m, err := Generate("generated", 9, 9, 10, msboard.NewLocation(4, 4), msboard.GeneratorOptions{MinOpening: 1}, 200)
+	if err != nil {
+		t.Fatalf("Generate() failed: %s", err)
+	}
+	if m.Mines != 10 || m.Steps == 0 || !strings.ContainsAny(p.Layout, "012345678") {
+		t.Errorf("Generate() wanted a no-guess position after the first click, got %q rated %+v", p.Layout, m)
+	}
+	if _, err = Rate(p); err != nil {
+		t.Errorf("Generate() gave a puzzle that doesn't rate: %s", err)
+	}
+}
+
+func TestLibrary(t *testing.T) {
+	lib := NewLibrary()
+	easy := Puzzle{Title: "easy", Layout: "1*1/111/..."}
+	if e, added, err := lib.Add(easy, "tutorial"); err != nil || !added || !e.HasTag("gentle") || !e.HasTag("tutorial") {
+		t.Fatalf("Add() wanted a new entry tagged gentle and tutorial got %+v added %v err %v", e, added, err)
+	}
+
+	// the same position upside down is already there
+	b, _ := easy.Board()
+	flipped, _ := b.Transform(msboard.TransformMirrorH)
+	if e, added, err := lib.Add(FromBoard("flipped", flipped)); err != nil || added || e.Title != "easy" {
+		t.Errorf("Add() of a flipped copy wanted the existing entry got %+v added %v err %v", e, added, err)
+	}
+	if !lib.Contains(flipped) {
+		t.Errorf("Contains() missed a flipped copy")
+	}
+	if _, _, err := lib.Add(Puzzle{Title: "guess", Layout: "..*/..."}); err != ErrNeedsGuess {
+		t.Errorf("Add() of a guessing puzzle wanted ErrNeedsGuess got %v", err)
+	}
+	lib.Add(Puzzle{Title: "hard", Layout: "*1.."})
+
+	if got := lib.Tagged("gentle"); len(got) != 1 || got[0].Title != "easy" {
+		t.Errorf("Tagged(gentle) wanted the easy puzzle got %+v", got)
+	}
+	if got := lib.Tagged("fiendish"); len(got) != 1 || got[0].Title != "hard" {
+		t.Errorf("Tagged(fiendish) wanted the hard puzzle got %+v", got)
+	}
+
+	// a duplicate slipped into the file is dropped on reading
+	lib.Entries = append(lib.Entries, Entry{Title: "copy", Layout: flipped.Layout()})
+	buf := bytes.NewBufferString("")
+	if err := WriteLibrary(buf, lib); err != nil {
+		t.Fatalf("WriteLibrary() failed: %s", err)
+	}
+	got, err := ReadLibrary(buf)
+	if err != nil || len(got.Entries) != 2 || got.Entries[1].Metrics.Solver != "endgame" {
+		t.Errorf("ReadLibrary() wanted the two puzzles got %+v err %v", got, err)
+	}
+}
+
+func TestLibraryFiles(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "library.json")
+	lib, err := LoadLibrary(filename)
+	if err != nil || len(lib.Entries) != 0 {
+		t.Fatalf("LoadLibrary() of a new file wanted an empty library got %+v err %v", lib, err)
+	}
+
+	lib.Add(Puzzle{Title: "easy", Layout: "1*1/111/..."})
+	if err = SaveLibrary(filename, lib); err != nil {
+		t.Fatalf("SaveLibrary() failed: %s", err)
+	}
+	got, err := LoadLibrary(filename)
+	if err != nil || len(got.Entries) != 1 || got.Entries[0].Hash != lib.Entries[0].Hash {
+		t.Errorf("LoadLibrary() wanted %+v got %+v err %v", lib.Entries, got, err)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+
+	Pack.go - curated puzzle packs, either a directory of puzzle files or a single JSON bundle of puzzles
+
+	mike@pocomotech.com
+
+*/
+
+package mspuzzle
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-mines/msengine"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Pack : a titled series of puzzles, played in order
+type Pack struct {
+	Format  msengine.Header `json:"format"`
+	Title   string          `json:"title"`
+	Puzzles []Puzzle        `json:"puzzles"`
+}
+
+// WritePack -- encode the pack as an indented JSON bundle
+func WritePack(w io.Writer, p Pack) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+// ReadPack -- decode a JSON bundle, checking every puzzle's layout. Puzzles inherit the pack's format
+func ReadPack(r io.Reader) (Pack, error) {
+	var retval Pack
+	if err := json.NewDecoder(r).Decode(&retval); err != nil {
+		return Pack{}, err
+	}
+	if err := retval.Format.Check(); err != nil {
+		return Pack{}, fmt.Errorf("pack %q: %s", retval.Title, err)
+	}
+	if len(retval.Puzzles) == 0 {
+		return Pack{}, fmt.Errorf("pack %q has no puzzles", retval.Title)
+	}
+	for i := range retval.Puzzles {
+		retval.Puzzles[i].Format = retval.Format
+		if _, err := retval.Puzzles[i].Board(); err != nil {
+			return Pack{}, fmt.Errorf("pack %q: %s", retval.Title, err)
+		}
+	}
+	return retval, nil
+}
+
+// LoadPack -- read a pack from a JSON bundle, or from a directory holding one puzzle file per puzzle, played in
+// file name order. Packs without a title are named after the file or directory
+func LoadPack(path string) (Pack, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Pack{}, err
+	}
+
+	var retval Pack
+	if info.IsDir() {
+		retval, err = loadPackDir(path)
+	} else {
+		retval, err = loadPackFile(path)
+	}
+	if err != nil {
+		return Pack{}, err
+	}
+	if retval.Title == "" {
+		retval.Title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return retval, nil
+}
+
+// LoadPacks -- every pack in a directory: each subdirectory and JSON file in it is one pack, sorted by name
+func LoadPacks(dir string) ([]Pack, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var retval []Pack
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		p, err := LoadPack(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		retval = append(retval, p)
+	}
+	return retval, nil
+}
+
+// loadPackFile -- read a JSON bundle
+func loadPackFile(filename string) (Pack, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return Pack{}, err
+	}
+	defer f.Close()
+
+	return ReadPack(f)
+}
+
+// loadPackDir -- read the puzzle files in a directory as a pack
+func loadPackDir(dir string) (Pack, error) {
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return Pack{}, err
+	}
+	if len(filenames) == 0 {
+		return Pack{}, fmt.Errorf("no puzzle files in %s", dir)
+	}
+	sort.Strings(filenames)
+
+	retval := Pack{Format: msengine.CurrentHeader()}
+	for _, filename := range filenames {
+		p, err := LoadFile(filename)
+		if err != nil {
+			return Pack{}, fmt.Errorf("%s: %s", filename, err)
+		}
+		retval.Puzzles = append(retval.Puzzles, p)
+	}
+	return retval, nil
+}
@@ -0,0 +1,104 @@
+/*
+	Test functions for puzzle packs and completion stats
+
+	mike@pocomotech.com
+*/
+
+package mspuzzle
+
+import (
+	"go-mines/msboard"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadPack(t *testing.T) {
+	dir := t.TempDir()
+
+	// a directory of puzzle files, played in file name order
+	firsts := filepath.Join(dir, "firsts")
+	os.Mkdir(firsts, 0755)
+	SaveFile(filepath.Join(firsts, "2.json"), Puzzle{Title: "second", Layout: "*1.."})
+	SaveFile(filepath.Join(firsts, "1.json"), Puzzle{Title: "first", Layout: "1*1/111/...", Par: 5})
+
+	// and a bundle
+	bundle := `{"title": "Corners", "puzzles": [{"title": "corner", "layout": "*1./11./...", "par": 2.5}]}`
+	os.WriteFile(filepath.Join(dir, "corners.json"), []byte(bundle), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a pack"), 0644)
+
+	packs, err := LoadPacks(dir)
+	if err != nil || len(packs) != 2 {
+		t.Fatalf("LoadPacks() wanted 2 packs got %+v err %v", packs, err)
+	}
+	if packs[0].Title != "Corners" || packs[0].Puzzles[0].ParTime() != 2500*time.Millisecond {
+		t.Errorf("LoadPacks() bundle wanted Corners with a 2.5s par got %+v", packs[0])
+	}
+	if packs[1].Title != "firsts" || len(packs[1].Puzzles) != 2 || packs[1].Puzzles[0].Title != "first" {
+		t.Errorf("LoadPacks() directory wanted firsts with first then second got %+v", packs[1])
+	}
+
+	if _, err = ReadPack(strings.NewReader(`{"title": "broken", "puzzles": [{"layout": "*2/.."}]}`)); err == nil {
+		t.Errorf("ReadPack() accepted an invalid layout")
+	}
+	if _, err = ReadPack(strings.NewReader(`{"title": "empty"}`)); err == nil {
+		t.Errorf("ReadPack() accepted a pack without puzzles")
+	}
+	if _, err = LoadPack(t.TempDir()); err == nil {
+		t.Errorf("LoadPack() accepted an empty directory")
+	}
+}
+
+func TestStats(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "stats.json")
+	stats, err := LoadStats(filename)
+	if err != nil {
+		t.Fatalf("LoadStats() of a new file failed: %s", err)
+	}
+
+	p := Puzzle{Title: "first", Layout: "1*1/111/...", Par: 5, ParClicks: 1}
+	stats.Record(p, false, 3*time.Second, 2, true)
+	stats.Record(p, true, 9*time.Second, 1, true)
+	stats.Record(p, true, 4*time.Second, 1, true)
+	if err = SaveStats(filename, stats); err != nil {
+		t.Fatalf("SaveStats() failed: %s", err)
+	}
+
+	got, err := LoadStats(filename)
+	want := Completion{Attempts: 3, Solved: 2, Best: 4, Stars: 3}
+	if err != nil || got.Completion(p) != want {
+		t.Errorf("LoadStats() wanted %+v got %+v err %v", want, got.Completion(p), err)
+	}
+
+	// the record follows the position, whatever it's called or however it's turned
+	b, _ := p.Board()
+	turned, _ := b.Transform(msboard.TransformRotate90)
+	renamed := FromBoard("renamed", turned)
+	if got.Completion(renamed) != want {
+		t.Errorf("Completion() of the renamed, turned puzzle wanted %+v got %+v", want, got.Completion(renamed))
+	}
+	pack := Pack{Puzzles: []Puzzle{p, {Layout: "*1.."}}}
+	if solved := got.Solved(pack); solved != 1 {
+		t.Errorf("Solved() wanted 1 of the pack got %d", solved)
+	}
+}
+
+func TestStatsUnrated(t *testing.T) {
+	p := Puzzle{Title: "first", Layout: "1*1/111/...", Par: 5, ParClicks: 1}
+	stats := NewStats()
+	stats.Record(p, true, 4*time.Second, 1, true)
+	stars, c := stats.Record(p, true, time.Second, 1, false)
+
+	// an assisted clear counts, but doesn't set the best time
+	want := Completion{Attempts: 2, Solved: 2, Unrated: 1, Best: 4, Stars: 3}
+	if c != want || stars != 3 {
+		t.Errorf("Record() of an unrated clear wanted %+v and 3 stars got %+v and %d", want, c, stars)
+	}
+
+	stats = NewStats()
+	if _, c = stats.Record(p, true, time.Second, 1, false); c.Best != 0 || c.Stars != 0 || c.Solved != 1 {
+		t.Errorf("Record() of only unrated clears wanted no best time or stars got %+v", c)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+
+	Par.go - par times from bot simulations, and the stars a player earns against them
+
+	mike@pocomotech.com
+
+*/
+
+package mspuzzle
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/msbot"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// ParRuns : bot games simulated to set a puzzle's par
+const ParRuns = 25
+
+// ParSkill : the bot whose median time sets par
+const ParSkill = msbot.SkillExpert
+
+// SimulatePar -- have a bot of the given skill clear the puzzle runs times, each clicking at the skill's pace with
+// random choices from seed plus the run number. Returns the median time and clicks of the runs it won
+func SimulatePar(p Puzzle, skill msbot.Skill, runs int, seed int64) (time.Duration, int, error) {
+	var times []time.Duration
+	var clicks []int
+	for run := 0; run < runs; run++ {
+		b, err := p.Board()
+		if err != nil {
+			return 0, 0, err
+		}
+		rng := rand.New(rand.NewSource(seed + int64(run)))
+		player := skill.Player(rng)
+
+		played, clicked := time.Duration(0), 0
+		for b.Status() == msboard.StatusPlaying {
+			moves := player.Next(b.Snapshot())
+			if len(moves) == 0 {
+				break
+			}
+			for _, m := range moves {
+				if view, _ := b.Snapshot().Cell(m.Location); view.State != msboard.CellHidden {
+					continue
+				}
+				b.Click(m.Location)
+				played += skill.Pace(rng)
+				clicked++
+			}
+		}
+		if b.Status() == msboard.StatusWon {
+			times, clicks = append(times, played), append(clicks, clicked)
+		}
+	}
+	if len(times) == 0 {
+		return 0, 0, fmt.Errorf("the %v bot never cleared puzzle %q", skill, p.Title)
+	}
+
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	sort.Ints(clicks)
+	return times[len(times)/2], clicks[len(clicks)/2], nil
+}
+
+// WithPar -- the puzzle with any par time or par clicks it lacks set from simulations of the ParSkill bot, the
+// time rounded up to a whole second
+func (p Puzzle) WithPar() (Puzzle, error) {
+	if p.Par > 0 && p.ParClicks > 0 {
+		return p, nil
+	}
+	par, clicks, err := SimulatePar(p, ParSkill, ParRuns, 1)
+	if err != nil {
+		return p, err
+	}
+	if p.Par <= 0 {
+		p.Par = math.Ceil(par.Seconds())
+	}
+	if p.ParClicks <= 0 {
+		p.ParClicks = clicks
+	}
+	return p, nil
+}
+
+// Stars -- 1 to 3 stars for clearing a puzzle: one for clearing it, a second for taking no more than twice par and
+// a third for beating par in no more than a quarter more moves than par clicks. Puzzles without a par only earn
+// the first, and those without par clicks aren't judged on moves. 0 if the puzzle wasn't cleared
+func Stars(p Puzzle, won bool, played time.Duration, moves int) int {
+	if !won {
+		return 0
+	}
+	if p.Par <= 0 {
+		return 1
+	}
+
+	retval := 1
+	if played <= 2*p.ParTime() {
+		retval++
+	}
+	if played <= p.ParTime() && (p.ParClicks <= 0 || 4*moves <= 5*p.ParClicks) {
+		retval++
+	}
+	return retval
+}
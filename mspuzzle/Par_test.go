@@ -0,0 +1,60 @@
+/*
+	Test functions for par times and stars
+
+	mike@pocomotech.com
+*/
+
+package mspuzzle
+
+import (
+	"go-mines/msbot"
+	"testing"
+	"time"
+)
+
+func TestSimulatePar(t *testing.T) {
+	p := Puzzle{Title: "corner", Layout: "1*1/111/..."}
+	par, clicks, err := SimulatePar(p, msbot.SkillExpert, 5, 1)
+	// one click clears the bottom row, at half to one and a half times the expert's second a click
+	if err != nil || clicks != 1 || par < 500*time.Millisecond || par > 1500*time.Millisecond {
+		t.Errorf("SimulatePar() wanted 1 click in about a second got %d in %v err %v", clicks, par, err)
+	}
+
+	withPar, err := p.WithPar()
+	if err != nil || withPar.Par != 1 && withPar.Par != 2 || withPar.ParClicks != 1 {
+		t.Errorf("WithPar() wanted a 1 or 2 second par and 1 click got %+v err %v", withPar, err)
+	}
+	curated := Puzzle{Layout: p.Layout, Par: 30, ParClicks: 4}
+	if got, _ := curated.WithPar(); got.Par != 30 || got.ParClicks != 4 {
+		t.Errorf("WithPar() changed a puzzle that already had a par: %+v", got)
+	}
+
+	if _, _, err = SimulatePar(p, msbot.SkillExpert, 0, 1); err == nil {
+		t.Errorf("SimulatePar() without a won game wanted an error")
+	}
+}
+
+func TestStars(t *testing.T) {
+	p := Puzzle{Par: 10, ParClicks: 8}
+	var cases = []struct {
+		p      Puzzle
+		won    bool
+		played time.Duration
+		moves  int
+		want   int
+	}{
+		{p, false, 5 * time.Second, 8, 0},
+		{p, true, 5 * time.Second, 10, 3},
+		{p, true, 5 * time.Second, 11, 2}, // quick, but too many moves
+		{p, true, 20 * time.Second, 8, 2},
+		{p, true, 21 * time.Second, 8, 1},
+		{Puzzle{Par: 10}, true, 10 * time.Second, 50, 3},
+		{Puzzle{}, true, time.Second, 1, 1},
+	}
+	for _, testcase := range cases {
+		if got := Stars(testcase.p, testcase.won, testcase.played, testcase.moves); got != testcase.want {
+			t.Errorf("Stars(%+v, %v, %v, %d) wanted %d got %d", testcase.p, testcase.won, testcase.played,
+				testcase.moves, testcase.want, got)
+		}
+	}
+}
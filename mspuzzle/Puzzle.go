@@ -0,0 +1,97 @@
+/*
+
+	Puzzle.go - saved positions for puzzle and tutorial modes
+
+	mike@pocomotech.com
+
+*/
+
+// Package mspuzzle -- puzzle files: titled board positions stored as JSON around a layout string
+package mspuzzle
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"io"
+	"os"
+	"time"
+)
+
+// Puzzle : a named position, see msboard.ParseLayout for the layout string format
+type Puzzle struct {
+	Format    msengine.Header `json:"format"`
+	Title     string          `json:"title"`
+	Layout    string          `json:"layout"`
+	Par       float64         `json:"par,omitempty"`       // target time to clear the puzzle in seconds, 0 for none
+	ParClicks int             `json:"parClicks,omitempty"` // moves the par bot needed, 0 for unknown
+}
+
+// FromBoard -- capture a board position as a puzzle
+func FromBoard(title string, b *msboard.Board) Puzzle {
+	return Puzzle{Format: msengine.HeaderFor(b), Title: title, Layout: b.Layout()}
+}
+
+// ParTime -- the par time as a duration, 0 for none
+func (p Puzzle) ParTime() time.Duration {
+	return time.Duration(p.Par * float64(time.Second))
+}
+
+// Board -- build a playable board from the puzzle's layout, on the topology and by the rules its format names
+func (p Puzzle) Board() (*msboard.Board, error) {
+	b, err := msboard.ParseTopologyLayout(p.Layout, p.Format.Topology)
+	if err != nil {
+		return nil, fmt.Errorf("puzzle %q: %s", p.Title, err)
+	}
+	if err := p.Format.Configure(b); err != nil {
+		return nil, fmt.Errorf("puzzle %q: %s", p.Title, err)
+	}
+	return b, nil
+}
+
+// Write -- encode the puzzle as indented JSON
+func Write(w io.Writer, p Puzzle) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+// Read -- decode a puzzle, checking that its layout is valid
+func Read(r io.Reader) (Puzzle, error) {
+	var retval Puzzle
+	if err := json.NewDecoder(r).Decode(&retval); err != nil {
+		return Puzzle{}, err
+	}
+	if err := retval.Format.Check(); err != nil {
+		return Puzzle{}, fmt.Errorf("puzzle %q: %s", retval.Title, err)
+	}
+	if _, err := retval.Board(); err != nil {
+		return Puzzle{}, err
+	}
+	return retval, nil
+}
+
+// SaveFile -- write the puzzle to a file, replacing any existing one
+func SaveFile(filename string, p Puzzle) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err = Write(f, p); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadFile -- read a puzzle from a file
+func LoadFile(filename string) (Puzzle, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return Puzzle{}, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
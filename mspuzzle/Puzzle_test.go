@@ -0,0 +1,96 @@
+/*
+	Test functions for puzzle files
+
+	mike@pocomotech.com
+*/
+
+package mspuzzle
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPuzzleReadWrite(t *testing.T) {
+	p := Puzzle{Title: "corner 1-1", Layout: "*1./11./..."}
+
+	buf := bytes.NewBufferString("")
+	if err := Write(buf, p); err != nil {
+		t.Fatalf("Write() failed: %s", err)
+	}
+	got, err := Read(buf)
+	if err != nil || !reflect.DeepEqual(got, p) {
+		t.Errorf("Read() wanted %+v got %+v err %v", p, got, err)
+	}
+
+	if _, err = Read(strings.NewReader(`{"title": "broken", "layout": "*2/.."}`)); err == nil {
+		t.Errorf("Read() accepted an invalid layout")
+	}
+	if _, err = Read(strings.NewReader(`{"format": {"engine": "9.0.0"}, "title": "future", "layout": "..*"}`)); err == nil {
+		t.Errorf("Read() accepted a puzzle from an incompatible engine")
+	}
+}
+
+func TestPuzzleFiles(t *testing.T) {
+	p := Puzzle{Title: "file", Layout: "..*/..."}
+	filename := filepath.Join(t.TempDir(), "puzzle.json")
+
+	if err := SaveFile(filename, p); err != nil {
+		t.Fatalf("SaveFile() failed: %s", err)
+	}
+	got, err := LoadFile(filename)
+	if err != nil || !reflect.DeepEqual(got, p) {
+		t.Errorf("LoadFile() wanted %+v got %+v err %v", p, got, err)
+	}
+
+	b, _ := got.Board()
+	if b.MineCount() != 1 {
+		t.Errorf("Puzzle board wanted 1 mine got %d", b.MineCount())
+	}
+}
+
+func TestPuzzleTopology(t *testing.T) {
+	// a single row whose ends touch
+	ring := func(rows, cols int, l msboard.Location) []msboard.Location {
+		return []msboard.Location{
+			msboard.NewLocation(l.Row(), (l.Col()+cols-1)%cols),
+			msboard.NewLocation(l.Row(), (l.Col()+1)%cols),
+		}
+	}
+	if err := msboard.RegisterTopology("ring", ring, nil); err != nil {
+		t.Fatalf("RegisterTopology failed: %s", err)
+	}
+
+	b, err := msboard.ParseTopologyLayout("*1..1", "ring")
+	if err != nil {
+		t.Fatalf("ParseTopologyLayout failed: %s", err)
+	}
+	p := FromBoard("ring", b)
+	if p.Format.Topology != "ring" {
+		t.Errorf("FromBoard wanted the ring topology recorded got %q", p.Format.Topology)
+	}
+
+	buf := bytes.NewBufferString("")
+	if err = Write(buf, p); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	got, err := Read(buf)
+	if err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if board, err := got.Board(); err != nil || board.Topology() != "ring" {
+		t.Errorf("read puzzle wanted a ring board got %v", err)
+	}
+
+	p.Format.Topology = "hex"
+	if err = Write(buf, p); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	if _, err = Read(buf); err == nil {
+		t.Errorf("Read of a puzzle on an unknown topology should fail")
+	}
+}
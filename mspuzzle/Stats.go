@@ -0,0 +1,125 @@
+/*
+
+	Stats.go - which puzzles a player has cleared and how quickly, kept across sessions
+
+	mike@pocomotech.com
+
+*/
+
+package mspuzzle
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// Completion : a player's record on one puzzle
+type Completion struct {
+	Attempts int     `json:"attempts"`
+	Solved   int     `json:"solved"`
+	Unrated  int     `json:"unrated,omitempty"` // attempts with assistance or variant rules, see Record
+	Best     float64 `json:"best,omitempty"`    // fastest rated clear in seconds, 0 if never cleared rated
+	Stars    int     `json:"stars,omitempty"`   // most stars earned in a rated attempt, see Stars
+}
+
+// BestTime -- the fastest clear as a duration, 0 if never cleared
+func (c Completion) BestTime() time.Duration {
+	return time.Duration(c.Best * float64(time.Second))
+}
+
+// Stats : completion records keyed by the puzzle's canonical hash, so they follow a puzzle renamed or moved to
+// another pack
+type Stats struct {
+	Puzzles map[string]Completion `json:"puzzles"`
+}
+
+// NewStats -- create empty stats
+func NewStats() *Stats {
+	return &Stats{Puzzles: make(map[string]Completion)}
+}
+
+// statsKey -- the canonical hash of the puzzle's position, empty if its layout is invalid
+func statsKey(p Puzzle) string {
+	b, err := p.Board()
+	if err != nil {
+		return ""
+	}
+	return b.CanonicalHash()
+}
+
+// Record -- count an attempt at a puzzle, and if it was cleared the time taken and the stars earned with that
+// many moves. Unrated attempts count towards attempts and clears but never set the best time or stars, so assisted
+// play can't flatter them. Returns the stars earned this time along with the updated record
+func (s *Stats) Record(p Puzzle, solved bool, played time.Duration, moves int, rated bool) (int, Completion) {
+	key := statsKey(p)
+	c := s.Puzzles[key]
+	c.Attempts++
+	if !rated {
+		c.Unrated++
+	}
+	stars := Stars(p, solved, played, moves)
+	if solved {
+		c.Solved++
+		if seconds := played.Seconds(); rated && (c.Best == 0 || seconds < c.Best) {
+			c.Best = seconds
+		}
+	}
+	if rated && stars > c.Stars {
+		c.Stars = stars
+	}
+	s.Puzzles[key] = c
+	return stars, c
+}
+
+// Completion -- the record for a puzzle, the zero Completion if it was never attempted
+func (s *Stats) Completion(p Puzzle) Completion {
+	return s.Puzzles[statsKey(p)]
+}
+
+// Solved -- number of puzzles in the pack cleared at least once
+func (s *Stats) Solved(pack Pack) int {
+	retval := 0
+	for _, p := range pack.Puzzles {
+		if s.Completion(p).Solved > 0 {
+			retval++
+		}
+	}
+	return retval
+}
+
+// SaveStats -- write stats to a file, replacing any existing one
+func SaveStats(filename string, s *Stats) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(s); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadStats -- read stats from a file; a file that doesn't exist yet is empty stats
+func LoadStats(filename string) (*Stats, error) {
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return NewStats(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	retval := NewStats()
+	if err := json.NewDecoder(f).Decode(retval); err != nil {
+		return nil, err
+	}
+	if nil == retval.Puzzles {
+		retval.Puzzles = make(map[string]Completion)
+	}
+	return retval, nil
+}
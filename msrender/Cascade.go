@@ -0,0 +1,54 @@
+/*
+
+	Cascade.go - flood reveals drawn wave by wave, so a big cascade can be followed as it spreads
+
+	mike@pocomotech.com
+
+*/
+
+package msrender
+
+import (
+	"go-mines/msboard"
+	"io"
+	"time"
+)
+
+// Cascade -- draw the cascade that led to s a wave at a time, waiting delay between frames through sleep: first
+// with only the clicked cell revealed, then each wave added in turn. The final frame, with every wave revealed, is
+// left to the caller's next render. waves is as msboard.CascadeWaves gives it; a single wave draws nothing. out is
+// flushed after each frame if it can be
+func Cascade(out io.Writer, r Renderer, s msboard.Snapshot, waves [][]msboard.Location, delay time.Duration,
+	sleep func(time.Duration)) error {
+	if len(waves) < 2 {
+		return nil
+	}
+
+	// start from the position with every wave but the first hidden again, and reveal a wave per frame
+	frame := s
+	frame.Cells = make([][]msboard.CellView, len(s.Cells))
+	for row := range s.Cells {
+		frame.Cells[row] = append([]msboard.CellView(nil), s.Cells[row]...)
+	}
+	for _, wave := range waves[1:] {
+		for _, l := range wave {
+			frame.Cells[l.Row()][l.Col()] = msboard.CellView{State: msboard.CellHidden}
+		}
+	}
+
+	for _, wave := range waves[1:] {
+		if err := r.Render(out, frame); err != nil {
+			return err
+		}
+		if flusher, ok := out.(interface{ Flush() error }); ok {
+			if err := flusher.Flush(); err != nil {
+				return err
+			}
+		}
+		sleep(delay)
+		for _, l := range wave {
+			frame.Cells[l.Row()][l.Col()] = s.Cells[l.Row()][l.Col()]
+		}
+	}
+	return nil
+}
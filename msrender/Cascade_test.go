@@ -0,0 +1,56 @@
+/*
+	Test functions for wave by wave cascades
+
+	mike@pocomotech.com
+*/
+
+package msrender
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCascade(t *testing.T) {
+	b, err := msboard.ParseLayout("..../..../..../...*")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+	revealed := b.Click(msboard.NewLocation(0, 0))
+	s := b.Snapshot()
+	waves := msboard.CascadeWaves(s, revealed)
+
+	var slept []time.Duration
+	out := bytes.NewBufferString("")
+	if err := Cascade(out, FrameRenderer{}, s, waves, 30*time.Millisecond, func(d time.Duration) {
+		slept = append(slept, d)
+	}); err != nil {
+		t.Fatalf("Cascade failed: %s", err)
+	}
+
+	// one frame per wave but the last, each revealing one more ring around the click
+	frames := strings.Split(strings.TrimSuffix(out.String(), "\n"), "    A  B  C  D\n")[1:]
+	want := []string{
+		" 1  _  .  .  .\n 2  .  .  .  .\n 3  .  .  .  .\n 4  .  .  .  .",
+		" 1  _  _  .  .\n 2  _  _  .  .\n 3  .  .  .  .\n 4  .  .  .  .",
+		" 1  _  _  _  .\n 2  _  _  _  .\n 3  _  _  1  .\n 4  .  .  .  .",
+	}
+	if len(frames) != len(want) || len(slept) != len(want) || slept[0] != 30*time.Millisecond {
+		t.Fatalf("Cascade wanted %d frames each followed by a 30ms wait got %d frames and waits %v:\n%s",
+			len(want), len(frames), slept, out.String())
+	}
+	for i := range want {
+		if strings.TrimSpace(frames[i]) != strings.TrimSpace(want[i]) {
+			t.Errorf("Cascade frame %d wanted\n%s\ngot\n%s", i, want[i], frames[i])
+		}
+	}
+
+	out.Reset()
+	Cascade(out, FrameRenderer{}, s, waves[:1], time.Second, func(time.Duration) { t.Errorf("single wave slept") })
+	if out.Len() != 0 {
+		t.Errorf("Cascade of a single wave drew %q", out.String())
+	}
+}
@@ -0,0 +1,57 @@
+/*
+
+	Emoji.go - a theme of emoji glyphs, for chat adapters and terminals with color emoji fonts
+
+		⬜⬜🚩
+		🟦1️⃣2️⃣
+
+	Emoji take two terminal columns, so EmojiTheme is a WideTheme and the grid is spaced for it. Terminals can't
+	report whether they draw emoji, so the theme is only chosen when asked for, see Overrides.Emoji
+
+	mike@pocomotech.com
+
+*/
+
+package msrender
+
+import (
+	"go-mines/msboard"
+)
+
+// EmojiTheme : emoji glyphs, two terminal columns each
+type EmojiTheme struct{}
+
+// compile time check that the grid is spaced for emoji
+var _ WideTheme = EmojiTheme{}
+
+// keycaps : the emoji for scores 0 to 10; 0 is drawn as a blank tile rather than a keycap
+var keycaps = [...]string{"🟦", "1️⃣", "2️⃣", "3️⃣", "4️⃣", "5️⃣", "6️⃣", "7️⃣", "8️⃣", "9️⃣", "🔟"}
+
+// Cell -- emoji for the cell. Negative scores take the keycap of their size, their sign drawn before it
+func (EmojiTheme) Cell(v msboard.CellView) string {
+	switch v.State {
+	case msboard.CellHidden:
+		if v.OutOfRange {
+			return "⬛"
+		}
+		return "⬜"
+	case msboard.CellFlagged:
+		return "🚩"
+	case msboard.CellMine:
+		return "💣"
+	}
+	score := v.Score
+	if score < 0 {
+		score = -score
+	}
+	if score < len(keycaps) {
+		return keycaps[score]
+	}
+	// more neighbors than keycaps, on boards of other shapes
+	return "#️⃣"
+}
+
+// GlyphWidth -- 2, emoji are double width
+func (EmojiTheme) GlyphWidth() int {
+	return 2
+}
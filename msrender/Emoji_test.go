@@ -0,0 +1,63 @@
+/*
+	Test functions for the emoji theme
+
+	mike@pocomotech.com
+*/
+
+package msrender
+
+import (
+	"bytes"
+	"fmt"
+	"go-mines/msboard"
+	"strings"
+	"testing"
+)
+
+func TestEmojiTheme(t *testing.T) {
+	theme := (Capabilities{Emoji: true, Color: ColorTrue}).Theme()
+	for _, c := range []struct {
+		v    msboard.CellView
+		want string
+	}{
+		{msboard.CellView{State: msboard.CellHidden}, "⬜"},
+		{msboard.CellView{State: msboard.CellHidden, OutOfRange: true}, "⬛"},
+		{msboard.CellView{State: msboard.CellFlagged}, "🚩"},
+		{msboard.CellView{State: msboard.CellMine}, "💣"},
+		{msboard.CellView{State: msboard.CellRevealed}, "🟦"},
+		{msboard.CellView{State: msboard.CellRevealed, Score: 3}, "3️⃣"},
+		{msboard.CellView{State: msboard.CellRevealed, Score: -2}, "2️⃣"},
+		{msboard.CellView{State: msboard.CellRevealed, Score: 12}, "#️⃣"},
+	} {
+		if got := theme.Cell(c.v); got != c.want {
+			t.Errorf("emoji for %+v wanted %q got %q", c.v, c.want, got)
+		}
+	}
+	if glyphWidth(theme) != 2 || glyphWidth(ColorTheme{UnicodeTheme{}, Color16}) != 1 {
+		t.Errorf("glyph widths wanted 2 for emoji and 1 for colored unicode")
+	}
+}
+
+func TestEmojiRender(t *testing.T) {
+	b, _ := msboard.ParseLayout("*../.../...")
+	b.Click(msboard.NewLocation(0, 1))
+	b.ToggleFlag(msboard.NewLocation(0, 0))
+
+	buf := bytes.NewBufferString("")
+	FrameRenderer{Theme: EmojiTheme{}}.Render(buf, b.Snapshot())
+	want := "    A  B  C\n 1  🚩 1️⃣ ⬜\n 2  ⬜ ⬜ ⬜\n 3  ⬜ ⬜ ⬜\n"
+	if buf.String() != want {
+		t.Errorf("emoji board wanted\n%s\ngot\n%s", want, buf.String())
+	}
+
+	// partial redraws put a changed emoji back where the full frame drew it
+	r := NewPartialRenderer(EmojiTheme{}, Options{})
+	r.Render(bytes.NewBufferString(""), b.Snapshot())
+	b.Click(msboard.NewLocation(2, 2))
+	buf.Reset()
+	r.Render(buf, b.Snapshot())
+	line, column := newFrame(b.Snapshot(), Options{}).fitting(EmojiTheme{}).cellPosition(msboard.NewLocation(2, 2))
+	if want := fmt.Sprintf(ansiMoveFmt, line, column) + "🟦"; !strings.Contains(buf.String(), want) {
+		t.Errorf("partial emoji frame wanted %q in %q", want, buf.String())
+	}
+}
@@ -0,0 +1,105 @@
+/*
+
+	HTML.go - a position as a standalone HTML table, styled inline in the classic colors, for pages that show a
+	board without a stylesheet of their own, such as blog posts
+
+	Hidden cells are raised gray tiles, revealed ones flat and lighter, with the scores colored as the terminal
+	themes color them. The table carries its own styles, so it can be pasted into any page.
+
+	mike@pocomotech.com
+
+*/
+
+package msrender
+
+import (
+	"bufio"
+	"fmt"
+	"go-mines/msboard"
+	"html"
+	"io"
+)
+
+// Inline styles of the table and its cells
+const (
+	htmlTableStyle = "border-collapse:collapse;font:bold 14px monospace;text-align:center"
+	htmlLabelStyle = "width:20px;height:20px;padding:0;color:#808080;font-weight:normal"
+	htmlCellStyle  = "width:20px;height:20px;padding:0;border:1px solid #808080"
+	htmlHidden     = "background:#c0c0c0;border-style:outset"
+	htmlOutOfRange = "background:#808080;border-style:outset"
+	htmlRevealed   = "background:#e0e0e0"
+	htmlMine       = "background:#ff0000"
+)
+
+// HTMLRenderer : draws a snapshot as an HTML table, the whole board with its row and column labels
+type HTMLRenderer struct {
+	Coordinates msboard.LocationCodec // labels the rows and columns, nil for the default of the board's topology
+	Transform   msboard.Transform     // turns or flips the board as drawn
+}
+
+// compile time check that HTML tables can be drawn wherever a renderer is wanted
+var _ Renderer = HTMLRenderer{}
+
+// RenderHTML -- draw a position as an HTML table, labelled in the default coordinates of its topology
+func RenderHTML(out io.Writer, s msboard.Snapshot) error {
+	return HTMLRenderer{}.Render(out, s)
+}
+
+// Render -- draw the board as a table, one line of HTML per row
+func (r HTMLRenderer) Render(out io.Writer, s msboard.Snapshot) error {
+	w := bufio.NewWriter(out)
+	s = s.Transform(r.Transform)
+	labels := coordinates(Options{Coordinates: r.Coordinates}, s)
+	rows := len(s.Cells)
+
+	fmt.Fprintf(w, "<table style=\"%s\">\n<tr><th></th>", htmlTableStyle)
+	for col := 0; col < s.Cols; col++ {
+		fmt.Fprintf(w, "<th style=\"%s\">%s</th>", htmlLabelStyle, html.EscapeString(labels.ColLabel(col, s.Cols)))
+	}
+	fmt.Fprintln(w, "</tr>")
+	for row := 0; row < rows; row++ {
+		fmt.Fprintf(w, "<tr><th style=\"%s\">%s</th>", htmlLabelStyle, html.EscapeString(labels.RowLabel(row, rows)))
+		for _, v := range s.Cells[row] {
+			style, text := htmlCell(v)
+			fmt.Fprintf(w, "<td style=\"%s;%s\">%s</td>", htmlCellStyle, style, text)
+		}
+		fmt.Fprintln(w, "</tr>")
+	}
+	fmt.Fprintln(w, "</table>")
+
+	return w.Flush()
+}
+
+// htmlCell -- the style and text of a cell
+func htmlCell(v msboard.CellView) (style, text string) {
+	switch v.State {
+	case msboard.CellHidden:
+		if v.OutOfRange {
+			return htmlOutOfRange, ""
+		}
+		return htmlHidden, ""
+	case msboard.CellFlagged:
+		return htmlHidden + ";" + htmlColor(0), "⚑"
+	case msboard.CellMine:
+		return htmlMine, "✹"
+	}
+
+	// negative scores take the color of their size
+	score := v.Score
+	if score < 0 {
+		score = -score
+	}
+	switch {
+	case score == 0:
+		return htmlRevealed, ""
+	case score < len(scoreColors):
+		return htmlRevealed + ";" + htmlColor(score), fmt.Sprint(v.Score)
+	}
+	return htmlRevealed, fmt.Sprint(v.Score)
+}
+
+// htmlColor -- CSS text color of a slot of the classic colors
+func htmlColor(slot int) string {
+	c := scoreColors[slot]
+	return fmt.Sprintf("color:#%02x%02x%02x", c.r, c.g, c.b)
+}
@@ -0,0 +1,56 @@
+/*
+	Test functions for the HTML renderer
+
+	mike@pocomotech.com
+*/
+
+package msrender
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"strings"
+	"testing"
+)
+
+func TestRenderHTML(t *testing.T) {
+	b, _ := msboard.ParseLayout("*../.../...")
+	b.Click(msboard.NewLocation(0, 1))
+	b.ToggleFlag(msboard.NewLocation(0, 0))
+
+	buf := bytes.NewBufferString("")
+	if err := RenderHTML(buf, b.Snapshot()); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 6 || !strings.HasPrefix(lines[0], "<table ") || lines[5] != "</table>" {
+		t.Fatalf("wanted a table of a header and 3 rows got\n%s", buf.String())
+	}
+	if !strings.Contains(lines[1], ">A</th>") || !strings.Contains(lines[1], ">C</th>") {
+		t.Errorf("header wanted column labels A to C got %s", lines[1])
+	}
+
+	want := "<tr><th style=\"" + htmlLabelStyle + "\">1</th>" +
+		"<td style=\"" + htmlCellStyle + ";" + htmlHidden + ";color:#ff0000\">⚑</td>" +
+		"<td style=\"" + htmlCellStyle + ";" + htmlRevealed + ";color:#0000ff\">1</td>" +
+		"<td style=\"" + htmlCellStyle + ";" + htmlHidden + "\"></td></tr>"
+	if lines[2] != want {
+		t.Errorf("first row wanted\n%s\ngot\n%s", want, lines[2])
+	}
+
+	// labels are escaped, whatever the coordinates
+	buf.Reset()
+	HTMLRenderer{Coordinates: lessThanCodec{}}.Render(buf, b.Snapshot())
+	if strings.Contains(buf.String(), "<1") || !strings.Contains(buf.String(), "&lt;1") {
+		t.Errorf("labels weren't escaped:\n%s", buf.String())
+	}
+}
+
+// lessThanCodec : labels that need escaping in HTML
+type lessThanCodec struct {
+	msboard.LetterNumberCodec
+}
+
+func (lessThanCodec) RowLabel(row, rows int) string {
+	return "<" + msboard.LetterNumberCodec{}.RowLabel(row, rows)
+}
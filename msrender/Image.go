@@ -0,0 +1,193 @@
+/*
+
+	Image.go - a position drawn as a picture, for animations and anywhere else text won't do
+
+	Cells are drawn in the classic style: hidden cells are raised gray tiles, revealed ones flat, scores in the
+	colors the terminal themes use, flags red on a black pole and mines black, on red where one went off. Scores are
+	drawn from a tiny built in bitmap font scaled to the cell, so no font files are needed. Images are paletted, so
+	they can be frames of a GIF as they are.
+
+	mike@pocomotech.com
+
+*/
+
+package msrender
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"image"
+	"image/color"
+)
+
+// MinImageCell : smallest cell size in pixels that leaves room for a score
+const MinImageCell = 8
+
+// Palette entries Image draws with; the score colors follow, one per score from 1 to 8
+const (
+	paletteRevealed  = iota // background of revealed cells
+	paletteHidden           // face of hidden cells
+	paletteShadow           // grid lines, the lower edges of hidden cells and cells lost in the fog
+	paletteHighlight        // upper edges of hidden cells
+	paletteBlack            // mines, flag poles and scores past 8
+	paletteRed              // flags and the background of an exploded mine
+	paletteScores
+)
+
+// Palette : the colors of the images Image draws
+var Palette = imagePalette()
+
+// imagePalette -- the fixed colors followed by the score colors
+func imagePalette() color.Palette {
+	retval := color.Palette{
+		color.RGBA{0xe0, 0xe0, 0xe0, 0xff},
+		color.RGBA{0xc0, 0xc0, 0xc0, 0xff},
+		color.RGBA{0x80, 0x80, 0x80, 0xff},
+		color.RGBA{0xff, 0xff, 0xff, 0xff},
+		color.RGBA{0x00, 0x00, 0x00, 0xff},
+		color.RGBA{0xff, 0x00, 0x00, 0xff},
+	}
+	for _, c := range scoreColors[1:] {
+		retval = append(retval, color.RGBA{uint8(c.r), uint8(c.g), uint8(c.b), 0xff})
+	}
+	return retval
+}
+
+// glyphs : 3 by 5 pixel bitmaps of the characters a score can need, a row to a byte with the left pixel the
+// highest of three bits
+var glyphs = map[rune][5]uint8{
+	'0': {7, 5, 5, 5, 7},
+	'1': {2, 6, 2, 2, 7},
+	'2': {7, 1, 7, 4, 7},
+	'3': {7, 1, 3, 1, 7},
+	'4': {5, 5, 7, 1, 1},
+	'5': {7, 4, 7, 1, 7},
+	'6': {7, 4, 7, 5, 7},
+	'7': {7, 1, 2, 2, 2},
+	'8': {7, 5, 7, 5, 7},
+	'9': {7, 5, 7, 1, 7},
+	'-': {0, 0, 7, 0, 0},
+}
+
+// Image -- draw a snapshot with square cells of cellSize pixels, at least MinImageCell
+func Image(s msboard.Snapshot, cellSize int) (*image.Paletted, error) {
+	if cellSize < MinImageCell {
+		return nil, fmt.Errorf("cells must be at least %d pixels, got %d", MinImageCell, cellSize)
+	}
+
+	img := image.NewPaletted(image.Rect(0, 0, s.Cols*cellSize, len(s.Cells)*cellSize), Palette)
+	for row := range s.Cells {
+		for col, v := range s.Cells[row] {
+			drawCell(img, image.Rect(col*cellSize, row*cellSize, (col+1)*cellSize, (row+1)*cellSize), v)
+		}
+	}
+	return img, nil
+}
+
+// drawCell -- draw a cell into its square of the image
+func drawCell(img *image.Paletted, r image.Rectangle, v msboard.CellView) {
+	size := r.Dx()
+	edge := maxInt(1, size/10)
+	switch v.State {
+	case msboard.CellHidden, msboard.CellFlagged:
+		if v.OutOfRange {
+			fill(img, r, paletteShadow)
+			break
+		}
+		// a raised tile: light along the top and left, dark along the bottom and right
+		fill(img, r, paletteHighlight)
+		fill(img, image.Rect(r.Min.X+edge, r.Min.Y+edge, r.Max.X, r.Max.Y), paletteShadow)
+		fill(img, image.Rect(r.Min.X+edge, r.Min.Y+edge, r.Max.X-edge, r.Max.Y-edge), paletteHidden)
+		if v.State == msboard.CellFlagged {
+			drawFlag(img, r)
+		}
+		return
+	case msboard.CellMine:
+		fill(img, r, paletteRed)
+		drawMine(img, r)
+	default:
+		fill(img, r, paletteRevealed)
+		if v.Score != 0 {
+			drawScore(img, r, v.Score)
+		}
+	}
+	// grid lines along the bottom and right of flat cells
+	fill(img, image.Rect(r.Min.X, r.Max.Y-1, r.Max.X, r.Max.Y), paletteShadow)
+	fill(img, image.Rect(r.Max.X-1, r.Min.Y, r.Max.X, r.Max.Y), paletteShadow)
+}
+
+// drawFlag -- a red pennant on a black pole, over the middle of the cell
+func drawFlag(img *image.Paletted, r image.Rectangle) {
+	size := r.Dx()
+	pole := r.Min.X + size/2
+	top, bottom := r.Min.Y+size/5, r.Max.Y-size/5
+	fill(img, image.Rect(pole, top, pole+maxInt(1, size/12), bottom), paletteBlack)
+	// the pennant narrows to a point halfway down the pole
+	height := (bottom - top) / 2
+	for y := 0; y < height; y++ {
+		width := (size * 3 / 10) * (height - absInt(2*y-height)) / height
+		fill(img, image.Rect(pole-width, top+y, pole, top+y+1), paletteRed)
+	}
+}
+
+// drawMine -- a black disc in the middle of the cell
+func drawMine(img *image.Paletted, r image.Rectangle) {
+	size := r.Dx()
+	radius := size * 3 / 10
+	cx, cy := r.Min.X+size/2, r.Min.Y+size/2
+	for y := cy - radius; y <= cy+radius; y++ {
+		for x := cx - radius; x <= cx+radius; x++ {
+			if (x-cx)*(x-cx)+(y-cy)*(y-cy) <= radius*radius {
+				img.SetColorIndex(x, y, paletteBlack)
+			}
+		}
+	}
+}
+
+// drawScore -- a score centered in the cell, in its color
+func drawScore(img *image.Paletted, r image.Rectangle, score int) {
+	text := fmt.Sprint(score)
+	if score < 0 {
+		score = -score
+	}
+	index := uint8(paletteBlack)
+	if score <= len(scoreColors)-1 {
+		index = uint8(paletteScores + score - 1)
+	}
+
+	// glyphs are 3 pixels wide with a pixel between them, all scaled to the cell
+	size := r.Dx()
+	scale := maxInt(1, size/8)
+	width, height := (4*len(text)-1)*scale, 5*scale
+	x0, y0 := r.Min.X+(size-width)/2, r.Min.Y+(size-height)/2
+	for i, c := range text {
+		bits := glyphs[c]
+		for row := 0; row < 5; row++ {
+			for col := 0; col < 3; col++ {
+				if bits[row]&(4>>col) == 0 {
+					continue
+				}
+				x, y := x0+(4*i+col)*scale, y0+row*scale
+				fill(img, image.Rect(x, y, x+scale, y+scale), index)
+			}
+		}
+	}
+}
+
+// fill -- paint a rectangle of the image one palette color
+func fill(img *image.Paletted, r image.Rectangle, index uint8) {
+	r = r.Intersect(img.Rect)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetColorIndex(x, y, index)
+		}
+	}
+}
+
+// absInt -- the size of a number
+func absInt(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
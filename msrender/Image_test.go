@@ -0,0 +1,57 @@
+/*
+	Test functions for drawing positions as images
+
+	mike@pocomotech.com
+*/
+
+package msrender
+
+import (
+	"go-mines/msboard"
+	"testing"
+)
+
+func TestImage(t *testing.T) {
+	b, _ := msboard.ParseLayout("*../...")
+	b.Click(msboard.NewLocation(0, 1))
+	b.ToggleFlag(msboard.NewLocation(0, 0))
+
+	if _, err := Image(b.Snapshot(), MinImageCell-1); err == nil {
+		t.Errorf("Image with %d pixel cells wanted an error", MinImageCell-1)
+	}
+	img, err := Image(b.Snapshot(), 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Rect.Dx() != 48 || img.Rect.Dy() != 32 {
+		t.Fatalf("image of a 2x3 board wanted 48x32 got %v", img.Rect)
+	}
+
+	// counts of each color in a cell's square
+	colors := func(row, col int) map[uint8]int {
+		retval := map[uint8]int{}
+		for y := row * 16; y < (row+1)*16; y++ {
+			for x := col * 16; x < (col+1)*16; x++ {
+				retval[img.ColorIndexAt(x, y)]++
+			}
+		}
+		return retval
+	}
+	if flag := colors(0, 0); flag[paletteRed] == 0 || flag[paletteBlack] == 0 || flag[paletteHidden] == 0 {
+		t.Errorf("flag wanted red, black and hidden gray got %v", flag)
+	}
+	if one := colors(0, 1); one[paletteScores] == 0 || one[paletteRevealed] == 0 {
+		t.Errorf("score 1 wanted its color on the revealed background got %v", one)
+	}
+	if hidden := colors(0, 2); hidden[paletteHidden] == 0 || hidden[paletteHighlight] == 0 || hidden[paletteRed] != 0 {
+		t.Errorf("hidden cell wanted a raised gray tile got %v", hidden)
+	}
+
+	b.Click(msboard.NewLocation(0, 0))
+	b.ToggleFlag(msboard.NewLocation(0, 0))
+	b.Click(msboard.NewLocation(0, 0))
+	img, _ = Image(b.Snapshot(), 16)
+	if mine := colors(0, 0); mine[paletteRed] == 0 || mine[paletteBlack] == 0 {
+		t.Errorf("exploded mine wanted black on red got %v", mine)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+
+	Markdown.go - a position written as Markdown, for pasting into issues, bug reports and forum posts
+
+	The code style is the plain console board in a fenced code block, which every Markdown reader shows in columns.
+	The table style is a GitHub-flavored Markdown table, a board cell to a table cell:
+
+		|   | A | B | C |
+		|---|---|---|---|
+		| 1 | ⚑ | 1 | · |
+
+	The spoiler style is for chats that hide ||text|| until it is clicked, Discord among them: a line of cells per
+	row with what each hidden cell hides behind a spoiler, to be uncovered one at a time. GitHub shows the marks as
+	typed.
+
+	mike@pocomotech.com
+
+*/
+
+package msrender
+
+import (
+	"bufio"
+	"fmt"
+	"go-mines/msboard"
+	"io"
+	"strings"
+)
+
+// MarkdownStyle : how a board is written in Markdown
+type MarkdownStyle int
+
+// Markdown styles
+const (
+	MarkdownCode     MarkdownStyle = iota // the console board in a fenced code block
+	MarkdownTable                         // a GitHub-flavored Markdown table
+	MarkdownSpoilers                      // rows of cells, the hidden ones behind spoilers
+)
+
+// MarkdownRenderer : writes a snapshot as Markdown, the whole board with its row and column labels
+type MarkdownRenderer struct {
+	Style       MarkdownStyle
+	Coordinates msboard.LocationCodec // labels the rows and columns, nil for the default of the board's topology
+	Transform   msboard.Transform     // turns or flips the board as drawn
+	// MineAt tells the spoiler style what hidden cells hide: ||*|| for a mine and ||.|| for a safe cell. Normally
+	// Board.MineAt, so only for finished games or positions meant to give the answer away; nil draws hidden cells
+	// as they are
+	MineAt func(msboard.Location) bool
+}
+
+// compile time check that Markdown can be written wherever a renderer is wanted
+var _ Renderer = MarkdownRenderer{}
+
+// RenderMarkdown -- write a position as the console board in a fenced code block
+func RenderMarkdown(out io.Writer, s msboard.Snapshot) error {
+	return MarkdownRenderer{}.Render(out, s)
+}
+
+// Render -- write the board in the renderer's style
+func (r MarkdownRenderer) Render(out io.Writer, s msboard.Snapshot) error {
+	switch r.Style {
+	case MarkdownTable:
+		return r.table(out, s)
+	case MarkdownSpoilers:
+		return r.spoilers(out, s)
+	}
+
+	w := bufio.NewWriter(out)
+	fmt.Fprintln(w, "```text")
+	if err := (FrameRenderer{Options: Options{Coordinates: r.Coordinates, Transform: r.Transform}}).Render(w, s); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "```")
+	return w.Flush()
+}
+
+// table -- write the board as a Markdown table, with a header row of column labels and the row labels first
+func (r MarkdownRenderer) table(out io.Writer, s msboard.Snapshot) error {
+	w := bufio.NewWriter(out)
+	s = s.Transform(r.Transform)
+	labels := coordinates(Options{Coordinates: r.Coordinates}, s)
+	rows := len(s.Cells)
+
+	names := []string{" "}
+	for col := 0; col < s.Cols; col++ {
+		names = append(names, markdownEscape(labels.ColLabel(col, s.Cols)))
+	}
+	fmt.Fprintf(w, "| %s |\n|%s\n", strings.Join(names, " | "), strings.Repeat("---|", len(names)))
+
+	for row := 0; row < rows; row++ {
+		cells := []string{markdownEscape(labels.RowLabel(row, rows))}
+		for _, v := range s.Cells[row] {
+			cells = append(cells, strings.TrimSpace(sign(v)+UnicodeTheme{}.Cell(v)))
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+	}
+	return w.Flush()
+}
+
+// spoilers -- write the board a row to a line, without labels, each hidden cell behind a spoiler
+func (r MarkdownRenderer) spoilers(out io.Writer, s msboard.Snapshot) error {
+	w := bufio.NewWriter(out)
+	back := r.Transform.Inverse()
+	s = s.Transform(r.Transform)
+
+	for row := range s.Cells {
+		cells := make([]string, len(s.Cells[row]))
+		for col, v := range s.Cells[row] {
+			cells[col] = strings.TrimSpace(sign(v) + ASCIITheme{}.Cell(v))
+			if nil != r.MineAt && v.State == msboard.CellHidden {
+				cells[col] = "||.||"
+				if r.MineAt(back.Location(msboard.NewLocation(row, col), len(s.Cells), s.Cols)) {
+					cells[col] = "||*||"
+				}
+			}
+		}
+		fmt.Fprintln(w, strings.Join(cells, " "))
+	}
+	return w.Flush()
+}
+
+// markdownEscape -- a label with the characters that would end a table cell or start emphasis escaped
+func markdownEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`).Replace(text)
+}
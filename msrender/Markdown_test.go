@@ -0,0 +1,48 @@
+/*
+	Test functions for the Markdown renderer
+
+	mike@pocomotech.com
+*/
+
+package msrender
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	b, _ := msboard.ParseLayout("*../.../...")
+	b.Click(msboard.NewLocation(0, 1))
+	b.ToggleFlag(msboard.NewLocation(0, 0))
+
+	buf := bytes.NewBufferString("")
+	if err := RenderMarkdown(buf, b.Snapshot()); err != nil {
+		t.Fatal(err)
+	}
+	want := "```text\n    A  B  C\n 1  +  1  .\n 2  .  .  .\n 3  .  .  .\n```\n"
+	if buf.String() != want {
+		t.Errorf("code block wanted\n%s\ngot\n%s", want, buf.String())
+	}
+
+	buf.Reset()
+	MarkdownRenderer{Style: MarkdownTable}.Render(buf, b.Snapshot())
+	want = "|   | A | B | C |\n|---|---|---|---|\n| 1 | ⚑ | 1 | · |\n| 2 | · | · | · |\n| 3 | · | · | · |\n"
+	if buf.String() != want {
+		t.Errorf("table wanted\n%s\ngot\n%s", want, buf.String())
+	}
+}
+
+func TestRenderMarkdownSpoilers(t *testing.T) {
+	b, _ := msboard.ParseLayout("..*/...")
+	b.Click(msboard.NewLocation(1, 0))
+
+	// turned a quarter, the mine at C1 is drawn at the bottom right
+	buf := bytes.NewBufferString("")
+	MarkdownRenderer{Style: MarkdownSpoilers, Transform: msboard.TransformRotate90, MineAt: b.MineAt}.Render(buf, b.Snapshot())
+	want := "_ _\n1 1\n||.|| ||*||\n"
+	if buf.String() != want {
+		t.Errorf("spoiler table wanted\n%s\ngot\n%s", want, buf.String())
+	}
+}
@@ -0,0 +1,143 @@
+/*
+
+	Overlay.go - per-cell decorations drawn on top of a theme
+
+	mike@pocomotech.com
+
+*/
+
+package msrender
+
+import (
+	"go-mines/msboard"
+)
+
+// Overlay : decorates the themed glyph of a cell, returning the text to draw in its place. The result must still
+// occupy a single terminal column
+type Overlay interface {
+	Decorate(l msboard.Location, v msboard.CellView, glyph string) string
+}
+
+// Preparer : overlays that look beyond a single cell. Renderers call Prepare with each snapshot before drawing it
+type Preparer interface {
+	Prepare(s msboard.Snapshot)
+}
+
+// prepare -- hand the snapshot about to be drawn to the overlay, if it wants it
+func prepare(o Overlay, s msboard.Snapshot) {
+	if preparer, ok := o.(Preparer); ok {
+		preparer.Prepare(s)
+	}
+}
+
+// Overlays : several overlays applied in order, each decorating the result of the one before
+type Overlays []Overlay
+
+// Decorate -- apply every overlay in turn
+func (o Overlays) Decorate(l msboard.Location, v msboard.CellView, glyph string) string {
+	for _, overlay := range o {
+		glyph = overlay.Decorate(l, v, glyph)
+	}
+	return glyph
+}
+
+// Prepare -- pass the snapshot on to every overlay that wants it
+func (o Overlays) Prepare(s msboard.Snapshot) {
+	for _, overlay := range o {
+		prepare(overlay, s)
+	}
+}
+
+// Resettable : renderers that remember earlier frames. Reset forces the next frame to be drawn in full
+type Resettable interface {
+	Reset()
+}
+
+// Redraw -- make the next Render of r draw a full frame, needed after changing an overlay
+func Redraw(r Renderer) {
+	if resettable, ok := r.(Resettable); ok {
+		resettable.Reset()
+	}
+}
+
+// XRayOverlay : debugging overlay that shows hidden mines faintly
+type XRayOverlay struct {
+	Enabled bool
+	Faint   bool                        // use the ANSI faint attribute rather than a distinct glyph
+	MineAt  func(msboard.Location) bool // hidden board state, normally Board.MineAt
+}
+
+// Decorate -- mark hidden or flagged cells that hold a mine
+func (x *XRayOverlay) Decorate(l msboard.Location, v msboard.CellView, glyph string) string {
+	if !x.Enabled || nil == x.MineAt || (v.State != msboard.CellHidden && v.State != msboard.CellFlagged) {
+		return glyph
+	}
+	if !x.MineAt(l) {
+		return glyph
+	}
+	if x.Faint {
+		return "\x1b[2m*\x1b[22m"
+	}
+	return "x"
+}
+
+// SatisfiedOverlay : marks numbers whose neighboring flags already match their score, so players can see which
+// constraints are done. Numbers are dimmed with the ANSI faint attribute, or replaced by '=' without it
+type SatisfiedOverlay struct {
+	Enabled  bool
+	Faint    bool
+	snapshot msboard.Snapshot
+}
+
+// Prepare -- remember the snapshot so flags around each number can be counted
+func (o *SatisfiedOverlay) Prepare(s msboard.Snapshot) {
+	o.snapshot = s
+}
+
+// Decorate -- mark revealed numbers with exactly as many flags around them as their score
+func (o *SatisfiedOverlay) Decorate(l msboard.Location, v msboard.CellView, glyph string) string {
+	if !o.Enabled || v.State != msboard.CellRevealed || v.Score == 0 || o.snapshot.FlagsAround(l) != v.Score {
+		return glyph
+	}
+	if o.Faint {
+		return "\x1b[2m" + glyph + "\x1b[22m"
+	}
+	return "="
+}
+
+// LastMoveOverlay : highlights the most recent move in reverse video and underlines the other cells its flood
+// fill revealed, so players can follow big cascades. It relies on ANSI attributes, so only enable it for
+// terminals
+type LastMoveOverlay struct {
+	Enabled  bool
+	last     msboard.Location
+	moved    bool
+	revealed map[msboard.Location]bool
+}
+
+// Set -- record the latest move and the cells it revealed, replacing the previous highlight
+func (o *LastMoveOverlay) Set(last msboard.Location, revealed []msboard.Location) {
+	o.last, o.moved = last, true
+	o.revealed = make(map[msboard.Location]bool, len(revealed))
+	for _, l := range revealed {
+		o.revealed[l] = true
+	}
+}
+
+// Clear -- remove the highlight
+func (o *LastMoveOverlay) Clear() {
+	o.moved, o.revealed = false, nil
+}
+
+// Decorate -- reverse the last move's cell and underline the rest of its reveals
+func (o *LastMoveOverlay) Decorate(l msboard.Location, v msboard.CellView, glyph string) string {
+	switch {
+	case !o.Enabled || !o.moved:
+		return glyph
+	case l == o.last:
+		return "\x1b[7m" + glyph + "\x1b[27m"
+	case o.revealed[l]:
+		return "\x1b[4m" + glyph + "\x1b[24m"
+	}
+	return glyph
+}
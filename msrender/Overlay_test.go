@@ -0,0 +1,121 @@
+/*
+	Test functions for render overlays
+
+	mike@pocomotech.com
+*/
+
+package msrender
+
+import (
+	"bytes"
+	"fmt"
+	"go-mines/msboard"
+	"strings"
+	"testing"
+)
+
+func TestXRayOverlay(t *testing.T) {
+	mine := msboard.NewLocation(1, 1)
+	x := &XRayOverlay{MineAt: func(l msboard.Location) bool { return l == mine }}
+	hidden := msboard.CellView{State: msboard.CellHidden}
+
+	if got := x.Decorate(mine, hidden, "."); got != "." {
+		t.Errorf("Disabled x-ray should leave glyphs alone, got %q", got)
+	}
+
+	x.Enabled = true
+	if got := x.Decorate(mine, hidden, "."); got != "x" {
+		t.Errorf("X-ray of hidden mine wanted %q got %q", "x", got)
+	}
+	if got := x.Decorate(msboard.NewLocation(0, 0), hidden, "."); got != "." {
+		t.Errorf("X-ray of safe cell should be unchanged, got %q", got)
+	}
+	if got := x.Decorate(mine, msboard.CellView{State: msboard.CellMine}, "*"); got != "*" {
+		t.Errorf("X-ray of revealed mine should be unchanged, got %q", got)
+	}
+
+	x.Faint = true
+	if got := x.Decorate(mine, msboard.CellView{State: msboard.CellFlagged}, "+"); got != "\x1b[2m*\x1b[22m" {
+		t.Errorf("Faint x-ray of flagged mine got %q", got)
+	}
+}
+
+func TestSatisfiedOverlay(t *testing.T) {
+	b, _ := msboard.ParseLayout("1*./11.")
+	b.ToggleFlag(msboard.NewLocation(0, 1))
+	one := msboard.CellView{State: msboard.CellRevealed, Score: 1}
+
+	o := &SatisfiedOverlay{}
+	o.Prepare(b.Snapshot())
+	if got := o.Decorate(msboard.NewLocation(0, 0), one, "1"); got != "1" {
+		t.Errorf("Disabled overlay should leave glyphs alone, got %q", got)
+	}
+
+	o.Enabled = true
+	if got := o.Decorate(msboard.NewLocation(0, 0), one, "1"); got != "=" {
+		t.Errorf("Satisfied number wanted %q got %q", "=", got)
+	}
+	o.Faint = true
+	if got := o.Decorate(msboard.NewLocation(1, 1), one, "1"); got != "\x1b[2m1\x1b[22m" {
+		t.Errorf("Faint satisfied number got %q", got)
+	}
+
+	b.ToggleFlag(msboard.NewLocation(1, 2))
+	o.Prepare(b.Snapshot())
+	if got := o.Decorate(msboard.NewLocation(1, 1), one, "1"); got != "1" {
+		t.Errorf("Over-flagged number should be unchanged, got %q", got)
+	}
+}
+
+// TestPartialRedrawsDecoratedNeighbors -- flagging a cell changes the decoration of the numbers around it, which
+// must be redrawn even though their own state is unchanged
+func TestPartialRedrawsDecoratedNeighbors(t *testing.T) {
+	b, _ := msboard.ParseLayout("1*./11.")
+	satisfied := &SatisfiedOverlay{Enabled: true}
+	r := NewPartialRenderer(nil, Options{Overlay: Overlays{&XRayOverlay{}, satisfied}})
+
+	out := bytes.NewBufferString("")
+	r.Render(out, b.Snapshot())
+	out.Reset()
+
+	b.ToggleFlag(msboard.NewLocation(0, 1))
+	r.Render(out, b.Snapshot())
+	got := out.String()
+
+	// the flag at B1 and the satisfied 1s at A1, A2 and B2 are rewritten, nothing else
+	for _, l := range []msboard.Location{msboard.NewLocation(0, 0), msboard.NewLocation(1, 0), msboard.NewLocation(1, 1)} {
+		line, column := newFrame(b.Snapshot(), Options{}).cellPosition(l)
+		if want := fmt.Sprintf(ansiMoveFmt, line, column) + "="; !strings.Contains(got, want) {
+			t.Errorf("partial render missing %q for %v in %q", want, l, got)
+		}
+	}
+	if count := strings.Count(got, "\x1b["); count != 4+2 {
+		t.Errorf("partial render wanted 4 cell moves plus cursor park and clear, got %d escapes in %q", count, got)
+	}
+}
+
+func TestLastMoveOverlay(t *testing.T) {
+	o := &LastMoveOverlay{Enabled: true}
+	one := msboard.CellView{State: msboard.CellRevealed, Score: 1}
+	a1, b1, c1 := msboard.NewLocation(0, 0), msboard.NewLocation(0, 1), msboard.NewLocation(0, 2)
+
+	if got := o.Decorate(a1, one, "1"); got != "1" {
+		t.Errorf("LastMoveOverlay before any move should leave glyphs alone, got %q", got)
+	}
+
+	o.Set(a1, []msboard.Location{a1, b1})
+	if got := o.Decorate(a1, one, "1"); got != "\x1b[7m1\x1b[27m" {
+		t.Errorf("last move wanted reverse video got %q", got)
+	}
+	if got := o.Decorate(b1, one, "1"); got != "\x1b[4m1\x1b[24m" {
+		t.Errorf("flood filled cell wanted underline got %q", got)
+	}
+	if got := o.Decorate(c1, one, "1"); got != "1" {
+		t.Errorf("untouched cell should be unchanged, got %q", got)
+	}
+
+	o.Clear()
+	if got := o.Decorate(a1, one, "1"); got != "1" {
+		t.Errorf("cleared overlay should leave glyphs alone, got %q", got)
+	}
+}
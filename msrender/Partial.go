@@ -0,0 +1,150 @@
+/*
+
+	Partial.go - ANSI cursor-addressed renderer that only redraws changed cells
+
+	mike@pocomotech.com
+
+*/
+
+package msrender
+
+import (
+	"bufio"
+	"fmt"
+	"go-mines/msboard"
+	"io"
+	"sync"
+)
+
+// ANSI control sequences used by the partial renderer
+const (
+	ansiClearScreen   = "\x1b[2J\x1b[H" // clear screen and home the cursor
+	ansiClearBelow    = "\x1b[J"        // clear from cursor to end of screen
+	ansiMoveFmt       = "\x1b[%d;%dH"   // move cursor to 1-based line;column
+	ansiSaveCursor    = "\x1b7"         // remember the cursor position and attributes
+	ansiRestoreCursor = "\x1b8"         // return to the remembered cursor position and attributes
+	ansiClearLine     = "\x1b[2K"       // clear the whole line the cursor is on
+)
+
+// PartialRenderer : renderer for interactive terminals. The first frame clears the screen and draws the full
+// board at the top; later frames rewrite only the cells whose drawn text changed, then park the cursor below the
+// board so prompts never scroll the grid away. Comparing drawn text rather than cell states also catches overlay
+// decorations that depend on neighboring cells. Lines below the board can be rewritten from another goroutine
+// with WriteBelow, and the status line above it with RefreshStatus
+type PartialRenderer struct {
+	mu        sync.Mutex // guards the fields below between Render, WriteBelow and RefreshStatus
+	theme     Theme
+	opts      Options
+	lastFrame frame
+	glyphs    [][]string       // text drawn for each cell in the last frame
+	last      msboard.Snapshot // snapshot of the last frame, for RefreshStatus
+	status    string           // status line drawn, if the options have one
+	drawn     bool
+}
+
+// NewPartialRenderer -- create a partial renderer that will draw a full frame on first use. A nil theme draws
+// plain ASCII
+func NewPartialRenderer(theme Theme, opts Options) *PartialRenderer {
+	retval := new(PartialRenderer)
+	retval.theme = themeOrDefault(theme)
+	retval.opts = opts
+	return retval
+}
+
+// Reset -- forget the previous frame so the next Render redraws everything
+func (r *PartialRenderer) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.drawn = false
+}
+
+// Render -- draw the board, redrawing only changed cells when a previous frame is on screen
+func (r *PartialRenderer) Render(out io.Writer, s msboard.Snapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	w := bufio.NewWriter(out)
+	prepare(r.opts.Overlay, s)
+	draw := cellDrawer(r.theme, r.opts, s)
+	original, s := s, s.Transform(r.opts.Transform)
+	f := newFrame(s, r.opts).fitting(r.theme)
+
+	// scrolling or resizing moves every cell, so redraw from scratch
+	full := !r.drawn || f != r.lastFrame
+	if full {
+		w.WriteString(ansiClearScreen)
+		if err := (FrameRenderer{r.theme, r.opts}).Render(w, original); err != nil {
+			return err
+		}
+		r.glyphs = make([][]string, len(s.Cells))
+		for row := range r.glyphs {
+			r.glyphs[row] = make([]string, s.Cols)
+		}
+	}
+	r.last = original
+	if nil != r.opts.Status {
+		if status := r.opts.Status(original); full {
+			r.status = status
+		} else if status != r.status {
+			r.status = status
+			fmt.Fprintf(w, ansiMoveFmt+ansiClearLine+"%s", 1, 1, status)
+		}
+	}
+
+	for row := f.firstRow; row < f.endRow; row++ {
+		for col := f.firstCol; col < f.endCol; col++ {
+			l := msboard.NewLocation(row, col)
+			// the space before the cell holds the sign of a negative score, rewritten only when it changes
+			glyph := sign(s.Cells[row][col]) + draw(l, s.Cells[row][col])
+			if !full && glyph != r.glyphs[row][col] {
+				line, column := f.cellPosition(l)
+				if glyph[0] == r.glyphs[row][col][0] {
+					fmt.Fprintf(w, ansiMoveFmt, line, column)
+					w.WriteString(glyph[1:])
+				} else {
+					fmt.Fprintf(w, ansiMoveFmt, line, column-1)
+					w.WriteString(glyph)
+				}
+			}
+			r.glyphs[row][col] = glyph
+		}
+	}
+
+	// park the cursor on the line below the board and clear old prompts
+	fmt.Fprintf(w, ansiMoveFmt, f.lines()+1, 1)
+	w.WriteString(ansiClearBelow)
+
+	r.lastFrame, r.drawn = f, true
+	return w.Flush()
+}
+
+// WriteBelow -- replace line n, counting from 1, below the board in place and put the cursor back where it was,
+// so a status line can change while the player is typing. Nothing is written before the first frame. out must be
+// safe for use alongside whatever else writes to the terminal
+func (r *PartialRenderer) WriteBelow(out io.Writer, n int, text string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.drawn {
+		return nil
+	}
+	_, err := fmt.Fprintf(out, ansiSaveCursor+ansiMoveFmt+ansiClearLine+"%s"+ansiRestoreCursor, r.lastFrame.lines()+n, 1, text)
+	return err
+}
+
+// RefreshStatus -- redraw the status line above the board in place, if it has changed since it was drawn, and put
+// the cursor back where it was, so counters such as a clock can tick while the player is typing. Nothing is
+// written before the first frame or without a status line. out must be safe for use alongside whatever else writes
+// to the terminal
+func (r *PartialRenderer) RefreshStatus(out io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.drawn || nil == r.opts.Status {
+		return nil
+	}
+	status := r.opts.Status(r.last)
+	if status == r.status {
+		return nil
+	}
+	r.status = status
+	_, err := fmt.Fprintf(out, ansiSaveCursor+ansiMoveFmt+ansiClearLine+"%s"+ansiRestoreCursor, 1, 1, status)
+	return err
+}
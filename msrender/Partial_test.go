@@ -0,0 +1,140 @@
+/*
+	Test functions for the partial redraw renderer
+
+	mike@pocomotech.com
+*/
+
+package msrender
+
+import (
+	"bytes"
+	"fmt"
+	"go-mines/msboard"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestPartialRender(t *testing.T) {
+	rand.Seed(1995)
+	b := msboard.NewBoard("easy")
+	b.Initialize(msboard.NewLocation(0, 0))
+	r := NewPartialRenderer(nil, Options{})
+
+	// first frame is a full redraw
+	buf := bytes.NewBufferString("")
+	r.Render(buf, b.Snapshot())
+	first := buf.String()
+	if !strings.HasPrefix(first, ansiClearScreen) || !strings.Contains(first, headerLine(9)) {
+		t.Errorf("First partial frame should clear the screen and draw the full board, got %q", first)
+	}
+
+	// flagging one cell rewrites just that cell, then parks the cursor below the board
+	flagAt := msboard.NewLocation(2, 3)
+	b.ToggleFlag(flagAt)
+	buf.Reset()
+	r.Render(buf, b.Snapshot())
+
+	line, column := newFrame(b.Snapshot(), Options{}).cellPosition(flagAt)
+	want := fmt.Sprintf(ansiMoveFmt, line, column) + "+" + fmt.Sprintf(ansiMoveFmt, 11, 1) + ansiClearBelow
+	if buf.String() != want {
+		t.Errorf("Partial frame for a single flag wanted %q got %q", want, buf.String())
+	}
+
+	// after a reset the next frame is full again
+	r.Reset()
+	buf.Reset()
+	r.Render(buf, b.Snapshot())
+	if !strings.HasPrefix(buf.String(), ansiClearScreen) {
+		t.Errorf("Frame after Reset() should be a full redraw, got %q", buf.String())
+	}
+}
+
+func TestWriteBelow(t *testing.T) {
+	b, _ := msboard.ParseLayout("*../.../...")
+	r := NewPartialRenderer(nil, Options{})
+
+	buf := bytes.NewBufferString("")
+	r.WriteBelow(buf, 2, "status")
+	if buf.Len() != 0 {
+		t.Errorf("WriteBelow before the first frame wanted nothing got %q", buf.String())
+	}
+
+	// the board takes 4 lines: column letters and three rows
+	r.Render(buf, b.Snapshot())
+	buf.Reset()
+	r.WriteBelow(buf, 2, "status")
+	want := ansiSaveCursor + fmt.Sprintf(ansiMoveFmt, 6, 1) + ansiClearLine + "status" + ansiRestoreCursor
+	if buf.String() != want {
+		t.Errorf("WriteBelow wanted %q got %q", want, buf.String())
+	}
+}
+
+func TestForOutput(t *testing.T) {
+	if _, ok := ForOutput(bytes.NewBufferString(""), Overrides{}).(FrameRenderer); !ok {
+		t.Errorf("Non-terminal output should use the plain renderer")
+	}
+}
+
+func TestPartialRenderViewport(t *testing.T) {
+	rand.Seed(1995)
+	b := msboard.NewBoard("hard")
+	b.Initialize(msboard.NewLocation(0, 0))
+	view := Viewport{Rows: 10, Cols: 8}
+	r := NewPartialRenderer(nil, Options{View: &view})
+
+	buf := bytes.NewBufferString("")
+	r.Render(buf, b.Snapshot())
+
+	// changes outside the viewport draw nothing but the cursor parking
+	b.ToggleFlag(msboard.NewLocation(20, 12))
+	buf.Reset()
+	r.Render(buf, b.Snapshot())
+	want := fmt.Sprintf(ansiMoveFmt, 14, 1) + ansiClearBelow
+	if buf.String() != want {
+		t.Errorf("Off-screen change wanted %q got %q", want, buf.String())
+	}
+
+	// scrolling forces a full redraw
+	view.Follow(msboard.NewLocation(20, 12), b.Rows(), b.Cols())
+	buf.Reset()
+	r.Render(buf, b.Snapshot())
+	if !strings.HasPrefix(buf.String(), ansiClearScreen) {
+		t.Errorf("Frame after scrolling should be a full redraw, got %q", buf.String())
+	}
+}
+
+func TestPartialStatus(t *testing.T) {
+	b, _ := msboard.ParseLayout("*../.../...")
+	status := "first"
+	r := NewPartialRenderer(nil, Options{Status: func(s msboard.Snapshot) string { return status }})
+
+	buf := bytes.NewBufferString("")
+	r.RefreshStatus(buf)
+	r.Render(buf, b.Snapshot())
+	if !strings.HasPrefix(buf.String(), ansiClearScreen+"first\n"+headerLine(3)) {
+		t.Errorf("first frame wanted the status line above the board got %q", buf.String())
+	}
+
+	// the board moves down a line to make room
+	flagAt := msboard.NewLocation(0, 0)
+	b.ToggleFlag(flagAt)
+	buf.Reset()
+	r.Render(buf, b.Snapshot())
+	line, column := newFrame(b.Snapshot(), Options{}).cellPosition(flagAt)
+	want := fmt.Sprintf(ansiMoveFmt, line+1, column) + "+" + fmt.Sprintf(ansiMoveFmt, 6, 1) + ansiClearBelow
+	if buf.String() != want {
+		t.Errorf("partial frame under a status line wanted %q got %q", want, buf.String())
+	}
+
+	// an unchanged status isn't redrawn, a changed one is, in place
+	buf.Reset()
+	if r.RefreshStatus(buf); buf.Len() != 0 {
+		t.Errorf("RefreshStatus of an unchanged status wanted nothing got %q", buf.String())
+	}
+	status = "second"
+	r.RefreshStatus(buf)
+	if want := ansiSaveCursor + fmt.Sprintf(ansiMoveFmt, 1, 1) + ansiClearLine + "second" + ansiRestoreCursor; buf.String() != want {
+		t.Errorf("RefreshStatus wanted %q got %q", want, buf.String())
+	}
+}
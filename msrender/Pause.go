@@ -0,0 +1,35 @@
+/*
+
+	Pause.go - the screens shown instead of the board while a game is paused or passed between players
+
+	mike@pocomotech.com
+
+*/
+
+package msrender
+
+import (
+	"fmt"
+	"io"
+)
+
+// PauseScreen -- hide the board while the clock is stopped. Terminals are cleared so the board can't be studied,
+// and r is reset so the board is drawn in full on resume
+func PauseScreen(out io.Writer, r Renderer, caps Capabilities) error {
+	if caps.TTY {
+		fmt.Fprint(out, ansiClearScreen)
+	}
+	Redraw(r)
+	_, err := fmt.Fprint(out, "\n    *** PAUSED ***\n\n    The board is hidden while the clock is stopped. Press enter to resume.\n")
+	return err
+}
+
+// Handover -- clear the screen between players sharing a terminal, so the next one can't see what the last left
+// there, then show a message
+func Handover(out io.Writer, caps Capabilities, message string) error {
+	if caps.TTY {
+		fmt.Fprint(out, ansiClearScreen)
+	}
+	_, err := fmt.Fprintf(out, "\n    *** %s ***\n\n", message)
+	return err
+}
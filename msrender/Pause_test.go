@@ -0,0 +1,50 @@
+package msrender
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"strings"
+	"testing"
+)
+
+func TestPauseScreen(t *testing.T) {
+	b := msboard.NewBoard("easy")
+	b.Initialize(msboard.NewLocation(0, 0))
+	r := NewPartialRenderer(ASCIITheme{}, Options{})
+
+	var out bytes.Buffer
+	r.Render(&out, b.Snapshot())
+	out.Reset()
+
+	PauseScreen(&out, r, Capabilities{TTY: true})
+	if !strings.HasPrefix(out.String(), ansiClearScreen) || !strings.Contains(out.String(), "PAUSED") {
+		t.Errorf("PauseScreen wanted a cleared screen and pause message, got %q", out.String())
+	}
+
+	// the board comes back in full after a pause
+	out.Reset()
+	r.Render(&out, b.Snapshot())
+	if !strings.HasPrefix(out.String(), ansiClearScreen) {
+		t.Errorf("Render after PauseScreen wanted a full redraw, got %q", out.String())
+	}
+
+	out.Reset()
+	PauseScreen(&out, FrameRenderer{}, Capabilities{})
+	if strings.Contains(out.String(), "\x1b") {
+		t.Errorf("PauseScreen wrote escape codes to a non-terminal: %q", out.String())
+	}
+}
+
+func TestHandover(t *testing.T) {
+	var out bytes.Buffer
+	Handover(&out, Capabilities{TTY: true}, "Pass to player 2")
+	if !strings.HasPrefix(out.String(), ansiClearScreen) || !strings.Contains(out.String(), "Pass to player 2") {
+		t.Errorf("Handover wanted a cleared screen and message, got %q", out.String())
+	}
+
+	out.Reset()
+	Handover(&out, Capabilities{}, "Pass to player 2")
+	if strings.Contains(out.String(), "\x1b") {
+		t.Errorf("Handover wrote escape codes to a non-terminal: %q", out.String())
+	}
+}
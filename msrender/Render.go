@@ -0,0 +1,206 @@
+/*
+
+	Render.go - Snapshot based board renderers for console front ends
+
+	mike@pocomotech.com
+
+*/
+
+// Package msrender -- renderers that draw board Snapshots for the console and other front ends
+package msrender
+
+import (
+	"bufio"
+	"fmt"
+	"go-mines/msboard"
+	"io"
+)
+
+// Renderer : draws a board Snapshot to an output stream
+type Renderer interface {
+	Render(out io.Writer, s msboard.Snapshot) error
+}
+
+// frame : layout of one rendered board, shared by the full and partial renderers
+type frame struct {
+	rows, cols         int // board size
+	statusLines        int // 1 with a status line above the column labels, otherwise 0
+	firstRow, endRow   int // visible rows [firstRow,endRow)
+	firstCol, endCol   int // visible columns [firstCol,endCol)
+	clipRows, clipCols bool
+	layout             msboard.GridLayout
+}
+
+// newFrame -- lay out a snapshot, labelled in the options' coordinates, as seen through their viewport; a nil
+// viewport shows the whole board. Glyphs are taken to be one column wide, see fitting
+func newFrame(s msboard.Snapshot, opts Options) frame {
+	retval := frame{rows: len(s.Cells), cols: s.Cols}
+	if nil != opts.Status {
+		retval.statusLines = 1
+	}
+	retval.layout = msboard.NewGridLayout(retval.rows, retval.cols, coordinates(opts, s))
+	retval.layout.RightToLeft = opts.RightToLeft
+	view := opts.View
+	if nil == view {
+		view = &Viewport{}
+	}
+	retval.firstRow, retval.endRow, retval.firstCol, retval.endCol = view.Window(retval.rows, retval.cols)
+	retval.clipRows = retval.endRow-retval.firstRow < retval.rows
+	retval.clipCols = retval.endCol-retval.firstCol < retval.cols
+	return retval
+}
+
+// fitting -- the frame spaced for the glyphs of a theme
+func (f frame) fitting(theme Theme) frame {
+	f.layout.GlyphWidth = glyphWidth(theme)
+	if f.layout.CellWidth < f.layout.GlyphWidth+1 {
+		// the sign and the glyph, with nothing between cells
+		f.layout.CellWidth = f.layout.GlyphWidth + 1
+	}
+	return f
+}
+
+// gridTop -- number of lines drawn above the first visible row
+func (f frame) gridTop() int {
+	if f.clipRows {
+		return f.statusLines + headerLines + 1
+	}
+	return f.statusLines + headerLines
+}
+
+// lines -- total number of lines in the frame
+func (f frame) lines() int {
+	retval := f.gridTop() + f.endRow - f.firstRow
+	if f.clipRows {
+		retval++
+	}
+	return retval
+}
+
+// visible -- true if the location is drawn in this frame
+func (f frame) visible(l msboard.Location) bool {
+	return l.Row() >= f.firstRow && l.Row() < f.endRow && l.Col() >= f.firstCol && l.Col() < f.endCol
+}
+
+// cellPosition -- 1-based terminal line and column of a visible cell
+func (f frame) cellPosition(l msboard.Location) (line, column int) {
+	return f.gridTop() + l.Row() - f.firstRow + 1, f.layout.Column(l.Col()-f.firstCol) + 1
+}
+
+// Options : per-game renderer settings. View and Overlay are shared with the caller, who may scroll the view or
+// change the overlay between frames. Overlays are given locations on the board, whatever the transform
+type Options struct {
+	View      *Viewport         // window onto the board, nil draws the whole board
+	Overlay   Overlay           // per-cell decorations, nil for none
+	Transform msboard.Transform // turns or flips the board as drawn; the view scrolls the drawn board
+	// Coordinates labels the rows and columns, nil for the default scheme of the board's topology. Labels name
+	// cells of the board as drawn
+	Coordinates msboard.LocationCodec
+	RightToLeft bool // row labels on the right of the board, for players reading right to left
+	// Status gives the line drawn above the board for a snapshot, such as its counters, nil for none. Partial
+	// renderers may call it again between frames with the last snapshot drawn, see RefreshStatus
+	Status func(s msboard.Snapshot) string
+}
+
+// FrameRenderer : full-frame renderer producing the classic ConsoleRender layout. A nil Theme draws plain ASCII
+type FrameRenderer struct {
+	Theme Theme
+	Options
+}
+
+// Render -- draw the complete board, or the part of it visible through the viewport
+func (r FrameRenderer) Render(out io.Writer, s msboard.Snapshot) error {
+	w := bufio.NewWriter(out)
+	theme := themeOrDefault(r.Theme)
+	prepare(r.Overlay, s)
+	draw := cellDrawer(theme, r.Options, s)
+	original, s := s, s.Transform(r.Transform)
+	f := newFrame(s, r.Options).fitting(theme)
+	labels := coordinates(r.Options, s)
+
+	if nil != r.Status {
+		fmt.Fprintln(w, r.Status(original))
+	}
+	fmt.Fprintln(w, f.header(labels))
+	if f.clipRows {
+		fmt.Fprintln(w, f.indicator('^', f.firstRow, "above"))
+	}
+	cells := make([]string, f.endCol-f.firstCol)
+	for row := f.firstRow; row < f.endRow; row++ {
+		for col := f.firstCol; col < f.endCol; col++ {
+			cells[col-f.firstCol] = sign(s.Cells[row][col]) + draw(msboard.NewLocation(row, col), s.Cells[row][col])
+		}
+		fmt.Fprintln(w, f.layout.Row(labels.RowLabel(row, f.rows), cells))
+	}
+	if f.clipRows {
+		fmt.Fprintln(w, f.indicator('v', f.rows-f.endRow, "below"))
+	}
+
+	return w.Flush()
+}
+
+// cellDrawer -- function producing the text for a cell of the transformed snapshot s: the theme glyph, decorated by
+// the overlay if any, which is given the cell's location on the untransformed board
+func cellDrawer(theme Theme, opts Options, s msboard.Snapshot) func(msboard.Location, msboard.CellView) string {
+	back := opts.Transform.Inverse()
+	rows, cols := opts.Transform.Size(s.Rows, s.Cols)
+	return func(l msboard.Location, v msboard.CellView) string {
+		glyph := theme.Cell(v)
+		if nil != opts.Overlay {
+			glyph = opts.Overlay.Decorate(back.Location(l, rows, cols), v, glyph)
+		}
+		return glyph
+	}
+}
+
+// sign -- the character drawn in the space before a cell: a minus for the negative scores of the anti-mine
+// variant, otherwise blank
+func sign(v msboard.CellView) string {
+	if v.State == msboard.CellRevealed && v.Score < 0 {
+		return "-"
+	}
+	return " "
+}
+
+// coordinates -- the scheme labelling the rows and columns of a snapshot: the one chosen in the options, otherwise
+// its topology's
+func coordinates(opts Options, s msboard.Snapshot) msboard.LocationCodec {
+	if nil != opts.Coordinates {
+		return opts.Coordinates
+	}
+	if t, err := msboard.LookupTopology(s.Topology); err == nil {
+		return t.Codec
+	}
+	return msboard.LetterNumberCodec{}
+}
+
+// header -- column label heading aligned with the cell grid, with scroll markers for off-screen columns
+func (f frame) header(labels msboard.LocationCodec) string {
+	names := make([]string, 0, f.endCol-f.firstCol)
+	for col := f.firstCol; col < f.endCol; col++ {
+		names = append(names, labels.ColLabel(col, f.cols))
+	}
+	retval := f.layout.Header(names)
+	if f.clipCols && f.firstCol > 0 {
+		margin := f.layout.Margin()
+		retval = retval[:margin-2] + "< " + retval[margin:]
+	}
+	if f.clipCols && f.endCol < f.cols {
+		retval += " >"
+	}
+	return retval
+}
+
+// indicator -- scroll indicator line for rows hidden above or below the viewport, blank if there are none
+func (f frame) indicator(arrow rune, hidden int, where string) string {
+	if hidden <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%*s%c %d more rows %s", f.layout.Margin(), "", arrow, hidden, where)
+}
+
+// headerLine -- column letter heading for a whole board
+func headerLine(cols int) string {
+	codec := msboard.LetterNumberCodec{}
+	return frame{cols: cols, endCol: cols, layout: msboard.NewGridLayout(0, cols, codec)}.header(codec)
+}
@@ -0,0 +1,172 @@
+/*
+	Test functions for Snapshot renderers
+
+	mike@pocomotech.com
+*/
+
+package msrender
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// TestPlainMatchesConsoleRender -- the snapshot renderer must produce the same frame as Board.ConsoleRender
+func TestPlainMatchesConsoleRender(t *testing.T) {
+	rand.Seed(1995)
+
+	for _, difficulty := range []string{"easy", "medium", "hard"} {
+		b := msboard.NewBoard(difficulty)
+		b.Initialize(msboard.NewLocation(0, 0))
+		b.RevealAll()
+
+		want := bytes.NewBufferString("")
+		b.ConsoleRender(want)
+
+		got := bytes.NewBufferString("")
+		if err := (FrameRenderer{}).Render(got, b.Snapshot()); err != nil {
+			t.Errorf("FrameRenderer failed for %q: %s", difficulty, err)
+		}
+
+		if want.String() != got.String() {
+			t.Errorf("FrameRenderer mismatch for %q. Expected:\n%s\nGot:\n%s", difficulty, want.String(), got.String())
+		}
+	}
+}
+
+// TestRenderUninitialized -- a board waiting for its first click renders as all hidden, without a dummy layout
+func TestRenderUninitialized(t *testing.T) {
+	b := msboard.NewBoard("easy")
+
+	got := bytes.NewBufferString("")
+	if err := (FrameRenderer{}).Render(got, b.Snapshot()); err != nil {
+		t.Fatalf("FrameRenderer failed for an uninitialized board: %s", err)
+	}
+
+	b.Initialize(msboard.NewLocation(0, 0))
+	want := bytes.NewBufferString("")
+	b.ConsoleRender(want)
+
+	if want.String() != got.String() {
+		t.Errorf("uninitialized render wanted an all hidden board:\n%s\nGot:\n%s", want.String(), got.String())
+	}
+}
+
+// TestRenderTransform -- a transformed render draws the transformed board, with overlays still decorating the
+// cells at their board locations
+func TestRenderTransform(t *testing.T) {
+	b, err := msboard.ParseLayout("*1./.../..*/...")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+	turned, _ := b.Transform(msboard.TransformRotate90)
+
+	want := bytes.NewBufferString("")
+	(FrameRenderer{}).Render(want, turned.Snapshot())
+	got := bytes.NewBufferString("")
+	if err := (FrameRenderer{Options: Options{Transform: msboard.TransformRotate90}}).Render(got, b.Snapshot()); err != nil {
+		t.Fatalf("FrameRenderer failed: %s", err)
+	}
+	if want.String() != got.String() {
+		t.Errorf("rotated render mismatch. Expected:\n%s\nGot:\n%s", want.String(), got.String())
+	}
+
+	// the revealed 1 at B1 is drawn at D2 once turned
+	last := &LastMoveOverlay{Enabled: true}
+	last.Set(msboard.NewLocation(0, 1), nil)
+	opts := Options{Overlay: last, Transform: msboard.TransformRotate90}
+	got.Reset()
+	if err := NewPartialRenderer(nil, opts).Render(got, b.Snapshot()); err != nil {
+		t.Fatalf("PartialRenderer failed: %s", err)
+	}
+	if lines := strings.Split(got.String(), "\n"); !strings.HasSuffix(lines[2], "\x1b[7m1\x1b[27m") {
+		t.Errorf("rotated render wanted the last move highlighted at D2, got %q", lines[2])
+	}
+}
+
+// TestRenderNegativeScores -- anti-mine boards draw the minus sign of a negative score in the space before it,
+// in full frames and partial redraws alike
+func TestRenderNegativeScores(t *testing.T) {
+	b, err := msboard.ParseLayout("x-1./-1-1.")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+
+	got := bytes.NewBufferString("")
+	(FrameRenderer{}).Render(got, b.Snapshot())
+	for _, want := range []string{" 1  . -1  .\n", " 2 -1 -1  .\n"} {
+		if !strings.Contains(got.String(), want) {
+			t.Errorf("FrameRenderer wanted row %q in:\n%s", want, got.String())
+		}
+	}
+
+	hidden, _ := msboard.ParseLayout("x../...")
+	r := NewPartialRenderer(ASCIITheme{}, Options{})
+	r.Render(got, hidden.Snapshot())
+	got.Reset()
+	hidden.Click(msboard.NewLocation(0, 1))
+	r.Render(got, hidden.Snapshot())
+	if !strings.HasPrefix(got.String(), "\x1b[2;7H-1") {
+		t.Errorf("PartialRenderer wanted the sign drawn before B1, got %q", got.String())
+	}
+}
+
+func TestRenderCoordinates(t *testing.T) {
+	b, err := msboard.ParseLayout("*1./11./...")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+
+	var cases = []struct {
+		codec msboard.LocationCodec
+		want  []string
+	}{
+		{nil, []string{"    A  B  C\n", " 1  .  1  .\n"}},
+		{msboard.NumberNumberCodec{}, []string{"    1  2  3\n", " 1  .  1  .\n"}},
+		{msboard.ChessCodec{}, []string{"    a  b  c\n", " 3  .  1  .\n", " 1  .  .  .\n"}},
+		{msboard.ChessCodec{Origin: msboard.CornerTopRight}, []string{"    c  b  a\n", " 1  .  1  .\n"}},
+	}
+	for _, testcase := range cases {
+		got := bytes.NewBufferString("")
+		(FrameRenderer{Options: Options{Coordinates: testcase.codec}}).Render(got, b.Snapshot())
+		for _, want := range testcase.want {
+			if !strings.Contains(got.String(), want) {
+				t.Errorf("%T labels wanted %q in:\n%s", testcase.codec, want, got.String())
+			}
+		}
+	}
+}
+
+// TestRenderLayout -- boards whose labels outgrow the classic widths get a wider gutter or wider cells, with the
+// header still over the cells, and right-to-left frames put the row labels after the cells
+func TestRenderLayout(t *testing.T) {
+	tall := msboard.NewCustomBoard(100, 2, 1)
+	tall.Initialize(msboard.NewLocation(0, 0))
+	got := bytes.NewBufferString("")
+	(FrameRenderer{}).Render(got, tall.Snapshot())
+	lines := strings.Split(got.String(), "\n")
+	if lines[0] != "     A  B" || lines[1] != "  1  .  ." || lines[100] != "100  .  ." {
+		t.Errorf("three digit rows misaligned:\n%s", strings.Join(lines[:3], "\n"))
+	}
+
+	b, err := msboard.ParseLayout("*1./11./...")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+	got.Reset()
+	(FrameRenderer{Options: Options{Coordinates: msboard.NumberNumberCodec{}, RightToLeft: true}}).Render(got, b.Snapshot())
+	if want := "  1  2  3\n  .  1  . 1\n  1  1  . 2\n  .  .  . 3\n"; got.String() != want {
+		t.Errorf("right-to-left render wanted:\n%s\nGot:\n%s", want, got.String())
+	}
+
+	// scrolled right, the marker fits the narrow margin
+	got.Reset()
+	view := &Viewport{Rows: 3, Cols: 2, Col: 1}
+	(FrameRenderer{Options: Options{View: view, RightToLeft: true}}).Render(got, b.Snapshot())
+	if lines := strings.Split(got.String(), "\n"); lines[0] != "< B  C" || lines[1] != "  1  . 1" {
+		t.Errorf("scrolled right-to-left render got:\n%s", got.String())
+	}
+}
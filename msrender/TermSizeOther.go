@@ -0,0 +1,20 @@
+//go:build !linux && !darwin
+
+/*
+
+	TermSizeOther.go - terminal size fallback for platforms without TIOCGWINSZ
+
+	mike@pocomotech.com
+
+*/
+
+package msrender
+
+import (
+	"io"
+)
+
+// terminalSize -- unknown on this platform; COLUMNS and LINES can still supply it
+func terminalSize(out io.Writer) (width, height int) {
+	return 0, 0
+}
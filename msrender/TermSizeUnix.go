@@ -0,0 +1,35 @@
+//go:build linux || darwin
+
+/*
+
+	TermSizeUnix.go - terminal size query via the TIOCGWINSZ ioctl
+
+	mike@pocomotech.com
+
+*/
+
+package msrender
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// terminalSize -- width and height in characters of the terminal behind out, zeros if it can't be determined
+func terminalSize(out io.Writer) (width, height int) {
+	f, ok := out.(*os.File)
+	if !ok {
+		return 0, 0
+	}
+
+	var ws struct {
+		rows, cols, xpixel, ypixel uint16
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0, 0
+	}
+	return int(ws.cols), int(ws.rows)
+}
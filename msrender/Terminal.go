@@ -0,0 +1,182 @@
+/*
+
+	Terminal.go - output device capability detection used to choose a renderer
+
+	mike@pocomotech.com
+
+*/
+
+package msrender
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Capabilities : what the output device can display
+type Capabilities struct {
+	TTY    bool       // interactive terminal, supports cursor addressing
+	Color  ColorDepth // colors available for numbers, flags and mines
+	UTF8   bool       // non-ASCII glyphs display correctly
+	Emoji  bool       // draw the board in emoji, which can't be detected and is only set by an override
+	Width  int        // terminal size in characters, zero if unknown
+	Height int
+}
+
+// Overrides : user settings that take precedence over detection. Empty fields (or "auto") mean detect
+type Overrides struct {
+	Color string // "never", "16", "256" or "truecolor"
+	UTF8  string // "yes" or "no"
+	Emoji string // "yes" or "no"; auto is no, as terminals can't say whether they draw emoji
+}
+
+// Environment variables consulted by Detect. Command line overrides win over these; COLUMNS and LINES override
+// the terminal size reported by the operating system
+const (
+	EnvColor = "GOMINES_COLOR"
+	EnvUTF8  = "GOMINES_UTF8"
+	EnvEmoji = "GOMINES_EMOJI"
+)
+
+// IsTerminal -- true if the writer is a character device (an interactive terminal) rather than a file or pipe
+func IsTerminal(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Detect -- work out the capabilities of the output, using getenv (normally os.Getenv) for terminal settings.
+// Output that is not a terminal gets plain ASCII unless the user overrides it
+func Detect(out io.Writer, getenv func(string) string, o Overrides) (Capabilities, error) {
+	retval := Capabilities{TTY: IsTerminal(out)}
+
+	term := getenv("TERM")
+	if retval.TTY && term != "dumb" && getenv("NO_COLOR") == "" {
+		colorterm := strings.ToLower(getenv("COLORTERM"))
+		switch {
+		case colorterm == "truecolor" || colorterm == "24bit":
+			retval.Color = ColorTrue
+		case strings.Contains(term, "256color"):
+			retval.Color = Color256
+		case term != "":
+			retval.Color = Color16
+		}
+	}
+
+	if retval.TTY {
+		retval.Width, retval.Height = terminalSize(out)
+		if columns, err := strconv.Atoi(getenv("COLUMNS")); err == nil && columns > 0 {
+			retval.Width = columns
+		}
+		if lines, err := strconv.Atoi(getenv("LINES")); err == nil && lines > 0 {
+			retval.Height = lines
+		}
+
+		for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+			if value := getenv(name); value != "" {
+				value = strings.ToLower(value)
+				retval.UTF8 = strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+				break
+			}
+		}
+	}
+
+	// explicit settings: environment first, then the caller's overrides on top
+	for _, setting := range []Overrides{{getenv(EnvColor), getenv(EnvUTF8), getenv(EnvEmoji)}, o} {
+		if err := retval.apply(setting); err != nil {
+			return retval, err
+		}
+	}
+
+	return retval, nil
+}
+
+// apply -- overwrite detected capabilities with any non-auto settings
+func (c *Capabilities) apply(o Overrides) error {
+	switch strings.ToLower(o.Color) {
+	case "", "auto":
+	case "never", "none", "no":
+		c.Color = ColorNone
+	case "16", "always", "yes":
+		c.Color = Color16
+	case "256":
+		c.Color = Color256
+	case "truecolor", "24bit":
+		c.Color = ColorTrue
+	default:
+		return fmt.Errorf("unrecognized color setting %q", o.Color)
+	}
+
+	switch strings.ToLower(o.UTF8) {
+	case "", "auto":
+	case "yes", "on", "1", "true":
+		c.UTF8 = true
+	case "no", "off", "0", "false":
+		c.UTF8 = false
+	default:
+		return fmt.Errorf("unrecognized utf8 setting %q", o.UTF8)
+	}
+
+	switch strings.ToLower(o.Emoji) {
+	case "", "auto":
+	case "yes", "on", "1", "true":
+		c.Emoji = true
+	case "no", "off", "0", "false":
+		c.Emoji = false
+	default:
+		return fmt.Errorf("unrecognized emoji setting %q", o.Emoji)
+	}
+
+	return nil
+}
+
+// Theme -- the richest theme the capabilities support. Emoji bring their own colors
+func (c Capabilities) Theme() Theme {
+	if c.Emoji {
+		return EmojiTheme{}
+	}
+	var retval Theme = ASCIITheme{}
+	if c.UTF8 {
+		retval = UnicodeTheme{}
+	}
+	if c.Color != ColorNone {
+		retval = ColorTheme{retval, c.Color}
+	}
+	return retval
+}
+
+// Viewport -- viewport through which a board, drawn in the capabilities' theme with the options, fits the terminal;
+// unlimited if the size is unknown
+func (c Capabilities) Viewport(s msboard.Snapshot, opts Options) Viewport {
+	return NewViewport(c.Width, c.Height, s, c.Theme(), opts)
+}
+
+// Renderer -- choose a renderer for the capabilities: cursor-addressed partial redraw on terminals, full frames
+// otherwise
+func (c Capabilities) Renderer(opts Options) Renderer {
+	if c.TTY {
+		return NewPartialRenderer(c.Theme(), opts)
+	}
+	return FrameRenderer{c.Theme(), opts}
+}
+
+// ForOutput -- detect the capabilities of out from the process environment and choose a renderer, falling back
+// to plain ASCII frames if the overrides are invalid
+func ForOutput(out io.Writer, o Overrides) Renderer {
+	caps, err := Detect(out, os.Getenv, o)
+	if err != nil {
+		return FrameRenderer{}
+	}
+	return caps.Renderer(Options{})
+}
@@ -0,0 +1,91 @@
+/*
+	Test functions for terminal capability detection
+
+	mike@pocomotech.com
+*/
+
+package msrender
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"testing"
+)
+
+// fakeEnv -- getenv replacement backed by a map
+func fakeEnv(vars map[string]string) func(string) string {
+	return func(name string) string {
+		return vars[name]
+	}
+}
+
+func TestDetectNonTerminal(t *testing.T) {
+	env := fakeEnv(map[string]string{"TERM": "xterm-256color", "LANG": "en_US.UTF-8", "COLORTERM": "truecolor"})
+
+	caps, err := Detect(bytes.NewBufferString(""), env, Overrides{})
+	if err != nil || caps != (Capabilities{}) {
+		t.Errorf("Piped output should get plain capabilities, got %+v err %v", caps, err)
+	}
+	if _, ok := caps.Renderer(Options{}).(FrameRenderer); !ok {
+		t.Errorf("Piped output should use the frame renderer")
+	}
+	if _, ok := caps.Theme().(ASCIITheme); !ok {
+		t.Errorf("Piped output should use the ASCII theme")
+	}
+}
+
+func TestDetectOverrides(t *testing.T) {
+	var cases = []struct {
+		env  map[string]string
+		o    Overrides
+		want Capabilities
+		err  bool
+	}{
+		{map[string]string{}, Overrides{Color: "256", UTF8: "yes"}, Capabilities{Color: Color256, UTF8: true}, false},
+		{map[string]string{EnvColor: "truecolor"}, Overrides{}, Capabilities{Color: ColorTrue}, false},
+		{map[string]string{EnvColor: "truecolor", EnvUTF8: "1"}, Overrides{Color: "never"}, Capabilities{UTF8: true}, false},
+		{map[string]string{}, Overrides{Color: "purple"}, Capabilities{}, true},
+		{map[string]string{}, Overrides{UTF8: "maybe"}, Capabilities{}, true},
+		{map[string]string{EnvEmoji: "yes"}, Overrides{}, Capabilities{Emoji: true}, false},
+		{map[string]string{EnvEmoji: "yes"}, Overrides{Emoji: "no"}, Capabilities{}, false},
+		{map[string]string{}, Overrides{Emoji: "sometimes"}, Capabilities{}, true},
+	}
+
+	for _, testcase := range cases {
+		got, err := Detect(bytes.NewBufferString(""), fakeEnv(testcase.env), testcase.o)
+		if (err != nil) != testcase.err {
+			t.Errorf("Detect(%v, %+v) error wanted %v got %v", testcase.env, testcase.o, testcase.err, err)
+			continue
+		}
+		if err == nil && got != testcase.want {
+			t.Errorf("Detect(%v, %+v) wanted %+v got %+v", testcase.env, testcase.o, testcase.want, got)
+		}
+	}
+}
+
+func TestThemes(t *testing.T) {
+	flag := msboard.CellView{State: msboard.CellFlagged}
+	three := msboard.CellView{State: msboard.CellRevealed, Score: 3}
+	blank := msboard.CellView{State: msboard.CellRevealed}
+
+	if got := (Capabilities{}).Theme().Cell(flag); got != "+" {
+		t.Errorf("ASCII flag wanted %q got %q", "+", got)
+	}
+	if got := (Capabilities{UTF8: true}).Theme().Cell(flag); got != "⚑" {
+		t.Errorf("Unicode flag wanted %q got %q", "⚑", got)
+	}
+	if got := (Capabilities{Color: Color16}).Theme().Cell(three); got != "\x1b[1;91m3\x1b[0m" {
+		t.Errorf("16 color score 3 got %q", got)
+	}
+	if got := (Capabilities{Color: ColorTrue}).Theme().Cell(blank); got != "_" {
+		t.Errorf("Zero score cells should not be colored, got %q", got)
+	}
+
+	fogged := msboard.CellView{State: msboard.CellHidden, OutOfRange: true}
+	if got := (Capabilities{}).Theme().Cell(fogged); got != "~" {
+		t.Errorf("ASCII out of range cell wanted %q got %q", "~", got)
+	}
+	if got := (Capabilities{UTF8: true}).Theme().Cell(fogged); got != "░" {
+		t.Errorf("Unicode out of range cell wanted %q got %q", "░", got)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+
+	Theme.go - cell glyph and color themes shared by the renderers
+
+	mike@pocomotech.com
+
+*/
+
+package msrender
+
+import (
+	"fmt"
+	"go-mines/msboard"
+)
+
+// Theme : maps a cell view to the text drawn for it. Whatever the escape sequences involved, the text must
+// occupy exactly one terminal column so the grid layout holds, unless the theme is a WideTheme
+type Theme interface {
+	Cell(v msboard.CellView) string
+}
+
+// WideTheme : a theme whose glyphs all take the same number of terminal columns, more than one, as emoji take two.
+// Renderers space the grid to fit them
+type WideTheme interface {
+	Theme
+	GlyphWidth() int
+}
+
+// ASCIITheme : classic 7-bit console glyphs, safe for files, pipes and dumb terminals
+type ASCIITheme struct{}
+
+// Cell -- plain ASCII glyph for the cell
+func (ASCIITheme) Cell(v msboard.CellView) string {
+	return string(v.Rune())
+}
+
+// UnicodeTheme : box and symbol glyphs for UTF-8 capable terminals
+type UnicodeTheme struct{}
+
+// Cell -- unicode glyph for the cell
+func (UnicodeTheme) Cell(v msboard.CellView) string {
+	switch v.State {
+	case msboard.CellHidden:
+		if v.OutOfRange {
+			return "░"
+		}
+		return "·"
+	case msboard.CellFlagged:
+		return "⚑"
+	case msboard.CellMine:
+		return "✹"
+	}
+	return string(v.Rune())
+}
+
+// ColorDepth : number of colors a terminal can display
+type ColorDepth int
+
+// Supported color depths, in increasing order of capability
+const (
+	ColorNone ColorDepth = iota // no color escapes at all
+	Color16                     // basic 16 color SGR codes
+	Color256                    // xterm 256 color palette
+	ColorTrue                   // 24 bit RGB
+)
+
+// classic Windows Minesweeper number colors: 16 color SGR code, 256 palette index and RGB for scores 1..8,
+// with flags and mines in slot 0
+var scoreColors = [...]struct {
+	sgr16, xterm256 int
+	r, g, b         int
+}{
+	{91, 196, 255, 0, 0},     // flag/mine
+	{94, 21, 0, 0, 255},      // 1
+	{32, 28, 0, 128, 0},      // 2
+	{91, 196, 255, 0, 0},     // 3
+	{34, 18, 0, 0, 128},      // 4
+	{31, 88, 128, 0, 0},      // 5
+	{36, 30, 0, 128, 128},    // 6
+	{35, 90, 128, 0, 128},    // 7, classic black is invisible on dark terminals
+	{90, 244, 128, 128, 128}, // 8
+}
+
+// ColorTheme : wraps another theme's glyphs in ANSI color escapes for numbers, flags and mines
+type ColorTheme struct {
+	Base  Theme
+	Depth ColorDepth
+}
+
+// Cell -- colored glyph for the cell
+func (t ColorTheme) Cell(v msboard.CellView) string {
+	glyph := themeOrDefault(t.Base).Cell(v)
+
+	slot := -1
+	switch v.State {
+	case msboard.CellFlagged, msboard.CellMine:
+		slot = 0
+	case msboard.CellRevealed:
+		// negative scores take the color of their size
+		score := v.Score
+		if score < 0 {
+			score = -score
+		}
+		if score > 0 && score < len(scoreColors) {
+			slot = score
+		}
+	}
+	if slot < 0 {
+		return glyph
+	}
+
+	c := scoreColors[slot]
+	switch t.Depth {
+	case ColorNone:
+		return glyph
+	case Color256:
+		return fmt.Sprintf("\x1b[1;38;5;%dm%s\x1b[0m", c.xterm256, glyph)
+	case ColorTrue:
+		return fmt.Sprintf("\x1b[1;38;2;%d;%d;%dm%s\x1b[0m", c.r, c.g, c.b, glyph)
+	}
+	return fmt.Sprintf("\x1b[1;%dm%s\x1b[0m", c.sgr16, glyph)
+}
+
+// glyphWidth -- terminal columns each of a theme's glyphs takes. Color doesn't change a glyph's width
+func glyphWidth(t Theme) int {
+	if colored, ok := t.(ColorTheme); ok {
+		return glyphWidth(themeOrDefault(colored.Base))
+	}
+	if wide, ok := t.(WideTheme); ok {
+		return wide.GlyphWidth()
+	}
+	return 1
+}
+
+// themeOrDefault -- nil themes mean plain ASCII
+func themeOrDefault(t Theme) Theme {
+	if nil == t {
+		return ASCIITheme{}
+	}
+	return t
+}
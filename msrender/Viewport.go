@@ -0,0 +1,119 @@
+/*
+
+	Viewport.go - scrollable window onto boards larger than the terminal
+
+	mike@pocomotech.com
+
+*/
+
+package msrender
+
+import (
+	"go-mines/msboard"
+)
+
+// Viewport : window onto a board, measured in cells. Row and Col are the first visible cell; a zero Rows or Cols
+// means that dimension is unlimited
+type Viewport struct {
+	Row, Col   int
+	Rows, Cols int
+}
+
+// Lines used around the visible grid: column header, the two scroll indicator lines, and room for prompts
+const (
+	headerLines    = 1
+	indicatorLines = 2
+	promptLines    = 3
+)
+
+// NewViewport -- largest viewport through which a board, drawn in a theme with the options, fits a terminal of the
+// given size in characters. The board is laid out as the renderers lay it out, so long labels and wide glyphs
+// leave room for fewer cells, and a status line takes a row. The options' own view is ignored. Zero sizes give an
+// unlimited viewport
+func NewViewport(width, height int, s msboard.Snapshot, theme Theme, opts Options) Viewport {
+	opts.View = nil
+	f := newFrame(s.Transform(opts.Transform), opts).fitting(themeOrDefault(theme))
+
+	retval := Viewport{}
+	if width > 0 {
+		// after the last cell's glyph come its column label, which is as wide as a cell less the space after it,
+		// and the right hand scroll marker on the header, or a row label on the right
+		last := f.layout.CellWidth - 1 + 2
+		if f.layout.RightToLeft {
+			last = maxInt(last, f.layout.GlyphWidth+1+f.layout.LabelWidth)
+		}
+		retval.Cols = maxInt(1, (width-f.layout.Margin()-last)/f.layout.CellWidth+1)
+	}
+	if height > 0 {
+		retval.Rows = maxInt(1, height-f.statusLines-headerLines-indicatorLines-promptLines)
+	}
+	return retval
+}
+
+// Window -- visible cell range [firstRow,endRow) x [firstCol,endCol) of a board, with the viewport clamped
+// so it never scrolls past the board edges
+func (v Viewport) Window(rows, cols int) (firstRow, endRow, firstCol, endCol int) {
+	firstRow, endRow = clampSpan(v.Row, v.Rows, rows)
+	firstCol, endCol = clampSpan(v.Col, v.Cols, cols)
+	return
+}
+
+// clampSpan -- clamp a window of length size starting at start into [0,total)
+func clampSpan(start, size, total int) (first, end int) {
+	if size <= 0 || size >= total {
+		return 0, total
+	}
+	first = minInt(maxInt(start, 0), total-size)
+	return first, first + size
+}
+
+// Clamp -- keep the viewport within a board of the given size
+func (v *Viewport) Clamp(rows, cols int) {
+	v.Row, _, v.Col, _ = v.Window(rows, cols)
+}
+
+// Scroll -- move the viewport by a number of rows and columns, staying within the board
+func (v *Viewport) Scroll(dRow, dCol, rows, cols int) {
+	v.Row += dRow
+	v.Col += dCol
+	v.Clamp(rows, cols)
+}
+
+// Follow -- scroll the minimum distance needed to bring a location into view
+func (v *Viewport) Follow(l msboard.Location, rows, cols int) {
+	if v.Rows > 0 {
+		if l.Row() < v.Row {
+			v.Row = l.Row()
+		} else if l.Row() >= v.Row+v.Rows {
+			v.Row = l.Row() - v.Rows + 1
+		}
+	}
+	if v.Cols > 0 {
+		if l.Col() < v.Col {
+			v.Col = l.Col()
+		} else if l.Col() >= v.Col+v.Cols {
+			v.Col = l.Col() - v.Cols + 1
+		}
+	}
+	v.Clamp(rows, cols)
+}
+
+// Contains -- true if the location is visible through the viewport on a board of the given size
+func (v Viewport) Contains(l msboard.Location, rows, cols int) bool {
+	firstRow, endRow, firstCol, endCol := v.Window(rows, cols)
+	return l.Row() >= firstRow && l.Row() < endRow && l.Col() >= firstCol && l.Col() < endCol
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,124 @@
+/*
+	Test functions for board viewports
+
+	mike@pocomotech.com
+*/
+
+package msrender
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestNewViewport(t *testing.T) {
+	hard := msboard.NewBoard("hard").Snapshot()
+	huge := msboard.NewCustomBoard(200, 800, 1000).Snapshot()
+	status := func(s msboard.Snapshot) string { return "status" }
+
+	var cases = []struct {
+		width, height int
+		s             msboard.Snapshot
+		theme         Theme
+		opts          Options
+		want          Viewport
+	}{
+		{0, 0, hard, nil, Options{}, Viewport{}},
+		{80, 24, hard, nil, Options{}, Viewport{Rows: 18, Cols: 25}},
+		{80, 24, hard, EmojiTheme{}, Options{}, Viewport{Rows: 18, Cols: 25}},
+		{80, 24, hard, nil, Options{Status: status}, Viewport{Rows: 17, Cols: 25}},
+		{80, 24, hard, nil, Options{RightToLeft: true}, Viewport{Rows: 18, Cols: 25}},
+		// three digit rows and three letter columns widen the gutter and every cell
+		{80, 24, huge, nil, Options{}, Viewport{Rows: 18, Cols: 18}},
+		{10, 3, hard, nil, Options{}, Viewport{Rows: 1, Cols: 1}},
+	}
+
+	for _, testcase := range cases {
+		got := NewViewport(testcase.width, testcase.height, testcase.s, testcase.theme, testcase.opts)
+		if got != testcase.want {
+			t.Errorf("NewViewport(%d, %d) of %dx%d wanted %+v got %+v", testcase.width, testcase.height,
+				testcase.s.Rows, testcase.s.Cols, testcase.want, got)
+		}
+	}
+
+	// the view fits every line drawn, even scrolled to the longest labels with more columns to the right, and one
+	// more column wouldn't
+	for _, s := range []msboard.Snapshot{hard, huge} {
+		view := NewViewport(80, 24, s, nil, Options{})
+		view.Row, view.Col = 1, s.Cols-view.Cols-2
+		if widest := widestLine(s, view); widest > 80 {
+			t.Errorf("%dx%d board drawn %d wide through %+v", s.Rows, s.Cols, widest, view)
+		}
+		view.Cols++
+		if widest := widestLine(s, view); widest <= 80 {
+			t.Errorf("%dx%d board would fit %d columns, not %d", s.Rows, s.Cols, view.Cols, view.Cols-1)
+		}
+	}
+}
+
+// widestLine -- characters in the longest line of a board drawn through a view
+func widestLine(s msboard.Snapshot, view Viewport) int {
+	buf := bytes.NewBufferString("")
+	FrameRenderer{Options: Options{View: &view}}.Render(buf, s)
+	retval := 0
+	for _, line := range strings.Split(buf.String(), "\n") {
+		retval = maxInt(retval, len(line))
+	}
+	return retval
+}
+
+func TestViewportScrolling(t *testing.T) {
+	rows, cols := 30, 40
+	v := Viewport{Rows: 10, Cols: 20}
+
+	v.Scroll(-5, -5, rows, cols)
+	if v.Row != 0 || v.Col != 0 {
+		t.Errorf("Scrolling past the top left should clamp to 0,0, got %d,%d", v.Row, v.Col)
+	}
+
+	v.Scroll(100, 100, rows, cols)
+	if v.Row != 20 || v.Col != 20 {
+		t.Errorf("Scrolling past the bottom right should clamp to 20,20, got %d,%d", v.Row, v.Col)
+	}
+
+	target := msboard.NewLocation(3, 7)
+	v.Follow(target, rows, cols)
+	if !v.Contains(target, rows, cols) || v.Row != 3 || v.Col != 7 {
+		t.Errorf("Follow(%v) left viewport at %d,%d", target, v.Row, v.Col)
+	}
+	if v.Contains(msboard.NewLocation(13, 7), rows, cols) {
+		t.Errorf("Viewport should not contain a row beyond its height")
+	}
+
+	// unlimited viewports contain everything
+	if !(Viewport{}).Contains(msboard.NewLocation(29, 39), rows, cols) {
+		t.Errorf("Unlimited viewport should contain every cell")
+	}
+}
+
+func TestFrameRendererViewport(t *testing.T) {
+	rand.Seed(1995)
+	b := msboard.NewCustomBoard(40, 30, 100)
+	b.Initialize(msboard.NewLocation(0, 0))
+
+	view := Viewport{Row: 5, Col: 26, Rows: 4, Cols: 3}
+	buf := bytes.NewBufferString("")
+	FrameRenderer{Options: Options{View: &view}}.Render(buf, b.Snapshot())
+
+	want := []string{
+		"  < AA AB AC >",
+		"    ^ 5 more rows above",
+		" 6  .  .  .",
+		" 7  .  .  .",
+		" 8  .  .  .",
+		" 9  .  .  .",
+		"    v 31 more rows below",
+		"",
+	}
+	if buf.String() != strings.Join(want, "\n") {
+		t.Errorf("Viewport render wanted:\n%s\ngot:\n%s", strings.Join(want, "\n"), buf.String())
+	}
+}
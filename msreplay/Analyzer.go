@@ -0,0 +1,293 @@
+/*
+
+	Analyzer.go - post-game review: judge every move of a replay against what the player could see
+
+	mike@pocomotech.com
+
+*/
+
+package msreplay
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+// Prober : solver that estimates the chance each cell of a position holds a mine, see mssolver.Frontier
+type Prober interface {
+	Probabilities(s msboard.Snapshot) ([][]float64, error)
+}
+
+// Estimator : Prober whose answers may be estimates, giving for every cell the half width of a 95% confidence
+// interval, zero for exact values and nil margins when every value is exact. See mssolver.Fallback
+type Estimator interface {
+	Estimate(s msboard.Snapshot) (probabilities, margins [][]float64, err error)
+}
+
+// Verdict : how a move looked given the position it was made in
+type Verdict int
+
+// Move verdicts
+const (
+	VerdictOpening          Verdict = iota // first reveal of the game, always safe
+	VerdictForced                          // provably right: a certainly safe reveal or a certain mine flagged
+	VerdictGuess                           // no safe reveal existed and the move took the lowest risk available
+	VerdictSuboptimal                      // no safe reveal existed but a lower risk reveal did
+	VerdictUnnecessaryGuess                // a risky reveal while a certainly safe one existed
+	VerdictSpeculativeFlag                 // a flag on a cell that wasn't certainly mined
+	VerdictNoEffect                        // a move that changed nothing, or removed a flag
+	VerdictUnknown                         // the position was too complex to judge
+)
+
+var verdictNames = [...]string{
+	"opening", "forced", "guess", "suboptimal", "unnecessary guess", "speculative flag", "no effect", "unknown",
+}
+
+// String -- short verdict name
+func (v Verdict) String() string {
+	if v < 0 || int(v) >= len(verdictNames) {
+		return "invalid"
+	}
+	return verdictNames[v]
+}
+
+// Annotation : the analyzer's judgement of one move
+type Annotation struct {
+	Index     int          // zero-based move number
+	Move      msboard.Move // the move as played
+	Verdict   Verdict
+	Risk      float64       // chance the move's cell held a mine, from what the player could see
+	Margin    float64       // half width of the 95% confidence interval on Risk, zero when it's exact
+	BestRisk  float64       // lowest risk of any hidden cell in the same position
+	SafeMoves int           // number of certainly safe reveals that were available
+	Exploded  bool          // the move revealed a mine
+	Think     time.Duration // time taken over the move, zero for untimed replays
+}
+
+// String -- one line description of the annotated move
+func (a Annotation) String() string {
+	retval := fmt.Sprintf("%3d. %-6s %-4s ", a.Index+1, a.Move.Type, cellName(a.Move.Location))
+
+	switch a.Verdict {
+	case VerdictSuboptimal:
+		outcome := "safe but suboptimal"
+		if a.Exploded {
+			outcome = "suboptimal guess"
+		}
+		retval += fmt.Sprintf("%s (%s risk, %s was available)", outcome, a.risk(a.Risk), percent(a.BestRisk))
+	case VerdictUnnecessaryGuess:
+		retval += fmt.Sprintf("unnecessary guess (%s risk when a safe move existed)", a.risk(a.Risk))
+	case VerdictGuess:
+		retval += fmt.Sprintf("guess (%s risk, the lowest available)", a.risk(a.Risk))
+	case VerdictSpeculativeFlag:
+		retval += fmt.Sprintf("speculative flag (%s chance of a mine)", a.risk(1-a.Risk))
+	default:
+		retval += a.Verdict.String()
+	}
+
+	if a.Exploded {
+		retval += " - BOOM"
+	}
+	if a.Think > 0 {
+		retval += fmt.Sprintf(" [%s]", a.Think.Round(100*time.Millisecond))
+	}
+	return retval
+}
+
+// risk -- a probability about the move, with its margin if it's an estimate
+func (a Annotation) risk(p float64) string {
+	if a.Margin > 0 {
+		return fmt.Sprintf("%s ±%s", percent(p), percent(a.Margin))
+	}
+	return percent(p)
+}
+
+// Analyze -- judge every move of a replay using a solver's view of the position it was made in. Estimators may
+// answer with estimates; moves judged from those carry a margin, and no estimate is ever taken as certain
+func Analyze(r Replay, p Prober) ([]Annotation, error) {
+	positions, err := r.Positions()
+	if err != nil {
+		return nil, err
+	}
+
+	retval := make([]Annotation, 0, len(r.Moves))
+	for i, m := range r.Moves {
+		before, after := positions[i], positions[i+1]
+		a := Annotation{Index: r.Compacted + i, Move: m, Think: r.ThinkTime(i)}
+		if view, ok := after.Cell(m.Location); ok {
+			a.Exploded = view.State == msboard.CellMine && after.Status == msboard.StatusLost &&
+				before.Status != msboard.StatusLost
+		}
+		a.Verdict = judge(&a, before, after, p)
+		retval = append(retval, a)
+	}
+
+	return retval, nil
+}
+
+// judge -- classify one move, filling in the risk figures on the annotation
+func judge(a *Annotation, before, after msboard.Snapshot, p Prober) Verdict {
+	view, _ := before.Cell(a.Move.Location)
+	changed, _ := after.Cell(a.Move.Location)
+
+	switch {
+	case view == changed:
+		return VerdictNoEffect
+	case a.Move.Type == msboard.MoveFlag && view.State == msboard.CellFlagged:
+		return VerdictNoEffect
+	case a.Move.Type == msboard.MoveReveal && !anyRevealed(before):
+		return VerdictOpening
+	}
+
+	var probabilities, margins [][]float64
+	var err error
+	if e, ok := p.(Estimator); ok {
+		probabilities, margins, err = e.Estimate(before)
+	} else {
+		probabilities, err = p.Probabilities(before)
+	}
+	if err != nil {
+		return VerdictUnknown
+	}
+	exact := func(row, col int) bool {
+		return nil == margins || margins[row][col] == 0
+	}
+
+	a.Risk = probabilities[a.Move.Location.Row()][a.Move.Location.Col()]
+	if !exact(a.Move.Location.Row(), a.Move.Location.Col()) {
+		a.Margin = margins[a.Move.Location.Row()][a.Move.Location.Col()]
+	}
+	a.BestRisk = 1
+	for row := range probabilities {
+		for col, risk := range probabilities[row] {
+			if cell, _ := before.Cell(msboard.NewLocation(row, col)); cell.State != msboard.CellHidden {
+				continue
+			}
+			if risk == 0 && exact(row, col) {
+				a.SafeMoves++
+			}
+			a.BestRisk = math.Min(a.BestRisk, risk)
+		}
+	}
+
+	if a.Move.Type == msboard.MoveFlag {
+		if a.Risk == 1 && a.Margin == 0 {
+			return VerdictForced
+		}
+		return VerdictSpeculativeFlag
+	}
+
+	switch {
+	case a.Risk == 0 && a.Margin == 0:
+		return VerdictForced
+	case a.SafeMoves > 0:
+		return VerdictUnnecessaryGuess
+	case a.Risk > a.BestRisk+riskTolerance:
+		return VerdictSuboptimal
+	}
+	return VerdictGuess
+}
+
+// anyRevealed -- true once some cell of the position has been revealed
+func anyRevealed(s msboard.Snapshot) bool {
+	for row := range s.Cells {
+		for _, view := range s.Cells[row] {
+			if view.State == msboard.CellRevealed || view.State == msboard.CellMine {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ThinkStats : where the time went in a timed game
+type ThinkStats struct {
+	Total     time.Duration // time from the board being shown to the last move
+	PerReveal time.Duration // mean think time of reveals
+	Slowest   []Annotation  // the longest decisions, slowest first
+}
+
+// Timings -- think time statistics over annotated moves, keeping the n slowest decisions. False if the moves
+// weren't timed
+func Timings(notes []Annotation, n int) (ThinkStats, bool) {
+	var retval ThinkStats
+	timed, reveals := false, 0
+	for _, a := range notes {
+		retval.Total += a.Think
+		timed = timed || a.Think > 0
+		if a.Move.Type == msboard.MoveReveal && a.Verdict != VerdictNoEffect {
+			retval.PerReveal += a.Think
+			reveals++
+		}
+	}
+	if !timed {
+		return ThinkStats{}, false
+	}
+	if reveals > 0 {
+		retval.PerReveal /= time.Duration(reveals)
+	}
+
+	retval.Slowest = append([]Annotation(nil), notes...)
+	sort.SliceStable(retval.Slowest, func(i, j int) bool {
+		return retval.Slowest[i].Think > retval.Slowest[j].Think
+	})
+	if len(retval.Slowest) > n {
+		retval.Slowest = retval.Slowest[:n]
+	}
+	return retval, true
+}
+
+// slowestShown -- number of slow decisions listed in a report
+const slowestShown = 3
+
+// riskTolerance -- guesses this close to the best available risk count as equally good
+const riskTolerance = 0.005
+
+// WriteReport -- print every annotated move followed by a summary of verdicts
+func WriteReport(w io.Writer, notes []Annotation) error {
+	counts := make(map[Verdict]int)
+	for _, a := range notes {
+		if _, err := fmt.Fprintln(w, a); err != nil {
+			return err
+		}
+		counts[a.Verdict]++
+	}
+
+	fmt.Fprintf(w, "\n%d moves:", len(notes))
+	for v := VerdictOpening; int(v) < len(verdictNames); v++ {
+		if counts[v] > 0 {
+			fmt.Fprintf(w, " %d %s,", counts[v], v)
+		}
+	}
+	_, err := fmt.Fprintf(w, " mistakes: %d\n", counts[VerdictUnnecessaryGuess]+counts[VerdictSuboptimal])
+
+	if stats, ok := Timings(notes, slowestShown); ok {
+		fmt.Fprintf(w, "time %s, average %s per reveal, slowest decisions:\n",
+			stats.Total.Round(100*time.Millisecond), stats.PerReveal.Round(100*time.Millisecond))
+		for _, a := range stats.Slowest {
+			fmt.Fprintln(w, a)
+		}
+	}
+	return err
+}
+
+// cellName -- location as the player types it, column letters then row number
+func cellName(l msboard.Location) string {
+	retval := ""
+	for col := l.Col(); col >= 0; col = col/26 - 1 {
+		retval = string(rune('A'+col%26)) + retval
+	}
+	return fmt.Sprintf("%s%d", retval, l.Row()+1)
+}
+
+// percent -- probability as a whole percentage, keeping small but nonzero risks visible
+func percent(p float64) string {
+	if p > 0 && p < 0.01 {
+		return "<1%"
+	}
+	return fmt.Sprintf("%.0f%%", p*100)
+}
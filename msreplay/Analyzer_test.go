@@ -0,0 +1,132 @@
+package msreplay
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"go-mines/mssolver"
+	"strings"
+	"testing"
+	"time"
+)
+
+func reveal(row, col int) msboard.Move {
+	return msboard.Move{Type: msboard.MoveReveal, Location: msboard.NewLocation(row, col)}
+}
+
+func flag(row, col int) msboard.Move {
+	return msboard.Move{Type: msboard.MoveFlag, Location: msboard.NewLocation(row, col)}
+}
+
+func TestAnalyze(t *testing.T) {
+	var cases = []struct {
+		layout string
+		moves  []msboard.Move
+		want   []Verdict
+	}{
+		// opening floods to D1..D3, where D2 is certainly safe and D1, D3 certainly mined
+		{"...*/..../...*",
+			[]msboard.Move{reveal(0, 0), reveal(0, 0), flag(0, 3), flag(0, 3), reveal(2, 3)},
+			[]Verdict{VerdictOpening, VerdictNoEffect, VerdictForced, VerdictNoEffect, VerdictUnnecessaryGuess}},
+		{"...*/..../...*",
+			[]msboard.Move{reveal(0, 0), reveal(1, 3)},
+			[]Verdict{VerdictOpening, VerdictForced}},
+		// no safe cell: B1, A2 and B2 each hide the mine a third of the time, C1 and C2 half the time
+		{"1*./..*", []msboard.Move{reveal(1, 0)}, []Verdict{VerdictGuess}},
+		{"1*./..*", []msboard.Move{reveal(0, 2)}, []Verdict{VerdictSuboptimal}},
+		{"1*./..*", []msboard.Move{flag(1, 1)}, []Verdict{VerdictSpeculativeFlag}},
+	}
+
+	for _, testcase := range cases {
+		r := Replay{Layout: testcase.layout, Moves: testcase.moves}
+		notes, err := Analyze(r, mssolver.Frontier{})
+		if err != nil {
+			t.Fatalf("Analyze(%q) failed: %s", testcase.layout, err)
+		}
+		for i, a := range notes {
+			if a.Verdict != testcase.want[i] {
+				t.Errorf("Analyze(%q) move %d wanted %v got %v", testcase.layout, i+1, testcase.want[i], a)
+			}
+		}
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	r := Replay{Layout: "...*/..../...*", Moves: []msboard.Move{reveal(0, 0), reveal(0, 3)}}
+	notes, _ := Analyze(r, mssolver.Frontier{})
+
+	var out bytes.Buffer
+	WriteReport(&out, notes)
+	report := out.String()
+
+	for _, want := range []string{
+		"  1. reveal A1   opening",
+		"  2. reveal D1   unnecessary guess (100% risk when a safe move existed) - BOOM",
+		"mistakes: 1",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("WriteReport missing %q in:\n%s", want, report)
+		}
+	}
+}
+
+func TestTimings(t *testing.T) {
+	started := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := Replay{Layout: "...*/..../...*", Started: started}
+	r.Record(reveal(0, 0), started.Add(3*time.Second))
+	r.Record(flag(0, 3), started.Add(4*time.Second))
+	r.Record(reveal(1, 3), started.Add(14*time.Second))
+	notes, _ := Analyze(r, mssolver.Frontier{})
+
+	stats, ok := Timings(notes, 2)
+	if !ok {
+		t.Fatalf("Timings reported an untimed replay")
+	}
+	if stats.Total != 14*time.Second || stats.PerReveal != 6500*time.Millisecond {
+		t.Errorf("Timings wanted 14s total, 6.5s per reveal got %v, %v", stats.Total, stats.PerReveal)
+	}
+	if len(stats.Slowest) != 2 || stats.Slowest[0].Index != 2 || stats.Slowest[1].Index != 0 {
+		t.Errorf("Timings slowest wanted moves 3, 1 got %v", stats.Slowest)
+	}
+
+	r.Times = nil
+	notes, _ = Analyze(r, mssolver.Frontier{})
+	if _, ok = Timings(notes, 2); ok {
+		t.Errorf("Timings reported an untimed replay as timed")
+	}
+}
+
+// estimated : Frontier's probabilities passed off as estimates with a fixed margin on every hidden cell
+type estimated struct{ margin float64 }
+
+func (e estimated) Probabilities(s msboard.Snapshot) ([][]float64, error) {
+	return mssolver.Frontier{}.Probabilities(s)
+}
+
+func (e estimated) Estimate(s msboard.Snapshot) (probabilities, margins [][]float64, err error) {
+	probabilities, err = e.Probabilities(s)
+	margins = make([][]float64, s.Rows)
+	for row := range margins {
+		margins[row] = make([]float64, s.Cols)
+		for col := range margins[row] {
+			if view, _ := s.Cell(msboard.NewLocation(row, col)); view.State == msboard.CellHidden {
+				margins[row][col] = e.margin
+			}
+		}
+	}
+	return probabilities, margins, err
+}
+
+func TestAnalyzeEstimates(t *testing.T) {
+	// D2 is certainly safe, but an estimate of zero risk isn't certainty
+	r := Replay{Layout: "...*/..../...*", Moves: []msboard.Move{reveal(0, 0), reveal(1, 3)}}
+	notes, err := Analyze(r, estimated{0.02})
+	if err != nil {
+		t.Fatalf("Analyze failed: %s", err)
+	}
+	if a := notes[1]; a.Verdict != VerdictGuess || a.Margin != 0.02 || a.SafeMoves != 0 {
+		t.Errorf("wanted an estimated guess with no safe moves got %v, margin %v", a, a.Margin)
+	}
+	if got := notes[1].String(); !strings.Contains(got, "0% ±2%") {
+		t.Errorf("wanted the margin in %q", got)
+	}
+}
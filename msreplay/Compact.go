@@ -0,0 +1,163 @@
+/*
+
+	Compact.go - keeping replays of long games to a bounded size
+
+	A replay is a log of moves played from the empty board. Compacting folds the moves before the last few into a
+	base position, written one character per cell, so the replay starts from there instead; the mine layout is kept
+	in full, so the moves that are left play back and review exactly as before. Limit compacts only as much as it
+	must to bring the saved file under a size.
+
+	mike@pocomotech.com
+
+*/
+
+package msreplay
+
+import (
+	"bytes"
+	"fmt"
+	"go-mines/msboard"
+	"strings"
+	"time"
+)
+
+// Characters of a base position
+const (
+	baseHidden   = '.'
+	baseFlagged  = 'f'
+	baseRevealed = 'o'
+	baseExploded = '*' // a mine revealed, by a losing click or a surrender
+)
+
+// Compact -- the replay with every move but the last keep folded into its base position. Compacted replays can't
+// be regenerated from their draws, since the first click is gone, so the draws are dropped; the mine layout stays
+func (r Replay) Compact(keep int) (Replay, error) {
+	if keep < 0 {
+		keep = 0
+	}
+	cut := len(r.Moves) - keep
+	if cut <= 0 {
+		return r, nil
+	}
+	positions, err := r.Positions()
+	if err != nil {
+		return Replay{}, err
+	}
+	if r.Layout == "" {
+		// the base needs the layout to play back from
+		b, _ := r.Board()
+		r.Layout = b.MineLayout()
+	}
+
+	retval := r
+	retval.Base = baseText(positions[cut])
+	retval.Compacted = r.Compacted + cut
+	retval.Moves = append([]msboard.Move{}, r.Moves[cut:]...)
+	retval.Draws = nil
+	if r.Timed() {
+		// think times stay the same: the last move folded in is when the base position was reached
+		retval.Started = r.Times[cut-1]
+		retval.Times = append([]time.Time{}, r.Times[cut:]...)
+	}
+	return retval, nil
+}
+
+// Limit -- the replay compacted just enough that Write encodes it in at most limit bytes, keeping as many of the
+// last moves as fit. 0 is no limit. An error if even the base position alone is too big
+func (r Replay) Limit(limit int) (Replay, error) {
+	if limit <= 0 {
+		return r, nil
+	}
+	size := func(r Replay) int {
+		var buf bytes.Buffer
+		Write(&buf, r)
+		return buf.Len()
+	}
+	if size(r) <= limit {
+		return r, nil
+	}
+
+	// the fewer moves kept, the smaller the replay: find the most that fit
+	var best Replay
+	found := false
+	low, high := 0, len(r.Moves)-1
+	for low <= high {
+		keep := (low + high) / 2
+		compacted, err := r.Compact(keep)
+		if err != nil {
+			return Replay{}, err
+		}
+		if size(compacted) <= limit {
+			best, found = compacted, true
+			low = keep + 1
+		} else {
+			high = keep - 1
+		}
+	}
+	if !found {
+		return Replay{}, fmt.Errorf("the replay can't be made smaller than %d bytes", limit)
+	}
+	return best, nil
+}
+
+// baseText -- a position as a base: one character per cell, rows separated by '/'
+func baseText(s msboard.Snapshot) string {
+	var sb strings.Builder
+	for row := range s.Cells {
+		if row != 0 {
+			sb.WriteByte('/')
+		}
+		for _, v := range s.Cells[row] {
+			switch v.State {
+			case msboard.CellFlagged:
+				sb.WriteByte(baseFlagged)
+			case msboard.CellRevealed:
+				sb.WriteByte(baseRevealed)
+			case msboard.CellMine:
+				sb.WriteByte(baseExploded)
+			default:
+				sb.WriteByte(baseHidden)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// restoreBase -- put a board holding the replay's mines, nothing revealed yet, into a base position
+func restoreBase(b *msboard.Board, base string) error {
+	// the scores and mines of every cell, as they show once revealed
+	all, err := b.Transform(msboard.TransformIdentity)
+	if err != nil {
+		return err
+	}
+	all.RevealAll()
+	shown := all.Snapshot()
+
+	target := b.Snapshot()
+	lines := strings.Split(base, "/")
+	if len(lines) != b.Rows() {
+		return fmt.Errorf("base position has %d rows, the board %d", len(lines), b.Rows())
+	}
+	for row, line := range lines {
+		if len(line) != b.Cols() {
+			return fmt.Errorf("base position row %d has %d cells, the board %d", row+1, len(line), b.Cols())
+		}
+		for col := range line {
+			switch line[col] {
+			case baseHidden:
+			case baseFlagged:
+				target.Cells[row][col] = msboard.CellView{State: msboard.CellFlagged}
+			case baseRevealed, baseExploded:
+				v := shown.Cells[row][col]
+				if (v.State == msboard.CellMine) != (line[col] == baseExploded) {
+					return fmt.Errorf("base position doesn't match the mines at row %d column %d", row+1, col+1)
+				}
+				target.Cells[row][col] = v
+			default:
+				return fmt.Errorf("unrecognized base position character %q at row %d column %d", line[col], row+1,
+					col+1)
+			}
+		}
+	}
+	return b.Restore(target)
+}
@@ -0,0 +1,125 @@
+package msreplay
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"go-mines/mssolver"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// longReplay -- a timed game of a move a second, flagging and unflagging a mine before clearing the board
+func longReplay(t *testing.T) Replay {
+	b, err := msboard.ParseLayout("..../..../...*/....")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+	started := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := New(b, 1995, started)
+	moves := []msboard.Move{
+		{Type: msboard.MoveReveal, Location: msboard.NewLocation(3, 3)},
+		{Type: msboard.MoveFlag, Location: msboard.NewLocation(2, 3)},
+		{Type: msboard.MoveFlag, Location: msboard.NewLocation(2, 3)},
+		{Type: msboard.MoveFlag, Location: msboard.NewLocation(2, 3)},
+		{Type: msboard.MoveReveal, Location: msboard.NewLocation(0, 0)},
+	}
+	for i, m := range moves {
+		r.Record(m, started.Add(time.Duration(i+1)*time.Second))
+	}
+	return *r
+}
+
+func TestCompact(t *testing.T) {
+	r := longReplay(t)
+	want, err := r.Positions()
+	if err != nil {
+		t.Fatalf("Positions failed: %s", err)
+	}
+
+	compacted, err := r.Compact(2)
+	if err != nil {
+		t.Fatalf("Compact failed: %s", err)
+	}
+	if len(compacted.Moves) != 2 || compacted.Compacted != 3 || compacted.Base != "..../..../..../...o" {
+		t.Fatalf("Compact(2) wanted 2 moves after 3 in base ..../..../..../...o got %d after %d in %q",
+			len(compacted.Moves), compacted.Compacted, compacted.Base)
+	}
+
+	// through JSON and back, the moves left play out the same positions
+	var buf bytes.Buffer
+	Write(&buf, compacted)
+	read, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read of a compacted replay failed: %s", err)
+	}
+	got, err := read.Positions()
+	if err != nil || !reflect.DeepEqual(got, want[3:]) {
+		t.Errorf("compacted positions wanted %v got %v, err %v", want[3:], got, err)
+	}
+	if read.ThinkTime(0) != time.Second || read.ThinkTime(1) != time.Second {
+		t.Errorf("compacted think times wanted 1s, 1s got %v, %v", read.ThinkTime(0), read.ThinkTime(1))
+	}
+
+	// compacting again counts every move folded in, and analysis numbers the moves as they were played
+	again, err := read.Compact(1)
+	if err != nil || again.Compacted != 4 || len(again.Moves) != 1 || again.Base != "..../..../...f/...o" {
+		t.Fatalf("Compact(1) wanted 1 move after 4 in base ..../..../...f/...o got %d after %d in %q, err %v",
+			len(again.Moves), again.Compacted, again.Base, err)
+	}
+	notes, err := Analyze(again, mssolver.Frontier{})
+	if err != nil || len(notes) != 1 || notes[0].Index != 4 {
+		t.Errorf("Analyze of a compacted replay wanted move 5 got %+v, err %v", notes, err)
+	}
+
+	// keeping every move changes nothing
+	if same, _ := r.Compact(5); !reflect.DeepEqual(same, r) {
+		t.Errorf("Compact(5) of 5 moves changed the replay to %+v", same)
+	}
+}
+
+func TestCompactBadBase(t *testing.T) {
+	r := longReplay(t)
+	var cases = []string{
+		"..../..../....",        // too few rows
+		"..../..../...../....",  // a row too long
+		"..../..../...x/....",   // not a cell
+		"o.../..../...o/....",   // the mine shown as safe
+		"*.../..../..../....",   // a safe cell shown as a mine
+		"..../..../..../...3",   // scores aren't kept
+		"..../..../...f/...o/.", // too many rows
+	}
+
+	for _, base := range cases {
+		r.Base = base
+		if _, err := r.Board(); nil == err {
+			t.Errorf("Board() accepted base %q", base)
+		}
+	}
+}
+
+func TestLimit(t *testing.T) {
+	r := longReplay(t)
+	size := func(r Replay) int {
+		var buf bytes.Buffer
+		Write(&buf, r)
+		return buf.Len()
+	}
+
+	if got, err := r.Limit(0); err != nil || !reflect.DeepEqual(got, r) {
+		t.Errorf("Limit(0) changed the replay, err %v", err)
+	}
+	if got, err := r.Limit(size(r)); err != nil || !reflect.DeepEqual(got, r) {
+		t.Errorf("Limit of its own size changed the replay, err %v", err)
+	}
+
+	// a limit just short of the full replay folds in a single move
+	got, err := r.Limit(size(r) - 1)
+	if err != nil || got.Compacted != 1 || size(got) > size(r)-1 {
+		t.Errorf("Limit(%d) wanted 1 move compacted got %d in %d bytes, err %v", size(r)-1, got.Compacted, size(got), err)
+	}
+
+	if _, err := r.Limit(10); nil == err {
+		t.Errorf("Limit(10) wanted an error")
+	}
+}
@@ -0,0 +1,203 @@
+/*
+
+	Diff.go - find where two records of the same game part ways, to track down nondeterminism between engine
+	versions
+
+	mike@pocomotech.com
+
+*/
+
+package msreplay
+
+import (
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"io"
+	"strings"
+)
+
+// diffMargin : cells of context shown around the differing region
+const diffMargin = 2
+
+// Divergence : the first point at which two replays differ. Move is the number of moves both had made, so 0
+// means they start from different layouts
+type Divergence struct {
+	Move   int
+	Reason string
+	Cells  []msboard.Location // cells that differ at that point, in reading order
+	A, B   []string           // each board at that point, one string per row, see grid
+}
+
+// Diff -- replay a and b side by side and report the first move or cell where they differ, nil if they are the
+// same game throughout
+func Diff(a, b Replay) (*Divergence, error) {
+	boardA, err := a.Board()
+	if err != nil {
+		return nil, fmt.Errorf("a: %s", err)
+	}
+	boardB, err := b.Board()
+	if err != nil {
+		return nil, fmt.Errorf("b: %s", err)
+	}
+	if boardA.Rows() != boardB.Rows() || boardA.Cols() != boardB.Cols() {
+		return &Divergence{
+			Reason: fmt.Sprintf("boards are %dx%d and %dx%d", boardA.Rows(), boardA.Cols(), boardB.Rows(), boardB.Cols()),
+			A:      grid(boardA),
+			B:      grid(boardB),
+		}, nil
+	}
+
+	diverge := func(move int, reason string) *Divergence {
+		retval := &Divergence{Move: move, Reason: reason, A: grid(boardA), B: grid(boardB)}
+		for row := range retval.A {
+			for col := range retval.A[row] {
+				if retval.A[row][col] != retval.B[row][col] {
+					retval.Cells = append(retval.Cells, msboard.NewLocation(row, col))
+				}
+			}
+		}
+		return retval
+	}
+
+	if boardA.MineLayout() != boardB.MineLayout() {
+		return diverge(0, "mine layouts differ"), nil
+	}
+	if d := diverge(0, "starting positions differ"); len(d.Cells) > 0 {
+		return d, nil
+	}
+
+	for i := 0; i < len(a.Moves) && i < len(b.Moves); i++ {
+		if a.Moves[i] != b.Moves[i] {
+			return diverge(i, fmt.Sprintf("move %d is %s in a, %s in b", i+1, moveName(a.Moves[i]), moveName(b.Moves[i]))), nil
+		}
+		if _, err = msengine.Apply(boardA, a.Moves[i]); err != nil {
+			return nil, fmt.Errorf("a move %d: %s", i+1, err)
+		}
+		if _, err = msengine.Apply(boardB, b.Moves[i]); err != nil {
+			return nil, fmt.Errorf("b move %d: %s", i+1, err)
+		}
+		if d := diverge(i+1, fmt.Sprintf("move %d, %s, has different results", i+1, moveName(a.Moves[i]))); len(d.Cells) > 0 {
+			return d, nil
+		}
+	}
+
+	if len(a.Moves) != len(b.Moves) {
+		return diverge(len(a.Moves), fmt.Sprintf("a has %d moves, b has %d", len(a.Moves), len(b.Moves))), nil
+	}
+	return nil, nil
+}
+
+// grid -- every cell of a board, hidden state included: '.' hidden, '*' hidden mine, 'F' and 'f' flags on a mine
+// and a safe cell, '_' and '1'-'8' revealed scores, 'X' a revealed mine
+func grid(b *msboard.Board) []string {
+	s := b.Snapshot()
+	retval := make([]string, s.Rows)
+	for row := range retval {
+		var sb strings.Builder
+		for col := 0; col < s.Cols; col++ {
+			l := msboard.NewLocation(row, col)
+			view, _ := s.Cell(l)
+			mine := b.MineAt(l)
+			switch {
+			case view.State == msboard.CellMine:
+				sb.WriteByte('X')
+			case view.State == msboard.CellRevealed && view.Score == 0:
+				sb.WriteByte('_')
+			case view.State == msboard.CellRevealed:
+				sb.WriteByte(byte('0' + view.Score))
+			case view.State == msboard.CellFlagged && mine:
+				sb.WriteByte('F')
+			case view.State == msboard.CellFlagged:
+				sb.WriteByte('f')
+			case mine:
+				sb.WriteByte('*')
+			default:
+				sb.WriteByte('.')
+			}
+		}
+		retval[row] = sb.String()
+	}
+	return retval
+}
+
+// WriteDivergence -- describe a divergence, with the region around the differing cells of both boards side by
+// side and the differing cells marked
+func WriteDivergence(out io.Writer, d *Divergence) error {
+	if nil == d {
+		_, err := fmt.Fprintln(out, "no divergence: both replays are the same game")
+		return err
+	}
+	if _, err := fmt.Fprintf(out, "diverged after %d moves: %s\n", d.Move, d.Reason); err != nil {
+		return err
+	}
+	if len(d.Cells) == 0 {
+		return nil
+	}
+
+	// bounding box of the differences, with some context around it
+	top, left, bottom, right := d.Cells[0].Row(), d.Cells[0].Col(), d.Cells[0].Row(), d.Cells[0].Col()
+	for _, l := range d.Cells {
+		top, bottom = minInt(top, l.Row()), maxInt(bottom, l.Row())
+		left, right = minInt(left, l.Col()), maxInt(right, l.Col())
+	}
+	top, left = maxInt(0, top-diffMargin), maxInt(0, left-diffMargin)
+	bottom, right = minInt(len(d.A)-1, bottom+diffMargin), minInt(len(d.A[0])-1, right+diffMargin)
+
+	differs := make(map[msboard.Location]bool, len(d.Cells))
+	for _, l := range d.Cells {
+		differs[l] = true
+	}
+	width := 2 * (right - left + 1)
+
+	var header strings.Builder
+	for col := left; col <= right; col++ {
+		header.WriteString(fmt.Sprintf("%-2s", columnName(col)))
+	}
+	fmt.Fprintf(out, "\n%4s %-*s   %s\n", "", width, "a", "b")
+	fmt.Fprintf(out, "%4s %-*s   %s\n", "", width, header.String(), header.String())
+	for row := top; row <= bottom; row++ {
+		var sides [2]strings.Builder
+		for side, rows := range [2][]string{d.A, d.B} {
+			for col := left; col <= right; col++ {
+				marker := byte(' ')
+				if differs[msboard.NewLocation(row, col)] {
+					marker = '<'
+				}
+				sides[side].WriteByte(rows[row][col])
+				sides[side].WriteByte(marker)
+			}
+		}
+		if _, err := fmt.Fprintf(out, "%4d %-*s   %s\n", row+1, width, sides[0].String(), sides[1].String()); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(out, "%d cells differ, marked <\n", len(d.Cells))
+	return err
+}
+
+// moveName -- a move as the review shows it, e.g. "reveal C3"
+func moveName(m msboard.Move) string {
+	return fmt.Sprintf("%v %s", m.Type, cellName(m.Location))
+}
+
+// columnName -- column letters as cellName writes them
+func columnName(col int) string {
+	return strings.TrimRight(cellName(msboard.NewLocation(0, col)), "1")
+}
+
+// minInt -- smaller of two ints
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// maxInt -- larger of two ints
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,68 @@
+package msreplay
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"strings"
+	"testing"
+)
+
+// diffReplay -- replay of a fixed layout with the given moves
+func diffReplay(layout string, moves ...msboard.Move) Replay {
+	return Replay{Layout: layout, Moves: moves}
+}
+
+func TestDiff(t *testing.T) {
+	a1 := reveal(0, 0)
+	var cases = []struct {
+		name   string
+		a, b   Replay
+		move   int
+		reason string
+		cells  int
+	}{
+		{"mines", diffReplay("..*/..."), diffReplay(".*./..."), 0, "mine layouts differ", 2},
+		{"moves", diffReplay("..*/...", a1, reveal(1, 2)), diffReplay("..*/...", a1, reveal(1, 0)), 1,
+			"move 2 is reveal C2 in a, reveal A2 in b", 0},
+		{"length", diffReplay("..*/...", a1), diffReplay("..*/...", a1, reveal(1, 2)), 1, "a has 1 moves, b has 2", 0},
+		{"start", diffReplay("..*/..."), diffReplay("_1*/..."), 0, "starting positions differ", 2},
+		{"size", diffReplay("..*/..."), diffReplay("..*/.../..."), 0, "boards are 2x3 and 3x3", 0},
+	}
+
+	for _, testcase := range cases {
+		d, err := Diff(testcase.a, testcase.b)
+		if err != nil {
+			t.Fatalf("%s: Diff failed: %s", testcase.name, err)
+		}
+		if nil == d {
+			t.Errorf("%s: Diff found no divergence", testcase.name)
+			continue
+		}
+		if d.Move != testcase.move || d.Reason != testcase.reason || len(d.Cells) != testcase.cells {
+			t.Errorf("%s: Diff wanted move %d %q with %d cells got %d %q %v", testcase.name, testcase.move,
+				testcase.reason, testcase.cells, d.Move, d.Reason, d.Cells)
+		}
+	}
+
+	same := diffReplay("..*/...", a1, reveal(1, 2))
+	if d, err := Diff(same, same); err != nil || nil != d {
+		t.Errorf("Diff of a replay with itself wanted nothing got %+v, %v", d, err)
+	}
+}
+
+func TestWriteDivergence(t *testing.T) {
+	d, _ := Diff(diffReplay("..*/..."), diffReplay(".*./..."))
+	out := bytes.NewBufferString("")
+	WriteDivergence(out, d)
+	for _, want := range []string{"diverged after 0 moves: mine layouts differ", "A B C", ". .<*<   . *<.<", "2 cells differ"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("WriteDivergence output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	WriteDivergence(out, nil)
+	if !strings.Contains(out.String(), "no divergence") {
+		t.Errorf("WriteDivergence(nil) output unexpected: %s", out.String())
+	}
+}
@@ -0,0 +1,74 @@
+/*
+
+	GIF.go - a replay as an animated GIF, a frame per move, so a finished game can be shared as a short animation
+
+	Frames are drawn by msrender.Image from the positions Positions replays: the board before the first move, then
+	after each one. The last frame is held longer before the animation loops.
+
+	mike@pocomotech.com
+
+*/
+
+package msreplay
+
+import (
+	"fmt"
+	"go-mines/msrender"
+	"image/gif"
+	"io"
+	"time"
+)
+
+// Defaults of the GIF options
+const (
+	DefaultGIFDelay = 500 * time.Millisecond
+	DefaultGIFHold  = 3 * time.Second
+	DefaultGIFCell  = 16
+)
+
+// GIFOptions : how a replay is animated. Zero fields take the defaults
+type GIFOptions struct {
+	Delay    time.Duration // each move is shown for this long
+	Hold     time.Duration // the final position is shown for this long before the animation starts again
+	CellSize int           // pixels per cell, at least msrender.MinImageCell
+}
+
+// WriteGIF -- draw the replay as an animated GIF, looping forever
+func WriteGIF(out io.Writer, r Replay, opts GIFOptions) error {
+	if opts.Delay == 0 {
+		opts.Delay = DefaultGIFDelay
+	}
+	if opts.Hold == 0 {
+		opts.Hold = DefaultGIFHold
+	}
+	if opts.CellSize == 0 {
+		opts.CellSize = DefaultGIFCell
+	}
+	if opts.Delay < 0 || opts.Hold < 0 {
+		return fmt.Errorf("GIF frame times can't be negative")
+	}
+
+	positions, err := r.Positions()
+	if err != nil {
+		return err
+	}
+	animation := &gif.GIF{}
+	for _, s := range positions {
+		frame, err := msrender.Image(s, opts.CellSize)
+		if err != nil {
+			return err
+		}
+		animation.Image = append(animation.Image, frame)
+		animation.Delay = append(animation.Delay, hundredths(opts.Delay))
+	}
+	animation.Delay[len(animation.Delay)-1] = hundredths(opts.Hold)
+	return gif.EncodeAll(out, animation)
+}
+
+// hundredths -- a frame time in the hundredths of a second GIFs count in, at least one
+func hundredths(d time.Duration) int {
+	if retval := int(d / (10 * time.Millisecond)); retval > 0 {
+		return retval
+	}
+	return 1
+}
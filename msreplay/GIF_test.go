@@ -0,0 +1,38 @@
+package msreplay
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"image/gif"
+	"testing"
+	"time"
+)
+
+func TestWriteGIF(t *testing.T) {
+	b, _ := msboard.ParseLayout(".../.../..*")
+	started := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := New(b, 1995, started)
+	r.Record(msboard.Move{Type: msboard.MoveFlag, Location: msboard.NewLocation(2, 2)}, started.Add(time.Second))
+	r.Record(msboard.Move{Type: msboard.MoveReveal, Location: msboard.NewLocation(0, 0)}, started.Add(2*time.Second))
+
+	buf := bytes.NewBuffer(nil)
+	if err := WriteGIF(buf, *r, GIFOptions{Delay: 250 * time.Millisecond, CellSize: 10}); err != nil {
+		t.Fatal(err)
+	}
+	animation, err := gif.DecodeAll(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(animation.Image) != 3 || animation.Config.Width != 30 || animation.Config.Height != 30 {
+		t.Fatalf("wanted 3 frames of 30x30 got %d of %dx%d", len(animation.Image), animation.Config.Width,
+			animation.Config.Height)
+	}
+	if animation.Delay[0] != 25 || animation.Delay[2] != 300 || animation.LoopCount != 0 {
+		t.Errorf("wanted frames of 25 held for 300 looping forever got %v, loop count %d", animation.Delay,
+			animation.LoopCount)
+	}
+
+	if err := WriteGIF(buf, *r, GIFOptions{CellSize: 2}); err == nil {
+		t.Errorf("WriteGIF with 2 pixel cells wanted an error")
+	}
+}
@@ -0,0 +1,79 @@
+/*
+
+	Ghost.go - timed replays played back against the clock, so a player can race a previous game
+
+	mike@pocomotech.com
+
+*/
+
+package msreplay
+
+import (
+	"errors"
+	"go-mines/msboard"
+	"sort"
+	"time"
+)
+
+// Ghost : a timed replay positioned by elapsed time rather than move number
+type Ghost struct {
+	replay    Replay
+	positions []msboard.Snapshot // position before each move, then the final position
+	offsets   []time.Duration    // time of each move from the start of the game
+}
+
+// NewGhost -- prepare a timed replay for playback
+func NewGhost(r Replay) (*Ghost, error) {
+	if !r.Timed() || len(r.Moves) == 0 {
+		return nil, errors.New("replay has no timed moves to race against")
+	}
+	positions, err := r.Positions()
+	if err != nil {
+		return nil, err
+	}
+
+	retval := &Ghost{replay: r, positions: positions, offsets: make([]time.Duration, len(r.Moves))}
+	for i, at := range r.Times {
+		retval.offsets[i] = at.Sub(r.Started)
+	}
+	return retval, nil
+}
+
+// Replay -- the replay being played back
+func (g *Ghost) Replay() Replay {
+	return g.replay
+}
+
+// At -- the ghost's position after the given time has elapsed, and the number of moves it has made
+func (g *Ghost) At(elapsed time.Duration) (msboard.Snapshot, int) {
+	moves := sort.Search(len(g.offsets), func(i int) bool {
+		return g.offsets[i] > elapsed
+	})
+	return g.positions[moves], moves
+}
+
+// Duration -- time the ghost took over the whole game
+func (g *Ghost) Duration() time.Duration {
+	if len(g.offsets) == 0 {
+		return 0
+	}
+	return g.offsets[len(g.offsets)-1]
+}
+
+// Progress -- fraction of a position's safe cells that have been revealed
+func Progress(s msboard.Snapshot) float64 {
+	safe := s.Rows*s.Cols - s.Mines
+	if safe <= 0 {
+		return 0
+	}
+
+	revealed := 0
+	for row := range s.Cells {
+		for _, view := range s.Cells[row] {
+			if view.State == msboard.CellRevealed {
+				revealed++
+			}
+		}
+	}
+	return float64(revealed) / float64(safe)
+}
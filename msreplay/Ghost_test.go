@@ -0,0 +1,45 @@
+package msreplay
+
+import (
+	"go-mines/msboard"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestGhost(t *testing.T) {
+	started := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := Replay{Layout: "...*/..../...*", Started: started}
+	r.Record(reveal(0, 0), started.Add(3*time.Second))
+	r.Record(reveal(1, 3), started.Add(8*time.Second))
+
+	ghost, err := NewGhost(r)
+	if err != nil {
+		t.Fatalf("NewGhost failed: %s", err)
+	}
+	if ghost.Duration() != 8*time.Second {
+		t.Errorf("Duration wanted 8s got %v", ghost.Duration())
+	}
+
+	var cases = []struct {
+		elapsed  time.Duration
+		moves    int
+		progress float64
+	}{
+		{0, 0, 0},
+		{3 * time.Second, 1, 9. / 10},
+		{5 * time.Second, 1, 9. / 10},
+		{time.Minute, 2, 1},
+	}
+	for _, testcase := range cases {
+		position, moves := ghost.At(testcase.elapsed)
+		if moves != testcase.moves || math.Abs(Progress(position)-testcase.progress) > 1e-9 {
+			t.Errorf("At(%v) wanted %d moves, progress %.2f got %d, %.2f", testcase.elapsed, testcase.moves,
+				testcase.progress, moves, Progress(position))
+		}
+	}
+
+	if _, err = NewGhost(Replay{Layout: "..*", Moves: []msboard.Move{reveal(0, 0)}}); nil == err {
+		t.Errorf("NewGhost accepted an untimed replay")
+	}
+}
@@ -0,0 +1,265 @@
+/*
+
+	Replay.go - complete game records that can be stepped through after the fact
+
+	mike@pocomotech.com
+
+*/
+
+// Package msreplay -- game replays: the mine layout a game was played on plus every move in order, stored as
+// JSON, and tools for reviewing them
+package msreplay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Replay : record of one game. Layout holds the mines as generated, before the first move, so a replay doesn't
+// depend on the random number generator that produced it. Draws, when present, holds every random draw the
+// generator made, so the layout can also be regenerated with the Options it was made with; see Regenerate.
+// Times, when present, holds the wall-clock time of each move and Started the time the empty board was first shown.
+// Unrated marks games that weren't played straight by the standard rules, which leaderboards should leave out.
+// Generator is the msboard.GeneratorVersion the board was laid out by, 0 for replays from before versions, which
+// were laid out by version 1; draws only regenerate a layout under the version that made them. Fog is the
+// visibility radius of a game played in the fog, which its header also names
+type Replay struct {
+	Format     msengine.Header          `json:"format"`
+	Difficulty string                   `json:"difficulty"`
+	Seed       int64                    `json:"seed"`
+	Layout     string                   `json:"layout"`
+	Options    msboard.GeneratorOptions `json:"options"`
+	Draws      []int                    `json:"draws,omitempty"`
+	Generator  int                      `json:"generator,omitempty"`
+	Code       string                   `json:"code,omitempty"` // board code of a shareable layout, see msboard.BoardCode
+	Fog        int                      `json:"fog,omitempty"`
+	Started    time.Time                `json:"started"`
+	Moves      []msboard.Move           `json:"moves"`
+	Times      []time.Time              `json:"times,omitempty"`
+	Unrated    bool                     `json:"unrated,omitempty"`   // assisted or variant play, left out of best times
+	Base       string                   `json:"base,omitempty"`      // position the moves start from, see Compact
+	Compacted  int                      `json:"compacted,omitempty"` // moves folded into the base
+}
+
+// New -- start a replay of the game on an initialized board, whose moves are yet to be recorded. started is when
+// the player first saw the board
+func New(b *msboard.Board, seed int64, started time.Time) *Replay {
+	return &Replay{
+		Format:     msengine.HeaderFor(b),
+		Difficulty: b.Difficulty(),
+		Seed:       seed,
+		Layout:     b.MineLayout(),
+		Generator:  msboard.GeneratorVersion,
+		Fog:        b.Fog(),
+		Started:    started,
+	}
+}
+
+// Record -- append a move made at the given time to the replay
+func (r *Replay) Record(m msboard.Move, at time.Time) {
+	r.Moves = append(r.Moves, m)
+	r.Times = append(r.Times, at)
+}
+
+// Timed -- true if every move has a timestamp
+func (r Replay) Timed() bool {
+	return !r.Started.IsZero() && len(r.Times) == len(r.Moves)
+}
+
+// ThinkTime -- time the player took over move i, from the previous move or from the start of the game
+func (r Replay) ThinkTime(i int) time.Duration {
+	if !r.Timed() || i < 0 || i >= len(r.Moves) {
+		return 0
+	}
+	if i == 0 {
+		return r.Times[0].Sub(r.Started)
+	}
+	return r.Times[i].Sub(r.Times[i-1])
+}
+
+// Board -- the starting position, with every mine placed and nothing revealed, or the base position of a compacted
+// replay, played by the rules the game was. Replays recorded without a layout are regenerated from their draws
+func (r Replay) Board() (*msboard.Board, error) {
+	if r.Layout == "" {
+		if len(r.Draws) > 0 {
+			return r.Regenerate()
+		}
+		return nil, errors.New("replay has no layout")
+	}
+	b, err := msboard.ParseTopologyLayout(r.Layout, r.Format.Topology)
+	if err != nil {
+		return nil, fmt.Errorf("replay layout: %s", err)
+	}
+	if err := r.configure(b); err != nil {
+		return nil, err
+	}
+	if r.Base != "" {
+		if err := restoreBase(b, r.Base); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// Regenerate -- lay out the board again by playing the recorded draws back through the generator, around the
+// first move. If the replay also has a layout the two must match
+func (r Replay) Regenerate() (*msboard.Board, error) {
+	if len(r.Draws) == 0 || len(r.Moves) == 0 {
+		return nil, errors.New("replay has no recorded draws to regenerate from")
+	}
+	if generator := r.generator(); generator != msboard.GeneratorVersion {
+		return nil, fmt.Errorf("replay was laid out by generator version %d, its draws can't be regenerated by version %d",
+			generator, msboard.GeneratorVersion)
+	}
+
+	b := msboard.NewBoard(r.Difficulty)
+	if r.Layout != "" {
+		shape, err := msboard.ParseTopologyLayout(r.Layout, r.Format.Topology)
+		if err != nil {
+			return nil, fmt.Errorf("replay layout: %s", err)
+		}
+		b = msboard.NewCustomBoard(shape.Rows(), shape.Cols(), shape.MineCount())
+	}
+	if nil == b {
+		return nil, fmt.Errorf("can't regenerate a %q board without its layout", r.Difficulty)
+	}
+	if err := b.SetTopology(r.Format.Topology); err != nil {
+		return nil, err
+	}
+	if err := r.configure(b); err != nil {
+		return nil, err
+	}
+
+	rng := msboard.NewPlaybackRNG(r.Draws)
+	opts := r.Options
+	opts.RNG = rng
+	if err := b.InitializeWithOptions(r.Moves[0].Location, opts); err != nil {
+		return nil, err
+	}
+	if rng.Remaining() != 0 {
+		return nil, fmt.Errorf("regenerated board left %d recorded draws unused", rng.Remaining())
+	}
+	if r.Layout != "" && b.MineLayout() != r.Layout {
+		return nil, errors.New("regenerated board doesn't match the recorded layout")
+	}
+	return b, nil
+}
+
+// configure -- set a board up to be played as the game was: by the rules the header names, in the fog if it was
+func (r Replay) configure(b *msboard.Board) error {
+	if err := r.Format.Configure(b); err != nil {
+		return err
+	}
+	fogged := false
+	for _, rule := range r.Format.Rules {
+		fogged = fogged || rule == msengine.RuleFog
+	}
+	if fogged != (r.Fog > 0) {
+		return fmt.Errorf("replay fog radius %d doesn't match its header %v", r.Fog, r.Format.Rules)
+	}
+	b.SetFog(r.Fog)
+	return nil
+}
+
+// generator -- the generator version the board was laid out by
+func (r Replay) generator() int {
+	if r.Generator == 0 {
+		return 1
+	}
+	return r.Generator
+}
+
+// Positions -- replay the game, returning the position before each move followed by the final position
+func (r Replay) Positions() ([]msboard.Snapshot, error) {
+	b, err := r.Board()
+	if err != nil {
+		return nil, err
+	}
+
+	retval := make([]msboard.Snapshot, 0, len(r.Moves)+1)
+	retval = append(retval, b.Snapshot())
+	for i, m := range r.Moves {
+		if _, err = msengine.Apply(b, m); err != nil {
+			return nil, fmt.Errorf("replay move %d: %s", i+1, err)
+		}
+		retval = append(retval, b.Snapshot())
+	}
+	return retval, nil
+}
+
+// Write -- encode the replay as indented JSON
+func Write(w io.Writer, r Replay) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// Read -- decode a replay, checking that it can be played back
+func Read(rd io.Reader) (Replay, error) {
+	var retval Replay
+	if err := json.NewDecoder(rd).Decode(&retval); err != nil {
+		return Replay{}, err
+	}
+	if err := retval.Format.Check(); err != nil {
+		return Replay{}, fmt.Errorf("replay: %s", err)
+	}
+	if _, err := retval.Positions(); err != nil {
+		return Replay{}, err
+	}
+	if len(retval.Times) != 0 && len(retval.Times) != len(retval.Moves) {
+		return Replay{}, fmt.Errorf("replay has %d moves but %d timestamps", len(retval.Moves), len(retval.Times))
+	}
+	return retval, nil
+}
+
+// SaveFile -- write the replay to a file, replacing any existing one
+func SaveFile(filename string, r Replay) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err = Write(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadFile -- read a replay from a file
+func LoadFile(filename string) (Replay, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return Replay{}, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// LoadDir -- read every replay file in a directory, in file name order, which for saved games is the order they
+// were played
+func LoadDir(dir string) ([]Replay, error) {
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(filenames)
+
+	retval := make([]Replay, 0, len(filenames))
+	for _, filename := range filenames {
+		r, err := LoadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", filename, err)
+		}
+		retval = append(retval, r)
+	}
+	return retval, nil
+}
@@ -0,0 +1,195 @@
+package msreplay
+
+import (
+	"bytes"
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReplayRoundTrip(t *testing.T) {
+	b, _ := msboard.ParseLayout(".../.../.**")
+	started := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := New(b, 1995, started)
+	r.Record(msboard.Move{Type: msboard.MoveReveal, Location: msboard.NewLocation(0, 0)}, started.Add(2*time.Second))
+	r.Record(msboard.Move{Type: msboard.MoveFlag, Location: msboard.NewLocation(2, 2)}, started.Add(7*time.Second))
+
+	var buf bytes.Buffer
+	if err := Write(&buf, *r); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if got.Seed != 1995 || got.Layout != r.Layout || len(got.Moves) != 2 || got.Moves[1] != r.Moves[1] {
+		t.Errorf("round trip wanted %+v got %+v", *r, got)
+	}
+
+	if !got.Timed() || got.ThinkTime(0) != 2*time.Second || got.ThinkTime(1) != 5*time.Second {
+		t.Errorf("think times wanted 2s, 5s got %v, %v", got.ThinkTime(0), got.ThinkTime(1))
+	}
+
+	positions, err := got.Positions()
+	if err != nil || len(positions) != 3 {
+		t.Fatalf("Positions wanted 3 got %d, err %v", len(positions), err)
+	}
+	if view, _ := positions[2].Cell(msboard.NewLocation(2, 2)); view.State != msboard.CellFlagged {
+		t.Errorf("final position wanted C3 flagged got %v", view)
+	}
+	if view, _ := positions[0].Cell(msboard.NewLocation(0, 0)); view.State != msboard.CellHidden {
+		t.Errorf("starting position wanted A1 hidden got %v", view)
+	}
+}
+
+func TestReadRejectsBadReplay(t *testing.T) {
+	var cases = []string{
+		`{"layout": "", "moves": []}`,
+		`{"format": {"engine": "0.1.0", "variant": "battleship"}, "layout": "..*", "moves": []}`,
+		`{"layout": "..*", "moves": [{"type": "reveal", "location": [4, 0]}]}`,
+		`{"layout": "..*", "moves": [{"type": "defuse", "location": [0, 0]}]}`,
+		`{"layout": "..*", "moves": [{"type": "reveal", "location": [0, 0]}],
+			"times": ["2024-03-01T12:00:00Z", "2024-03-01T12:00:01Z"]}`,
+	}
+
+	for _, text := range cases {
+		if _, err := Read(strings.NewReader(text)); nil == err {
+			t.Errorf("Read(%s) accepted a bad replay", text)
+		}
+	}
+}
+
+func TestReplayRegenerate(t *testing.T) {
+	first := msboard.NewLocation(4, 4)
+	rng := &msboard.RecordingRNG{}
+	b := msboard.NewBoard("easy")
+	b.FirstClickWithOptions(first, msboard.GeneratorOptions{NoEights: true, RNG: rng})
+
+	r := New(b, 0, time.Time{})
+	r.Options, r.Draws = msboard.GeneratorOptions{NoEights: true}, rng.Draws
+	r.Record(msboard.Move{Type: msboard.MoveReveal, Location: first}, time.Time{})
+
+	regenerated, err := r.Regenerate()
+	if err != nil || regenerated.MineLayout() != b.MineLayout() {
+		t.Fatalf("Regenerate wanted the recorded layout got %v", err)
+	}
+
+	// the draws alone are enough to play the replay back, once they've been through JSON
+	var buf bytes.Buffer
+	r.Layout = ""
+	Write(&buf, *r)
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read of a replay without a layout failed: %s", err)
+	}
+	if board, err := got.Board(); err != nil || board.MineLayout() != b.MineLayout() {
+		t.Errorf("Board from draws wanted the recorded layout got %v", err)
+	}
+
+	// draws that don't reproduce the layout are caught
+	r.Layout = b.MineLayout()
+	r.Draws = append([]int{1}, rng.Draws[1:]...)
+	if _, err := r.Regenerate(); err == nil {
+		t.Errorf("Regenerate with altered draws should fail")
+	}
+
+	// replays from before generator versions were laid out by version 1; other versions' draws aren't played back
+	r.Draws = rng.Draws
+	if r.Generator = 0; msboard.GeneratorVersion == 1 {
+		if _, err := r.Regenerate(); err != nil {
+			t.Errorf("Regenerate of an unversioned replay failed: %s", err)
+		}
+	}
+	r.Generator = msboard.GeneratorVersion + 1
+	if _, err := r.Regenerate(); err == nil {
+		t.Errorf("Regenerate accepted draws from generator version %d", r.Generator)
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	first := Replay{Layout: "1*./..*", Moves: []msboard.Move{{Type: msboard.MoveReveal, Location: msboard.NewLocation(1, 0)}}}
+	second := Replay{Layout: "1*/.."}
+	SaveFile(filepath.Join(dir, "replay-2.json"), second)
+	SaveFile(filepath.Join(dir, "replay-1.json"), first)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a replay"), 0644)
+
+	replays, err := LoadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replays) != 2 || replays[0].Layout != first.Layout || replays[1].Layout != second.Layout {
+		t.Errorf("loaded %+v", replays)
+	}
+
+	os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0644)
+	if _, err := LoadDir(dir); err == nil || !strings.Contains(err.Error(), "broken.json") {
+		t.Errorf("broken replay gave %v", err)
+	}
+}
+
+func TestReplayVariant(t *testing.T) {
+	b, _ := msboard.ParseLayout("*../.../...")
+	b.SetRules(msboard.ChordRules{})
+	r := New(b, 1995, time.Now())
+	r.Record(msboard.Move{Type: msboard.MoveReveal, Location: msboard.NewLocation(0, 1)}, time.Now())
+	r.Record(msboard.Move{Type: msboard.MoveFlag, Location: msboard.NewLocation(0, 0)}, time.Now())
+	r.Record(msboard.Move{Type: msboard.MoveChord, Location: msboard.NewLocation(0, 1)}, time.Now())
+
+	var buf bytes.Buffer
+	if err := Write(&buf, *r); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if got.Format.Variant != "chord" {
+		t.Errorf("replay wanted the chord variant got %+v", got.Format)
+	}
+	// the chord clears the board, as it did in the game
+	positions, err := got.Positions()
+	if err != nil || positions[len(positions)-1].Status != msboard.StatusWon {
+		t.Errorf("replayed chord wanted a win, err %v", err)
+	}
+
+	// variants the engine doesn't know are refused rather than played as classic games
+	got.Format.Variant = "chord-with-moving-mines"
+	buf.Reset()
+	Write(&buf, got)
+	if _, err := Read(&buf); nil == err {
+		t.Errorf("Read accepted a replay of an unknown variant")
+	}
+}
+
+func TestReplayFog(t *testing.T) {
+	b, _ := msboard.ParseLayout("*..../...../.....")
+	b.SetFog(1)
+	r := New(b, 1995, time.Now())
+	r.Record(msboard.Move{Type: msboard.MoveReveal, Location: msboard.NewLocation(0, 1)}, time.Now())
+
+	var buf bytes.Buffer
+	if err := Write(&buf, *r); err != nil {
+		t.Fatalf("Write failed: %s", err)
+	}
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read failed: %s", err)
+	}
+	if got.Fog != 1 || len(got.Format.Rules) != 1 || got.Format.Rules[0] != msengine.RuleFog {
+		t.Errorf("fog replay wanted radius 1 and the fog rule got %d, %+v", got.Fog, got.Format)
+	}
+	if replayed, err := got.Board(); err != nil || replayed.Fog() != 1 {
+		t.Errorf("replay board wanted fog 1, err %v", err)
+	}
+
+	// a radius without the rule, or the rule without a radius, isn't played
+	got.Fog = 0
+	if _, err := got.Positions(); nil == err {
+		t.Errorf("replay of a fog game without its radius accepted")
+	}
+}
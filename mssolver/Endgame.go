@@ -0,0 +1,204 @@
+/*
+
+	Endgame.go - exact mine probabilities, weighing every frontier arrangement by the ways the rest of the mines
+	can be spread over the interior
+
+	mike@pocomotech.com
+
+*/
+
+package mssolver
+
+import (
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"math"
+	"time"
+)
+
+// Endgame : exact enumeration that also honors the number of mines left. Frontier counts every arrangement along
+// the frontier equally, but an arrangement using fewer mines leaves more for the interior, which can hold them in
+// more ways; Endgame weighs each arrangement by those ways, so its probabilities are exact. That matters most
+// near the end of a game, where the mine count often settles which arrangement is right, and that's also where
+// the enumeration is cheapest. Like Frontier it gives up with ErrTooComplex on positions too big to enumerate, or
+// that take longer than the Budget, if one is set
+type Endgame struct {
+	Budget time.Duration
+}
+
+// compile time check that Endgame can be used through the engine API
+var _ msengine.Solver = Endgame{}
+
+// mineTally : the arrangements of one component that use a given number of mines
+type mineTally struct {
+	solutions float64
+	counts    []float64 // arrangements with a mine on each cell of the component
+}
+
+// Probabilities -- chance that each cell holds a mine, indexed [row][col], given the revealed scores and the
+// number of mines left. Revealed cells report 0, revealed mines 1
+func (e Endgame) Probabilities(s msboard.Snapshot) ([][]float64, error) {
+	p, retval, err := start(s)
+	if err != nil {
+		return nil, err
+	}
+	if e.Budget > 0 {
+		p.deadline = time.Now().Add(e.Budget)
+	}
+
+	// per component, the arrangements broken down by how many mines they use
+	components := p.components()
+	tallies := make([][]mineTally, len(components))
+	for i, component := range components {
+		if tallies[i], err = p.tally(component); err != nil {
+			return nil, err
+		}
+	}
+	if err = p.combine(s, components, tallies, retval); err != nil {
+		return nil, err
+	}
+	return retval, nil
+}
+
+// tally -- the arrangements of one component, broken down by how many mines they use
+func (p position) tally(component []int) ([]mineTally, error) {
+	retval := make([]mineTally, len(component)+1)
+	err := p.search(component, func(assigned []bool, placed int) bool {
+		t := &retval[placed]
+		if nil == t.counts {
+			t.counts = make([]float64, len(component))
+		}
+		t.solutions++
+		for j, mine := range assigned {
+			if mine {
+				t.counts[j]++
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return retval, nil
+}
+
+// combine -- fill in the probabilities of the components' cells and the interior from the components' tallies,
+// weighing every combination of arrangements by the ways the interior can hold the mines left over
+func (p position) combine(s msboard.Snapshot, components [][]int, tallies [][]mineTally, retval [][]float64) error {
+	// ways to place the remaining mines in the interior for each total on the frontier
+	interiorWays := p.interiorWays()
+	all := convolve(tallies, -1)
+	total := 0.0
+	for m, ways := range all {
+		total += ways * interiorWays[m]
+	}
+	if total == 0 {
+		return ErrInconsistent
+	}
+
+	for i, component := range components {
+		others := convolve(tallies, i)
+		for j, cell := range component {
+			mine, safe := 0.0, 0.0
+			for m, t := range tallies[i] {
+				if t.solutions == 0 {
+					continue
+				}
+				for rest, ways := range others {
+					weight := ways * interiorWays[m+rest]
+					mine += t.counts[j] * weight
+					safe += (t.solutions - t.counts[j]) * weight
+				}
+			}
+			l := p.frontier[cell]
+			retval[l.Row()][l.Col()] = ratio(mine, safe)
+		}
+	}
+
+	if p.interior > 0 {
+		mine, safe := 0.0, 0.0
+		for m, ways := range all {
+			left := float64(p.remaining - m)
+			mine += ways * interiorWays[m] * left
+			safe += ways * interiorWays[m] * (float64(p.interior) - left)
+		}
+		p.fillInterior(s, retval, ratio(mine, safe))
+	}
+	return nil
+}
+
+// Deductions -- cells, on the frontier or off it, that are certainly safe or certainly mined. Positions too
+// complex to enumerate yield no deductions
+func (e Endgame) Deductions(s msboard.Snapshot) (safe, mines []msboard.Location) {
+	probabilities, err := e.Probabilities(s)
+	if err != nil {
+		return nil, nil
+	}
+
+	var candidates []msboard.Location
+	for row := 0; row < s.Rows; row++ {
+		for col := 0; col < s.Cols; col++ {
+			if l := msboard.NewLocation(row, col); unknown(s, l) {
+				candidates = append(candidates, l)
+			}
+		}
+	}
+	return certain(candidates, probabilities)
+}
+
+// interiorWays -- for each number of mines on the frontier, the ways of placing the rest in the interior. Only the
+// ratios matter, so the values are scaled to keep the largest at 1
+func (p position) interiorWays() []float64 {
+	retval := make([]float64, len(p.frontier)+1)
+	logs := make([]float64, len(retval))
+	largest := math.Inf(-1)
+	for m := range retval {
+		left := p.remaining - m
+		if left < 0 || left > p.interior {
+			logs[m] = math.Inf(-1)
+			continue
+		}
+		logs[m] = logChoose(p.interior, left)
+		largest = math.Max(largest, logs[m])
+	}
+	for m := range retval {
+		if !math.IsInf(logs[m], -1) {
+			retval[m] = math.Exp(logs[m] - largest)
+		}
+	}
+	return retval
+}
+
+// convolve -- number of arrangements of every component but skip, by total mines used
+func convolve(tallies [][]mineTally, skip int) []float64 {
+	retval := []float64{1}
+	for i, byMines := range tallies {
+		if i == skip {
+			continue
+		}
+		next := make([]float64, len(retval)+len(byMines)-1)
+		for a, ways := range retval {
+			for b, t := range byMines {
+				next[a+b] += ways * t.solutions
+			}
+		}
+		retval = next
+	}
+	return retval
+}
+
+// logChoose -- natural log of n choose k
+func logChoose(n, k int) float64 {
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+	return a - b - c
+}
+
+// ratio -- mine / (mine + safe), exactly 0 or 1 when either side is zero
+func ratio(mine, safe float64) float64 {
+	if mine+safe == 0 {
+		return 0
+	}
+	return mine / (mine + safe)
+}
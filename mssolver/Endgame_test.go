@@ -0,0 +1,125 @@
+package mssolver
+
+import (
+	"go-mines/msboard"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// bruteForce -- exact probabilities by trying every way of placing the remaining mines among the unknown cells
+func bruteForce(s msboard.Snapshot) [][]float64 {
+	var cells []msboard.Location
+	revealedMines := 0
+	for row := 0; row < s.Rows; row++ {
+		for col := 0; col < s.Cols; col++ {
+			l := msboard.NewLocation(row, col)
+			if unknown(s, l) {
+				cells = append(cells, l)
+			} else if view, _ := s.Cell(l); view.State == msboard.CellMine {
+				revealedMines++
+			}
+		}
+	}
+
+	counts := make(map[msboard.Location]float64)
+	layouts := 0.0
+	for set := 0; set < 1<<uint(len(cells)); set++ {
+		mined := make(map[msboard.Location]bool)
+		for i, l := range cells {
+			if set&(1<<uint(i)) != 0 {
+				mined[l] = true
+			}
+		}
+		if len(mined) != s.Mines-revealedMines || !fits(s, mined) {
+			continue
+		}
+		layouts++
+		for l := range mined {
+			counts[l]++
+		}
+	}
+
+	retval := make([][]float64, s.Rows)
+	for row := range retval {
+		retval[row] = make([]float64, s.Cols)
+		for col := range retval[row] {
+			l := msboard.NewLocation(row, col)
+			if view, _ := s.Cell(l); view.State == msboard.CellMine {
+				retval[row][col] = 1
+			} else if unknown(s, l) {
+				retval[row][col] = counts[l] / layouts
+			}
+		}
+	}
+	return retval
+}
+
+// fits -- true if every revealed score matches the mines around it
+func fits(s msboard.Snapshot, mined map[msboard.Location]bool) bool {
+	for row := 0; row < s.Rows; row++ {
+		for col := 0; col < s.Cols; col++ {
+			view, _ := s.Cell(msboard.NewLocation(row, col))
+			if view.State != msboard.CellRevealed {
+				continue
+			}
+			around := 0
+			for _, n := range neighbors(s, msboard.NewLocation(row, col)) {
+				if nv, _ := s.Cell(n); mined[n] || nv.State == msboard.CellMine {
+					around++
+				}
+			}
+			if around != view.Score {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestEndgameExact(t *testing.T) {
+	differs := 0
+	for seed := int64(1); seed <= 40; seed++ {
+		rand.Seed(seed)
+		b := msboard.NewCustomBoard(4, 4, 5)
+		b.FirstClick(msboard.NewLocation(rand.Intn(4), rand.Intn(4)))
+		s := b.Snapshot()
+		if s.Status != msboard.StatusPlaying {
+			continue
+		}
+
+		want := bruteForce(s)
+		got, err := Endgame{}.Probabilities(s)
+		if err != nil {
+			t.Fatalf("seed %d: Probabilities failed: %s", seed, err)
+		}
+		frontier, _ := Frontier{}.Probabilities(s)
+		for row := range want {
+			for col := range want[row] {
+				if math.Abs(got[row][col]-want[row][col]) > 1e-9 {
+					t.Errorf("seed %d: %d,%d wanted %.4f got %.4f", seed, row, col, want[row][col], got[row][col])
+				}
+				if math.Abs(frontier[row][col]-want[row][col]) > 1e-9 {
+					differs++
+				}
+			}
+		}
+	}
+
+	// the point of the mine count: frontier only weighting gets some of these positions wrong
+	if differs == 0 {
+		t.Errorf("expected frontier only probabilities to differ from the exact ones somewhere")
+	}
+}
+
+func TestEndgameDeductions(t *testing.T) {
+	// the only mine is one of the three cells next to the 1, so the four cells away from it are all safe
+	b, _ := msboard.ParseLayout("1*../....")
+	safe, mines := Endgame{}.Deductions(b.Snapshot())
+	if len(mines) != 0 || len(safe) != 4 {
+		t.Errorf("Deductions wanted the four interior cells safe got safe %v, mines %v", safe, mines)
+	}
+	if safe, _ = (Frontier{}).Deductions(b.Snapshot()); len(safe) != 0 {
+		t.Errorf("Frontier only deduces frontier cells, got %v", safe)
+	}
+}
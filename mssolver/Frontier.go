@@ -0,0 +1,359 @@
+/*
+
+	Frontier.go - mine probabilities by enumerating arrangements along the revealed frontier
+
+	mike@pocomotech.com
+
+*/
+
+// Package mssolver -- solvers that reason about a position using only the player-visible Snapshot, so they can
+// never peek at hidden mines
+package mssolver
+
+import (
+	"errors"
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"time"
+)
+
+// ErrTooComplex : the frontier has too many possible mine arrangements to enumerate
+var ErrTooComplex = errors.New("position too complex to enumerate")
+
+// ErrInconsistent : no mine arrangement fits the revealed scores
+var ErrInconsistent = errors.New("position has no consistent mine arrangement")
+
+// ErrRules : the position is played under rules the solver doesn't know, such as anti-mines; see Signed
+var ErrRules = errors.New("position uses anti-mines, which only the signed solver handles")
+
+// maxSearchNodes -- backtracking steps allowed for each independent part of the frontier
+const maxSearchNodes = 2000000
+
+// deadlineCheck -- backtracking steps between looks at the clock, for searches with a deadline
+const deadlineCheck = 1024
+
+// Frontier : exact enumeration of the mine arrangements along the frontier, the hidden cells next to revealed
+// scores. Every arrangement that fits the scores counts equally; cells away from the frontier share the mines
+// left over. Flags are ignored, since the player's flags may be wrong
+type Frontier struct{}
+
+// compile time check that Frontier can be used through the engine API
+var _ msengine.Solver = Frontier{}
+
+// constraint : a revealed score and the unknown cells around it
+type constraint struct {
+	cells []int // indices into the frontier
+	need  int   // mines still to be placed among cells
+}
+
+// position : the unknowns of a snapshot, split into frontier cells and constraints over them
+type position struct {
+	frontier    []msboard.Location
+	constraints []constraint
+	interior    int       // unknown cells not touching any revealed score
+	remaining   int       // mines not yet revealed
+	deadline    time.Time // enumeration gives up with ErrTooComplex after this, if set
+}
+
+// Probabilities -- chance that each cell holds a mine, indexed [row][col]. Revealed cells report 0, revealed
+// mines 1
+func (Frontier) Probabilities(s msboard.Snapshot) ([][]float64, error) {
+	p, retval, err := start(s)
+	if err != nil {
+		return nil, err
+	}
+
+	frontierMines := 0.0
+	for _, component := range p.components() {
+		counts, solutions, expected, err := p.enumerate(component)
+		if err != nil {
+			return nil, err
+		}
+		for i, cell := range component {
+			l := p.frontier[cell]
+			retval[l.Row()][l.Col()] = float64(counts[i]) / float64(solutions)
+		}
+		frontierMines += expected
+	}
+
+	// the interior shares whatever the frontier is expected to leave
+	if p.interior > 0 {
+		p.fillInterior(s, retval, (float64(p.remaining)-frontierMines)/float64(p.interior))
+	}
+
+	return retval, nil
+}
+
+// Deductions -- frontier cells that are certainly safe or certainly mined. Positions too complex to enumerate
+// yield no deductions
+func (f Frontier) Deductions(s msboard.Snapshot) (safe, mines []msboard.Location) {
+	probabilities, err := f.Probabilities(s)
+	if err != nil {
+		return nil, nil
+	}
+	return certain(newPosition(s).frontier, probabilities)
+}
+
+// start -- the position of a snapshot, and probabilities filled in for its revealed cells. ErrRules for positions
+// with anti-mines, whose scores the enumeration can't read
+func start(s msboard.Snapshot) (position, [][]float64, error) {
+	if s.AntiMines > 0 {
+		return position{}, nil, ErrRules
+	}
+	p := newPosition(s)
+	for _, c := range p.constraints {
+		if len(c.cells) == 0 {
+			return p, nil, ErrInconsistent // a score with the wrong number of revealed mines around it
+		}
+	}
+
+	retval := make([][]float64, s.Rows)
+	for row := range retval {
+		retval[row] = make([]float64, s.Cols)
+		for col := range retval[row] {
+			if view, _ := s.Cell(msboard.NewLocation(row, col)); view.State == msboard.CellMine {
+				retval[row][col] = 1
+			}
+		}
+	}
+	return p, retval, nil
+}
+
+// fillInterior -- set the probability of every unknown cell off the frontier, clamped to [0,1]
+func (p position) fillInterior(s msboard.Snapshot, probabilities [][]float64, interior float64) {
+	if interior < 0 {
+		interior = 0
+	} else if interior > 1 {
+		interior = 1
+	}
+	onFrontier := make(map[msboard.Location]bool, len(p.frontier))
+	for _, l := range p.frontier {
+		onFrontier[l] = true
+	}
+	for row := range probabilities {
+		for col := range probabilities[row] {
+			l := msboard.NewLocation(row, col)
+			if unknown(s, l) && !onFrontier[l] {
+				probabilities[row][col] = interior
+			}
+		}
+	}
+}
+
+// certain -- the cells among candidates with probability exactly 0 or 1
+func certain(candidates []msboard.Location, probabilities [][]float64) (safe, mines []msboard.Location) {
+	for _, l := range candidates {
+		switch probabilities[l.Row()][l.Col()] {
+		case 0:
+			safe = append(safe, l)
+		case 1:
+			mines = append(mines, l)
+		}
+	}
+	return safe, mines
+}
+
+// unknown -- true for cells whose contents the player can't see
+func unknown(s msboard.Snapshot, l msboard.Location) bool {
+	view, ok := s.Cell(l)
+	return ok && (view.State == msboard.CellHidden || view.State == msboard.CellFlagged)
+}
+
+// neighbors -- on-board locations around l in the snapshot's topology
+func neighbors(s msboard.Snapshot, l msboard.Location) []msboard.Location {
+	return s.Neighbors(l)
+}
+
+// newPosition -- collect the frontier, constraints and counts from a snapshot
+func newPosition(s msboard.Snapshot) position {
+	var retval position
+	index := make(map[msboard.Location]int)
+	unknownCount, revealedMines := 0, 0
+
+	for row := 0; row < s.Rows; row++ {
+		for col := 0; col < s.Cols; col++ {
+			l := msboard.NewLocation(row, col)
+			view, _ := s.Cell(l)
+			switch {
+			case unknown(s, l):
+				unknownCount++
+				continue
+			case view.State == msboard.CellMine:
+				revealedMines++
+				continue
+			}
+
+			c := constraint{need: view.Score}
+			for _, n := range neighbors(s, l) {
+				if nv, _ := s.Cell(n); nv.State == msboard.CellMine {
+					c.need--
+				} else if unknown(s, n) {
+					i, seen := index[n]
+					if !seen {
+						i = len(retval.frontier)
+						index[n] = i
+						retval.frontier = append(retval.frontier, n)
+					}
+					c.cells = append(c.cells, i)
+				}
+			}
+			if len(c.cells) > 0 || c.need != 0 {
+				retval.constraints = append(retval.constraints, c)
+			}
+		}
+	}
+
+	retval.interior = unknownCount - len(retval.frontier)
+	retval.remaining = s.Mines - revealedMines
+	return retval
+}
+
+// components -- split the frontier into groups of cells that share no constraints, so each can be enumerated
+// on its own
+func (p position) components() [][]int {
+	parent := make([]int, len(p.frontier))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for _, c := range p.constraints {
+		for _, cell := range c.cells[1:] {
+			parent[find(cell)] = find(c.cells[0])
+		}
+	}
+
+	groups := make(map[int]int)
+	retval := make([][]int, 0)
+	for i := range p.frontier {
+		root := find(i)
+		g, ok := groups[root]
+		if !ok {
+			g = len(retval)
+			groups[root] = g
+			retval = append(retval, nil)
+		}
+		retval[g] = append(retval[g], i)
+	}
+	return retval
+}
+
+// enumerate -- count the arrangements of one component that satisfy every score, returning how many of them
+// place a mine on each cell, the number of arrangements and the mean number of mines they use
+func (p position) enumerate(component []int) (counts []int64, solutions int64, expected float64, err error) {
+	counts = make([]int64, len(component))
+	mineTotal := int64(0)
+	err = p.search(component, func(assigned []bool, placed int) bool {
+		if placed > p.remaining || p.remaining-placed > p.interior+p.otherFrontier(len(component)) {
+			return true
+		}
+		solutions++
+		mineTotal += int64(placed)
+		for j, mine := range assigned {
+			if mine {
+				counts[j]++
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if solutions == 0 {
+		return nil, 0, 0, ErrInconsistent
+	}
+	return counts, solutions, float64(mineTotal) / float64(solutions), nil
+}
+
+// search -- backtrack over the arrangements of one component, calling found with every arrangement that satisfies
+// the scores and the number of mines it places, until found returns false
+func (p position) search(component []int, found func(assigned []bool, placed int) bool) error {
+	local := make(map[int]int, len(component))
+	for i, cell := range component {
+		local[cell] = i
+	}
+
+	// constraints touching this component, and per cell the constraints it appears in
+	type tally struct {
+		need, mines, open int
+	}
+	tallies := make([]tally, 0)
+	watching := make([][]int, len(component))
+	for _, c := range p.constraints {
+		if len(c.cells) == 0 {
+			continue
+		}
+		if _, ok := local[c.cells[0]]; !ok {
+			continue
+		}
+		for _, cell := range c.cells {
+			watching[local[cell]] = append(watching[local[cell]], len(tallies))
+		}
+		tallies = append(tallies, tally{need: c.need, open: len(c.cells)})
+	}
+
+	assigned := make([]bool, len(component))
+	nodes, placed, stopped := 0, 0, false
+
+	var search func(i int) bool
+	search = func(i int) bool {
+		if nodes++; nodes > maxSearchNodes {
+			return false
+		}
+		if nodes%deadlineCheck == 0 && !p.deadline.IsZero() && time.Now().After(p.deadline) {
+			return false
+		}
+		if i == len(component) {
+			stopped = !found(assigned, placed)
+			return !stopped
+		}
+
+		for _, mine := range [...]bool{false, true} {
+			ok := true
+			for _, t := range watching[i] {
+				tallies[t].open--
+				if mine {
+					tallies[t].mines++
+				}
+				if tallies[t].mines > tallies[t].need || tallies[t].mines+tallies[t].open < tallies[t].need {
+					ok = false
+				}
+			}
+			if mine {
+				placed++
+			}
+			assigned[i] = mine
+
+			if ok && !search(i+1) {
+				return false
+			}
+
+			if mine {
+				placed--
+			}
+			for _, t := range watching[i] {
+				tallies[t].open++
+				if mine {
+					tallies[t].mines--
+				}
+			}
+		}
+		assigned[i] = false
+		return true
+	}
+
+	if !search(0) && !stopped {
+		return ErrTooComplex
+	}
+	return nil
+}
+
+// otherFrontier -- frontier cells outside a component of the given size, which could also hold leftover mines
+func (p position) otherFrontier(componentSize int) int {
+	return len(p.frontier) - componentSize
+}
@@ -0,0 +1,63 @@
+package mssolver
+
+import (
+	"go-mines/msboard"
+	"math"
+	"testing"
+)
+
+func TestFrontierProbabilities(t *testing.T) {
+	var cases = []struct {
+		layout string
+		want   [][]float64
+	}{
+		// single certain mine, the rest of the board must be safe
+		{"1*.", [][]float64{{0, 1, 0}}},
+		// one mine among three frontier cells, interior cells get what's left
+		{"1*./...", [][]float64{{0, 1. / 3, 0}, {1. / 3, 1. / 3, 0}}},
+		{"1*./..*", [][]float64{{0, 1. / 3, 0.5}, {1. / 3, 1. / 3, 0.5}}},
+		// fully determined by overlapping constraints
+		{"__1*/__2./__1*", [][]float64{{0, 0, 0, 1}, {0, 0, 0, 0}, {0, 0, 0, 1}}},
+	}
+
+	for _, testcase := range cases {
+		b, err := msboard.ParseLayout(testcase.layout)
+		if err != nil {
+			t.Fatalf("ParseLayout(%q) failed: %s", testcase.layout, err)
+		}
+		got, err := Frontier{}.Probabilities(b.Snapshot())
+		if err != nil {
+			t.Errorf("Probabilities(%q) failed: %s", testcase.layout, err)
+			continue
+		}
+		for row := range testcase.want {
+			for col, want := range testcase.want[row] {
+				if math.Abs(got[row][col]-want) > 1e-9 {
+					t.Errorf("Probabilities(%q) at %d,%d wanted %.3f got %.3f", testcase.layout, row, col, want, got[row][col])
+				}
+			}
+		}
+	}
+}
+
+func TestFrontierDeductions(t *testing.T) {
+	b, _ := msboard.ParseLayout("__1*/__2./__1*")
+	safe, mines := Frontier{}.Deductions(b.Snapshot())
+
+	if len(safe) != 1 || safe[0] != msboard.NewLocation(1, 3) {
+		t.Errorf("Deductions safe wanted [D2] got %v", safe)
+	}
+	if len(mines) != 2 {
+		t.Errorf("Deductions mines wanted D1 and D3 got %v", mines)
+	}
+}
+
+func TestFrontierInconsistent(t *testing.T) {
+	// a 2 with only one unknown neighbor can't be satisfied
+	s := msboard.Snapshot{Rows: 1, Cols: 2, Mines: 1, Cells: [][]msboard.CellView{
+		{{State: msboard.CellRevealed, Score: 2}, {State: msboard.CellHidden}},
+	}}
+	if _, err := (Frontier{}).Probabilities(s); err != ErrInconsistent {
+		t.Errorf("Probabilities wanted ErrInconsistent got %v", err)
+	}
+}
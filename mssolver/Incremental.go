@@ -0,0 +1,191 @@
+/*
+
+	Incremental.go - the Subset solver's deductions kept up to date as a game is played, for hints that are
+	cheap enough to offer after every move
+
+	mike@pocomotech.com
+
+*/
+
+package mssolver
+
+import (
+	"go-mines/msboard"
+	"go-mines/msengine"
+)
+
+// pairReach -- scores further apart than this, in rows or columns, share no neighbors
+const pairReach = 2
+
+// Incremental : Subset deductions maintained move by move. Pass it as the EngineEvents of ApplyWithEvents and it
+// reconsiders only the scores around cells that changed, where Subset starts over from every score on the board.
+// Deductions already made stay made, so the cost of a move is proportional to what it revealed
+type Incremental struct {
+	s       msboard.Snapshot          // the position as of the last event
+	known   map[msboard.Location]bool // deduced cells not yet revealed, true for a mine
+	rules   map[msboard.Location]rule // current rule of every score with unknown neighbors left
+	pending map[msboard.Location]bool // scores whose rules must be rebuilt before the next answer
+	changed map[msboard.Location]bool // rules rebuilt since pairs including them were last compared
+}
+
+// compile time check that Incremental can follow a game through the engine's event hooks
+var _ msengine.EngineEvents = (*Incremental)(nil)
+
+// NewIncremental -- start following a game from the position s, which may be before or after the first click
+func NewIncremental(s msboard.Snapshot) *Incremental {
+	retval := &Incremental{
+		s:       s,
+		known:   make(map[msboard.Location]bool),
+		rules:   make(map[msboard.Location]rule),
+		pending: make(map[msboard.Location]bool),
+		changed: make(map[msboard.Location]bool),
+	}
+
+	// a private copy, since events update it in place
+	retval.s.Cells = make([][]msboard.CellView, len(s.Cells))
+	for row := range s.Cells {
+		retval.s.Cells[row] = append([]msboard.CellView(nil), s.Cells[row]...)
+		for col := range s.Cells[row] {
+			retval.pending[msboard.NewLocation(row, col)] = true
+		}
+	}
+	return retval
+}
+
+// OnReveal -- a safe cell was revealed, which changes its own rule and its neighbors'
+func (inc *Incremental) OnReveal(l msboard.Location, v msboard.CellView) {
+	inc.set(l, v)
+	inc.touch(l)
+}
+
+// OnFlag -- a flag was placed or removed. Flags may be wrong, so no rule changes
+func (inc *Incremental) OnFlag(l msboard.Location, flagged bool) {
+	state := msboard.CellHidden
+	if flagged {
+		state = msboard.CellFlagged
+	}
+	inc.set(l, msboard.CellView{State: state})
+}
+
+// OnExplode -- a mine was revealed
+func (inc *Incremental) OnExplode(l msboard.Location) {
+	inc.set(l, msboard.CellView{State: msboard.CellMine})
+	inc.s.Status = msboard.StatusLost
+	inc.touch(l)
+}
+
+// OnWin -- the game was won
+func (inc *Incremental) OnWin() {
+	inc.s.Status = msboard.StatusWon
+}
+
+// Deductions -- cells that follow as safe or mined from single scores and pairs of scores, in reading order;
+// the same cells Subset would find in the current position, so none under the anti-mine rules
+func (inc *Incremental) Deductions() (safe, mines []msboard.Location) {
+	if inc.s.AntiMines > 0 {
+		return nil, nil
+	}
+	inc.update()
+	for l, mine := range inc.known {
+		if mine {
+			mines = append(mines, l)
+		} else {
+			safe = append(safe, l)
+		}
+	}
+	sortLocations(safe)
+	sortLocations(mines)
+	return safe, mines
+}
+
+// set -- record the new view of a cell; a revealed cell is no longer a pending deduction
+func (inc *Incremental) set(l msboard.Location, v msboard.CellView) {
+	if _, ok := inc.s.Cell(l); !ok {
+		return
+	}
+	inc.s.Cells[l.Row()][l.Col()] = v
+	if v.State == msboard.CellRevealed || v.State == msboard.CellMine {
+		delete(inc.known, l)
+	}
+}
+
+// touch -- mark the rules of l and its neighbors for rebuilding
+func (inc *Incremental) touch(l msboard.Location) {
+	inc.pending[l] = true
+	for _, n := range neighbors(inc.s, l) {
+		inc.pending[n] = true
+	}
+}
+
+// update -- rebuild the pending rules and follow the consequences until nothing new is deduced, the same fixed
+// point deduce reaches from scratch
+func (inc *Incremental) update() {
+	mark := func(cells map[msboard.Location]bool, mine bool) bool {
+		for l := range cells {
+			inc.known[l] = mine
+			inc.touch(l)
+		}
+		return len(cells) > 0
+	}
+
+	for len(inc.pending) > 0 || len(inc.changed) > 0 {
+		for l := range inc.pending {
+			if r, ok := ruleAt(inc.s, l, inc.known); ok {
+				inc.rules[l] = r
+				inc.changed[l] = true
+			} else {
+				delete(inc.rules, l)
+				delete(inc.changed, l)
+			}
+			delete(inc.pending, l)
+		}
+
+		progress := false
+		for l := range inc.changed {
+			switch r := inc.rules[l]; {
+			case r.need == 0:
+				progress = mark(r.cells, false) || progress
+			case r.need == len(r.cells):
+				progress = mark(r.cells, true) || progress
+			}
+		}
+		if progress {
+			continue
+		}
+
+		// pairs where neither rule changed were compared before and gave nothing
+		for l := range inc.changed {
+			for row := l.Row() - pairReach; row <= l.Row()+pairReach && !progress; row++ {
+				for col := l.Col() - pairReach; col <= l.Col()+pairReach && !progress; col++ {
+					other, ok := inc.rules[msboard.NewLocation(row, col)]
+					if !ok || msboard.NewLocation(row, col) == l {
+						continue
+					}
+					a, b := inc.rules[l], other
+					if len(a.cells) > len(b.cells) {
+						a, b = b, a
+					}
+					if len(a.cells) == len(b.cells) || !contains(b.cells, a.cells) {
+						continue
+					}
+					rest := make(map[msboard.Location]bool, len(b.cells)-len(a.cells))
+					for c := range b.cells {
+						if !a.cells[c] {
+							rest[c] = true
+						}
+					}
+					switch b.need - a.need {
+					case 0:
+						progress = mark(rest, false)
+					case len(rest):
+						progress = mark(rest, true)
+					}
+				}
+			}
+			if progress {
+				break
+			}
+			delete(inc.changed, l)
+		}
+	}
+}
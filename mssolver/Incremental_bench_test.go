@@ -0,0 +1,63 @@
+/*
+	Benchmarks for keeping hints current during play. Run with
+
+		go test -run - -bench PerMove ./mssolver/
+
+	Each benchmark replays the same expert game, applying one move and then asking for the deductions, the work
+	behind offering a hint after every move
+
+	mike@pocomotech.com
+*/
+
+package mssolver
+
+import (
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"math/rand"
+	"testing"
+)
+
+// benchmarkGame -- an expert game with its first click, and the moves that finish it
+func benchmarkGame(bm *testing.B) (*msboard.Board, msboard.Location, []msboard.Move) {
+	rand.Seed(3)
+	b := msboard.NewBoard("hard")
+	first := msboard.NewLocation(8, 15)
+	if _, err := b.FirstClick(first); err != nil {
+		bm.Fatal(err)
+	}
+	return b, first, playedMoves(b, rand.New(rand.NewSource(3)))
+}
+
+func BenchmarkSubsetPerMove(bm *testing.B) {
+	b, first, moves := benchmarkGame(bm)
+	bm.ResetTimer()
+	for i := 0; i < bm.N; i++ {
+		if i%len(moves) == 0 {
+			bm.StopTimer()
+			b.Reset()
+			b.Click(first)
+			bm.StartTimer()
+		}
+		msengine.Apply(b, moves[i%len(moves)])
+		Subset{}.Deductions(b.Snapshot())
+	}
+}
+
+func BenchmarkIncrementalPerMove(bm *testing.B) {
+	b, first, moves := benchmarkGame(bm)
+	var inc *Incremental
+	bm.ResetTimer()
+	for i := 0; i < bm.N; i++ {
+		if i%len(moves) == 0 {
+			bm.StopTimer()
+			b.Reset()
+			b.Click(first)
+			inc = NewIncremental(b.Snapshot())
+			inc.Deductions()
+			bm.StartTimer()
+		}
+		msengine.ApplyWithEvents(b, moves[i%len(moves)], inc)
+		inc.Deductions()
+	}
+}
@@ -0,0 +1,80 @@
+package mssolver
+
+import (
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// playedMoves -- a whole game on b from its first click, revealing one deduced safe cell per move, flagging
+// deduced mines now and then and, when nothing is deduced, revealing a safe cell as a lucky guess would
+func playedMoves(b *msboard.Board, rng *rand.Rand) []msboard.Move {
+	var retval []msboard.Move
+	for b.Status() == msboard.StatusPlaying {
+		safe, mines := Subset{}.Deductions(b.Snapshot())
+		m := msboard.Move{Type: msboard.MoveReveal}
+		switch {
+		case len(mines) > 0 && rng.Intn(4) == 0:
+			m = msboard.Move{Type: msboard.MoveFlag, Location: mines[rng.Intn(len(mines))]}
+		case len(safe) > 0:
+			m.Location = safe[rng.Intn(len(safe))]
+		default:
+			for {
+				m.Location = msboard.NewLocation(rng.Intn(b.Rows()), rng.Intn(b.Cols()))
+				if view, _ := b.Snapshot().Cell(m.Location); !b.MineAt(m.Location) && view.State == msboard.CellHidden {
+					break
+				}
+			}
+		}
+		msengine.Apply(b, m)
+		retval = append(retval, m)
+	}
+	return retval
+}
+
+func TestIncrementalMatchesSubset(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		rand.Seed(seed)
+		b := msboard.NewBoard("hard")
+		first := msboard.NewLocation(8, 15)
+		b.FirstClick(first)
+		moves := playedMoves(b, rand.New(rand.NewSource(seed)))
+
+		// follow the same game from before the first click
+		b.Reset()
+		inc := NewIncremental(b.Snapshot())
+		for i, m := range append([]msboard.Move{{Type: msboard.MoveReveal, Location: first}}, moves...) {
+			if _, err := msengine.ApplyWithEvents(b, m, inc); err != nil {
+				t.Fatalf("seed %d move %d: %s", seed, i, err)
+			}
+			wantSafe, wantMines := Subset{}.Deductions(b.Snapshot())
+			gotSafe, gotMines := inc.Deductions()
+			if !reflect.DeepEqual(gotSafe, wantSafe) || !reflect.DeepEqual(gotMines, wantMines) {
+				t.Fatalf("seed %d move %d (%v): wanted %v, %v got %v, %v", seed, i, m, wantSafe, wantMines,
+					gotSafe, gotMines)
+			}
+		}
+	}
+}
+
+func TestIncrementalFromPosition(t *testing.T) {
+	b, err := msboard.ParseLayout("__1*/__2./__1*")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+	inc := NewIncremental(b.Snapshot())
+	safe, mines := inc.Deductions()
+	if len(safe) != 1 || len(mines) != 2 {
+		t.Fatalf("wanted 1 safe, 2 mines got %v, %v", safe, mines)
+	}
+
+	// revealing the safe cell uses up the deduction
+	if _, err := msengine.ApplyWithEvents(b, msboard.Move{Type: msboard.MoveReveal, Location: safe[0]}, inc); err != nil {
+		t.Fatal(err)
+	}
+	if safe, _ := inc.Deductions(); len(safe) != 0 {
+		t.Errorf("wanted no safe cells after the reveal got %v", safe)
+	}
+}
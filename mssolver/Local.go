@@ -0,0 +1,163 @@
+/*
+
+	Local.go - fast solvers that reason from one or two revealed scores at a time
+
+	mike@pocomotech.com
+
+*/
+
+package mssolver
+
+import (
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"sort"
+)
+
+// Counting : single cell deductions. A score whose mines are all accounted for makes its other hidden neighbors
+// safe, and a score with exactly as many hidden neighbors as missing mines makes them all mines. Repeated until
+// nothing more follows. Fast, but blind to anything needing two scores together
+type Counting struct{}
+
+// Subset : Counting plus deductions from pairs of scores where one's hidden neighbors include all of the other's,
+// which covers the classic 1-1 and 1-2-1 patterns. Still far cheaper than Frontier, which it can't match on
+// positions that need whole-frontier reasoning
+type Subset struct{}
+
+// compile time checks that the local solvers can be used through the engine API
+var (
+	_ msengine.Solver = Counting{}
+	_ msengine.Solver = Subset{}
+)
+
+// Deductions -- cells that follow as safe or mined from single scores
+func (Counting) Deductions(s msboard.Snapshot) (safe, mines []msboard.Location) {
+	return deduce(s, false)
+}
+
+// Deductions -- cells that follow as safe or mined from single scores and pairs of scores
+func (Subset) Deductions(s msboard.Snapshot) (safe, mines []msboard.Location) {
+	return deduce(s, true)
+}
+
+// rule : a revealed score restricted to the neighbors not yet known, with the mines still missing among them
+type rule struct {
+	cells map[msboard.Location]bool
+	need  int
+}
+
+// rules -- one rule per revealed score with unknown neighbors, given what has been deduced so far
+func rules(s msboard.Snapshot, known map[msboard.Location]bool) []rule {
+	var retval []rule
+	for row := 0; row < s.Rows; row++ {
+		for col := 0; col < s.Cols; col++ {
+			if r, ok := ruleAt(s, msboard.NewLocation(row, col), known); ok {
+				retval = append(retval, r)
+			}
+		}
+	}
+	return retval
+}
+
+// ruleAt -- the rule for the score at l, false if l isn't a revealed score or has no unknown neighbors left
+func ruleAt(s msboard.Snapshot, l msboard.Location, known map[msboard.Location]bool) (rule, bool) {
+	view, _ := s.Cell(l)
+	if view.State != msboard.CellRevealed {
+		return rule{}, false
+	}
+
+	r := rule{cells: make(map[msboard.Location]bool), need: view.Score}
+	for _, n := range neighbors(s, l) {
+		mine, deduced := known[n]
+		switch nv, _ := s.Cell(n); {
+		case nv.State == msboard.CellMine || (deduced && mine):
+			r.need--
+		case unknown(s, n) && !deduced:
+			r.cells[n] = true
+		}
+	}
+	return r, len(r.cells) > 0
+}
+
+// deduce -- apply the single score rules, and optionally the pair rules, until nothing new follows. Results are
+// in reading order. None are made under the anti-mine rules, where a score no longer counts its mines
+func deduce(s msboard.Snapshot, pairs bool) (safe, mines []msboard.Location) {
+	if s.AntiMines > 0 {
+		return nil, nil
+	}
+	known := make(map[msboard.Location]bool) // true for a deduced mine, false for a deduced safe cell
+	mark := func(cells map[msboard.Location]bool, mine bool) bool {
+		for l := range cells {
+			known[l] = mine
+		}
+		return len(cells) > 0
+	}
+
+	for progress := true; progress; {
+		progress = false
+		current := rules(s, known)
+		for _, r := range current {
+			switch {
+			case r.need == 0:
+				progress = mark(r.cells, false) || progress
+			case r.need == len(r.cells):
+				progress = mark(r.cells, true) || progress
+			}
+		}
+		if progress || !pairs {
+			continue
+		}
+
+		for i, a := range current {
+			for j, b := range current {
+				if i == j || len(a.cells) >= len(b.cells) || !contains(b.cells, a.cells) {
+					continue
+				}
+				rest := make(map[msboard.Location]bool, len(b.cells)-len(a.cells))
+				for l := range b.cells {
+					if !a.cells[l] {
+						rest[l] = true
+					}
+				}
+				switch b.need - a.need {
+				case 0:
+					progress = mark(rest, false) || progress
+				case len(rest):
+					progress = mark(rest, true) || progress
+				}
+			}
+			if progress {
+				break
+			}
+		}
+	}
+
+	for l, mine := range known {
+		if mine {
+			mines = append(mines, l)
+		} else {
+			safe = append(safe, l)
+		}
+	}
+	sortLocations(safe)
+	sortLocations(mines)
+	return safe, mines
+}
+
+// contains -- true if every cell of inner is also in outer
+func contains(outer, inner map[msboard.Location]bool) bool {
+	for l := range inner {
+		if !outer[l] {
+			return false
+		}
+	}
+	return true
+}
+
+// sortLocations -- put locations in reading order
+func sortLocations(locations []msboard.Location) {
+	sort.Slice(locations, func(i, j int) bool {
+		a, b := locations[i], locations[j]
+		return a.Row() < b.Row() || (a.Row() == b.Row() && a.Col() < b.Col())
+	})
+}
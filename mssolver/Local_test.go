@@ -0,0 +1,97 @@
+package mssolver
+
+import (
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestLocalDeductions(t *testing.T) {
+	var cases = []struct {
+		layout      string
+		solver      msengine.Solver
+		safe, mines int
+	}{
+		// a 1 with one hidden neighbor
+		{"1*", Counting{}, 0, 1},
+		// a 2 with two hidden neighbors
+		{"*2*/121/___", Counting{}, 0, 2},
+		// 1-2-1, across or down, needs the pair rule
+		{"__1*/__2./__1*", Counting{}, 0, 0},
+		{"__1*/__2./__1*", Subset{}, 1, 2},
+		{"*.*/121/___", Counting{}, 0, 0},
+		{"*.*/121/___", Subset{}, 1, 2},
+		{"*.*/121/___", Frontier{}, 1, 2},
+	}
+
+	for _, testcase := range cases {
+		b, err := msboard.ParseLayout(testcase.layout)
+		if err != nil {
+			t.Fatalf("ParseLayout(%q) failed: %s", testcase.layout, err)
+		}
+		safe, mines := testcase.solver.Deductions(b.Snapshot())
+		if len(safe) != testcase.safe || len(mines) != testcase.mines {
+			t.Errorf("%T on %q wanted %d safe, %d mines got %v, %v", testcase.solver, testcase.layout,
+				testcase.safe, testcase.mines, safe, mines)
+		}
+	}
+}
+
+func TestLocalSoundness(t *testing.T) {
+	// on real games every local deduction must be right, and each solver must find at least what the weaker
+	// one does
+	for seed := int64(1); seed <= 20; seed++ {
+		rand.Seed(seed)
+		b := msboard.NewBoard("medium")
+		b.FirstClick(msboard.NewLocation(8, 8))
+		s := b.Snapshot()
+
+		countSafe, countMines := Counting{}.Deductions(s)
+		subsetSafe, subsetMines := Subset{}.Deductions(s)
+		frontierSafe, frontierMines := Frontier{}.Deductions(s)
+
+		for _, l := range append(append([]msboard.Location(nil), subsetSafe...), frontierSafe...) {
+			if b.MineAt(l) {
+				t.Errorf("seed %d: %v deduced safe but holds a mine", seed, l)
+			}
+		}
+		for _, l := range append(append([]msboard.Location(nil), subsetMines...), frontierMines...) {
+			if !b.MineAt(l) {
+				t.Errorf("seed %d: %v deduced mined but is safe", seed, l)
+			}
+		}
+		if !includes(subsetSafe, countSafe) || !includes(subsetMines, countMines) {
+			t.Errorf("seed %d: subset solver missed counting deductions", seed)
+		}
+		if !includes(frontierSafe, subsetSafe) || !includes(frontierMines, subsetMines) {
+			t.Errorf("seed %d: frontier solver missed subset deductions", seed)
+		}
+	}
+}
+
+func TestLocalOrder(t *testing.T) {
+	b, _ := msboard.ParseLayout("*.*/121/___")
+	safe, mines := Subset{}.Deductions(b.Snapshot())
+	if want := []msboard.Location{msboard.NewLocation(0, 0), msboard.NewLocation(0, 2)}; !reflect.DeepEqual(mines, want) {
+		t.Errorf("Deductions mines wanted %v got %v", want, mines)
+	}
+	if want := []msboard.Location{msboard.NewLocation(0, 1)}; !reflect.DeepEqual(safe, want) {
+		t.Errorf("Deductions safe wanted %v got %v", want, safe)
+	}
+}
+
+// includes -- true if every location of inner is in outer
+func includes(outer, inner []msboard.Location) bool {
+	set := make(map[msboard.Location]bool, len(outer))
+	for _, l := range outer {
+		set[l] = true
+	}
+	for _, l := range inner {
+		if !set[l] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,288 @@
+/*
+
+	Parallel.go - exact probabilities for big frontiers, enumerating independent regions concurrently and
+	remembering the ones already counted
+
+	mike@pocomotech.com
+
+*/
+
+package mssolver
+
+import (
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCachedRegions -- regions remembered by a Parallel solver before its cache starts over
+const maxCachedRegions = 4096
+
+// Parallel : Endgame's probabilities, with the independent regions of the frontier enumerated concurrently. The
+// arrangements of every region are remembered, so regions a move didn't touch cost nothing the next time. Regions
+// that can't be enumerated within the Budget get a local estimate, see Estimate, rather than failing the whole
+// position. Create with NewParallel; the zero value works but remembers nothing
+type Parallel struct {
+	Workers int           // regions enumerated at once, 0 for one per CPU
+	Budget  time.Duration // time allowed for a position, 0 for no limit beyond the search size
+	cache   *regionCache
+}
+
+// compile time check that Parallel can be used through the engine API
+var _ msengine.Solver = Parallel{}
+
+// Estimate : mine probabilities, indexed [row][col], some of which may be approximate
+type Estimate struct {
+	Probabilities [][]float64
+	Approximate   []msboard.Location // cells whose probability is a local estimate, in reading order
+}
+
+// regionCache : tallies of regions already enumerated, by regionKey
+type regionCache struct {
+	sync.Mutex
+	tallies map[string][]mineTally
+}
+
+// NewParallel -- solver using the given number of workers, 0 for one per CPU, and time budget, 0 for none
+func NewParallel(workers int, budget time.Duration) Parallel {
+	return Parallel{Workers: workers, Budget: budget, cache: &regionCache{tallies: make(map[string][]mineTally)}}
+}
+
+// Probabilities -- chance that each cell holds a mine, indexed [row][col]; see Estimate for which are exact
+func (par Parallel) Probabilities(s msboard.Snapshot) ([][]float64, error) {
+	e, err := par.Estimate(s)
+	return e.Probabilities, err
+}
+
+// Deductions -- cells that are certainly safe or certainly mined. Once any region had to be estimated, the count
+// of mines left to the others depends on the estimate, so only cells of exactly enumerated regions are considered,
+// and only those settled by every arrangement of their region whatever its number of mines
+func (par Parallel) Deductions(s msboard.Snapshot) (safe, mines []msboard.Location) {
+	p, probabilities, regions, tallies, errs, err := par.enumerate(s)
+	if err != nil {
+		return nil, nil
+	}
+	estimated := false
+	for _, err := range errs {
+		switch err {
+		case nil:
+		case ErrTooComplex:
+			estimated = true
+		default:
+			return nil, nil
+		}
+	}
+
+	if !estimated {
+		if err := p.combine(s, regions, tallies, probabilities); err != nil {
+			return nil, nil
+		}
+		var candidates []msboard.Location
+		for row := 0; row < s.Rows; row++ {
+			for col := 0; col < s.Cols; col++ {
+				if l := msboard.NewLocation(row, col); unknown(s, l) {
+					candidates = append(candidates, l)
+				}
+			}
+		}
+		return certain(candidates, probabilities)
+	}
+
+	for i, region := range regions {
+		if errs[i] != nil {
+			continue
+		}
+		for j, cell := range region {
+			solutions, mined := 0.0, 0.0
+			for _, t := range tallies[i] {
+				if t.solutions > 0 {
+					solutions += t.solutions
+					mined += t.counts[j]
+				}
+			}
+			switch {
+			case solutions == 0:
+			case mined == 0:
+				safe = append(safe, p.frontier[cell])
+			case mined == solutions:
+				mines = append(mines, p.frontier[cell])
+			}
+		}
+	}
+	sortLocations(safe)
+	sortLocations(mines)
+	return safe, mines
+}
+
+// Estimate -- probabilities for every cell: exact for the regions enumerated in time, and for the interior if
+// every region was, estimated from the scores around them for the rest
+func (par Parallel) Estimate(s msboard.Snapshot) (Estimate, error) {
+	p, probabilities, regions, tallies, errs, err := par.enumerate(s)
+	if err != nil {
+		return Estimate{}, err
+	}
+
+	// regions that couldn't be enumerated get an estimate, and the mines expected in them are taken out of the
+	// count shared by the rest
+	var exact [][]int
+	var exactTallies [][]mineTally
+	var retval Estimate
+	estimated := 0.0
+	for i, region := range regions {
+		switch errs[i] {
+		case nil:
+			exact = append(exact, region)
+			exactTallies = append(exactTallies, tallies[i])
+		case ErrTooComplex:
+			for _, cell := range region {
+				l := p.frontier[cell]
+				probabilities[l.Row()][l.Col()] = p.localEstimate(cell)
+				estimated += probabilities[l.Row()][l.Col()]
+				retval.Approximate = append(retval.Approximate, l)
+			}
+		default:
+			return Estimate{}, errs[i]
+		}
+	}
+	if p.remaining -= int(math.Round(estimated)); p.remaining < 0 {
+		p.remaining = 0
+	}
+
+	if err := p.combine(s, exact, exactTallies, probabilities); err != nil {
+		return Estimate{}, err
+	}
+	sortLocations(retval.Approximate)
+	retval.Probabilities = probabilities
+	return retval, nil
+}
+
+// enumerate -- the position, its independent regions and their tallies, with an error for each region that
+// couldn't be enumerated within the budget
+func (par Parallel) enumerate(s msboard.Snapshot) (p position, probabilities [][]float64, regions [][]int,
+	tallies [][]mineTally, errs []error, err error) {
+	if p, probabilities, err = start(s); err != nil {
+		return
+	}
+	if par.Budget > 0 {
+		p.deadline = time.Now().Add(par.Budget)
+	}
+	regions = p.components()
+	tallies, errs = par.tallies(p, regions)
+	return
+}
+
+// tallies -- enumerate the regions, several at once, taking those seen before from the cache
+func (par Parallel) tallies(p position, regions [][]int) ([][]mineTally, []error) {
+	retval := make([][]mineTally, len(regions))
+	errs := make([]error, len(regions))
+
+	workers := par.Workers
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				key := p.regionKey(regions[i])
+				if t, ok := par.cache.get(key); ok {
+					retval[i] = t
+					continue
+				}
+				if retval[i], errs[i] = p.tally(regions[i]); nil == errs[i] {
+					par.cache.put(key, retval[i])
+				}
+			}
+		}()
+	}
+	for i := range regions {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return retval, errs
+}
+
+// regionKey -- identifies a region by its cells and the scores over them, which is all its tallies depend on
+func (p position) regionKey(region []int) string {
+	local := make(map[int]int, len(region))
+	var sb strings.Builder
+	for i, cell := range region {
+		local[cell] = i
+		l := p.frontier[cell]
+		sb.WriteString(strconv.Itoa(l.Row()))
+		sb.WriteByte(',')
+		sb.WriteString(strconv.Itoa(l.Col()))
+		sb.WriteByte(' ')
+	}
+	for _, c := range p.constraints {
+		if len(c.cells) == 0 {
+			continue
+		}
+		if _, ok := local[c.cells[0]]; !ok {
+			continue
+		}
+		sb.WriteString("|")
+		sb.WriteString(strconv.Itoa(c.need))
+		for _, cell := range c.cells {
+			sb.WriteByte(' ')
+			sb.WriteString(strconv.Itoa(local[cell]))
+		}
+	}
+	return sb.String()
+}
+
+// localEstimate -- mine probability of a frontier cell from the scores around it alone: certain if any of them
+// settles it, otherwise the average share of missing mines over their unknown neighbors
+func (p position) localEstimate(cell int) float64 {
+	total, scores := 0.0, 0
+	for _, c := range p.constraints {
+		for _, other := range c.cells {
+			if other != cell {
+				continue
+			}
+			share := float64(c.need) / float64(len(c.cells))
+			if share <= 0 || share >= 1 {
+				return math.Max(0, math.Min(1, share))
+			}
+			total += share
+			scores++
+		}
+	}
+	if scores == 0 {
+		return 0
+	}
+	return total / float64(scores)
+}
+
+// get -- cached tallies for a region, false if it hasn't been seen. A nil cache remembers nothing
+func (c *regionCache) get(key string) ([]mineTally, bool) {
+	if nil == c {
+		return nil, false
+	}
+	c.Lock()
+	defer c.Unlock()
+	t, ok := c.tallies[key]
+	return t, ok
+}
+
+// put -- remember a region's tallies, starting over when the cache is full
+func (c *regionCache) put(key string, t []mineTally) {
+	if nil == c {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	if len(c.tallies) >= maxCachedRegions {
+		c.tallies = make(map[string][]mineTally)
+	}
+	c.tallies[key] = t
+}
@@ -0,0 +1,119 @@
+package mssolver
+
+import (
+	"go-mines/msboard"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParallelMatchesEndgame(t *testing.T) {
+	par := NewParallel(4, 0)
+	for seed := int64(1); seed <= 20; seed++ {
+		rand.Seed(seed)
+		b := msboard.NewBoard("medium")
+		b.FirstClick(msboard.NewLocation(8, 8))
+		s := b.Snapshot()
+
+		want, wantErr := Endgame{}.Probabilities(s)
+		// the second time round every region comes from the cache
+		for pass := 0; pass < 2; pass++ {
+			e, err := par.Estimate(s)
+			if err != wantErr {
+				t.Fatalf("seed %d: wanted error %v got %v", seed, wantErr, err)
+			}
+			if err != nil {
+				continue
+			}
+			if len(e.Approximate) > 0 {
+				t.Errorf("seed %d: unexpected estimates for %v", seed, e.Approximate)
+			}
+			for row := range want {
+				for col := range want[row] {
+					if math.Abs(e.Probabilities[row][col]-want[row][col]) > 1e-9 {
+						t.Errorf("seed %d pass %d: %d,%d wanted %.6f got %.6f", seed, pass, row, col,
+							want[row][col], e.Probabilities[row][col])
+					}
+				}
+			}
+		}
+	}
+	if len(par.cache.tallies) == 0 {
+		t.Errorf("no regions were cached")
+	}
+}
+
+func TestParallelEstimatesOverBudget(t *testing.T) {
+	// a mine in every third cell of a long hidden row over a row of 1s: one region, with a long search
+	var hidden, scores, open strings.Builder
+	for col := 0; col < 600; col++ {
+		if col%3 == 1 {
+			hidden.WriteByte('*')
+		} else {
+			hidden.WriteByte('.')
+		}
+		scores.WriteByte('1')
+		open.WriteByte('_')
+	}
+	b, err := msboard.ParseLayout(hidden.String() + "/" + scores.String() + "/" + open.String())
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+	s := b.Snapshot()
+
+	// a budget that's gone by the first clock check
+	e, err := Parallel{Budget: time.Nanosecond}.Estimate(s)
+	if err != nil {
+		t.Fatalf("Estimate failed: %s", err)
+	}
+	if len(e.Approximate) != 600 {
+		t.Fatalf("wanted the whole row estimated got %d cells", len(e.Approximate))
+	}
+	// away from the ends every score shares one mine among three cells
+	for _, l := range e.Approximate[2 : len(e.Approximate)-2] {
+		if p := e.Probabilities[l.Row()][l.Col()]; math.Abs(p-1.0/3) > 1e-9 {
+			t.Fatalf("%v wanted estimate 1/3 got %v", l, p)
+		}
+	}
+	if safe, mines := (Parallel{Budget: time.Nanosecond}).Deductions(s); len(safe)+len(mines) > 0 {
+		t.Errorf("wanted no deductions from estimates got %v, %v", safe, mines)
+	}
+
+	// with time to spare the row is enumerated, and it has three arrangements
+	e, err = NewParallel(0, time.Minute).Estimate(s)
+	if err != nil || len(e.Approximate) > 0 {
+		t.Fatalf("Estimate wanted exact results got %v, %d estimates", err, len(e.Approximate))
+	}
+}
+
+func TestParallelDeductionsOverBudget(t *testing.T) {
+	// expert boards opened up by random safe clicks, with big regions estimated and the rest enumerated; whatever
+	// the clock allowed, every deduction must be right
+	for seed := int64(1); seed <= 200; seed++ {
+		rand.Seed(seed)
+		b := msboard.NewBoard("hard")
+		b.FirstClick(msboard.NewLocation(8, 15))
+		for i := 0; i < 40 && b.Status() == msboard.StatusPlaying; i++ {
+			if l := msboard.NewLocation(rand.Intn(b.Rows()), rand.Intn(b.Cols())); !b.MineAt(l) {
+				b.Click(l)
+			}
+		}
+		if b.Status() != msboard.StatusPlaying {
+			continue
+		}
+
+		safe, mines := Parallel{Budget: time.Microsecond}.Deductions(b.Snapshot())
+		for _, l := range safe {
+			if b.MineAt(l) {
+				t.Errorf("seed %d: mine at %v deduced safe", seed, l)
+			}
+		}
+		for _, l := range mines {
+			if !b.MineAt(l) {
+				t.Errorf("seed %d: safe cell %v deduced a mine", seed, l)
+			}
+		}
+	}
+}
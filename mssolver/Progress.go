@@ -0,0 +1,76 @@
+/*
+
+	Progress.go - whether the player can make progress without guessing, cheap enough to check after every move
+
+	mike@pocomotech.com
+
+*/
+
+package mssolver
+
+import (
+	"go-mines/msboard"
+	"time"
+)
+
+// Progress : whether a position has a move that needs no guess
+type Progress int
+
+// Answers to whether a safe move exists
+const (
+	ProgressUnknown Progress = iota // the budget ran out before it was settled
+	ProgressSafe                    // some hidden cell is certainly safe
+	ProgressGuess                   // every hidden cell might be a mine, the player has to guess
+)
+
+var progressNames = [...]string{"unknown", "safe move", "guess"}
+
+// String -- human readable answer
+func (p Progress) String() string {
+	if p < 0 || int(p) >= len(progressNames) {
+		return "unknown"
+	}
+	return progressNames[p]
+}
+
+// SafeMoveExists -- decide within the budget whether a position has a certainly safe hidden cell. The cheap
+// solvers settle most positions; only when they find nothing is the exact Endgame enumeration run, with whatever
+// is left of the budget. Under the anti-mine rules only Signed applies, and since it ignores the mine count,
+// finding nothing there leaves the answer unknown
+func SafeMoveExists(s msboard.Snapshot, budget time.Duration) Progress {
+	if s.Status != msboard.StatusPlaying {
+		return ProgressUnknown
+	}
+	if s.AntiMines > 0 {
+		if safe, _ := (Signed{}).Deductions(s); len(safe) > 0 {
+			return ProgressSafe
+		}
+		return ProgressUnknown
+	}
+	deadline := time.Now().Add(budget)
+
+	for _, solver := range []interface {
+		Deductions(msboard.Snapshot) ([]msboard.Location, []msboard.Location)
+	}{Counting{}, Subset{}} {
+		if safe, _ := solver.Deductions(s); len(safe) > 0 {
+			return ProgressSafe
+		}
+	}
+
+	left := time.Until(deadline)
+	if left <= 0 {
+		return ProgressUnknown
+	}
+	probabilities, err := Endgame{Budget: left}.Probabilities(s)
+	if err != nil {
+		return ProgressUnknown
+	}
+	for row := range probabilities {
+		for col, p := range probabilities[row] {
+			if p == 0 && unknown(s, msboard.NewLocation(row, col)) {
+				return ProgressSafe
+			}
+		}
+	}
+	return ProgressGuess
+}
@@ -0,0 +1,61 @@
+package mssolver
+
+import (
+	"go-mines/msboard"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestSafeMoveExists(t *testing.T) {
+	var cases = []struct {
+		layout string
+		budget time.Duration
+		want   Progress
+	}{
+		// 1-2-1 has a safe cell in the middle
+		{"__1*/__2./__1*", time.Second, ProgressSafe},
+		// a 50/50 under a pair of 1s
+		{"*./11/__", time.Second, ProgressGuess},
+		// local rules find nothing and there's no time left for enumeration
+		{"*./11/__", 0, ProgressUnknown},
+	}
+
+	for _, testcase := range cases {
+		b, err := msboard.ParseLayout(testcase.layout)
+		if err != nil {
+			t.Fatalf("ParseLayout(%q) failed: %s", testcase.layout, err)
+		}
+		if got := SafeMoveExists(b.Snapshot(), testcase.budget); got != testcase.want {
+			t.Errorf("SafeMoveExists(%q, %s) wanted %v got %v", testcase.layout, testcase.budget, testcase.want, got)
+		}
+	}
+}
+
+func TestSafeMoveExistsAgreesWithEndgame(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		rand.Seed(seed)
+		b := msboard.NewBoard("medium")
+		b.FirstClick(msboard.NewLocation(8, 8))
+		s := b.Snapshot()
+
+		safe, _ := Endgame{}.Deductions(s)
+		want := ProgressGuess
+		if len(safe) > 0 {
+			want = ProgressSafe
+		}
+		if got := SafeMoveExists(s, time.Minute); got != want {
+			t.Errorf("seed %d: SafeMoveExists wanted %v got %v", seed, want, got)
+		}
+	}
+}
+
+func TestSafeMoveExistsFinishedGame(t *testing.T) {
+	b, err := msboard.ParseLayout("1*")
+	if err != nil {
+		t.Fatalf("ParseLayout failed: %s", err)
+	}
+	if got := SafeMoveExists(b.Snapshot(), time.Second); got != ProgressUnknown {
+		t.Errorf("finished game wanted %v got %v", ProgressUnknown, got)
+	}
+}
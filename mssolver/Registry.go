@@ -0,0 +1,75 @@
+/*
+
+	Registry.go - solvers by name, so users can pick the speed/accuracy trade-off they want and researchers can add
+	their own
+
+	mike@pocomotech.com
+
+*/
+
+package mssolver
+
+import (
+	"errors"
+	"fmt"
+	"go-mines/msengine"
+	"sort"
+	"sync"
+)
+
+// registry : every solver available by name
+var registry = struct {
+	sync.RWMutex
+	solvers map[string]msengine.Solver
+}{
+	solvers: map[string]msengine.Solver{
+		"counting": Counting{},
+		"subset":   Subset{},
+		"frontier": Frontier{},
+		"endgame":  Endgame{},
+		"parallel": NewParallel(0, 0),
+		"sampler":  Sampler{},
+		"signed":   Signed{},
+	},
+}
+
+// DefaultSolver : name of the solver used when none is chosen, accurate and quick enough for any point of a game
+const DefaultSolver = "frontier"
+
+// Register -- make a solver available by name. Names are unique; registering a taken name is an error
+func Register(name string, s msengine.Solver) error {
+	if name == "" || nil == s {
+		return errors.New("solvers need a name and an implementation")
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	if _, taken := registry.solvers[name]; taken {
+		return fmt.Errorf("solver %q is already registered", name)
+	}
+	registry.solvers[name] = s
+	return nil
+}
+
+// Lookup -- the solver registered under a name
+func Lookup(name string) (msengine.Solver, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+	s, ok := registry.solvers[name]
+	if !ok {
+		return nil, fmt.Errorf("no solver named %q", name)
+	}
+	return s, nil
+}
+
+// Names -- names of every registered solver, sorted
+func Names() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	retval := make([]string, 0, len(registry.solvers))
+	for name := range registry.solvers {
+		retval = append(retval, name)
+	}
+	sort.Strings(retval)
+	return retval
+}
@@ -0,0 +1,40 @@
+package mssolver
+
+import (
+	"go-mines/msboard"
+	"reflect"
+	"testing"
+)
+
+// noDeductions : a solver that never finds anything
+type noDeductions struct{}
+
+func (noDeductions) Deductions(s msboard.Snapshot) (safe, mines []msboard.Location) {
+	return nil, nil
+}
+
+func TestRegistry(t *testing.T) {
+	want := []string{"counting", "endgame", "frontier", "parallel", "sampler", "signed", "subset"}
+	if got := Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("built in solvers wanted %v got %v", want, got)
+	}
+	if s, err := Lookup(DefaultSolver); err != nil || s != (Frontier{}) {
+		t.Errorf("Lookup(%q) wanted Frontier got %v, %v", DefaultSolver, s, err)
+	}
+	if _, err := Lookup("oracle"); err == nil {
+		t.Errorf("Lookup of an unknown solver should fail")
+	}
+
+	if err := Register("none", noDeductions{}); err != nil {
+		t.Fatalf("Register failed: %s", err)
+	}
+	if s, err := Lookup("none"); err != nil || s != (noDeductions{}) {
+		t.Errorf("Lookup of a registered solver wanted it back got %v, %v", s, err)
+	}
+	if err := Register("none", Counting{}); err == nil {
+		t.Errorf("registering a taken name should fail")
+	}
+	if err := Register("", Counting{}); err == nil {
+		t.Errorf("registering without a name should fail")
+	}
+}
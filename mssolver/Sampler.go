@@ -0,0 +1,433 @@
+/*
+
+	Sampler.go - Monte Carlo mine probabilities for positions too big to enumerate
+
+	The sampler takes a random walk over the arrangements of the frontier that fit every revealed score, keeping
+	only a count of the mines left for the interior. Each step either swaps a mined frontier cell with an empty one,
+	or redraws a small block of cells around a frontier cell from every arrangement of the block that fits, weighted
+	by the ways of placing the mines then left in the interior. In the long run every complete layout is equally
+	likely, as it is under Endgame, so the share of recorded layouts with a mine on a cell estimates that cell's
+	probability. The walk is split into batches, and the spread of the batch results gives the confidence interval.
+
+	mike@pocomotech.com
+
+*/
+
+package mssolver
+
+import (
+	"errors"
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"math"
+	"math/rand"
+)
+
+// defaultSamples -- layouts recorded when a Sampler doesn't say
+const defaultSamples = 20000
+
+// sampleSpacing -- walk steps between recorded layouts, so consecutive records differ
+const sampleSpacing = 10
+
+// sampleBatches -- batches the records are split into to estimate the error
+const sampleBatches = 20
+
+// maxBlock -- most cells redrawn together in one step of the walk
+const maxBlock = 12
+
+// startArrangements -- arrangements of each region looked at when choosing where the walk starts
+const startArrangements = 1000
+
+// ErrNoStart : the sampler found no layout to start its walk from
+var ErrNoStart = errors.New("no starting layout found for sampling")
+
+// Sampler : Monte Carlo estimates of mine probabilities, for positions Frontier and Endgame can't enumerate.
+// Estimates come with the half width of a 95% confidence interval, and are never certain, so a Sampler deduces
+// nothing. The walk redraws at most a few cells at once, so layouts that differ along a long chain of tightly
+// packed scores are rarely reached from each other; the intervals are too narrow then
+type Sampler struct {
+	Samples int   // layouts recorded, 0 for the default
+	Seed    int64 // seed of the walk, so estimates can be repeated
+}
+
+// compile time check that Sampler can be used through the engine API
+var _ msengine.Solver = Sampler{}
+
+// Fallback : exact probabilities from Exact, or the Sampler's estimates for positions too complex for it
+type Fallback struct {
+	Exact interface {
+		Probabilities(s msboard.Snapshot) ([][]float64, error)
+	}
+	Sampler Sampler
+}
+
+// Probabilities -- chance that each cell holds a mine, indexed [row][col], exact where possible
+func (f Fallback) Probabilities(s msboard.Snapshot) ([][]float64, error) {
+	retval, _, err := f.Estimate(s)
+	return retval, err
+}
+
+// Estimate -- probabilities from Exact with no margins, or if it can't enumerate the position, the Sampler's
+// estimates and margins
+func (f Fallback) Estimate(s msboard.Snapshot) (probabilities, margins [][]float64, err error) {
+	probabilities, err = f.Exact.Probabilities(s)
+	if err == ErrTooComplex {
+		return f.Sampler.Estimate(s)
+	}
+	return probabilities, nil, err
+}
+
+// Probabilities -- estimated chance that each cell holds a mine, indexed [row][col]
+func (sm Sampler) Probabilities(s msboard.Snapshot) ([][]float64, error) {
+	retval, _, err := sm.Estimate(s)
+	return retval, err
+}
+
+// Deductions -- none: sampling can make a cell very likely safe, but never certainly
+func (Sampler) Deductions(s msboard.Snapshot) (safe, mines []msboard.Location) {
+	return nil, nil
+}
+
+// Estimate -- estimated probabilities and their margins, the half width of a 95% confidence interval, both
+// indexed [row][col]. Revealed cells report 0 and revealed mines 1, with no margin
+func (sm Sampler) Estimate(s msboard.Snapshot) (probabilities, margins [][]float64, err error) {
+	p, probabilities, err := start(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	margins = make([][]float64, s.Rows)
+	for row := range margins {
+		margins[row] = make([]float64, s.Cols)
+	}
+	if len(p.frontier)+p.interior == 0 {
+		return probabilities, margins, nil
+	}
+
+	mined, err := p.startLayout()
+	if err != nil {
+		return nil, nil, err
+	}
+	w := newWalk(p, mined, rand.New(rand.NewSource(sm.Seed)))
+
+	samples := sm.Samples
+	if samples < sampleBatches {
+		samples = defaultSamples
+	}
+	perBatch := samples / sampleBatches
+	samples = perBatch * sampleBatches
+	w.run(samples * sampleSpacing / 10) // burn in
+
+	// one column per frontier cell, then the share of the interior with a mine
+	batches := make([][]float64, sampleBatches)
+	for b := range batches {
+		batches[b] = make([]float64, len(p.frontier)+1)
+		for i := 0; i < perBatch; i++ {
+			w.run(sampleSpacing)
+			for c, mine := range w.mined {
+				if mine {
+					batches[b][c]++
+				}
+			}
+			if p.interior > 0 {
+				batches[b][len(p.frontier)] += float64(w.left) / float64(p.interior)
+			}
+		}
+	}
+
+	// rule of three: a cell never seen with a mine could still have one about 3/n of the time
+	floor := 3 / float64(samples)
+	if p.interior > 0 {
+		mean, spread := batchStats(batches, len(p.frontier), perBatch)
+		p.fillInterior(s, probabilities, mean)
+		margin := math.Max(floor, 1.96*spread/math.Sqrt(sampleBatches))
+		for row := range margins {
+			for col := range margins[row] {
+				if unknown(s, msboard.NewLocation(row, col)) {
+					margins[row][col] = margin // the frontier's own are filled in next
+				}
+			}
+		}
+	}
+	for c, l := range p.frontier {
+		mean, spread := batchStats(batches, c, perBatch)
+		probabilities[l.Row()][l.Col()] = mean
+		margins[l.Row()][l.Col()] = math.Max(floor, 1.96*spread/math.Sqrt(sampleBatches))
+	}
+	return probabilities, margins, nil
+}
+
+// batchStats -- mean and standard deviation of the batch estimates for one cell
+func batchStats(batches [][]float64, cell, perBatch int) (mean, spread float64) {
+	for b := range batches {
+		mean += batches[b][cell] / float64(perBatch)
+	}
+	mean /= float64(len(batches))
+	for b := range batches {
+		d := batches[b][cell]/float64(perBatch) - mean
+		spread += d * d
+	}
+	return mean, math.Sqrt(spread / float64(len(batches)-1))
+}
+
+// startLayout -- mines for the frontier cells that fit every score and leave a number of mines the interior can
+// hold. Each region offers one arrangement per mine count among the first it finds; the regions start at their
+// fewest and move up while the interior would be overfull
+func (p position) startLayout() ([]bool, error) {
+	regions := p.components()
+	options := make([]map[int][]bool, len(regions))
+	for i, region := range regions {
+		options[i] = make(map[int][]bool)
+		seen := 0
+		err := p.search(region, func(assigned []bool, placed int) bool {
+			if _, ok := options[i][placed]; !ok {
+				options[i][placed] = append([]bool(nil), assigned...)
+			}
+			seen++
+			return seen < startArrangements
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(options[i]) == 0 {
+			return nil, ErrInconsistent
+		}
+	}
+
+	chosen := make([]int, len(regions))
+	total := 0
+	for i := range regions {
+		chosen[i] = len(regions[i]) + 1
+		for count := range options[i] {
+			if count < chosen[i] {
+				chosen[i] = count
+			}
+		}
+		total += chosen[i]
+	}
+	for i := range regions {
+		for count := range options[i] {
+			if p.remaining-total > p.interior && count > chosen[i] && total+count-chosen[i] <= p.remaining {
+				total += count - chosen[i]
+				chosen[i] = count
+			}
+		}
+	}
+	if total > p.remaining || p.remaining-total > p.interior {
+		return nil, ErrNoStart
+	}
+
+	retval := make([]bool, len(p.frontier))
+	for i, region := range regions {
+		for j, cell := range region {
+			retval[cell] = options[i][chosen[i]][j]
+		}
+	}
+	return retval, nil
+}
+
+// walk : the state of the sampler's random walk
+type walk struct {
+	rng       *rand.Rand
+	mined     []bool    // per frontier cell
+	left      int       // mines in the interior
+	interior  int       // cells in the interior
+	logWays   []float64 // per number of mines in the interior, the log of the ways of placing them
+	blocks    [][]int   // per frontier cell, it and the cells sharing a constraint with it, up to maxBlock
+	watching  [][]int   // per frontier cell, the constraints it appears in
+	need, got []int     // per constraint, the mines wanted and placed
+	open      []int     // per constraint, cells of the block being redrawn not yet decided
+}
+
+// newWalk -- start a walk from a fitting arrangement of the frontier
+func newWalk(p position, mined []bool, rng *rand.Rand) *walk {
+	retval := &walk{
+		rng:      rng,
+		mined:    mined,
+		left:     p.remaining,
+		interior: p.interior,
+		logWays:  make([]float64, p.interior+1),
+		blocks:   make([][]int, len(p.frontier)),
+		watching: make([][]int, len(p.frontier)),
+		need:     make([]int, len(p.constraints)),
+		got:      make([]int, len(p.constraints)),
+		open:     make([]int, len(p.constraints)),
+	}
+	for _, mine := range mined {
+		if mine {
+			retval.left--
+		}
+	}
+	for k := range retval.logWays {
+		retval.logWays[k] = logChoose(p.interior, k)
+	}
+	for i, c := range p.constraints {
+		retval.need[i] = c.need
+		for _, cell := range c.cells {
+			retval.watching[cell] = append(retval.watching[cell], i)
+			if mined[cell] {
+				retval.got[i]++
+			}
+		}
+	}
+	for cell := range retval.blocks {
+		block := []int{cell}
+		inBlock := map[int]bool{cell: true}
+		for _, t := range retval.watching[cell] {
+			for _, other := range p.constraints[t].cells {
+				if !inBlock[other] && len(block) < maxBlock {
+					inBlock[other] = true
+					block = append(block, other)
+				}
+			}
+		}
+		retval.blocks[cell] = block
+	}
+	return retval
+}
+
+// run -- take n steps, each either swapping two frontier cells or redrawing the block around one
+func (w *walk) run(n int) {
+	if len(w.mined) == 0 {
+		return
+	}
+	for ; n > 0; n-- {
+		if w.rng.Intn(2) == 0 {
+			w.swap(w.rng.Intn(len(w.mined)), w.rng.Intn(len(w.mined)))
+		} else {
+			w.redraw(w.blocks[w.rng.Intn(len(w.mined))])
+		}
+	}
+}
+
+// swap -- exchange the contents of two frontier cells if the scores still fit afterwards
+func (w *walk) swap(a, b int) {
+	if w.mined[a] == w.mined[b] {
+		return
+	}
+	if w.mined[b] {
+		a, b = b, a
+	}
+	w.shift(a, -1)
+	w.shift(b, 1)
+	if !w.fits(a) || !w.fits(b) {
+		w.shift(a, 1)
+		w.shift(b, -1)
+		return
+	}
+	w.mined[a], w.mined[b] = false, true
+}
+
+// redraw -- choose new mines for a block from every arrangement of it that fits the scores, given the cells
+// around it, weighted by the ways of placing the mines then left in the interior
+func (w *walk) redraw(block []int) {
+	free := w.left
+	for _, cell := range block {
+		if w.mined[cell] {
+			w.shift(cell, -1)
+			w.mined[cell] = false
+			free++
+		}
+		for _, t := range w.watching[cell] {
+			w.open[t]++
+		}
+	}
+
+	var options [][]bool
+	var weights []float64
+	assigned := make([]bool, len(block))
+	var search func(i, placed int)
+	search = func(i, placed int) {
+		if i == len(block) {
+			if free-placed <= w.interior {
+				options = append(options, append([]bool(nil), assigned...))
+				weights = append(weights, w.logWays[free-placed])
+			}
+			return
+		}
+		cell := block[i]
+		for _, t := range w.watching[cell] {
+			w.open[t]--
+		}
+		for _, mine := range [...]bool{false, true} {
+			if mine {
+				if placed == free {
+					break
+				}
+				w.shift(cell, 1)
+			}
+			if w.possible(cell) {
+				assigned[i] = mine
+				if mine {
+					search(i+1, placed+1)
+				} else {
+					search(i+1, placed)
+				}
+			}
+			if mine {
+				w.shift(cell, -1)
+			}
+		}
+		for _, t := range w.watching[cell] {
+			w.open[t]++
+		}
+	}
+	search(0, 0)
+	for _, cell := range block {
+		for _, t := range w.watching[cell] {
+			w.open[t]--
+		}
+	}
+
+	// the arrangement the block had is always among the options
+	largest := math.Inf(-1)
+	for _, weight := range weights {
+		largest = math.Max(largest, weight)
+	}
+	total := 0.0
+	for i := range weights {
+		weights[i] = math.Exp(weights[i] - largest)
+		total += weights[i]
+	}
+	pick, r := 0, w.rng.Float64()*total
+	for pick < len(options)-1 && r >= weights[pick] {
+		r -= weights[pick]
+		pick++
+	}
+
+	w.left = free
+	for j, cell := range block {
+		if options[pick][j] {
+			w.shift(cell, 1)
+			w.mined[cell] = true
+			w.left--
+		}
+	}
+}
+
+// shift -- adjust the mine count of every constraint watching a frontier cell
+func (w *walk) shift(cell, delta int) {
+	for _, t := range w.watching[cell] {
+		w.got[t] += delta
+	}
+}
+
+// fits -- true if every constraint watching a frontier cell has exactly its mines
+func (w *walk) fits(cell int) bool {
+	for _, t := range w.watching[cell] {
+		if w.got[t] != w.need[t] {
+			return false
+		}
+	}
+	return true
+}
+
+// possible -- true if every constraint watching a frontier cell can still get exactly its mines from the cells of
+// the block not yet decided
+func (w *walk) possible(cell int) bool {
+	for _, t := range w.watching[cell] {
+		if w.got[t] > w.need[t] || w.got[t]+w.open[t] < w.need[t] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package mssolver
+
+import (
+	"go-mines/msboard"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSamplerMatchesEndgame(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		rand.Seed(seed)
+		b := msboard.NewBoard("medium")
+		b.FirstClick(msboard.NewLocation(8, 8))
+		s := b.Snapshot()
+
+		want, err := Endgame{}.Probabilities(s)
+		if err != nil {
+			continue
+		}
+		got, margins, err := Sampler{Seed: seed}.Estimate(s)
+		if err != nil {
+			t.Fatalf("seed %d: Estimate failed: %s", seed, err)
+		}
+		for row := range want {
+			for col := range want[row] {
+				if !unknown(s, msboard.NewLocation(row, col)) {
+					if margins[row][col] != 0 {
+						t.Errorf("seed %d: %d,%d is known but has margin %v", seed, row, col, margins[row][col])
+					}
+					continue
+				}
+				if margins[row][col] <= 0 {
+					t.Errorf("seed %d: %d,%d is an estimate with no margin", seed, row, col)
+				}
+				// 95% intervals miss now and then, so allow a few of them
+				if math.Abs(got[row][col]-want[row][col]) > 3*margins[row][col]+0.01 {
+					t.Errorf("seed %d: %d,%d wanted %.4f got %.4f ±%.4f", seed, row, col, want[row][col],
+						got[row][col], margins[row][col])
+				}
+			}
+		}
+	}
+}
+
+func TestSamplerRepeatable(t *testing.T) {
+	b, _ := msboard.ParseLayout("1*../..../.*..")
+	first, _, err := Sampler{Samples: 1000, Seed: 7}.Estimate(b.Snapshot())
+	if err != nil {
+		t.Fatalf("Estimate failed: %s", err)
+	}
+	second, _, _ := Sampler{Samples: 1000, Seed: 7}.Estimate(b.Snapshot())
+	for row := range first {
+		for col := range first[row] {
+			if first[row][col] != second[row][col] {
+				t.Errorf("%d,%d differs between runs with one seed: %v, %v", row, col, first[row][col], second[row][col])
+			}
+		}
+	}
+	if safe, mines := (Sampler{}).Deductions(b.Snapshot()); len(safe)+len(mines) > 0 {
+		t.Errorf("wanted no deductions from estimates got %v, %v", safe, mines)
+	}
+}
+
+// tooComplex : an exact solver that gives up on everything
+type tooComplex struct{}
+
+func (tooComplex) Probabilities(s msboard.Snapshot) ([][]float64, error) {
+	return nil, ErrTooComplex
+}
+
+func TestFallback(t *testing.T) {
+	b, _ := msboard.ParseLayout("1*../....")
+	s := b.Snapshot()
+
+	_, margins, err := Fallback{Exact: Endgame{}}.Estimate(s)
+	if err != nil || margins != nil {
+		t.Errorf("wanted exact probabilities without margins got %v, %v", margins, err)
+	}
+
+	got, margins, err := Fallback{Exact: tooComplex{}, Sampler: Sampler{Seed: 1}}.Estimate(s)
+	if err != nil || nil == margins {
+		t.Fatalf("wanted estimates with margins got %v, %v", margins, err)
+	}
+	// the mine is one of the three cells next to the 1
+	for _, l := range []msboard.Location{msboard.NewLocation(0, 1), msboard.NewLocation(1, 0), msboard.NewLocation(1, 1)} {
+		if p := got[l.Row()][l.Col()]; math.Abs(p-1.0/3) > 3*margins[l.Row()][l.Col()]+0.01 {
+			t.Errorf("%v wanted about 1/3 got %.4f", l, p)
+		}
+	}
+}
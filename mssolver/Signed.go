@@ -0,0 +1,147 @@
+/*
+
+	Signed.go - a solver for the anti-mine variant, where each hidden cell adds -1, 0 or +1 to the scores around it
+
+	mike@pocomotech.com
+
+*/
+
+package mssolver
+
+import (
+	"go-mines/msboard"
+	"go-mines/msengine"
+)
+
+// Signed : exact enumeration of the frontier under the anti-mine rules. Every frontier cell is tried as an
+// anti-mine, a safe cell and a mine, keeping the assignments whose sums match every revealed score. Cells safe in
+// all of them are safe; cells dangerous in all of them are mines, of one kind or the other. The mine counts are
+// not used, so deductions are sound but may miss some an exact count would allow. Positions under the classic
+// rules are handed to Frontier
+type Signed struct{}
+
+// compile time check that Signed can be used through the engine API
+var _ msengine.Solver = Signed{}
+
+// Deductions -- frontier cells that are certainly safe or certainly one kind of mine or the other. Components too
+// large to enumerate, and lost games, yield no deductions
+func (Signed) Deductions(s msboard.Snapshot) (safe, mines []msboard.Location) {
+	if s.AntiMines == 0 {
+		return Frontier{}.Deductions(s)
+	}
+	if s.Status != msboard.StatusPlaying {
+		return nil, nil // a revealed mine's sign isn't shown, so its scores can't be read
+	}
+
+	p := newPosition(s)
+	for _, component := range p.components() {
+		outcomes, err := p.enumerateSigned(component)
+		if err != nil {
+			continue
+		}
+		for i, cell := range component {
+			switch outcomes[i] {
+			case outcomeSafe:
+				safe = append(safe, p.frontier[cell])
+			case outcomeMine:
+				mines = append(mines, p.frontier[cell])
+			}
+		}
+	}
+	sortLocations(safe)
+	sortLocations(mines)
+	return safe, mines
+}
+
+// outcome : what a cell has been in the assignments found so far, as a set of bits
+type outcome int
+
+// Outcomes of a frontier cell across the assignments that fit
+const (
+	outcomeSafe outcome = 1 << iota // zero in some assignment
+	outcomeMine                     // a mine or anti-mine in some assignment
+)
+
+// enumerateSigned -- the outcomes of each cell of a component across every assignment of -1, 0 or +1 that fits the
+// scores. ErrInconsistent if none does, ErrTooComplex if the search outgrows maxSearchNodes
+func (p position) enumerateSigned(component []int) ([]outcome, error) {
+	local := make(map[int]int, len(component)) // frontier index to position in component
+	for i, cell := range component {
+		local[cell] = i
+	}
+
+	// the constraints over this component, and for each cell the constraints it takes part in
+	var sums, open, needs []int
+	involved := make([][]int, len(component))
+	for _, c := range p.constraints {
+		if len(c.cells) == 0 {
+			continue
+		}
+		if _, ok := local[c.cells[0]]; !ok {
+			continue
+		}
+		for _, cell := range c.cells {
+			involved[local[cell]] = append(involved[local[cell]], len(needs))
+		}
+		sums = append(sums, 0)
+		open = append(open, len(c.cells))
+		needs = append(needs, c.need)
+	}
+
+	values := make([]int, len(component))
+	retval := make([]outcome, len(component))
+	solutions, nodes := 0, 0
+
+	// fits -- the constraints of cell i can still be met by the cells not yet assigned, each worth -1 to +1
+	fits := func(i int) bool {
+		for _, c := range involved[i] {
+			if needs[c] < sums[c]-open[c] || needs[c] > sums[c]+open[c] {
+				return false
+			}
+		}
+		return true
+	}
+
+	var search func(i int) bool
+	search = func(i int) bool {
+		if nodes++; nodes > maxSearchNodes {
+			return false
+		}
+		if i == len(component) {
+			solutions++
+			for j, v := range values {
+				if v == 0 {
+					retval[j] |= outcomeSafe
+				} else {
+					retval[j] |= outcomeMine
+				}
+			}
+			return true
+		}
+
+		for _, v := range [...]int{0, 1, -1} {
+			values[i] = v
+			for _, c := range involved[i] {
+				sums[c] += v
+				open[c]--
+			}
+			ok := !fits(i) || search(i+1)
+			for _, c := range involved[i] {
+				sums[c] -= v
+				open[c]++
+			}
+			if !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	if !search(0) {
+		return nil, ErrTooComplex
+	}
+	if solutions == 0 {
+		return nil, ErrInconsistent
+	}
+	return retval, nil
+}
@@ -0,0 +1,71 @@
+package mssolver
+
+import (
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"math/rand"
+	"testing"
+)
+
+func TestSignedDeductions(t *testing.T) {
+	var cases = []struct {
+		layout      string
+		solver      msengine.Solver
+		safe, mines int
+	}{
+		// A2 scoring -1 on its own makes A1 an anti-mine; C1 and C2 could still cancel out
+		{"x-1./-1-1.", Signed{}, 0, 1},
+		{"x-1./-1-1.", Counting{}, 0, 0},
+		{"x-1./-1-1.", Subset{}, 0, 0},
+		{"x-1./-1-1.", Frontier{}, 0, 0},
+		// a zero next to an anti-mine proves nothing, where the classic rules would clear its neighbors
+		{"*_x/...", Signed{}, 0, 0},
+		{"*_x/...", Counting{}, 0, 0},
+		// classic positions are solved as Frontier would
+		{"*.*/121/___", Signed{}, 1, 2},
+	}
+
+	for _, testcase := range cases {
+		b, err := msboard.ParseLayout(testcase.layout)
+		if err != nil {
+			t.Fatalf("ParseLayout(%q) failed: %s", testcase.layout, err)
+		}
+		safe, mines := testcase.solver.Deductions(b.Snapshot())
+		if len(safe) != testcase.safe || len(mines) != testcase.mines {
+			t.Errorf("%T on %q wanted %d safe, %d mines got %v, %v", testcase.solver, testcase.layout,
+				testcase.safe, testcase.mines, safe, mines)
+		}
+	}
+}
+
+func TestSignedSoundness(t *testing.T) {
+	// playing out real anti-mine games on Signed's safe cells, every deduction must be right
+	for seed := int64(1); seed <= 10; seed++ {
+		rand.Seed(seed)
+		b := msboard.NewBoard("medium")
+		if _, err := b.FirstClickWithOptions(msboard.NewLocation(8, 8), msboard.GeneratorOptions{AntiMines: 12}); err != nil {
+			t.Fatalf("seed %d: FirstClickWithOptions failed: %s", seed, err)
+		}
+		if _, err := (Frontier{}).Probabilities(b.Snapshot()); err != ErrRules {
+			t.Errorf("seed %d: Frontier wanted ErrRules got %v", seed, err)
+		}
+
+		for b.Status() == msboard.StatusPlaying {
+			safe, mines := Signed{}.Deductions(b.Snapshot())
+			for _, l := range mines {
+				if !b.MineAt(l) {
+					t.Fatalf("seed %d: %v deduced a mine but is safe", seed, l)
+				}
+			}
+			if len(safe) == 0 {
+				break
+			}
+			for _, l := range safe {
+				if b.MineAt(l) {
+					t.Fatalf("seed %d: %v deduced safe but holds a mine", seed, l)
+				}
+				b.Click(l)
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+/*
+
+	Adaptive.go - choosing the mine density of a player's next board from how their recent games went, to keep
+	them challenged without being swamped
+
+	A DensityPolicy sees the player's recent adaptive games and the density they were last played at, and returns
+	the next density with a reason the player is shown. ChallengeBand, the default, aims to keep the win rate inside
+	a band: above it boards get denser, below it sparser, and inside it the speed of the latest win, in 3BV per
+	second, against the wins before it nudges the density up or down.
+
+	mike@pocomotech.com
+
+*/
+
+package msstats
+
+import (
+	"fmt"
+)
+
+// AdaptiveDifficulty : the difficulty adaptive games are recorded under
+const AdaptiveDifficulty = "adaptive"
+
+// DensityPolicy : decides the density of the next adaptive board from the recent adaptive games, oldest first,
+// and the density of the last one
+type DensityPolicy interface {
+	Name() string
+	Next(recent []Record, current float64) (density float64, reason string)
+}
+
+// ChallengeBand : keeps the win rate over the last Window games between Low and High, moving the density by Step
+// at a time and never outside MinDensity and MaxDensity
+type ChallengeBand struct {
+	Low, High              float64
+	Window                 int
+	Step                   float64
+	MinDensity, MaxDensity float64
+}
+
+// DefaultChallengeBand -- winning between 40% and 70% of the last 10 games, a point of density at a time, between
+// the beginner board's density and a little past the expert board's
+func DefaultChallengeBand() ChallengeBand {
+	return ChallengeBand{Low: 0.4, High: 0.7, Window: 10, Step: 0.01, MinDensity: 0.10, MaxDensity: 0.22}
+}
+
+// speedMargin : how much faster or slower than the earlier wins the latest must be to move the density inside
+// the band
+const speedMargin = 0.2
+
+// Name -- the policy as the player sees it
+func (p ChallengeBand) Name() string {
+	return fmt.Sprintf("win %.0f%%-%.0f%% of the last %d", 100*p.Low, 100*p.High, p.Window)
+}
+
+// Next -- the density of the next board, and why
+func (p ChallengeBand) Next(recent []Record, current float64) (float64, string) {
+	if len(recent) > p.Window {
+		recent = recent[len(recent)-p.Window:]
+	}
+	if len(recent) == 0 {
+		return p.clamp(current), "no adaptive games yet"
+	}
+
+	wins, speeds := 0, []float64{}
+	for _, r := range recent {
+		if r.Won {
+			wins++
+			if r.Time > 0 {
+				speeds = append(speeds, float64(r.ThreeBV)/r.Time)
+			}
+		}
+	}
+	rate := float64(wins) / float64(len(recent))
+	won := fmt.Sprintf("won %d of %d", wins, len(recent))
+	switch {
+	case rate > p.High:
+		return p.clamp(current + p.Step), won + ", above the band: more mines"
+	case rate < p.Low:
+		return p.clamp(current - p.Step), won + ", below the band: fewer mines"
+	}
+
+	// inside the band, the latest win's speed against the wins before it
+	if last := len(speeds) - 1; last > 0 && recent[len(recent)-1].Won {
+		earlier := 0.0
+		for _, s := range speeds[:last] {
+			earlier += s
+		}
+		earlier /= float64(last)
+		switch {
+		case speeds[last] > earlier*(1+speedMargin):
+			return p.clamp(current + p.Step/2), won + " and winning faster: a few more mines"
+		case speeds[last] < earlier*(1-speedMargin):
+			return p.clamp(current - p.Step/2), won + " and winning slower: a few fewer mines"
+		}
+	}
+	return p.clamp(current), won + ", inside the band"
+}
+
+// clamp -- a density kept between the policy's bounds
+func (p ChallengeBand) clamp(density float64) float64 {
+	if density < p.MinDensity {
+		return p.MinDensity
+	}
+	if density > p.MaxDensity {
+		return p.MaxDensity
+	}
+	return density
+}
+
+// Adaptive -- the adaptive games of the history, oldest first
+func (h History) Adaptive() []Record {
+	var retval []Record
+	for _, r := range h.Games {
+		if r.Difficulty == AdaptiveDifficulty {
+			retval = append(retval, r)
+		}
+	}
+	return retval
+}
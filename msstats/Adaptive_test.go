@@ -0,0 +1,56 @@
+/*
+	Test functions for the adaptive density policy
+
+	mike@pocomotech.com
+*/
+
+package msstats
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestChallengeBand(t *testing.T) {
+	p := DefaultChallengeBand()
+	m := Metrics{ThreeBV: 40, Density: 0.12}
+	win := func(seconds float64) Record {
+		return Record{Difficulty: AdaptiveDifficulty, Metrics: m, Won: true, Time: seconds}
+	}
+	loss := Record{Difficulty: AdaptiveDifficulty, Metrics: m, Time: 10}
+
+	var cases = []struct {
+		recent  []Record
+		current float64
+		want    float64
+		reason  string
+	}{
+		{nil, 0.12, 0.12, "no adaptive games yet"},
+		{[]Record{win(40), win(40), win(40)}, 0.12, 0.13, "won 3 of 3, above the band"},
+		{[]Record{loss, loss, win(40)}, 0.12, 0.11, "won 1 of 3, below the band"},
+		{[]Record{loss, win(40), win(40)}, 0.12, 0.12, "inside the band"},
+		{[]Record{loss, win(40), win(20)}, 0.12, 0.125, "winning faster"},
+		{[]Record{loss, win(40), win(80)}, 0.12, 0.115, "winning slower"},
+		{[]Record{win(40), win(40), win(40)}, 0.22, 0.22, "above the band"},
+		{[]Record{loss, loss, loss}, 0.10, 0.10, "below the band"},
+		// only the last Window games count
+		{append([]Record{loss, loss, loss, loss, loss}, []Record{win(40), win(40), win(40), win(40), win(40),
+			win(40), win(40), win(40), win(40), win(40)}...), 0.12, 0.13, "won 10 of 10"},
+	}
+	for _, c := range cases {
+		density, reason := p.Next(c.recent, c.current)
+		if math.Abs(density-c.want) > 1e-9 || !strings.Contains(reason, c.reason) {
+			t.Errorf("Next(%d games, %v) wanted %v %q got %v %q", len(c.recent), c.current, c.want, c.reason,
+				density, reason)
+		}
+	}
+	if p.Name() != "win 40%-70% of the last 10" {
+		t.Errorf("policy name wrong: %q", p.Name())
+	}
+
+	h := History{Games: []Record{loss, {Difficulty: "easy"}, win(40)}}
+	if adaptive := h.Adaptive(); len(adaptive) != 2 || !adaptive[1].Won {
+		t.Errorf("Adaptive wanted the 2 adaptive games got %+v", adaptive)
+	}
+}
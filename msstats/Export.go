@@ -0,0 +1,118 @@
+/*
+
+	Export.go - the game history as CSV or JSON, one row or object per game, for spreadsheets and dashboards
+
+	mike@pocomotech.com
+
+*/
+
+package msstats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Export formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// csvHeader : column names of a CSV export, in order
+var csvHeader = []string{"date", "difficulty", "seed", "result", "time", "moves", "3bv", "3bv_per_second", "density",
+	"rated"}
+
+// exportRecord : one game as exported to JSON, with the result spelled out and speed worked out for the reader
+type exportRecord struct {
+	Date       time.Time `json:"date"`
+	Difficulty string    `json:"difficulty"`
+	Seed       int64     `json:"seed"`
+	Result     string    `json:"result"`
+	Time       float64   `json:"time"`
+	Moves      int       `json:"moves"`
+	ThreeBV    int       `json:"3bv"`
+	Speed      float64   `json:"3bv_per_second"`
+	Density    float64   `json:"density"`
+	Rated      bool      `json:"rated"`
+}
+
+// Result -- "won" or "lost"
+func (r Record) Result() string {
+	if r.Won {
+		return "won"
+	}
+	return "lost"
+}
+
+// Speed -- 3BV per second of play, 0 for a game with no time on the clock
+func (r Record) Speed() float64 {
+	if r.Time <= 0 {
+		return 0
+	}
+	return float64(r.ThreeBV) / r.Time
+}
+
+// Export -- write the games in a format, FormatCSV or FormatJSON
+func Export(w io.Writer, games []Record, format string) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, games)
+	case FormatJSON:
+		return WriteJSON(w, games)
+	}
+	return fmt.Errorf("unsupported export format %q, use %s or %s", format, FormatCSV, FormatJSON)
+}
+
+// WriteCSV -- write the games as CSV with a header row, dates in RFC 3339 and times in seconds
+func WriteCSV(w io.Writer, games []Record) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range games {
+		err := cw.Write([]string{
+			r.Date.Format(time.RFC3339),
+			r.Difficulty,
+			strconv.FormatInt(r.Seed, 10),
+			r.Result(),
+			strconv.FormatFloat(r.Time, 'f', 3, 64),
+			strconv.Itoa(r.Moves),
+			strconv.Itoa(r.ThreeBV),
+			strconv.FormatFloat(r.Speed(), 'f', 3, 64),
+			strconv.FormatFloat(r.Density, 'f', 4, 64),
+			strconv.FormatBool(r.Rated),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON -- write the games as an indented JSON array of objects with the same fields as the CSV columns
+func WriteJSON(w io.Writer, games []Record) error {
+	out := make([]exportRecord, 0, len(games))
+	for _, r := range games {
+		out = append(out, exportRecord{
+			Date:       r.Date,
+			Difficulty: r.Difficulty,
+			Seed:       r.Seed,
+			Result:     r.Result(),
+			Time:       r.Time,
+			Moves:      r.Moves,
+			ThreeBV:    r.ThreeBV,
+			Speed:      r.Speed(),
+			Density:    r.Density,
+			Rated:      r.Rated,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,73 @@
+/*
+	Test functions for exporting the game history
+
+	mike@pocomotech.com
+*/
+
+package msstats
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+var exportGames = []Record{
+	{Date: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), Difficulty: "easy", Seed: 1995,
+		Metrics: Metrics{ThreeBV: 30, Density: 10.0 / 81}, Won: true, Time: 15, Moves: 22, Rated: true},
+	{Date: time.Date(2024, 3, 2, 9, 30, 0, 0, time.UTC), Difficulty: "hard", Seed: -7,
+		Metrics: Metrics{ThreeBV: 250, Density: 99.0 / 480}, Time: 40.5, Moves: 31},
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(&buf, exportGames, FormatCSV); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || len(rows[0]) != len(csvHeader) || rows[0][0] != "date" {
+		t.Fatalf("rows %q", rows)
+	}
+	want := []string{"2024-03-01T12:00:00Z", "easy", "1995", "won", "15.000", "22", "30", "2.000", "0.1235", "true"}
+	for i, v := range want {
+		if rows[1][i] != v {
+			t.Errorf("column %s is %q, want %q", csvHeader[i], rows[1][i], v)
+		}
+	}
+	if rows[2][2] != "-7" || rows[2][3] != "lost" || rows[2][9] != "false" {
+		t.Errorf("second game %q", rows[2])
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(&buf, exportGames, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	var games []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &games); err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 2 || games[0]["result"] != "won" || games[0]["3bv"] != 30.0 ||
+		games[0]["3bv_per_second"] != 2.0 || games[1]["result"] != "lost" || games[1]["seed"] != -7.0 {
+		t.Errorf("games %v", games)
+	}
+
+	// no games is an empty array, not null
+	buf.Reset()
+	WriteJSON(&buf, nil)
+	if buf.String() != "[]\n" {
+		t.Errorf("no games exported as %q", buf.String())
+	}
+}
+
+func TestExportFormat(t *testing.T) {
+	if err := Export(&bytes.Buffer{}, exportGames, "xml"); err == nil {
+		t.Error("unsupported format accepted")
+	}
+}
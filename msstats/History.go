@@ -0,0 +1,122 @@
+/*
+
+	History.go - the record of a player's finished games, kept as a JSON file so statistics and the rating carry
+	over from one session to the next
+
+	mike@pocomotech.com
+
+*/
+
+package msstats
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+)
+
+// Record : one finished game
+type Record struct {
+	Date       time.Time `json:"date"` // when the game finished
+	Difficulty string    `json:"difficulty"`
+	Seed       int64     `json:"seed"`
+	Metrics
+	Won   bool    `json:"won"`
+	Time  float64 `json:"time"` // seconds on the clock, pauses excluded
+	Moves int     `json:"moves"`
+	Rated bool    `json:"rated"` // played straight by the standard rules; only rated games move the rating
+}
+
+// Played -- time on the clock as a duration
+func (r Record) Played() time.Duration {
+	return time.Duration(r.Time * float64(time.Second))
+}
+
+// History : finished games, oldest first
+type History struct {
+	Games []Record `json:"games"`
+}
+
+// Summary : totals for the games of one difficulty
+type Summary struct {
+	Difficulty string
+	Played     int
+	Won        int
+	Best       time.Duration // fastest rated win, 0 if none
+}
+
+// Add -- append a finished game
+func (h *History) Add(r Record) {
+	h.Games = append(h.Games, r)
+}
+
+// Rating -- the rating earned by the rated games, played in order from a new player's rating
+func (h History) Rating() Rating {
+	retval := NewRating()
+	for _, r := range h.Games {
+		if r.Rated {
+			retval = retval.Update(r.Metrics, r.Won, r.Played())
+		}
+	}
+	return retval
+}
+
+// Summaries -- totals for each difficulty played, by name
+func (h History) Summaries() []Summary {
+	byName := map[string]*Summary{}
+	var retval []Summary
+	for _, r := range h.Games {
+		s, ok := byName[r.Difficulty]
+		if !ok {
+			s = &Summary{Difficulty: r.Difficulty}
+			byName[r.Difficulty] = s
+		}
+		s.Played++
+		if r.Won {
+			s.Won++
+			if r.Rated && (s.Best == 0 || r.Played() < s.Best) {
+				s.Best = r.Played()
+			}
+		}
+	}
+
+	for _, s := range byName {
+		retval = append(retval, *s)
+	}
+	sort.Slice(retval, func(i, j int) bool { return retval[i].Difficulty < retval[j].Difficulty })
+	return retval
+}
+
+// SaveHistory -- write the history to a file, replacing any existing one
+func SaveHistory(filename string, h *History) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(h); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadHistory -- read the history from a file; a file that doesn't exist yet is an empty history
+func LoadHistory(filename string) (*History, error) {
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return &History{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	retval := &History{}
+	if err := json.NewDecoder(f).Decode(retval); err != nil {
+		return nil, err
+	}
+	return retval, nil
+}
@@ -0,0 +1,54 @@
+/*
+	Test functions for the game history
+
+	mike@pocomotech.com
+*/
+
+package msstats
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "history.json")
+	h, err := LoadHistory(filename)
+	if err != nil || len(h.Games) != 0 {
+		t.Fatalf("missing file: %v, %d games", err, len(h.Games))
+	}
+
+	m := Metrics{ThreeBV: 30, Density: 10.0 / 81}
+	h.Add(Record{Difficulty: "beginner", Metrics: m, Won: true, Time: 20, Rated: true})
+	h.Add(Record{Difficulty: "beginner", Metrics: m, Won: true, Time: 10})
+	h.Add(Record{Difficulty: "beginner", Metrics: m, Won: false, Time: 5, Rated: true})
+	h.Add(Record{Difficulty: "expert", Metrics: m, Won: false, Time: 5, Rated: true})
+	if err := SaveHistory(filename, h); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadHistory(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Games) != 4 || loaded.Games[0].ThreeBV != 30 {
+		t.Fatalf("loaded %+v", loaded.Games)
+	}
+
+	s := loaded.Summaries()
+	if len(s) != 2 || s[0].Difficulty != "beginner" || s[1].Difficulty != "expert" {
+		t.Fatalf("summaries %+v", s)
+	}
+	// the unrated win is faster, but only rated wins set the best time
+	if s[0].Played != 3 || s[0].Won != 2 || s[0].Best != 20*time.Second {
+		t.Errorf("beginner %+v", s[0])
+	}
+	if s[1].Played != 1 || s[1].Won != 0 || s[1].Best != 0 {
+		t.Errorf("expert %+v", s[1])
+	}
+
+	if r := loaded.Rating(); r.Games != 3 {
+		t.Errorf("rating from %d games, want the 3 rated", r.Games)
+	}
+}
@@ -0,0 +1,271 @@
+/*
+
+	Import.go - bring game records from other clients, or another copy of this one, into the history
+
+	Other clients' CSV exports are read by their header row, so columns can come in any order and ones this history
+	has no use for are skipped. Games already in the history, by when they finished, difficulty, result, time and
+	3BV, are left out, so an export can be imported again after more games are added to it.
+
+	mike@pocomotech.com
+
+*/
+
+package msstats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Import formats, besides FormatCSV and FormatJSON from this client's own export
+const (
+	FormatMinesweeperOnline = "msonline" // CSV game list exported by minesweeper.online
+)
+
+// classicLevels : the standard boards other clients name, as rows, columns and mines
+var classicLevels = map[string][3]int{
+	"beginner":     {9, 9, 10},
+	"intermediate": {16, 16, 40},
+	"expert":       {16, 30, 99},
+}
+
+// onlineColumns : header names minesweeper.online and similar clients use for each field, lower case
+var onlineColumns = map[string][]string{
+	"date":   {"date", "started", "start time", "finished", "played"},
+	"level":  {"level", "difficulty", "mode"},
+	"result": {"result", "status", "outcome", "won"},
+	"time":   {"time", "duration", "seconds"},
+	"3bv":    {"3bv", "bbbv", "3bv total"},
+	"moves":  {"clicks", "moves", "total clicks"},
+	"width":  {"width", "columns", "cols"},
+	"height": {"height", "rows"},
+	"mines":  {"mines", "bombs"},
+}
+
+// dateLayouts : date formats seen in CSV exports, tried in order
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"02.01.2006 15:04:05",
+	"02.01.2006 15:04",
+	"01/02/2006 15:04:05",
+	"01/02/2006 15:04",
+	"01/02/2006",
+}
+
+// Import -- read game records in a format: FormatCSV or FormatJSON as written by Export, or FormatMinesweeperOnline
+func Import(r io.Reader, format string) ([]Record, error) {
+	switch format {
+	case FormatJSON:
+		return readJSON(r)
+	case FormatCSV, FormatMinesweeperOnline:
+		// this client's export is a CSV with known column names too
+		return readCSV(r, format)
+	}
+	return nil, fmt.Errorf("unsupported import format %q, use %s, %s or %s", format, FormatCSV, FormatJSON,
+		FormatMinesweeperOnline)
+}
+
+// readJSON -- records from this client's JSON export
+func readJSON(r io.Reader) ([]Record, error) {
+	var games []exportRecord
+	if err := json.NewDecoder(r).Decode(&games); err != nil {
+		return nil, err
+	}
+	retval := make([]Record, 0, len(games))
+	for _, e := range games {
+		retval = append(retval, Record{
+			Date:       e.Date,
+			Difficulty: e.Difficulty,
+			Seed:       e.Seed,
+			Metrics:    Metrics{ThreeBV: e.ThreeBV, Density: e.Density},
+			Won:        e.Result == "won",
+			Time:       e.Time,
+			Moves:      e.Moves,
+			Rated:      e.Rated,
+		})
+	}
+	return retval, nil
+}
+
+// readCSV -- records from a CSV with a header row, this client's own or another's
+func readCSV(r io.Reader, format string) ([]Record, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("no header row")
+	}
+
+	names := onlineColumns
+	if format == FormatCSV {
+		names = map[string][]string{}
+		for _, column := range csvHeader {
+			names[column] = []string{column}
+		}
+		names["level"] = []string{"difficulty"}
+	}
+	columns := map[string]int{}
+	for i, heading := range rows[0] {
+		heading = strings.ToLower(strings.TrimSpace(heading))
+		for field, aliases := range names {
+			for _, alias := range aliases {
+				if _, seen := columns[field]; !seen && heading == alias {
+					columns[field] = i
+				}
+			}
+		}
+	}
+	for _, field := range []string{"date", "level", "result", "time"} {
+		if _, ok := columns[field]; !ok {
+			return nil, fmt.Errorf("no %s column in the header %q", field, rows[0])
+		}
+	}
+
+	retval := make([]Record, 0, len(rows)-1)
+	for n, row := range rows[1:] {
+		get := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		rec, err := csvRecord(get, format)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n+2, err)
+		}
+		retval = append(retval, rec)
+	}
+	return retval, nil
+}
+
+// csvRecord -- one game from the fields of a CSV row
+func csvRecord(get func(field string) string, format string) (Record, error) {
+	var retval Record
+	var err error
+	if retval.Date, err = parseDate(get("date")); err != nil {
+		return Record{}, err
+	}
+	if retval.Time, err = parseSeconds(get("time")); err != nil {
+		return Record{}, err
+	}
+	switch strings.ToLower(get("result")) {
+	case "won", "win", "true", "1", "solved", "success":
+		retval.Won = true
+	case "lost", "loss", "lose", "false", "0", "failed", "fail", "blown":
+	default:
+		return Record{}, fmt.Errorf("unknown result %q", get("result"))
+	}
+
+	retval.Difficulty = strings.ToLower(get("level"))
+	if v := get("3bv"); v != "" {
+		if retval.ThreeBV, err = strconv.Atoi(v); err != nil {
+			return Record{}, fmt.Errorf("bad 3BV %q", v)
+		}
+	}
+	if v := get("moves"); v != "" {
+		if retval.Moves, err = strconv.Atoi(v); err != nil {
+			return Record{}, fmt.Errorf("bad move count %q", v)
+		}
+	}
+
+	if format == FormatCSV {
+		// this client's own export carries everything
+		if retval.Seed, err = strconv.ParseInt(get("seed"), 10, 64); err != nil {
+			return Record{}, fmt.Errorf("bad seed %q", get("seed"))
+		}
+		if retval.Density, err = strconv.ParseFloat(get("density"), 64); err != nil {
+			return Record{}, fmt.Errorf("bad density %q", get("density"))
+		}
+		retval.Rated = get("rated") == "true"
+		return retval, nil
+	}
+
+	// the classic boards are played straight; boards of other sizes are custom games, and not rated
+	size, classic := classicLevels[retval.Difficulty]
+	width, _ := strconv.Atoi(get("width"))
+	height, _ := strconv.Atoi(get("height"))
+	mines, _ := strconv.Atoi(get("mines"))
+	if width > 0 && height > 0 && mines > 0 {
+		classic = classic && (size == [3]int{height, width, mines} || size == [3]int{width, height, mines})
+		size = [3]int{height, width, mines}
+	}
+	if size[0] > 0 {
+		retval.Density = float64(size[2]) / float64(size[0]*size[1])
+	}
+	retval.Rated = classic && retval.ThreeBV > 0
+	return retval, nil
+}
+
+// parseDate -- a date in any of the dateLayouts, taken as UTC when it has no zone
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", s)
+}
+
+// parseSeconds -- a time in seconds, written as 12.345 or as minutes and seconds, 1:02.345
+func parseSeconds(s string) (float64, error) {
+	minutes := 0
+	if i := strings.LastIndex(s, ":"); i >= 0 {
+		var err error
+		if minutes, err = strconv.Atoi(s[:i]); err != nil {
+			return 0, fmt.Errorf("bad time %q", s)
+		}
+		s = s[i+1:]
+	}
+	seconds, err := strconv.ParseFloat(strings.TrimSuffix(s, "s"), 64)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("bad time %q", s)
+	}
+	return float64(minutes*60) + seconds, nil
+}
+
+// key : what makes two records the same game, whichever client wrote them
+type key struct {
+	date       int64 // seconds since the epoch
+	difficulty string
+	won        bool
+	millis     int64
+	threeBV    int
+}
+
+// keyOf -- the identity of a record, with times rounded to what exports keep
+func keyOf(r Record) key {
+	return key{r.Date.Unix(), r.Difficulty, r.Won, int64(r.Time*1000 + 0.5), r.ThreeBV}
+}
+
+// Merge -- add the games not already in the history, keeping it in date order so the rating is earned in the order
+// the games were played. Returns the number added
+func (h *History) Merge(games []Record) int {
+	seen := map[key]bool{}
+	for _, r := range h.Games {
+		seen[keyOf(r)] = true
+	}
+
+	added := 0
+	for _, r := range games {
+		if k := keyOf(r); !seen[k] {
+			seen[k] = true
+			h.Games = append(h.Games, r)
+			added++
+		}
+	}
+	sort.SliceStable(h.Games, func(i, j int) bool { return h.Games[i].Date.Before(h.Games[j].Date) })
+	return added
+}
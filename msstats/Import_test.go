@@ -0,0 +1,94 @@
+/*
+	Test functions for importing game records
+
+	mike@pocomotech.com
+*/
+
+package msstats
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImportMinesweeperOnline(t *testing.T) {
+	csv := `Date,Level,Result,Time,3BV,Clicks,Efficiency
+2023-05-01 10:15:00,Beginner,Won,12.345,28,35,80%
+2023-05-01 10:20:00,Expert,Lost,1:02.5,180,90,
+2023-05-02 08:00:00,Custom,Won,30,50,60,83%
+`
+	games, err := Import(strings.NewReader(csv), FormatMinesweeperOnline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 3 {
+		t.Fatalf("games %+v", games)
+	}
+
+	g := games[0]
+	if !g.Date.Equal(time.Date(2023, 5, 1, 10, 15, 0, 0, time.UTC)) || g.Difficulty != "beginner" || !g.Won ||
+		g.Time != 12.345 || g.ThreeBV != 28 || g.Moves != 35 || g.Density != 10.0/81 || !g.Rated {
+		t.Errorf("beginner game %+v", g)
+	}
+	if g := games[1]; g.Won || g.Time != 62.5 || g.Density != 99.0/480 || !g.Rated {
+		t.Errorf("expert game %+v", g)
+	}
+	// a custom board's density isn't known, so it can't be rated
+	if g := games[2]; g.Rated || g.Density != 0 {
+		t.Errorf("custom game %+v", g)
+	}
+
+	for _, bad := range []string{
+		"Level,Result,Time\nBeginner,Won,10\n",
+		"Date,Level,Result,Time\nyesterday,Beginner,Won,10\n",
+		"Date,Level,Result,Time\n2023-05-01,Beginner,Drawn,10\n",
+		"Date,Level,Result,Time\n2023-05-01,Beginner,Won,ten\n",
+	} {
+		if _, err := Import(strings.NewReader(bad), FormatMinesweeperOnline); err == nil {
+			t.Errorf("imported %q", bad)
+		}
+	}
+}
+
+func TestImportOwnExport(t *testing.T) {
+	for _, format := range []string{FormatCSV, FormatJSON} {
+		var buf bytes.Buffer
+		Export(&buf, exportGames, format)
+		games, err := Import(&buf, format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if len(games) != len(exportGames) {
+			t.Fatalf("%s: %+v", format, games)
+		}
+		for i, g := range games {
+			want := exportGames[i]
+			if !g.Date.Equal(want.Date) || g.Difficulty != want.Difficulty || g.Seed != want.Seed ||
+				g.Won != want.Won || g.Time != want.Time || g.Moves != want.Moves || g.ThreeBV != want.ThreeBV ||
+				g.Rated != want.Rated {
+				t.Errorf("%s: game %d is %+v, want %+v", format, i, g, want)
+			}
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	h := &History{}
+	if added := h.Merge(exportGames); added != 2 {
+		t.Errorf("added %d to an empty history", added)
+	}
+
+	// the same games again, through an export that rounds them, and one older game
+	var buf bytes.Buffer
+	Export(&buf, exportGames, FormatCSV)
+	games, _ := Import(&buf, FormatCSV)
+	older := Record{Date: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Difficulty: "easy", Time: 9}
+	if added := h.Merge(append(games, older)); added != 1 {
+		t.Errorf("added %d, want only the older game", added)
+	}
+	if len(h.Games) != 3 || h.Games[0] != older {
+		t.Errorf("history not in date order: %+v", h.Games)
+	}
+}
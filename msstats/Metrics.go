@@ -0,0 +1,101 @@
+/*
+
+	Metrics.go - how hard a board is to clear, measured from its mines alone so any player's game on it can be
+	compared: 3BV, the fewest clicks that clear it, and mine density
+
+	mike@pocomotech.com
+
+*/
+
+// Package msstats -- player statistics: the history of finished games, the difficulty metrics of the boards they
+// were played on, and the skill rating worked out from them
+package msstats
+
+import (
+	"go-mines/msboard"
+)
+
+// Metrics : difficulty of a board, whoever plays it
+type Metrics struct {
+	ThreeBV int     `json:"3bv"`     // Bechtel's Board Benchmark Value, the fewest clicks that clear the board
+	Density float64 `json:"density"` // mines per cell
+}
+
+// MetricsOf -- the metrics of a board whose mines are placed; the zero Metrics for one whose aren't
+func MetricsOf(b *msboard.Board) Metrics {
+	if nil == b || !b.Initialized() {
+		return Metrics{}
+	}
+	return Metrics{
+		ThreeBV: ThreeBV(b),
+		Density: float64(b.MineCount()) / float64(b.Rows()*b.Cols()),
+	}
+}
+
+// ThreeBV -- the fewest clicks that clear a board without flags: one for each opening, a connected region of cells
+// with no mine around them together with the numbers bordering it, plus one for every safe cell outside the
+// openings. Neighbors follow the board's topology; 0 for a board whose mines aren't placed
+func ThreeBV(b *msboard.Board) int {
+	if nil == b || !b.Initialized() {
+		return 0
+	}
+	s := b.Snapshot()
+
+	// safe cells with no mine around them, which open their neighbors when clicked
+	open := make([][]bool, b.Rows())
+	for row := range open {
+		open[row] = make([]bool, b.Cols())
+		for col := range open[row] {
+			l := msboard.NewLocation(row, col)
+			if b.MineAt(l) {
+				continue
+			}
+			open[row][col] = true
+			for _, n := range s.Neighbors(l) {
+				if b.MineAt(n) {
+					open[row][col] = false
+					break
+				}
+			}
+		}
+	}
+
+	retval := 0
+	cleared := make([][]bool, b.Rows())
+	for row := range cleared {
+		cleared[row] = make([]bool, b.Cols())
+	}
+	for row := range open {
+		for col := range open[row] {
+			if !open[row][col] || cleared[row][col] {
+				continue
+			}
+			// one click clears the whole opening
+			retval++
+			cleared[row][col] = true
+			for pending := []msboard.Location{msboard.NewLocation(row, col)}; len(pending) > 0; {
+				l := pending[len(pending)-1]
+				pending = pending[:len(pending)-1]
+				for _, n := range s.Neighbors(l) {
+					if cleared[n.Row()][n.Col()] {
+						continue
+					}
+					cleared[n.Row()][n.Col()] = true
+					if open[n.Row()][n.Col()] {
+						pending = append(pending, n)
+					}
+				}
+			}
+		}
+	}
+
+	// every other safe cell takes a click of its own
+	for row := range cleared {
+		for col := range cleared[row] {
+			if !cleared[row][col] && !b.MineAt(msboard.NewLocation(row, col)) {
+				retval++
+			}
+		}
+	}
+	return retval
+}
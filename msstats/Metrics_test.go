@@ -0,0 +1,46 @@
+/*
+	Test functions for board metrics
+
+	mike@pocomotech.com
+*/
+
+package msstats
+
+import (
+	"go-mines/msboard"
+	"testing"
+)
+
+func TestThreeBV(t *testing.T) {
+	for _, tc := range []struct {
+		layout string
+		want   int
+	}{
+		// one opening clears everything
+		{"*1../11../..../....", 1},
+		// an opening, and two numbers cut off from it by mines
+		{"1*2*/1121/....", 3},
+		// no openings at all: every safe cell is a click
+		{"*2*/2.2/*2*", 5},
+	} {
+		b, err := msboard.ParseLayout(tc.layout)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.layout, err)
+		}
+		if got := ThreeBV(b); got != tc.want {
+			t.Errorf("%q: 3BV %d, want %d", tc.layout, got, tc.want)
+		}
+	}
+
+	if got := ThreeBV(msboard.NewBoard("beginner")); got != 0 {
+		t.Errorf("board with no mines laid: 3BV %d, want 0", got)
+	}
+}
+
+func TestMetricsOf(t *testing.T) {
+	b, _ := msboard.ParseLayout("*1../11../..../....")
+	m := MetricsOf(b)
+	if m.ThreeBV != 1 || m.Density != 1.0/16 {
+		t.Errorf("metrics %+v, want 3BV 1 and density 1/16", m)
+	}
+}
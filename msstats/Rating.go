@@ -0,0 +1,79 @@
+/*
+
+	Rating.go - an Elo style skill rating: every rated game is a match between the player and the board, whose own
+	rating comes from its metrics, and the player's rating moves by how much better or worse the game went than
+	their ratings predicted
+
+	Winning scores between a half and a whole point depending on speed in 3BV per second, so fast clears count for
+	more than slow ones; losing scores nothing. The constants put the classic beginner, intermediate and expert
+	boards near 1200, 1500 and 1800.
+
+	mike@pocomotech.com
+
+*/
+
+package msstats
+
+import (
+	"math"
+	"time"
+)
+
+// Rating constants
+const (
+	InitialRating     = 1500.0 // a new player's rating
+	ProvisionalGames  = 20     // games played with the larger ProvisionalFactor while the rating settles
+	ProvisionalFactor = 40.0   // most a rating moves in one of the first games
+	Factor            = 20.0   // most a rating moves in one game after that
+	ExpertSpeed       = 2.0    // 3BV per second at which a win scores a whole point
+)
+
+// Rating : a player's skill as a single number, and how many rated games it is based on
+type Rating struct {
+	Value float64 `json:"value"`
+	Games int     `json:"games"`
+}
+
+// NewRating -- the rating of a player yet to play
+func NewRating() Rating {
+	return Rating{Value: InitialRating}
+}
+
+// BoardRating -- the rating a board plays at, higher for boards needing more clicks and more densely mined
+func BoardRating(m Metrics) float64 {
+	return 200 + 90*math.Log2(math.Max(float64(m.ThreeBV), 1)) + 4500*m.Density
+}
+
+// Expected -- the score a player rated player is expected to make against a board rated board, 0 to 1
+func Expected(player, board float64) float64 {
+	return 1 / (1 + math.Pow(10, (board-player)/400))
+}
+
+// Score -- what a game earns, 0 to 1: nothing for a loss, and for a win a half point plus up to a half point more
+// for speed, reaching a whole point at ExpertSpeed
+func Score(m Metrics, won bool, played time.Duration) float64 {
+	if !won {
+		return 0
+	}
+	if played <= 0 {
+		return 1
+	}
+	speed := float64(m.ThreeBV) / played.Seconds()
+	return 0.5 + 0.5*math.Min(speed/ExpertSpeed, 1)
+}
+
+// Update -- the rating after a game on a board with metrics m, won or lost in the time played
+func (r Rating) Update(m Metrics, won bool, played time.Duration) Rating {
+	k := Factor
+	if r.Games < ProvisionalGames {
+		k = ProvisionalFactor
+	}
+	r.Value += k * (Score(m, won, played) - Expected(r.Value, BoardRating(m)))
+	r.Games++
+	return r
+}
+
+// Provisional -- true while the rating is based on too few games to be settled
+func (r Rating) Provisional() bool {
+	return r.Games < ProvisionalGames
+}
@@ -0,0 +1,84 @@
+/*
+	Test functions for the skill rating
+
+	mike@pocomotech.com
+*/
+
+package msstats
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBoardRating(t *testing.T) {
+	// typical 3BV of the classic boards
+	for _, tc := range []struct {
+		name string
+		m    Metrics
+		want float64
+	}{
+		{"beginner", Metrics{ThreeBV: 30, Density: 10.0 / 81}, 1200},
+		{"intermediate", Metrics{ThreeBV: 120, Density: 40.0 / 256}, 1500},
+		{"expert", Metrics{ThreeBV: 250, Density: 99.0 / 480}, 1800},
+	} {
+		if got := BoardRating(tc.m); math.Abs(got-tc.want) > 100 {
+			t.Errorf("%s rated %.0f, want about %.0f", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	m := Metrics{ThreeBV: 100}
+	if got := Score(m, false, time.Second); got != 0 {
+		t.Errorf("loss scored %v", got)
+	}
+	if got := Score(m, true, 50*time.Second); got != 1 {
+		t.Errorf("win at expert speed scored %v, want 1", got)
+	}
+	if got := Score(m, true, 100*time.Second); got != 0.75 {
+		t.Errorf("win at half expert speed scored %v, want 0.75", got)
+	}
+	if got := Score(m, true, time.Hour); got <= 0.5 || got >= 0.51 {
+		t.Errorf("slow win scored %v, want just over 0.5", got)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	m := Metrics{ThreeBV: 120, Density: 40.0 / 256}
+	r := NewRating()
+
+	won := r.Update(m, true, 60*time.Second)
+	lost := r.Update(m, false, 60*time.Second)
+	if won.Value <= r.Value || lost.Value >= r.Value {
+		t.Errorf("from %.0f, a win went to %.0f and a loss to %.0f", r.Value, won.Value, lost.Value)
+	}
+	if won.Games != 1 || lost.Games != 1 {
+		t.Errorf("games %d and %d after one game", won.Games, lost.Games)
+	}
+	if math.Abs(won.Value-r.Value) > ProvisionalFactor || math.Abs(lost.Value-r.Value) > ProvisionalFactor {
+		t.Error("rating moved by more than the provisional factor")
+	}
+
+	// settled ratings move half as far
+	settled := Rating{Value: InitialRating, Games: ProvisionalGames}
+	if d, s := lost.Value-r.Value, settled.Update(m, false, 0).Value-settled.Value; math.Abs(d-2*s) > 1e-9 {
+		t.Errorf("provisional change %v, settled %v", d, s)
+	}
+	if !r.Provisional() || settled.Provisional() {
+		t.Error("provisional wrong")
+	}
+}
+
+func TestRatingConverges(t *testing.T) {
+	// winning every expert game in 100 seconds settles well above expert
+	m := Metrics{ThreeBV: 250, Density: 99.0 / 480}
+	r := NewRating()
+	for i := 0; i < 200; i++ {
+		r = r.Update(m, true, 100*time.Second)
+	}
+	if r.Value < BoardRating(m)+100 {
+		t.Errorf("rating %.0f after 200 fast expert wins, board %.0f", r.Value, BoardRating(m))
+	}
+}
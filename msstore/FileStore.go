@@ -0,0 +1,111 @@
+/*
+
+	FileStore.go - a store in a directory: a subdirectory for each kind and a JSON file for each item, so replays
+	land where -replays would put them and every file can be read on its own
+
+	mike@pocomotech.com
+
+*/
+
+package msstore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileStore : items kept as <dir>/<kind>/<key>.json
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore -- a store in a directory, created if it doesn't exist yet
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Dir -- the directory the items of a kind are kept in
+func (s *FileStore) Dir(kind Kind) string {
+	return filepath.Join(s.dir, string(kind))
+}
+
+// Put -- write an item to its file, replacing any existing one
+func (s *FileStore) Put(kind Kind, key string, data []byte) error {
+	return s.PutAt(kind, key, data, time.Time{})
+}
+
+// PutAt -- write an item to its file, replacing any existing one, and give the file a modification time; the zero
+// time leaves it as written
+func (s *FileStore) PutAt(kind Kind, key string, data []byte, at time.Time) error {
+	if err := checkKey(kind, key); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir(kind), 0755); err != nil {
+		return err
+	}
+
+	// written alongside and renamed into place, so a crash never leaves half an item
+	filename := filepath.Join(s.Dir(kind), key+".json")
+	if err := os.WriteFile(filename+".tmp", data, 0644); err != nil {
+		return err
+	}
+	if !at.IsZero() {
+		if err := os.Chtimes(filename+".tmp", at, at); err != nil {
+			return err
+		}
+	}
+	return os.Rename(filename+".tmp", filename)
+}
+
+// Get -- read an item from its file
+func (s *FileStore) Get(kind Kind, key string) ([]byte, error) {
+	if err := checkKey(kind, key); err != nil {
+		return nil, err
+	}
+	retval, err := os.ReadFile(filepath.Join(s.Dir(kind), key+".json"))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return retval, err
+}
+
+// List -- the keys of the JSON files of a kind
+func (s *FileStore) List(kind Kind) ([]string, error) {
+	stamps, err := s.Stamps(kind)
+	if err != nil {
+		return nil, err
+	}
+	return sortedKeys(stamps), nil
+}
+
+// Stamps -- the keys of the JSON files of a kind, with their modification times
+func (s *FileStore) Stamps(kind Kind) (map[string]time.Time, error) {
+	if err := checkKey(kind, "list"); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(s.Dir(kind))
+	if os.IsNotExist(err) {
+		return map[string]time.Time{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	retval := map[string]time.Time{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		retval[strings.TrimSuffix(entry.Name(), ".json")] = info.ModTime()
+	}
+	return retval, nil
+}
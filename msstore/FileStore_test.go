@@ -0,0 +1,47 @@
+/*
+	Test functions for the directory store
+
+	mike@pocomotech.com
+*/
+
+package msstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "store")
+	s, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, s)
+
+	// items are plain files, and other files are left out of the lists
+	if _, err := os.Stat(filepath.Join(dir, "replays", "a.json")); err != nil {
+		t.Error(err)
+	}
+	os.WriteFile(filepath.Join(dir, "replays", "notes.txt"), []byte("not an item"), 0644)
+	if keys, _ := s.List(KindReplays); len(keys) != 3 {
+		t.Errorf("listed %q", keys)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	if s, err := Open(dir); err != nil {
+		t.Error(err)
+	} else if _, ok := s.(*FileStore); !ok {
+		t.Errorf("directory opened as %T", s)
+	}
+
+	// without -tags sqlite there's no driver, and the error says how to get one
+	if sqliteDriver == "" {
+		if s, err := Open("sqlite:" + filepath.Join(dir, "games.db")); err == nil || s != nil {
+			t.Errorf("opened %v without a SQLite driver", s)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+/*
+
+	Items.go - games, replays and puzzle stats read from and written to any store
+
+	mike@pocomotech.com
+
+*/
+
+package msstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go-mines/msanalysis"
+	"go-mines/mspuzzle"
+	"go-mines/msreplay"
+	"go-mines/msstats"
+	"strconv"
+	"time"
+)
+
+// puzzleStatsKey : the KindStats item puzzle completion is kept in
+const puzzleStatsKey = "puzzles"
+
+// GameKey -- the key a game is kept under: when it finished, to the nanosecond in UTC so keys sort by date, and
+// its seed
+func GameKey(r msstats.Record) string {
+	return r.Date.UTC().Format("20060102T150405.000000000Z") + "_" + strconv.FormatInt(r.Seed, 10)
+}
+
+// AddGame -- keep a finished game, replacing one with the same key
+func AddGame(s Store, r msstats.Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return s.Put(KindGames, GameKey(r), data)
+}
+
+// SaveHistory -- keep every game of a history, such as one merged from an import
+func SaveHistory(s Store, h *msstats.History) error {
+	for _, r := range h.Games {
+		if err := AddGame(s, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadHistory -- every game kept, oldest first
+func LoadHistory(s Store) (*msstats.History, error) {
+	keys, err := s.List(KindGames)
+	if err != nil {
+		return nil, err
+	}
+	retval := &msstats.History{}
+	for _, key := range keys {
+		data, err := s.Get(KindGames, key)
+		if err != nil {
+			return nil, err
+		}
+		var r msstats.Record
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, fmt.Errorf("game %s: %v", key, err)
+		}
+		retval.Add(r)
+	}
+	return retval, nil
+}
+
+// SimKey -- the key game i of a simulation run is kept under: when the run started, as GameKey dates games, then
+// the game's number, so keys sort by run and then in the order the games were played
+func SimKey(run time.Time, i int) string {
+	return run.UTC().Format("20060102T150405.000000000Z") + "_" + fmt.Sprintf("%09d", i)
+}
+
+// AddSimGame -- keep a simulated game under a key, see SimKey
+func AddSimGame(s Store, key string, g msanalysis.GameResult) error {
+	data, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+	return s.Put(KindSims, key, data)
+}
+
+// LoadSimGames -- every simulated game kept, in key order
+func LoadSimGames(s Store) ([]msanalysis.GameResult, error) {
+	keys, err := s.List(KindSims)
+	if err != nil {
+		return nil, err
+	}
+	retval := make([]msanalysis.GameResult, 0, len(keys))
+	for _, key := range keys {
+		data, err := s.Get(KindSims, key)
+		if err != nil {
+			return nil, err
+		}
+		var g msanalysis.GameResult
+		if err := json.Unmarshal(data, &g); err != nil {
+			return nil, fmt.Errorf("simulated game %s: %v", key, err)
+		}
+		retval = append(retval, g)
+	}
+	return retval, nil
+}
+
+// ReplayKey -- the key a replay is kept under, as the file name -replays gives it
+func ReplayKey(at time.Time) string {
+	return fmt.Sprintf("replay-%d", at.Unix())
+}
+
+// SaveReplay -- keep a replay
+func SaveReplay(s Store, key string, r msreplay.Replay) error {
+	var buf bytes.Buffer
+	if err := msreplay.Write(&buf, r); err != nil {
+		return err
+	}
+	return s.Put(KindReplays, key, buf.Bytes())
+}
+
+// LoadReplays -- every replay kept, in key order
+func LoadReplays(s Store) ([]msreplay.Replay, error) {
+	keys, err := s.List(KindReplays)
+	if err != nil {
+		return nil, err
+	}
+	retval := make([]msreplay.Replay, 0, len(keys))
+	for _, key := range keys {
+		data, err := s.Get(KindReplays, key)
+		if err != nil {
+			return nil, err
+		}
+		r, err := msreplay.Read(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("replay %s: %v", key, err)
+		}
+		retval = append(retval, r)
+	}
+	return retval, nil
+}
+
+// SavePuzzleStats -- keep puzzle completion, replacing what was kept before
+func SavePuzzleStats(s Store, stats *mspuzzle.Stats) error {
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
+	}
+	return s.Put(KindStats, puzzleStatsKey, data)
+}
+
+// LoadPuzzleStats -- puzzle completion as kept; empty stats if none has been
+func LoadPuzzleStats(s Store) (*mspuzzle.Stats, error) {
+	retval := mspuzzle.NewStats()
+	data, err := s.Get(KindStats, puzzleStatsKey)
+	if err == ErrNotFound {
+		return retval, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, retval); err != nil {
+		return nil, fmt.Errorf("puzzle stats: %v", err)
+	}
+	if nil == retval.Puzzles {
+		retval.Puzzles = make(map[string]mspuzzle.Completion)
+	}
+	return retval, nil
+}
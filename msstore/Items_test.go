@@ -0,0 +1,141 @@
+/*
+	Test functions for games, replays and puzzle stats in a store
+
+	mike@pocomotech.com
+*/
+
+package msstore
+
+import (
+	"go-mines/msanalysis"
+	"go-mines/msboard"
+	"go-mines/msbot"
+	"go-mines/mspuzzle"
+	"go-mines/msreplay"
+	"go-mines/msstats"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestItems(t *testing.T) {
+	s, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// games come back in date order, whatever order they were added in
+	later := msstats.Record{Date: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), Difficulty: "hard", Seed: -7}
+	earlier := msstats.Record{Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Difficulty: "easy", Won: true}
+	AddGame(s, later)
+	if err := SaveHistory(s, &msstats.History{Games: []msstats.Record{earlier, later}}); err != nil {
+		t.Fatal(err)
+	}
+	h, err := LoadHistory(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Games) != 2 || h.Games[0].Difficulty != "easy" || h.Games[1].Seed != -7 {
+		t.Errorf("history %+v", h.Games)
+	}
+
+	r := msreplay.Replay{Layout: "1*./..*", Moves: []msboard.Move{{Type: msboard.MoveReveal,
+		Location: msboard.NewLocation(1, 0)}}}
+	if err := SaveReplay(s, ReplayKey(time.Unix(1700000000, 0)), r); err != nil {
+		t.Fatal(err)
+	}
+	replays, err := LoadReplays(s)
+	if err != nil || len(replays) != 1 || replays[0].Layout != r.Layout || len(replays[0].Moves) != 1 {
+		t.Errorf("replays %+v, %v", replays, err)
+	}
+	if keys, _ := s.List(KindReplays); keys[0] != "replay-1700000000" {
+		t.Errorf("replay kept as %q", keys)
+	}
+
+	stats, err := LoadPuzzleStats(s)
+	if err != nil || len(stats.Puzzles) != 0 {
+		t.Fatalf("empty store has puzzle stats %+v, %v", stats, err)
+	}
+	p := mspuzzle.Puzzle{Title: "corner", Layout: "1*1/111/..."}
+	stats.Record(p, true, 5*time.Second, 3, true)
+	SavePuzzleStats(s, stats)
+	if stats, err = LoadPuzzleStats(s); err != nil || stats.Completion(p).Solved != 1 {
+		t.Errorf("puzzle stats %+v, %v", stats, err)
+	}
+}
+
+func TestSimGames(t *testing.T) {
+	s, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// games come back run by run, each run in the order it was played
+	first := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+	AddSimGame(s, SimKey(second, 1), msanalysis.GameResult{Mode: msanalysis.ModePolicies, Policy: "corner"})
+	for i := 1; i <= 10; i++ {
+		if err := AddSimGame(s, SimKey(first, i), msanalysis.GameResult{Mode: msanalysis.ModeOpenings,
+			Seed: int64(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	games, err := LoadSimGames(s)
+	if err != nil || len(games) != 11 {
+		t.Fatalf("simulated games %+v, %v", games, err)
+	}
+	if games[0].Seed != 1 || games[9].Seed != 10 || games[10].Policy != "corner" {
+		t.Errorf("simulated games out of order: %+v", games)
+	}
+
+	// player data is kept apart from them
+	if h, _ := LoadHistory(s); len(h.Games) != 0 {
+		t.Errorf("simulated games read as played ones: %+v", h.Games)
+	}
+}
+
+func TestSimGameRebuild(t *testing.T) {
+	s, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run, i := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), 0
+	candidates := []msboard.Location{msboard.NewLocation(0, 0), msboard.NewLocation(4, 4)}
+	if _, err = msanalysis.Openings(10, 9, 9, 10, candidates, msbot.Player{}, func(g msanalysis.GameResult) error {
+		i++
+		return AddSimGame(s, SimKey(run, i), g)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	games, err := LoadSimGames(s)
+	if err != nil || len(games) != 20 {
+		t.Fatalf("simulated games %+v, %v", games, err)
+	}
+
+	// each game kept rebuilds the board it was played on, whatever math/rand has done since, so the bot plays it
+	// out the same way again
+	rand.Seed(1995)
+	for _, g := range games {
+		b, err := g.Board()
+		if err != nil {
+			t.Fatalf("rebuilding %+v failed: %s", g, err)
+		}
+		status, clicks, guesses := b.Status(), 1, 0
+		if status == msboard.StatusPlaying {
+			first, _ := msboard.LetterNumberCodec{}.Parse(g.First, g.Rows, g.Cols)
+			var bot msbot.Player
+			if status, clicks, guesses, err = bot.PlayCounted(b, first); err != nil {
+				t.Fatalf("replaying %+v failed: %s", g, err)
+			}
+		}
+		if won := status == msboard.StatusWon; won != g.Won || clicks != g.Clicks || guesses != g.Guesses {
+			t.Errorf("rebuilt %+v played out as won %v, %d clicks, %d guesses", g, won, clicks, guesses)
+		}
+	}
+
+	bad := msanalysis.GameResult{Rows: 9, Cols: 9, Mines: 10, First: "?"}
+	if _, err = bad.Board(); nil == err {
+		t.Errorf("rebuilt a game with no first click")
+	}
+}
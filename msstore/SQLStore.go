@@ -0,0 +1,125 @@
+/*
+
+	SQLStore.go - a store in a SQL database: one table of items keyed by kind and key, for servers that keep
+	everything in one place
+
+	The statements are plain SQL with SQLite's INSERT OR REPLACE, so any database/sql driver for SQLite will do;
+	OpenSQLite uses the one built in with -tags sqlite.
+
+	mike@pocomotech.com
+
+*/
+
+package msstore
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// sqliteDriver : database/sql driver name OpenSQLite uses, set by SQLiteDriver.go in builds with -tags sqlite
+var sqliteDriver = ""
+
+// SQLStore : items kept as rows of an items table
+type SQLStore struct {
+	db *sql.DB
+}
+
+// NewSQLStore -- a store in an open database, creating its table if it isn't there yet
+func NewSQLStore(db *sql.DB) (*SQLStore, error) {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS items (
+		kind     TEXT NOT NULL,
+		key      TEXT NOT NULL,
+		data     BLOB NOT NULL,
+		modified INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (kind, key)
+	)`)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLStore{db: db}, nil
+}
+
+// OpenSQLite -- a store in a SQLite database file, created if it doesn't exist yet. Needs a build with -tags sqlite
+func OpenSQLite(filename string) (*SQLStore, error) {
+	if sqliteDriver == "" {
+		return nil, errors.New("built without SQLite support, rebuild with go build -tags sqlite")
+	}
+	db, err := sql.Open(sqliteDriver, filename)
+	if err != nil {
+		return nil, err
+	}
+	retval, err := NewSQLStore(db)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return retval, nil
+}
+
+// Close -- close the database
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+// Put -- add or replace an item's row
+func (s *SQLStore) Put(kind Kind, key string, data []byte) error {
+	return s.PutAt(kind, key, data, time.Now())
+}
+
+// PutAt -- add or replace an item's row, as modified at a time
+func (s *SQLStore) PutAt(kind Kind, key string, data []byte, at time.Time) error {
+	if err := checkKey(kind, key); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(`INSERT OR REPLACE INTO items (kind, key, data, modified) VALUES (?, ?, ?, ?)`, string(kind),
+		key, data, at.UnixNano())
+	return err
+}
+
+// Get -- an item's data
+func (s *SQLStore) Get(kind Kind, key string) ([]byte, error) {
+	if err := checkKey(kind, key); err != nil {
+		return nil, err
+	}
+	var retval []byte
+	err := s.db.QueryRow(`SELECT data FROM items WHERE kind = ? AND key = ?`, string(kind), key).Scan(&retval)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return retval, err
+}
+
+// List -- the keys of every item of a kind
+func (s *SQLStore) List(kind Kind) ([]string, error) {
+	stamps, err := s.Stamps(kind)
+	if err != nil {
+		return nil, err
+	}
+	return sortedKeys(stamps), nil
+}
+
+// Stamps -- the keys of every item of a kind, with when each was modified
+func (s *SQLStore) Stamps(kind Kind) (map[string]time.Time, error) {
+	if err := checkKey(kind, "list"); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.Query(`SELECT key, modified FROM items WHERE kind = ?`, string(kind))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	retval := map[string]time.Time{}
+	for rows.Next() {
+		var key string
+		var modified int64
+		if err := rows.Scan(&key, &modified); err != nil {
+			return nil, err
+		}
+		retval[key] = time.Unix(0, modified)
+	}
+	return retval, rows.Err()
+}
@@ -0,0 +1,36 @@
+//go:build sqlite
+
+/*
+	Test functions for the SQL store, run in builds with SQLite support
+
+		go test -tags sqlite ./msstore/
+
+	mike@pocomotech.com
+*/
+
+package msstore
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLStore(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "games.db")
+	s, err := OpenSQLite(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, s)
+	s.Close()
+
+	// reopened, the items are still there
+	s, err = OpenSQLite(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if keys, err := s.List(KindReplays); err != nil || len(keys) != 3 {
+		t.Errorf("reopened store lists %q, %v", keys, err)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build sqlite
+
+/*
+
+	SQLiteDriver.go - builds with SQLite support link in a driver for OpenSQLite
+
+		go get github.com/mattn/go-sqlite3
+		go build -tags sqlite
+
+	mike@pocomotech.com
+
+*/
+
+package msstore
+
+import (
+	_ "github.com/mattn/go-sqlite3" // registers the sqlite3 driver
+)
+
+func init() {
+	sqliteDriver = "sqlite3"
+}
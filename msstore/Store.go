@@ -0,0 +1,99 @@
+/*
+
+	Store.go - where finished games, replays and stats are kept, behind one small interface so a desktop install can
+	keep files in a directory and a server can keep everything in a database
+
+	mike@pocomotech.com
+
+*/
+
+// Package msstore -- storage backends for player data: games, replays and stats as JSON items, kept in a directory
+// or a SQL database
+package msstore
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kind : what a stored item is; each kind is listed separately
+type Kind string
+
+// Item kinds
+const (
+	KindGames   Kind = "games"   // one msstats.Record per finished game
+	KindReplays Kind = "replays" // one msreplay.Replay per saved game
+	KindStats   Kind = "stats"   // named totals, such as puzzle completion
+	KindSims    Kind = "sims"    // one msanalysis.GameResult per game a simulation played; never synced
+)
+
+// ErrNotFound : Get of a key that was never Put
+var ErrNotFound = errors.New("not found")
+
+// Store : a backend for JSON items of each kind, named by keys that sort in the order they were made
+type Store interface {
+	Put(kind Kind, key string, data []byte) error // add or replace an item
+	Get(kind Kind, key string) ([]byte, error)    // an item, or ErrNotFound
+	List(kind Kind) ([]string, error)             // keys of every item of a kind, sorted
+}
+
+// Stamped : a Store that knows when each item was last modified, which syncing needs to tell the newer of two
+// copies
+type Stamped interface {
+	Store
+	Stamps(kind Kind) (map[string]time.Time, error) // keys of every item of a kind, with when each was modified
+}
+
+// Local : a Stamped store on this machine, which can take an item with the modification time of another copy
+type Local interface {
+	Stamped
+	PutAt(kind Kind, key string, data []byte, at time.Time) error
+}
+
+// Open -- the store a location names: "sqlite:<file>" for a SQLite database, otherwise a directory
+func Open(location string) (Store, error) {
+	if filename := strings.TrimPrefix(location, "sqlite:"); filename != location {
+		s, err := OpenSQLite(filename)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	s, err := NewFileStore(location)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// sortedKeys -- the keys of a map of stamps, sorted
+func sortedKeys(stamps map[string]time.Time) []string {
+	retval := make([]string, 0, len(stamps))
+	for key := range stamps {
+		retval = append(retval, key)
+	}
+	sort.Strings(retval)
+	return retval
+}
+
+// checkKey -- refuse keys that can't be used as file names in every backend
+func checkKey(kind Kind, key string) error {
+	switch kind {
+	case KindGames, KindReplays, KindStats, KindSims:
+	default:
+		return fmt.Errorf("unknown kind %q", kind)
+	}
+	if key == "" || strings.Trim(key, ".") == "" {
+		return fmt.Errorf("invalid key %q", key)
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' ||
+			r == '.') {
+			return fmt.Errorf("invalid key %q, use letters, digits, '-', '_' and '.'", key)
+		}
+	}
+	return nil
+}
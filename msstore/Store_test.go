@@ -0,0 +1,49 @@
+/*
+	Test functions for the Store interface, shared by every backend
+
+	mike@pocomotech.com
+*/
+
+package msstore
+
+import (
+	"testing"
+)
+
+// testStore -- the behavior every Store must have, run against a new, empty one
+func testStore(t *testing.T, s Store) {
+	if keys, err := s.List(KindReplays); err != nil || len(keys) != 0 {
+		t.Errorf("new store lists %q, %v", keys, err)
+	}
+	if _, err := s.Get(KindStats, "puzzles"); err != ErrNotFound {
+		t.Errorf("missing item gave %v", err)
+	}
+
+	for _, key := range []string{"b", "a", "c.1"} {
+		if err := s.Put(KindReplays, key, []byte(`{"key":"`+key+`"}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Put(KindStats, "a", []byte(`{}`))
+	s.Put(KindReplays, "b", []byte(`{"key":"b2"}`))
+
+	keys, err := s.List(KindReplays)
+	if err != nil || len(keys) != 3 || keys[0] != "a" || keys[1] != "b" || keys[2] != "c.1" {
+		t.Errorf("listed %q, %v", keys, err)
+	}
+	if data, err := s.Get(KindReplays, "b"); err != nil || string(data) != `{"key":"b2"}` {
+		t.Errorf("replaced item is %q, %v", data, err)
+	}
+	if keys, _ := s.List(KindStats); len(keys) != 1 {
+		t.Errorf("kinds mixed up: stats %q", keys)
+	}
+
+	for _, key := range []string{"", "..", "a/b", `a\b`, "a b"} {
+		if err := s.Put(KindReplays, key, nil); err == nil {
+			t.Errorf("put with key %q", key)
+		}
+	}
+	if err := s.Put(Kind("other"), "a", nil); err == nil {
+		t.Error("put of an unknown kind")
+	}
+}
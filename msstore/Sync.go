@@ -0,0 +1,127 @@
+/*
+
+	Sync.go - keeping a local store and a remote one alike, so games, replays and stats follow the player from
+	machine to machine
+
+	Games and replays are never changed once written, so syncing them is a matter of copying each side's new ones
+	across. Stats are rewritten after every puzzle, so both sides can change the same item; the copy modified last
+	wins. After every copy the local item takes the remote's modification time, so the two agree on what's in sync
+	even when their clocks don't.
+
+	mike@pocomotech.com
+
+*/
+
+package msstore
+
+import (
+	"fmt"
+	"time"
+)
+
+// SyncReport : what a sync moved
+type SyncReport struct {
+	Uploaded   int
+	Downloaded int
+	Replaced   int // items on both sides that differed, settled in favor of the newer copy
+}
+
+// Moved -- true if anything was copied either way
+func (r SyncReport) Moved() bool {
+	return r.Uploaded > 0 || r.Downloaded > 0
+}
+
+// String -- short description for the player
+func (r SyncReport) String() string {
+	retval := fmt.Sprintf("%d up, %d down", r.Uploaded, r.Downloaded)
+	if r.Replaced > 0 {
+		retval += fmt.Sprintf(", %d replaced by a newer copy", r.Replaced)
+	}
+	return retval
+}
+
+// Sync -- copy every item of every kind that's newer on one side, or missing from it, to the other. Simulated
+// games are research data rather than a player's, and stay where they were made
+func Sync(local Local, remote Stamped) (SyncReport, error) {
+	var retval SyncReport
+	for _, kind := range []Kind{KindGames, KindReplays, KindStats} {
+		if err := syncKind(local, remote, kind, &retval); err != nil {
+			return retval, fmt.Errorf("syncing %s: %v", kind, err)
+		}
+	}
+	return retval, nil
+}
+
+// put -- copy an item to a store, keeping its modification time if the store can
+func put(s Stamped, kind Kind, key string, data []byte, at time.Time) error {
+	if local, ok := s.(Local); ok {
+		return local.PutAt(kind, key, data, at)
+	}
+	return s.Put(kind, key, data)
+}
+
+// syncKind -- sync the items of one kind
+func syncKind(local Local, remote Stamped, kind Kind, report *SyncReport) error {
+	mine, err := local.Stamps(kind)
+	if err != nil {
+		return err
+	}
+	theirs, err := remote.Stamps(kind)
+	if err != nil {
+		return err
+	}
+
+	uploaded := map[string][]byte{}
+	for key, at := range mine {
+		remoteAt, ok := theirs[key]
+		if ok && !at.After(remoteAt) {
+			continue
+		}
+		if ok {
+			report.Replaced++
+		}
+		data, err := local.Get(kind, key)
+		if err != nil {
+			return err
+		}
+		if err := put(remote, kind, key, data, at); err != nil {
+			return err
+		}
+		uploaded[key] = data
+		report.Uploaded++
+	}
+
+	for key, at := range theirs {
+		localAt, ok := mine[key]
+		if ok && !at.After(localAt) {
+			continue
+		}
+		if ok {
+			report.Replaced++
+		}
+		data, err := remote.Get(kind, key)
+		if err != nil {
+			return err
+		}
+		if err := local.PutAt(kind, key, data, at); err != nil {
+			return err
+		}
+		report.Downloaded++
+	}
+
+	// remotes that stamp what they're sent with their own clock: take their times so the copies match next time
+	if len(uploaded) == 0 {
+		return nil
+	}
+	if theirs, err = remote.Stamps(kind); err != nil {
+		return err
+	}
+	for key, data := range uploaded {
+		if at, ok := theirs[key]; ok {
+			if err := local.PutAt(kind, key, data, at); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+/*
+	Test functions for syncing stores
+
+	mike@pocomotech.com
+*/
+
+package msstore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSync(t *testing.T) {
+	home, _ := NewFileStore(t.TempDir())
+	work, _ := NewFileStore(t.TempDir())
+	remote, _ := NewFileStore(t.TempDir())
+	hour := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	home.PutAt(KindGames, "g1", []byte("home game"), hour)
+	home.PutAt(KindStats, "puzzles", []byte("home stats"), hour)
+	work.PutAt(KindGames, "g2", []byte("work game"), hour)
+	work.PutAt(KindStats, "puzzles", []byte("newer work stats"), hour.Add(time.Minute))
+
+	if r, err := Sync(home, remote); err != nil || r.Uploaded != 2 || r.Downloaded != 0 {
+		t.Fatalf("first sync %+v, %v", r, err)
+	}
+	if r, err := Sync(work, remote); err != nil || r.Uploaded != 2 || r.Downloaded != 1 || r.Replaced != 1 {
+		t.Fatalf("second machine's sync %+v, %v", r, err)
+	}
+	if r, err := Sync(home, remote); err != nil || r.Uploaded != 0 || r.Downloaded != 2 {
+		t.Fatalf("first machine's second sync %+v, %v", r, err)
+	}
+
+	// both machines have every game, and the newer stats
+	for _, s := range []*FileStore{home, work} {
+		if keys, _ := s.List(KindGames); len(keys) != 2 {
+			t.Errorf("games %q", keys)
+		}
+		if data, _ := s.Get(KindStats, "puzzles"); string(data) != "newer work stats" {
+			t.Errorf("stats %q", data)
+		}
+	}
+
+	// in sync, nothing moves
+	if r, err := Sync(home, remote); err != nil || r.Moved() {
+		t.Errorf("sync with nothing new %+v, %v", r, err)
+	}
+	if r := (SyncReport{Uploaded: 1, Replaced: 2}); r.String() != "1 up, 0 down, 2 replaced by a newer copy" {
+		t.Errorf("report %q", r)
+	}
+}
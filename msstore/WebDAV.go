@@ -0,0 +1,215 @@
+/*
+
+	WebDAV.go - a remote store on a WebDAV server, such as Nextcloud, ownCloud or Apache mod_dav, laid out like a
+	FileStore: a collection for each kind and a JSON file for each item
+
+	mike@pocomotech.com
+
+*/
+
+package msstore
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// webDAVTimeout : longest a request waits for the server
+const webDAVTimeout = 30 * time.Second
+
+// WebDAV : items kept on a WebDAV server under a base URL
+type WebDAV struct {
+	base     url.URL
+	user     string
+	password string
+	client   *http.Client
+	made     map[string]bool // collections known to exist
+}
+
+// NewWebDAV -- a remote store under a base URL, logging in with the user and password in the URL if there are
+// any; password, when not empty, takes the place of the URL's
+func NewWebDAV(rawurl, password string) (*WebDAV, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("WebDAV needs an http or https URL, got %q", rawurl)
+	}
+
+	retval := &WebDAV{client: &http.Client{Timeout: webDAVTimeout}, made: map[string]bool{}}
+	if nil != u.User {
+		retval.user = u.User.Username()
+		retval.password, _ = u.User.Password()
+		u.User = nil
+	}
+	if password != "" {
+		retval.password = password
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/"
+	retval.base = *u
+	return retval, nil
+}
+
+// location -- URL of a collection or item below the base
+func (d *WebDAV) location(elem ...string) string {
+	u := d.base
+	u.Path = path.Join(append([]string{u.Path}, elem...)...)
+	return u.String()
+}
+
+// do -- send a request, with the login if there is one
+func (d *WebDAV) do(method, target string, body []byte, header map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest(method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if d.user != "" {
+		req.SetBasicAuth(d.user, d.password)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	return d.client.Do(req)
+}
+
+// status -- an error for a response that wasn't a success, closing its body
+func status(resp *http.Response, method string) error {
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s %s: %s", method, resp.Request.URL.Redacted(), resp.Status)
+	}
+	return nil
+}
+
+// makeCollection -- create a collection if it isn't known to exist; one that already does is fine
+func (d *WebDAV) makeCollection(target string) error {
+	if d.made[target] {
+		return nil
+	}
+	resp, err := d.do("MKCOL", target, nil, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusMethodNotAllowed {
+		// already there
+		resp.StatusCode = http.StatusOK
+	}
+	if err = status(resp, "MKCOL"); err != nil {
+		return err
+	}
+	d.made[target] = true
+	return nil
+}
+
+// Put -- upload an item, creating the collections it goes in the first time
+func (d *WebDAV) Put(kind Kind, key string, data []byte) error {
+	if err := checkKey(kind, key); err != nil {
+		return err
+	}
+	if err := d.makeCollection(d.location()); err != nil {
+		return err
+	}
+	if err := d.makeCollection(d.location(string(kind)) + "/"); err != nil {
+		return err
+	}
+	resp, err := d.do(http.MethodPut, d.location(string(kind), key+".json"), data,
+		map[string]string{"Content-Type": "application/json"})
+	if err != nil {
+		return err
+	}
+	return status(resp, http.MethodPut)
+}
+
+// Get -- download an item
+func (d *WebDAV) Get(kind Kind, key string) ([]byte, error) {
+	if err := checkKey(kind, key); err != nil {
+		return nil, err
+	}
+	resp, err := d.do(http.MethodGet, d.location(string(kind), key+".json"), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, status(resp, http.MethodGet)
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// List -- the keys of the items of a kind
+func (d *WebDAV) List(kind Kind) ([]string, error) {
+	stamps, err := d.Stamps(kind)
+	if err != nil {
+		return nil, err
+	}
+	return sortedKeys(stamps), nil
+}
+
+// propfind : the parts of a PROPFIND multistatus response a listing needs
+type propfind struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Modified string `xml:"propstat>prop>getlastmodified"`
+	} `xml:"response"`
+}
+
+// propfindBody : asks for each member's modification time
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<propfind xmlns="DAV:"><prop><getlastmodified/></prop></propfind>`
+
+// Stamps -- the keys of the items of a kind with their modification times, from a PROPFIND of its collection
+func (d *WebDAV) Stamps(kind Kind) (map[string]time.Time, error) {
+	if err := checkKey(kind, "list"); err != nil {
+		return nil, err
+	}
+	resp, err := d.do("PROPFIND", d.location(string(kind))+"/", []byte(propfindBody),
+		map[string]string{"Depth": "1", "Content-Type": "application/xml"})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		// nothing uploaded yet
+		resp.Body.Close()
+		return map[string]time.Time{}, nil
+	}
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, status(resp, "PROPFIND")
+	}
+	defer resp.Body.Close()
+
+	var listing propfind
+	if err := xml.NewDecoder(resp.Body).Decode(&listing); err != nil {
+		return nil, fmt.Errorf("PROPFIND %s: %v", kind, err)
+	}
+	retval := map[string]time.Time{}
+	for _, r := range listing.Responses {
+		href, err := url.PathUnescape(r.Href)
+		if err != nil || !strings.HasSuffix(href, ".json") {
+			// the collection itself, or something other than an item
+			continue
+		}
+		key := strings.TrimSuffix(path.Base(href), ".json")
+		if checkKey(kind, key) != nil {
+			continue
+		}
+		at, err := http.ParseTime(r.Modified)
+		if err != nil {
+			return nil, fmt.Errorf("PROPFIND %s: %s has no modification time", kind, key)
+		}
+		retval[key] = at
+	}
+	return retval, nil
+}
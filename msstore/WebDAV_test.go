@@ -0,0 +1,127 @@
+/*
+	Test functions for the WebDAV store, against a small in-memory server
+
+	mike@pocomotech.com
+*/
+
+package msstore
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// davServer : enough of WebDAV for the store: MKCOL, PUT, GET and a depth 1 PROPFIND, with one login
+type davServer struct {
+	sync.Mutex
+	files    map[string][]byte
+	modified map[string]time.Time
+	dirs     map[string]bool
+	now      time.Time
+}
+
+func (s *davServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if user, password, _ := r.BasicAuth(); user != "player" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+
+	switch r.Method {
+	case "MKCOL":
+		if s.dirs[r.URL.Path] {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		s.dirs[strings.TrimSuffix(r.URL.Path, "/")+"/"] = true
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		s.now = s.now.Add(time.Second)
+		s.files[r.URL.Path], s.modified[r.URL.Path] = data, s.now
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodGet:
+		data, ok := s.files[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case "PROPFIND":
+		if !s.dirs[r.URL.Path] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusMultiStatus)
+		fmt.Fprintf(w, `<?xml version="1.0"?><d:multistatus xmlns:d="DAV:"><d:response><d:href>%s</d:href>
+			<d:propstat><d:prop><d:resourcetype><d:collection/></d:resourcetype></d:prop></d:propstat></d:response>`,
+			r.URL.Path)
+		for name, at := range s.modified {
+			if strings.HasPrefix(name, r.URL.Path) {
+				fmt.Fprintf(w, `<d:response><d:href>%s</d:href><d:propstat><d:prop><d:getlastmodified>%s`+
+					`</d:getlastmodified></d:prop></d:propstat></d:response>`, name, at.UTC().Format(http.TimeFormat))
+			}
+		}
+		fmt.Fprint(w, `</d:multistatus>`)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newDAVServer() *httptest.Server {
+	return httptest.NewServer(&davServer{files: map[string][]byte{}, modified: map[string]time.Time{},
+		dirs: map[string]bool{}, now: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)})
+}
+
+func TestWebDAV(t *testing.T) {
+	server := newDAVServer()
+	defer server.Close()
+
+	d, err := NewWebDAV(strings.Replace(server.URL, "http://", "http://player:secret@", 1)+"/mines", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, d)
+
+	// the password can come separately, and a wrong one is refused
+	if d, _ = NewWebDAV(strings.Replace(server.URL, "http://", "http://player@", 1)+"/mines", "wrong"); d != nil {
+		if _, err := d.List(KindReplays); err == nil || !strings.Contains(err.Error(), "401") {
+			t.Errorf("wrong password gave %v", err)
+		}
+	}
+	if _, err := NewWebDAV("ftp://example.com/mines", ""); err == nil {
+		t.Error("WebDAV over ftp")
+	}
+}
+
+func TestSyncWebDAV(t *testing.T) {
+	server := newDAVServer()
+	defer server.Close()
+	remote, _ := NewWebDAV(server.URL+"/mines", "")
+	remote.user, remote.password = "player", "secret"
+
+	home, _ := NewFileStore(t.TempDir())
+	home.Put(KindGames, "g1", []byte("home game"))
+	if r, err := Sync(home, remote); err != nil || r.Uploaded != 1 {
+		t.Fatalf("sync %+v, %v", r, err)
+	}
+	// the local copy takes the server's time, so a second sync has nothing to do
+	if r, err := Sync(home, remote); err != nil || r.Moved() {
+		t.Errorf("second sync %+v, %v", r, err)
+	}
+
+	work, _ := NewFileStore(t.TempDir())
+	if r, err := Sync(work, remote); err != nil || r.Downloaded != 1 {
+		t.Fatalf("other machine's sync %+v, %v", r, err)
+	}
+	if data, _ := work.Get(KindGames, "g1"); string(data) != "home game" {
+		t.Errorf("synced game %q", data)
+	}
+}
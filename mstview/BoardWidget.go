@@ -0,0 +1,196 @@
+//go:build tview
+
+/*
+
+	BoardWidget.go - a Pane as a tview primitive, for dropping a playable board into tview applications. tview isn't
+	needed by the rest of go-mines, so the widget builds only when asked for:
+
+		go get github.com/rivo/tview
+		go build -tags tview
+
+	mike@pocomotech.com
+
+*/
+
+package mstview
+
+import (
+	"go-mines/msengine"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Colors of revealed scores, as on the classic board
+var scoreColors = []tcell.Color{tcell.ColorGray, tcell.ColorBlue, tcell.ColorGreen, tcell.ColorRed, tcell.ColorNavy,
+	tcell.ColorMaroon, tcell.ColorTeal, tcell.ColorWhite, tcell.ColorGray}
+
+// BoardWidget : a tview primitive playing a board. Arrow keys or hjkl move the cursor, space or enter reveals, f
+// flags, c chords and F flags every mine a single score forces; with the mouse enabled the left button reveals and
+// the right flags
+type BoardWidget struct {
+	*tview.Box
+	pane    *Pane
+	changed func(status msengine.Status, err error)
+}
+
+// NewBoardWidget -- a widget playing a board
+func NewBoardWidget(b msengine.Board) *BoardWidget {
+	return &BoardWidget{Box: tview.NewBox(), pane: NewPane(b)}
+}
+
+// Pane -- the board and cursor behind the widget, for setting events and theme or moving the cursor
+func (w *BoardWidget) Pane() *Pane {
+	return w.pane
+}
+
+// SetChangedFunc -- call handler after every move with the game status, or the error the move was refused with
+func (w *BoardWidget) SetChangedFunc(handler func(status msengine.Status, err error)) *BoardWidget {
+	w.changed = handler
+	return w
+}
+
+// play -- make a move at the cursor and tell the handler
+func (w *BoardWidget) play(t msengine.MoveType) {
+	status, err := w.pane.Play(t)
+	w.notify(status, err)
+}
+
+// flagObvious -- flag the mines single scores force, telling the changed func the status after
+func (w *BoardWidget) flagObvious() {
+	_, status, err := w.pane.FlagObvious()
+	w.notify(status, err)
+}
+
+// notify -- tell the changed func, if there is one, the status after a move
+func (w *BoardWidget) notify(status msengine.Status, err error) {
+	if nil != w.changed {
+		w.changed(status, err)
+	}
+}
+
+// cellStyle -- how a cell's glyph is drawn
+func cellStyle(v msengine.CellView) tcell.Style {
+	style := tcell.StyleDefault
+	switch v.State {
+	case msengine.CellFlagged:
+		return style.Foreground(tcell.ColorRed)
+	case msengine.CellMine:
+		return style.Foreground(tcell.ColorRed).Bold(true)
+	case msengine.CellRevealed:
+		if v.Score >= 0 && v.Score < len(scoreColors) {
+			return style.Foreground(scoreColors[v.Score])
+		}
+		return style.Foreground(tcell.ColorPurple)
+	}
+	return style.Dim(true)
+}
+
+// Draw -- draw the board inside the box, scrolled to keep the cursor in view when the board doesn't fit, with the
+// cursor cell reversed while the widget has focus
+func (w *BoardWidget) Draw(screen tcell.Screen) {
+	w.Box.DrawForSubclass(screen, w)
+	x, y, width, height := w.GetInnerRect()
+	w.pane.Fit(width, height)
+
+	for line, text := range w.pane.Lines() {
+		if line >= height {
+			break
+		}
+		column := 0
+		for _, r := range text {
+			if column >= width {
+				break
+			}
+			screen.SetContent(x+column, y+line, r, nil, tcell.StyleDefault)
+			column++
+		}
+	}
+
+	// restyle the glyphs drawn above
+	s := w.pane.Snapshot()
+	for row := range s.Cells {
+		for col, v := range s.Cells[row] {
+			l := msengine.NewLocation(row, col)
+			if !w.pane.Visible(l) {
+				continue
+			}
+			column, line := w.pane.Position(l)
+			if column >= width || line >= height {
+				continue
+			}
+			style := cellStyle(v)
+			if l == w.pane.Cursor() && w.HasFocus() {
+				style = style.Reverse(true)
+			}
+			r, _, _, _ := screen.GetContent(x+column, y+line)
+			screen.SetContent(x+column, y+line, r, nil, style)
+		}
+	}
+}
+
+// InputHandler -- move the cursor and play at it from the keyboard
+func (w *BoardWidget) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return w.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		switch event.Key() {
+		case tcell.KeyUp:
+			w.pane.MoveCursor(-1, 0)
+		case tcell.KeyDown:
+			w.pane.MoveCursor(1, 0)
+		case tcell.KeyLeft:
+			w.pane.MoveCursor(0, -1)
+		case tcell.KeyRight:
+			w.pane.MoveCursor(0, 1)
+		case tcell.KeyEnter:
+			w.play(msengine.MoveReveal)
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'k':
+				w.pane.MoveCursor(-1, 0)
+			case 'j':
+				w.pane.MoveCursor(1, 0)
+			case 'h':
+				w.pane.MoveCursor(0, -1)
+			case 'l':
+				w.pane.MoveCursor(0, 1)
+			case ' ':
+				w.play(msengine.MoveReveal)
+			case 'f':
+				w.play(msengine.MoveFlag)
+			case 'c':
+				w.play(msengine.MoveChord)
+			case 'F':
+				w.flagObvious()
+			}
+		}
+	})
+}
+
+// MouseHandler -- reveal with the left button and flag with the right, taking focus
+func (w *BoardWidget) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse,
+	setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+	return w.WrapMouseHandler(func(action tview.MouseAction, event *tcell.EventMouse,
+		setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+		x, y := event.Position()
+		if !w.InRect(x, y) {
+			return false, nil
+		}
+		var t msengine.MoveType
+		switch action {
+		case tview.MouseLeftClick:
+			t = msengine.MoveReveal
+		case tview.MouseRightClick:
+			t = msengine.MoveFlag
+		default:
+			return false, nil
+		}
+
+		setFocus(w)
+		innerX, innerY, _, _ := w.GetInnerRect()
+		if l, ok := w.pane.CellAt(x-innerX, y-innerY); ok {
+			w.pane.SetCursor(l)
+			w.play(t)
+		}
+		return true, nil
+	})
+}
@@ -0,0 +1,195 @@
+/*
+
+	Pane.go - a board played from a cursor, the state behind BoardWidget, kept apart from tview so it builds and is
+	tested without it
+
+	The pane is drawn as a header of column labels then a line for each row, laid out by msboard.GridLayout as the
+	console game draws boards, so positions on screen and cells map back and forth the same way.
+
+	mike@pocomotech.com
+
+*/
+
+// Package mstview -- a playable minesweeper pane for Go terminal applications built on tview, see BoardWidget
+package mstview
+
+import (
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"go-mines/msrender"
+	"go-mines/mssolver"
+)
+
+// Pane : a board and the cursor moves are made at
+type Pane struct {
+	board  msengine.Board
+	cursor msengine.Location
+	events msengine.EngineEvents // told what every move did, nil for no one
+	codec  msboard.LocationCodec
+	theme  msrender.Theme
+	view   msrender.Viewport // the cells drawn, all of them until Fit is called
+}
+
+// NewPane -- a pane playing a board, with the cursor on the top left cell. Boards still on the classic rules,
+// where revealed cells ignore clicks, are switched to msboard.ChordRules so a chord at the cursor does something;
+// boards set to other rules keep them
+func NewPane(b msengine.Board) *Pane {
+	if _, classic := b.Rules().(msboard.ClassicRules); classic {
+		b.SetRules(msboard.ChordRules{})
+	}
+	return &Pane{board: b, cursor: msengine.NewLocation(0, 0), codec: msboard.LetterNumberCodec{},
+		theme: msrender.UnicodeTheme{}}
+}
+
+// SetEvents -- report what every following move does to ev, nil to stop
+func (p *Pane) SetEvents(ev msengine.EngineEvents) {
+	p.events = ev
+}
+
+// SetTheme -- draw cells with a theme's glyphs; themes adding terminal escapes, such as msrender.ColorTheme, don't
+// suit a tview screen
+func (p *Pane) SetTheme(t msrender.Theme) {
+	p.theme = t
+}
+
+// Board -- the board being played
+func (p *Pane) Board() msengine.Board {
+	return p.board
+}
+
+// Snapshot -- what the player sees of the board
+func (p *Pane) Snapshot() msengine.Snapshot {
+	return p.board.Snapshot()
+}
+
+// Cursor -- the cell moves are made at
+func (p *Pane) Cursor() msengine.Location {
+	return p.cursor
+}
+
+// SetCursor -- put the cursor on a cell; false, leaving it where it was, if the cell isn't on the board
+func (p *Pane) SetCursor(l msengine.Location) bool {
+	if !p.board.ValidLocation(l) {
+		return false
+	}
+	p.cursor = l
+	return true
+}
+
+// MoveCursor -- move the cursor by a number of rows and columns, stopping at the edges of the board
+func (p *Pane) MoveCursor(dRow, dCol int) {
+	s := p.board.Snapshot()
+	p.cursor = msengine.NewLocation(clamp(p.cursor.Row()+dRow, s.Rows), clamp(p.cursor.Col()+dCol, s.Cols))
+}
+
+// Fit -- draw only as many cells as fit in width columns and height lines, header included, scrolled the least
+// needed to bring the cursor into view. Calling it before every draw keeps the cursor on screen as it moves
+func (p *Pane) Fit(width, height int) {
+	s := p.board.Snapshot()
+	layout := p.Layout()
+	p.view.Rows = maxInt(1, height-1)
+	p.view.Cols = maxInt(1, (width-1-layout.Margin())/layout.CellWidth+1)
+	p.view.Follow(p.cursor, s.Rows, s.Cols)
+}
+
+// Visible -- true if a cell is drawn, not scrolled out of view
+func (p *Pane) Visible(l msengine.Location) bool {
+	s := p.board.Snapshot()
+	return p.view.Contains(l, s.Rows, s.Cols)
+}
+
+// maxInt -- the larger of a and b
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// clamp -- i kept within 0 to n-1
+func clamp(i, n int) int {
+	if i >= n {
+		i = n - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	return i
+}
+
+// Play -- make a move at the cursor, reporting what it did to the events, and return the game status after it
+func (p *Pane) Play(t msengine.MoveType) (msengine.Status, error) {
+	return msengine.ApplyWithEvents(p.board, msengine.Move{Type: t, Location: p.cursor}, p.events)
+}
+
+// FlagObvious -- flag every hidden cell a single score forces to be a mine, as mssolver.Counting finds them,
+// reporting each flag to the events. Returns how many cells were flagged and the game status after
+func (p *Pane) FlagObvious() (int, msengine.Status, error) {
+	s := p.board.Snapshot()
+	_, mines := mssolver.Counting{}.Deductions(s)
+	flagged := 0
+	for _, l := range mines {
+		// the solver doesn't trust flags, so it can name cells already flagged
+		if v, _ := s.Cell(l); v.State == msengine.CellFlagged {
+			continue
+		}
+		if _, err := msengine.ApplyWithEvents(p.board, msengine.Move{Type: msengine.MoveFlag, Location: l},
+			p.events); err != nil {
+			return flagged, p.board.Status(), err
+		}
+		flagged++
+	}
+	return flagged, p.board.Status(), nil
+}
+
+// Layout -- the widths the pane is drawn with
+func (p *Pane) Layout() msboard.GridLayout {
+	s := p.board.Snapshot()
+	return msboard.NewGridLayout(s.Rows, s.Cols, p.codec)
+}
+
+// Lines -- the pane as text: the header, then each row, cut down to the cells Fit leaves in view
+func (p *Pane) Lines() []string {
+	s := p.board.Snapshot()
+	layout := msboard.NewGridLayout(s.Rows, s.Cols, p.codec)
+	firstRow, endRow, firstCol, endCol := p.view.Window(s.Rows, s.Cols)
+
+	labels := make([]string, 0, endCol-firstCol)
+	for col := firstCol; col < endCol; col++ {
+		labels = append(labels, p.codec.ColLabel(col, s.Cols))
+	}
+	retval := []string{layout.Header(labels)}
+	for row := firstRow; row < endRow; row++ {
+		cells := make([]string, 0, endCol-firstCol)
+		for _, v := range s.Cells[row][firstCol:endCol] {
+			sign := " "
+			if v.State == msengine.CellRevealed && v.Score < 0 {
+				sign = "-"
+			}
+			cells = append(cells, sign+p.theme.Cell(v))
+		}
+		retval = append(retval, layout.Row(p.codec.RowLabel(row, s.Rows), cells))
+	}
+	return retval
+}
+
+// CellAt -- the cell drawn at a column and line of the pane, counted from its top left corner; the sign before a
+// cell's glyph counts as part of the cell. false for the header, labels and gaps
+func (p *Pane) CellAt(x, y int) (msengine.Location, bool) {
+	s := p.board.Snapshot()
+	layout := p.Layout()
+	firstRow, _, firstCol, _ := p.view.Window(s.Rows, s.Cols)
+	offset := x - layout.Column(0) + 1
+	if y < 1 || offset < 0 || offset%layout.CellWidth > 1 {
+		return msengine.Location{}, false
+	}
+	l := msengine.NewLocation(firstRow+y-1, firstCol+offset/layout.CellWidth)
+	return l, p.Visible(l)
+}
+
+// Position -- the column and line of the pane a cell's glyph is drawn at; off the pane for cells out of view
+func (p *Pane) Position(l msengine.Location) (x, y int) {
+	s := p.board.Snapshot()
+	firstRow, _, firstCol, _ := p.view.Window(s.Rows, s.Cols)
+	return p.Layout().Column(l.Col() - firstCol), l.Row() - firstRow + 1
+}
@@ -0,0 +1,174 @@
+package mstview
+
+import (
+	"go-mines/msboard"
+	"go-mines/msengine"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// flagCounter : EngineEvents counting flag changes
+type flagCounter struct {
+	msengine.NoEvents
+	flags int
+}
+
+func (f *flagCounter) OnFlag(l msengine.Location, flagged bool) { f.flags++ }
+
+func TestPaneCursor(t *testing.T) {
+	b, _ := msboard.ParseLayout(".../..*")
+	p := NewPane(b)
+
+	p.MoveCursor(5, -1)
+	if got, want := p.Cursor(), msengine.NewLocation(1, 0); got != want {
+		t.Errorf("MoveCursor past the edges wanted %v got %v", want, got)
+	}
+	if p.SetCursor(msengine.NewLocation(2, 0)) || p.Cursor() != msengine.NewLocation(1, 0) {
+		t.Errorf("SetCursor off the board moved the cursor to %v", p.Cursor())
+	}
+
+	counter := new(flagCounter)
+	p.SetEvents(counter)
+	p.SetCursor(msengine.NewLocation(1, 2))
+	if _, err := p.Play(msengine.MoveFlag); err != nil || counter.flags != 1 {
+		t.Errorf("Play(flag) wanted 1 flag event got %d, err %v", counter.flags, err)
+	}
+	if status, err := p.Play(msengine.MoveReveal); err != nil || status != msengine.StatusPlaying {
+		t.Errorf("Play(reveal) on a flag wanted playing got %v, err %v", status, err)
+	}
+}
+
+func TestPaneLines(t *testing.T) {
+	b, _ := msboard.ParseLayout(".../..*")
+	p := NewPane(b)
+	p.SetCursor(msengine.NewLocation(0, 0))
+	p.Play(msengine.MoveReveal)
+	p.SetCursor(msengine.NewLocation(1, 2))
+	p.Play(msengine.MoveFlag)
+
+	want := []string{"    A  B  C", " 1  _  1  ·", " 2  _  1  ⚑"}
+	if got := p.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines wanted %q got %q", want, got)
+	}
+
+	// every cell is found where it's drawn, sign included, and nowhere else
+	for row := 0; row < 2; row++ {
+		for col := 0; col < 3; col++ {
+			l := msengine.NewLocation(row, col)
+			x, y := p.Position(l)
+			for _, at := range []int{x - 1, x} {
+				if got, ok := p.CellAt(at, y); !ok || got != l {
+					t.Errorf("CellAt(%d, %d) wanted %v got %v, %v", at, y, l, got, ok)
+				}
+			}
+			if _, ok := p.CellAt(x+1, y); ok {
+				t.Errorf("CellAt(%d, %d) after %v's glyph found a cell", x+1, y, l)
+			}
+		}
+	}
+	for _, at := range [][2]int{{4, 0}, {0, 1}, {1, 2}, {4, 3}, {13, 1}} {
+		if l, ok := p.CellAt(at[0], at[1]); ok {
+			t.Errorf("CellAt(%d, %d) wanted no cell got %v", at[0], at[1], l)
+		}
+	}
+}
+
+func TestPaneFlagObvious(t *testing.T) {
+	// the opening leaves C1 and D1 hidden: the 1 at B1 has only C1 to blame, while D1 is left for the player
+	b, _ := msboard.ParseLayout("..*./..../....")
+	p := NewPane(b)
+	p.Play(msengine.MoveReveal)
+
+	counter := new(flagCounter)
+	p.SetEvents(counter)
+	flagged, status, err := p.FlagObvious()
+	if err != nil || flagged != 1 || counter.flags != 1 || status != msengine.StatusPlaying {
+		t.Fatalf("FlagObvious wanted 1 flag got %d (%d events), %v, %v", flagged, counter.flags, status, err)
+	}
+	if v, _ := p.Snapshot().Cell(msengine.NewLocation(0, 2)); v.State != msengine.CellFlagged {
+		t.Errorf("FlagObvious didn't flag C1")
+	}
+
+	// flags already placed aren't toggled off again
+	if flagged, _, _ := p.FlagObvious(); flagged != 0 || counter.flags != 1 {
+		t.Errorf("second FlagObvious flagged %d more", flagged)
+	}
+}
+
+func TestPaneChord(t *testing.T) {
+	b, _ := msboard.ParseLayout("*../.../...")
+	p := NewPane(b)
+	if p.Board().Rules().Name() != "chord" {
+		t.Fatalf("NewPane left the board on the %s rules", p.Board().Rules().Name())
+	}
+
+	p.SetCursor(msengine.NewLocation(1, 1))
+	p.Play(msengine.MoveReveal)
+	p.SetCursor(msengine.NewLocation(0, 0))
+	p.Play(msengine.MoveFlag)
+
+	// the 1 at B2 has its mine flagged, so chording it opens everything else
+	p.SetCursor(msengine.NewLocation(1, 1))
+	if status, err := p.Play(msengine.MoveChord); err != nil || status != msengine.StatusWon {
+		t.Errorf("chord at B2 wanted a win got %v, %v", status, err)
+	}
+
+	// rules chosen by the embedder are kept
+	b, _ = msboard.ParseLayout("*../.../...")
+	b.SetRules(custom{})
+	if got := NewPane(b).Board().Rules().Name(); got != "custom" {
+		t.Errorf("NewPane replaced the embedder's rules with %s", got)
+	}
+}
+
+// custom : rules of an embedder's own
+type custom struct {
+	msboard.ChordRules
+}
+
+func (custom) Name() string { return "custom" }
+
+func TestPaneFit(t *testing.T) {
+	rows := make([]string, 20)
+	for i := range rows {
+		rows[i] = strings.Repeat(".", 30)
+	}
+	b, _ := msboard.ParseLayout(strings.Join(rows, "/"))
+	p := NewPane(b)
+
+	// room for the header, 5 rows and 4 columns: the row labels take " 1 " before each cell's sign
+	width := p.Layout().Column(3) + 1
+	p.Fit(width, 6)
+	want := []string{"    A  B  C  D", " 1  ·  ·  ·  ·", " 2  ·  ·  ·  ·", " 3  ·  ·  ·  ·", " 4  ·  ·  ·  ·",
+		" 5  ·  ·  ·  ·"}
+	if got := p.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines wanted %q got %q", want, got)
+	}
+
+	// moving past the edge scrolls just far enough to follow the cursor
+	p.SetCursor(msengine.NewLocation(7, 5))
+	p.Fit(width, 6)
+	want = []string{"    C  D  E  F", " 4  ·  ·  ·  ·", " 5  ·  ·  ·  ·", " 6  ·  ·  ·  ·", " 7  ·  ·  ·  ·",
+		" 8  ·  ·  ·  ·"}
+	if got := p.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("scrolled Lines wanted %q got %q", want, got)
+	}
+	x, y := p.Position(p.Cursor())
+	if l, ok := p.CellAt(x, y); !ok || l != p.Cursor() || x >= width || y >= 6 {
+		t.Errorf("cursor drawn at %d, %d, found there %v, %v", x, y, l, ok)
+	}
+	if l := msengine.NewLocation(0, 0); p.Visible(l) {
+		t.Errorf("A1 visible after scrolling away")
+	}
+	if l, ok := p.CellAt(p.Layout().Column(4), 1); ok {
+		t.Errorf("CellAt found %v past the visible columns", l)
+	}
+
+	// the bottom right corner stops at the edges of the board
+	p.SetCursor(msengine.NewLocation(19, 29))
+	p.Fit(width, 6)
+	if got := p.Lines(); got[0] != "    AA AB AC AD" || !strings.HasPrefix(got[5], "20") {
+		t.Errorf("corner Lines got %q", got)
+	}
+}